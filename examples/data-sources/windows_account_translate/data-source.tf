@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Resolve the built-in Administrators group whatever its localized name.
+data "windows_account_translate" "admins" {
+  sid = "S-1-5-32-544"
+}
+
+output "admins_name" {
+  value = data.windows_account_translate.admins.account_name # e.g. "BUILTIN\\Administrators"
+}
+
+# Look up the SID of a domain account.
+data "windows_account_translate" "svc" {
+  account_name = "CONTOSO\\svc-backup"
+}
+
+output "svc_sid" {
+  value = data.windows_account_translate.svc.sid
+}
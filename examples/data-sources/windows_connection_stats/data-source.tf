@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+resource "windows_feature" "iis" {
+  name = "Web-Server"
+}
+
+# WinRM activity of this run so far.
+data "windows_connection_stats" "this" {
+  # Read after the resources whose runs should be counted.
+  depends_on = [windows_feature.iis]
+}
+
+output "winrm_runs" {
+  value = "${data.windows_connection_stats.this.runs} runs, ${data.windows_connection_stats.this.dial_failures} dial failures"
+}
@@ -0,0 +1,41 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Require a cumulative update before enabling IIS.
+data "windows_hotfix" "jan_cu" {
+  hotfix_id = "KB5034123"
+}
+
+resource "windows_feature" "iis" {
+  name = "Web-Server"
+
+  lifecycle {
+    precondition {
+      condition     = data.windows_hotfix.jan_cu.installed
+      error_message = "KB5034123 must be installed before IIS is enabled."
+    }
+  }
+}
+
+# Every installed update.
+data "windows_hotfix" "all" {}
+
+output "security_updates" {
+  value = [
+    for h in data.windows_hotfix.all.hotfixes : h.hotfix_id
+    if h.description == "Security Update"
+  ]
+}
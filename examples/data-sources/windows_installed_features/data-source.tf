@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Audit features installed outside Terraform.
+locals {
+  managed_features = ["Web-Server", "Web-WebServer", "Web-Mgmt-Console"]
+}
+
+data "windows_installed_features" "all" {}
+
+output "unmanaged_features" {
+  value = [
+    for f in data.windows_installed_features.all.features : f.name
+    if !contains(local.managed_features, f.name)
+  ]
+}
+
+# Only the IIS role services.
+data "windows_installed_features" "iis" {
+  name_filter = "Web-*"
+}
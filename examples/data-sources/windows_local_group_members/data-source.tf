@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Members of the local Administrators group.
+data "windows_local_group_members" "admins" {
+  group_name = "Administrators"
+}
+
+output "admin_sids" {
+  value = [for m in data.windows_local_group_members.admins.members : m.sid]
+}
+
+output "domain_admin_groups" {
+  value = [
+    for m in data.windows_local_group_members.admins.members : m.name
+    if m.object_class == "Group" && m.principal_source == "ActiveDirectory"
+  ]
+}
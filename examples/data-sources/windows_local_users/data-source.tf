@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Enabled local accounts.
+data "windows_local_users" "enabled" {
+  enabled_only = true
+}
+
+output "enabled_account_names" {
+  value = [for u in data.windows_local_users.enabled.users : u.name]
+}
+
+output "accounts_never_expiring" {
+  value = [for u in data.windows_local_users.enabled.users : u.name if u.account_never_expires]
+}
@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Singleton — no lookup keys required.
+data "windows_logged_on_users" "current" {}
+
+output "active_users" {
+  value = [
+    for s in data.windows_logged_on_users.current.sessions : s.username
+    if s.state == "Active"
+  ]
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Singleton: no lookup keys required.
+data "windows_pending_reboot" "this" {}
+
+# Flush a reboot left over from earlier changes before installing IIS.
+resource "windows_reboot" "before_iis" {
+  count = data.windows_pending_reboot.this.pending ? 1 : 0
+
+  triggers = {
+    reasons = join(",", data.windows_pending_reboot.this.reasons)
+  }
+}
+
+resource "windows_feature" "iis" {
+  name       = "Web-Server"
+  depends_on = [windows_reboot.before_iis]
+}
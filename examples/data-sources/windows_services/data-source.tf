@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# The SQL Server services that are running, and the accounts they run as.
+data "windows_services" "sql" {
+  name_filter   = "MSSQL*"
+  status_filter = "Running"
+}
+
+output "sql_accounts" {
+  value = { for s in data.windows_services.sql.services : s.name => s.start_name }
+}
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Singleton: no lookup keys required. Install IIS on Server SKUs only.
+data "windows_system_info" "this" {}
+
+resource "windows_feature" "iis" {
+  count = data.windows_system_info.this.product_type == "Server" ? 1 : 0
+  name  = "Web-Server"
+}
+
+output "os" {
+  value = "${data.windows_system_info.this.os_name} (build ${data.windows_system_info.this.os_build})"
+}
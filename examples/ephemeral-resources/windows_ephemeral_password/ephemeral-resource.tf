@@ -0,0 +1,30 @@
+terraform {
+  # Ephemeral resources and write-only arguments need Terraform 1.11+.
+  required_version = ">= 1.11"
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Generated at plan/apply time; never written to plan or state.
+ephemeral "windows_ephemeral_password" "svc_app" {
+  length = 32
+}
+
+# password_wo is write-only: the generated value reaches the host but not the
+# state file. Bump password_wo_version to apply a freshly generated password.
+resource "windows_local_user" "svc_app" {
+  name                = "svc-app"
+  password_wo         = ephemeral.windows_ephemeral_password.svc_app.result
+  password_wo_version = 1
+}
@@ -0,0 +1,4 @@
+# windows_autologon is a per-host singleton; the import ID is always
+# "autologon". The password is never read back, so the first apply after
+# import rewrites DefaultPassword from configuration.
+terraform import windows_autologon.kiosk autologon
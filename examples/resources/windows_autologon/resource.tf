@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+variable "kiosk_password" {
+  type      = string
+  sensitive = true
+}
+
+# Log the kiosk account on automatically for the next reboot only.
+# The password is stored in plaintext in the Winlogon registry key; with
+# auto_logon_count set, Windows deletes it after the counted logon.
+resource "windows_autologon" "kiosk" {
+  username         = "kiosk"
+  password         = var.kiosk_password
+  auto_logon_count = 1
+}
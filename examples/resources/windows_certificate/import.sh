@@ -0,0 +1,2 @@
+# Import a certificate by <store_location>/<store_name>/<thumbprint>.
+terraform import windows_certificate.site LocalMachine/WebHosting/3F2A9C1D4B5E6F708192A3B4C5D6E7F809A1B2C3
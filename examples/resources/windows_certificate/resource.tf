@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Import a site certificate into the IIS WebHosting store.
+variable "site_pfx_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "windows_certificate" "site" {
+  pfx_base64 = filebase64("${path.module}/www.example.com.pfx")
+  password   = var.site_pfx_password
+  store_name = "WebHosting"
+}
+
+output "site_thumbprint" {
+  value = windows_certificate.site.thumbprint
+}
@@ -34,3 +34,11 @@ resource "windows_feature" "netfx3" {
   name   = "NET-Framework-Core"
   source = "\\\\fileserver\\share\\sources\\sxs"
 }
+
+# Internet-restricted host: never fall back to Windows Update for the payload,
+# so an unreachable or incomplete source fails fast instead of hanging.
+resource "windows_feature" "netfx3_offline" {
+  name               = "NET-Framework-Core"
+  source             = "wim:D:\\sources\\install.wim:2"
+  use_windows_update = false
+}
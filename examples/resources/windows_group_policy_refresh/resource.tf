@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Refresh computer policy after a registry change.
+resource "windows_registry_value" "no_auto_update" {
+  hive         = "HKLM"
+  path         = "SOFTWARE\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU"
+  name         = "NoAutoUpdate"
+  type         = "REG_DWORD"
+  value_string = "1"
+}
+
+resource "windows_group_policy_refresh" "after_au" {
+  triggers = {
+    value = windows_registry_value.no_auto_update.value_string
+  }
+  target       = "Computer"
+  wait_seconds = 120
+}
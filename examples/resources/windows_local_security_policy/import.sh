@@ -0,0 +1,2 @@
+# The policy is a singleton: import it by the fixed ID local_security_policy.
+terraform import windows_local_security_policy.baseline local_security_policy
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Password and lockout baseline.
+resource "windows_local_security_policy" "baseline" {
+  minimum_password_length  = 14
+  password_history_size    = 24
+  lockout_threshold        = 10
+  lockout_duration_minutes = 15
+}
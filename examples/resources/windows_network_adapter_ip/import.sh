@@ -0,0 +1,2 @@
+# Import an address by <interface_alias>/<ip_address>.
+terraform import windows_network_adapter_ip.mgmt "Ethernet/192.0.2.20"
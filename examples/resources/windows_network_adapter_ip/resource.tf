@@ -0,0 +1,41 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+# The provider connects to the management address that the "mgmt" resource
+# below replaces.
+provider "windows" {
+  host      = "192.0.2.10"
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Static address on a secondary interface.
+resource "windows_network_adapter_ip" "backup_lan" {
+  interface_alias = "Ethernet 2"
+  ip_address      = "10.20.0.15"
+  prefix_length   = 24
+}
+
+# Re-address the management interface.
+resource "windows_network_adapter_ip" "mgmt" {
+  interface_alias = "Ethernet"
+  ip_address      = "192.0.2.20"
+  prefix_length   = 24
+  default_gateway = "192.0.2.1"
+
+  # The provider loses 192.0.2.10 while this applies; confirm through the
+  # new address instead.
+  reconnect_host = "192.0.2.20"
+
+  timeouts = {
+    create = "5m"
+    update = "5m"
+  }
+}
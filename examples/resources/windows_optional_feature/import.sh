@@ -0,0 +1,2 @@
+# Import an enabled optional feature by its name.
+terraform import windows_optional_feature.wsl Microsoft-Windows-Subsystem-Linux
@@ -0,0 +1,39 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Minimal example: enable WSL.
+resource "windows_optional_feature" "wsl" {
+  name = "Microsoft-Windows-Subsystem-Linux"
+}
+
+# Enable Hyper-V with its parent features, then reboot.
+resource "windows_optional_feature" "hyperv" {
+  name = "Microsoft-Hyper-V"
+  all  = true
+}
+
+resource "windows_reboot" "after_hyperv" {
+  triggers = {
+    feature = windows_optional_feature.hyperv.id
+  }
+}
+
+# Offline source for an air-gapped host, never contacting Windows Update.
+resource "windows_optional_feature" "netfx3" {
+  name         = "NetFx3"
+  source       = "D:\\sources\\sxs"
+  limit_access = true
+}
@@ -0,0 +1,2 @@
+# Import the page file of a drive by its letter.
+terraform import windows_pagefile.c C:
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Page file on C: from 4 to 8 GiB. Windows applies the change at the next
+# reboot.
+resource "windows_pagefile" "c" {
+  drive           = "C:"
+  initial_size_mb = 4096
+  maximum_size_mb = 8192
+}
+
+resource "windows_reboot" "pagefile" {
+  triggers = {
+    initial = windows_pagefile.c.initial_size_mb
+    maximum = windows_pagefile.c.maximum_size_mb
+  }
+}
@@ -0,0 +1,52 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Create a directory share.
+resource "windows_powershell_script" "reports_share" {
+  create_script = <<-EOT
+    New-Item -ItemType Directory -Path 'D:\Reports' -Force | Out-Null
+    if (-not (Get-SmbShare -Name 'Reports' -ErrorAction SilentlyContinue)) {
+      New-SmbShare -Name 'Reports' -Path 'D:\Reports' -ReadAccess 'Everyone' | Out-Null
+    }
+  EOT
+
+  read_script = <<-EOT
+    $s = Get-SmbShare -Name 'Reports' -ErrorAction SilentlyContinue
+    if ($s) { $s | Select-Object Name, Path, Description | ConvertTo-Json -Compress }
+  EOT
+
+  delete_script = "Remove-SmbShare -Name 'Reports' -Force"
+}
+
+output "reports_share_path" {
+  value = jsondecode(windows_powershell_script.reports_share.result).Path
+}
+
+# Re-run in place when the baseline changes, with a longer timeout.
+resource "windows_powershell_script" "dotnet_tls" {
+  create_script = <<-EOT
+    Set-ItemProperty -Path 'HKLM:\SOFTWARE\Microsoft\.NETFramework\v4.0.30319' -Name SchUseStrongCrypto -Value 1 -Type DWord
+  EOT
+  update_script = <<-EOT
+    Set-ItemProperty -Path 'HKLM:\SOFTWARE\Microsoft\.NETFramework\v4.0.30319' -Name SchUseStrongCrypto -Value 1 -Type DWord
+  EOT
+
+  command_timeout = "2m"
+
+  triggers = {
+    baseline = var.tls_baseline_version
+  }
+}
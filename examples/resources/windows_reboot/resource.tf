@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Reboot once IIS is installed, before configuring its service.
+resource "windows_feature" "iis" {
+  name = "Web-Server"
+}
+
+resource "windows_reboot" "after_iis" {
+  triggers = {
+    feature = windows_feature.iis.id
+  }
+}
+
+resource "windows_service" "w3svc" {
+  name       = "W3SVC"
+  status     = "Running"
+  depends_on = [windows_reboot.after_iis]
+}
+
+# Allow a slow boot after patching.
+resource "windows_reboot" "patch" {
+  triggers = {
+    kb = "KB5031364"
+  }
+  timeout = "45m"
+}
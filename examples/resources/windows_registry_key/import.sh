@@ -0,0 +1,2 @@
+# Import an existing key by its full path.
+terraform import windows_registry_key.app 'HKLM:\SOFTWARE\Contoso\MyApp'
@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Create an application key and write a value under it.
+resource "windows_registry_key" "app" {
+  path = "HKLM:\\SOFTWARE\\Contoso\\MyApp"
+}
+
+resource "windows_registry_value" "version" {
+  hive                  = "HKLM"
+  path                  = "SOFTWARE\\Contoso\\MyApp"
+  name                  = "Version"
+  type                  = "REG_SZ"
+  value_string          = "1.2.3"
+  create_key_if_missing = false
+  depends_on            = [windows_registry_key.app]
+}
+
+# Adopt a key that already exists and remove it with its children on destroy.
+resource "windows_registry_key" "legacy" {
+  path             = "HKLM\\SOFTWARE\\LegacyVendor"
+  allow_existing   = true
+  delete_recursive = true
+}
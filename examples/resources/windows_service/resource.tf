@@ -19,7 +19,8 @@ resource "windows_service" "example" {
   name         = "example-svc"
   display_name = "Example Service"
   description  = "Managed by Terraform."
-  binary_path  = "C:\\Program Files\\Example\\example.exe --run"
+  binary_path  = "C:\\Program Files\\Example\\example.exe"
+  arguments    = "--run --config \"C:\\Example Data\\config.json\""
   start_type   = "Automatic"
   status       = "Running"
 }
@@ -36,3 +37,18 @@ resource "windows_service" "app" {
   service_password = var.svc_myapp_password
   dependencies     = ["LanmanServer", "Tcpip"]
 }
+
+# Restart twice on failure, then run a notification script.
+resource "windows_service" "worker" {
+  name        = "myworker"
+  binary_path = "C:\\Program Files\\MyApp\\worker.exe"
+
+  recovery = {
+    first_action          = "Restart"
+    second_action         = "Restart"
+    subsequent_actions    = "Run"
+    command               = "C:\\ops\\notify.cmd myworker"
+    restart_delay_seconds = 30
+    reset_period_seconds  = 3600
+  }
+}
@@ -0,0 +1,2 @@
+# Import the state of a service by its short name.
+terraform import windows_service_state.spooler Spooler
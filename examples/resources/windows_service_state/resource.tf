@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Stop and disable the Print Spooler.
+resource "windows_service_state" "spooler" {
+  name       = "Spooler"
+  state      = "Stopped"
+  start_type = "Disabled"
+}
+
+# Keep IIS running, whatever its start type.
+resource "windows_feature" "iis" {
+  name = "Web-Server"
+}
+
+resource "windows_service_state" "w3svc" {
+  name  = "W3SVC"
+  state = "Running"
+
+  depends_on = [windows_feature.iis]
+}
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    windows = {
+      source  = "kfrlabs/windows"
+      version = "~> 0.0"
+    }
+  }
+}
+
+provider "windows" {
+  host      = var.windows_host
+  username  = var.windows_username
+  password  = var.windows_password
+  auth_type = "ntlm"
+}
+
+# Wait for the IIS service to run after installing the feature.
+resource "windows_feature" "iis" {
+  name = "Web-Server"
+}
+
+resource "windows_wait_for_service" "w3svc" {
+  name    = "W3SVC"
+  timeout = "10m"
+
+  triggers = {
+    feature = windows_feature.iis.id
+  }
+}
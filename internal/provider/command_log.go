@@ -0,0 +1,51 @@
+// Package provider: logging of every WinRM run.
+//
+// commandLogObserver is the winclient.CommandObserver set by Configure. It
+// logs each run's operation, duration, byte counts and new connections with
+// tflog, on the context of the resource or data source operation that issued
+// it, so TF_LOG=DEBUG output carries tf_resource_type / tf_data_source_type
+// and tf_rpc next to every duration and slow operations can be found without
+// guessing.
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+var _ winclient.CommandObserver = commandLogObserver{}
+
+// commandLogObserver logs WinRM runs with tflog.
+type commandLogObserver struct{}
+
+// OnStart logs the start of a run at trace level.
+func (commandLogObserver) OnStart(ctx context.Context, ev winclient.CommandStart) {
+	tflog.Trace(ctx, "WinRM command started", map[string]interface{}{"host": ev.Host, "operation": ev.Op})
+}
+
+// OnComplete logs the outcome of a run at debug level.
+func (commandLogObserver) OnComplete(ctx context.Context, ev winclient.CommandResult) {
+	fields := map[string]interface{}{
+		"host":        ev.Host,
+		"operation":   ev.Op,
+		"duration_ms": ev.Duration.Milliseconds(),
+	}
+	msg := "WinRM command refused"
+	if ev.Sent {
+		msg = "WinRM command completed"
+		fields["stdin_bytes"] = ev.StdinBytes
+		fields["stdout_bytes"] = ev.StdoutBytes
+		fields["stderr_bytes"] = ev.StderrBytes
+		fields["new_connections"] = ev.NewConnections
+	}
+	if ev.Err != nil {
+		fields["error"] = ev.Err.Error()
+		if kind := winclient.TransportFailureKind(ev.Err); kind != "" {
+			fields["failure_kind"] = string(kind)
+		}
+	}
+	tflog.Debug(ctx, msg, fields)
+}
@@ -0,0 +1,196 @@
+// Package provider: windows_account_translate data source implementation.
+//
+// Resolves a SID to an account name or an account name to a SID on the remote
+// host (exactly one of the two must be provided), e.g. to turn a well-known
+// SID into the localized group name a non-English host expects. WinRM
+// interaction is delegated to winclient.AccountClient
+// (internal/winclient/account.go).
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource                     = (*windowsAccountTranslateDataSource)(nil)
+	_ datasource.DataSourceWithConfigure        = (*windowsAccountTranslateDataSource)(nil)
+	_ datasource.DataSourceWithConfigValidators = (*windowsAccountTranslateDataSource)(nil)
+)
+
+// NewWindowsAccountTranslateDataSource is the constructor registered in
+// provider.go.
+func NewWindowsAccountTranslateDataSource() datasource.DataSource {
+	return &windowsAccountTranslateDataSource{}
+}
+
+// windowsAccountTranslateDataSource is the TPF data source type for
+// windows_account_translate.
+type windowsAccountTranslateDataSource struct {
+	client winclient.WindowsAccountClient
+}
+
+// windowsAccountTranslateDataSourceModel is the Terraform state model for the
+// windows_account_translate data source.
+type windowsAccountTranslateDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	SID         types.String `tfsdk:"sid"`
+	AccountName types.String `tfsdk:"account_name"`
+	Domain      types.String `tfsdk:"domain"`
+	AccountType types.String `tfsdk:"account_type"`
+}
+
+// Metadata sets the data source type name ("windows_account_translate").
+func (d *windowsAccountTranslateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_translate"
+}
+
+// Schema returns the TPF schema for the windows_account_translate data source.
+func (d *windowsAccountTranslateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Translates a SID to an account name, or an account name to a SID, on the remote Windows host.\n\n" +
+			"Exactly one of `sid` or `account_name` must be provided. The other attribute is populated from " +
+			"the host's translation, along with `domain` and `account_type`.\n\n" +
+			"Returns an error when the SID or name does not map to an account (e.g. a deleted domain account).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID; equal to the SID.",
+			},
+			"sid": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "Security Identifier to translate (e.g. \"S-1-5-32-544\"). Exactly one of sid or account_name must be specified.",
+				MarkdownDescription: "Security Identifier (SID) to translate (e.g. `S-1-5-32-544`). Exactly one of `sid` or `account_name` must be specified.",
+			},
+			"account_name": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Account name to translate: DOMAIN\\name, name@domain or a bare name. Populated in " +
+					"canonical DOMAIN\\name form (bare for principals such as Everyone). Exactly one of sid or " +
+					"account_name must be specified.",
+			},
+			"domain": schema.StringAttribute{
+				Computed:    true,
+				Description: "Domain part of the account name (e.g. BUILTIN, NT AUTHORITY, the computer name or a domain); empty when there is none.",
+			},
+			"account_type": schema.StringAttribute{
+				Computed: true,
+				Description: "Account type: User, Group, Alias (local group), WellKnownGroup, Computer, Domain, " +
+					"DeletedAccount, Invalid, or Unknown when Windows does not say.",
+			},
+		},
+	}
+}
+
+// ConfigValidators enforces ExactlyOneOf(sid, account_name).
+func (d *windowsAccountTranslateDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("sid"),
+			path.MatchRoot("account_name"),
+		),
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsAccountTranslateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.client = winclient.NewAccountClient(c)
+}
+
+// Read translates the configured SID or account name on the remote host.
+func (d *windowsAccountTranslateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsAccountTranslateDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "windows_account_translate data source Read start", map[string]interface{}{
+		"sid":          config.SID.ValueString(),
+		"account_name": config.AccountName.ValueString(),
+	})
+
+	var acct *winclient.Account
+	var err error
+	key := config.SID.ValueString()
+	if !config.AccountName.IsNull() && config.AccountName.ValueString() != "" {
+		key = config.AccountName.ValueString()
+		acct, err = d.client.LookupName(ctx, key)
+	} else {
+		acct, err = d.client.LookupSID(ctx, key)
+	}
+	if err != nil {
+		if winclient.IsAccountError(err, winclient.AccountErrorNotFound) {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Data source not found: windows_account_translate %q", key),
+				fmt.Sprintf("%q does not map to an account on the target host. The account may have been "+
+					"deleted, or belong to a domain the host cannot reach or does not trust.", key),
+			)
+			return
+		}
+		addAccountDiag(&resp.Diagnostics, "Read windows_account_translate data source failed", err)
+		return
+	}
+
+	state := windowsAccountTranslateDataSourceModel{
+		ID:          types.StringValue(acct.SID),
+		SID:         types.StringValue(acct.SID),
+		AccountName: types.StringValue(acct.AccountName),
+		Domain:      types.StringValue(acct.Domain),
+		AccountType: types.StringValue(acct.AccountType),
+	}
+
+	tflog.Debug(ctx, "windows_account_translate data source Read end", map[string]interface{}{
+		"sid":          state.SID.ValueString(),
+		"account_name": state.AccountName.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// addAccountDiag converts a winclient error into a Terraform diagnostic.
+func addAccountDiag(diags *diag.Diagnostics, summary string, err error) {
+	var ae *winclient.AccountError
+	if errors.As(err, &ae) {
+		detail := ae.Message
+		if ae.Kind == winclient.AccountErrorInvalidParameter {
+			detail += "\n\nsid must be a SID in S-1-... form."
+		}
+		if len(ae.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range ae.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if ae.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", ae.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
@@ -0,0 +1,62 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for the windows_account_translate data source.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsAccountTranslateDataSource
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsAccountTranslateDataSource_RoundTrip resolves the
+// Administrators alias by SID, then its name back to the same SID.
+func TestAccWindowsAccountTranslateDataSource_RoundTrip(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "windows_account_translate" "by_sid" {
+  sid = "S-1-5-32-544"
+}
+
+data "windows_account_translate" "by_name" {
+  account_name = data.windows_account_translate.by_sid.account_name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_account_translate.by_sid", "domain", "BUILTIN"),
+					resource.TestCheckResourceAttr("data.windows_account_translate.by_sid", "account_type", "Alias"),
+					resource.TestCheckResourceAttr("data.windows_account_translate.by_name", "sid", "S-1-5-32-544"),
+					resource.TestCheckResourceAttrPair("data.windows_account_translate.by_name", "account_name",
+						"data.windows_account_translate.by_sid", "account_name"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccWindowsAccountTranslateDataSource_Unmapped expects a clear error for
+// a well-formed SID that maps to no account.
+func TestAccWindowsAccountTranslateDataSource_Unmapped(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "windows_account_translate" "orphan" {
+  sid = "S-1-5-21-1-2-3-999999"
+}
+`,
+				ExpectError: regexp.MustCompile(`does not map to an account`),
+			},
+		},
+	})
+}
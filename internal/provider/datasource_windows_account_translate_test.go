@@ -0,0 +1,160 @@
+// Package provider — unit tests for the windows_account_translate data source.
+//
+// A fakeAccountClient is injected into windowsAccountTranslateDataSource.client,
+// so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeAccountClient struct {
+	out *winclient.Account
+	err error
+
+	bySID, byName string
+}
+
+func (f *fakeAccountClient) LookupSID(_ context.Context, sid string) (*winclient.Account, error) {
+	f.bySID = sid
+	return f.out, f.err
+}
+
+func (f *fakeAccountClient) LookupName(_ context.Context, name string) (*winclient.Account, error) {
+	f.byName = name
+	return f.out, f.err
+}
+
+func accountDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":           tftypes.String,
+		"sid":          tftypes.String,
+		"account_name": tftypes.String,
+		"domain":       tftypes.String,
+		"account_type": tftypes.String,
+	}}
+}
+
+func accountDSRead(t *testing.T, client winclient.WindowsAccountClient, sid, name interface{}) (*datasource.ReadResponse, windowsAccountTranslateDataSourceModel) {
+	t.Helper()
+	d := &windowsAccountTranslateDataSource{client: client}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	cfg := tfsdk.Config{Schema: sr.Schema, Raw: tftypes.NewValue(accountDSObjType(), map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, nil),
+		"sid":          tftypes.NewValue(tftypes.String, sid),
+		"account_name": tftypes.NewValue(tftypes.String, name),
+		"domain":       tftypes.NewValue(tftypes.String, nil),
+		"account_type": tftypes.NewValue(tftypes.String, nil),
+	})}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sr.Schema, Raw: tftypes.NewValue(accountDSObjType(), nil)}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var got windowsAccountTranslateDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+var adminsAccount = &winclient.Account{
+	SID: "S-1-5-32-544", AccountName: `BUILTIN\Administrators`, Domain: "BUILTIN", AccountType: "Alias",
+}
+
+func TestAccountTranslateDSMetadataAndSchema(t *testing.T) {
+	d := &windowsAccountTranslateDataSource{}
+	mr := &datasource.MetadataResponse{}
+	d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, mr)
+	if mr.TypeName != "windows_account_translate" {
+		t.Errorf("TypeName = %q", mr.TypeName)
+	}
+	sr := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, sr)
+	for _, k := range []string{"sid", "account_name"} {
+		if a := sr.Schema.Attributes[k]; !a.IsOptional() || !a.IsComputed() {
+			t.Errorf("%q must be Optional+Computed", k)
+		}
+	}
+	for _, k := range []string{"id", "domain", "account_type"} {
+		if a, ok := sr.Schema.Attributes[k]; !ok || !a.IsComputed() || a.IsOptional() {
+			t.Errorf("%q must be Computed only", k)
+		}
+	}
+	if len(d.ConfigValidators(context.Background())) != 1 {
+		t.Error("expected the ExactlyOneOf validator")
+	}
+}
+
+func TestAccountTranslateDSRead_BySID(t *testing.T) {
+	fake := &fakeAccountClient{out: adminsAccount}
+	resp, got := accountDSRead(t, fake, "S-1-5-32-544", nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if fake.bySID != "S-1-5-32-544" || fake.byName != "" {
+		t.Errorf("wrong lookup: sid=%q name=%q", fake.bySID, fake.byName)
+	}
+	if got.ID.ValueString() != "S-1-5-32-544" || got.AccountName.ValueString() != `BUILTIN\Administrators` ||
+		got.Domain.ValueString() != "BUILTIN" || got.AccountType.ValueString() != "Alias" {
+		t.Errorf("unexpected state: %+v", got)
+	}
+}
+
+func TestAccountTranslateDSRead_ByName(t *testing.T) {
+	fake := &fakeAccountClient{out: adminsAccount}
+	resp, got := accountDSRead(t, fake, nil, "administrators")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if fake.byName != "administrators" || fake.bySID != "" {
+		t.Errorf("wrong lookup: sid=%q name=%q", fake.bySID, fake.byName)
+	}
+	if got.SID.ValueString() != "S-1-5-32-544" || got.AccountName.ValueString() != `BUILTIN\Administrators` {
+		t.Errorf("account_name must be canonical: %+v", got)
+	}
+}
+
+func TestAccountTranslateDSRead_Errors(t *testing.T) {
+	fake := &fakeAccountClient{err: winclient.NewAccountError(winclient.AccountErrorNotFound,
+		"Some or all identity references could not be translated.", nil, nil)}
+	resp, _ := accountDSRead(t, fake, "S-1-5-21-1-2-3-1001", nil)
+	if !resp.Diagnostics.HasError() ||
+		!strings.Contains(resp.Diagnostics[0].Summary(), `windows_account_translate "S-1-5-21-1-2-3-1001"`) ||
+		!strings.Contains(resp.Diagnostics[0].Detail(), "does not map to an account") {
+		t.Errorf("unmappable SID must give a clear error: %v", resp.Diagnostics)
+	}
+
+	fake = &fakeAccountClient{err: winclient.NewAccountError(winclient.AccountErrorInvalidParameter,
+		"Value was invalid.", nil, map[string]string{"host": "win01"})}
+	resp, _ = accountDSRead(t, fake, "not-a-sid", nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: invalid_parameter") {
+		t.Errorf("expected invalid_parameter diag: %v", resp.Diagnostics)
+	}
+
+	resp, _ = accountDSRead(t, &fakeAccountClient{err: errors.New("boom")}, "S-1-5-18", nil)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Detail() != "boom" {
+		t.Errorf("expected plain error: %v", resp.Diagnostics)
+	}
+}
+
+func TestAccountTranslateDSConfigure(t *testing.T) {
+	d := &windowsAccountTranslateDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: "nope"}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("wrong type must produce error")
+	}
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || d.client == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,165 @@
+// Package provider: windows_connection_stats data source implementation.
+//
+// Singleton data source — no lookup keys. Reports the run statistics the
+// shared winclient.Client has recorded in this provider process (see
+// internal/winclient/health.go). It is read from memory: no WinRM run is
+// made, so reading it does not change the counters it reports.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsConnectionStatsDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsConnectionStatsDataSource)(nil)
+)
+
+// connectionStatsSource is the subset of *winclient.Client the data source
+// reads. It exists so tests can substitute a fake.
+type connectionStatsSource interface {
+	Config() winclient.Config
+	ConnectionStats() winclient.ConnectionStats
+	InFlight() int
+}
+
+// NewWindowsConnectionStatsDataSource is the constructor registered in provider.go.
+func NewWindowsConnectionStatsDataSource() datasource.DataSource {
+	return &windowsConnectionStatsDataSource{}
+}
+
+// windowsConnectionStatsDataSource is the TPF data source type for
+// windows_connection_stats.
+type windowsConnectionStatsDataSource struct {
+	client connectionStatsSource
+}
+
+// windowsConnectionStatsDataSourceModel is the Terraform state model for the
+// windows_connection_stats data source.
+type windowsConnectionStatsDataSourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Host            types.String `tfsdk:"host"`
+	InFlightRuns    types.Int64  `tfsdk:"in_flight_runs"`
+	Runs            types.Int64  `tfsdk:"runs"`
+	DialFailures    types.Int64  `tfsdk:"dial_failures"`
+	AuthFailures    types.Int64  `tfsdk:"auth_failures"`
+	CommandFailures types.Int64  `tfsdk:"command_failures"`
+	Cancelled       types.Int64  `tfsdk:"cancelled"`
+	LastFailureKind types.String `tfsdk:"last_failure_kind"`
+	LastFailure     types.String `tfsdk:"last_failure"`
+}
+
+// Metadata sets the data source type name ("windows_connection_stats").
+func (d *windowsConnectionStatsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_connection_stats"
+}
+
+// Schema returns the TPF schema for the windows_connection_stats data source.
+func (d *windowsConnectionStatsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports the WinRM run statistics the provider has recorded so far in this " +
+			"Terraform run: runs in flight, completed runs and failures by kind. The values are read from " +
+			"the provider's memory, so no command is sent to the host. This is a **singleton** data " +
+			"source — no lookup keys are required.\n\n" +
+			"Counters start at zero when the provider process starts; values read during `plan` only " +
+			"cover the runs made before this data source was read.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID; the configured host.",
+			},
+			"host": schema.StringAttribute{
+				Computed:    true,
+				Description: "Host the statistics refer to.",
+			},
+			"in_flight_runs": schema.Int64Attribute{
+				Computed:    true,
+				Description: "PowerShell runs executing when the data source was read.",
+			},
+			"runs": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Runs that reached the transport, successful or not.",
+			},
+			"dial_failures": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Runs that failed because the host could not be reached.",
+			},
+			"auth_failures": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Runs that failed because the host rejected the credentials or the TLS handshake.",
+			},
+			"command_failures": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Runs that reached the host but failed (non-zero exit code, SOAP fault, connection lost).",
+			},
+			"cancelled": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Runs abandoned because their operation was cancelled or timed out.",
+			},
+			"last_failure_kind": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Kind of the most recent failure: dial, auth or command. Empty when none occurred.",
+				MarkdownDescription: "Kind of the most recent failure: `dial`, `auth` or `command`. Empty when none occurred.",
+			},
+			"last_failure": schema.StringAttribute{
+				Computed:    true,
+				Description: "Error message of the most recent failure. Empty when none occurred.",
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsConnectionStatsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.client = c
+}
+
+// Read snapshots the client's statistics.
+func (d *windowsConnectionStatsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsConnectionStatsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host := d.client.Config().Host
+	st := d.client.ConnectionStats()
+	state := windowsConnectionStatsDataSourceModel{
+		ID:              types.StringValue(host),
+		Host:            types.StringValue(host),
+		InFlightRuns:    types.Int64Value(int64(d.client.InFlight())),
+		Runs:            types.Int64Value(int64(st.Runs)),
+		DialFailures:    types.Int64Value(int64(st.DialFailures)),
+		AuthFailures:    types.Int64Value(int64(st.AuthFailures)),
+		CommandFailures: types.Int64Value(int64(st.CommandFailures)),
+		Cancelled:       types.Int64Value(int64(st.Cancelled)),
+		LastFailureKind: types.StringValue(string(st.LastFailureKind)),
+		LastFailure:     types.StringValue(st.LastFailure),
+	}
+
+	tflog.Debug(ctx, "windows_connection_stats data source Read", map[string]interface{}{
+		"host": host, "runs": st.Runs, "in_flight": state.InFlightRuns.ValueInt64(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,115 @@
+// Package provider — unit tests for the windows_connection_stats data source.
+//
+// windows_connection_stats is a singleton read from the client's memory.
+// Tests cover: Metadata, Schema, Configure, Read.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeConnectionStatsSource struct {
+	stats    winclient.ConnectionStats
+	inFlight int
+}
+
+func (f *fakeConnectionStatsSource) Config() winclient.Config {
+	return winclient.Config{Host: "win01.example.com"}
+}
+func (f *fakeConnectionStatsSource) ConnectionStats() winclient.ConnectionStats { return f.stats }
+func (f *fakeConnectionStatsSource) InFlight() int                              { return f.inFlight }
+
+var connectionStatsDSAttrs = []string{
+	"id", "host", "in_flight_runs", "runs", "dial_failures", "auth_failures",
+	"command_failures", "cancelled", "last_failure_kind", "last_failure",
+}
+
+func connectionStatsDSConfig() tfsdk.Config {
+	d := &windowsConnectionStatsDataSource{}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	types := map[string]tftypes.Type{}
+	vals := map[string]tftypes.Value{}
+	for _, k := range connectionStatsDSAttrs {
+		typ := tftypes.Type(tftypes.Number)
+		switch k {
+		case "id", "host", "last_failure_kind", "last_failure":
+			typ = tftypes.String
+		}
+		types[k] = typ
+		vals[k] = tftypes.NewValue(typ, nil)
+	}
+	return tfsdk.Config{
+		Schema: sr.Schema,
+		Raw:    tftypes.NewValue(tftypes.Object{AttributeTypes: types}, vals),
+	}
+}
+
+func TestConnectionStatsDSMetadataAndSchema(t *testing.T) {
+	d := NewWindowsConnectionStatsDataSource()
+	mresp := &datasource.MetadataResponse{}
+	d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, mresp)
+	if mresp.TypeName != "windows_connection_stats" {
+		t.Errorf("TypeName = %q, want windows_connection_stats", mresp.TypeName)
+	}
+
+	sresp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, sresp)
+	if len(sresp.Schema.Attributes) != len(connectionStatsDSAttrs) {
+		t.Errorf("schema has %d attributes, want %d", len(sresp.Schema.Attributes), len(connectionStatsDSAttrs))
+	}
+	for _, k := range connectionStatsDSAttrs {
+		a, ok := sresp.Schema.Attributes[k]
+		if !ok || !a.IsComputed() {
+			t.Errorf("attribute %q must exist and be computed", k)
+		}
+	}
+}
+
+func TestConnectionStatsDSConfigure(t *testing.T) {
+	d := &windowsConnectionStatsDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: 42}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "winclient.Client") {
+		t.Errorf("wrong type must produce error, got %v", resp.Diagnostics)
+	}
+
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || d.client == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
+
+func TestConnectionStatsDSRead(t *testing.T) {
+	d := &windowsConnectionStatsDataSource{client: &fakeConnectionStatsSource{
+		inFlight: 2,
+		stats: winclient.ConnectionStats{
+			Runs: 40, DialFailures: 3, CommandFailures: 1, Cancelled: 2,
+			LastFailureKind: winclient.FailureDial, LastFailure: "dial tcp: connection refused",
+		},
+	}}
+	cfg := connectionStatsDSConfig()
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	var state windowsConnectionStatsDataSourceModel
+	resp.State.Get(context.Background(), &state)
+	if state.ID.ValueString() != "win01.example.com" || state.InFlightRuns.ValueInt64() != 2 ||
+		state.Runs.ValueInt64() != 40 || state.DialFailures.ValueInt64() != 3 ||
+		state.AuthFailures.ValueInt64() != 0 || state.CommandFailures.ValueInt64() != 1 ||
+		state.Cancelled.ValueInt64() != 2 || state.LastFailureKind.ValueString() != "dial" ||
+		state.LastFailure.ValueString() != "dial tcp: connection refused" {
+		t.Errorf("unexpected state: %+v", state)
+	}
+}
@@ -119,7 +119,8 @@ func (d *windowsFeatureDataSource) Read(ctx context.Context, req datasource.Read
 	name := config.Name.ValueString()
 	tflog.Debug(ctx, "windows_feature data source Read start", map[string]interface{}{"name": name})
 
-	info, err := d.feat.Read(ctx, name)
+	// Repeated references to the same feature within a plan share one read.
+	info, err := d.feat.ReadCached(ctx, name)
 	if err != nil {
 		var fe *winclient.FeatureError
 		if errors.As(err, &fe) && fe.Kind == winclient.FeatureErrorNotFound {
@@ -24,11 +24,16 @@ import (
 // ---------------------------------------------------------------------------
 
 type fakeFeatureClientDS struct {
-	readOut *winclient.FeatureInfo
-	readErr error
+	readOut     *winclient.FeatureInfo
+	readErr     error
+	cachedReads int
 }
 
 func (f *fakeFeatureClientDS) Read(_ context.Context, _ string) (*winclient.FeatureInfo, error) {
+	panic("the data source must read through ReadCached")
+}
+func (f *fakeFeatureClientDS) ReadCached(_ context.Context, _ string) (*winclient.FeatureInfo, error) {
+	f.cachedReads++
 	return f.readOut, f.readErr
 }
 func (f *fakeFeatureClientDS) Install(_ context.Context, _ winclient.FeatureInput) (*winclient.FeatureInfo, *winclient.InstallResult, error) {
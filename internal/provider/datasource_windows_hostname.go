@@ -1,7 +1,8 @@
 // Package provider: windows_hostname data source implementation.
 //
 // Singleton data source — no lookup keys. Reads the current hostname state
-// of the remote Windows host (active name, pending name, reboot flag, machine ID).
+// of the remote Windows host (active name, pending name, reboot flag, machine ID)
+// and its network identity (FQDN, domain or workgroup, primary DNS suffix).
 // Input-only resource attributes (name, force) are absent from this data source.
 package provider
 
@@ -41,6 +42,11 @@ type windowsHostnameDataSourceModel struct {
 	PendingName   types.String `tfsdk:"pending_name"`
 	RebootPending types.Bool   `tfsdk:"reboot_pending"`
 	MachineID     types.String `tfsdk:"machine_id"`
+	FQDN          types.String `tfsdk:"fqdn"`
+	PartOfDomain  types.Bool   `tfsdk:"part_of_domain"`
+	Domain        types.String `tfsdk:"domain"`
+	Workgroup     types.String `tfsdk:"workgroup"`
+	DNSSuffix     types.String `tfsdk:"dns_suffix"`
 }
 
 // Metadata sets the data source type name ("windows_hostname").
@@ -55,6 +61,8 @@ func (d *windowsHostnameDataSource) Schema(_ context.Context, _ datasource.Schem
 			"managing it. This is a **singleton** data source — no lookup keys are required.\n\n" +
 			"Exposes `current_name` (active name), `pending_name` (effective after next reboot), " +
 			"`reboot_pending`, and the stable `machine_id` (HKLM MachineGuid).\n\n" +
+			"Also exposes the host's network identity (`fqdn`, `part_of_domain`, `domain` or " +
+			"`workgroup`, `dns_suffix`), e.g. to build AD-join names or certificate SANs.\n\n" +
 			"The Terraform data source ID is always `\"current\"`.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -73,14 +81,40 @@ func (d *windowsHostnameDataSource) Schema(_ context.Context, _ datasource.Schem
 			},
 			"reboot_pending": schema.BoolAttribute{
 				Computed:            true,
-				Description:         "True when pending_name (case-insensitive) differs from current_name.",
-				MarkdownDescription: "True when `pending_name` differs from `current_name` (case-insensitive).",
+				Description:         "True when pending_name differs from current_name, or the pending DNS host name (Tcpip NV Hostname) differs from the active one. Case-insensitive.",
+				MarkdownDescription: "True when `pending_name` differs from `current_name`, or when the pending DNS host name (Tcpip `NV Hostname`) differs from the active `Hostname` (case-insensitive).",
 			},
 			"machine_id": schema.StringAttribute{
 				Computed:            true,
 				Description:         "Stable per-machine identifier (HKLM Cryptography MachineGuid).",
 				MarkdownDescription: "Stable per-machine identifier read from `HKLM:\\SOFTWARE\\Microsoft\\Cryptography\\MachineGuid`.",
 			},
+			"fqdn": schema.StringAttribute{
+				Computed:    true,
+				Description: "Lower-case fully qualified name from [System.Net.Dns]::GetHostEntry. Falls back to current_name plus dns_suffix (or current_name alone) when DNS returns no dotted name.",
+				MarkdownDescription: "Lower-case fully qualified name from `[System.Net.Dns]::GetHostEntry`. Falls back to " +
+					"`current_name` plus `dns_suffix` (or `current_name` alone) when DNS returns no dotted name.",
+			},
+			"part_of_domain": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "True when the host is joined to an Active Directory domain (Win32_ComputerSystem.PartOfDomain).",
+				MarkdownDescription: "True when the host is joined to an Active Directory domain (`Win32_ComputerSystem.PartOfDomain`).",
+			},
+			"domain": schema.StringAttribute{
+				Computed:            true,
+				Description:         "AD domain name when part_of_domain is true; empty on a workgroup host.",
+				MarkdownDescription: "AD domain name when `part_of_domain` is true; empty on a workgroup host.",
+			},
+			"workgroup": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Workgroup name when part_of_domain is false; empty on a domain-joined host.",
+				MarkdownDescription: "Workgroup name when `part_of_domain` is false; empty on a domain-joined host.",
+			},
+			"dns_suffix": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Primary DNS suffix of the host. Empty when none is configured.",
+				MarkdownDescription: "Primary DNS suffix of the host (`Tcpip\\Parameters` `Domain`). Empty when none is configured.",
+			},
 		},
 	}
 }
@@ -124,11 +158,17 @@ func (d *windowsHostnameDataSource) Read(ctx context.Context, req datasource.Rea
 		PendingName:   types.StringValue(live.PendingName),
 		RebootPending: types.BoolValue(live.RebootPending),
 		MachineID:     types.StringValue(live.MachineID),
+		FQDN:          types.StringValue(live.FQDN),
+		PartOfDomain:  types.BoolValue(live.PartOfDomain),
+		Domain:        types.StringValue(live.Domain),
+		Workgroup:     types.StringValue(live.Workgroup),
+		DNSSuffix:     types.StringValue(live.DNSSuffix),
 	}
 
 	tflog.Debug(ctx, "windows_hostname data source Read end", map[string]interface{}{
 		"current_name": state.CurrentName.ValueString(),
 		"machine_id":   state.MachineID.ValueString(),
+		"fqdn":         state.FQDN.ValueString(),
 	})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -50,6 +50,11 @@ func hostnameDSObjType() tftypes.Object {
 		"pending_name":   tftypes.String,
 		"reboot_pending": tftypes.Bool,
 		"machine_id":     tftypes.String,
+		"fqdn":           tftypes.String,
+		"part_of_domain": tftypes.Bool,
+		"domain":         tftypes.String,
+		"workgroup":      tftypes.String,
+		"dns_suffix":     tftypes.String,
 	}}
 }
 
@@ -65,6 +70,11 @@ func hostnameDSConfig() tfsdk.Config {
 			"pending_name":   tftypes.NewValue(tftypes.String, nil),
 			"reboot_pending": tftypes.NewValue(tftypes.Bool, nil),
 			"machine_id":     tftypes.NewValue(tftypes.String, nil),
+			"fqdn":           tftypes.NewValue(tftypes.String, nil),
+			"part_of_domain": tftypes.NewValue(tftypes.Bool, nil),
+			"domain":         tftypes.NewValue(tftypes.String, nil),
+			"workgroup":      tftypes.NewValue(tftypes.String, nil),
+			"dns_suffix":     tftypes.NewValue(tftypes.String, nil),
 		}),
 	}
 }
@@ -96,7 +106,8 @@ func TestHostnameDSSchema_AllAttributes(t *testing.T) {
 	d := &windowsHostnameDataSource{}
 	resp := &datasource.SchemaResponse{}
 	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
-	want := []string{"id", "current_name", "pending_name", "reboot_pending", "machine_id"}
+	want := []string{"id", "current_name", "pending_name", "reboot_pending", "machine_id",
+		"fqdn", "part_of_domain", "domain", "workgroup", "dns_suffix"}
 	for _, k := range want {
 		if _, ok := resp.Schema.Attributes[k]; !ok {
 			t.Errorf("schema missing attribute %q", k)
@@ -121,9 +132,9 @@ func TestHostnameDSSchema_NoExtraAttributes(t *testing.T) {
 	d := &windowsHostnameDataSource{}
 	resp := &datasource.SchemaResponse{}
 	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
-	// Singleton: 5 attributes (id + 4 computed).
-	if len(resp.Schema.Attributes) != 5 {
-		t.Errorf("schema has %d attributes, want 5", len(resp.Schema.Attributes))
+	// Singleton: 10 attributes (id + 4 hostname + 5 identity, all computed).
+	if len(resp.Schema.Attributes) != 10 {
+		t.Errorf("schema has %d attributes, want 10", len(resp.Schema.Attributes))
 	}
 }
 
@@ -198,6 +209,42 @@ func TestHostnameDSRead_HappyPath(t *testing.T) {
 	}
 }
 
+func TestHostnameDSRead_Identity(t *testing.T) {
+	cases := []struct {
+		name string
+		in   winclient.HostnameState
+	}{
+		{"domain", winclient.HostnameState{
+			CurrentName: "WEB01", PendingName: "WEB01", MachineID: "g",
+			FQDN: "web01.corp.example.com", PartOfDomain: true, Domain: "corp.example.com", DNSSuffix: "corp.example.com",
+		}},
+		{"workgroup", winclient.HostnameState{
+			CurrentName: "WEB01", PendingName: "WEB01", MachineID: "g",
+			FQDN: "web01", Workgroup: "WORKGROUP",
+		}},
+	}
+	for _, tc := range cases {
+		in := tc.in
+		d := &windowsHostnameDataSource{hn: &fakeHostnameClientDS{readOut: &in}}
+		cfg := hostnameDSConfig()
+		resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+		d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected errors: %v", tc.name, resp.Diagnostics)
+		}
+		var state windowsHostnameDataSourceModel
+		resp.State.Get(context.Background(), &state)
+		if state.FQDN.ValueString() != in.FQDN || state.PartOfDomain.ValueBool() != in.PartOfDomain ||
+			state.Domain.ValueString() != in.Domain || state.Workgroup.ValueString() != in.Workgroup ||
+			state.DNSSuffix.ValueString() != in.DNSSuffix {
+			t.Errorf("%s: state = %+v", tc.name, state)
+		}
+		if state.Domain.IsNull() || state.DNSSuffix.IsNull() {
+			t.Errorf("%s: empty identity fields must be \"\", not null", tc.name)
+		}
+	}
+}
+
 func TestHostnameDSRead_RebootPending(t *testing.T) {
 	d := &windowsHostnameDataSource{
 		hn: &fakeHostnameClientDS{
@@ -0,0 +1,220 @@
+// Package provider: windows_hotfix data source implementation.
+//
+// Reports whether an update is installed (hotfix_id set) or lists every
+// installed update (hotfix_id omitted), as seen by Get-HotFix. Typical use
+// is a precondition that a security update is present before a role is
+// configured.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsHotfixDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsHotfixDataSource)(nil)
+)
+
+// NewWindowsHotfixDataSource is the constructor registered in provider.go.
+func NewWindowsHotfixDataSource() datasource.DataSource {
+	return &windowsHotfixDataSource{}
+}
+
+// windowsHotfixDataSource is the TPF data source type for windows_hotfix.
+type windowsHotfixDataSource struct {
+	hf winclient.WindowsHotfixClient
+}
+
+// windowsHotfixDataSourceModel is the Terraform state model for the
+// windows_hotfix data source.
+type windowsHotfixDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	HotfixID  types.String `tfsdk:"hotfix_id"`
+	Installed types.Bool   `tfsdk:"installed"`
+	Hotfixes  types.List   `tfsdk:"hotfixes"`
+}
+
+// windowsHotfixModel is one element of the hotfixes list.
+type windowsHotfixModel struct {
+	HotfixID    types.String `tfsdk:"hotfix_id"`
+	Description types.String `tfsdk:"description"`
+	InstalledOn types.String `tfsdk:"installed_on"`
+	InstalledBy types.String `tfsdk:"installed_by"`
+}
+
+// hotfixAttrTypes is the attr.Type map for a hotfixes element.
+var hotfixAttrTypes = map[string]attr.Type{
+	"hotfix_id":    types.StringType,
+	"description":  types.StringType,
+	"installed_on": types.StringType,
+	"installed_by": types.StringType,
+}
+
+// Metadata sets the data source type name ("windows_hotfix").
+func (d *windowsHotfixDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hotfix"
+}
+
+// Schema returns the TPF schema for the windows_hotfix data source.
+func (d *windowsHotfixDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports the updates installed on the remote Windows host, as listed by `Get-HotFix` " +
+			"(`Win32_QuickFixEngineering`).\n\n" +
+			"With `hotfix_id` set, `installed` tells whether that update is present and `hotfixes` holds at most " +
+			"its entry; an update that is not installed is **not** an error. Without `hotfix_id`, `hotfixes` lists " +
+			"every installed update.\n\n" +
+			"`Get-HotFix` only reports updates serviced through CBS (security updates, cumulative updates); " +
+			"updates installed by MSI or other installers are not listed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Data source ID; the hotfix ID when hotfix_id is set, otherwise \"current\".",
+				MarkdownDescription: "Data source ID; the hotfix ID when `hotfix_id` is set, otherwise `\"current\"`.",
+			},
+			"hotfix_id": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^KB\d+$`), "must be a KB identifier such as KB5034123"),
+				},
+				Description:         "KB identifier of the update to look up, e.g. KB5034123. Omit to list every installed update.",
+				MarkdownDescription: "KB identifier of the update to look up, e.g. `KB5034123`. Omit to list every installed update.",
+			},
+			"installed": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether hotfix_id is installed. Without hotfix_id, whether any update is reported.",
+				MarkdownDescription: "Whether `hotfix_id` is installed. Without `hotfix_id`, whether any update is reported.",
+			},
+			"hotfixes": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching installed updates, sorted by hotfix ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"hotfix_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "KB identifier of the update.",
+						},
+						"description": schema.StringAttribute{
+							Computed:            true,
+							Description:         "Update category, e.g. Security Update.",
+							MarkdownDescription: "Update category, e.g. `Security Update`.",
+						},
+						"installed_on": schema.StringAttribute{
+							Computed:            true,
+							Description:         "Installation date as YYYY-MM-DD. Empty when the host does not record one.",
+							MarkdownDescription: "Installation date as `YYYY-MM-DD`. Empty when the host does not record one.",
+						},
+						"installed_by": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account that installed the update. Often empty.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsHotfixDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.hf = winclient.NewHotfixClient(c)
+}
+
+// Read looks up hotfix_id, or lists every installed update.
+func (d *windowsHotfixDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsHotfixDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hotfixID := config.HotfixID.ValueString()
+	tflog.Debug(ctx, "windows_hotfix data source Read start", map[string]interface{}{
+		"hotfix_id": hotfixID,
+	})
+
+	hotfixes, err := d.hf.List(ctx, hotfixID)
+	if err != nil {
+		addHotfixDiag(&resp.Diagnostics, "Read windows_hotfix data source failed", err)
+		return
+	}
+
+	elems := make([]attr.Value, 0, len(hotfixes))
+	for _, h := range hotfixes {
+		obj, diags := types.ObjectValueFrom(ctx, hotfixAttrTypes, windowsHotfixModel{
+			HotfixID:    types.StringValue(h.HotfixID),
+			Description: types.StringValue(h.Description),
+			InstalledOn: types.StringValue(h.InstalledOn),
+			InstalledBy: types.StringValue(h.InstalledBy),
+		})
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, obj)
+	}
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: hotfixAttrTypes}, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := "current"
+	if hotfixID != "" {
+		id = hotfixID
+	}
+	state := windowsHotfixDataSourceModel{
+		ID:        types.StringValue(id),
+		HotfixID:  config.HotfixID,
+		Installed: types.BoolValue(len(hotfixes) > 0),
+		Hotfixes:  list,
+	}
+
+	tflog.Debug(ctx, "windows_hotfix data source Read end", map[string]interface{}{
+		"hotfix_count": len(hotfixes),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// addHotfixDiag converts a winclient error into a Terraform diagnostic.
+func addHotfixDiag(diags *diag.Diagnostics, summary string, err error) {
+	var he *winclient.HotfixError
+	if errors.As(err, &he) {
+		detail := he.Message
+		if len(he.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range he.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if he.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", he.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
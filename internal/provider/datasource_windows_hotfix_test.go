@@ -0,0 +1,190 @@
+// Package provider — unit tests for the windows_hotfix data source.
+//
+// Tests cover: Metadata, Schema, Configure, Read by hotfix_id (installed and
+// not installed), Read listing every update, Read error.
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// ---------------------------------------------------------------------------
+// Fake client
+// ---------------------------------------------------------------------------
+
+type fakeHotfixClient struct {
+	out    []winclient.Hotfix
+	err    error
+	lastID string
+}
+
+func (f *fakeHotfixClient) List(_ context.Context, hotfixID string) ([]winclient.Hotfix, error) {
+	f.lastID = hotfixID
+	return f.out, f.err
+}
+
+// ---------------------------------------------------------------------------
+// tftypes helpers
+// ---------------------------------------------------------------------------
+
+func hotfixDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":        tftypes.String,
+		"hotfix_id": tftypes.String,
+		"installed": tftypes.Bool,
+		"hotfixes": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"hotfix_id":    tftypes.String,
+			"description":  tftypes.String,
+			"installed_on": tftypes.String,
+			"installed_by": tftypes.String,
+		}}},
+	}}
+}
+
+func hotfixDSConfig(hotfixID interface{}) tfsdk.Config {
+	d := &windowsHotfixDataSource{}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	objType := hotfixDSObjType()
+	return tfsdk.Config{
+		Schema: sr.Schema,
+		Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+			"id":        tftypes.NewValue(tftypes.String, nil),
+			"hotfix_id": tftypes.NewValue(tftypes.String, hotfixID),
+			"installed": tftypes.NewValue(tftypes.Bool, nil),
+			"hotfixes":  tftypes.NewValue(objType.AttributeTypes["hotfixes"], nil),
+		}),
+	}
+}
+
+func readHotfixDS(t *testing.T, client winclient.WindowsHotfixClient, hotfixID interface{}) (*datasource.ReadResponse, windowsHotfixDataSourceModel, []windowsHotfixModel) {
+	t.Helper()
+	d := &windowsHotfixDataSource{hf: client}
+	cfg := hotfixDSConfig(hotfixID)
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsHotfixDataSourceModel
+	var hotfixes []windowsHotfixModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+		state.Hotfixes.ElementsAs(context.Background(), &hotfixes, false)
+	}
+	return resp, state, hotfixes
+}
+
+// ---------------------------------------------------------------------------
+// Metadata / Schema / Configure
+// ---------------------------------------------------------------------------
+
+func TestHotfixDSMetadata(t *testing.T) {
+	d := &windowsHotfixDataSource{}
+	resp := &datasource.MetadataResponse{}
+	d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_hotfix" {
+		t.Errorf("TypeName = %q, want windows_hotfix", resp.TypeName)
+	}
+}
+
+func TestHotfixDSSchema_Attributes(t *testing.T) {
+	d := &windowsHotfixDataSource{}
+	resp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
+	for _, k := range []string{"id", "hotfix_id", "installed", "hotfixes"} {
+		if _, ok := resp.Schema.Attributes[k]; !ok {
+			t.Errorf("schema missing attribute %q", k)
+		}
+	}
+	if !resp.Schema.Attributes["hotfix_id"].IsOptional() {
+		t.Error("hotfix_id must be optional")
+	}
+}
+
+func TestHotfixDSConfigure(t *testing.T) {
+	d := &windowsHotfixDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: 42}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "winclient.Client") {
+		t.Errorf("wrong type must produce error, got %v", resp.Diagnostics)
+	}
+
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || d.hf == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Read
+// ---------------------------------------------------------------------------
+
+func TestHotfixDSRead_Installed(t *testing.T) {
+	fake := &fakeHotfixClient{out: []winclient.Hotfix{
+		{HotfixID: "KB5034123", Description: "Security Update", InstalledOn: "2026-01-09", InstalledBy: `NT AUTHORITY\SYSTEM`},
+	}}
+	resp, state, hotfixes := readHotfixDS(t, fake, "KB5034123")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	if fake.lastID != "KB5034123" {
+		t.Errorf("List called with %q", fake.lastID)
+	}
+	if state.ID.ValueString() != "KB5034123" || !state.Installed.ValueBool() {
+		t.Errorf("state = %+v", state)
+	}
+	if len(hotfixes) != 1 || hotfixes[0].InstalledOn.ValueString() != "2026-01-09" ||
+		hotfixes[0].Description.ValueString() != "Security Update" {
+		t.Errorf("hotfixes = %+v", hotfixes)
+	}
+}
+
+func TestHotfixDSRead_NotInstalled(t *testing.T) {
+	resp, state, hotfixes := readHotfixDS(t, &fakeHotfixClient{out: []winclient.Hotfix{}}, "KB1")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("a missing hotfix must not be an error: %v", resp.Diagnostics)
+	}
+	if state.Installed.ValueBool() || state.Hotfixes.IsNull() || len(hotfixes) != 0 {
+		t.Errorf("state = %+v, hotfixes = %+v", state, hotfixes)
+	}
+}
+
+func TestHotfixDSRead_ListAll(t *testing.T) {
+	fake := &fakeHotfixClient{out: []winclient.Hotfix{
+		{HotfixID: "KB5034123", Description: "Security Update"},
+		{HotfixID: "KB5034439", Description: "Update"},
+	}}
+	resp, state, hotfixes := readHotfixDS(t, fake, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	if fake.lastID != "" || state.ID.ValueString() != "current" || !state.HotfixID.IsNull() {
+		t.Errorf("lastID = %q, state = %+v", fake.lastID, state)
+	}
+	if len(hotfixes) != 2 || hotfixes[1].HotfixID.ValueString() != "KB5034439" {
+		t.Errorf("hotfixes = %+v", hotfixes)
+	}
+}
+
+func TestHotfixDSRead_Error(t *testing.T) {
+	resp, _, _ := readHotfixDS(t, &fakeHotfixClient{
+		err: winclient.NewHotfixError(winclient.HotfixErrorPermission, "Access is denied", nil,
+			map[string]string{"host": "win01"}),
+	}, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: permission_denied") {
+		t.Errorf("diagnostics = %v", resp.Diagnostics)
+	}
+
+	resp, _, _ = readHotfixDS(t, &fakeHotfixClient{err: errors.New("boom")}, nil)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Detail() != "boom" {
+		t.Errorf("plain error should pass through, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,183 @@
+// Package provider: windows_installed_features data source implementation.
+//
+// Lists the installed Windows Server roles and features, optionally narrowed
+// by a name wildcard. Typical use is auditing a host for features that were
+// installed outside Terraform.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsInstalledFeaturesDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsInstalledFeaturesDataSource)(nil)
+)
+
+// NewWindowsInstalledFeaturesDataSource is the constructor registered in provider.go.
+func NewWindowsInstalledFeaturesDataSource() datasource.DataSource {
+	return &windowsInstalledFeaturesDataSource{}
+}
+
+// windowsInstalledFeaturesDataSource is the TPF data source type for
+// windows_installed_features.
+type windowsInstalledFeaturesDataSource struct {
+	feat winclient.WindowsFeatureLister
+}
+
+// windowsInstalledFeaturesDataSourceModel is the Terraform state model for
+// the windows_installed_features data source.
+type windowsInstalledFeaturesDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	NameFilter types.String `tfsdk:"name_filter"`
+	Features   types.List   `tfsdk:"features"`
+}
+
+// windowsInstalledFeatureModel is one element of the features list.
+type windowsInstalledFeatureModel struct {
+	Name         types.String `tfsdk:"name"`
+	DisplayName  types.String `tfsdk:"display_name"`
+	InstallState types.String `tfsdk:"install_state"`
+	Depth        types.Int64  `tfsdk:"depth"`
+	Parent       types.String `tfsdk:"parent"`
+}
+
+// installedFeatureAttrTypes is the attr.Type map for a features element.
+var installedFeatureAttrTypes = map[string]attr.Type{
+	"name":          types.StringType,
+	"display_name":  types.StringType,
+	"install_state": types.StringType,
+	"depth":         types.Int64Type,
+	"parent":        types.StringType,
+}
+
+// Metadata sets the data source type name ("windows_installed_features").
+func (d *windowsInstalledFeaturesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_installed_features"
+}
+
+// Schema returns the TPF schema for the windows_installed_features data source.
+func (d *windowsInstalledFeaturesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the installed Windows Server roles and features on the remote host, " +
+			"optionally filtered by name wildcard. Backed by `Get-WindowsFeature` (ServerManager module). " +
+			"No match yields an empty `features` list, not an error.\n\n" +
+			"The Terraform data source ID is the name filter (`*` when unset).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID derived from the name filter.",
+			},
+			"name_filter": schema.StringAttribute{
+				Optional:            true,
+				Description:         "PowerShell wildcard matched case-insensitively against the feature name (e.g. Web-*). Omit to list every installed feature.",
+				MarkdownDescription: "PowerShell wildcard (`*`, `?`, `[a-z]`) matched case-insensitively against the feature name, e.g. `Web-*`. Omit to list every installed feature.",
+			},
+			"features": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Installed features, in Get-WindowsFeature tree order (each parent before its children).",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Technical feature name (e.g. Web-Server).",
+						},
+						"display_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Human-readable feature name.",
+						},
+						"install_state": schema.StringAttribute{
+							Computed:    true,
+							Description: "Install state as reported by Get-WindowsFeature; always Installed.",
+						},
+						"depth": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Level in the role/feature tree; 1 for a top-level role or feature.",
+						},
+						"parent": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the parent feature; empty for a top-level one.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsInstalledFeaturesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.feat = winclient.NewFeatureClient(c)
+}
+
+// Read enumerates the installed features on the remote Windows host.
+func (d *windowsInstalledFeaturesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsInstalledFeaturesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameFilter := config.NameFilter.ValueString()
+	tflog.Debug(ctx, "windows_installed_features data source Read start", map[string]interface{}{
+		"name_filter": nameFilter,
+	})
+
+	features, err := d.feat.ListInstalled(ctx, nameFilter)
+	if err != nil {
+		addFeatureDiag(&resp.Diagnostics, "Read windows_installed_features data source failed", err)
+		return
+	}
+
+	elems := make([]attr.Value, 0, len(features))
+	for _, f := range features {
+		obj, diags := types.ObjectValueFrom(ctx, installedFeatureAttrTypes, windowsInstalledFeatureModel{
+			Name:         types.StringValue(f.Name),
+			DisplayName:  types.StringValue(f.DisplayName),
+			InstallState: types.StringValue(f.InstallState),
+			Depth:        types.Int64Value(int64(f.Depth)),
+			Parent:       types.StringValue(f.Parent),
+		})
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, obj)
+	}
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: installedFeatureAttrTypes}, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := nameFilter
+	if id == "" {
+		id = "*"
+	}
+	config.ID = types.StringValue(id)
+	config.Features = list
+
+	tflog.Debug(ctx, "windows_installed_features data source Read end", map[string]interface{}{
+		"feature_count": len(features),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
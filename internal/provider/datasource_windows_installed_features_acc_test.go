@@ -0,0 +1,45 @@
+//go:build acceptance
+
+// Package provider — acceptance test for the windows_installed_features data source.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD and a
+// Windows Server target (Get-WindowsFeature ships with ServerManager only).
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsInstalledFeaturesDataSource_Basic lists PowerShellRoot, which
+// is installed by default on every supported Windows Server release.
+func TestAccWindowsInstalledFeaturesDataSource_Basic(t *testing.T) {
+	testAccFeatureDSPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "windows_installed_features" "ps" {
+  name_filter = "PowerShellRoot"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_installed_features.ps", "id", "PowerShellRoot"),
+					resource.TestCheckResourceAttr("data.windows_installed_features.ps", "features.#", "1"),
+					resource.TestCheckResourceAttr("data.windows_installed_features.ps", "features.0.install_state", "Installed"),
+					resource.TestCheckResourceAttr("data.windows_installed_features.ps", "features.0.depth", "1"),
+				),
+			},
+			{
+				Config: `data "windows_installed_features" "all" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_installed_features.all", "id", "*"),
+					resource.TestCheckResourceAttrSet("data.windows_installed_features.all", "features.0.name"),
+				),
+			},
+		},
+	})
+}
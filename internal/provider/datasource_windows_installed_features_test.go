@@ -0,0 +1,103 @@
+// Package provider — unit tests for the windows_installed_features data source.
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeFeatureLister struct {
+	out         []winclient.InstalledFeature
+	err         error
+	lastPattern string
+}
+
+func (f *fakeFeatureLister) ListInstalled(_ context.Context, namePattern string) ([]winclient.InstalledFeature, error) {
+	f.lastPattern = namePattern
+	return f.out, f.err
+}
+
+func installedFeaturesDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":          tftypes.String,
+		"name_filter": tftypes.String,
+		"features": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":          tftypes.String,
+			"display_name":  tftypes.String,
+			"install_state": tftypes.String,
+			"depth":         tftypes.Number,
+			"parent":        tftypes.String,
+		}}},
+	}}
+}
+
+func readInstalledFeaturesDS(t *testing.T, client winclient.WindowsFeatureLister, nameFilter any) (*datasource.ReadResponse, windowsInstalledFeaturesDataSourceModel, []windowsInstalledFeatureModel) {
+	t.Helper()
+	d := &windowsInstalledFeaturesDataSource{feat: client}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	objType := installedFeaturesDSObjType()
+	cfg := tfsdk.Config{Schema: sr.Schema, Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, nil),
+		"name_filter": tftypes.NewValue(tftypes.String, nameFilter),
+		"features":    tftypes.NewValue(objType.AttributeTypes["features"], nil),
+	})}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sr.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsInstalledFeaturesDataSourceModel
+	var features []windowsInstalledFeatureModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+		state.Features.ElementsAs(context.Background(), &features, false)
+	}
+	return resp, state, features
+}
+
+func TestInstalledFeaturesDataSource_Metadata(t *testing.T) {
+	resp := &datasource.MetadataResponse{}
+	(&windowsInstalledFeaturesDataSource{}).Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_installed_features" {
+		t.Errorf("TypeName = %q", resp.TypeName)
+	}
+}
+
+func TestInstalledFeaturesDataSource_Read(t *testing.T) {
+	fake := &fakeFeatureLister{out: []winclient.InstalledFeature{
+		{Name: "Web-Server", DisplayName: "Web Server (IIS)", InstallState: "Installed", Depth: 1},
+		{Name: "Web-WebServer", DisplayName: "Web Server", InstallState: "Installed", Depth: 2, Parent: "Web-Server"},
+	}}
+	resp, state, features := readInstalledFeaturesDS(t, fake, "Web-*")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if fake.lastPattern != "Web-*" || state.ID.ValueString() != "Web-*" {
+		t.Errorf("pattern = %q, id = %q", fake.lastPattern, state.ID.ValueString())
+	}
+	if len(features) != 2 || features[1].Depth.ValueInt64() != 2 || features[1].Parent.ValueString() != "Web-Server" || features[0].Parent.ValueString() != "" {
+		t.Errorf("features = %+v", features)
+	}
+}
+
+func TestInstalledFeaturesDataSource_Read_NoFilterNoMatch(t *testing.T) {
+	resp, state, features := readInstalledFeaturesDS(t, &fakeFeatureLister{out: []winclient.InstalledFeature{}}, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "*" || state.Features.IsNull() || len(features) != 0 {
+		t.Errorf("state = %+v, features = %+v", state, features)
+	}
+}
+
+func TestInstalledFeaturesDataSource_Read_Error(t *testing.T) {
+	fake := &fakeFeatureLister{err: winclient.NewFeatureError(winclient.FeatureErrorUnsupportedSKU, "Install-WindowsFeature is not available on this host.", nil, nil)}
+	resp, _, _ := readInstalledFeaturesDS(t, fake, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error diagnostic")
+	}
+}
@@ -2,16 +2,21 @@
 //
 // Reads the observed state of a Windows local group by name OR SID (exactly
 // one of the two must be provided). The write-only attributes of the resource
-// are absent; all returned attributes are Computed.
+// are absent; all returned attributes are Computed. The member list comes from
+// the same read as the group, so it costs no extra WinRM round trip.
 package provider
 
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -39,10 +44,13 @@ type windowsLocalGroupDataSource struct {
 // windowsLocalGroupDataSourceModel is the Terraform state model for the
 // windows_local_group data source.
 type windowsLocalGroupDataSourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	SID         types.String `tfsdk:"sid"`
-	Description types.String `tfsdk:"description"`
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	SID             types.String `tfsdk:"sid"`
+	Description     types.String `tfsdk:"description"`
+	PrincipalSource types.String `tfsdk:"principal_source"`
+	ObjectClass     types.String `tfsdk:"object_class"`
+	Members         types.List   `tfsdk:"members"`
 }
 
 // Metadata sets the data source type name ("windows_local_group").
@@ -77,6 +85,39 @@ func (d *windowsLocalGroupDataSource) Schema(_ context.Context, _ datasource.Sch
 				Computed:    true,
 				Description: "Free-text description of the group as returned by Windows.",
 			},
+			"principal_source": schema.StringAttribute{
+				Computed:    true,
+				Description: "Origin of the group as reported by Get-LocalGroup: Local, or Unknown when Windows does not say.",
+			},
+			"object_class": schema.StringAttribute{
+				Computed:    true,
+				Description: "Object class reported by Get-LocalGroup (\"Group\").",
+			},
+			"members": schema.ListNestedAttribute{
+				Computed: true,
+				Description: "Members of the group, sorted by name. Orphaned domain SIDs are listed with " +
+					"principal_source and object_class set to Unknown.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Member name as reported by Windows (e.g. WIN01\\bob or CONTOSO\\alice). The SID for an orphaned domain account.",
+						},
+						"object_class": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account type: User, Group, or Unknown when the fallback listing could not tell.",
+						},
+						"sid": schema.StringAttribute{
+							Computed:    true,
+							Description: "Security Identifier of the member.",
+						},
+						"principal_source": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account origin: Local, ActiveDirectory, AzureAD, MicrosoftAccount, or Unknown.",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -156,17 +197,51 @@ func (d *windowsLocalGroupDataSource) Read(ctx context.Context, req datasource.R
 		return
 	}
 
+	members, diags := localGroupDataSourceMembers(ctx, gs.Members)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	state := windowsLocalGroupDataSourceModel{
-		ID:          types.StringValue(gs.SID),
-		Name:        types.StringValue(gs.Name),
-		SID:         types.StringValue(gs.SID),
-		Description: types.StringValue(gs.Description),
+		ID:              types.StringValue(gs.SID),
+		Name:            types.StringValue(gs.Name),
+		SID:             types.StringValue(gs.SID),
+		Description:     types.StringValue(gs.Description),
+		PrincipalSource: types.StringValue(gs.PrincipalSource),
+		ObjectClass:     types.StringValue(gs.ObjectClass),
+		Members:         members,
 	}
 
 	tflog.Debug(ctx, "windows_local_group data source Read end", map[string]interface{}{
-		"name": state.Name.ValueString(),
-		"sid":  state.SID.ValueString(),
+		"name":         state.Name.ValueString(),
+		"sid":          state.SID.ValueString(),
+		"member_count": len(gs.Members),
 	})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
+
+// localGroupDataSourceMembers converts GroupState.Members into the members
+// list, sorted by name like windows_local_group_members.
+func localGroupDataSourceMembers(ctx context.Context, in []winclient.GroupMember) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	sorted := append([]winclient.GroupMember(nil), in...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	elems := make([]attr.Value, 0, len(sorted))
+	for _, m := range sorted {
+		obj, d := types.ObjectValueFrom(ctx, localGroupMembersEntryAttrTypes, windowsLocalGroupMembersEntryModel{
+			Name:            types.StringValue(m.Name),
+			ObjectClass:     types.StringValue(m.ObjectClass),
+			SID:             types.StringValue(m.SID),
+			PrincipalSource: types.StringValue(m.PrincipalSource),
+		})
+		diags.Append(d...)
+		elems = append(elems, obj)
+	}
+	list, d := types.ListValue(types.ObjectType{AttrTypes: localGroupMembersEntryAttrTypes}, elems)
+	diags.Append(d...)
+	return list, diags
+}
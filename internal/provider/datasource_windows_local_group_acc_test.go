@@ -42,6 +42,9 @@ data "windows_local_group" "admins" {
 					resource.TestCheckResourceAttrSet("data.windows_local_group.admins", "id"),
 					resource.TestCheckResourceAttr("data.windows_local_group.admins", "name", "Administrators"),
 					resource.TestCheckResourceAttrSet("data.windows_local_group.admins", "sid"),
+					resource.TestCheckResourceAttr("data.windows_local_group.admins", "principal_source", "Local"),
+					resource.TestCheckResourceAttr("data.windows_local_group.admins", "object_class", "Group"),
+					resource.TestCheckResourceAttrSet("data.windows_local_group.admins", "members.#"),
 				),
 			},
 		},
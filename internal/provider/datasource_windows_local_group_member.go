@@ -8,7 +8,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -71,7 +70,7 @@ func (d *windowsLocalGroupMemberDataSource) Schema(_ context.Context, _ datasour
 			},
 			"member_name": schema.StringAttribute{
 				Required:    true,
-				Description: "Display name of the member to look up within the group.",
+				Description: "Member to look up within the group: DOMAIN\\user, a bare local name, user@domain or a SID. It is resolved to a SID and matched on SID, so a bare name never matches a domain account with the same short name.",
 			},
 			"group_sid": schema.StringAttribute{
 				Computed:    true,
@@ -110,8 +109,8 @@ func (d *windowsLocalGroupMemberDataSource) Configure(_ context.Context, req dat
 //
 // Resolution flow:
 //  1. ResolveGroup(ctx, c, groupName) → GroupState with SID.
-//  2. member.List(ctx, groupSID) → all current members.
-//  3. Match by MemberName (case-insensitive). Not found → AddError.
+//  2. member.Find(ctx, groupSID, memberName) → match on the member's SID,
+//     so "alice" (local) never matches "CONTOSO\alice". Not found → AddError.
 func (d *windowsLocalGroupMemberDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var config windowsLocalGroupMemberDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
@@ -141,8 +140,9 @@ func (d *windowsLocalGroupMemberDataSource) Read(ctx context.Context, req dataso
 		return
 	}
 
-	// Step 2: list all members of the group.
-	members, err := d.member.List(ctx, gs.SID)
+	// Step 2: find the member, matching on its resolved SID (full name only
+	// when the name cannot be resolved).
+	found, err := d.member.Find(ctx, gs.SID, memberName)
 	if err != nil {
 		if winclient.IsLocalGroupMemberError(err, winclient.LocalGroupMemberErrorGroupNotFound) {
 			resp.Diagnostics.AddError(
@@ -152,19 +152,9 @@ func (d *windowsLocalGroupMemberDataSource) Read(ctx context.Context, req dataso
 			return
 		}
 		addLocalGroupMemberDiag(&resp.Diagnostics, "windows_local_group_member data source: list members failed", err)
-
 		return
 	}
 
-	// Step 3: find the member by name (case-insensitive).
-	var found *winclient.LocalGroupMemberState
-	for _, m := range members {
-		if strings.EqualFold(m.MemberName, memberName) {
-			found = m
-			break
-		}
-	}
-
 	if found == nil {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf("Data source not found: windows_local_group_member — member %q not found in group %q", memberName, groupName),
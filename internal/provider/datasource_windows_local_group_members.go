@@ -0,0 +1,210 @@
+// Package provider: windows_local_group_members data source implementation.
+//
+// Lists every member of one local group. It complements
+// windows_local_group_member, which looks up a single (group, member) pair.
+// The group is resolved to its SID first, then the members are read with the
+// same three-tier List used by the membership resource, so orphaned domain
+// SIDs are listed rather than failing the read.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsLocalGroupMembersDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsLocalGroupMembersDataSource)(nil)
+)
+
+// NewWindowsLocalGroupMembersDataSource is the constructor registered in provider.go.
+func NewWindowsLocalGroupMembersDataSource() datasource.DataSource {
+	return &windowsLocalGroupMembersDataSource{}
+}
+
+// windowsLocalGroupMembersDataSource is the TPF data source type for
+// windows_local_group_members.
+type windowsLocalGroupMembersDataSource struct {
+	// resolveGroup resolves a group name or SID; it wraps
+	// winclient.ResolveGroup and is replaced in unit tests.
+	resolveGroup func(ctx context.Context, groupOrSID string) (*winclient.GroupState, error)
+	member       winclient.ClientLocalGroupMember
+}
+
+// windowsLocalGroupMembersDataSourceModel is the Terraform state model for
+// the windows_local_group_members data source.
+type windowsLocalGroupMembersDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	GroupName types.String `tfsdk:"group_name"`
+	GroupSID  types.String `tfsdk:"group_sid"`
+	Members   types.List   `tfsdk:"members"`
+}
+
+// windowsLocalGroupMembersEntryModel is one element of the members list.
+type windowsLocalGroupMembersEntryModel struct {
+	Name            types.String `tfsdk:"name"`
+	ObjectClass     types.String `tfsdk:"object_class"`
+	SID             types.String `tfsdk:"sid"`
+	PrincipalSource types.String `tfsdk:"principal_source"`
+}
+
+// localGroupMembersEntryAttrTypes is the attr.Type map for a members element.
+var localGroupMembersEntryAttrTypes = map[string]attr.Type{
+	"name":             types.StringType,
+	"object_class":     types.StringType,
+	"sid":              types.StringType,
+	"principal_source": types.StringType,
+}
+
+// Metadata sets the data source type name ("windows_local_group_members").
+func (d *windowsLocalGroupMembersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_local_group_members"
+}
+
+// Schema returns the TPF schema for the windows_local_group_members data source.
+func (d *windowsLocalGroupMembersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the members of a local group (`Get-LocalGroupMember`). An empty group yields an " +
+			"empty `members` list; a group that does not exist is an error.\n\n" +
+			"Orphaned domain SIDs, which make `Get-LocalGroupMember` fail, are still listed through a WMI or " +
+			"`net localgroup` fallback; their `principal_source` and `object_class` are then `Unknown`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "SID of the group.",
+			},
+			"group_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name or SID of the local group (e.g. \"Administrators\" or \"S-1-5-32-544\").",
+			},
+			"group_sid": schema.StringAttribute{
+				Computed:    true,
+				Description: "Security Identifier of the group, resolved from group_name.",
+			},
+			"members": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Members of the group, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Member name as reported by Windows (e.g. WIN01\\bob or CONTOSO\\alice). The SID for an orphaned domain account.",
+						},
+						"object_class": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account type: User, Group, or Unknown when the fallback listing could not tell.",
+						},
+						"sid": schema.StringAttribute{
+							Computed:    true,
+							Description: "Security Identifier of the member.",
+						},
+						"principal_source": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account origin: Local, ActiveDirectory, AzureAD, MicrosoftAccount, or Unknown.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsLocalGroupMembersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.resolveGroup = func(ctx context.Context, groupOrSID string) (*winclient.GroupState, error) {
+		return winclient.ResolveGroup(ctx, c, groupOrSID)
+	}
+	d.member = winclient.NewLocalGroupMemberClient(c)
+}
+
+// Read resolves the group and lists its members.
+func (d *windowsLocalGroupMembersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsLocalGroupMembersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	groupName := config.GroupName.ValueString()
+
+	tflog.Debug(ctx, "windows_local_group_members data source Read start", map[string]interface{}{
+		"group_name": groupName,
+	})
+
+	gs, err := d.resolveGroup(ctx, groupName)
+	if err != nil {
+		if winclient.IsLocalGroupError(err, winclient.LocalGroupErrorNotFound) {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Data source not found: windows_local_group_members — group %q not found", groupName),
+				fmt.Sprintf("No local group with name/SID %q was found on the target host.", groupName),
+			)
+			return
+		}
+		addLocalGroupDiag(&resp.Diagnostics, "windows_local_group_members data source: resolve group failed", err)
+		return
+	}
+
+	members, err := d.member.List(ctx, gs.SID)
+	if err != nil {
+		if winclient.IsLocalGroupMemberError(err, winclient.LocalGroupMemberErrorGroupNotFound) {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Data source not found: windows_local_group_members — group %q not found", groupName),
+				fmt.Sprintf("Group %q (SID %s) was not found when listing members.", groupName, gs.SID),
+			)
+			return
+		}
+		addLocalGroupMemberDiag(&resp.Diagnostics, "windows_local_group_members data source: list members failed", err)
+		return
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return strings.ToLower(members[i].MemberName) < strings.ToLower(members[j].MemberName)
+	})
+	elems := make([]attr.Value, 0, len(members))
+	for _, m := range members {
+		obj, diags := types.ObjectValueFrom(ctx, localGroupMembersEntryAttrTypes, windowsLocalGroupMembersEntryModel{
+			Name:            types.StringValue(m.MemberName),
+			ObjectClass:     types.StringValue(m.ObjectClass),
+			SID:             types.StringValue(m.MemberSID),
+			PrincipalSource: types.StringValue(m.PrincipalSource),
+		})
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, obj)
+	}
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: localGroupMembersEntryAttrTypes}, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.ID = types.StringValue(gs.SID)
+	config.GroupSID = types.StringValue(gs.SID)
+	config.Members = list
+
+	tflog.Debug(ctx, "windows_local_group_members data source Read end", map[string]interface{}{
+		"group_sid": gs.SID, "member_count": len(members),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
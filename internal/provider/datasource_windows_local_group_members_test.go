@@ -0,0 +1,110 @@
+// Package provider — unit tests for the windows_local_group_members data source.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+func lgMembersDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":         tftypes.String,
+		"group_name": tftypes.String,
+		"group_sid":  tftypes.String,
+		"members": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":             tftypes.String,
+			"object_class":     tftypes.String,
+			"sid":              tftypes.String,
+			"principal_source": tftypes.String,
+		}}},
+	}}
+}
+
+func readLocalGroupMembersDS(t *testing.T, resolveErr error, member winclient.ClientLocalGroupMember) (*datasource.ReadResponse, windowsLocalGroupMembersDataSourceModel, []windowsLocalGroupMembersEntryModel) {
+	t.Helper()
+	d := &windowsLocalGroupMembersDataSource{
+		resolveGroup: func(_ context.Context, name string) (*winclient.GroupState, error) {
+			if resolveErr != nil {
+				return nil, resolveErr
+			}
+			return &winclient.GroupState{Name: name, SID: "S-1-5-32-544"}, nil
+		},
+		member: member,
+	}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	objType := lgMembersDSObjType()
+	cfg := tfsdk.Config{Schema: sr.Schema, Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, nil),
+		"group_name": tftypes.NewValue(tftypes.String, "Administrators"),
+		"group_sid":  tftypes.NewValue(tftypes.String, nil),
+		"members":    tftypes.NewValue(objType.AttributeTypes["members"], nil),
+	})}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sr.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsLocalGroupMembersDataSourceModel
+	var members []windowsLocalGroupMembersEntryModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+		state.Members.ElementsAs(context.Background(), &members, false)
+	}
+	return resp, state, members
+}
+
+func TestLocalGroupMembersDS_Metadata(t *testing.T) {
+	resp := &datasource.MetadataResponse{}
+	(&windowsLocalGroupMembersDataSource{}).Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_local_group_members" {
+		t.Errorf("TypeName = %q", resp.TypeName)
+	}
+}
+
+func TestLocalGroupMembersDS_Read(t *testing.T) {
+	fake := &fakeLocalGroupMemberClient{listOut: []*winclient.LocalGroupMemberState{
+		{GroupSID: "S-1-5-32-544", MemberSID: "S-1-5-21-1-2-3-500", MemberName: `WIN01\Administrator`, PrincipalSource: "Local", ObjectClass: "User"},
+		{GroupSID: "S-1-5-32-544", MemberSID: "S-1-5-21-9-8-7-512", MemberName: `CONTOSO\Domain Admins`, PrincipalSource: "ActiveDirectory", ObjectClass: "Group"},
+	}}
+	resp, state, members := readLocalGroupMembersDS(t, nil, fake)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "S-1-5-32-544" || state.GroupSID.ValueString() != "S-1-5-32-544" {
+		t.Errorf("id = %q, group_sid = %q", state.ID.ValueString(), state.GroupSID.ValueString())
+	}
+	if len(members) != 2 || members[0].Name.ValueString() != `CONTOSO\Domain Admins` {
+		t.Fatalf("members = %+v, want 2 sorted by name", members)
+	}
+	if members[0].ObjectClass.ValueString() != "Group" || members[0].SID.ValueString() != "S-1-5-21-9-8-7-512" ||
+		members[1].PrincipalSource.ValueString() != "Local" {
+		t.Errorf("members = %+v", members)
+	}
+}
+
+func TestLocalGroupMembersDS_Read_EmptyGroup(t *testing.T) {
+	resp, _, members := readLocalGroupMembersDS(t, nil, &fakeLocalGroupMemberClient{})
+	if resp.Diagnostics.HasError() || len(members) != 0 {
+		t.Errorf("an empty group must yield an empty list: %v, %+v", resp.Diagnostics, members)
+	}
+}
+
+func TestLocalGroupMembersDS_Read_GroupNotFound(t *testing.T) {
+	resp, _, _ := readLocalGroupMembersDS(t,
+		winclient.NewLocalGroupError(winclient.LocalGroupErrorNotFound, "group not found", nil, nil),
+		&fakeLocalGroupMemberClient{})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Summary(), "not found") {
+		t.Errorf("diags = %v", resp.Diagnostics)
+	}
+
+	resp, _, _ = readLocalGroupMembersDS(t, nil, &fakeLocalGroupMemberClient{listErr: winclient.NewLocalGroupMemberError(
+		winclient.LocalGroupMemberErrorGroupNotFound, "gone", nil, nil)})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Summary(), "not found") {
+		t.Errorf("diags = %v", resp.Diagnostics)
+	}
+}
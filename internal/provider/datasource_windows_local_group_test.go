@@ -1,7 +1,8 @@
 // Package provider — unit tests for the windows_local_group data source.
 //
 // Tests cover: Metadata, Schema (ExactlyOneOf, Optional+Computed name/sid),
-// Configure, Read by name, Read by SID, not-found, nil result, generic error.
+// Configure, Read by name, Read by SID, members, not-found, nil result,
+// generic error.
 package provider
 
 import (
@@ -53,10 +54,22 @@ func (f *fakeLocalGroupClientDS) ImportBySID(_ context.Context, _ string) (*winc
 
 func localGroupDSObjType() tftypes.Object {
 	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
-		"id":          tftypes.String,
-		"name":        tftypes.String,
-		"sid":         tftypes.String,
-		"description": tftypes.String,
+		"id":               tftypes.String,
+		"name":             tftypes.String,
+		"sid":              tftypes.String,
+		"description":      tftypes.String,
+		"principal_source": tftypes.String,
+		"object_class":     tftypes.String,
+		"members":          tftypes.List{ElementType: localGroupDSMemberObjType()},
+	}}
+}
+
+func localGroupDSMemberObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":             tftypes.String,
+		"object_class":     tftypes.String,
+		"sid":              tftypes.String,
+		"principal_source": tftypes.String,
 	}}
 }
 
@@ -67,10 +80,13 @@ func localGroupDSConfigByName(name string) tfsdk.Config {
 	return tfsdk.Config{
 		Schema: sr.Schema,
 		Raw: tftypes.NewValue(localGroupDSObjType(), map[string]tftypes.Value{
-			"id":          tftypes.NewValue(tftypes.String, nil),
-			"name":        tftypes.NewValue(tftypes.String, name),
-			"sid":         tftypes.NewValue(tftypes.String, nil),
-			"description": tftypes.NewValue(tftypes.String, nil),
+			"id":               tftypes.NewValue(tftypes.String, nil),
+			"name":             tftypes.NewValue(tftypes.String, name),
+			"sid":              tftypes.NewValue(tftypes.String, nil),
+			"description":      tftypes.NewValue(tftypes.String, nil),
+			"principal_source": tftypes.NewValue(tftypes.String, nil),
+			"object_class":     tftypes.NewValue(tftypes.String, nil),
+			"members":          tftypes.NewValue(tftypes.List{ElementType: localGroupDSMemberObjType()}, nil),
 		}),
 	}
 }
@@ -82,10 +98,13 @@ func localGroupDSConfigBySID(sid string) tfsdk.Config {
 	return tfsdk.Config{
 		Schema: sr.Schema,
 		Raw: tftypes.NewValue(localGroupDSObjType(), map[string]tftypes.Value{
-			"id":          tftypes.NewValue(tftypes.String, nil),
-			"name":        tftypes.NewValue(tftypes.String, nil),
-			"sid":         tftypes.NewValue(tftypes.String, sid),
-			"description": tftypes.NewValue(tftypes.String, nil),
+			"id":               tftypes.NewValue(tftypes.String, nil),
+			"name":             tftypes.NewValue(tftypes.String, nil),
+			"sid":              tftypes.NewValue(tftypes.String, sid),
+			"description":      tftypes.NewValue(tftypes.String, nil),
+			"principal_source": tftypes.NewValue(tftypes.String, nil),
+			"object_class":     tftypes.NewValue(tftypes.String, nil),
+			"members":          tftypes.NewValue(tftypes.List{ElementType: localGroupDSMemberObjType()}, nil),
 		}),
 	}
 }
@@ -117,7 +136,7 @@ func TestLocalGroupDSSchema_AllAttributes(t *testing.T) {
 	d := &windowsLocalGroupDataSource{}
 	resp := &datasource.SchemaResponse{}
 	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
-	for _, k := range []string{"id", "name", "sid", "description"} {
+	for _, k := range []string{"id", "name", "sid", "description", "principal_source", "object_class", "members"} {
 		if _, ok := resp.Schema.Attributes[k]; !ok {
 			t.Errorf("schema missing attribute %q", k)
 		}
@@ -145,7 +164,7 @@ func TestLocalGroupDSSchema_DescriptionComputed(t *testing.T) {
 	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
 
 	type computedChecker interface{ IsComputed() bool }
-	for _, k := range []string{"id", "description"} {
+	for _, k := range []string{"id", "description", "principal_source", "object_class", "members"} {
 		attr := resp.Schema.Attributes[k]
 		cc, ok := attr.(computedChecker)
 		if !ok || !cc.IsComputed() {
@@ -221,6 +240,73 @@ func TestLocalGroupDSRead_ByName_HappyPath(t *testing.T) {
 	}
 }
 
+func TestLocalGroupDSRead_MembersAndPrincipalSource(t *testing.T) {
+	d := &windowsLocalGroupDataSource{
+		grp: &fakeLocalGroupClientDS{
+			importByNameOut: &winclient.GroupState{
+				Name:            "Administrators",
+				SID:             "S-1-5-32-544",
+				PrincipalSource: "Local",
+				ObjectClass:     "Group",
+				Members: []winclient.GroupMember{
+					{SID: "S-1-5-21-1-2-3-500", Name: `WIN01\Administrator`, PrincipalSource: "Local", ObjectClass: "User"},
+					{SID: "S-1-5-21-9-9-9-512", Name: `CONTOSO\Domain Admins`, PrincipalSource: "ActiveDirectory", ObjectClass: "Group"},
+					{SID: "S-1-5-21-7-7-7-1105", Name: "S-1-5-21-7-7-7-1105", PrincipalSource: "Unknown", ObjectClass: "Unknown"},
+				},
+			},
+		},
+	}
+	cfg := localGroupDSConfigByName("Administrators")
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state windowsLocalGroupDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.PrincipalSource.ValueString() != "Local" || state.ObjectClass.ValueString() != "Group" {
+		t.Errorf("principal_source, object_class = %q, %q", state.PrincipalSource.ValueString(), state.ObjectClass.ValueString())
+	}
+	var members []windowsLocalGroupMembersEntryModel
+	resp.Diagnostics.Append(state.Members.ElementsAs(context.Background(), &members, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("decode members: %v", resp.Diagnostics)
+	}
+	wantNames := []string{`CONTOSO\Domain Admins`, "S-1-5-21-7-7-7-1105", `WIN01\Administrator`}
+	if len(members) != len(wantNames) {
+		t.Fatalf("members = %+v", members)
+	}
+	for i, n := range wantNames {
+		if members[i].Name.ValueString() != n {
+			t.Errorf("members[%d].name = %q, want %q (sorted by name)", i, members[i].Name.ValueString(), n)
+		}
+	}
+	if m := members[0]; m.ObjectClass.ValueString() != "Group" || m.PrincipalSource.ValueString() != "ActiveDirectory" ||
+		m.SID.ValueString() != "S-1-5-21-9-9-9-512" {
+		t.Errorf("members[0] = %+v", m)
+	}
+}
+
+func TestLocalGroupDSRead_EmptyGroupHasEmptyMembers(t *testing.T) {
+	d := &windowsLocalGroupDataSource{
+		grp: &fakeLocalGroupClientDS{
+			importByNameOut: &winclient.GroupState{Name: "Empty", SID: "S-1-5-21-1-2-3-1010", Members: []winclient.GroupMember{}},
+		},
+	}
+	cfg := localGroupDSConfigByName("Empty")
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	var state windowsLocalGroupDataSourceModel
+	resp.State.Get(context.Background(), &state)
+	if state.Members.IsNull() || len(state.Members.Elements()) != 0 {
+		t.Errorf("members = %v, want empty list", state.Members)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Read — by SID
 // ---------------------------------------------------------------------------
@@ -43,6 +43,9 @@ func (f *fakeLocalUserClientDS) Rename(_ context.Context, _, _ string) error {
 func (f *fakeLocalUserClientDS) SetPassword(_ context.Context, _, _ string) error {
 	panic("SetPassword not used in data source")
 }
+func (f *fakeLocalUserClientDS) SetPasswordExpired(_ context.Context, _ string, _ bool) error {
+	panic("SetPasswordExpired not used in data source")
+}
 func (f *fakeLocalUserClientDS) Enable(_ context.Context, _ string) error {
 	panic("Enable not used in data source")
 }
@@ -58,6 +61,15 @@ func (f *fakeLocalUserClientDS) ImportByName(_ context.Context, _ string) (*winc
 func (f *fakeLocalUserClientDS) ImportBySID(_ context.Context, _ string) (*winclient.UserState, error) {
 	return f.importBySIDOut, f.importBySIDErr
 }
+func (f *fakeLocalUserClientDS) Groups(_ context.Context, _ string) ([]string, error) {
+	panic("Groups not used in data source")
+}
+func (f *fakeLocalUserClientDS) AddToGroups(_ context.Context, _ string, _ []string) error {
+	panic("AddToGroups not used in data source")
+}
+func (f *fakeLocalUserClientDS) RemoveFromGroups(_ context.Context, _ string, _ []string) error {
+	panic("RemoveFromGroups not used in data source")
+}
 
 // ---------------------------------------------------------------------------
 // tftypes helpers
@@ -0,0 +1,231 @@
+// Package provider: windows_local_users data source implementation.
+//
+// Lists every local user account, or only the enabled ones, with the same
+// attributes as the windows_local_user data source. Typical use is auditing
+// the accounts present on a host.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsLocalUsersDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsLocalUsersDataSource)(nil)
+)
+
+// NewWindowsLocalUsersDataSource is the constructor registered in provider.go.
+func NewWindowsLocalUsersDataSource() datasource.DataSource {
+	return &windowsLocalUsersDataSource{}
+}
+
+// windowsLocalUsersDataSource is the TPF data source type for windows_local_users.
+type windowsLocalUsersDataSource struct {
+	users winclient.LocalUserLister
+}
+
+// windowsLocalUsersDataSourceModel is the Terraform state model for the
+// windows_local_users data source.
+type windowsLocalUsersDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	EnabledOnly types.Bool   `tfsdk:"enabled_only"`
+	Users       types.List   `tfsdk:"users"`
+}
+
+// windowsLocalUserSummaryModel is one element of the users list. Its fields
+// match windowsLocalUserDataSourceModel minus the ID.
+type windowsLocalUserSummaryModel struct {
+	SID                      types.String `tfsdk:"sid"`
+	Name                     types.String `tfsdk:"name"`
+	FullName                 types.String `tfsdk:"full_name"`
+	Description              types.String `tfsdk:"description"`
+	Enabled                  types.Bool   `tfsdk:"enabled"`
+	PasswordNeverExpires     types.Bool   `tfsdk:"password_never_expires"`
+	UserMayNotChangePassword types.Bool   `tfsdk:"user_may_not_change_password"`
+	AccountNeverExpires      types.Bool   `tfsdk:"account_never_expires"`
+	AccountExpires           types.String `tfsdk:"account_expires"`
+	LastLogon                types.String `tfsdk:"last_logon"`
+	PasswordLastSet          types.String `tfsdk:"password_last_set"`
+	PrincipalSource          types.String `tfsdk:"principal_source"`
+}
+
+// localUserSummaryAttrTypes is the attr.Type map for a users element.
+var localUserSummaryAttrTypes = map[string]attr.Type{
+	"sid":                          types.StringType,
+	"name":                         types.StringType,
+	"full_name":                    types.StringType,
+	"description":                  types.StringType,
+	"enabled":                      types.BoolType,
+	"password_never_expires":       types.BoolType,
+	"user_may_not_change_password": types.BoolType,
+	"account_never_expires":        types.BoolType,
+	"account_expires":              types.StringType,
+	"last_logon":                   types.StringType,
+	"password_last_set":            types.StringType,
+	"principal_source":             types.StringType,
+}
+
+// Metadata sets the data source type name ("windows_local_users").
+func (d *windowsLocalUsersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_local_users"
+}
+
+// Schema returns the TPF schema for the windows_local_users data source.
+func (d *windowsLocalUsersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the local user accounts of the remote Windows host with one `Get-LocalUser` call, " +
+			"with the same attributes as the `windows_local_user` data source. Timestamps use the same RFC 3339 " +
+			"format as single-user reads.\n\n" +
+			"The Terraform data source ID is `all` or `enabled`, after `enabled_only`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID: all or enabled.",
+			},
+			"enabled_only": schema.BoolAttribute{
+				Optional:            true,
+				Description:         "Only list enabled accounts. Default: false.",
+				MarkdownDescription: "Only list enabled accounts. Default: `false`.",
+			},
+			"users": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Local user accounts, sorted case-insensitively by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"sid": schema.StringAttribute{
+							Computed:    true,
+							Description: "Security Identifier of the user.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "SAM account name of the user.",
+						},
+						"full_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Display name of the user account.",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Free-text description of the user account.",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:    true,
+							Description: "True when the account is active and not disabled.",
+						},
+						"password_never_expires": schema.BoolAttribute{
+							Computed:    true,
+							Description: "True when the account password has no expiry policy.",
+						},
+						"user_may_not_change_password": schema.BoolAttribute{
+							Computed:    true,
+							Description: "True when self-service password change is blocked for this account.",
+						},
+						"account_never_expires": schema.BoolAttribute{
+							Computed:    true,
+							Description: "True when the account has no expiry date set.",
+						},
+						"account_expires": schema.StringAttribute{
+							Computed:    true,
+							Description: "RFC3339 timestamp of the account expiry date, or empty string when the account never expires.",
+						},
+						"last_logon": schema.StringAttribute{
+							Computed:    true,
+							Description: "RFC3339 timestamp of the last logon, or empty string if the account has never been used.",
+						},
+						"password_last_set": schema.StringAttribute{
+							Computed:    true,
+							Description: "RFC3339 timestamp of the last password change, or empty string if not yet set.",
+						},
+						"principal_source": schema.StringAttribute{
+							Computed:    true,
+							Description: "Origin of the account: Local, ActiveDirectory, AzureAD, MicrosoftAccount, or Unknown.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsLocalUsersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.users = winclient.NewLocalUserClient(c)
+}
+
+// Read enumerates the local users on the remote Windows host.
+func (d *windowsLocalUsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsLocalUsersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	enabledOnly := config.EnabledOnly.ValueBool()
+	tflog.Debug(ctx, "windows_local_users data source Read start", map[string]interface{}{
+		"enabled_only": enabledOnly,
+	})
+
+	users, err := d.users.List(ctx, enabledOnly)
+	if err != nil {
+		addLocalUserDiag(&resp.Diagnostics, "Read windows_local_users data source failed", err)
+		return
+	}
+
+	elems := make([]attr.Value, 0, len(users))
+	for _, us := range users {
+		obj, diags := types.ObjectValueFrom(ctx, localUserSummaryAttrTypes, windowsLocalUserSummaryModel{
+			SID:                      types.StringValue(us.SID),
+			Name:                     types.StringValue(us.Name),
+			FullName:                 types.StringValue(us.FullName),
+			Description:              types.StringValue(us.Description),
+			Enabled:                  types.BoolValue(us.Enabled),
+			PasswordNeverExpires:     types.BoolValue(us.PasswordNeverExpires),
+			UserMayNotChangePassword: types.BoolValue(us.UserMayNotChangePassword),
+			AccountNeverExpires:      types.BoolValue(us.AccountNeverExpires),
+			AccountExpires:           types.StringValue(us.AccountExpires),
+			LastLogon:                types.StringValue(us.LastLogon),
+			PasswordLastSet:          types.StringValue(us.PasswordLastSet),
+			PrincipalSource:          types.StringValue(us.PrincipalSource),
+		})
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, obj)
+	}
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: localUserSummaryAttrTypes}, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.ID = types.StringValue("all")
+	if enabledOnly {
+		config.ID = types.StringValue("enabled")
+	}
+	config.Users = list
+
+	tflog.Debug(ctx, "windows_local_users data source Read end", map[string]interface{}{
+		"user_count": len(users),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,112 @@
+// Package provider — unit tests for the windows_local_users data source.
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeLocalUserLister struct {
+	out             []winclient.UserState
+	err             error
+	lastEnabledOnly bool
+}
+
+func (f *fakeLocalUserLister) List(_ context.Context, enabledOnly bool) ([]winclient.UserState, error) {
+	f.lastEnabledOnly = enabledOnly
+	return f.out, f.err
+}
+
+func localUsersDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":           tftypes.String,
+		"enabled_only": tftypes.Bool,
+		"users": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"sid":                          tftypes.String,
+			"name":                         tftypes.String,
+			"full_name":                    tftypes.String,
+			"description":                  tftypes.String,
+			"enabled":                      tftypes.Bool,
+			"password_never_expires":       tftypes.Bool,
+			"user_may_not_change_password": tftypes.Bool,
+			"account_never_expires":        tftypes.Bool,
+			"account_expires":              tftypes.String,
+			"last_logon":                   tftypes.String,
+			"password_last_set":            tftypes.String,
+			"principal_source":             tftypes.String,
+		}}},
+	}}
+}
+
+func readLocalUsersDS(t *testing.T, client winclient.LocalUserLister, enabledOnly any) (*datasource.ReadResponse, windowsLocalUsersDataSourceModel, []windowsLocalUserSummaryModel) {
+	t.Helper()
+	d := &windowsLocalUsersDataSource{users: client}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	objType := localUsersDSObjType()
+	cfg := tfsdk.Config{Schema: sr.Schema, Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, nil),
+		"enabled_only": tftypes.NewValue(tftypes.Bool, enabledOnly),
+		"users":        tftypes.NewValue(objType.AttributeTypes["users"], nil),
+	})}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sr.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsLocalUsersDataSourceModel
+	var users []windowsLocalUserSummaryModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+		state.Users.ElementsAs(context.Background(), &users, false)
+	}
+	return resp, state, users
+}
+
+func TestLocalUsersDataSource_Metadata(t *testing.T) {
+	resp := &datasource.MetadataResponse{}
+	(&windowsLocalUsersDataSource{}).Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_local_users" {
+		t.Errorf("TypeName = %q", resp.TypeName)
+	}
+}
+
+func TestLocalUsersDataSource_Read(t *testing.T) {
+	fake := &fakeLocalUserLister{out: []winclient.UserState{
+		{Name: "Administrator", SID: "S-1-5-21-1-500", Enabled: true, AccountNeverExpires: true, PasswordLastSet: "2026-01-01T00:00:00Z", PrincipalSource: "Local"},
+		{Name: "svc_app", SID: "S-1-5-21-1-1001", Enabled: true, AccountExpires: "2027-01-31T00:00:00Z", UserMayNotChangePassword: true, PrincipalSource: "Local"},
+	}}
+	resp, state, users := readLocalUsersDS(t, fake, true)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if !fake.lastEnabledOnly || state.ID.ValueString() != "enabled" {
+		t.Errorf("enabled_only not passed: id = %q", state.ID.ValueString())
+	}
+	if len(users) != 2 || users[0].SID.ValueString() != "S-1-5-21-1-500" || !users[0].AccountNeverExpires.ValueBool() ||
+		users[1].AccountExpires.ValueString() != "2027-01-31T00:00:00Z" || !users[1].UserMayNotChangePassword.ValueBool() {
+		t.Errorf("users = %+v", users)
+	}
+}
+
+func TestLocalUsersDataSource_Read_NoneAndDefaultFilter(t *testing.T) {
+	fake := &fakeLocalUserLister{out: []winclient.UserState{}}
+	resp, state, users := readLocalUsersDS(t, fake, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if fake.lastEnabledOnly || state.ID.ValueString() != "all" || state.Users.IsNull() || len(users) != 0 {
+		t.Errorf("state = %+v, users = %+v", state, users)
+	}
+}
+
+func TestLocalUsersDataSource_Read_Error(t *testing.T) {
+	fake := &fakeLocalUserLister{err: winclient.NewLocalUserError(winclient.LocalUserErrorPermission, "Access is denied.", nil, nil)}
+	resp, _, _ := readLocalUsersDS(t, fake, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error diagnostic")
+	}
+}
@@ -0,0 +1,194 @@
+// Package provider: windows_logged_on_users data source implementation.
+//
+// Singleton data source — no lookup keys. Lists the interactive (console and
+// RDP) sessions currently open on the remote Windows host. Typical use is a
+// precondition on a reboot or maintenance step: "do not proceed while
+// somebody is logged on".
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsLoggedOnUsersDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsLoggedOnUsersDataSource)(nil)
+)
+
+// NewWindowsLoggedOnUsersDataSource is the constructor registered in provider.go.
+func NewWindowsLoggedOnUsersDataSource() datasource.DataSource {
+	return &windowsLoggedOnUsersDataSource{}
+}
+
+// windowsLoggedOnUsersDataSource is the TPF data source type for
+// windows_logged_on_users.
+type windowsLoggedOnUsersDataSource struct {
+	lou winclient.WindowsLoggedOnUsersClient
+}
+
+// windowsLoggedOnUsersDataSourceModel is the Terraform state model for the
+// windows_logged_on_users data source.
+type windowsLoggedOnUsersDataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Sessions types.List   `tfsdk:"sessions"`
+}
+
+// windowsLoggedOnSessionModel is one element of the sessions list.
+type windowsLoggedOnSessionModel struct {
+	Username  types.String `tfsdk:"username"`
+	SessionID types.Int64  `tfsdk:"session_id"`
+	State     types.String `tfsdk:"state"`
+	LogonTime types.String `tfsdk:"logon_time"`
+}
+
+// loggedOnSessionAttrTypes is the attr.Type map for a sessions element.
+var loggedOnSessionAttrTypes = map[string]attr.Type{
+	"username":   types.StringType,
+	"session_id": types.Int64Type,
+	"state":      types.StringType,
+	"logon_time": types.StringType,
+}
+
+// Metadata sets the data source type name ("windows_logged_on_users").
+func (d *windowsLoggedOnUsersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_logged_on_users"
+}
+
+// Schema returns the TPF schema for the windows_logged_on_users data source.
+func (d *windowsLoggedOnUsersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the interactive sessions (console and RDP) currently open on the remote " +
+			"Windows host, as reported by `query user`. This is a **singleton** data source — no lookup " +
+			"keys are required.\n\n" +
+			"A host with nobody logged on yields an empty `sessions` list, not an error, so the data " +
+			"source can be used in a `precondition` to hold back disruptive changes such as a reboot " +
+			"while users are connected.\n\n" +
+			"The Terraform data source ID is always `\"current\"`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID; always \"current\" (singleton).",
+			},
+			"sessions": schema.ListNestedAttribute{
+				Computed:            true,
+				Description:         "Interactive sessions on the host. Empty when nobody is logged on.",
+				MarkdownDescription: "Interactive sessions on the host. Empty when nobody is logged on.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"username": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account name of the session owner (no domain prefix).",
+						},
+						"session_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Terminal Services session ID.",
+						},
+						"state": schema.StringAttribute{
+							Computed:            true,
+							Description:         "Session state: Active or Disconnected.",
+							MarkdownDescription: "Session state: `Active` or `Disconnected`.",
+						},
+						"logon_time": schema.StringAttribute{
+							Computed:            true,
+							Description:         "Logon time in UTC RFC 3339 format. Empty when the host does not report a parseable time.",
+							MarkdownDescription: "Logon time in UTC RFC 3339 format (e.g. `2026-10-17T08:15:00Z`). Empty when the host does not report a parseable time.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsLoggedOnUsersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.lou = winclient.NewLoggedOnUsersClient(c)
+}
+
+// Read enumerates the interactive sessions on the remote Windows host.
+func (d *windowsLoggedOnUsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsLoggedOnUsersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "windows_logged_on_users data source Read start")
+
+	sessions, err := d.lou.List(ctx)
+	if err != nil {
+		addLoggedOnUsersDiag(&resp.Diagnostics, "Read windows_logged_on_users data source failed", err)
+		return
+	}
+
+	elems := make([]attr.Value, 0, len(sessions))
+	for _, s := range sessions {
+		obj, diags := types.ObjectValueFrom(ctx, loggedOnSessionAttrTypes, windowsLoggedOnSessionModel{
+			Username:  types.StringValue(s.Username),
+			SessionID: types.Int64Value(s.SessionID),
+			State:     types.StringValue(s.State),
+			LogonTime: types.StringValue(s.LogonTime),
+		})
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, obj)
+	}
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: loggedOnSessionAttrTypes}, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := windowsLoggedOnUsersDataSourceModel{
+		ID:       types.StringValue("current"),
+		Sessions: list,
+	}
+
+	tflog.Debug(ctx, "windows_logged_on_users data source Read end", map[string]interface{}{
+		"session_count": len(sessions),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// addLoggedOnUsersDiag converts a winclient error into a Terraform diagnostic.
+func addLoggedOnUsersDiag(diags *diag.Diagnostics, summary string, err error) {
+	var le *winclient.LoggedOnUsersError
+	if errors.As(err, &le) {
+		detail := le.Message
+		if len(le.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range le.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if le.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", le.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
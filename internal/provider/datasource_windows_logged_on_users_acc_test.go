@@ -0,0 +1,45 @@
+//go:build acceptance
+
+// Package provider — acceptance-test skeleton for the windows_logged_on_users data source.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsLoggedOnUsersDataSource
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccLoggedOnUsersDSPreCheck(t *testing.T) {
+	t.Helper()
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("TF_ACC not set; skipping acceptance test")
+	}
+	for _, v := range []string{"WINDOWS_HOST", "WINDOWS_USERNAME", "WINDOWS_PASSWORD"} {
+		if os.Getenv(v) == "" {
+			t.Skipf("env %s not set; skipping acceptance test", v)
+		}
+	}
+}
+
+// TestAccWindowsLoggedOnUsersDataSource_Basic lists sessions on the target
+// host. A WinRM logon is not interactive, so the list may legitimately be
+// empty; only the shape is asserted.
+func TestAccWindowsLoggedOnUsersDataSource_Basic(t *testing.T) {
+	testAccLoggedOnUsersDSPreCheck(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "windows_logged_on_users" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_logged_on_users.test", "id", "current"),
+					resource.TestCheckResourceAttrSet("data.windows_logged_on_users.test", "sessions.#"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,191 @@
+// Package provider — unit tests for the windows_logged_on_users data source.
+//
+// windows_logged_on_users is a singleton: no Required lookup keys.
+// Tests cover: Metadata, Schema, Configure, Read happy path, Read with no
+// sessions, Read error.
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// ---------------------------------------------------------------------------
+// Fake client
+// ---------------------------------------------------------------------------
+
+type fakeLoggedOnUsersClient struct {
+	out []winclient.LoggedOnSession
+	err error
+}
+
+func (f *fakeLoggedOnUsersClient) List(_ context.Context) ([]winclient.LoggedOnSession, error) {
+	return f.out, f.err
+}
+
+// ---------------------------------------------------------------------------
+// tftypes helpers
+// ---------------------------------------------------------------------------
+
+func loggedOnUsersDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id": tftypes.String,
+		"sessions": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"username":   tftypes.String,
+			"session_id": tftypes.Number,
+			"state":      tftypes.String,
+			"logon_time": tftypes.String,
+		}}},
+	}}
+}
+
+func loggedOnUsersDSConfig() tfsdk.Config {
+	d := &windowsLoggedOnUsersDataSource{}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	objType := loggedOnUsersDSObjType()
+	return tfsdk.Config{
+		Schema: sr.Schema,
+		Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+			"id":       tftypes.NewValue(tftypes.String, nil),
+			"sessions": tftypes.NewValue(objType.AttributeTypes["sessions"], nil),
+		}),
+	}
+}
+
+func readLoggedOnUsersDS(t *testing.T, client winclient.WindowsLoggedOnUsersClient) (*datasource.ReadResponse, windowsLoggedOnUsersDataSourceModel, []windowsLoggedOnSessionModel) {
+	t.Helper()
+	d := &windowsLoggedOnUsersDataSource{lou: client}
+	cfg := loggedOnUsersDSConfig()
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsLoggedOnUsersDataSourceModel
+	var sessions []windowsLoggedOnSessionModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+		state.Sessions.ElementsAs(context.Background(), &sessions, false)
+	}
+	return resp, state, sessions
+}
+
+// ---------------------------------------------------------------------------
+// Metadata / Schema / Configure
+// ---------------------------------------------------------------------------
+
+func TestLoggedOnUsersDSMetadata(t *testing.T) {
+	d := &windowsLoggedOnUsersDataSource{}
+	resp := &datasource.MetadataResponse{}
+	d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_logged_on_users" {
+		t.Errorf("TypeName = %q, want windows_logged_on_users", resp.TypeName)
+	}
+}
+
+func TestNewWindowsLoggedOnUsersDataSource_NotNil(t *testing.T) {
+	if NewWindowsLoggedOnUsersDataSource() == nil {
+		t.Fatal("constructor must not return nil")
+	}
+}
+
+func TestLoggedOnUsersDSSchema_Attributes(t *testing.T) {
+	d := &windowsLoggedOnUsersDataSource{}
+	resp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
+	if len(resp.Schema.Attributes) != 2 {
+		t.Errorf("schema has %d attributes, want 2", len(resp.Schema.Attributes))
+	}
+	for _, k := range []string{"id", "sessions"} {
+		if _, ok := resp.Schema.Attributes[k]; !ok {
+			t.Errorf("schema missing attribute %q", k)
+		}
+	}
+}
+
+func TestLoggedOnUsersDSConfigure(t *testing.T) {
+	d := &windowsLoggedOnUsersDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: nil}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("nil ProviderData must not produce error: %v", resp.Diagnostics)
+	}
+
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: 42}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "winclient.Client") {
+		t.Errorf("wrong type must produce error, got %v", resp.Diagnostics)
+	}
+
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || d.lou == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Read
+// ---------------------------------------------------------------------------
+
+func TestLoggedOnUsersDSRead_HappyPath(t *testing.T) {
+	resp, state, sessions := readLoggedOnUsersDS(t, &fakeLoggedOnUsersClient{
+		out: []winclient.LoggedOnSession{
+			{Username: "administrator", SessionID: 1, State: "Active", LogonTime: "2026-10-17T08:15:00Z"},
+			{Username: "bob", SessionID: 3, State: "Disconnected"},
+		},
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "current" {
+		t.Errorf("ID = %q, want current", state.ID.ValueString())
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("sessions = %d, want 2", len(sessions))
+	}
+	if sessions[0].Username.ValueString() != "administrator" || sessions[0].SessionID.ValueInt64() != 1 ||
+		sessions[0].LogonTime.ValueString() != "2026-10-17T08:15:00Z" {
+		t.Errorf("unexpected first session: %+v", sessions[0])
+	}
+	if sessions[1].State.ValueString() != "Disconnected" || sessions[1].LogonTime.ValueString() != "" {
+		t.Errorf("unexpected second session: %+v", sessions[1])
+	}
+}
+
+func TestLoggedOnUsersDSRead_NoSessions(t *testing.T) {
+	resp, state, sessions := readLoggedOnUsersDS(t, &fakeLoggedOnUsersClient{out: []winclient.LoggedOnSession{}})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("no sessions must not be an error: %v", resp.Diagnostics)
+	}
+	if state.Sessions.IsNull() {
+		t.Error("sessions should be an empty list, not null")
+	}
+	if len(sessions) != 0 {
+		t.Errorf("sessions = %d, want 0", len(sessions))
+	}
+}
+
+func TestLoggedOnUsersDSRead_Error(t *testing.T) {
+	resp, _, _ := readLoggedOnUsersDS(t, &fakeLoggedOnUsersClient{
+		err: winclient.NewLoggedOnUsersError(winclient.LoggedOnUsersErrorPermission, "Access is denied", nil,
+			map[string]string{"host": "win01"}),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected error from read failure")
+	}
+	if !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: permission_denied") {
+		t.Errorf("detail should include kind, got %q", resp.Diagnostics[0].Detail())
+	}
+
+	resp, _, _ = readLoggedOnUsersDS(t, &fakeLoggedOnUsersClient{err: errors.New("boom")})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Detail() != "boom" {
+		t.Errorf("plain error should pass through, got %v", resp.Diagnostics)
+	}
+}
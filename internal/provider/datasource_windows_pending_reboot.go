@@ -0,0 +1,145 @@
+// Package provider: windows_pending_reboot data source implementation.
+//
+// Singleton data source — no lookup keys. Reports whether the connected host
+// already has a reboot queued, and why, so a configuration can decide to
+// schedule a reboot before installing features or packages.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsPendingRebootDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsPendingRebootDataSource)(nil)
+)
+
+// NewWindowsPendingRebootDataSource is the constructor registered in
+// provider.go.
+func NewWindowsPendingRebootDataSource() datasource.DataSource {
+	return &windowsPendingRebootDataSource{}
+}
+
+// windowsPendingRebootDataSource is the TPF data source type for
+// windows_pending_reboot.
+type windowsPendingRebootDataSource struct {
+	pr winclient.WindowsPendingRebootClient
+}
+
+// windowsPendingRebootDataSourceModel is the Terraform state model for the
+// windows_pending_reboot data source.
+type windowsPendingRebootDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Pending types.Bool   `tfsdk:"pending"`
+	Reasons types.List   `tfsdk:"reasons"`
+}
+
+// Metadata sets the data source type name ("windows_pending_reboot").
+func (d *windowsPendingRebootDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pending_reboot"
+}
+
+// Schema returns the TPF schema for the windows_pending_reboot data source.
+func (d *windowsPendingRebootDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports whether the remote Windows host has a reboot pending, from the registry " +
+			"signals left by component servicing, Windows Update, file replacement on boot and computer " +
+			"renames. This is a **singleton** data source — no lookup keys are required.\n\n" +
+			"The Terraform data source ID is always `\"current\"`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID; always \"current\" (singleton).",
+			},
+			"pending": schema.BoolAttribute{
+				Computed:    true,
+				Description: "True when at least one reason is present.",
+			},
+			"reasons": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Signals found, in check order: component_based_servicing, windows_update, " +
+					"pending_file_rename, computer_rename. Empty when no reboot is pending.",
+				MarkdownDescription: "Signals found, in check order: `component_based_servicing`, `windows_update`, " +
+					"`pending_file_rename`, `computer_rename`. Empty when no reboot is pending.",
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsPendingRebootDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.pr = winclient.NewPendingRebootClient(c)
+}
+
+// Read checks the pending-reboot signals.
+func (d *windowsPendingRebootDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsPendingRebootDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "windows_pending_reboot data source Read start")
+
+	pr, err := d.pr.Get(ctx)
+	if err != nil {
+		addPendingRebootDiag(&resp.Diagnostics, "Read windows_pending_reboot data source failed", err)
+		return
+	}
+
+	state := windowsPendingRebootDataSourceModel{
+		ID:      types.StringValue("current"),
+		Pending: types.BoolValue(pr.Pending),
+		Reasons: listFromStrings(pr.Reasons),
+	}
+
+	tflog.Debug(ctx, "windows_pending_reboot data source Read end", map[string]interface{}{
+		"pending": pr.Pending, "reasons": pr.Reasons,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// addPendingRebootDiag converts a winclient error into a Terraform
+// diagnostic.
+func addPendingRebootDiag(diags *diag.Diagnostics, summary string, err error) {
+	var pe *winclient.PendingRebootError
+	if errors.As(err, &pe) {
+		detail := pe.Message
+		if len(pe.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range pe.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if pe.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", pe.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
@@ -0,0 +1,34 @@
+//go:build acceptance
+
+// Package provider — acceptance test for the windows_pending_reboot data source.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsPendingRebootDataSource
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsPendingRebootDataSource_Basic reads the target host's
+// pending-reboot state. The host may or may not have a reboot queued, so only
+// the shape of the result is checked.
+func TestAccWindowsPendingRebootDataSource_Basic(t *testing.T) {
+	testAccLoggedOnUsersDSPreCheck(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "windows_pending_reboot" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_pending_reboot.test", "id", "current"),
+					resource.TestMatchResourceAttr("data.windows_pending_reboot.test", "pending", regexp.MustCompile(`^(true|false)$`)),
+					resource.TestCheckResourceAttrSet("data.windows_pending_reboot.test", "reasons.#"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,127 @@
+// Package provider — unit tests for the windows_pending_reboot data source.
+//
+// windows_pending_reboot is a singleton: no Required lookup keys.
+// Tests cover: Metadata, Schema, Configure, Read happy path, Read error.
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakePendingRebootClient struct {
+	out *winclient.PendingReboot
+	err error
+}
+
+func (f *fakePendingRebootClient) Get(_ context.Context) (*winclient.PendingReboot, error) {
+	return f.out, f.err
+}
+
+func pendingRebootDSConfig() tfsdk.Config {
+	d := &windowsPendingRebootDataSource{}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":      tftypes.String,
+		"pending": tftypes.Bool,
+		"reasons": tftypes.List{ElementType: tftypes.String},
+	}}
+	return tfsdk.Config{
+		Schema: sr.Schema,
+		Raw: tftypes.NewValue(typ, map[string]tftypes.Value{
+			"id":      tftypes.NewValue(tftypes.String, nil),
+			"pending": tftypes.NewValue(tftypes.Bool, nil),
+			"reasons": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		}),
+	}
+}
+
+func readPendingRebootDS(t *testing.T, client winclient.WindowsPendingRebootClient) (*datasource.ReadResponse, windowsPendingRebootDataSourceModel) {
+	t.Helper()
+	d := &windowsPendingRebootDataSource{pr: client}
+	cfg := pendingRebootDSConfig()
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsPendingRebootDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+	}
+	return resp, state
+}
+
+func TestPendingRebootDSMetadataAndSchema(t *testing.T) {
+	d := NewWindowsPendingRebootDataSource()
+	mresp := &datasource.MetadataResponse{}
+	d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, mresp)
+	if mresp.TypeName != "windows_pending_reboot" {
+		t.Errorf("TypeName = %q, want windows_pending_reboot", mresp.TypeName)
+	}
+
+	sresp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, sresp)
+	for _, k := range []string{"id", "pending", "reasons"} {
+		a, ok := sresp.Schema.Attributes[k]
+		if !ok || !a.IsComputed() {
+			t.Errorf("attribute %q must exist and be computed", k)
+		}
+	}
+}
+
+func TestPendingRebootDSConfigure(t *testing.T) {
+	d := &windowsPendingRebootDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: 42}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "winclient.Client") {
+		t.Errorf("wrong type must produce error, got %v", resp.Diagnostics)
+	}
+
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || d.pr == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
+
+func TestPendingRebootDSRead_HappyPath(t *testing.T) {
+	resp, state := readPendingRebootDS(t, &fakePendingRebootClient{out: &winclient.PendingReboot{
+		Pending: true,
+		Reasons: []string{winclient.PendingRebootWindowsUpdate, winclient.PendingRebootFileRename},
+	}})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	var reasons []string
+	state.Reasons.ElementsAs(context.Background(), &reasons, false)
+	if state.ID.ValueString() != "current" || !state.Pending.ValueBool() ||
+		strings.Join(reasons, ",") != "windows_update,pending_file_rename" {
+		t.Errorf("unexpected state: %+v", state)
+	}
+
+	resp, state = readPendingRebootDS(t, &fakePendingRebootClient{out: &winclient.PendingReboot{}})
+	if resp.Diagnostics.HasError() || state.Pending.ValueBool() || state.Reasons.IsNull() || len(state.Reasons.Elements()) != 0 {
+		t.Errorf("nothing pending must give pending = false and an empty list: %+v, %v", state, resp.Diagnostics)
+	}
+}
+
+func TestPendingRebootDSRead_Error(t *testing.T) {
+	resp, _ := readPendingRebootDS(t, &fakePendingRebootClient{
+		err: winclient.NewPendingRebootError(winclient.PendingRebootErrorPermission, "Access is denied", nil, nil),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: permission_denied") {
+		t.Errorf("expected a permission_denied diagnostic, got %v", resp.Diagnostics)
+	}
+
+	resp, _ = readPendingRebootDS(t, &fakePendingRebootClient{err: errors.New("boom")})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Detail() != "boom" {
+		t.Errorf("plain error should pass through, got %v", resp.Diagnostics)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -35,6 +36,9 @@ func NewWindowsRegistryValueDataSource() datasource.DataSource {
 // windows_registry_value.
 type windowsRegistryValueDataSource struct {
 	client winclient.RegistryValueClient
+	// keys tells a missing key from a missing value when Read finds nothing.
+	// Optional: when nil, the not-found error does not say which is absent.
+	keys winclient.RegistryKeyClient
 }
 
 // windowsRegistryValueDataSourceModel is the Terraform state model for the
@@ -61,7 +65,7 @@ func (d *windowsRegistryValueDataSource) Schema(_ context.Context, _ datasource.
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Reads a single named Windows registry value without managing its lifecycle.\n\n" +
 			"Lookup keys: `hive`, `path`, and `name` (all required; use `name = \"\"` for the Default value).\n\n" +
-			"Returns an error when the registry key or value does not exist on the target host.",
+			"Returns an error when the registry key or value does not exist on the target host, and says which of the two is missing.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -69,10 +73,12 @@ func (d *windowsRegistryValueDataSource) Schema(_ context.Context, _ datasource.
 			},
 			"hive": schema.StringAttribute{
 				Required:    true,
+				Validators:  []validator.String{hiveEnumValidator{}},
 				Description: "Registry hive: HKLM, HKCU, HKCR, HKU, or HKCC (case-insensitive).",
 			},
 			"path": schema.StringAttribute{
 				Required:    true,
+				Validators:  []validator.String{registryPathValidator{}},
 				Description: "Subkey path under the hive (backslash-separated, no leading/trailing backslash).",
 			},
 			"name": schema.StringAttribute{
@@ -119,6 +125,7 @@ func (d *windowsRegistryValueDataSource) Configure(_ context.Context, req dataso
 		return
 	}
 	d.client = winclient.NewRegistryValueClient(c)
+	d.keys = winclient.NewRegistryKeyClient(c)
 }
 
 // Read fetches the registry value state from the remote Windows host.
@@ -143,20 +150,14 @@ func (d *windowsRegistryValueDataSource) Read(ctx context.Context, req datasourc
 	rv, err := d.client.Read(ctx, hive, regPath, name, expand)
 	if err != nil {
 		if winclient.IsRegistryValueError(err, winclient.RegistryValueErrorNotFound) {
-			resp.Diagnostics.AddError(
-				fmt.Sprintf("Data source not found: windows_registry_value %s\\%s\\%s", hive, regPath, name),
-				fmt.Sprintf("No registry value named %q was found at hive=%s path=%s on the target host.", name, hive, regPath),
-			)
+			d.addNotFoundDiag(ctx, &resp.Diagnostics, hive, regPath, name)
 			return
 		}
 		addRVDiag(&resp.Diagnostics, "Read", err)
 		return
 	}
 	if rv == nil {
-		resp.Diagnostics.AddError(
-			fmt.Sprintf("Data source not found: windows_registry_value %s\\%s\\%s", hive, regPath, name),
-			fmt.Sprintf("No registry value named %q was found at hive=%s path=%s on the target host.", name, hive, regPath),
-		)
+		d.addNotFoundDiag(ctx, &resp.Diagnostics, hive, regPath, name)
 		return
 	}
 
@@ -183,6 +184,30 @@ func (d *windowsRegistryValueDataSource) Read(ctx context.Context, req datasourc
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// addNotFoundDiag reports a missing registry value. When the key client is
+// available it checks whether the key itself exists, so the error names what
+// is actually absent. A failed check falls back to the generic wording.
+func (d *windowsRegistryValueDataSource) addNotFoundDiag(ctx context.Context, diags *diag.Diagnostics, hive, regPath, name string) {
+	summary := fmt.Sprintf("Data source not found: windows_registry_value %s\\%s\\%s", hive, regPath, name)
+	detail := fmt.Sprintf("No registry value named %q was found at hive=%s path=%s on the target host.", name, hive, regPath)
+	if d.keys != nil {
+		key, err := d.keys.Read(ctx, hive, regPath)
+		switch {
+		case err != nil:
+			tflog.Debug(ctx, "windows_registry_value data source: key existence check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		case key == nil:
+			detail = fmt.Sprintf("The registry key %s\\%s does not exist on the target host.", hive, regPath)
+		case name == "":
+			detail = fmt.Sprintf("The registry key %s\\%s exists but has no Default value set.", hive, regPath)
+		default:
+			detail = fmt.Sprintf("The registry key %s\\%s exists but has no value named %q.", hive, regPath, name)
+		}
+	}
+	diags.AddError(summary, detail)
+}
+
 // applyRVStateDS populates the type and value fields of the data source model
 // from the registry value state returned by the client.
 func applyRVStateDS(m *windowsRegistryValueDataSourceModel, rv *winclient.RegistryValueState, diags *diag.Diagnostics) {
@@ -1,8 +1,8 @@
 // Package provider — unit tests for the windows_registry_value data source.
 //
 // Tests cover: Metadata, Schema, Configure, Read happy path (REG_SZ,
-// REG_MULTI_SZ, REG_BINARY), not-found, nil result, generic error,
-// and applyRVStateDS field mapping.
+// REG_MULTI_SZ, REG_BINARY), not-found (key vs value), nil result, generic
+// error, and applyRVStateDS field mapping.
 package provider
 
 import (
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
@@ -128,6 +129,17 @@ func TestRegistryValueDSSchema_RequiredLookupKeys(t *testing.T) {
 	}
 }
 
+func TestRegistryValueDSSchema_LookupKeyValidators(t *testing.T) {
+	d := &windowsRegistryValueDataSource{}
+	resp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, resp)
+	for _, k := range []string{"hive", "path"} {
+		if v := resp.Schema.Attributes[k].(schema.StringAttribute).Validators; len(v) == 0 {
+			t.Errorf("attribute %q has no validator", k)
+		}
+	}
+}
+
 func TestRegistryValueDSSchema_ComputedValueFields(t *testing.T) {
 	d := &windowsRegistryValueDataSource{}
 	resp := &datasource.SchemaResponse{}
@@ -325,6 +337,38 @@ func TestRegistryValueDSRead_NilResult(t *testing.T) {
 	}
 }
 
+func TestRegistryValueDSRead_NotFoundSaysWhatIsMissing(t *testing.T) {
+	cases := map[string]struct {
+		key     *winclient.RegistryKeyState
+		keyErr  error
+		name    string
+		wantSub string
+	}{
+		"key missing":           {name: "Val", wantSub: `registry key HKLM\SOFTWARE\MyApp does not exist`},
+		"value missing":         {key: &winclient.RegistryKeyState{Hive: "HKLM", Path: "SOFTWARE\\MyApp"}, name: "Val", wantSub: `exists but has no value named "Val"`},
+		"default value missing": {key: &winclient.RegistryKeyState{Hive: "HKLM", Path: "SOFTWARE\\MyApp"}, name: "", wantSub: "has no Default value set"},
+		"check failed":          {keyErr: errors.New("transport error"), name: "Val", wantSub: `No registry value named "Val"`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			keys := &fakeRegistryKeyClient{readOut: tc.key, readErr: tc.keyErr}
+			d := &windowsRegistryValueDataSource{client: &fakeRegistryValueClientDS{}, keys: keys}
+			cfg := regValueDSConfig("HKLM", "SOFTWARE\\MyApp", tc.name)
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+			d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected error")
+			}
+			if detail := resp.Diagnostics[0].Detail(); !strings.Contains(detail, tc.wantSub) {
+				t.Errorf("detail = %q, want it to contain %q", detail, tc.wantSub)
+			}
+			if keys.lastReadHive != "HKLM" || keys.lastReadPath != "SOFTWARE\\MyApp" {
+				t.Errorf("key check = %s\\%s", keys.lastReadHive, keys.lastReadPath)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Read — generic error
 // ---------------------------------------------------------------------------
@@ -0,0 +1,204 @@
+// Package provider: windows_services data source implementation.
+//
+// Lists the services registered in the SCM, optionally narrowed by a name
+// wildcard and a status. Typical use is driving for_each over a family of
+// services (e.g. every SQL Server instance) without naming each one.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsServicesDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsServicesDataSource)(nil)
+)
+
+// NewWindowsServicesDataSource is the constructor registered in provider.go.
+func NewWindowsServicesDataSource() datasource.DataSource {
+	return &windowsServicesDataSource{}
+}
+
+// windowsServicesDataSource is the TPF data source type for windows_services.
+type windowsServicesDataSource struct {
+	svc winclient.WindowsServiceLister
+}
+
+// windowsServicesDataSourceModel is the Terraform state model for the
+// windows_services data source.
+type windowsServicesDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	NameFilter   types.String `tfsdk:"name_filter"`
+	StatusFilter types.String `tfsdk:"status_filter"`
+	Services     types.List   `tfsdk:"services"`
+}
+
+// windowsServiceSummaryModel is one element of the services list.
+type windowsServiceSummaryModel struct {
+	Name        types.String `tfsdk:"name"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Status      types.String `tfsdk:"status"`
+	StartType   types.String `tfsdk:"start_type"`
+	StartName   types.String `tfsdk:"start_name"`
+}
+
+// serviceSummaryAttrTypes is the attr.Type map for a services element.
+var serviceSummaryAttrTypes = map[string]attr.Type{
+	"name":         types.StringType,
+	"display_name": types.StringType,
+	"status":       types.StringType,
+	"start_type":   types.StringType,
+	"start_name":   types.StringType,
+}
+
+// Metadata sets the data source type name ("windows_services").
+func (d *windowsServicesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_services"
+}
+
+// Schema returns the TPF schema for the windows_services data source.
+func (d *windowsServicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Windows services on the remote host, optionally filtered by name " +
+			"wildcard and status. No match yields an empty `services` list, not an error.\n\n" +
+			"The Terraform data source ID is `<name_filter>|<status_filter>` (empty filters render as `*`).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID derived from the filters.",
+			},
+			"name_filter": schema.StringAttribute{
+				Optional:            true,
+				Description:         "PowerShell wildcard matched case-insensitively against the short service name (e.g. MSSQL*). Omit to list every service.",
+				MarkdownDescription: "PowerShell wildcard (`*`, `?`, `[a-z]`) matched case-insensitively against the short service name, e.g. `MSSQL*`. Omit to list every service.",
+			},
+			"status_filter": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list services in this status: Running, Stopped, Paused, StartPending, StopPending, ContinuePending or PausePending.",
+				MarkdownDescription: "Only list services in this status: `Running`, `Stopped`, `Paused`, `StartPending`, `StopPending`, `ContinuePending` or `PausePending`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("Running", "Stopped", "Paused", "StartPending", "StopPending", "ContinuePending", "PausePending"),
+				},
+			},
+			"services": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching services, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Short service name.",
+						},
+						"display_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Human-readable display name shown in services.msc.",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "Runtime status as reported by Get-Service, including pending states.",
+						},
+						"start_type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Start mode: Automatic, AutomaticDelayedStart, Manual, Disabled, Boot or System.",
+						},
+						"start_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account the service logs on as (e.g. LocalSystem). Empty when Win32_Service could not be queried.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsServicesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.svc = winclient.NewServiceClient(c)
+}
+
+// Read enumerates the matching services on the remote Windows host.
+func (d *windowsServicesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsServicesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := winclient.ServiceListFilter{
+		NamePattern: config.NameFilter.ValueString(),
+		Status:      config.StatusFilter.ValueString(),
+	}
+	tflog.Debug(ctx, "windows_services data source Read start", map[string]interface{}{
+		"name_filter": filter.NamePattern, "status_filter": filter.Status,
+	})
+
+	services, err := d.svc.List(ctx, filter)
+	if err != nil {
+		addServiceDiag(&resp.Diagnostics, "Read windows_services data source failed", err)
+		return
+	}
+
+	elems := make([]attr.Value, 0, len(services))
+	for _, s := range services {
+		obj, diags := types.ObjectValueFrom(ctx, serviceSummaryAttrTypes, windowsServiceSummaryModel{
+			Name:        types.StringValue(s.Name),
+			DisplayName: types.StringValue(s.DisplayName),
+			Status:      types.StringValue(s.Status),
+			StartType:   types.StringValue(s.StartType),
+			StartName:   types.StringValue(s.StartName),
+		})
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, obj)
+	}
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: serviceSummaryAttrTypes}, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.ID = types.StringValue(servicesDataSourceID(filter))
+	config.Services = list
+
+	tflog.Debug(ctx, "windows_services data source Read end", map[string]interface{}{
+		"service_count": len(services),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// servicesDataSourceID renders the filters as "<name>|<status>", with "*"
+// standing in for an unset filter.
+func servicesDataSourceID(f winclient.ServiceListFilter) string {
+	name, status := f.NamePattern, f.Status
+	if name == "" {
+		name = "*"
+	}
+	if status == "" {
+		status = "*"
+	}
+	return name + "|" + status
+}
@@ -0,0 +1,38 @@
+//go:build acceptance
+
+// Package provider — acceptance test for the windows_services data source.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsServicesDataSource_Basic lists the WinRM service, which is
+// necessarily running on any host the provider can reach.
+func TestAccWindowsServicesDataSource_Basic(t *testing.T) {
+	testAccLoggedOnUsersDSPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "windows_services" "winrm" {
+  name_filter   = "WinR?"
+  status_filter = "Running"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_services.winrm", "id", "WinR?|Running"),
+					resource.TestCheckResourceAttr("data.windows_services.winrm", "services.#", "1"),
+					resource.TestCheckResourceAttr("data.windows_services.winrm", "services.0.name", "WinRM"),
+					resource.TestCheckResourceAttr("data.windows_services.winrm", "services.0.status", "Running"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,109 @@
+// Package provider — unit tests for the windows_services data source.
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeServiceLister struct {
+	out        []winclient.ServiceSummary
+	err        error
+	lastFilter winclient.ServiceListFilter
+}
+
+func (f *fakeServiceLister) List(_ context.Context, filter winclient.ServiceListFilter) ([]winclient.ServiceSummary, error) {
+	f.lastFilter = filter
+	return f.out, f.err
+}
+
+func servicesDSObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":            tftypes.String,
+		"name_filter":   tftypes.String,
+		"status_filter": tftypes.String,
+		"services": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":         tftypes.String,
+			"display_name": tftypes.String,
+			"status":       tftypes.String,
+			"start_type":   tftypes.String,
+			"start_name":   tftypes.String,
+		}}},
+	}}
+}
+
+func readServicesDS(t *testing.T, client winclient.WindowsServiceLister, nameFilter, statusFilter any) (*datasource.ReadResponse, windowsServicesDataSourceModel, []windowsServiceSummaryModel) {
+	t.Helper()
+	d := &windowsServicesDataSource{svc: client}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	objType := servicesDSObjType()
+	cfg := tfsdk.Config{Schema: sr.Schema, Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+		"id":            tftypes.NewValue(tftypes.String, nil),
+		"name_filter":   tftypes.NewValue(tftypes.String, nameFilter),
+		"status_filter": tftypes.NewValue(tftypes.String, statusFilter),
+		"services":      tftypes.NewValue(objType.AttributeTypes["services"], nil),
+	})}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sr.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsServicesDataSourceModel
+	var services []windowsServiceSummaryModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+		state.Services.ElementsAs(context.Background(), &services, false)
+	}
+	return resp, state, services
+}
+
+func TestServicesDataSource_Metadata(t *testing.T) {
+	resp := &datasource.MetadataResponse{}
+	(&windowsServicesDataSource{}).Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_services" {
+		t.Errorf("TypeName = %q", resp.TypeName)
+	}
+}
+
+func TestServicesDataSource_Read(t *testing.T) {
+	fake := &fakeServiceLister{out: []winclient.ServiceSummary{
+		{Name: "MSSQL$A", DisplayName: "SQL Server (A)", Status: "Running", StartType: "Automatic", StartName: `NT Service\MSSQL$A`},
+		{Name: "MSSQL$B", DisplayName: "SQL Server (B)", Status: "Running", StartType: "Manual", StartName: "LocalSystem"},
+	}}
+	resp, state, services := readServicesDS(t, fake, "MSSQL*", "Running")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if fake.lastFilter.NamePattern != "MSSQL*" || fake.lastFilter.Status != "Running" {
+		t.Errorf("filter = %+v", fake.lastFilter)
+	}
+	if state.ID.ValueString() != "MSSQL*|Running" {
+		t.Errorf("id = %q", state.ID.ValueString())
+	}
+	if len(services) != 2 || services[0].StartName.ValueString() != `NT Service\MSSQL$A` || services[1].StartType.ValueString() != "Manual" {
+		t.Errorf("services = %+v", services)
+	}
+}
+
+func TestServicesDataSource_Read_NoFiltersNoMatch(t *testing.T) {
+	fake := &fakeServiceLister{out: []winclient.ServiceSummary{}}
+	resp, state, services := readServicesDS(t, fake, nil, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "*|*" || state.Services.IsNull() || len(services) != 0 {
+		t.Errorf("state = %+v, services = %+v", state, services)
+	}
+}
+
+func TestServicesDataSource_Read_Error(t *testing.T) {
+	fake := &fakeServiceLister{err: winclient.NewServiceError(winclient.ServiceErrorPermission, "Access is denied", nil, nil)}
+	resp, _, _ := readServicesDS(t, fake, nil, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error diagnostic")
+	}
+}
@@ -0,0 +1,187 @@
+// Package provider: windows_system_info data source implementation.
+//
+// Singleton data source — no lookup keys. Reports the operating system and
+// hardware summary of the connected host (Win32_OperatingSystem and
+// Win32_ComputerSystem), for conditional logic such as choosing features by
+// OS SKU.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ datasource.DataSource              = (*windowsSystemInfoDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*windowsSystemInfoDataSource)(nil)
+)
+
+// NewWindowsSystemInfoDataSource is the constructor registered in provider.go.
+func NewWindowsSystemInfoDataSource() datasource.DataSource {
+	return &windowsSystemInfoDataSource{}
+}
+
+// windowsSystemInfoDataSource is the TPF data source type for
+// windows_system_info.
+type windowsSystemInfoDataSource struct {
+	si winclient.WindowsSystemInfoClient
+}
+
+// windowsSystemInfoDataSourceModel is the Terraform state model for the
+// windows_system_info data source.
+type windowsSystemInfoDataSourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	OSName              types.String `tfsdk:"os_name"`
+	OSVersion           types.String `tfsdk:"os_version"`
+	OSBuild             types.String `tfsdk:"os_build"`
+	OSArchitecture      types.String `tfsdk:"os_architecture"`
+	ProductType         types.String `tfsdk:"product_type"`
+	TotalPhysicalMemory types.Int64  `tfsdk:"total_physical_memory"`
+	LogicalProcessors   types.Int64  `tfsdk:"logical_processors"`
+	Domain              types.String `tfsdk:"domain"`
+	Workgroup           types.String `tfsdk:"workgroup"`
+}
+
+// Metadata sets the data source type name ("windows_system_info").
+func (d *windowsSystemInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_system_info"
+}
+
+// Schema returns the TPF schema for the windows_system_info data source.
+func (d *windowsSystemInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports the operating system and hardware summary of the remote Windows host, " +
+			"read from `Win32_OperatingSystem` and `Win32_ComputerSystem`. This is a **singleton** data " +
+			"source — no lookup keys are required.\n\n" +
+			"The Terraform data source ID is always `\"current\"`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Data source ID; always \"current\" (singleton).",
+			},
+			"os_name": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Operating system name, e.g. Microsoft Windows Server 2022 Standard.",
+				MarkdownDescription: "Operating system name, e.g. `Microsoft Windows Server 2022 Standard`.",
+			},
+			"os_version": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Operating system version, e.g. 10.0.20348.",
+				MarkdownDescription: "Operating system version, e.g. `10.0.20348`.",
+			},
+			"os_build": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Operating system build number, e.g. 20348.",
+				MarkdownDescription: "Operating system build number, e.g. `20348`.",
+			},
+			"os_architecture": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Operating system architecture, e.g. 64-bit.",
+				MarkdownDescription: "Operating system architecture, e.g. `64-bit`.",
+			},
+			"product_type": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Workstation, DomainController or Server.",
+				MarkdownDescription: "`Workstation`, `DomainController` or `Server`.",
+			},
+			"total_physical_memory": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Total physical memory in bytes.",
+			},
+			"logical_processors": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of logical processors.",
+			},
+			"domain": schema.StringAttribute{
+				Computed:    true,
+				Description: "Active Directory domain of a domain member; empty on a workgroup host.",
+			},
+			"workgroup": schema.StringAttribute{
+				Computed:    true,
+				Description: "Workgroup name of a workgroup host; empty on a domain member.",
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (d *windowsSystemInfoDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.si = winclient.NewSystemInfoClient(c)
+}
+
+// Read fetches the host summary.
+func (d *windowsSystemInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config windowsSystemInfoDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "windows_system_info data source Read start")
+
+	info, err := d.si.Get(ctx)
+	if err != nil {
+		addSystemInfoDiag(&resp.Diagnostics, "Read windows_system_info data source failed", err)
+		return
+	}
+
+	state := windowsSystemInfoDataSourceModel{
+		ID:                  types.StringValue("current"),
+		OSName:              types.StringValue(info.OSName),
+		OSVersion:           types.StringValue(info.OSVersion),
+		OSBuild:             types.StringValue(info.OSBuild),
+		OSArchitecture:      types.StringValue(info.OSArchitecture),
+		ProductType:         types.StringValue(info.ProductType),
+		TotalPhysicalMemory: types.Int64Value(info.TotalPhysicalMemory),
+		LogicalProcessors:   types.Int64Value(info.LogicalProcessors),
+		Domain:              types.StringValue(info.Domain),
+		Workgroup:           types.StringValue(info.Workgroup),
+	}
+
+	tflog.Debug(ctx, "windows_system_info data source Read end", map[string]interface{}{
+		"os_name": info.OSName, "os_build": info.OSBuild,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// addSystemInfoDiag converts a winclient error into a Terraform diagnostic.
+func addSystemInfoDiag(diags *diag.Diagnostics, summary string, err error) {
+	var se *winclient.SystemInfoError
+	if errors.As(err, &se) {
+		detail := se.Message
+		if len(se.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range se.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if se.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", se.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
@@ -0,0 +1,35 @@
+//go:build acceptance
+
+// Package provider — acceptance test for the windows_system_info data source.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsSystemInfoDataSource
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsSystemInfoDataSource_Basic reads the target host summary.
+func TestAccWindowsSystemInfoDataSource_Basic(t *testing.T) {
+	testAccLoggedOnUsersDSPreCheck(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "windows_system_info" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.windows_system_info.test", "id", "current"),
+					resource.TestMatchResourceAttr("data.windows_system_info.test", "os_name", regexp.MustCompile(`Windows`)),
+					resource.TestMatchResourceAttr("data.windows_system_info.test", "os_build", regexp.MustCompile(`^\d+$`)),
+					resource.TestMatchResourceAttr("data.windows_system_info.test", "product_type",
+						regexp.MustCompile(`^(Workstation|DomainController|Server)$`)),
+					resource.TestCheckResourceAttrSet("data.windows_system_info.test", "logical_processors"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,132 @@
+// Package provider — unit tests for the windows_system_info data source.
+//
+// windows_system_info is a singleton: no Required lookup keys.
+// Tests cover: Metadata, Schema, Configure, Read happy path, Read error.
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeSystemInfoClient struct {
+	out *winclient.SystemInfo
+	err error
+}
+
+func (f *fakeSystemInfoClient) Get(_ context.Context) (*winclient.SystemInfo, error) {
+	return f.out, f.err
+}
+
+var systemInfoDSAttrs = []string{
+	"id", "os_name", "os_version", "os_build", "os_architecture", "product_type",
+	"total_physical_memory", "logical_processors", "domain", "workgroup",
+}
+
+func systemInfoDSConfig() tfsdk.Config {
+	d := &windowsSystemInfoDataSource{}
+	sr := datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, &sr)
+	types := map[string]tftypes.Type{}
+	vals := map[string]tftypes.Value{}
+	for _, k := range systemInfoDSAttrs {
+		typ := tftypes.Type(tftypes.String)
+		if k == "total_physical_memory" || k == "logical_processors" {
+			typ = tftypes.Number
+		}
+		types[k] = typ
+		vals[k] = tftypes.NewValue(typ, nil)
+	}
+	return tfsdk.Config{
+		Schema: sr.Schema,
+		Raw:    tftypes.NewValue(tftypes.Object{AttributeTypes: types}, vals),
+	}
+}
+
+func readSystemInfoDS(t *testing.T, client winclient.WindowsSystemInfoClient) (*datasource.ReadResponse, windowsSystemInfoDataSourceModel) {
+	t.Helper()
+	d := &windowsSystemInfoDataSource{si: client}
+	cfg := systemInfoDSConfig()
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema}}
+	d.Read(context.Background(), datasource.ReadRequest{Config: cfg}, resp)
+	var state windowsSystemInfoDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &state)
+	}
+	return resp, state
+}
+
+func TestSystemInfoDSMetadataAndSchema(t *testing.T) {
+	d := NewWindowsSystemInfoDataSource()
+	mresp := &datasource.MetadataResponse{}
+	d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "windows"}, mresp)
+	if mresp.TypeName != "windows_system_info" {
+		t.Errorf("TypeName = %q, want windows_system_info", mresp.TypeName)
+	}
+
+	sresp := &datasource.SchemaResponse{}
+	d.Schema(context.Background(), datasource.SchemaRequest{}, sresp)
+	if len(sresp.Schema.Attributes) != len(systemInfoDSAttrs) {
+		t.Errorf("schema has %d attributes, want %d", len(sresp.Schema.Attributes), len(systemInfoDSAttrs))
+	}
+	for _, k := range systemInfoDSAttrs {
+		a, ok := sresp.Schema.Attributes[k]
+		if !ok || !a.IsComputed() {
+			t.Errorf("attribute %q must exist and be computed", k)
+		}
+	}
+}
+
+func TestSystemInfoDSConfigure(t *testing.T) {
+	d := &windowsSystemInfoDataSource{}
+	resp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: 42}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "winclient.Client") {
+		t.Errorf("wrong type must produce error, got %v", resp.Diagnostics)
+	}
+
+	resp = &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || d.si == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
+
+func TestSystemInfoDSRead_HappyPath(t *testing.T) {
+	resp, state := readSystemInfoDS(t, &fakeSystemInfoClient{out: &winclient.SystemInfo{
+		OSName: "Microsoft Windows Server 2022 Standard", OSVersion: "10.0.20348", OSBuild: "20348",
+		OSArchitecture: "64-bit", ProductType: winclient.ProductTypeServer,
+		TotalPhysicalMemory: 17179398144, LogicalProcessors: 4, Domain: "corp.example",
+	}})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "current" || state.ProductType.ValueString() != "Server" ||
+		state.OSBuild.ValueString() != "20348" || state.TotalPhysicalMemory.ValueInt64() != 17179398144 ||
+		state.LogicalProcessors.ValueInt64() != 4 || state.Domain.ValueString() != "corp.example" ||
+		state.Workgroup.ValueString() != "" {
+		t.Errorf("unexpected state: %+v", state)
+	}
+}
+
+func TestSystemInfoDSRead_Error(t *testing.T) {
+	resp, _ := readSystemInfoDS(t, &fakeSystemInfoClient{
+		err: winclient.NewSystemInfoError(winclient.SystemInfoErrorPermission, "Access is denied", nil, nil),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: permission_denied") {
+		t.Errorf("expected a permission_denied diagnostic, got %v", resp.Diagnostics)
+	}
+
+	resp, _ = readSystemInfoDS(t, &fakeSystemInfoClient{err: errors.New("boom")})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Detail() != "boom" {
+		t.Errorf("plain error should pass through, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,273 @@
+// Package provider: windows_ephemeral_password ephemeral resource.
+//
+// Generates a random password at plan/apply time that is never written to
+// plan or state (Terraform >= 1.10). It is meant to be wired into write-only
+// arguments such as windows_local_user.password_wo. No WinRM connection is
+// involved: the generator runs entirely inside the provider process using
+// crypto/rand.
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Framework interface assertions.
+var (
+	_ ephemeral.EphemeralResource                   = (*windowsEphemeralPasswordResource)(nil)
+	_ ephemeral.EphemeralResourceWithValidateConfig = (*windowsEphemeralPasswordResource)(nil)
+)
+
+// Character classes and defaults. The default special set leaves out
+// characters that need escaping in cmd.exe or PowerShell (" ' ` % ^ & | < >
+// and backslash), so the result can be passed to sc.exe, net.exe or a
+// PowerShell literal without quoting surprises.
+const (
+	passwordUpperChars      = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordLowerChars      = "abcdefghijklmnopqrstuvwxyz"
+	passwordNumericChars    = "0123456789"
+	passwordSpecialChars    = "!#$*()-_=+[]{}:?.,"
+	passwordDefaultLength   = 24
+	passwordMinLength       = 8
+	passwordMaxLength       = 256
+	passwordComplexityMinOf = 3 // Windows "meets complexity requirements": 3 of 4 classes
+)
+
+// NewWindowsEphemeralPasswordResource is the constructor registered in provider.go.
+func NewWindowsEphemeralPasswordResource() ephemeral.EphemeralResource {
+	return &windowsEphemeralPasswordResource{}
+}
+
+// windowsEphemeralPasswordResource is the TPF ephemeral resource type for
+// windows_ephemeral_password.
+type windowsEphemeralPasswordResource struct{}
+
+// windowsEphemeralPasswordModel is the config/result model.
+type windowsEphemeralPasswordModel struct {
+	Length          types.Int64  `tfsdk:"length"`
+	Upper           types.Bool   `tfsdk:"upper"`
+	Lower           types.Bool   `tfsdk:"lower"`
+	Numeric         types.Bool   `tfsdk:"numeric"`
+	Special         types.Bool   `tfsdk:"special"`
+	OverrideSpecial types.String `tfsdk:"override_special"`
+	Result          types.String `tfsdk:"result"`
+}
+
+// Metadata sets the ephemeral resource type name ("windows_ephemeral_password").
+func (r *windowsEphemeralPasswordResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ephemeral_password"
+}
+
+// Schema returns the TPF schema for windows_ephemeral_password.
+func (r *windowsEphemeralPasswordResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a random password that is **never persisted** to the Terraform plan or " +
+			"state. Requires Terraform 1.10 or later.\n\n" +
+			"The value is produced with `crypto/rand` each time Terraform opens the ephemeral resource, so " +
+			"it is only stable within a single plan or apply. Wire it into write-only arguments such as " +
+			"`windows_local_user.password_wo` and bump the matching `*_wo_version` when you want the new " +
+			"value applied.\n\n" +
+			"With the defaults the result contains at least one character from each of the four classes " +
+			"(upper, lower, numeric, special) and so satisfies the Windows *Password must meet complexity " +
+			"requirements* policy.",
+		Attributes: map[string]schema.Attribute{
+			"length": schema.Int64Attribute{
+				Optional: true,
+				Description: fmt.Sprintf("Password length. Default %d; must be between %d and %d.",
+					passwordDefaultLength, passwordMinLength, passwordMaxLength),
+				Validators: []validator.Int64{
+					int64validator.Between(passwordMinLength, passwordMaxLength),
+				},
+			},
+			"upper": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include upper-case letters (A-Z). Default true.",
+			},
+			"lower": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include lower-case letters (a-z). Default true.",
+			},
+			"numeric": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include digits (0-9). Default true.",
+			},
+			"special": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include special characters. Default true.",
+			},
+			"override_special": schema.StringAttribute{
+				Optional: true,
+				Description: "Replace the default special character set " + passwordSpecialChars +
+					" with this string. Only used when special is true.",
+				MarkdownDescription: "Replace the default special character set `" + passwordSpecialChars +
+					"` with this string. Only used when `special` is `true`. The default set omits characters " +
+					"that need escaping in cmd.exe or PowerShell.",
+			},
+			"result": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated password.",
+			},
+		},
+	}
+}
+
+// ValidateConfig rejects configurations that cannot produce a password:
+// every class disabled, an empty override_special, or a length too short to
+// hold one character of each enabled class. Fewer than three classes is
+// allowed but warned about, since Windows complexity rules would reject it.
+func (r *windowsEphemeralPasswordResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var cfg windowsEphemeralPasswordModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, v := range []types.Bool{cfg.Upper, cfg.Lower, cfg.Numeric, cfg.Special} {
+		if v.IsUnknown() {
+			return
+		}
+	}
+	if cfg.Length.IsUnknown() || cfg.OverrideSpecial.IsUnknown() {
+		return
+	}
+
+	if passwordBoolDefault(cfg.Special) && !cfg.OverrideSpecial.IsNull() && cfg.OverrideSpecial.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("override_special"), "Empty special character set",
+			"override_special must not be empty when special is true. Set special = false instead.")
+		return
+	}
+	classes := passwordClasses(cfg)
+	if len(classes) == 0 {
+		resp.Diagnostics.AddError("No character classes enabled",
+			"At least one of upper, lower, numeric or special must be true.")
+		return
+	}
+	if length := passwordLength(cfg); length < len(classes) {
+		resp.Diagnostics.AddAttributeError(path.Root("length"), "Password too short",
+			fmt.Sprintf("length %d cannot hold one character from each of the %d enabled classes.", length, len(classes)))
+		return
+	}
+	if len(classes) < passwordComplexityMinOf {
+		resp.Diagnostics.AddWarning("Password may not meet Windows complexity requirements",
+			fmt.Sprintf("Only %d character class(es) are enabled. Windows accounts with the complexity policy "+
+				"enabled require characters from at least %d of: upper, lower, numeric, special.",
+				len(classes), passwordComplexityMinOf))
+	}
+}
+
+// Open generates the password and returns it as the ephemeral result.
+func (r *windowsEphemeralPasswordResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var cfg windowsEphemeralPasswordModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pw, err := generatePassword(passwordLength(cfg), passwordClasses(cfg))
+	if err != nil {
+		resp.Diagnostics.AddError("Generate windows_ephemeral_password failed", err.Error())
+		return
+	}
+	cfg.Result = types.StringValue(pw)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &cfg)...)
+}
+
+// passwordBoolDefault returns v, treating null as true.
+func passwordBoolDefault(v types.Bool) bool {
+	return v.IsNull() || v.ValueBool()
+}
+
+// passwordLength returns the configured length or the default.
+func passwordLength(cfg windowsEphemeralPasswordModel) int {
+	if cfg.Length.IsNull() || cfg.Length.IsUnknown() {
+		return passwordDefaultLength
+	}
+	return int(cfg.Length.ValueInt64())
+}
+
+// passwordClasses returns the enabled character classes in a fixed order.
+func passwordClasses(cfg windowsEphemeralPasswordModel) []string {
+	var out []string
+	if passwordBoolDefault(cfg.Upper) {
+		out = append(out, passwordUpperChars)
+	}
+	if passwordBoolDefault(cfg.Lower) {
+		out = append(out, passwordLowerChars)
+	}
+	if passwordBoolDefault(cfg.Numeric) {
+		out = append(out, passwordNumericChars)
+	}
+	if passwordBoolDefault(cfg.Special) {
+		special := passwordSpecialChars
+		if !cfg.OverrideSpecial.IsNull() && !cfg.OverrideSpecial.IsUnknown() {
+			special = cfg.OverrideSpecial.ValueString()
+		}
+		if special != "" {
+			out = append(out, special)
+		}
+	}
+	return out
+}
+
+// generatePassword returns a length-character password drawn uniformly from
+// the union of classes, with at least one character from every class. All
+// randomness, including the final shuffle, comes from crypto/rand.
+func generatePassword(length int, classes []string) (string, error) {
+	if len(classes) == 0 {
+		return "", fmt.Errorf("no character classes enabled")
+	}
+	if length < len(classes) {
+		return "", fmt.Errorf("length %d is shorter than the %d enabled character classes", length, len(classes))
+	}
+	all := []rune(strings.Join(classes, ""))
+	out := make([]rune, 0, length)
+	for _, class := range classes {
+		c, err := randomRune([]rune(class))
+		if err != nil {
+			return "", err
+		}
+		out = append(out, c)
+	}
+	for len(out) < length {
+		c, err := randomRune(all)
+		if err != nil {
+			return "", err
+		}
+		out = append(out, c)
+	}
+	for i := len(out) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out), nil
+}
+
+// randomRune picks one rune from set.
+func randomRune(set []rune) (rune, error) {
+	i, err := randomIndex(len(set))
+	if err != nil {
+		return 0, err
+	}
+	return set[i], nil
+}
+
+// randomIndex returns a uniform integer in [0, n).
+func randomIndex(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("crypto/rand: %w", err)
+	}
+	return int(v.Int64()), nil
+}
@@ -0,0 +1,235 @@
+// Package provider — unit tests for the windows_ephemeral_password ephemeral resource.
+//
+// Tests cover: Metadata, Schema, the crypto/rand generator, ValidateConfig
+// (class and length rules) and Open.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ---------------------------------------------------------------------------
+// tftypes helpers
+// ---------------------------------------------------------------------------
+
+func ephemeralPasswordObjType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"length":           tftypes.Number,
+		"upper":            tftypes.Bool,
+		"lower":            tftypes.Bool,
+		"numeric":          tftypes.Bool,
+		"special":          tftypes.Bool,
+		"override_special": tftypes.String,
+		"result":           tftypes.String,
+	}}
+}
+
+func ephemeralPasswordSchema() schema.Schema {
+	r := &windowsEphemeralPasswordResource{}
+	resp := &ephemeral.SchemaResponse{}
+	r.Schema(context.Background(), ephemeral.SchemaRequest{}, resp)
+	return resp.Schema
+}
+
+func ephemeralPasswordConfig(overrides map[string]tftypes.Value) tfsdk.Config {
+	objType := ephemeralPasswordObjType()
+	vals := map[string]tftypes.Value{}
+	for k, t := range objType.AttributeTypes {
+		vals[k] = tftypes.NewValue(t, nil)
+	}
+	for k, v := range overrides {
+		vals[k] = v
+	}
+	return tfsdk.Config{Schema: ephemeralPasswordSchema(), Raw: tftypes.NewValue(objType, vals)}
+}
+
+func validateEphemeralPassword(overrides map[string]tftypes.Value) *ephemeral.ValidateConfigResponse {
+	r := &windowsEphemeralPasswordResource{}
+	resp := &ephemeral.ValidateConfigResponse{}
+	r.ValidateConfig(context.Background(), ephemeral.ValidateConfigRequest{Config: ephemeralPasswordConfig(overrides)}, resp)
+	return resp
+}
+
+func openEphemeralPassword(t *testing.T, overrides map[string]tftypes.Value) (*ephemeral.OpenResponse, windowsEphemeralPasswordModel) {
+	t.Helper()
+	r := &windowsEphemeralPasswordResource{}
+	cfg := ephemeralPasswordConfig(overrides)
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: cfg.Schema, Raw: cfg.Raw.Copy()}}
+	r.Open(context.Background(), ephemeral.OpenRequest{Config: cfg}, resp)
+	var got windowsEphemeralPasswordModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.Result.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+// ---------------------------------------------------------------------------
+// Metadata / Schema
+// ---------------------------------------------------------------------------
+
+func TestEphemeralPasswordMetadata(t *testing.T) {
+	r := &windowsEphemeralPasswordResource{}
+	resp := &ephemeral.MetadataResponse{}
+	r.Metadata(context.Background(), ephemeral.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_ephemeral_password" {
+		t.Errorf("TypeName = %q, want windows_ephemeral_password", resp.TypeName)
+	}
+}
+
+func TestEphemeralPasswordSchema_ResultIsSensitive(t *testing.T) {
+	s := ephemeralPasswordSchema()
+	res, ok := s.Attributes["result"].(schema.StringAttribute)
+	if !ok {
+		t.Fatal("result must be a StringAttribute")
+	}
+	if !res.Computed || !res.Sensitive {
+		t.Errorf("result must be Computed+Sensitive, got %+v", res)
+	}
+	if len(s.Attributes) != 7 {
+		t.Errorf("schema has %d attributes, want 7", len(s.Attributes))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// generatePassword
+// ---------------------------------------------------------------------------
+
+func TestGeneratePassword_LengthAndClasses(t *testing.T) {
+	classes := []string{passwordUpperChars, passwordLowerChars, passwordNumericChars, passwordSpecialChars}
+	for _, n := range []int{4, 8, 24, 256} {
+		pw, err := generatePassword(n, classes)
+		if err != nil {
+			t.Fatalf("generatePassword(%d): %v", n, err)
+		}
+		if len([]rune(pw)) != n {
+			t.Errorf("len = %d, want %d", len([]rune(pw)), n)
+		}
+		for _, c := range classes {
+			if !strings.ContainsAny(pw, c) {
+				t.Errorf("password %q has no character from %q", pw, c)
+			}
+		}
+		if strings.Trim(pw, strings.Join(classes, "")) != "" {
+			t.Errorf("password %q contains characters outside the enabled classes", pw)
+		}
+	}
+}
+
+func TestGeneratePassword_Unique(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		pw, err := generatePassword(24, []string{passwordLowerChars, passwordNumericChars})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[pw] {
+			t.Fatalf("duplicate password %q", pw)
+		}
+		seen[pw] = true
+	}
+}
+
+func TestGeneratePassword_Errors(t *testing.T) {
+	if _, err := generatePassword(8, nil); err == nil {
+		t.Error("no classes must be an error")
+	}
+	if _, err := generatePassword(2, []string{"a", "b", "c"}); err == nil {
+		t.Error("length below class count must be an error")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ValidateConfig
+// ---------------------------------------------------------------------------
+
+func TestEphemeralPasswordValidate_Defaults(t *testing.T) {
+	resp := validateEphemeralPassword(nil)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 0 {
+		t.Errorf("defaults must validate cleanly: %v", resp.Diagnostics)
+	}
+}
+
+func TestEphemeralPasswordValidate_NoClasses(t *testing.T) {
+	f := tftypes.NewValue(tftypes.Bool, false)
+	resp := validateEphemeralPassword(map[string]tftypes.Value{"upper": f, "lower": f, "numeric": f, "special": f})
+	if !resp.Diagnostics.HasError() {
+		t.Error("all classes disabled must be an error")
+	}
+}
+
+func TestEphemeralPasswordValidate_EmptyOverrideSpecial(t *testing.T) {
+	resp := validateEphemeralPassword(map[string]tftypes.Value{
+		"override_special": tftypes.NewValue(tftypes.String, ""),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Error("empty override_special with special=true must be an error")
+	}
+}
+
+func TestEphemeralPasswordValidate_WeakWarns(t *testing.T) {
+	f := tftypes.NewValue(tftypes.Bool, false)
+	resp := validateEphemeralPassword(map[string]tftypes.Value{"upper": f, "special": f})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("two classes is allowed: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a complexity warning, got %v", resp.Diagnostics)
+	}
+}
+
+func TestEphemeralPasswordValidate_UnknownSkips(t *testing.T) {
+	resp := validateEphemeralPassword(map[string]tftypes.Value{
+		"upper": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+		"lower": tftypes.NewValue(tftypes.Bool, false), "numeric": tftypes.NewValue(tftypes.Bool, false),
+		"special": tftypes.NewValue(tftypes.Bool, false),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Errorf("unknown values must defer validation: %v", resp.Diagnostics)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Open
+// ---------------------------------------------------------------------------
+
+func TestEphemeralPasswordOpen_Defaults(t *testing.T) {
+	resp, got := openEphemeralPassword(t, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	pw := got.Result.ValueString()
+	if len(pw) != passwordDefaultLength {
+		t.Errorf("len = %d, want %d", len(pw), passwordDefaultLength)
+	}
+	if !strings.ContainsAny(pw, passwordSpecialChars) || !strings.ContainsAny(pw, passwordUpperChars) {
+		t.Errorf("default password %q should contain every class", pw)
+	}
+}
+
+func TestEphemeralPasswordOpen_Custom(t *testing.T) {
+	resp, got := openEphemeralPassword(t, map[string]tftypes.Value{
+		"length":           tftypes.NewValue(tftypes.Number, 12),
+		"numeric":          tftypes.NewValue(tftypes.Bool, false),
+		"override_special": tftypes.NewValue(tftypes.String, "@"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	pw := got.Result.ValueString()
+	if len(pw) != 12 {
+		t.Errorf("len = %d, want 12", len(pw))
+	}
+	if strings.ContainsAny(pw, passwordNumericChars) {
+		t.Errorf("numeric=false but password %q has digits", pw)
+	}
+	if !strings.Contains(pw, "@") || strings.ContainsAny(pw, passwordSpecialChars) {
+		t.Errorf("override_special not honoured: %q", pw)
+	}
+}
@@ -6,21 +6,31 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
-var _ provider.Provider = (*windowsProvider)(nil)
+var (
+	_ provider.Provider                       = (*windowsProvider)(nil)
+	_ provider.ProviderWithEphemeralResources = (*windowsProvider)(nil)
+)
 
 // windowsProvider is the concrete provider implementation.
 type windowsProvider struct {
@@ -44,6 +54,47 @@ type providerModel struct {
 	Insecure types.Bool   `tfsdk:"insecure"`
 	AuthType types.String `tfsdk:"auth_type"`
 	Timeout  types.String `tfsdk:"timeout"`
+
+	DefaultCommandTimeout types.String `tfsdk:"default_command_timeout"`
+
+	RequireAdmin types.Bool `tfsdk:"require_admin"`
+
+	PowerShellPath types.String `tfsdk:"powershell_path"`
+
+	EnableReadBatching types.Bool `tfsdk:"enable_read_batching"`
+
+	UseEncodedCommand types.Bool `tfsdk:"use_encoded_command"`
+
+	RunAsUsername types.String `tfsdk:"run_as_username"`
+	RunAsPassword types.String `tfsdk:"run_as_password"`
+
+	BastionHost          types.String `tfsdk:"bastion_host"`
+	BastionPort          types.Int64  `tfsdk:"bastion_port"`
+	BastionUsername      types.String `tfsdk:"bastion_username"`
+	BastionPassword      types.String `tfsdk:"bastion_password"`
+	BastionKeyPath       types.String `tfsdk:"bastion_key_path"`
+	BastionKeyPassphrase types.String `tfsdk:"bastion_key_passphrase"`
+	BastionHostKey       types.String `tfsdk:"bastion_host_key"`
+	// BastionInsecureIgnoreHostKey opts out of bastion host key checking.
+	BastionInsecureIgnoreHostKey types.Bool `tfsdk:"bastion_insecure_ignore_host_key"`
+	// BastionKeepaliveInterval is in seconds; 0 disables keepalives.
+	BastionKeepaliveInterval types.Int64 `tfsdk:"bastion_keepalive_interval"`
+	BastionCiphers           types.List  `tfsdk:"bastion_ciphers"`
+	BastionKexAlgorithms     types.List  `tfsdk:"bastion_kex_algorithms"`
+	BastionMACs              types.List  `tfsdk:"bastion_macs"`
+	BastionHostKeyAlgorithms types.List  `tfsdk:"bastion_host_key_algorithms"`
+}
+
+// checkAdministrator is the indirection used by Configure for the
+// require_admin pre-flight. Tests may override it; production code must not.
+var checkAdministrator = func(ctx context.Context, c *winclient.Client) (*winclient.AdminCheckResult, error) {
+	return c.IsAdministrator(ctx)
+}
+
+// probePowerShell is the indirection used by Configure to verify a custom
+// powershell_path. Tests may override it; production code must not.
+var probePowerShell = func(ctx context.Context, c *winclient.Client) (*winclient.PowerShellInfo, error) {
+	return c.PowerShellVersion(ctx)
 }
 
 // Metadata sets the provider type name and version.
@@ -58,12 +109,15 @@ func (p *windowsProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 		Description: "The windows provider manages Windows resources over WinRM.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Description: "Hostname or IP address of the target Windows host. May also be set via WINDOWS_HOST.",
+				Description: "Hostname or IP address of the target Windows host. IPv6 literals may be given bare (2001:db8::1) or bracketed. May also be set via WINDOWS_HOST.",
 				Optional:    true,
 			},
 			"port": schema.Int64Attribute{
-				Description: "WinRM port (default 5985 for HTTP, 5986 for HTTPS).",
+				Description: "WinRM port (default 5985 for HTTP, 5986 for HTTPS). Set it when WinRM listens on a non-standard port.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 			},
 			"username": schema.StringAttribute{
 				Description: "WinRM username. May also be set via WINDOWS_USERNAME.",
@@ -93,6 +147,148 @@ func (p *windowsProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				Description: "Operation timeout as a Go duration string (e.g. 30s, 2m). Default: 30s.",
 				Optional:    true,
 			},
+			"default_command_timeout": schema.StringAttribute{
+				Description: "Default per-operation timeout, as a Go duration string (e.g. 45m, 1h), for resources " +
+					"that accept a timeouts block (windows_feature, windows_group_policy_refresh, windows_hostname, " +
+					"windows_legacy_package, windows_scheduled_task, windows_winget_package). It replaces each resource's built-in default; a timeouts block on a " +
+					"resource still wins. Default: unset (built-in defaults apply).",
+				Optional: true,
+			},
+			"require_admin": schema.BoolAttribute{
+				Description: "Verify once at configure time that the WinRM account holds an elevated token in " +
+					"BUILTIN\\Administrators, and fail with a single actionable error if it does not. Nearly every " +
+					"resource needs elevation; without the check a non-admin account fails later with scattered " +
+					"access-denied errors. If the host cannot be reached the check is skipped with a warning. Default: true.",
+				Optional: true,
+			},
+			"powershell_path": schema.StringAttribute{
+				Description: "PowerShell executable every script runs under, as a name on the host's PATH or a full path, " +
+					"e.g. pwsh.exe or C:\\Program Files\\PowerShell\\7\\pwsh.exe for hosts that only ship PowerShell 7. " +
+					"When set, Configure runs a version probe through it and fails if it does not respond. " +
+					"Default: " + winclient.DefaultPowerShellPath + ".",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^"\r\n]+$`), "must not contain quotes or line breaks"),
+				},
+			},
+			"enable_read_batching": schema.BoolAttribute{
+				Description: "Serve windows_local_user and windows_feature reads from one Get-LocalUser / Get-WindowsFeature " +
+					"call per kind, shared by every resource for 30s, instead of one call per resource. Speeds up the " +
+					"refresh of large configurations. Changes error granularity: when the shared call fails, every read " +
+					"waiting on it fails with the same error. Default: false.",
+				Optional: true,
+			},
+			"use_encoded_command": schema.BoolAttribute{
+				Description: "Start PowerShell with -EncodedCommand. Set to false to pass the fixed bootstrap as plain " +
+					"-Command text instead, for hosts whose policy blocks or alerts on encoded commands, or to read the " +
+					"command line as-is in process creation logs. Scripts are sent on stdin either way, so there is no " +
+					"command-length difference. Default: true.",
+				Optional: true,
+			},
+			"run_as_username": schema.StringAttribute{
+				Description: "Run every command as this account instead of the WinRM login (DOMAIN\\user, user@domain or " +
+					"a local user), for operations that need another privileged account. Each command opens a " +
+					"PowerShell remoting session to the host itself with these credentials, so the account must be " +
+					"allowed to connect to WinRM on the host (Administrators or Remote Management Users). Use a " +
+					"provider alias to run only some resources under it. Requires run_as_password.",
+				Optional: true,
+			},
+			"run_as_password": schema.StringAttribute{
+				Description: "Password of run_as_username. It is sent on the command's stdin, never in a script or command line.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"bastion_host": schema.StringAttribute{
+				Description: "SSH jump host to tunnel every WinRM connection through, for hosts in private networks. " +
+					"The bastion must allow TCP forwarding to host:port.",
+				Optional: true,
+			},
+			"bastion_port": schema.Int64Attribute{
+				Description: "SSH port of the bastion. Default: 22.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"bastion_username": schema.StringAttribute{
+				Description: "SSH username on the bastion. Required with bastion_host.",
+				Optional:    true,
+			},
+			"bastion_password": schema.StringAttribute{
+				Description: "SSH password on the bastion. One of bastion_password and bastion_key_path is required with bastion_host.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"bastion_key_path": schema.StringAttribute{
+				Description: "Path to an SSH private key (PEM or OpenSSH format) for the bastion. Set bastion_key_passphrase when the key is encrypted.",
+				Optional:    true,
+			},
+			"bastion_key_passphrase": schema.StringAttribute{
+				Description: "Passphrase of an encrypted bastion_key_path (RSA, ECDSA or Ed25519, PEM or OpenSSH format).",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"bastion_host_key": schema.StringAttribute{
+				Description: "The bastion's public host key in authorized_keys format (e.g. \"ssh-ed25519 AAAA...\"), " +
+					"or its fingerprint as SHA256:... or legacy MD5:aa:bb:... . Several keys or fingerprints may be " +
+					"given one per line; the bastion must match one of them. Required with bastion_host unless " +
+					"bastion_insecure_ignore_host_key is true. The Windows host itself is still verified by TLS when use_https is true.",
+				Optional: true,
+			},
+			"bastion_insecure_ignore_host_key": schema.BoolAttribute{
+				Description: "Accept any SSH host key from the bastion instead of pinning it with bastion_host_key " +
+					"(default: false). Leaves the tunnel open to interception; a warning is emitted on every configure.",
+				Optional: true,
+			},
+			"bastion_keepalive_interval": schema.Int64Attribute{
+				Description: "Seconds between SSH keepalive requests on the bastion session, so firewalls and NAT " +
+					"gateways do not drop it while idle. After 3 unanswered keepalives the session is closed and " +
+					"re-opened on the next connection. 0 disables keepalives. Default: 30.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 3600),
+				},
+			},
+			"bastion_ciphers": schema.ListAttribute{
+				Description: "SSH ciphers offered to the bastion, in preference order (e.g. [\"aes256-ctr\", \"aes128-cbc\"]). " +
+					"Replaces the built-in list, for bastions whose SSH server accepts none of the defaults. " +
+					"Legacy algorithms such as aes128-cbc are only offered when listed. Default: unset (built-in list).",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"bastion_kex_algorithms": schema.ListAttribute{
+				Description: "SSH key exchange algorithms offered to the bastion, in preference order " +
+					"(e.g. [\"curve25519-sha256\", \"diffie-hellman-group1-sha1\"]). Replaces the built-in list. Default: unset.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"bastion_macs": schema.ListAttribute{
+				Description: "SSH MAC algorithms offered to the bastion, in preference order " +
+					"(e.g. [\"hmac-sha2-256\", \"hmac-sha1\"]). Replaces the built-in list. Default: unset.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"bastion_host_key_algorithms": schema.ListAttribute{
+				Description: "SSH host key algorithms accepted from the bastion, in preference order " +
+					"(e.g. [\"ssh-ed25519\"]). A bastion with several host keys presents the key of the first " +
+					"algorithm it also supports, so set this to the type of the key pinned in bastion_host_key; " +
+					"otherwise ECDSA and RSA keys are preferred over Ed25519. Default: unset (built-in list).",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
 		},
 	}
 }
@@ -140,6 +336,14 @@ func (p *windowsProvider) Configure(ctx context.Context, req provider.ConfigureR
 		UseHTTPS: data.UseHTTPS.ValueBool(),
 		Insecure: data.Insecure.ValueBool(),
 		AuthType: data.AuthType.ValueString(),
+
+		PowerShellPath: data.PowerShellPath.ValueString(),
+
+		ReadBatching: data.EnableReadBatching.ValueBool(),
+
+		DisableEncodedCommand: !data.UseEncodedCommand.IsNull() && !data.UseEncodedCommand.ValueBool(),
+
+		Observer: commandLogObserver{},
 	}
 
 	winclient.ResolveFromEnv(&cfg)
@@ -172,31 +376,135 @@ func (p *windowsProvider) Configure(ctx context.Context, req provider.ConfigureR
 	}
 	cfg.Timeout = d
 
+	if v := data.DefaultCommandTimeout.ValueString(); v != "" {
+		dct, err := time.ParseDuration(v)
+		if err != nil || dct <= 0 {
+			resp.Diagnostics.AddAttributeError(pathAttr("default_command_timeout"), "Invalid default_command_timeout",
+				fmt.Sprintf("Expected a positive Go duration string such as 45m or 1h, got %q.", v))
+			return
+		}
+		cfg.DefaultCommandTimeout = dct
+	}
+
+	cfg.Bastion = bastionConfig(ctx, data, &resp.Diagnostics)
+	cfg.RunAs = runAsConfig(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	client, err := winclient.New(cfg)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to create WinRM client", err.Error())
 		return
 	}
 
+	if !data.PowerShellPath.IsNull() && !checkPowerShellPath(ctx, client, &resp.Diagnostics) {
+		return
+	}
+
+	if data.RequireAdmin.IsNull() || data.RequireAdmin.ValueBool() {
+		checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		res, err := checkAdministrator(checkCtx, client)
+		cancel()
+		switch {
+		case winclient.TransportFailureKind(err) == winclient.FailureAuth:
+			// Rejected credentials never recover within this run; every
+			// resource would fail the same way (see winclient/health.go).
+			resp.Diagnostics.AddError("WinRM authentication failed",
+				fmt.Sprintf("%s rejected the credentials for %q or the TLS handshake failed: %s\n\n"+
+					"Check username, password, auth_type, use_https and insecure.",
+					cfg.Host, cfg.Username, err))
+			return
+		case winclient.TransportFailureKind(err) == winclient.FailureDial:
+			resp.Diagnostics.AddWarning("Administrator pre-flight check skipped: host unreachable",
+				fmt.Sprintf("Could not connect to %s:%d to verify that %q is an administrator: %s. "+
+					"Resources will retry the connection with back-off.",
+					cfg.Host, client.Config().Port, cfg.Username, err))
+		case err != nil:
+			resp.Diagnostics.AddWarning("Administrator pre-flight check skipped",
+				fmt.Sprintf("Could not verify that %q is an administrator on %s: %s. "+
+					"Resources will report their own errors if the account lacks elevation.",
+					cfg.Username, cfg.Host, err))
+		case !res.IsAdmin:
+			resp.Diagnostics.AddAttributeError(pathAttr("require_admin"), "WinRM account is not an administrator",
+				fmt.Sprintf("The WinRM session on %s runs as %q without an elevated BUILTIN\\Administrators token. "+
+					"Nearly every windows_* resource requires elevation.\n\n"+
+					"Use an account in the local Administrators group. For a local account other than the built-in "+
+					"Administrator, remote UAC filters the token unless "+
+					"HKLM\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Policies\\System\\LocalAccountTokenFilterPolicy = 1. "+
+					"Set require_admin = false to skip this check for read-only use.",
+					cfg.Host, res.User))
+			return
+		}
+	}
+
+	registerClient(p, client)
 	resp.ResourceData = client
 	resp.DataSourceData = client
 }
 
+// checkPowerShellPath runs the version probe through the configured
+// powershell_path. It returns false, after adding an error, when the
+// executable does not respond or the credentials are rejected; an unreachable
+// host only produces a warning, as for require_admin.
+func checkPowerShellPath(ctx context.Context, client *winclient.Client, diags *diag.Diagnostics) bool {
+	cfg := client.Config()
+	checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	info, err := probePowerShell(checkCtx, client)
+	cancel()
+	switch {
+	case err == nil:
+		tflog.Info(ctx, "PowerShell executable verified", map[string]interface{}{
+			"powershell_path": cfg.PowerShellPath, "edition": info.Edition, "version": info.Version,
+		})
+		return true
+	case winclient.TransportFailureKind(err) == winclient.FailureAuth:
+		diags.AddError("WinRM authentication failed",
+			fmt.Sprintf("%s rejected the credentials for %q or the TLS handshake failed: %s\n\n"+
+				"Check username, password, auth_type, use_https and insecure.",
+				cfg.Host, cfg.Username, err))
+		return false
+	case winclient.TransportFailureKind(err) == winclient.FailureDial:
+		diags.AddWarning("PowerShell executable check skipped: host unreachable",
+			fmt.Sprintf("Could not connect to %s:%d to verify powershell_path %q: %s.",
+				cfg.Host, cfg.Port, cfg.PowerShellPath, err))
+		return true
+	default:
+		diags.AddAttributeError(pathAttr("powershell_path"), "PowerShell executable did not respond",
+			fmt.Sprintf("Running a version probe through %q on %s failed: %s\n\n"+
+				"Set powershell_path to an executable on the host's PATH (powershell.exe, pwsh.exe) or to its full path.",
+				cfg.PowerShellPath, cfg.Host, err))
+		return false
+	}
+}
+
 // Resources returns the set of resources implemented by this provider.
 // The list is empty at bootstrap and filled in by follow-up KDust tasks.
 func (p *windowsProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewWindowsAutologonResource,
+		NewWindowsCertificateResource,
 		NewWindowsEnvironmentVariableResource,
 		NewWindowsFeatureResource,
 		NewWindowsFirewallRuleResource,
+		NewWindowsGroupPolicyRefreshResource,
 		NewWindowsHostnameResource,
 		NewWindowsLegacyPackageResource,
 		NewWindowsLocalGroupResource,
 		NewWindowsLocalGroupMemberResource,
+		NewWindowsLocalSecurityPolicyResource,
 		NewWindowsLocalUserResource,
+		NewWindowsNetworkAdapterIPResource,
+		NewWindowsOptionalFeatureResource,
+		NewWindowsPagefileResource,
+		NewWindowsPowerShellScriptResource,
+		NewWindowsRebootResource,
+		NewWindowsRegistryKeyResource,
 		NewWindowsRegistryValueResource,
 		NewWindowsScheduledTaskResource,
 		NewWindowsServiceResource,
+		NewWindowsServiceStateResource,
+		NewWindowsWaitForServiceResource,
 		NewWindowsWingetPackageResource,
 	}
 }
@@ -204,16 +512,144 @@ func (p *windowsProvider) Resources(_ context.Context) []func() resource.Resourc
 // DataSources returns the set of data sources implemented by this provider.
 func (p *windowsProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewWindowsAccountTranslateDataSource,
+		NewWindowsConnectionStatsDataSource,
 		NewWindowsEnvironmentVariableDataSource,
 		NewWindowsFeatureDataSource,
 		NewWindowsFirewallRuleDataSource,
 		NewWindowsHostnameDataSource,
+		NewWindowsHotfixDataSource,
+		NewWindowsInstalledFeaturesDataSource,
 		NewWindowsLocalGroupDataSource,
 		NewWindowsLocalGroupMemberDataSource,
+		NewWindowsLocalGroupMembersDataSource,
 		NewWindowsLocalUserDataSource,
+		NewWindowsLocalUsersDataSource,
+		NewWindowsLoggedOnUsersDataSource,
+		NewWindowsPendingRebootDataSource,
 		NewWindowsRegistryValueDataSource,
 		NewWindowsScheduledTaskDataSource,
 		NewWindowsServiceDataSource,
+		NewWindowsServicesDataSource,
+		NewWindowsSystemInfoDataSource,
 		NewWindowsWingetPackageDataSource,
 	}
 }
+
+// EphemeralResources returns the set of ephemeral resources implemented by
+// this provider. They never touch the remote host and need no provider data.
+func (p *windowsProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewWindowsEphemeralPasswordResource,
+	}
+}
+
+// runAsConfig builds the RunAs account, or returns nil when neither
+// run_as_username nor run_as_password is set. One without the other, or a
+// value unknown at plan time, is an error.
+func runAsConfig(data providerModel, diags *diag.Diagnostics) *winclient.RunAsConfig {
+	for name, v := range map[string]types.String{"run_as_username": data.RunAsUsername, "run_as_password": data.RunAsPassword} {
+		if v.IsUnknown() {
+			diags.AddAttributeError(pathAttr(name), "Unknown "+name,
+				"The provider cannot create the WinRM client because "+name+" is unknown at plan time.")
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+	user, pass := data.RunAsUsername.ValueString(), data.RunAsPassword.ValueString()
+	switch {
+	case user == "" && pass == "":
+		return nil
+	case user == "":
+		diags.AddAttributeError(pathAttr("run_as_username"), "Missing run_as_username",
+			"run_as_password is set but run_as_username is not.")
+		return nil
+	case pass == "":
+		diags.AddAttributeError(pathAttr("run_as_password"), "Missing run_as_password",
+			"run_as_username is set but run_as_password is not.")
+		return nil
+	}
+	return &winclient.RunAsConfig{Username: user, Password: pass}
+}
+
+// bastionConfig builds the SSH bastion settings, or returns nil when
+// bastion_host is unset. bastion_* attributes without bastion_host are an
+// error rather than silently ignored.
+func bastionConfig(ctx context.Context, data providerModel, diags *diag.Diagnostics) *winclient.BastionConfig {
+	if data.BastionHost.ValueString() == "" {
+		for name, v := range map[string]attr.Value{
+			"bastion_port":                     data.BastionPort,
+			"bastion_username":                 data.BastionUsername,
+			"bastion_password":                 data.BastionPassword,
+			"bastion_key_path":                 data.BastionKeyPath,
+			"bastion_key_passphrase":           data.BastionKeyPassphrase,
+			"bastion_host_key":                 data.BastionHostKey,
+			"bastion_insecure_ignore_host_key": data.BastionInsecureIgnoreHostKey,
+			"bastion_keepalive_interval":       data.BastionKeepaliveInterval,
+			"bastion_ciphers":                  data.BastionCiphers,
+			"bastion_kex_algorithms":           data.BastionKexAlgorithms,
+			"bastion_macs":                     data.BastionMACs,
+			"bastion_host_key_algorithms":      data.BastionHostKeyAlgorithms,
+		} {
+			if !v.IsNull() {
+				diags.AddAttributeError(pathAttr(name), "Missing bastion_host",
+					fmt.Sprintf("%s is set but bastion_host is not. Set bastion_host to tunnel WinRM through an SSH jump host.", name))
+			}
+		}
+		return nil
+	}
+	if !data.BastionKeyPassphrase.IsNull() && data.BastionKeyPath.ValueString() == "" {
+		diags.AddAttributeError(pathAttr("bastion_key_passphrase"), "Missing bastion_key_path",
+			"bastion_key_passphrase is set but bastion_key_path is not. Set bastion_key_path to the encrypted key it unlocks.")
+	}
+	hostKeyAlgorithms, d := stringsFromList(ctx, data.BastionHostKeyAlgorithms)
+	diags.Append(d...)
+	hostKeySet, insecureHostKey := data.BastionHostKey.ValueString() != "", data.BastionInsecureIgnoreHostKey.ValueBool()
+	switch {
+	case hostKeySet && insecureHostKey:
+		diags.AddAttributeError(pathAttr("bastion_insecure_ignore_host_key"), "Conflicting bastion host key settings",
+			"bastion_insecure_ignore_host_key is true but bastion_host_key is set. Remove one of them.")
+	case insecureHostKey:
+		diags.AddAttributeWarning(pathAttr("bastion_insecure_ignore_host_key"), "Bastion host key not verified",
+			fmt.Sprintf("bastion_insecure_ignore_host_key is true, so the SSH host key of %s is accepted without verification. "+
+				"Set bastion_host_key to the bastion's public host key (ssh-keyscan output) to guard against interception, "+
+				"after checking its fingerprint against the bastion itself (ssh-keygen -lf on its host key file).",
+				data.BastionHost.ValueString()))
+	case !hostKeySet:
+		diags.AddAttributeError(pathAttr("bastion_host_key"), "Missing bastion_host_key",
+			fmt.Sprintf("bastion_host is set but bastion_host_key is not, so the SSH host key of %s cannot be verified. "+
+				"Set bastion_host_key to the bastion's public host key (ssh-keyscan output) after checking its fingerprint "+
+				"against the bastion itself (ssh-keygen -lf on its host key file), or set bastion_insecure_ignore_host_key = true "+
+				"to accept any host key.", data.BastionHost.ValueString()))
+	}
+	// Null keeps the winclient default; an explicit 0 disables keepalives.
+	var keepalive time.Duration
+	if !data.BastionKeepaliveInterval.IsNull() {
+		keepalive = time.Duration(data.BastionKeepaliveInterval.ValueInt64()) * time.Second
+		if keepalive == 0 {
+			keepalive = -1
+		}
+	}
+	ciphers, d := stringsFromList(ctx, data.BastionCiphers)
+	diags.Append(d...)
+	kex, d := stringsFromList(ctx, data.BastionKexAlgorithms)
+	diags.Append(d...)
+	macs, d := stringsFromList(ctx, data.BastionMACs)
+	diags.Append(d...)
+	return &winclient.BastionConfig{
+		Host:                  data.BastionHost.ValueString(),
+		Port:                  int(data.BastionPort.ValueInt64()),
+		Username:              data.BastionUsername.ValueString(),
+		Password:              data.BastionPassword.ValueString(),
+		KeyPath:               data.BastionKeyPath.ValueString(),
+		KeyPassphrase:         data.BastionKeyPassphrase.ValueString(),
+		HostKey:               data.BastionHostKey.ValueString(),
+		InsecureIgnoreHostKey: insecureHostKey,
+		KeepaliveInterval:     keepalive,
+		Ciphers:               ciphers,
+		KeyExchanges:          kex,
+		MACs:                  macs,
+		HostKeyAlgorithms:     hostKeyAlgorithms,
+	}
+}
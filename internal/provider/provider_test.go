@@ -9,12 +9,34 @@ package provider
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
 )
 
 func TestProvider_New(t *testing.T) {
@@ -44,20 +66,47 @@ func TestProvider_Schema(t *testing.T) {
 	p := &windowsProvider{}
 	resp := &provider.SchemaResponse{}
 	p.Schema(context.Background(), provider.SchemaRequest{}, resp)
-	for _, k := range []string{"host", "port", "username", "password", "use_https", "insecure", "auth_type", "timeout"} {
+	for _, k := range []string{"host", "port", "username", "password", "use_https", "insecure", "auth_type", "timeout", "default_command_timeout", "require_admin", "enable_read_batching", "use_encoded_command"} {
 		if _, ok := resp.Schema.Attributes[k]; !ok {
 			t.Errorf("provider schema missing %q", k)
 		}
 	}
 }
 
+// TestProvider_Schema_PortRange checks that port rejects values outside the
+// TCP range; an omitted port keeps the scheme default (5985/5986).
+func TestProvider_Schema_PortRange(t *testing.T) {
+	p := &windowsProvider{}
+	resp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, resp)
+	attr, ok := resp.Schema.Attributes["port"].(schema.Int64Attribute)
+	if !ok {
+		t.Fatalf("port is %T, want schema.Int64Attribute", resp.Schema.Attributes["port"])
+	}
+	for port, wantErr := range map[int64]bool{0: true, 22: false, 5986: false, 65535: false, 65536: true} {
+		vresp := &validator.Int64Response{}
+		for _, v := range attr.Validators {
+			v.ValidateInt64(context.Background(), validator.Int64Request{
+				Path:        path.Root("port"),
+				ConfigValue: types.Int64Value(port),
+			}, vresp)
+		}
+		if got := vresp.Diagnostics.HasError(); got != wantErr {
+			t.Errorf("port %d: error = %v, want %v", port, got, wantErr)
+		}
+	}
+}
+
 func TestProvider_ResourcesAndDataSources(t *testing.T) {
 	p := &windowsProvider{}
-	if got := len(p.Resources(context.Background())); got != 12 {
-		t.Errorf("Resources len = %d, want 12 (service + feature + hostname + local_group + local_group_member + local_user + registry_value + environment_variable + scheduled_task + firewall_rule + winget_package + legacy_package)", got)
+	if got := len(p.Resources(context.Background())); got != 24 {
+		t.Errorf("Resources len = %d, want 24 (service + service_state + wait_for_service + feature + optional_feature + hostname + local_group + local_group_member + local_user + network_adapter_ip + pagefile + powershell_script + registry_key + registry_value + environment_variable + scheduled_task + firewall_rule + winget_package + legacy_package + autologon + certificate + reboot + local_security_policy + group_policy_refresh)", got)
+	}
+	if got := len(p.DataSources(context.Background())); got != 21 {
+		t.Errorf("DataSources len = %d, want 21 (account_translate + connection_stats + feature + installed_features + hostname + hotfix + local_group + local_group_member + local_group_members + local_user + local_users + logged_on_users + pending_reboot + registry_value + service + services + system_info + environment_variable + scheduled_task + firewall_rule + winget_package)", got)
 	}
-	if got := len(p.DataSources(context.Background())); got != 11 {
-		t.Errorf("DataSources len = %d, want 11 (feature + hostname + local_group + local_group_member + local_user + registry_value + service + environment_variable + scheduled_task + firewall_rule + winget_package)", got)
+	if got := len(p.EphemeralResources(context.Background())); got != 1 {
+		t.Errorf("EphemeralResources len = %d, want 1 (ephemeral_password)", got)
 	}
 }
 
@@ -78,6 +127,33 @@ func providerConfigObjectType() tftypes.Object {
 		"insecure":  tftypes.Bool,
 		"auth_type": tftypes.String,
 		"timeout":   tftypes.String,
+
+		"default_command_timeout": tftypes.String,
+
+		"require_admin": tftypes.Bool,
+
+		"powershell_path": tftypes.String,
+
+		"enable_read_batching": tftypes.Bool,
+
+		"use_encoded_command": tftypes.Bool,
+
+		"run_as_username": tftypes.String,
+		"run_as_password": tftypes.String,
+
+		"bastion_host":                     tftypes.String,
+		"bastion_port":                     tftypes.Number,
+		"bastion_username":                 tftypes.String,
+		"bastion_password":                 tftypes.String,
+		"bastion_key_path":                 tftypes.String,
+		"bastion_key_passphrase":           tftypes.String,
+		"bastion_host_key":                 tftypes.String,
+		"bastion_insecure_ignore_host_key": tftypes.Bool,
+		"bastion_keepalive_interval":       tftypes.Number,
+		"bastion_ciphers":                  tftypes.List{ElementType: tftypes.String},
+		"bastion_kex_algorithms":           tftypes.List{ElementType: tftypes.String},
+		"bastion_macs":                     tftypes.List{ElementType: tftypes.String},
+		"bastion_host_key_algorithms":      tftypes.List{ElementType: tftypes.String},
 	}}
 }
 
@@ -97,9 +173,298 @@ func providerCfgValue(host, user, pass, timeout *string) tftypes.Value {
 		"insecure":  tftypes.NewValue(tftypes.Bool, nil),
 		"auth_type": tftypes.NewValue(tftypes.String, nil),
 		"timeout":   s(timeout),
+
+		"default_command_timeout": tftypes.NewValue(tftypes.String, nil),
+
+		"require_admin": tftypes.NewValue(tftypes.Bool, nil),
+
+		"powershell_path": tftypes.NewValue(tftypes.String, nil),
+
+		"enable_read_batching": tftypes.NewValue(tftypes.Bool, nil),
+
+		"use_encoded_command": tftypes.NewValue(tftypes.Bool, nil),
+
+		"run_as_username": tftypes.NewValue(tftypes.String, nil),
+		"run_as_password": tftypes.NewValue(tftypes.String, nil),
+
+		"bastion_host":                     tftypes.NewValue(tftypes.String, nil),
+		"bastion_port":                     tftypes.NewValue(tftypes.Number, nil),
+		"bastion_username":                 tftypes.NewValue(tftypes.String, nil),
+		"bastion_password":                 tftypes.NewValue(tftypes.String, nil),
+		"bastion_key_path":                 tftypes.NewValue(tftypes.String, nil),
+		"bastion_key_passphrase":           tftypes.NewValue(tftypes.String, nil),
+		"bastion_host_key":                 tftypes.NewValue(tftypes.String, nil),
+		"bastion_insecure_ignore_host_key": tftypes.NewValue(tftypes.Bool, nil),
+		"bastion_keepalive_interval":       tftypes.NewValue(tftypes.Number, nil),
+		"bastion_ciphers":                  tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"bastion_kex_algorithms":           tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"bastion_macs":                     tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"bastion_host_key_algorithms":      tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
 	})
 }
 
+// stubCheckAdministrator replaces the require_admin pre-flight for the
+// duration of the test and returns a pointer to the call counter.
+func stubCheckAdministrator(t *testing.T, res *winclient.AdminCheckResult, err error) *int {
+	t.Helper()
+	calls := 0
+	prev := checkAdministrator
+	checkAdministrator = func(_ context.Context, _ *winclient.Client) (*winclient.AdminCheckResult, error) {
+		calls++
+		return res, err
+	}
+	t.Cleanup(func() { checkAdministrator = prev })
+	return &calls
+}
+
+// configureWithRequireAdmin runs Configure with a complete config and the
+// given require_admin value (nil = unset).
+func configureWithRequireAdmin(t *testing.T, requireAdmin *bool) *provider.ConfigureResponse {
+	t.Helper()
+	os.Unsetenv("WINDOWS_HOST")
+	os.Unsetenv("WINDOWS_USERNAME")
+	os.Unsetenv("WINDOWS_PASSWORD")
+
+	p := &windowsProvider{}
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+
+	h, u, pw, to := "10.0.0.1", "admin", "secret", "15s"
+	var vals map[string]tftypes.Value
+	if err := providerCfgValue(&h, &u, &pw, &to).As(&vals); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	if requireAdmin != nil {
+		vals["require_admin"] = tftypes.NewValue(tftypes.Bool, *requireAdmin)
+	}
+	cfg := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(providerConfigObjectType(), vals)}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(context.Background(), provider.ConfigureRequest{Config: cfg}, resp)
+	return resp
+}
+
+func TestProvider_Configure_RequireAdmin_NotAdmin(t *testing.T) {
+	calls := stubCheckAdministrator(t, &winclient.AdminCheckResult{User: `WIN01\operator`, IsAdmin: false}, nil)
+	resp := configureWithRequireAdmin(t, nil)
+	if *calls != 1 {
+		t.Errorf("pre-flight calls = %d, want 1 (require_admin defaults to true)", *calls)
+	}
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a non-admin account")
+	}
+	if d := resp.Diagnostics[0].Detail(); !strings.Contains(d, `WIN01\\operator`) || !strings.Contains(d, "require_admin = false") {
+		t.Errorf("detail should name the account and the opt-out, got %q", d)
+	}
+	if resp.ResourceData != nil {
+		t.Error("ResourceData must not be set when the pre-flight fails")
+	}
+}
+
+func TestProvider_Configure_RequireAdmin_CheckErrorWarns(t *testing.T) {
+	stubCheckAdministrator(t, nil, errors.New("dial tcp: connection refused"))
+	resp := configureWithRequireAdmin(t, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("an unreachable host must not fail Configure: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected one warning, got %v", resp.Diagnostics)
+	}
+	if resp.ResourceData == nil {
+		t.Error("ResourceData should still be set")
+	}
+}
+
+func TestProvider_Configure_RequireAdmin_AuthFailureFails(t *testing.T) {
+	stubCheckAdministrator(t, nil, fmt.Errorf("winclient: administrator check: %w", &winclient.TransportError{
+		Kind: winclient.FailureAuth, Err: errors.New("http response error: 401 - invalid content type"),
+	}))
+	resp := configureWithRequireAdmin(t, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("rejected credentials must fail Configure")
+	}
+	if s := resp.Diagnostics[0].Summary(); s != "WinRM authentication failed" {
+		t.Errorf("summary = %q", s)
+	}
+	if resp.ResourceData != nil {
+		t.Error("ResourceData must not be set after an authentication failure")
+	}
+}
+
+func TestProvider_Configure_RequireAdmin_DialFailureWarns(t *testing.T) {
+	stubCheckAdministrator(t, nil, &winclient.TransportError{
+		Kind: winclient.FailureDial, Err: errors.New("dial tcp 10.0.0.1:5985: connect: connection refused"),
+	})
+	resp := configureWithRequireAdmin(t, nil)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected one warning, got %v", resp.Diagnostics)
+	}
+	if s := resp.Diagnostics[0].Summary(); !strings.Contains(s, "unreachable") {
+		t.Errorf("summary should say the host is unreachable, got %q", s)
+	}
+	if d := resp.Diagnostics[0].Detail(); !strings.Contains(d, "10.0.0.1:5985") {
+		t.Errorf("detail should name host:port, got %q", d)
+	}
+}
+
+func TestProvider_Configure_RequireAdmin_Disabled(t *testing.T) {
+	calls := stubCheckAdministrator(t, &winclient.AdminCheckResult{IsAdmin: false}, nil)
+	off := false
+	resp := configureWithRequireAdmin(t, &off)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	if *calls != 0 {
+		t.Errorf("pre-flight must not run when require_admin = false (calls = %d)", *calls)
+	}
+}
+
+// stubProbePowerShell replaces the powershell_path probe for the duration of
+// the test and returns a pointer to the call counter.
+func stubProbePowerShell(t *testing.T, res *winclient.PowerShellInfo, err error) *int {
+	t.Helper()
+	calls := 0
+	prev := probePowerShell
+	probePowerShell = func(_ context.Context, _ *winclient.Client) (*winclient.PowerShellInfo, error) {
+		calls++
+		return res, err
+	}
+	t.Cleanup(func() { probePowerShell = prev })
+	return &calls
+}
+
+// configureWithPowerShellPath runs Configure with a complete config,
+// require_admin = false and the given powershell_path (nil = unset).
+func configureWithPowerShellPath(t *testing.T, psPath *string) *provider.ConfigureResponse {
+	t.Helper()
+	os.Unsetenv("WINDOWS_HOST")
+	os.Unsetenv("WINDOWS_USERNAME")
+	os.Unsetenv("WINDOWS_PASSWORD")
+
+	p := &windowsProvider{}
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+
+	h, u, pw, to := "10.0.0.1", "admin", "secret", "15s"
+	var vals map[string]tftypes.Value
+	if err := providerCfgValue(&h, &u, &pw, &to).As(&vals); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	vals["require_admin"] = tftypes.NewValue(tftypes.Bool, false)
+	if psPath != nil {
+		vals["powershell_path"] = tftypes.NewValue(tftypes.String, *psPath)
+	}
+	cfg := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(providerConfigObjectType(), vals)}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(context.Background(), provider.ConfigureRequest{Config: cfg}, resp)
+	return resp
+}
+
+func TestProvider_Configure_PowerShellPath(t *testing.T) {
+	calls := stubProbePowerShell(t, &winclient.PowerShellInfo{Edition: "Core", Version: "7.4.6"}, nil)
+	pwsh := "pwsh.exe"
+	resp := configureWithPowerShellPath(t, &pwsh)
+	if resp.Diagnostics.HasError() || *calls != 1 {
+		t.Fatalf("diags = %v, probe calls = %d", resp.Diagnostics, *calls)
+	}
+	c, ok := resp.ResourceData.(*winclient.Client)
+	if !ok || c.Config().PowerShellPath != "pwsh.exe" {
+		t.Errorf("client PowerShellPath not set: %#v", resp.ResourceData)
+	}
+
+	resp = configureWithPowerShellPath(t, nil)
+	if resp.Diagnostics.HasError() || *calls != 1 {
+		t.Errorf("the default executable must not be probed (calls = %d): %v", *calls, resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_EnableReadBatching(t *testing.T) {
+	for _, set := range []bool{false, true} {
+		os.Unsetenv("WINDOWS_HOST")
+		os.Unsetenv("WINDOWS_USERNAME")
+		os.Unsetenv("WINDOWS_PASSWORD")
+		p := &windowsProvider{}
+		schemaResp := &provider.SchemaResponse{}
+		p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+
+		h, u, pw, to := "10.0.0.1", "admin", "secret", "15s"
+		var vals map[string]tftypes.Value
+		if err := providerCfgValue(&h, &u, &pw, &to).As(&vals); err != nil {
+			t.Fatalf("As: %v", err)
+		}
+		vals["require_admin"] = tftypes.NewValue(tftypes.Bool, false)
+		if set {
+			vals["enable_read_batching"] = tftypes.NewValue(tftypes.Bool, true)
+		}
+		resp := &provider.ConfigureResponse{}
+		p.Configure(context.Background(), provider.ConfigureRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(providerConfigObjectType(), vals)},
+		}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		c := resp.ResourceData.(*winclient.Client)
+		if c.Config().ReadBatching != set {
+			t.Errorf("enable_read_batching set = %v: ReadBatching = %v", set, c.Config().ReadBatching)
+		}
+	}
+}
+
+func TestProvider_Configure_UseEncodedCommand(t *testing.T) {
+	yes, no := true, false
+	cases := []struct {
+		val         *bool
+		wantDisable bool
+	}{{nil, false}, {&yes, false}, {&no, true}}
+	for i, tc := range cases {
+		os.Unsetenv("WINDOWS_HOST")
+		os.Unsetenv("WINDOWS_USERNAME")
+		os.Unsetenv("WINDOWS_PASSWORD")
+		p := &windowsProvider{}
+		schemaResp := &provider.SchemaResponse{}
+		p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+
+		h, u, pw, to := "10.0.0.1", "admin", "secret", "15s"
+		var vals map[string]tftypes.Value
+		if err := providerCfgValue(&h, &u, &pw, &to).As(&vals); err != nil {
+			t.Fatalf("As: %v", err)
+		}
+		vals["require_admin"] = tftypes.NewValue(tftypes.Bool, false)
+		if tc.val != nil {
+			vals["use_encoded_command"] = tftypes.NewValue(tftypes.Bool, *tc.val)
+		}
+		resp := &provider.ConfigureResponse{}
+		p.Configure(context.Background(), provider.ConfigureRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(providerConfigObjectType(), vals)},
+		}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		c := resp.ResourceData.(*winclient.Client)
+		if c.Config().DisableEncodedCommand != tc.wantDisable {
+			t.Errorf("use_encoded_command case %d: DisableEncodedCommand = %v, want %v", i, c.Config().DisableEncodedCommand, tc.wantDisable)
+		}
+	}
+}
+
+func TestProvider_Configure_PowerShellPath_NotResponding(t *testing.T) {
+	stubProbePowerShell(t, nil, fmt.Errorf("winclient: powershell version probe: %w (stderr: 'pwsh.exe' is not recognized)",
+		&winclient.TransportError{Kind: winclient.FailureCommand, Err: errors.New("winclient: powershell exited with code 1")}))
+	pwsh := "pwsh.exe"
+	resp := configureWithPowerShellPath(t, &pwsh)
+	if !resp.Diagnostics.HasError() || resp.ResourceData != nil {
+		t.Fatalf("a missing executable must fail Configure: %v", resp.Diagnostics)
+	}
+	if d := resp.Diagnostics[0].Detail(); !strings.Contains(d, "is not recognized") {
+		t.Errorf("detail should carry the probe error, got %q", d)
+	}
+
+	stubProbePowerShell(t, nil, &winclient.TransportError{Kind: winclient.FailureDial, Err: errors.New("connection refused")})
+	resp = configureWithPowerShellPath(t, &pwsh)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("an unreachable host must only warn: %v", resp.Diagnostics)
+	}
+}
+
 // TestProvider_Configure_HappyPath covers the full success path: config has
 // host/username/password/timeout populated; the response gets a non-nil
 // ResourceData (our *winclient.Client).
@@ -108,6 +473,7 @@ func TestProvider_Configure_HappyPath(t *testing.T) {
 	os.Unsetenv("WINDOWS_HOST")
 	os.Unsetenv("WINDOWS_USERNAME")
 	os.Unsetenv("WINDOWS_PASSWORD")
+	stubCheckAdministrator(t, &winclient.AdminCheckResult{User: `WIN01\admin`, IsAdmin: true}, nil)
 
 	p := &windowsProvider{}
 	schemaResp := &provider.SchemaResponse{}
@@ -124,6 +490,9 @@ func TestProvider_Configure_HappyPath(t *testing.T) {
 	if resp.ResourceData == nil || resp.DataSourceData == nil {
 		t.Error("Configure should populate ResourceData and DataSourceData")
 	}
+	if c, ok := resp.ResourceData.(*winclient.Client); !ok || c.Config().Observer != (commandLogObserver{}) {
+		t.Error("Configure should log every WinRM run through commandLogObserver")
+	}
 }
 
 func TestProvider_Configure_MissingCredentials(t *testing.T) {
@@ -160,3 +529,456 @@ func TestProvider_Configure_InvalidTimeout(t *testing.T) {
 		t.Fatal("expected error diag for invalid timeout")
 	}
 }
+
+// TestShutdown_ClosesConfiguredClients checks that Shutdown drains the
+// clients registered by Configure and that they refuse new runs afterwards.
+func TestShutdown_ClosesConfiguredClients(t *testing.T) {
+	c, err := winclient.New(winclient.Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	registerClient(&windowsProvider{}, c)
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, _, err := c.RunPowerShell(context.Background(), "Get-Date"); !errors.Is(err, winclient.ErrClientClosed) {
+		t.Errorf("RunPowerShell after Shutdown = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestShutdown_ReConfigureRetiresClient checks that a second Configure of
+// the same provider instance replaces its registry entry and closes the
+// client it replaces.
+func TestShutdown_ReConfigureRetiresClient(t *testing.T) {
+	p := &windowsProvider{}
+	first, err := winclient.New(winclient.Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	second, err := winclient.New(winclient.Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	registerClient(p, first)
+	registerClient(p, second)
+
+	clientsMu.Lock()
+	got := clients[p]
+	clientsMu.Unlock()
+	if got != second {
+		t.Fatal("re-Configure must replace the registered client")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, _, err := first.RunPowerShell(context.Background(), "Get-Date")
+		if errors.Is(err, winclient.ErrClientClosed) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("replaced client still accepts runs: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+// TestShutdownOnDone_InFlightRunCompletes drives a run through a client built
+// by Configure and signals shutdown while the run is in flight: the run must
+// complete within the grace window rather than be cancelled, and later runs
+// must be refused.
+func TestShutdownOnDone_InFlightRunCompletes(t *testing.T) {
+	received := make(chan struct{})
+	var once sync.Once
+	var cancelled atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		once.Do(func() { close(received) })
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			cancelled.Store(true)
+			return
+		}
+		http.Error(w, "done", http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	portNum, _ := strconv.Atoi(port)
+
+	resp := configureWithValues(t, map[string]tftypes.Value{
+		"host":      tftypes.NewValue(tftypes.String, host),
+		"port":      tftypes.NewValue(tftypes.Number, portNum),
+		"auth_type": tftypes.NewValue(tftypes.String, "basic"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*winclient.Client)
+
+	ctx, stop := context.WithCancel(context.Background())
+	wait := ShutdownOnDone(ctx, 5*time.Second)
+	runErr := make(chan error, 1)
+	go func() {
+		_, _, err := client.RunPowerShell(context.Background(), "Get-Date")
+		runErr <- err
+	}()
+	<-received
+	stop()
+
+	if err := wait(); err != nil {
+		t.Fatalf("Shutdown within the grace window: %v", err)
+	}
+	select {
+	case err := <-runErr:
+		if errors.Is(err, context.Canceled) || errors.Is(err, winclient.ErrClientClosed) {
+			t.Errorf("in-flight run was cut short: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown returned before the in-flight run")
+	}
+	if cancelled.Load() {
+		t.Error("the in-flight request was cancelled")
+	}
+	if _, _, err := client.RunPowerShell(context.Background(), "Get-Date"); !errors.Is(err, winclient.ErrClientClosed) {
+		t.Errorf("RunPowerShell after shutdown = %v, want ErrClientClosed", err)
+	}
+}
+
+// testBastionHostKey pins a bastion host key in Configure tests.
+const testBastionHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqD7Fx0gB5uJ2bW5m7y8Nf3f6Qk9mJ1Y3c2wq7s4Z1p"
+
+// configureWithBastion runs Configure with a complete config plus the given
+// string attributes (bastion_*, default_command_timeout); require_admin is
+// disabled.
+func configureWithBastion(t *testing.T, bastion map[string]string) *provider.ConfigureResponse {
+	t.Helper()
+	vals := make(map[string]tftypes.Value, len(bastion))
+	for k, v := range bastion {
+		vals[k] = tftypes.NewValue(tftypes.String, v)
+	}
+	return configureWithValues(t, vals)
+}
+
+// configureWithValues is configureWithBastion for attributes of any type.
+func configureWithValues(t *testing.T, overrides map[string]tftypes.Value) *provider.ConfigureResponse {
+	t.Helper()
+	os.Unsetenv("WINDOWS_HOST")
+	os.Unsetenv("WINDOWS_USERNAME")
+	os.Unsetenv("WINDOWS_PASSWORD")
+
+	p := &windowsProvider{}
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+
+	h, u, pw, to := "10.0.0.1", "admin", "secret", "15s"
+	var vals map[string]tftypes.Value
+	if err := providerCfgValue(&h, &u, &pw, &to).As(&vals); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	vals["require_admin"] = tftypes.NewValue(tftypes.Bool, false)
+	for k, v := range overrides {
+		vals[k] = v
+	}
+	cfg := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(providerConfigObjectType(), vals)}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(context.Background(), provider.ConfigureRequest{Config: cfg}, resp)
+	return resp
+}
+
+func TestProvider_Configure_Bastion(t *testing.T) {
+	resp := configureWithBastion(t, map[string]string{
+		"bastion_host":     "jump.example.com",
+		"bastion_username": "ops",
+		"bastion_password": "pw",
+		"bastion_host_key": testBastionHostKey,
+	})
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	c, ok := resp.ResourceData.(*winclient.Client)
+	if !ok {
+		t.Fatalf("ResourceData = %T", resp.ResourceData)
+	}
+	b := c.Config().Bastion
+	if b == nil || b.Host != "jump.example.com" || b.Username != "ops" || b.Port != 0 || b.HostKey != testBastionHostKey || b.InsecureIgnoreHostKey {
+		t.Errorf("bastion config = %+v", b)
+	}
+}
+
+func TestProvider_Configure_BastionHostKeyRequired(t *testing.T) {
+	// Configure must not reach out to the bastion, whatever the host key
+	// settings.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			c.Close()
+		}
+	}()
+	bastion := map[string]tftypes.Value{
+		"bastion_host":     tftypes.NewValue(tftypes.String, "127.0.0.1"),
+		"bastion_port":     tftypes.NewValue(tftypes.Number, ln.Addr().(*net.TCPAddr).Port),
+		"bastion_username": tftypes.NewValue(tftypes.String, "ops"),
+		"bastion_password": tftypes.NewValue(tftypes.String, "pw"),
+	}
+	resp := configureWithValues(t, bastion)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Missing bastion_host_key" {
+		t.Errorf("unpinned host key must be rejected: %v", resp.Diagnostics)
+	}
+
+	bastion["bastion_insecure_ignore_host_key"] = tftypes.NewValue(tftypes.Bool, true)
+	resp = configureWithValues(t, bastion)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 ||
+		resp.Diagnostics.Warnings()[0].Summary() != "Bastion host key not verified" {
+		t.Fatalf("insecure opt-in: diags = %v", resp.Diagnostics)
+	}
+	if b := resp.ResourceData.(*winclient.Client).Config().Bastion; !b.InsecureIgnoreHostKey {
+		t.Errorf("InsecureIgnoreHostKey not passed on: %+v", b)
+	}
+
+	bastion["bastion_host_key"] = tftypes.NewValue(tftypes.String, testBastionHostKey)
+	resp = configureWithValues(t, bastion)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bastion_host_key is set") {
+		t.Errorf("host key and insecure opt-in together: %v", resp.Diagnostics)
+	}
+
+	if n := accepted.Load(); n != 0 {
+		t.Errorf("Configure opened %d connection(s) to the bastion", n)
+	}
+}
+
+func TestProvider_Configure_BastionKeepalive(t *testing.T) {
+	bastion := map[string]tftypes.Value{
+		"bastion_host":     tftypes.NewValue(tftypes.String, "jump.example.com"),
+		"bastion_host_key": tftypes.NewValue(tftypes.String, testBastionHostKey),
+		"bastion_username": tftypes.NewValue(tftypes.String, "ops"),
+		"bastion_password": tftypes.NewValue(tftypes.String, "pw"),
+	}
+	for in, want := range map[int64]time.Duration{60: time.Minute, 0: -1} {
+		bastion["bastion_keepalive_interval"] = tftypes.NewValue(tftypes.Number, in)
+		resp := configureWithValues(t, bastion)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%d: unexpected diags: %v", in, resp.Diagnostics)
+		}
+		if got := resp.ResourceData.(*winclient.Client).Config().Bastion.KeepaliveInterval; got != want {
+			t.Errorf("%d: KeepaliveInterval = %s, want %s", in, got, want)
+		}
+	}
+
+	resp := configureWithValues(t, map[string]tftypes.Value{
+		"bastion_keepalive_interval": tftypes.NewValue(tftypes.Number, 30),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bastion_keepalive_interval is set but bastion_host is not") {
+		t.Errorf("keepalive without bastion_host: %v", resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_BastionAlgorithms(t *testing.T) {
+	list := func(names ...string) tftypes.Value {
+		vals := make([]tftypes.Value, len(names))
+		for i, n := range names {
+			vals[i] = tftypes.NewValue(tftypes.String, n)
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, vals)
+	}
+	bastion := map[string]tftypes.Value{
+		"bastion_host":           tftypes.NewValue(tftypes.String, "jump.example.com"),
+		"bastion_host_key":       tftypes.NewValue(tftypes.String, testBastionHostKey),
+		"bastion_username":       tftypes.NewValue(tftypes.String, "ops"),
+		"bastion_password":       tftypes.NewValue(tftypes.String, "pw"),
+		"bastion_ciphers":        list("aes256-ctr", "aes128-cbc"),
+		"bastion_kex_algorithms": list("diffie-hellman-group14-sha1"),
+		"bastion_macs":           list("hmac-sha1"),
+	}
+	resp := configureWithValues(t, bastion)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	b := resp.ResourceData.(*winclient.Client).Config().Bastion
+	if strings.Join(b.Ciphers, ",") != "aes256-ctr,aes128-cbc" ||
+		strings.Join(b.KeyExchanges, ",") != "diffie-hellman-group14-sha1" || strings.Join(b.MACs, ",") != "hmac-sha1" {
+		t.Errorf("bastion algorithms = %v / %v / %v", b.Ciphers, b.KeyExchanges, b.MACs)
+	}
+
+	bastion["bastion_ciphers"] = list("blowfish-cbc")
+	resp = configureWithValues(t, bastion)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), `unsupported bastion cipher "blowfish-cbc"`) {
+		t.Errorf("unknown cipher: %v", resp.Diagnostics)
+	}
+
+	resp = configureWithValues(t, map[string]tftypes.Value{"bastion_macs": list("hmac-sha1")})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bastion_macs is set but bastion_host is not") {
+		t.Errorf("bastion_macs without bastion_host: %v", resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_BastionKeyPassphrase(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte("s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bastion := map[string]tftypes.Value{
+		"bastion_host":           tftypes.NewValue(tftypes.String, "jump.example.com"),
+		"bastion_host_key":       tftypes.NewValue(tftypes.String, testBastionHostKey),
+		"bastion_username":       tftypes.NewValue(tftypes.String, "ops"),
+		"bastion_key_path":       tftypes.NewValue(tftypes.String, keyPath),
+		"bastion_key_passphrase": tftypes.NewValue(tftypes.String, "s3cret"),
+	}
+	resp := configureWithValues(t, bastion)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	if b := resp.ResourceData.(*winclient.Client).Config().Bastion; b.KeyPassphrase != "s3cret" {
+		t.Errorf("KeyPassphrase = %q, want s3cret", b.KeyPassphrase)
+	}
+
+	delete(bastion, "bastion_key_passphrase")
+	resp = configureWithValues(t, bastion)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "set bastion_key_passphrase") {
+		t.Errorf("encrypted key without passphrase: %v", resp.Diagnostics)
+	}
+
+	resp = configureWithValues(t, map[string]tftypes.Value{
+		"bastion_host":           tftypes.NewValue(tftypes.String, "jump.example.com"),
+		"bastion_username":       tftypes.NewValue(tftypes.String, "ops"),
+		"bastion_password":       tftypes.NewValue(tftypes.String, "pw"),
+		"bastion_key_passphrase": tftypes.NewValue(tftypes.String, "s3cret"),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bastion_key_passphrase is set but bastion_key_path is not") {
+		t.Errorf("passphrase without key path: %v", resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_BastionHostKeyAlgorithms(t *testing.T) {
+	algs := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "ssh-ed25519"),
+		tftypes.NewValue(tftypes.String, "ecdsa-sha2-nistp256"),
+	})
+	bastion := map[string]tftypes.Value{
+		"bastion_host":                tftypes.NewValue(tftypes.String, "jump.example.com"),
+		"bastion_host_key":            tftypes.NewValue(tftypes.String, testBastionHostKey),
+		"bastion_username":            tftypes.NewValue(tftypes.String, "ops"),
+		"bastion_password":            tftypes.NewValue(tftypes.String, "pw"),
+		"bastion_host_key_algorithms": algs,
+	}
+	resp := configureWithValues(t, bastion)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	b := resp.ResourceData.(*winclient.Client).Config().Bastion
+	if strings.Join(b.HostKeyAlgorithms, ",") != "ssh-ed25519,ecdsa-sha2-nistp256" {
+		t.Errorf("HostKeyAlgorithms = %v", b.HostKeyAlgorithms)
+	}
+
+	bastion["bastion_host_key_algorithms"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "ssh-ed448"),
+	})
+	resp = configureWithValues(t, bastion)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), `unsupported bastion host key algorithm "ssh-ed448"`) {
+		t.Errorf("unknown host key algorithm: %v", resp.Diagnostics)
+	}
+
+	resp = configureWithValues(t, map[string]tftypes.Value{"bastion_host_key_algorithms": algs})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bastion_host_key_algorithms is set but bastion_host is not") {
+		t.Errorf("bastion_host_key_algorithms without bastion_host: %v", resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_BastionErrors(t *testing.T) {
+	resp := configureWithBastion(t, map[string]string{"bastion_username": "ops"})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bastion_username is set but bastion_host is not") {
+		t.Errorf("bastion_* without bastion_host: %v", resp.Diagnostics)
+	}
+
+	resp = configureWithBastion(t, map[string]string{"bastion_host": "jump", "bastion_username": "ops", "bastion_host_key": testBastionHostKey})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "password or a key path") {
+		t.Errorf("bastion without credentials: %v", resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_RunAs(t *testing.T) {
+	resp := configureWithBastion(t, map[string]string{
+		"run_as_username": `CONTOSO\svc-acl`,
+		"run_as_password": "pw",
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	ra := resp.ResourceData.(*winclient.Client).Config().RunAs
+	if ra == nil || ra.Username != `CONTOSO\svc-acl` || ra.Password != "pw" {
+		t.Errorf("RunAs = %+v", ra)
+	}
+
+	if resp = configureWithBastion(t, nil); resp.ResourceData.(*winclient.Client).Config().RunAs != nil {
+		t.Error("RunAs must be nil when run_as_* is unset")
+	}
+
+	for attr, want := range map[string]string{
+		"run_as_username": "run_as_username is set but run_as_password is not",
+		"run_as_password": "run_as_password is set but run_as_username is not",
+	} {
+		resp = configureWithBastion(t, map[string]string{attr: "x"})
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), want) {
+			t.Errorf("only %s: %v", attr, resp.Diagnostics)
+		}
+	}
+
+	resp = configureWithValues(t, map[string]tftypes.Value{
+		"run_as_username": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"run_as_password": tftypes.NewValue(tftypes.String, "pw"),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Summary(), "Unknown run_as_username") {
+		t.Errorf("unknown run_as_username: %v", resp.Diagnostics)
+	}
+}
+
+func TestProvider_Configure_DefaultCommandTimeout(t *testing.T) {
+	resp := configureWithBastion(t, map[string]string{"default_command_timeout": "45m"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diags: %v", resp.Diagnostics)
+	}
+	c := resp.ResourceData.(*winclient.Client)
+	if got := c.DefaultCommandTimeout(); got != 45*time.Minute {
+		t.Errorf("DefaultCommandTimeout = %v, want 45m", got)
+	}
+
+	resp = configureWithBastion(t, nil)
+	if got := resp.ResourceData.(*winclient.Client).DefaultCommandTimeout(); got != 0 {
+		t.Errorf("unset DefaultCommandTimeout = %v, want 0", got)
+	}
+
+	for _, bad := range []string{"soon", "0s", "-5m"} {
+		resp = configureWithBastion(t, map[string]string{"default_command_timeout": bad})
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("default_command_timeout = %q: expected an error", bad)
+		}
+	}
+}
+
+func TestOperationTimeout(t *testing.T) {
+	if got := operationTimeout(0, 30*time.Minute); got != 30*time.Minute {
+		t.Errorf("unset provider default: %v", got)
+	}
+	if got := operationTimeout(2*time.Hour, 30*time.Minute); got != 2*time.Hour {
+		t.Errorf("provider default: %v", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Package provider: shared read_retries attribute.
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// readRetriesAttribute returns the schema of the optional read_retries
+// attribute shared by resources whose refresh can race host start-up.
+func readRetriesAttribute(what string) schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Optional: true,
+		Validators: []validator.Int64{
+			int64validator.Between(0, 10),
+		},
+		Description: "Extra attempts when reading the " + what + " fails with a transient error. Unset means no retries.",
+		MarkdownDescription: "Extra attempts when reading the " + what + " fails with a transient error " +
+			"(e.g. `The RPC server is unavailable` or `not ready` right after a reboot), with a back-off " +
+			"starting at 2s and doubling. Other errors are reported on the first attempt. Unset means no retries.",
+	}
+}
+
+// withReadRetries returns ctx carrying the configured read_retries for the
+// winclient read. Null or unknown means no retries.
+func withReadRetries(ctx context.Context, v types.Int64) context.Context {
+	if v.IsNull() || v.IsUnknown() {
+		return ctx
+	}
+	return winclient.WithReadRetries(ctx, int(v.ValueInt64()))
+}
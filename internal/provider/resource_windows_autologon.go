@@ -0,0 +1,337 @@
+// Package provider: windows_autologon resource implementation.
+//
+// This file contains the TPF schema, model and CRUD + ImportState handlers
+// for the windows_autologon resource. All WinRM interaction is delegated to
+// winclient.AutologonClient (internal/winclient).
+//
+// Automatic logon is a host-wide singleton, so the resource ID is the
+// constant "autologon".
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                = (*windowsAutologonResource)(nil)
+	_ resource.ResourceWithConfigure   = (*windowsAutologonResource)(nil)
+	_ resource.ResourceWithImportState = (*windowsAutologonResource)(nil)
+)
+
+// autologonID is the fixed resource ID: a host has a single Winlogon
+// automatic-logon configuration.
+const autologonID = "autologon"
+
+// NewWindowsAutologonResource is the constructor registered in provider.go.
+func NewWindowsAutologonResource() resource.Resource { return &windowsAutologonResource{} }
+
+// windowsAutologonResource is the TPF resource type for windows_autologon.
+type windowsAutologonResource struct {
+	al winclient.WindowsAutologonClient
+}
+
+// windowsAutologonModel is the Terraform state/plan model for the
+// windows_autologon resource.
+type windowsAutologonModel struct {
+	ID                  types.String `tfsdk:"id"`
+	Username            types.String `tfsdk:"username"`
+	Domain              types.String `tfsdk:"domain"`
+	Password            types.String `tfsdk:"password"`
+	AutoLogonCount      types.Int64  `tfsdk:"auto_logon_count"`
+	Enabled             types.Bool   `tfsdk:"enabled"`
+	RemainingLogonCount types.Int64  `tfsdk:"remaining_logon_count"`
+}
+
+// autologonUsernameRegex rejects DOMAIN\user forms; the domain has its own
+// attribute because Winlogon stores it in a separate value.
+var autologonUsernameRegex = regexp.MustCompile(`^[^\\]+$`)
+
+// Metadata sets the resource type name ("windows_autologon").
+func (r *windowsAutologonResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_autologon"
+}
+
+// Schema returns the complete TPF schema.
+func (r *windowsAutologonResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsAutologonSchemaDefinition()
+}
+
+// windowsAutologonSchemaDefinition returns the resource schema. Extracted
+// into a function so it can be unit-tested independently of the resource
+// type.
+func windowsAutologonSchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Configures Windows automatic logon (Winlogon `AutoAdminLogon`) on a remote host over WinRM/PowerShell.\n\n" +
+			"The resource writes `DefaultUserName`, `DefaultDomainName`, `DefaultPassword`, `AutoLogonCount` and `AutoAdminLogon` under " +
+			"`HKLM:\\SOFTWARE\\Microsoft\\Windows NT\\CurrentVersion\\Winlogon`.\n\n" +
+			"~> **Security:** `DefaultPassword` is stored **in plaintext** in the registry and is readable by any local administrator " +
+			"and by anyone with offline access to the disk. Prefer a dedicated low-privilege account, and set `auto_logon_count` so " +
+			"Windows deletes `DefaultPassword` and disables automatic logon by itself once the count is used up.\n\n" +
+			"Destroy removes `AutoAdminLogon`, `DefaultPassword` and `AutoLogonCount`. `DefaultUserName` and `DefaultDomainName` are " +
+			"left in place because Winlogon also uses them to pre-fill the logon screen.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Terraform resource ID. Always \"autologon\".",
+				MarkdownDescription: "Terraform resource ID. Always `autologon`: a host has a single automatic-logon configuration.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Required:            true,
+				Description:         "Account to log on automatically (DefaultUserName). Do not include a domain prefix; use domain.",
+				MarkdownDescription: "Account to log on automatically (`DefaultUserName`). Do not include a `DOMAIN\\` prefix; set `domain` instead. Compared case-insensitively.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.RegexMatches(autologonUsernameRegex,
+						"must not contain a backslash; set the domain attribute instead"),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Domain of the account (DefaultDomainName). Defaults to the local computer name.",
+				MarkdownDescription: "Domain of the account (`DefaultDomainName`). When omitted the local computer name is written, which is what Winlogon expects for a local account. Compared case-insensitively.",
+			},
+			"password": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				Description:         "Password of the account. Stored in plaintext in the DefaultPassword registry value.",
+				MarkdownDescription: "Password of the account. Stored **in plaintext** in the `DefaultPassword` registry value on the host (and, as a sensitive value, in Terraform state). Never read back from the host.",
+			},
+			"auto_logon_count": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Number of automatic logons before Windows disables automatic logon and deletes DefaultPassword. Omit for unlimited.",
+				MarkdownDescription: "Number of automatic logons before Windows disables automatic logon and deletes `DefaultPassword` (`AutoLogonCount`). Omit for unlimited logons. **Recommended**, so the plaintext password does not outlive its purpose. Windows decrements the live value; the decrement is reported in `remaining_logon_count` and is not treated as drift. Change this value to re-arm automatic logon once it has been used up.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "True while AutoAdminLogon is 1 on the host.",
+				MarkdownDescription: "`true` while `AutoAdminLogon` is `1` on the host. Becomes `false` once `auto_logon_count` has been used up.",
+			},
+			"remaining_logon_count": schema.Int64Attribute{
+				Computed:            true,
+				Description:         "Live AutoLogonCount value. Null when auto_logon_count is not set.",
+				MarkdownDescription: "Live `AutoLogonCount` value as decremented by Windows. `null` when `auto_logon_count` is not set; `0` once it has been used up.",
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsAutologonResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.al = winclient.NewAutologonClient(c)
+}
+
+// ImportState lets `terraform import windows_autologon.this autologon` adopt
+// an existing configuration. The password is never read back, so the first
+// plan after import always updates it.
+func (r *windowsAutologonResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != autologonID {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("windows_autologon is a per-host singleton; import it with the ID %q, got %q.", autologonID, req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), autologonID)...)
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+// Create writes the Winlogon values and warns that the password now lives in
+// the registry in plaintext.
+func (r *windowsAutologonResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsAutologonModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.al.Set(ctx, autologonInputFromModel(plan))
+	if err != nil {
+		addAutologonDiag(&resp.Diagnostics, "Create windows_autologon failed", err)
+		return
+	}
+	addAutologonPasswordWarning(&resp.Diagnostics, plan)
+	final := modelFromAutologonState(live, plan)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Read refreshes the computed attributes. Automatic logon that was switched
+// off out-of-band removes the resource from state, except when
+// auto_logon_count is set: Windows disabling it after the last counted logon
+// is the configured end state, not drift.
+func (r *windowsAutologonResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsAutologonModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.al.Read(ctx)
+	if err != nil {
+		addAutologonDiag(&resp.Diagnostics, "Read windows_autologon failed", err)
+		return
+	}
+	if !live.Enabled && state.AutoLogonCount.IsNull() {
+		tflog.Warn(ctx, "windows_autologon is disabled on the host — removing from state", map[string]interface{}{
+			"username": live.Username,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	final := modelFromAutologonState(live, state)
+	if live.Enabled && !live.PasswordSet {
+		// DefaultPassword was removed out-of-band: force the next apply to
+		// write it again.
+		final.Password = types.StringNull()
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Update rewrites every Winlogon value from the plan. Setting
+// auto_logon_count again re-arms automatic logon after it has been used up.
+func (r *windowsAutologonResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan windowsAutologonModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.al.Set(ctx, autologonInputFromModel(plan))
+	if err != nil {
+		addAutologonDiag(&resp.Diagnostics, "Update windows_autologon failed", err)
+		return
+	}
+	final := modelFromAutologonState(live, plan)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Delete disables automatic logon and removes the stored password.
+func (r *windowsAutologonResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsAutologonModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.al.Disable(ctx); err != nil {
+		addAutologonDiag(&resp.Diagnostics, "Delete windows_autologon failed", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// autologonInputFromModel builds the client input from a plan.
+func autologonInputFromModel(m windowsAutologonModel) winclient.AutologonInput {
+	in := winclient.AutologonInput{
+		Username: m.Username.ValueString(),
+		Domain:   m.Domain.ValueString(),
+		Password: m.Password.ValueString(),
+	}
+	if !m.AutoLogonCount.IsNull() && !m.AutoLogonCount.IsUnknown() {
+		n := m.AutoLogonCount.ValueInt64()
+		in.AutoLogonCount = &n
+	}
+	return in
+}
+
+// modelFromAutologonState projects a winclient.AutologonState onto a
+// windowsAutologonModel. password and auto_logon_count always come from
+// prior (neither is read back as configured). username and domain keep the
+// prior spelling when the host reports a case-insensitive match; domain
+// stays null when it was not configured, since the host then reports the
+// computer name.
+func modelFromAutologonState(live *winclient.AutologonState, prior windowsAutologonModel) windowsAutologonModel {
+	username := prior.Username
+	if username.IsNull() || username.IsUnknown() || !strings.EqualFold(username.ValueString(), live.Username) {
+		username = types.StringValue(live.Username)
+	}
+	domain := prior.Domain
+	if !domain.IsNull() && !domain.IsUnknown() && !strings.EqualFold(domain.ValueString(), live.Domain) {
+		domain = types.StringValue(live.Domain)
+	}
+	remaining := types.Int64Null()
+	if live.AutoLogonCount != nil {
+		remaining = types.Int64Value(*live.AutoLogonCount)
+	} else if !prior.AutoLogonCount.IsNull() && !live.Enabled {
+		// Windows deletes AutoLogonCount along with DefaultPassword once the
+		// count reaches zero.
+		remaining = types.Int64Value(0)
+	}
+	return windowsAutologonModel{
+		ID:                  types.StringValue(autologonID),
+		Username:            username,
+		Domain:              domain,
+		Password:            prior.Password,
+		AutoLogonCount:      prior.AutoLogonCount,
+		Enabled:             types.BoolValue(live.Enabled),
+		RemainingLogonCount: remaining,
+	}
+}
+
+// addAutologonPasswordWarning reminds the operator that the password is now
+// stored in plaintext on the host.
+func addAutologonPasswordWarning(diags *diag.Diagnostics, m windowsAutologonModel) {
+	detail := "Windows automatic logon stores the password in plaintext in the DefaultPassword value under " +
+		`HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Winlogon. Any local administrator, and anyone with ` +
+		"offline access to the disk, can read it. Use a dedicated low-privilege account."
+	if m.AutoLogonCount.IsNull() {
+		detail += "\n\nauto_logon_count is not set, so the password stays in the registry until this resource is " +
+			"destroyed. Setting auto_logon_count makes Windows delete DefaultPassword after that many logons."
+	}
+	diags.AddWarning("Automatic logon password is stored in the registry", detail)
+}
+
+// addAutologonDiag converts a *winclient.AutologonError into a TPF
+// diagnostic.
+func addAutologonDiag(diags *diag.Diagnostics, summary string, err error) {
+	var ae *winclient.AutologonError
+	if errors.As(err, &ae) {
+		detail := ae.Message
+		if len(ae.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range ae.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if ae.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", ae.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
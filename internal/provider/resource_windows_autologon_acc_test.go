@@ -0,0 +1,75 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for windows_autologon.
+//
+// Requires:
+//   - TF_ACC=1
+//   - WINDOWS_HOST / WINDOWS_USERNAME / WINDOWS_PASSWORD env vars
+//   - A Windows target with WinRM enabled and Local Administrator rights.
+//   - WINDOWS_AUTOLOGON_ALLOW=1
+//
+// SAFETY: the test writes a throwaway password to the Winlogon
+// DefaultPassword value and enables automatic logon with
+// auto_logon_count = 1, so a reboot during the run logs the host on once as
+// the test account. Destroy removes the values again. It is opt-in because
+// it replaces any automatic logon already configured on the host.
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccAutologonPreCheck(t *testing.T) {
+	t.Helper()
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("TF_ACC not set; skipping acceptance test")
+	}
+	for _, v := range []string{"WINDOWS_HOST", "WINDOWS_USERNAME", "WINDOWS_PASSWORD"} {
+		if os.Getenv(v) == "" {
+			t.Skipf("env %s not set; skipping acceptance test", v)
+		}
+	}
+	if os.Getenv("WINDOWS_AUTOLOGON_ALLOW") != "1" {
+		t.Skip("WINDOWS_AUTOLOGON_ALLOW != 1; skipping automatic logon test")
+	}
+}
+
+// TestAccWindowsAutologon_Basic — create + idempotency + import.
+func TestAccWindowsAutologon_Basic(t *testing.T) {
+	testAccAutologonPreCheck(t)
+
+	cfg := `
+resource "windows_autologon" "test" {
+  username         = "tf_acc_autologon"
+  password         = "TfAcc!Autologon-2026"
+  auto_logon_count = 1
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windows_autologon.test", "id", "autologon"),
+					resource.TestCheckResourceAttr("windows_autologon.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("windows_autologon.test", "remaining_logon_count", "1"),
+				),
+			},
+			{
+				Config:   cfg,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "windows_autologon.test",
+				ImportState:             true,
+				ImportStateId:           "autologon",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "auto_logon_count"},
+			},
+		},
+	})
+}
@@ -0,0 +1,305 @@
+// Package provider — unit tests for the windows_autologon resource.
+//
+// They exercise the schema, helpers and CRUD handlers without touching
+// WinRM, using a fakeAutologonClient injected into
+// windowsAutologonResource.al.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// -----------------------------------------------------------------------------
+// Fake WindowsAutologonClient
+// -----------------------------------------------------------------------------
+
+type fakeAutologonClient struct {
+	setIn      winclient.AutologonInput
+	setOut     *winclient.AutologonState
+	setErr     error
+	readOut    *winclient.AutologonState
+	readErr    error
+	disableErr error
+	disabled   bool
+}
+
+func (f *fakeAutologonClient) Set(_ context.Context, in winclient.AutologonInput) (*winclient.AutologonState, error) {
+	f.setIn = in
+	return f.setOut, f.setErr
+}
+func (f *fakeAutologonClient) Read(_ context.Context) (*winclient.AutologonState, error) {
+	return f.readOut, f.readErr
+}
+func (f *fakeAutologonClient) Disable(_ context.Context) error {
+	f.disabled = true
+	return f.disableErr
+}
+
+func alCount(n int64) *int64 { return &n }
+
+func alObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":                    tftypes.String,
+		"username":              tftypes.String,
+		"domain":                tftypes.String,
+		"password":              tftypes.String,
+		"auto_logon_count":      tftypes.Number,
+		"enabled":               tftypes.Bool,
+		"remaining_logon_count": tftypes.Number,
+	}}
+}
+
+func alObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{}
+	for k, t := range alObjectType().AttributeTypes {
+		base[k] = tftypes.NewValue(t, nil)
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(alObjectType(), base)
+}
+
+func alPlanValues(count any) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"id":                    tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"username":              tftypes.NewValue(tftypes.String, "kiosk"),
+		"password":              tftypes.NewValue(tftypes.String, "S3cret!pw"),
+		"auto_logon_count":      tftypes.NewValue(tftypes.Number, count),
+		"enabled":               tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+		"remaining_logon_count": tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+	}
+}
+
+func alStateValues(count any) map[string]tftypes.Value {
+	v := alPlanValues(count)
+	v["id"] = tftypes.NewValue(tftypes.String, autologonID)
+	v["enabled"] = tftypes.NewValue(tftypes.Bool, true)
+	v["remaining_logon_count"] = tftypes.NewValue(tftypes.Number, count)
+	return v
+}
+
+func alRead(t *testing.T, fake *fakeAutologonClient, prior map[string]tftypes.Value) (*resource.ReadResponse, windowsAutologonModel) {
+	t.Helper()
+	r := &windowsAutologonResource{al: fake}
+	s := windowsAutologonSchemaDefinition()
+	state := tfsdk.State{Schema: s, Raw: alObj(prior)}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: s, Raw: state.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+	var m windowsAutologonModel
+	if !resp.State.Raw.IsNull() {
+		resp.State.Get(context.Background(), &m)
+	}
+	return resp, m
+}
+
+// -----------------------------------------------------------------------------
+// Metadata + Schema
+// -----------------------------------------------------------------------------
+
+func TestAutologonMetadata(t *testing.T) {
+	r := &windowsAutologonResource{}
+	resp := &resource.MetadataResponse{}
+	r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_autologon" {
+		t.Errorf("TypeName = %q, want windows_autologon", resp.TypeName)
+	}
+}
+
+func TestAutologonSchema_PasswordSensitive(t *testing.T) {
+	s := windowsAutologonSchemaDefinition()
+	pw, ok := s.Attributes["password"].(schema.StringAttribute)
+	if !ok || !pw.Sensitive || !pw.Required {
+		t.Errorf("password must be Required+Sensitive, got %+v", s.Attributes["password"])
+	}
+	if len(s.Attributes) != len(alObjectType().AttributeTypes) {
+		t.Errorf("schema has %d attributes, test type has %d", len(s.Attributes), len(alObjectType().AttributeTypes))
+	}
+}
+
+func TestAutologonUsernameRegex(t *testing.T) {
+	if !autologonUsernameRegex.MatchString("kiosk") || !autologonUsernameRegex.MatchString("kiosk@corp.example") {
+		t.Error("plain and UPN names must be accepted")
+	}
+	if autologonUsernameRegex.MatchString(`CORP\kiosk`) {
+		t.Error(`DOMAIN\user must be rejected`)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Create
+// -----------------------------------------------------------------------------
+
+func TestAutologonCreate_WarnsAndPassesInput(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		count     any
+		wantCount *int64
+		wantHint  bool
+	}{
+		{"unlimited", nil, nil, true},
+		{"counted", 2, alCount(2), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeAutologonClient{setOut: &winclient.AutologonState{
+				Enabled: true, Username: "kiosk", Domain: "WIN01", AutoLogonCount: tc.wantCount, PasswordSet: true,
+			}}
+			r := &windowsAutologonResource{al: fake}
+			s := windowsAutologonSchemaDefinition()
+			resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: alObj(nil)}}
+			r.Create(context.Background(), resource.CreateRequest{
+				Plan: tfsdk.Plan{Schema: s, Raw: alObj(alPlanValues(tc.count))},
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+			}
+			if fake.setIn.Password != "S3cret!pw" || fake.setIn.Username != "kiosk" || fake.setIn.Domain != "" {
+				t.Errorf("input = %+v", fake.setIn)
+			}
+			if (fake.setIn.AutoLogonCount == nil) != (tc.wantCount == nil) {
+				t.Errorf("AutoLogonCount = %v, want %v", fake.setIn.AutoLogonCount, tc.wantCount)
+			}
+			warns := resp.Diagnostics.Warnings()
+			if len(warns) != 1 || !strings.Contains(warns[0].Detail(), "plaintext") {
+				t.Fatalf("expected one plaintext warning, got %v", warns)
+			}
+			if got := strings.Contains(warns[0].Detail(), "auto_logon_count is not set"); got != tc.wantHint {
+				t.Errorf("count hint present = %v, want %v", got, tc.wantHint)
+			}
+			var m windowsAutologonModel
+			resp.State.Get(context.Background(), &m)
+			if m.ID.ValueString() != autologonID || !m.Domain.IsNull() || !m.Enabled.ValueBool() {
+				t.Errorf("state = %+v", m)
+			}
+		})
+	}
+}
+
+func TestAutologonCreate_Error(t *testing.T) {
+	fake := &fakeAutologonClient{setErr: winclient.NewAutologonError(winclient.AutologonErrorPermission, "Access is denied", nil, nil)}
+	r := &windowsAutologonResource{al: fake}
+	s := windowsAutologonSchemaDefinition()
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: alObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{
+		Plan: tfsdk.Plan{Schema: s, Raw: alObj(alPlanValues(nil))},
+	}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "Kind: permission_denied") {
+		t.Errorf("expected permission_denied diag, got %v", resp.Diagnostics)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Read
+// -----------------------------------------------------------------------------
+
+func TestAutologonRead_DisabledOutOfBandRemoves(t *testing.T) {
+	fake := &fakeAutologonClient{readOut: &winclient.AutologonState{Enabled: false, Username: "kiosk"}}
+	resp, _ := alRead(t, fake, alStateValues(nil))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("disabled autologon without a count should be removed from state")
+	}
+}
+
+func TestAutologonRead_CountExhaustedKeepsState(t *testing.T) {
+	fake := &fakeAutologonClient{readOut: &winclient.AutologonState{Enabled: false, Username: "kiosk", Domain: "WIN01"}}
+	resp, m := alRead(t, fake, alStateValues(3))
+	if resp.Diagnostics.HasError() || resp.State.Raw.IsNull() {
+		t.Fatalf("exhausted count must keep the resource: %v", resp.Diagnostics)
+	}
+	if m.Enabled.ValueBool() || m.RemainingLogonCount.ValueInt64() != 0 {
+		t.Errorf("enabled = %v, remaining = %v", m.Enabled, m.RemainingLogonCount)
+	}
+	if m.AutoLogonCount.ValueInt64() != 3 || m.Password.ValueString() != "S3cret!pw" {
+		t.Error("configured count and password must be preserved")
+	}
+}
+
+func TestAutologonRead_DecrementIsNotDrift(t *testing.T) {
+	fake := &fakeAutologonClient{readOut: &winclient.AutologonState{
+		Enabled: true, Username: "KIOSK", Domain: "WIN01", AutoLogonCount: alCount(1), PasswordSet: true,
+	}}
+	_, m := alRead(t, fake, alStateValues(3))
+	if m.AutoLogonCount.ValueInt64() != 3 || m.RemainingLogonCount.ValueInt64() != 1 {
+		t.Errorf("auto_logon_count = %v, remaining = %v", m.AutoLogonCount, m.RemainingLogonCount)
+	}
+	if m.Username.ValueString() != "kiosk" {
+		t.Errorf("case-only username difference should keep prior spelling, got %q", m.Username.ValueString())
+	}
+}
+
+func TestAutologonRead_Drift(t *testing.T) {
+	fake := &fakeAutologonClient{readOut: &winclient.AutologonState{
+		Enabled: true, Username: "other", Domain: "CORP", PasswordSet: false,
+	}}
+	prior := alStateValues(nil)
+	prior["domain"] = tftypes.NewValue(tftypes.String, "WIN01")
+	_, m := alRead(t, fake, prior)
+	if m.Username.ValueString() != "other" || m.Domain.ValueString() != "CORP" {
+		t.Errorf("username/domain drift not reported: %+v", m)
+	}
+	if !m.Password.IsNull() {
+		t.Error("a missing DefaultPassword should null the password to force a rewrite")
+	}
+}
+
+func TestAutologonRead_Import(t *testing.T) {
+	fake := &fakeAutologonClient{readOut: &winclient.AutologonState{
+		Enabled: true, Username: "kiosk", Domain: "WIN01", PasswordSet: true,
+	}}
+	_, m := alRead(t, fake, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, autologonID)})
+	if m.Username.ValueString() != "kiosk" || !m.Domain.IsNull() || !m.Password.IsNull() {
+		t.Errorf("imported state = %+v", m)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Delete / ImportState / helpers
+// -----------------------------------------------------------------------------
+
+func TestAutologonDelete_Disables(t *testing.T) {
+	fake := &fakeAutologonClient{}
+	r := &windowsAutologonResource{al: fake}
+	s := windowsAutologonSchemaDefinition()
+	resp := &resource.DeleteResponse{}
+	r.Delete(context.Background(), resource.DeleteRequest{State: tfsdk.State{Schema: s, Raw: alObj(alStateValues(nil))}}, resp)
+	if resp.Diagnostics.HasError() || !fake.disabled {
+		t.Errorf("Delete should call Disable: %v", resp.Diagnostics)
+	}
+}
+
+func TestAutologonImportState(t *testing.T) {
+	r := &windowsAutologonResource{}
+	s := windowsAutologonSchemaDefinition()
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: alObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: autologonID}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	resp = &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: alObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "kiosk"}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("an ID other than \"autologon\" must be rejected")
+	}
+}
+
+func TestAutologonInputFromModel_UnknownCount(t *testing.T) {
+	in := autologonInputFromModel(windowsAutologonModel{
+		Username: types.StringValue("u"), AutoLogonCount: types.Int64Unknown(),
+	})
+	if in.AutoLogonCount != nil {
+		t.Errorf("unknown count should be omitted, got %v", *in.AutoLogonCount)
+	}
+}
@@ -0,0 +1,380 @@
+// Package provider: windows_certificate resource implementation.
+//
+// This file contains the TPF schema, model and CRUD + ImportState handlers
+// for the windows_certificate resource. All WinRM interaction is delegated to
+// winclient.CertificateClient (internal/winclient).
+//
+// The resource ID is "<store_location>/<store_name>/<thumbprint>". Every
+// input forces replacement: a certificate in a store cannot be edited, only
+// imported again. The one in-place update is adopting pfx_base64/password
+// after `terraform import`, which cannot read them back.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                = (*windowsCertificateResource)(nil)
+	_ resource.ResourceWithConfigure   = (*windowsCertificateResource)(nil)
+	_ resource.ResourceWithImportState = (*windowsCertificateResource)(nil)
+)
+
+// NewWindowsCertificateResource is the constructor registered in provider.go.
+func NewWindowsCertificateResource() resource.Resource { return &windowsCertificateResource{} }
+
+// windowsCertificateResource is the TPF resource type for windows_certificate.
+type windowsCertificateResource struct {
+	cert winclient.WindowsCertificateClient
+}
+
+// windowsCertificateModel is the Terraform state/plan model for the
+// windows_certificate resource.
+type windowsCertificateModel struct {
+	ID            types.String `tfsdk:"id"`
+	PFXBase64     types.String `tfsdk:"pfx_base64"`
+	Password      types.String `tfsdk:"password"`
+	StoreLocation types.String `tfsdk:"store_location"`
+	StoreName     types.String `tfsdk:"store_name"`
+	Thumbprint    types.String `tfsdk:"thumbprint"`
+	Subject       types.String `tfsdk:"subject"`
+	Issuer        types.String `tfsdk:"issuer"`
+	FriendlyName  types.String `tfsdk:"friendly_name"`
+	NotBefore     types.String `tfsdk:"not_before"`
+	NotAfter      types.String `tfsdk:"not_after"`
+	HasPrivateKey types.Bool   `tfsdk:"has_private_key"`
+}
+
+// certificateStoreNameRegex mirrors the client-side store name check.
+var certificateStoreNameRegex = regexp.MustCompile(`^[A-Za-z0-9 ._-]+$`)
+
+// certificateThumbprintRegex matches a SHA-1 thumbprint in an import ID.
+var certificateThumbprintRegex = regexp.MustCompile(`^[0-9A-Fa-f]{40}$`)
+
+// requiresReplaceUnlessImported replaces the certificate when pfx_base64 or
+// password changes, except right after import: the state then has no
+// pfx_base64, and the configured values are adopted in place.
+func requiresReplaceUnlessImported(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var prior types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("pfx_base64"), &prior)...)
+	resp.RequiresReplace = !prior.IsNull()
+}
+
+// Metadata sets the resource type name ("windows_certificate").
+func (r *windowsCertificateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate"
+}
+
+// Schema returns the complete TPF schema.
+func (r *windowsCertificateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsCertificateSchemaDefinition()
+}
+
+// windowsCertificateSchemaDefinition returns the resource schema. Extracted
+// into a function so it can be unit-tested independently of the resource
+// type.
+func windowsCertificateSchemaDefinition() schema.Schema {
+	replaceUnlessImported := stringplanmodifier.RequiresReplaceIf(requiresReplaceUnlessImported,
+		"Changing the PFX imports it again, except when adopting it after terraform import.",
+		"Changing the PFX imports it again, except when adopting it after `terraform import`.")
+	return schema.Schema{
+		MarkdownDescription: "Imports a PFX (PKCS#12) file into a Windows certificate store on a remote host over WinRM/PowerShell " +
+			"(`Import-PfxCertificate`), for example a TLS certificate for IIS or a service.\n\n" +
+			"The PFX and its password travel over stdin, never in the command line. `Import-PfxCertificate` only reads files, so the " +
+			"PFX is written to a private directory under the WinRM user's `%TEMP%`, overwritten with zeros and removed as soon as the " +
+			"import ends, including when it fails.\n\n" +
+			"Destroy removes the certificate and its private key. Every attribute forces replacement.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Terraform resource ID: <store_location>/<store_name>/<thumbprint>.",
+				MarkdownDescription: "Terraform resource ID: `<store_location>/<store_name>/<thumbprint>`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"pfx_base64": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				Description:         "The PFX file, base64-encoded (e.g. filebase64(\"site.pfx\")).",
+				MarkdownDescription: "The PFX file, base64-encoded (e.g. `filebase64(\"site.pfx\")`). Stored in Terraform state as a sensitive value. Changing it replaces the certificate.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{replaceUnlessImported},
+			},
+			"password": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Description:         "Password protecting the PFX. Omit for a PFX without a password.",
+				MarkdownDescription: "Password protecting the PFX. Omit for a PFX without a password. Changing it replaces the certificate.",
+				PlanModifiers:       []planmodifier.String{replaceUnlessImported},
+			},
+			"store_location": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("LocalMachine"),
+				Description:         "Store location: LocalMachine (default) or CurrentUser.",
+				MarkdownDescription: "Store location: `LocalMachine` (default) or `CurrentUser`. `CurrentUser` is the store of the WinRM account, not of any interactive user.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(winclient.CertificateStoreLocations...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"store_name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("My"),
+				Description:         "Store name under store_location: My (default, Personal), Root, CA, WebHosting, TrustedPeople, ...",
+				MarkdownDescription: "Store name under `store_location`: `My` (default, the Personal store), `Root`, `CA`, `WebHosting`, `TrustedPeople`, ... The store must exist.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(certificateStoreNameRegex, "must be a certificate store name such as My or WebHosting"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"thumbprint": schema.StringAttribute{
+				Computed:            true,
+				Description:         "SHA-1 thumbprint of the imported certificate, upper-case hex.",
+				MarkdownDescription: "SHA-1 thumbprint of the imported certificate, upper-case hex. Use it for IIS bindings or `netsh http add sslcert`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"subject": schema.StringAttribute{
+				Computed:    true,
+				Description: "Subject distinguished name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"issuer": schema.StringAttribute{
+				Computed:    true,
+				Description: "Issuer distinguished name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"friendly_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Friendly name stored in the PFX, or empty.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"not_before": schema.StringAttribute{
+				Computed:    true,
+				Description: "Start of the validity period, RFC 3339 UTC.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"not_after": schema.StringAttribute{
+				Computed:    true,
+				Description: "End of the validity period, RFC 3339 UTC.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"has_private_key": schema.BoolAttribute{
+				Computed:    true,
+				Description: "True when the private key was imported with the certificate.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsCertificateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.cert = winclient.NewCertificateClient(c)
+}
+
+// ImportState accepts "<store_location>/<store_name>/<thumbprint>". The PFX
+// cannot be read back; the configured pfx_base64 and password are adopted on
+// the next apply without importing again.
+func (r *windowsCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 3 || !certificateStoreNameRegex.MatchString(parts[1]) || !certificateThumbprintRegex.MatchString(parts[2]) {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("Expected <store_location>/<store_name>/<thumbprint> (e.g. LocalMachine/My/3F2A...C3), got %q.", req.ID))
+		return
+	}
+	loc := ""
+	for _, l := range winclient.CertificateStoreLocations {
+		if strings.EqualFold(l, parts[0]) {
+			loc = l
+		}
+	}
+	if loc == "" {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("store_location must be one of %s, got %q.", strings.Join(winclient.CertificateStoreLocations, ", "), parts[0]))
+		return
+	}
+	thumb := strings.ToUpper(parts[2])
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), certificateID(loc, parts[1], thumb))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("store_location"), loc)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("store_name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("thumbprint"), thumb)...)
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+// Create imports the PFX.
+func (r *windowsCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsCertificateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.cert.Import(ctx, winclient.CertificateInput{
+		PFXBase64:     plan.PFXBase64.ValueString(),
+		Password:      plan.Password.ValueString(),
+		StoreLocation: plan.StoreLocation.ValueString(),
+		StoreName:     plan.StoreName.ValueString(),
+	})
+	if err != nil {
+		addCertificateDiag(&resp.Diagnostics, "Create windows_certificate failed", err)
+		return
+	}
+	final := modelFromCertificateState(live, plan)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Read refreshes the certificate by thumbprint. A certificate removed
+// out-of-band removes the resource from state.
+func (r *windowsCertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsCertificateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.cert.Read(ctx, state.StoreLocation.ValueString(), state.StoreName.ValueString(), state.Thumbprint.ValueString())
+	if err != nil {
+		addCertificateDiag(&resp.Diagnostics, "Read windows_certificate failed", err)
+		return
+	}
+	if live == nil {
+		tflog.Warn(ctx, "windows_certificate not found in its store — removing from state", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	final := modelFromCertificateState(live, state)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Update is only reached when adopting pfx_base64/password after import;
+// every other change replaces the resource. Nothing runs on the host.
+func (r *windowsCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state windowsCertificateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.PFXBase64 = plan.PFXBase64
+	state.Password = plan.Password
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete removes the certificate and its private key.
+func (r *windowsCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsCertificateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.cert.Delete(ctx, state.StoreLocation.ValueString(), state.StoreName.ValueString(), state.Thumbprint.ValueString()); err != nil {
+		addCertificateDiag(&resp.Diagnostics, "Delete windows_certificate failed", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// certificateID builds the resource ID.
+func certificateID(location, store, thumbprint string) string {
+	return location + "/" + store + "/" + thumbprint
+}
+
+// modelFromCertificateState projects a winclient.CertificateState onto a
+// windowsCertificateModel. pfx_base64 and password always come from prior,
+// as do store_location and store_name (the host reports what was asked).
+func modelFromCertificateState(live *winclient.CertificateState, prior windowsCertificateModel) windowsCertificateModel {
+	return windowsCertificateModel{
+		ID:            types.StringValue(certificateID(prior.StoreLocation.ValueString(), prior.StoreName.ValueString(), live.Thumbprint)),
+		PFXBase64:     prior.PFXBase64,
+		Password:      prior.Password,
+		StoreLocation: prior.StoreLocation,
+		StoreName:     prior.StoreName,
+		Thumbprint:    types.StringValue(live.Thumbprint),
+		Subject:       types.StringValue(live.Subject),
+		Issuer:        types.StringValue(live.Issuer),
+		FriendlyName:  types.StringValue(live.FriendlyName),
+		NotBefore:     types.StringValue(live.NotBefore),
+		NotAfter:      types.StringValue(live.NotAfter),
+		HasPrivateKey: types.BoolValue(live.HasPrivateKey),
+	}
+}
+
+// addCertificateDiag converts a *winclient.CertificateError into a TPF
+// diagnostic.
+func addCertificateDiag(diags *diag.Diagnostics, summary string, err error) {
+	var ce *winclient.CertificateError
+	if errors.As(err, &ce) {
+		detail := ce.Message
+		if len(ce.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range ce.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if ce.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", ce.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
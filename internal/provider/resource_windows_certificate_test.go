@@ -0,0 +1,327 @@
+// Package provider — unit tests for the windows_certificate resource.
+//
+// They exercise the schema, helpers and CRUD handlers without touching
+// WinRM, using a fakeCertificateClient injected into
+// windowsCertificateResource.cert.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+const certTestThumbprint = "3F2A9C1D4B5E6F708192A3B4C5D6E7F809A1B2C3"
+
+// -----------------------------------------------------------------------------
+// Fake WindowsCertificateClient
+// -----------------------------------------------------------------------------
+
+type fakeCertificateClient struct {
+	importIn  winclient.CertificateInput
+	importOut *winclient.CertificateState
+	importErr error
+	readOut   *winclient.CertificateState
+	readErr   error
+	deleteErr error
+	deleted   []string
+}
+
+func (f *fakeCertificateClient) Import(_ context.Context, in winclient.CertificateInput) (*winclient.CertificateState, error) {
+	f.importIn = in
+	return f.importOut, f.importErr
+}
+func (f *fakeCertificateClient) Read(_ context.Context, _, _, _ string) (*winclient.CertificateState, error) {
+	return f.readOut, f.readErr
+}
+func (f *fakeCertificateClient) Delete(_ context.Context, location, store, thumbprint string) error {
+	f.deleted = append(f.deleted, location, store, thumbprint)
+	return f.deleteErr
+}
+
+func certLiveState() *winclient.CertificateState {
+	return &winclient.CertificateState{
+		Thumbprint: certTestThumbprint, Subject: "CN=www.example.com", Issuer: "CN=Example CA",
+		NotBefore: "2026-01-01T00:00:00Z", NotAfter: "2027-01-01T00:00:00Z", HasPrivateKey: true,
+		StoreLocation: "LocalMachine", StoreName: "My",
+	}
+}
+
+func certObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":              tftypes.String,
+		"pfx_base64":      tftypes.String,
+		"password":        tftypes.String,
+		"store_location":  tftypes.String,
+		"store_name":      tftypes.String,
+		"thumbprint":      tftypes.String,
+		"subject":         tftypes.String,
+		"issuer":          tftypes.String,
+		"friendly_name":   tftypes.String,
+		"not_before":      tftypes.String,
+		"not_after":       tftypes.String,
+		"has_private_key": tftypes.Bool,
+	}}
+}
+
+func certObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{}
+	for k, t := range certObjectType().AttributeTypes {
+		base[k] = tftypes.NewValue(t, nil)
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(certObjectType(), base)
+}
+
+func certPlanValues() map[string]tftypes.Value {
+	v := map[string]tftypes.Value{
+		"pfx_base64":     tftypes.NewValue(tftypes.String, "TUlJS1BGWA=="),
+		"password":       tftypes.NewValue(tftypes.String, "Pfx!pw"),
+		"store_location": tftypes.NewValue(tftypes.String, "LocalMachine"),
+		"store_name":     tftypes.NewValue(tftypes.String, "My"),
+	}
+	for _, k := range []string{"id", "thumbprint", "subject", "issuer", "friendly_name", "not_before", "not_after"} {
+		v[k] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	}
+	v["has_private_key"] = tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue)
+	return v
+}
+
+func certStateValues() map[string]tftypes.Value {
+	v := certPlanValues()
+	v["id"] = tftypes.NewValue(tftypes.String, "LocalMachine/My/"+certTestThumbprint)
+	v["thumbprint"] = tftypes.NewValue(tftypes.String, certTestThumbprint)
+	v["subject"] = tftypes.NewValue(tftypes.String, "CN=www.example.com")
+	v["issuer"] = tftypes.NewValue(tftypes.String, "CN=Example CA")
+	v["friendly_name"] = tftypes.NewValue(tftypes.String, "")
+	v["not_before"] = tftypes.NewValue(tftypes.String, "2026-01-01T00:00:00Z")
+	v["not_after"] = tftypes.NewValue(tftypes.String, "2027-01-01T00:00:00Z")
+	v["has_private_key"] = tftypes.NewValue(tftypes.Bool, true)
+	return v
+}
+
+// -----------------------------------------------------------------------------
+// Metadata + Schema
+// -----------------------------------------------------------------------------
+
+func TestCertificateMetadata(t *testing.T) {
+	r := &windowsCertificateResource{}
+	resp := &resource.MetadataResponse{}
+	r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_certificate" {
+		t.Errorf("TypeName = %q, want windows_certificate", resp.TypeName)
+	}
+}
+
+func TestCertificateSchema_SecretsSensitive(t *testing.T) {
+	s := windowsCertificateSchemaDefinition()
+	for _, name := range []string{"pfx_base64", "password"} {
+		a, ok := s.Attributes[name].(schema.StringAttribute)
+		if !ok || !a.Sensitive {
+			t.Errorf("%s must be a Sensitive string attribute", name)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Plan modifiers
+// -----------------------------------------------------------------------------
+
+func TestCertificateRequiresReplaceUnlessImported(t *testing.T) {
+	s := windowsCertificateSchemaDefinition()
+	imported := certStateValues()
+	imported["pfx_base64"] = tftypes.NewValue(tftypes.String, nil)
+	imported["password"] = tftypes.NewValue(tftypes.String, nil)
+	cases := map[string]struct {
+		prior map[string]tftypes.Value
+		want  bool
+	}{
+		"managed":  {certStateValues(), true},
+		"imported": {imported, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := planmodifier.StringRequest{State: tfsdk.State{Schema: s, Raw: certObj(tc.prior)}}
+			resp := &planmodifier.StringResponse{}
+			modifier := s.Attributes["pfx_base64"].(schema.StringAttribute).PlanModifiers[0]
+			req.Path = path.Root("pfx_base64")
+			req.StateValue = types.StringNull()
+			if tc.prior["pfx_base64"].IsKnown() && !tc.prior["pfx_base64"].IsNull() {
+				req.StateValue = types.StringValue("TUlJS1BGWA==")
+			}
+			req.PlanValue = types.StringValue("TkVXUEZY")
+			req.ConfigValue = req.PlanValue
+			req.Plan = tfsdk.Plan{Schema: s, Raw: certObj(certPlanValues())}
+			modifier.PlanModifyString(context.Background(), req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("diags: %v", resp.Diagnostics)
+			}
+			if resp.RequiresReplace != tc.want {
+				t.Errorf("RequiresReplace = %v, want %v", resp.RequiresReplace, tc.want)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+func TestCertificateCreate(t *testing.T) {
+	fake := &fakeCertificateClient{importOut: certLiveState()}
+	r := &windowsCertificateResource{cert: fake}
+	s := windowsCertificateSchemaDefinition()
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: certObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{
+		Plan: tfsdk.Plan{Schema: s, Raw: certObj(certPlanValues())},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if fake.importIn.PFXBase64 != "TUlJS1BGWA==" || fake.importIn.Password != "Pfx!pw" || fake.importIn.StoreName != "My" {
+		t.Errorf("import input = %+v", fake.importIn)
+	}
+	var m windowsCertificateModel
+	resp.State.Get(context.Background(), &m)
+	if m.ID.ValueString() != "LocalMachine/My/"+certTestThumbprint || m.Thumbprint.ValueString() != certTestThumbprint ||
+		!m.HasPrivateKey.ValueBool() || m.PFXBase64.ValueString() != "TUlJS1BGWA==" {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestCertificateCreate_Error(t *testing.T) {
+	fake := &fakeCertificateClient{importErr: winclient.NewCertificateError(winclient.CertificateErrorInvalidPassword,
+		"the PFX password is wrong", nil, map[string]string{"store": `Cert:\LocalMachine\My`})}
+	r := &windowsCertificateResource{cert: fake}
+	s := windowsCertificateSchemaDefinition()
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: certObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{
+		Plan: tfsdk.Plan{Schema: s, Raw: certObj(certPlanValues())},
+	}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error")
+	}
+	detail := resp.Diagnostics.Errors()[0].Detail()
+	if !strings.Contains(detail, "Kind: invalid_password") || !strings.Contains(detail, "Context:") {
+		t.Errorf("detail = %q", detail)
+	}
+}
+
+func TestCertificateRead_RemovedOutOfBand(t *testing.T) {
+	r := &windowsCertificateResource{cert: &fakeCertificateClient{}}
+	s := windowsCertificateSchemaDefinition()
+	state := tfsdk.State{Schema: s, Raw: certObj(certStateValues())}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: s, Raw: state.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("state should be removed when the certificate is gone")
+	}
+}
+
+func TestCertificateRead_AfterImportKeepsSecretsNull(t *testing.T) {
+	live := certLiveState()
+	live.FriendlyName = "www"
+	r := &windowsCertificateResource{cert: &fakeCertificateClient{readOut: live}}
+	s := windowsCertificateSchemaDefinition()
+	prior := certObj(map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "LocalMachine/My/"+certTestThumbprint),
+		"store_location": tftypes.NewValue(tftypes.String, "LocalMachine"),
+		"store_name":     tftypes.NewValue(tftypes.String, "My"),
+		"thumbprint":     tftypes.NewValue(tftypes.String, certTestThumbprint),
+	})
+	state := tfsdk.State{Schema: s, Raw: prior}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: s, Raw: state.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var m windowsCertificateModel
+	resp.State.Get(context.Background(), &m)
+	if !m.PFXBase64.IsNull() || !m.Password.IsNull() {
+		t.Error("pfx_base64/password cannot be read back and must stay null")
+	}
+	if m.FriendlyName.ValueString() != "www" || m.Subject.ValueString() != "CN=www.example.com" {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestCertificateUpdate_AdoptsSecrets(t *testing.T) {
+	r := &windowsCertificateResource{cert: &fakeCertificateClient{}}
+	s := windowsCertificateSchemaDefinition()
+	prior := certStateValues()
+	prior["pfx_base64"] = tftypes.NewValue(tftypes.String, nil)
+	prior["password"] = tftypes.NewValue(tftypes.String, nil)
+	plan := certStateValues()
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: certObj(prior)}}
+	r.Update(context.Background(), resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: s, Raw: certObj(plan)},
+		State: tfsdk.State{Schema: s, Raw: certObj(prior)},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var m windowsCertificateModel
+	resp.State.Get(context.Background(), &m)
+	if m.PFXBase64.ValueString() != "TUlJS1BGWA==" || m.Password.ValueString() != "Pfx!pw" {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestCertificateDelete(t *testing.T) {
+	fake := &fakeCertificateClient{}
+	r := &windowsCertificateResource{cert: fake}
+	s := windowsCertificateSchemaDefinition()
+	resp := &resource.DeleteResponse{}
+	r.Delete(context.Background(), resource.DeleteRequest{
+		State: tfsdk.State{Schema: s, Raw: certObj(certStateValues())},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if strings.Join(fake.deleted, "/") != "LocalMachine/My/"+certTestThumbprint {
+		t.Errorf("deleted = %v", fake.deleted)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// ImportState
+// -----------------------------------------------------------------------------
+
+func TestCertificateImportState(t *testing.T) {
+	r := &windowsCertificateResource{}
+	s := windowsCertificateSchemaDefinition()
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: certObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "localmachine/WebHosting/" + strings.ToLower(certTestThumbprint)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var m windowsCertificateModel
+	resp.State.Get(context.Background(), &m)
+	if m.ID.ValueString() != "LocalMachine/WebHosting/"+certTestThumbprint || m.StoreLocation.ValueString() != "LocalMachine" ||
+		m.StoreName.ValueString() != "WebHosting" || m.Thumbprint.ValueString() != certTestThumbprint {
+		t.Errorf("state = %+v", m)
+	}
+
+	for _, id := range []string{"My/" + certTestThumbprint, "Machine/My/" + certTestThumbprint, "LocalMachine/My/abc", "LocalMachine/*/" + certTestThumbprint} {
+		resp = &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: certObj(nil)}}
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: id}, resp)
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("ImportState(%q) should fail", id)
+		}
+	}
+}
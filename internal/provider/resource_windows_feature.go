@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
@@ -35,11 +36,16 @@ import (
 // minutes to install, so the default is generous.
 const featureDefaultTimeout = 30 * time.Minute
 
+// featureProgressInterval is how often a running install polls the feature's
+// install state for the progress log. Tests may shorten it.
+var featureProgressInterval = 15 * time.Second
+
 // Framework interface assertions.
 var (
-	_ resource.Resource                = (*windowsFeatureResource)(nil)
-	_ resource.ResourceWithConfigure   = (*windowsFeatureResource)(nil)
-	_ resource.ResourceWithImportState = (*windowsFeatureResource)(nil)
+	_ resource.Resource                     = (*windowsFeatureResource)(nil)
+	_ resource.ResourceWithConfigure        = (*windowsFeatureResource)(nil)
+	_ resource.ResourceWithImportState      = (*windowsFeatureResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*windowsFeatureResource)(nil)
 )
 
 // NewWindowsFeatureResource is the constructor registered in provider.go.
@@ -48,6 +54,9 @@ func NewWindowsFeatureResource() resource.Resource { return &windowsFeatureResou
 // windowsFeatureResource is the TPF resource type for windows_feature.
 type windowsFeatureResource struct {
 	feat winclient.WindowsFeatureClient
+	// defaultTimeout is the provider default_command_timeout (0 = unset,
+	// featureDefaultTimeout applies).
+	defaultTimeout time.Duration
 }
 
 // windowsFeatureModel is the Terraform state/plan model for windows_feature.
@@ -60,9 +69,15 @@ type windowsFeatureModel struct {
 	IncludeSubFeatures     types.Bool     `tfsdk:"include_sub_features"`
 	IncludeManagementTools types.Bool     `tfsdk:"include_management_tools"`
 	Source                 types.String   `tfsdk:"source"`
+	LogPath                types.String   `tfsdk:"log_path"`
+	UseWindowsUpdate       types.Bool     `tfsdk:"use_windows_update"`
 	Restart                types.Bool     `tfsdk:"restart"`
 	RestartPending         types.Bool     `tfsdk:"restart_pending"`
 	InstallState           types.String   `tfsdk:"install_state"`
+	Managed                types.Bool     `tfsdk:"managed"`
+	ForceUninstallAdopted  types.Bool     `tfsdk:"force_uninstall_adopted"`
+	SkipDestroy            types.Bool     `tfsdk:"skip_destroy"`
+	ReadRetries            types.Int64    `tfsdk:"read_retries"`
 	Timeouts               timeouts.Value `tfsdk:"timeouts"`
 }
 
@@ -76,11 +91,53 @@ func (r *windowsFeatureResource) Schema(ctx context.Context, _ resource.SchemaRe
 	resp.Schema = windowsFeatureSchemaDefinition(ctx)
 }
 
+// ConfigValidators returns the resource-level cross-field validators.
+func (r *windowsFeatureResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{featureSourceRequiredValidator{}}
+}
+
+// featureSourceRequiredValidator rejects use_windows_update=false without a
+// source at plan time: with no local payload and Windows Update ruled out
+// the install could only fail on the host.
+type featureSourceRequiredValidator struct{}
+
+func (v featureSourceRequiredValidator) Description(_ context.Context) string {
+	return "source is required when use_windows_update is false."
+}
+
+func (v featureSourceRequiredValidator) MarkdownDescription(_ context.Context) string {
+	return "`source` is required when `use_windows_update` is `false`."
+}
+
+func (v featureSourceRequiredValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var useWU types.Bool
+	var source types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("use_windows_update"), &useWU)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source"), &source)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if useWU.IsNull() || useWU.IsUnknown() || useWU.ValueBool() || source.IsUnknown() {
+		return
+	}
+	if source.IsNull() || strings.TrimSpace(source.ValueString()) == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source"),
+			"Missing feature source",
+			"use_windows_update = false disables the Windows Update fallback, so the feature payload must come "+
+				"from source. Set source to an SxS folder or WIM image (e.g. \"wim:D:\\sources\\install.wim:2\"), "+
+				"or remove use_windows_update = false.",
+		)
+	}
+}
+
 // windowsFeatureSchemaDefinition returns the windows_feature schema.
 //
-// ForceNew (RequiresReplace) on name, include_sub_features and
-// include_management_tools because Install-WindowsFeature cannot retroactively
-// shrink the feature tree once those switches have been applied.
+// ForceNew (RequiresReplace) on name. include_sub_features and
+// include_management_tools force replacement only when turned off, because
+// Install-WindowsFeature cannot retroactively shrink the feature tree once
+// those switches have been applied; turning one on (or repairing drift found
+// by Read) re-runs the install in place.
 func windowsFeatureSchemaDefinition(ctx context.Context) schema.Schema {
 	return schema.Schema{
 		MarkdownDescription: "Manages installation/uninstallation of a Windows Server role or feature " +
@@ -123,24 +180,48 @@ func windowsFeatureSchemaDefinition(ctx context.Context) schema.Schema {
 			"include_sub_features": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "Install all sub-features (-IncludeAllSubFeature). Default false. ForceNew.",
+				Description: "Install all sub-features (-IncludeAllSubFeature). Default false. Read sets it to false when any of the sub-features (other than those in Removed state) are no longer installed, so out-of-band removal shows as drift. Turning it off forces replacement; turning it on re-runs the install in place.",
 				Default:     booldefault.StaticBool(false),
 				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+					featureSwitchRequiresReplace(),
 				},
 			},
 			"include_management_tools": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "Install management tools (-IncludeManagementTools). Default false. ForceNew.",
+				Description: "Install management tools (-IncludeManagementTools). Default false. Read sets it to false when the feature's sub-feature tree holds management tools and none of them is installed any more, so out-of-band removal shows as drift. Turning it off forces replacement; turning it on re-runs the install in place.",
 				Default:     booldefault.StaticBool(false),
 				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+					featureSwitchRequiresReplace(),
 				},
 			},
 			"source": schema.StringAttribute{
 				Optional:    true,
-				Description: "Optional SxS / WIM source path used when feature payload has been removed (-Source). Required when current install_state=Removed.",
+				Description: "Optional SxS / WIM source path used when feature payload has been removed (-Source). Required when current install_state=Removed or use_windows_update=false.",
+			},
+			"log_path": schema.StringAttribute{
+				Optional: true,
+				Description: "Path of a log file on the remote host that Install/Uninstall-WindowsFeature write " +
+					"details to (-LogPath), e.g. C:\\Windows\\Logs\\feature.log. Updatable in place.",
+				MarkdownDescription: "Path of a log file on the remote host that `Install-WindowsFeature` / " +
+					"`Uninstall-WindowsFeature` write details to (`-LogPath`), e.g. `C:\\Windows\\Logs\\feature.log`. " +
+					"Useful to diagnose offline installs from `source`. Updatable in place.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"use_windows_update": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Allow Install-WindowsFeature to download the feature payload from Windows Update when it is not " +
+					"available locally or from source. Default true. Set false on hosts without Windows Update access so a " +
+					"missing payload fails fast instead of hanging until the timeout; source is then required.",
+				MarkdownDescription: "Allow `Install-WindowsFeature` to download the feature payload from Windows Update " +
+					"when it is not available locally or from `source`. Default `true`.\n\n" +
+					"Set `false` on hosts without Windows Update access so a missing payload fails fast instead of " +
+					"hanging until the timeout. `source` is then required (checked at plan time), and the servicing " +
+					"policy `UseWindowsUpdate=2` is applied for the duration of the install only, then restored.",
+				Default: booldefault.StaticBool(true),
 			},
 			"restart": schema.BoolAttribute{
 				Optional:    true,
@@ -159,6 +240,34 @@ func windowsFeatureSchemaDefinition(ctx context.Context) schema.Schema {
 					stringvalidator.OneOf("Installed", "Available", "Removed"),
 				},
 			},
+			"managed": schema.BoolAttribute{
+				Computed: true,
+				Description: "True when Terraform installed the feature. False when the feature was already installed at " +
+					"create time or was imported (adopted).",
+				MarkdownDescription: "`true` when Terraform installed the feature. `false` when the feature was already " +
+					"installed at create time or was brought in with `terraform import` (adopted). Adopted features are " +
+					"not uninstalled on destroy unless `force_uninstall_adopted` is `true`.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"force_uninstall_adopted": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "Uninstall the feature on destroy even when managed is false. Default false.",
+				MarkdownDescription: "Uninstall the feature on destroy even when `managed` is `false`. Default `false`: destroying an adopted feature only removes it from state, so Terraform never removes a role it did not install.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"skip_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Never uninstall the feature on destroy: destroy only removes it from state. Takes precedence over force_uninstall_adopted. Default false.",
+				MarkdownDescription: "Never uninstall the feature on destroy: destroy only removes it from state, whether or not " +
+					"Terraform installed it. Takes precedence over `force_uninstall_adopted`. Use it for shared roles that " +
+					"must outlive the resource block. Default `false`.",
+				Default: booldefault.StaticBool(false),
+			},
+			"read_retries": readRetriesAttribute("feature"),
 
 			// Per-operation timeouts (terraform-plugin-framework-timeouts).
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
@@ -184,12 +293,15 @@ func (r *windowsFeatureResource) Configure(_ context.Context, req resource.Confi
 		return
 	}
 	r.feat = winclient.NewFeatureClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
 }
 
 // ImportState lets `terraform import windows_feature.foo Web-Server` work.
+// Imported features are adopted: managed is recorded as false.
 func (r *windowsFeatureResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("managed"), false)...)
 }
 
 // -----------------------------------------------------------------------------
@@ -203,7 +315,7 @@ func (r *windowsFeatureResource) Create(ctx context.Context, req resource.Create
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	createTimeout, diags := plan.Timeouts.Create(ctx, featureDefaultTimeout)
+	createTimeout, diags := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, featureDefaultTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -216,6 +328,8 @@ func (r *windowsFeatureResource) Create(ctx context.Context, req resource.Create
 		IncludeManagementTools: plan.IncludeManagementTools.ValueBool(),
 		Source:                 plan.Source.ValueString(),
 		Restart:                plan.Restart.ValueBool(),
+		SkipWindowsUpdate:      !featureUseWindowsUpdate(plan),
+		LogPath:                plan.LogPath.ValueString(),
 	}
 
 	tflog.Debug(ctx, "windows_feature Create", map[string]interface{}{
@@ -223,14 +337,16 @@ func (r *windowsFeatureResource) Create(ctx context.Context, req resource.Create
 		"include_sub_features":     in.IncludeSubFeatures,
 		"include_management_tools": in.IncludeManagementTools,
 		"restart":                  in.Restart,
+		"use_windows_update":       !in.SkipWindowsUpdate,
 	})
 
-	info, result, err := r.feat.Install(ctx, in)
+	info, result, err := r.installWithProgress(ctx, in)
 	if err != nil {
 		addFeatureDiag(&resp.Diagnostics, "Create windows_feature failed", err)
 		return
 	}
 	final := modelFromFeature(info, plan)
+	final.Managed = types.BoolValue(result == nil || !result.AlreadyInstalled)
 	applyInstallResult(&resp.Diagnostics, &final, plan, result)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
 }
@@ -248,7 +364,7 @@ func (r *windowsFeatureResource) Read(ctx context.Context, req resource.ReadRequ
 		name = state.ID.ValueString()
 	}
 	tflog.Debug(ctx, "windows_feature Read", map[string]interface{}{"name": name})
-	info, err := r.feat.Read(ctx, name)
+	info, err := r.feat.Read(withReadRetries(ctx, state.ReadRetries), name)
 	if err != nil {
 		addFeatureDiag(&resp.Diagnostics, "Read windows_feature failed", err)
 		return
@@ -258,11 +374,35 @@ func (r *windowsFeatureResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 	final := modelFromFeature(info, state)
+	if info.Installed {
+		readComponentDrift(ctx, info, &final)
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
 }
 
-// Update applies in-place changes. Only `source` and `restart` are mutable in
-// place. Re-running Install-WindowsFeature is idempotent and refreshes state.
+// readComponentDrift turns include_sub_features / include_management_tools
+// off in m when Read found the parts they installed no longer present, so the
+// next plan shows the drift and re-runs the install.
+func readComponentDrift(ctx context.Context, info *winclient.FeatureInfo, m *windowsFeatureModel) {
+	name := m.Name.ValueString()
+	if m.IncludeSubFeatures.ValueBool() && !info.AllSubFeaturesInstalled {
+		tflog.Info(ctx, "windows_feature sub-features removed out of band", map[string]interface{}{"name": name})
+		m.IncludeSubFeatures = types.BoolValue(false)
+	}
+	if m.IncludeManagementTools.ValueBool() && !info.ManagementToolsInstalled {
+		tflog.Info(ctx, "windows_feature management tools removed out of band", map[string]interface{}{"name": name})
+		m.IncludeManagementTools = types.BoolValue(false)
+	}
+}
+
+// Update applies in-place changes. Only `source`, `log_path`,
+// `use_windows_update`, `restart`, `force_uninstall_adopted`, `skip_destroy`, `read_retries` and `timeouts`
+// are mutable in place, and none of them changes what is installed, so they
+// are persisted to state without touching the host. Install-WindowsFeature
+// is re-run only when the install switches differ from state (an off-to-on
+// change, or drift repair after Read found components missing) or when prior
+// state no longer reports the feature as installed; a needless reinstall can
+// trigger a reboot.
 func (r *windowsFeatureResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan, prior windowsFeatureModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -270,7 +410,7 @@ func (r *windowsFeatureResource) Update(ctx context.Context, req resource.Update
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	updateTimeout, diags := plan.Timeouts.Update(ctx, featureDefaultTimeout)
+	updateTimeout, diags := plan.Timeouts.Update(ctx, operationTimeout(r.defaultTimeout, featureDefaultTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -281,37 +421,99 @@ func (r *windowsFeatureResource) Update(ctx context.Context, req resource.Update
 	if name == "" {
 		name = prior.Name.ValueString()
 	}
+	if !featureNeedsReinstall(plan, prior) {
+		tflog.Debug(ctx, "windows_feature Update: no install-affecting change, skipping reinstall",
+			map[string]interface{}{"name": name})
+		final := prior
+		final.Source = plan.Source
+		final.LogPath = plan.LogPath
+		final.UseWindowsUpdate = plan.UseWindowsUpdate
+		final.Restart = plan.Restart
+		final.ForceUninstallAdopted = plan.ForceUninstallAdopted
+		final.SkipDestroy = plan.SkipDestroy
+		final.ReadRetries = plan.ReadRetries
+		final.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+		return
+	}
 	in := winclient.FeatureInput{
 		Name:                   name,
 		IncludeSubFeatures:     plan.IncludeSubFeatures.ValueBool(),
 		IncludeManagementTools: plan.IncludeManagementTools.ValueBool(),
 		Source:                 plan.Source.ValueString(),
 		Restart:                plan.Restart.ValueBool(),
+		SkipWindowsUpdate:      !featureUseWindowsUpdate(plan),
+		LogPath:                plan.LogPath.ValueString(),
 	}
 	tflog.Debug(ctx, "windows_feature Update", map[string]interface{}{
 		"name":                     name,
 		"include_sub_features":     in.IncludeSubFeatures,
 		"include_management_tools": in.IncludeManagementTools,
 		"restart":                  in.Restart,
+		"use_windows_update":       !in.SkipWindowsUpdate,
 	})
-	info, result, err := r.feat.Install(ctx, in)
+	info, result, err := r.installWithProgress(ctx, in)
 	if err != nil {
 		addFeatureDiag(&resp.Diagnostics, "Update windows_feature failed", err)
 		return
 	}
 	final := modelFromFeature(info, plan)
+	final.Managed = types.BoolValue(featureManaged(prior))
+	if result != nil && !result.AlreadyInstalled {
+		// Terraform (re)installed a feature that was missing: it owns it now.
+		final.Managed = types.BoolValue(true)
+	}
 	applyInstallResult(&resp.Diagnostics, &final, plan, result)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
 }
 
+// featureSwitchRequiresReplace forces replacement when an install switch goes
+// from true to false: Install-WindowsFeature cannot remove what the switch
+// added. Going from false to true is applied in place by Update.
+func featureSwitchRequiresReplace() planmodifier.Bool {
+	return boolplanmodifier.RequiresReplaceIf(
+		func(_ context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = req.StateValue.ValueBool() && !req.PlanValue.ValueBool()
+		},
+		"Turning this off forces replacement of the resource; turning it on re-runs the install in place.",
+		"Turning this off forces replacement of the resource; turning it on re-runs the install in place.",
+	)
+}
+
+// featureNeedsReinstall reports whether Update must re-run
+// Install-WindowsFeature: an install switch changed, or prior state does not
+// show the feature as installed.
+func featureNeedsReinstall(plan, prior windowsFeatureModel) bool {
+	if !plan.IncludeSubFeatures.Equal(prior.IncludeSubFeatures) ||
+		!plan.IncludeManagementTools.Equal(prior.IncludeManagementTools) {
+		return true
+	}
+	return !prior.Installed.ValueBool()
+}
+
 // Delete uninstalls the feature. Idempotent: a vanished feature is success.
+// An adopted feature (managed=false) is only removed from state unless
+// force_uninstall_adopted is set; with skip_destroy no feature is uninstalled.
 func (r *windowsFeatureResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state windowsFeatureModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	deleteTimeout, diags := state.Timeouts.Delete(ctx, featureDefaultTimeout)
+	if state.SkipDestroy.ValueBool() {
+		tflog.Info(ctx, "windows_feature Delete: skip_destroy is set, leaving the feature installed",
+			map[string]interface{}{"name": state.Name.ValueString()})
+		return
+	}
+	if !featureManaged(state) && !state.ForceUninstallAdopted.ValueBool() {
+		resp.Diagnostics.AddWarning(
+			"Adopted feature left installed",
+			fmt.Sprintf("Feature %q was already installed when Terraform adopted it (managed = false), so destroy only "+
+				"removes it from state. Set force_uninstall_adopted = true to uninstall it.", state.Name.ValueString()),
+		)
+		return
+	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, operationTimeout(r.defaultTimeout, featureDefaultTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -326,6 +528,7 @@ func (r *windowsFeatureResource) Delete(ctx context.Context, req resource.Delete
 		Name:                   name,
 		IncludeManagementTools: state.IncludeManagementTools.ValueBool(),
 		Restart:                state.Restart.ValueBool(),
+		LogPath:                state.LogPath.ValueString(),
 	}
 	tflog.Debug(ctx, "windows_feature Delete", map[string]interface{}{
 		"name":    name,
@@ -352,7 +555,8 @@ func (r *windowsFeatureResource) Delete(ctx context.Context, req resource.Delete
 // -----------------------------------------------------------------------------
 
 // modelFromFeature projects a winclient.FeatureInfo onto a windowsFeatureModel,
-// preserving desired-input fields (include_*, source, restart) from prior plan.
+// preserving desired-input fields (include_*, source, log_path, restart) from
+// prior plan.
 func modelFromFeature(info *winclient.FeatureInfo, prior windowsFeatureModel) windowsFeatureModel {
 	out := windowsFeatureModel{
 		ID:                     types.StringValue(info.Name),
@@ -365,7 +569,13 @@ func modelFromFeature(info *winclient.FeatureInfo, prior windowsFeatureModel) wi
 		IncludeSubFeatures:     prior.IncludeSubFeatures,
 		IncludeManagementTools: prior.IncludeManagementTools,
 		Source:                 prior.Source,
+		LogPath:                prior.LogPath,
+		UseWindowsUpdate:       prior.UseWindowsUpdate,
 		Restart:                prior.Restart,
+		Managed:                prior.Managed,
+		ForceUninstallAdopted:  prior.ForceUninstallAdopted,
+		SkipDestroy:            prior.SkipDestroy,
+		ReadRetries:            prior.ReadRetries,
 		// Preserve the user-configured per-operation timeouts across the
 		// projection (Set overwrites the full state object).
 		Timeouts: prior.Timeouts,
@@ -379,9 +589,36 @@ func modelFromFeature(info *winclient.FeatureInfo, prior windowsFeatureModel) wi
 	if out.Restart.IsNull() || out.Restart.IsUnknown() {
 		out.Restart = types.BoolValue(false)
 	}
+	if out.UseWindowsUpdate.IsNull() || out.UseWindowsUpdate.IsUnknown() {
+		out.UseWindowsUpdate = types.BoolValue(true)
+	}
+	out.Managed = types.BoolValue(featureManaged(prior))
+	if out.ForceUninstallAdopted.IsNull() || out.ForceUninstallAdopted.IsUnknown() {
+		out.ForceUninstallAdopted = types.BoolValue(false)
+	}
+	if out.SkipDestroy.IsNull() || out.SkipDestroy.IsUnknown() {
+		out.SkipDestroy = types.BoolValue(false)
+	}
 	return out
 }
 
+// featureManaged returns the managed marker. ImportState records false
+// explicitly, so a null marker only comes from state written before the
+// marker existed; those resources went through Create and keep the old
+// uninstall-on-destroy behaviour.
+func featureManaged(m windowsFeatureModel) bool {
+	return m.Managed.IsNull() || m.Managed.IsUnknown() || m.Managed.ValueBool()
+}
+
+// featureUseWindowsUpdate returns use_windows_update, treating null/unknown
+// as the default (true).
+func featureUseWindowsUpdate(m windowsFeatureModel) bool {
+	if m.UseWindowsUpdate.IsNull() || m.UseWindowsUpdate.IsUnknown() {
+		return true
+	}
+	return m.UseWindowsUpdate.ValueBool()
+}
+
 // applyInstallResult overwrites RestartPending from the install result and
 // emits a warning when a reboot is required but `restart` is disabled.
 func applyInstallResult(diags *diag.Diagnostics, m *windowsFeatureModel, plan windowsFeatureModel, result *winclient.InstallResult) {
@@ -420,3 +657,55 @@ func addFeatureDiag(diags *diag.Diagnostics, summary string, err error) {
 	}
 	diags.AddError(summary, err.Error())
 }
+
+// installWithProgress runs Install while a background poller reads the
+// feature's install state every featureProgressInterval and logs it at Info,
+// so TF_LOG shows a multi-minute role install advancing. Each poll is its own
+// WinRM command; the poller stops as soon as Install returns.
+func (r *windowsFeatureResource) installWithProgress(ctx context.Context, in winclient.FeatureInput) (*winclient.FeatureInfo, *winclient.InstallResult, error) {
+	pollCtx, stop := context.WithCancel(ctx)
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		pollFeatureProgress(pollCtx, r.feat, in.Name)
+	}()
+	info, result, err := r.feat.Install(ctx, in)
+	stop()
+	<-polled
+	return info, result, err
+}
+
+// pollFeatureProgress logs the install state of name until ctx is done. A
+// failed poll is logged at Debug and does not affect the install. Polls
+// bypass the enable_read_batching snapshot, which would keep serving the
+// state from before the install until it returns.
+func pollFeatureProgress(ctx context.Context, feat winclient.WindowsFeatureClient, name string) {
+	start := time.Now()
+	ticker := time.NewTicker(featureProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := feat.Read(winclient.WithoutReadBatching(ctx), name)
+		if ctx.Err() != nil {
+			return
+		}
+		fields := map[string]interface{}{
+			"name":    name,
+			"elapsed": time.Since(start).Round(time.Second).String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+			tflog.Debug(ctx, "windows_feature install progress poll failed", fields)
+			continue
+		}
+		fields["install_state"] = "Unknown"
+		if info != nil {
+			fields["install_state"] = info.InstallState
+		}
+		tflog.Info(ctx, "windows_feature install in progress", fields)
+	}
+}
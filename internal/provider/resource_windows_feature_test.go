@@ -22,11 +22,14 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
@@ -214,6 +217,9 @@ func TestModelFromFeature_NullDefaults(t *testing.T) {
 	if got.Restart.ValueBool() {
 		t.Error("Restart default should be false")
 	}
+	if !got.UseWindowsUpdate.ValueBool() {
+		t.Error("UseWindowsUpdate default should be true")
+	}
 }
 
 func TestApplyInstallResult_RestartWarning_EC4(t *testing.T) {
@@ -291,6 +297,21 @@ func TestFeatureConfigure_Valid(t *testing.T) {
 	}
 }
 
+func TestFeatureConfigure_ProviderDefaultTimeout(t *testing.T) {
+	c, err := winclient.New(winclient.Config{Host: "h", Username: "u", Password: "p", DefaultCommandTimeout: 90 * time.Minute})
+	if err != nil {
+		t.Fatalf("winclient.New: %v", err)
+	}
+	r := &windowsFeatureResource{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: c}, &resource.ConfigureResponse{})
+	if got := operationTimeout(r.defaultTimeout, featureDefaultTimeout); got != 90*time.Minute {
+		t.Errorf("effective default timeout = %v, want the provider's 90m", got)
+	}
+	if got := operationTimeout((&windowsFeatureResource{}).defaultTimeout, featureDefaultTimeout); got != featureDefaultTimeout {
+		t.Errorf("unconfigured resource timeout = %v, want %v", got, featureDefaultTimeout)
+	}
+}
+
 func TestNewWindowsFeatureResource_NotNil(t *testing.T) {
 	if NewWindowsFeatureResource() == nil {
 		t.Fatal("constructor must not return nil")
@@ -317,6 +338,9 @@ type fakeFeatureClient struct {
 func (f *fakeFeatureClient) Read(_ context.Context, _ string) (*winclient.FeatureInfo, error) {
 	return f.readOut, f.readErr
 }
+func (f *fakeFeatureClient) ReadCached(_ context.Context, _ string) (*winclient.FeatureInfo, error) {
+	panic("the resource must read fresh state, not ReadCached")
+}
 func (f *fakeFeatureClient) Install(_ context.Context, in winclient.FeatureInput) (*winclient.FeatureInfo, *winclient.InstallResult, error) {
 	f.installIn = in
 	return f.installOut, f.installRes, f.installErr
@@ -340,9 +364,15 @@ func featureObjectType() tftypes.Object {
 		"include_sub_features":     tftypes.Bool,
 		"include_management_tools": tftypes.Bool,
 		"source":                   tftypes.String,
+		"use_windows_update":       tftypes.Bool,
 		"restart":                  tftypes.Bool,
 		"restart_pending":          tftypes.Bool,
 		"install_state":            tftypes.String,
+		"managed":                  tftypes.Bool,
+		"force_uninstall_adopted":  tftypes.Bool,
+		"skip_destroy":             tftypes.Bool,
+		"read_retries":             tftypes.Number,
+		"log_path":                 tftypes.String,
 		"timeouts": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
 			"create": tftypes.String,
 			"update": tftypes.String,
@@ -371,9 +401,15 @@ func featObj(overrides map[string]tftypes.Value) tftypes.Value {
 		"include_sub_features":     tftypes.NewValue(tftypes.Bool, false),
 		"include_management_tools": tftypes.NewValue(tftypes.Bool, false),
 		"source":                   tftypes.NewValue(tftypes.String, nil),
+		"use_windows_update":       tftypes.NewValue(tftypes.Bool, true),
 		"restart":                  tftypes.NewValue(tftypes.Bool, false),
 		"restart_pending":          tftypes.NewValue(tftypes.Bool, nil),
 		"install_state":            tftypes.NewValue(tftypes.String, nil),
+		"managed":                  tftypes.NewValue(tftypes.Bool, nil),
+		"force_uninstall_adopted":  tftypes.NewValue(tftypes.Bool, false),
+		"skip_destroy":             tftypes.NewValue(tftypes.Bool, false),
+		"read_retries":             tftypes.NewValue(tftypes.Number, nil),
+		"log_path":                 tftypes.NewValue(tftypes.String, nil),
 		"timeouts":                 featureNullTimeoutsValue(),
 	}
 	for k, v := range overrides {
@@ -386,6 +422,7 @@ func okFeatureInfo() *winclient.FeatureInfo {
 	return &winclient.FeatureInfo{
 		Name: "Web-Server", DisplayName: "Web Server", Description: "IIS",
 		Installed: true, InstallState: "Installed", RestartPending: false,
+		AllSubFeaturesInstalled: true, ManagementToolsInstalled: true,
 	}
 }
 
@@ -408,6 +445,7 @@ func TestFeatureCreate_Handler_HappyPath(t *testing.T) {
 			"include_sub_features":     tftypes.NewValue(tftypes.Bool, true),
 			"include_management_tools": tftypes.NewValue(tftypes.Bool, true),
 			"source":                   tftypes.NewValue(tftypes.String, `\\srv\sxs`),
+			"log_path":                 tftypes.NewValue(tftypes.String, `C:\Windows\Logs\iis.log`),
 			"restart":                  tftypes.NewValue(tftypes.Bool, false),
 		}),
 	}
@@ -427,6 +465,45 @@ func TestFeatureCreate_Handler_HappyPath(t *testing.T) {
 	if fake.installIn.Source != `\\srv\sxs` {
 		t.Errorf("Source not propagated: %q", fake.installIn.Source)
 	}
+	if fake.installIn.LogPath != `C:\Windows\Logs\iis.log` {
+		t.Errorf("LogPath not propagated: %q", fake.installIn.LogPath)
+	}
+	if fake.installIn.SkipWindowsUpdate {
+		t.Error("use_windows_update defaults to true; SkipWindowsUpdate must be false")
+	}
+}
+
+func TestFeatureCreate_Handler_NoWindowsUpdate(t *testing.T) {
+	fake := &fakeFeatureClient{
+		installOut: okFeatureInfo(),
+		installRes: &winclient.InstallResult{Success: true, ExitCode: "Success"},
+	}
+	r := &windowsFeatureResource{feat: fake}
+
+	schemaDef := windowsFeatureSchemaDefinition(context.Background())
+	plan := tfsdk.Plan{
+		Schema: schemaDef,
+		Raw: featObj(map[string]tftypes.Value{
+			"name":               tftypes.NewValue(tftypes.String, "NET-Framework-Core"),
+			"source":             tftypes.NewValue(tftypes.String, `D:\sources\sxs`),
+			"use_windows_update": tftypes.NewValue(tftypes.Bool, false),
+		}),
+	}
+	resp := &resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaDef, Raw: featObj(nil)},
+	}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if !fake.installIn.SkipWindowsUpdate {
+		t.Error("use_windows_update=false must set SkipWindowsUpdate")
+	}
+	var got windowsFeatureModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if got.UseWindowsUpdate.ValueBool() {
+		t.Error("use_windows_update=false must be persisted to state")
+	}
 }
 
 func TestFeatureCreate_Handler_RestartWarning_EC4(t *testing.T) {
@@ -569,6 +646,109 @@ func TestFeatureRead_Handler_HappyPath(t *testing.T) {
 	}
 }
 
+// readFeatureWithSwitches runs Read on a Web-Server state with both install
+// switches on and returns the response and the refreshed model.
+func readFeatureWithSwitches(t *testing.T, fake *fakeFeatureClient) (*resource.ReadResponse, windowsFeatureModel) {
+	t.Helper()
+	r := &windowsFeatureResource{feat: fake}
+	schemaDef := windowsFeatureSchemaDefinition(context.Background())
+	prior := tfsdk.State{
+		Schema: schemaDef,
+		Raw: featObj(map[string]tftypes.Value{
+			"id":                       tftypes.NewValue(tftypes.String, "Web-Server"),
+			"name":                     tftypes.NewValue(tftypes.String, "Web-Server"),
+			"include_sub_features":     tftypes.NewValue(tftypes.Bool, true),
+			"include_management_tools": tftypes.NewValue(tftypes.Bool, true),
+		}),
+	}
+	resp := &resource.ReadResponse{
+		State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()},
+	}
+	r.Read(context.Background(), resource.ReadRequest{State: prior}, resp)
+	var got windowsFeatureModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+func TestFeatureRead_Handler_ComponentDrift(t *testing.T) {
+	fake := &fakeFeatureClient{readOut: okFeatureInfo()}
+	resp, got := readFeatureWithSwitches(t, fake)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if !got.IncludeSubFeatures.ValueBool() || !got.IncludeManagementTools.ValueBool() {
+		t.Errorf("complete feature tree must keep both switches on: %+v", got)
+	}
+
+	info := okFeatureInfo()
+	info.ManagementToolsInstalled = false
+	_, got = readFeatureWithSwitches(t, &fakeFeatureClient{readOut: info})
+	if !got.IncludeSubFeatures.ValueBool() || got.IncludeManagementTools.ValueBool() {
+		t.Errorf("removed management tools must show as include_management_tools=false only: %+v", got)
+	}
+
+	info = okFeatureInfo()
+	info.AllSubFeaturesInstalled = false
+	_, got = readFeatureWithSwitches(t, &fakeFeatureClient{readOut: info})
+	if got.IncludeSubFeatures.ValueBool() || !got.IncludeManagementTools.ValueBool() {
+		t.Errorf("removed sub-feature must show as include_sub_features=false only: %+v", got)
+	}
+}
+
+func TestFeatureRead_Handler_ComponentDriftSkipped(t *testing.T) {
+	// Switches off (the featObj default): missing components are not drift.
+	info := okFeatureInfo()
+	info.AllSubFeaturesInstalled, info.ManagementToolsInstalled = false, false
+	r := &windowsFeatureResource{feat: &fakeFeatureClient{readOut: info}}
+	schemaDef := windowsFeatureSchemaDefinition(context.Background())
+	prior := tfsdk.State{Schema: schemaDef, Raw: featObj(map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "Web-Server"),
+		"name": tftypes.NewValue(tftypes.String, "Web-Server"),
+	})}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: prior}, resp)
+	var got windowsFeatureModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if resp.Diagnostics.HasError() || got.IncludeSubFeatures.ValueBool() || got.IncludeManagementTools.ValueBool() {
+		t.Errorf("switches off must stay off: %+v %v", got, resp.Diagnostics)
+	}
+
+	// Feature itself not installed: the Installed drift already drives a
+	// reinstall, the switches are left as in state.
+	info = okFeatureInfo()
+	info.Installed, info.InstallState = false, "Available"
+	info.AllSubFeaturesInstalled, info.ManagementToolsInstalled = false, false
+	_, got = readFeatureWithSwitches(t, &fakeFeatureClient{readOut: info})
+	if !got.IncludeSubFeatures.ValueBool() || !got.IncludeManagementTools.ValueBool() {
+		t.Errorf("uninstalled feature: switches must be left as in state: %+v", got)
+	}
+}
+
+func TestFeatureSwitchRequiresReplace(t *testing.T) {
+	cases := []struct {
+		state, plan bool
+		want        bool
+	}{
+		{true, false, true},
+		{false, true, false},
+	}
+	for _, tc := range cases {
+		req := planmodifier.BoolRequest{
+			StateValue: types.BoolValue(tc.state),
+			PlanValue:  types.BoolValue(tc.plan),
+			State:      tfsdk.State{Raw: tftypes.NewValue(tftypes.Bool, true)},
+			Plan:       tfsdk.Plan{Raw: tftypes.NewValue(tftypes.Bool, true)},
+		}
+		resp := &planmodifier.BoolResponse{PlanValue: req.PlanValue}
+		featureSwitchRequiresReplace().PlanModifyBool(context.Background(), req, resp)
+		if resp.RequiresReplace != tc.want {
+			t.Errorf("%v -> %v: RequiresReplace = %v, want %v", tc.state, tc.plan, resp.RequiresReplace, tc.want)
+		}
+	}
+}
+
 func TestFeatureRead_Handler_DriftRemoved_EC2(t *testing.T) {
 	fake := &fakeFeatureClient{readOut: nil, readErr: nil}
 	r := &windowsFeatureResource{feat: fake}
@@ -675,6 +855,88 @@ func TestFeatureUpdate_Handler_HappyPath(t *testing.T) {
 	}
 }
 
+func TestFeatureUpdate_Handler_NonDestructiveChangeSkipsReinstall(t *testing.T) {
+	fake := &fakeFeatureClient{installErr: errors.New("Install must not be called")}
+	r := &windowsFeatureResource{feat: fake}
+	schemaDef := windowsFeatureSchemaDefinition(context.Background())
+	installed := map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "Web-Server"),
+		"name":            tftypes.NewValue(tftypes.String, "Web-Server"),
+		"display_name":    tftypes.NewValue(tftypes.String, "Web Server"),
+		"description":     tftypes.NewValue(tftypes.String, "IIS"),
+		"installed":       tftypes.NewValue(tftypes.Bool, true),
+		"restart_pending": tftypes.NewValue(tftypes.Bool, false),
+		"install_state":   tftypes.NewValue(tftypes.String, "Installed"),
+	}
+	prior := tfsdk.State{Schema: schemaDef, Raw: featObj(installed)}
+
+	planVals := map[string]tftypes.Value{}
+	for k, v := range installed {
+		planVals[k] = v
+	}
+	planVals["restart"] = tftypes.NewValue(tftypes.Bool, true)
+	planVals["read_retries"] = tftypes.NewValue(tftypes.Number, 3)
+	planVals["timeouts"] = tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"create": tftypes.String,
+		"update": tftypes.String,
+		"delete": tftypes.String,
+	}}, map[string]tftypes.Value{
+		"create": tftypes.NewValue(tftypes.String, nil),
+		"update": tftypes.NewValue(tftypes.String, "45m"),
+		"delete": tftypes.NewValue(tftypes.String, nil),
+	})
+	plan := tfsdk.Plan{Schema: schemaDef, Raw: featObj(planVals)}
+
+	resp := &resource.UpdateResponse{
+		State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()},
+	}
+	r.Update(context.Background(),
+		resource.UpdateRequest{Plan: plan, State: prior}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if fake.installIn.Name != "" {
+		t.Errorf("Install called for a non-destructive change: %+v", fake.installIn)
+	}
+	var got windowsFeatureModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if !got.Restart.ValueBool() {
+		t.Error("restart change not persisted to state")
+	}
+	if got.ReadRetries.ValueInt64() != 3 {
+		t.Errorf("read_retries change not persisted to state: %v", got.ReadRetries)
+	}
+	if got.DisplayName.ValueString() != "Web Server" || !got.Installed.ValueBool() {
+		t.Errorf("computed attributes not carried over from prior state: %+v", got)
+	}
+}
+
+func TestFeatureNeedsReinstall(t *testing.T) {
+	base := windowsFeatureModel{
+		IncludeSubFeatures:     types.BoolValue(false),
+		IncludeManagementTools: types.BoolValue(false),
+		Installed:              types.BoolValue(true),
+	}
+	if featureNeedsReinstall(base, base) {
+		t.Error("identical install switches on an installed feature must not reinstall")
+	}
+	changed := base
+	changed.IncludeManagementTools = types.BoolValue(true)
+	if !featureNeedsReinstall(changed, base) {
+		t.Error("include_management_tools change must reinstall")
+	}
+	changed = base
+	changed.IncludeSubFeatures = types.BoolValue(true)
+	if !featureNeedsReinstall(changed, base) {
+		t.Error("include_sub_features change must reinstall")
+	}
+	notInstalled := base
+	notInstalled.Installed = types.BoolValue(false)
+	if !featureNeedsReinstall(base, notInstalled) {
+		t.Error("feature no longer installed in prior state must reinstall")
+	}
+}
+
 func TestFeatureDelete_Handler_HappyPath(t *testing.T) {
 	fake := &fakeFeatureClient{
 		uninstOut: nil,
@@ -773,6 +1035,108 @@ func TestFeatureDelete_Handler_PermissionDenied(t *testing.T) {
 	}
 }
 
+func TestFeatureCreate_Handler_Managed(t *testing.T) {
+	for _, already := range []bool{false, true} {
+		fake := &fakeFeatureClient{
+			installOut: okFeatureInfo(),
+			installRes: &winclient.InstallResult{Success: true, AlreadyInstalled: already},
+		}
+		r := &windowsFeatureResource{feat: fake}
+		schemaDef := windowsFeatureSchemaDefinition(context.Background())
+		plan := tfsdk.Plan{Schema: schemaDef, Raw: featObj(map[string]tftypes.Value{
+			"name":    tftypes.NewValue(tftypes.String, "Web-Server"),
+			"managed": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+		})}
+		resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: featObj(nil)}}
+		r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		var m windowsFeatureModel
+		resp.State.Get(context.Background(), &m)
+		if m.Managed.ValueBool() == already {
+			t.Errorf("already_installed=%v: managed = %v", already, m.Managed.ValueBool())
+		}
+	}
+}
+
+func TestFeatureUpdate_Handler_ReinstallTakesOwnership(t *testing.T) {
+	fake := &fakeFeatureClient{
+		installOut: okFeatureInfo(),
+		installRes: &winclient.InstallResult{Success: true},
+	}
+	r := &windowsFeatureResource{feat: fake}
+	schemaDef := windowsFeatureSchemaDefinition(context.Background())
+	prior := featObj(map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, "Web-Server"),
+		"name":      tftypes.NewValue(tftypes.String, "Web-Server"),
+		"installed": tftypes.NewValue(tftypes.Bool, false),
+		"managed":   tftypes.NewValue(tftypes.Bool, false),
+	})
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Copy()}}
+	r.Update(context.Background(), resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: schemaDef, Raw: prior.Copy()},
+		State: tfsdk.State{Schema: schemaDef, Raw: prior.Copy()},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var m windowsFeatureModel
+	resp.State.Get(context.Background(), &m)
+	if !m.Managed.ValueBool() {
+		t.Error("a feature Terraform had to reinstall must become managed")
+	}
+}
+
+func TestFeatureDelete_Handler_AdoptedIsKept(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		fake := &fakeFeatureClient{uninstRes: &winclient.InstallResult{Success: true}}
+		r := &windowsFeatureResource{feat: fake}
+		schemaDef := windowsFeatureSchemaDefinition(context.Background())
+		prior := tfsdk.State{Schema: schemaDef, Raw: featObj(map[string]tftypes.Value{
+			"id":                      tftypes.NewValue(tftypes.String, "Web-Server"),
+			"name":                    tftypes.NewValue(tftypes.String, "Web-Server"),
+			"managed":                 tftypes.NewValue(tftypes.Bool, false),
+			"force_uninstall_adopted": tftypes.NewValue(tftypes.Bool, force),
+		})}
+		resp := &resource.DeleteResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+		r.Delete(context.Background(), resource.DeleteRequest{State: prior}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		uninstalled := fake.uninstIn.Name != ""
+		if uninstalled != force {
+			t.Errorf("force_uninstall_adopted=%v: uninstall called = %v", force, uninstalled)
+		}
+		if !force && resp.Diagnostics.WarningsCount() != 1 {
+			t.Errorf("expected an 'adopted feature left installed' warning, got %v", resp.Diagnostics)
+		}
+	}
+}
+
+func TestFeatureDelete_Handler_SkipDestroy(t *testing.T) {
+	for _, managed := range []bool{true, false} {
+		fake := &fakeFeatureClient{uninstRes: &winclient.InstallResult{Success: true}}
+		r := &windowsFeatureResource{feat: fake}
+		schemaDef := windowsFeatureSchemaDefinition(context.Background())
+		prior := tfsdk.State{Schema: schemaDef, Raw: featObj(map[string]tftypes.Value{
+			"id":                      tftypes.NewValue(tftypes.String, "Web-Server"),
+			"name":                    tftypes.NewValue(tftypes.String, "Web-Server"),
+			"managed":                 tftypes.NewValue(tftypes.Bool, managed),
+			"force_uninstall_adopted": tftypes.NewValue(tftypes.Bool, true),
+			"skip_destroy":            tftypes.NewValue(tftypes.Bool, true),
+		})}
+		resp := &resource.DeleteResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+		r.Delete(context.Background(), resource.DeleteRequest{State: prior}, resp)
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 0 {
+			t.Fatalf("managed=%v: diags: %v", managed, resp.Diagnostics)
+		}
+		if fake.uninstIn.Name != "" {
+			t.Errorf("managed=%v: skip_destroy must not uninstall", managed)
+		}
+	}
+}
+
 // -----------------------------------------------------------------------------
 // ImportState
 // -----------------------------------------------------------------------------
@@ -796,6 +1160,9 @@ func TestFeatureImportState_Handler(t *testing.T) {
 	if m.ID.ValueString() != "Web-Server" || m.Name.ValueString() != "Web-Server" {
 		t.Errorf("import state = id=%q name=%q", m.ID.ValueString(), m.Name.ValueString())
 	}
+	if m.Managed.IsNull() || m.Managed.ValueBool() {
+		t.Error("imported features are adopted: managed must be false")
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -809,3 +1176,92 @@ func diagDetails(d diag.Diagnostics) []string {
 	}
 	return out
 }
+
+// -----------------------------------------------------------------------------
+// featureSourceRequiredValidator
+// -----------------------------------------------------------------------------
+
+func TestFeatureSourceRequiredValidator(t *testing.T) {
+	schemaDef := windowsFeatureSchemaDefinition(context.Background())
+	cases := []struct {
+		name    string
+		useWU   tftypes.Value
+		source  tftypes.Value
+		wantErr bool
+	}{
+		{"default", tftypes.NewValue(tftypes.Bool, nil), tftypes.NewValue(tftypes.String, nil), false},
+		{"wu true no source", tftypes.NewValue(tftypes.Bool, true), tftypes.NewValue(tftypes.String, nil), false},
+		{"wu false with source", tftypes.NewValue(tftypes.Bool, false), tftypes.NewValue(tftypes.String, `D:\sources\sxs`), false},
+		{"wu false unknown source", tftypes.NewValue(tftypes.Bool, false), tftypes.NewValue(tftypes.String, tftypes.UnknownValue), false},
+		{"wu false no source", tftypes.NewValue(tftypes.Bool, false), tftypes.NewValue(tftypes.String, nil), true},
+		{"wu false blank source", tftypes.NewValue(tftypes.Bool, false), tftypes.NewValue(tftypes.String, "  "), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tfsdk.Config{Schema: schemaDef, Raw: featObj(map[string]tftypes.Value{
+				"name":               tftypes.NewValue(tftypes.String, "NET-Framework-Core"),
+				"use_windows_update": tc.useWU,
+				"source":             tc.source,
+			})}
+			resp := &resource.ValidateConfigResponse{}
+			featureSourceRequiredValidator{}.ValidateResource(context.Background(), resource.ValidateConfigRequest{Config: cfg}, resp)
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("HasError = %v, want %v: %v", resp.Diagnostics.HasError(), tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFeatureConfigValidators_Registered(t *testing.T) {
+	r := &windowsFeatureResource{}
+	vs := r.ConfigValidators(context.Background())
+	if len(vs) != 1 {
+		t.Fatalf("ConfigValidators = %d, want 1", len(vs))
+	}
+	if !strings.Contains(vs[0].Description(context.Background()), "use_windows_update") {
+		t.Errorf("unexpected validator: %q", vs[0].Description(context.Background()))
+	}
+}
+
+// progressFeatureClient blocks Install until release is closed and counts the
+// progress polls issued meanwhile.
+type progressFeatureClient struct {
+	fakeFeatureClient
+	release chan struct{}
+	reads   atomic.Int32
+}
+
+func (f *progressFeatureClient) Read(_ context.Context, name string) (*winclient.FeatureInfo, error) {
+	f.reads.Add(1)
+	return &winclient.FeatureInfo{Name: name, InstallState: "InstallPending"}, nil
+}
+
+func (f *progressFeatureClient) Install(_ context.Context, in winclient.FeatureInput) (*winclient.FeatureInfo, *winclient.InstallResult, error) {
+	<-f.release
+	return &winclient.FeatureInfo{Name: in.Name, Installed: true, InstallState: "Installed"}, &winclient.InstallResult{Success: true}, nil
+}
+
+func TestInstallWithProgress_PollsUntilInstallReturns(t *testing.T) {
+	prev := featureProgressInterval
+	featureProgressInterval = 5 * time.Millisecond
+	defer func() { featureProgressInterval = prev }()
+
+	fake := &progressFeatureClient{release: make(chan struct{})}
+	r := &windowsFeatureResource{feat: fake}
+	go func() {
+		time.Sleep(40 * time.Millisecond)
+		close(fake.release)
+	}()
+	info, result, err := r.installWithProgress(context.Background(), winclient.FeatureInput{Name: "Web-Server"})
+	if err != nil || info == nil || !info.Installed || result == nil || !result.Success {
+		t.Fatalf("installWithProgress = %+v, %+v, %v", info, result, err)
+	}
+	polls := fake.reads.Load()
+	if polls == 0 {
+		t.Fatal("expected progress polls while the install was running")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if after := fake.reads.Load(); after != polls {
+		t.Errorf("polling continued after Install returned: %d -> %d", polls, after)
+	}
+}
@@ -0,0 +1,264 @@
+// Package provider: windows_group_policy_refresh resource implementation.
+//
+// windows_group_policy_refresh runs gpupdate /force on create (and again
+// whenever triggers change, which forces replacement), typically after
+// registry or security policy changes that Group Policy should pick up. Like
+// windows_reboot it owns nothing on the host: Read and Delete are no-ops.
+// WinRM interaction is delegated to winclient.GroupPolicyClient
+// (internal/winclient/group_policy.go).
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource              = (*windowsGroupPolicyRefreshResource)(nil)
+	_ resource.ResourceWithConfigure = (*windowsGroupPolicyRefreshResource)(nil)
+)
+
+// groupPolicyRefreshDefaultTimeout is the fallback create timeout when the
+// user does not provide a `timeouts {}` block. It covers the default
+// wait_seconds with room for the WinRM round trip.
+const groupPolicyRefreshDefaultTimeout = 15 * time.Minute
+
+// defaultGPUpdateWaitSeconds matches gpupdate's own /wait default.
+const defaultGPUpdateWaitSeconds = 600
+
+// NewWindowsGroupPolicyRefreshResource is the constructor registered in
+// provider.go.
+func NewWindowsGroupPolicyRefreshResource() resource.Resource {
+	return &windowsGroupPolicyRefreshResource{}
+}
+
+// windowsGroupPolicyRefreshResource is the TPF resource type for
+// windows_group_policy_refresh.
+type windowsGroupPolicyRefreshResource struct {
+	client winclient.WindowsGroupPolicyClient
+	// defaultTimeout is the provider-level default_command_timeout; zero
+	// means groupPolicyRefreshDefaultTimeout.
+	defaultTimeout time.Duration
+}
+
+// windowsGroupPolicyRefreshModel is the Terraform state/plan model for
+// windows_group_policy_refresh.
+type windowsGroupPolicyRefreshModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Triggers    types.Map      `tfsdk:"triggers"`
+	Target      types.String   `tfsdk:"target"`
+	WaitSeconds types.Int64    `tfsdk:"wait_seconds"`
+	LastRefresh types.String   `tfsdk:"last_refresh"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+// windowsGroupPolicyRefreshSchemaDefinition returns the schema.Schema for
+// windows_group_policy_refresh.
+func windowsGroupPolicyRefreshSchemaDefinition(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Refreshes Group Policy on the remote Windows host (`gpupdate /force`). The refresh " +
+			"happens when the resource is created and whenever `triggers` changes; destroying the resource does " +
+			"nothing on the host.\n\n" +
+			"A refresh that gpupdate reports as failed (non-zero exit code, or a \"could not be updated " +
+			"successfully\" line) fails the apply with gpupdate's output.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "The `last_refresh` time recorded by the refresh that created this resource instance.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Arbitrary map of values that, when changed, refresh Group Policy again. " +
+					"Reference attributes of upstream resources (e.g. a registry value's `id`) to refresh after they change. " +
+					"**ForceNew.**",
+			},
+			"target": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(winclient.GroupPolicyTargetComputer, winclient.GroupPolicyTargetUser),
+				},
+				MarkdownDescription: "Refresh only `Computer` or only `User` policy (`/target`). User policy is that of " +
+					"the WinRM account. Default: both.",
+			},
+			"wait_seconds": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(defaultGPUpdateWaitSeconds),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(-1),
+				},
+				MarkdownDescription: "Seconds gpupdate waits for policy processing to finish (`/wait`); processing " +
+					"continues in the background after that. `0` returns at once, `-1` waits indefinitely. " +
+					"Keep the create timeout above this value. Default: `600`.",
+			},
+			"last_refresh": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Time gpupdate returned on the host, RFC 3339 in UTC.",
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+// Metadata sets the resource type name ("windows_group_policy_refresh").
+func (r *windowsGroupPolicyRefreshResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_policy_refresh"
+}
+
+// Schema returns the full TPF schema for windows_group_policy_refresh.
+func (r *windowsGroupPolicyRefreshResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsGroupPolicyRefreshSchemaDefinition(ctx)
+}
+
+// Configure extracts the shared *winclient.Client from provider data and
+// constructs the GroupPolicyClient.
+func (r *windowsGroupPolicyRefreshResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.client = winclient.NewGroupPolicyClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
+}
+
+// Create runs gpupdate /force.
+func (r *windowsGroupPolicyRefreshResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsGroupPolicyRefreshModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, groupPolicyRefreshDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	in := winclient.GroupPolicyRefreshInput{
+		Target:      plan.Target.ValueString(),
+		WaitSeconds: plan.WaitSeconds.ValueInt64(),
+	}
+	tflog.Info(ctx, "windows_group_policy_refresh Create: running gpupdate", map[string]interface{}{
+		"target": in.Target, "wait_seconds": in.WaitSeconds,
+	})
+
+	res, err := r.client.Refresh(ctx, in)
+	if err != nil {
+		addGroupPolicyDiag(&resp.Diagnostics, "Group Policy refresh failed", err)
+		return
+	}
+
+	tflog.Debug(ctx, "windows_group_policy_refresh Create: gpupdate output", map[string]interface{}{
+		"output": res.Output,
+	})
+	if res.LogoffRequired || res.RestartRequired {
+		when := "logon"
+		if res.RestartRequired {
+			when = "startup"
+		}
+		resp.Diagnostics.AddWarning("Some Group Policy settings apply only at the next "+when,
+			"gpupdate refreshed policy, but reported settings that only run during "+when+". "+
+				"Add a windows_reboot (with triggers referencing this resource's id) to apply them now.\n\n"+res.Output)
+	}
+
+	refreshed := types.StringValue(res.RefreshedAt.UTC().Format(time.RFC3339))
+	plan.ID = refreshed
+	plan.LastRefresh = refreshed
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the recorded state: a later refresh by the host's own policy
+// cycle is not drift.
+func (r *windowsGroupPolicyRefreshResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsGroupPolicyRefreshModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only records target, wait_seconds and timeouts changes; triggers is
+// ForceNew, so gpupdate is not run.
+func (r *windowsGroupPolicyRefreshResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state windowsGroupPolicyRefreshModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = state.ID
+	plan.LastRefresh = state.LastRefresh
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state.
+func (r *windowsGroupPolicyRefreshResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	tflog.Debug(ctx, "windows_group_policy_refresh Delete: nothing to do on the host")
+}
+
+// addGroupPolicyDiag converts a winclient error into a Terraform diagnostic.
+func addGroupPolicyDiag(diags *diag.Diagnostics, summary string, err error) {
+	var ge *winclient.GroupPolicyError
+	if errors.As(err, &ge) {
+		detail := ge.Message
+		switch ge.Kind {
+		case winclient.GroupPolicyErrorTimeout:
+			detail += "\n\nRaise the create timeout (timeouts block) or lower wait_seconds."
+		case winclient.GroupPolicyErrorPermission:
+			detail += "\n\nRefreshing computer policy requires a local Administrator."
+		}
+		if len(ge.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range ge.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if ge.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", ge.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
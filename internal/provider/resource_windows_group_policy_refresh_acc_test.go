@@ -0,0 +1,52 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for windows_group_policy_refresh.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsGroupPolicyRefresh
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccGroupPolicyRefreshConfig(trigger string) string {
+	return `
+resource "windows_group_policy_refresh" "test" {
+  triggers = {
+    run = "` + trigger + `"
+  }
+  target       = "Computer"
+  wait_seconds = 120
+}
+`
+}
+
+// TestAccWindowsGroupPolicyRefresh_Basic — refresh on create, no-op plan,
+// refresh again when triggers change.
+func TestAccWindowsGroupPolicyRefresh_Basic(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupPolicyRefreshConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("windows_group_policy_refresh.test", "last_refresh"),
+					resource.TestCheckResourceAttrPair("windows_group_policy_refresh.test", "id", "windows_group_policy_refresh.test", "last_refresh"),
+				),
+			},
+			{
+				Config:   testAccGroupPolicyRefreshConfig("1"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccGroupPolicyRefreshConfig("2"),
+				Check:  resource.TestCheckResourceAttrSet("windows_group_policy_refresh.test", "last_refresh"),
+			},
+		},
+	})
+}
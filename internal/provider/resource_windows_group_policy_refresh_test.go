@@ -0,0 +1,208 @@
+// Package provider — unit tests for the windows_group_policy_refresh resource.
+//
+// A fakeGroupPolicyClient is injected into
+// windowsGroupPolicyRefreshResource.client, so no WinRM connection is
+// required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeGroupPolicyClient struct {
+	out *winclient.GroupPolicyRefresh
+	err error
+
+	calls    int
+	lastIn   winclient.GroupPolicyRefreshInput
+	deadline time.Time
+}
+
+func (f *fakeGroupPolicyClient) Refresh(ctx context.Context, in winclient.GroupPolicyRefreshInput) (*winclient.GroupPolicyRefresh, error) {
+	f.calls++
+	f.lastIn = in
+	f.deadline, _ = ctx.Deadline()
+	return f.out, f.err
+}
+
+func gprTimeoutsType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{"create": tftypes.String}}
+}
+
+func gprObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":           tftypes.String,
+		"triggers":     tftypes.Map{ElementType: tftypes.String},
+		"target":       tftypes.String,
+		"wait_seconds": tftypes.Number,
+		"last_refresh": tftypes.String,
+		"timeouts":     gprTimeoutsType(),
+	}}
+}
+
+func gprObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "2026-10-17T09:30:00Z"),
+		"triggers": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"policy": tftypes.NewValue(tftypes.String, "v1"),
+		}),
+		"target":       tftypes.NewValue(tftypes.String, nil),
+		"wait_seconds": tftypes.NewValue(tftypes.Number, 600),
+		"last_refresh": tftypes.NewValue(tftypes.String, "2026-10-17T09:30:00Z"),
+		"timeouts":     tftypes.NewValue(gprTimeoutsType(), nil),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(gprObjectType(), base)
+}
+
+func gprPlan(overrides map[string]tftypes.Value) tfsdk.Plan {
+	return tfsdk.Plan{Raw: gprObj(overrides), Schema: windowsGroupPolicyRefreshSchemaDefinition(context.Background())}
+}
+
+func gprState(overrides map[string]tftypes.Value) tfsdk.State {
+	return tfsdk.State{Raw: gprObj(overrides), Schema: windowsGroupPolicyRefreshSchemaDefinition(context.Background())}
+}
+
+func gprCreate(t *testing.T, r *windowsGroupPolicyRefreshResource, overrides map[string]tftypes.Value) (*resource.CreateResponse, windowsGroupPolicyRefreshModel) {
+	t.Helper()
+	if overrides == nil {
+		overrides = map[string]tftypes.Value{}
+	}
+	overrides["id"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	overrides["last_refresh"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	schemaDef := windowsGroupPolicyRefreshSchemaDefinition(context.Background())
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: tftypes.NewValue(gprObjectType(), nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: gprPlan(overrides)}, resp)
+	var got windowsGroupPolicyRefreshModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+func TestGroupPolicyRefreshSchema(t *testing.T) {
+	s := windowsGroupPolicyRefreshSchemaDefinition(context.Background())
+	for _, name := range []string{"id", "triggers", "target", "wait_seconds", "last_refresh", "timeouts"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !s.Attributes["triggers"].IsOptional() || !s.Attributes["last_refresh"].IsComputed() {
+		t.Error("triggers must be optional and last_refresh computed")
+	}
+}
+
+func TestGroupPolicyRefreshCreate(t *testing.T) {
+	fake := &fakeGroupPolicyClient{out: &winclient.GroupPolicyRefresh{
+		RefreshedAt: time.Date(2026, 10, 17, 9, 30, 0, 500, time.UTC),
+		Output:      "Computer Policy update has completed successfully.",
+	}}
+	r := &windowsGroupPolicyRefreshResource{client: fake}
+	resp, got := gprCreate(t, r, map[string]tftypes.Value{
+		"target":       tftypes.NewValue(tftypes.String, "Computer"),
+		"wait_seconds": tftypes.NewValue(tftypes.Number, 120),
+	})
+	if resp.Diagnostics.HasError() || len(resp.Diagnostics) != 0 {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if fake.calls != 1 || fake.lastIn.Target != "Computer" || fake.lastIn.WaitSeconds != 120 {
+		t.Errorf("Refresh called %d times with %+v", fake.calls, fake.lastIn)
+	}
+	if got.ID.ValueString() != "2026-10-17T09:30:00Z" || got.LastRefresh.ValueString() != "2026-10-17T09:30:00Z" {
+		t.Errorf("unexpected state: %+v", got)
+	}
+	if d := time.Until(fake.deadline); d <= 14*time.Minute || d > groupPolicyRefreshDefaultTimeout {
+		t.Errorf("default create timeout not applied: deadline in %s", d)
+	}
+}
+
+func TestGroupPolicyRefreshCreate_ProviderDefaultTimeout(t *testing.T) {
+	fake := &fakeGroupPolicyClient{out: &winclient.GroupPolicyRefresh{RefreshedAt: time.Now()}}
+	r := &windowsGroupPolicyRefreshResource{client: fake, defaultTimeout: 2 * time.Minute}
+	gprCreate(t, r, nil)
+	if d := time.Until(fake.deadline); d <= time.Minute || d > 2*time.Minute {
+		t.Errorf("provider default_command_timeout not applied: deadline in %s", d)
+	}
+}
+
+func TestGroupPolicyRefreshCreate_LogoffWarning(t *testing.T) {
+	fake := &fakeGroupPolicyClient{out: &winclient.GroupPolicyRefresh{
+		RefreshedAt: time.Now(), RestartRequired: true, Output: "OK to restart? (Y/N)",
+	}}
+	resp, _ := gprCreate(t, &windowsGroupPolicyRefreshResource{client: fake}, nil)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 ||
+		!strings.Contains(resp.Diagnostics[0].Summary(), "startup") {
+		t.Errorf("expected one startup warning, got %v", resp.Diagnostics)
+	}
+}
+
+func TestGroupPolicyRefreshCreate_Failure(t *testing.T) {
+	fake := &fakeGroupPolicyClient{err: winclient.NewGroupPolicyError(winclient.GroupPolicyErrorRefreshFailed,
+		"gpupdate /force /wait:600 reported a failure (exit code 1): Computer policy could not be updated successfully.",
+		nil, map[string]string{"host": "win01"})}
+	resp, _ := gprCreate(t, &windowsGroupPolicyRefreshResource{client: fake}, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error")
+	}
+	detail := resp.Diagnostics[0].Detail()
+	if !strings.Contains(detail, "could not be updated successfully") || !strings.Contains(detail, "Kind: refresh_failed") {
+		t.Errorf("detail must carry the gpupdate output and kind: %q", detail)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("a failed refresh must not be saved to state")
+	}
+
+	fake = &fakeGroupPolicyClient{err: winclient.NewGroupPolicyError(winclient.GroupPolicyErrorTimeout, "operation \"refresh\" timed out or was cancelled", nil, nil)}
+	resp, _ = gprCreate(t, &windowsGroupPolicyRefreshResource{client: fake}, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "wait_seconds") {
+		t.Errorf("timeout should hint at timeouts and wait_seconds: %v", resp.Diagnostics)
+	}
+}
+
+func TestGroupPolicyRefreshUpdate_NoRefresh(t *testing.T) {
+	fake := &fakeGroupPolicyClient{}
+	r := &windowsGroupPolicyRefreshResource{client: fake}
+	plan := gprPlan(map[string]tftypes.Value{
+		"wait_seconds": tftypes.NewValue(tftypes.Number, 30),
+		"id":           tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"last_refresh": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+	resp := &resource.UpdateResponse{State: gprState(nil)}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: gprState(nil)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+	if fake.calls != 0 {
+		t.Error("Update must not run gpupdate")
+	}
+	var got windowsGroupPolicyRefreshModel
+	resp.State.Get(context.Background(), &got)
+	if got.WaitSeconds.ValueInt64() != 30 || got.LastRefresh.ValueString() != "2026-10-17T09:30:00Z" {
+		t.Errorf("unexpected state: %+v", got)
+	}
+}
+
+func TestGroupPolicyRefreshConfigure(t *testing.T) {
+	r := &windowsGroupPolicyRefreshResource{}
+	resp := &resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: "nope"}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "winclient.Client") {
+		t.Errorf("wrong type must produce error, got %v", resp.Diagnostics)
+	}
+	resp = &resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || r.client == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
@@ -13,7 +13,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -37,12 +40,20 @@ var (
 	_ resource.ResourceWithImportState = (*windowsHostnameResource)(nil)
 )
 
+// hostnameDefaultTimeout is the fallback per-operation timeout when the user
+// does not provide a `timeouts {}` block. A rename only writes the registry,
+// so the default is short; it mainly bounds a host that stops answering.
+const hostnameDefaultTimeout = 5 * time.Minute
+
 // NewWindowsHostnameResource is the constructor registered in provider.go.
 func NewWindowsHostnameResource() resource.Resource { return &windowsHostnameResource{} }
 
 // windowsHostnameResource is the TPF resource type for windows_hostname.
 type windowsHostnameResource struct {
 	hn winclient.WindowsHostnameClient
+	// defaultTimeout is the provider-level default_command_timeout; zero
+	// means hostnameDefaultTimeout.
+	defaultTimeout time.Duration
 }
 
 // windowsHostnameModel is the Terraform state/plan model for the
@@ -52,13 +63,14 @@ type windowsHostnameResource struct {
 // MachineID is anchored as the Terraform resource ID (EC-8, EC-10): it
 // survives renames and detects machine replacement out-of-band.
 type windowsHostnameModel struct {
-	ID            types.String `tfsdk:"id"`
-	Name          types.String `tfsdk:"name"`
-	CurrentName   types.String `tfsdk:"current_name"`
-	PendingName   types.String `tfsdk:"pending_name"`
-	RebootPending types.Bool   `tfsdk:"reboot_pending"`
-	MachineID     types.String `tfsdk:"machine_id"`
-	Force         types.Bool   `tfsdk:"force"`
+	ID            types.String   `tfsdk:"id"`
+	Name          types.String   `tfsdk:"name"`
+	CurrentName   types.String   `tfsdk:"current_name"`
+	PendingName   types.String   `tfsdk:"pending_name"`
+	RebootPending types.Bool     `tfsdk:"reboot_pending"`
+	MachineID     types.String   `tfsdk:"machine_id"`
+	Force         types.Bool     `tfsdk:"force"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 // netbiosNameRegex enforces the structural part of the NetBIOS rule.
@@ -105,13 +117,13 @@ func (r *windowsHostnameResource) Metadata(_ context.Context, req resource.Metad
 }
 
 // Schema returns the complete TPF schema.
-func (r *windowsHostnameResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = windowsHostnameSchemaDefinition()
+func (r *windowsHostnameResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsHostnameSchemaDefinition(ctx)
 }
 
 // windowsHostnameSchemaDefinition returns the resource schema. Extracted into
 // a function so it can be unit-tested independently of the resource type.
-func windowsHostnameSchemaDefinition() schema.Schema {
+func windowsHostnameSchemaDefinition(ctx context.Context) schema.Schema {
 	return schema.Schema{
 		MarkdownDescription: "Manages the NetBIOS computer name (hostname) of a remote Windows machine over WinRM/PowerShell.\n\n" +
 			"Renames are **asynchronous**: `Rename-Computer` only persists the new name to the registry; the change becomes active after the next reboot. " +
@@ -156,8 +168,8 @@ func windowsHostnameSchemaDefinition() schema.Schema {
 			},
 			"reboot_pending": schema.BoolAttribute{
 				Computed:            true,
-				Description:         "True when pending_name (case-insensitive) differs from current_name.",
-				MarkdownDescription: "True when `pending_name` differs from `current_name` (case-insensitive). Drives downstream reboot triggers.",
+				Description:         "True when pending_name differs from current_name, or the pending DNS host name (Tcpip NV Hostname) differs from the active one. Case-insensitive.",
+				MarkdownDescription: "True when `pending_name` differs from `current_name`, or when the pending DNS host name (Tcpip `NV Hostname`) differs from the active `Hostname` (both case-insensitive). Read from the registry on every refresh, so it clears once the host has rebooted. Drives downstream reboot triggers.",
 			},
 			"machine_id": schema.StringAttribute{
 				Computed:            true,
@@ -177,6 +189,11 @@ func windowsHostnameSchemaDefinition() schema.Schema {
 					boolplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+			}),
 		},
 	}
 }
@@ -195,6 +212,7 @@ func (r *windowsHostnameResource) Configure(_ context.Context, req resource.Conf
 		return
 	}
 	r.hn = winclient.NewHostnameClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
 }
 
 // ImportState lets `terraform import windows_hostname.this <machine_guid>` work.
@@ -216,6 +234,14 @@ func (r *windowsHostnameResource) Create(ctx context.Context, req resource.Creat
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, hostnameDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	in := winclient.HostnameInput{
 		Name:  plan.Name.ValueString(),
 		Force: plan.Force.ValueBool(),
@@ -239,6 +265,14 @@ func (r *windowsHostnameResource) Read(ctx context.Context, req resource.ReadReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	readTimeout, diags := state.Timeouts.Read(ctx, operationTimeout(r.defaultTimeout, hostnameDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	id := state.MachineID.ValueString()
 	if id == "" {
 		id = state.ID.ValueString()
@@ -272,6 +306,14 @@ func (r *windowsHostnameResource) Update(ctx context.Context, req resource.Updat
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, operationTimeout(r.defaultTimeout, hostnameDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	id := prior.MachineID.ValueString()
 	if id == "" {
 		id = prior.ID.ValueString()
@@ -338,6 +380,8 @@ func modelFromHostnameState(state *winclient.HostnameState, prior windowsHostnam
 		RebootPending: types.BoolValue(state.RebootPending),
 		MachineID:     types.StringValue(state.MachineID),
 		Force:         force,
+		// Preserve the user-configured per-operation timeouts.
+		Timeouts: prior.Timeouts,
 	}
 }
 
@@ -347,6 +391,16 @@ func maybeWarnRebootPending(diags *diag.Diagnostics, m, _ windowsHostnameModel)
 	if !m.RebootPending.ValueBool() {
 		return
 	}
+	if strings.EqualFold(m.CurrentName.ValueString(), m.PendingName.ValueString()) {
+		diags.AddWarning(
+			"Reboot pending to activate hostname change",
+			fmt.Sprintf(
+				"The computer name is %q but its DNS host name change (Tcpip NV Hostname) only takes effect on the next reboot. "+
+					"Reboot the target host to complete the rename. The windows_hostname resource never reboots automatically (EC-9).",
+				m.CurrentName.ValueString()),
+		)
+		return
+	}
 	diags.AddWarning(
 		"Reboot pending to activate hostname change",
 		fmt.Sprintf(
@@ -11,10 +11,13 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -35,15 +38,20 @@ type fakeHostnameClient struct {
 	updateOut *winclient.HostnameState
 	updateErr error
 	deleteErr error
+	// deadline is the context deadline seen by the last Create/Read/Update.
+	deadline time.Time
 }
 
-func (f *fakeHostnameClient) Create(_ context.Context, _ winclient.HostnameInput) (*winclient.HostnameState, error) {
+func (f *fakeHostnameClient) Create(ctx context.Context, _ winclient.HostnameInput) (*winclient.HostnameState, error) {
+	f.deadline, _ = ctx.Deadline()
 	return f.createOut, f.createErr
 }
-func (f *fakeHostnameClient) Read(_ context.Context, _ string) (*winclient.HostnameState, error) {
+func (f *fakeHostnameClient) Read(ctx context.Context, _ string) (*winclient.HostnameState, error) {
+	f.deadline, _ = ctx.Deadline()
 	return f.readOut, f.readErr
 }
-func (f *fakeHostnameClient) Update(_ context.Context, _ string, _ winclient.HostnameInput) (*winclient.HostnameState, error) {
+func (f *fakeHostnameClient) Update(ctx context.Context, _ string, _ winclient.HostnameInput) (*winclient.HostnameState, error) {
+	f.deadline, _ = ctx.Deadline()
 	return f.updateOut, f.updateErr
 }
 func (f *fakeHostnameClient) Delete(_ context.Context, _ string) error {
@@ -71,6 +79,15 @@ func hnObjectType() tftypes.Object {
 		"reboot_pending": tftypes.Bool,
 		"machine_id":     tftypes.String,
 		"force":          tftypes.Bool,
+		"timeouts":       hnTimeoutsType(),
+	}}
+}
+
+func hnTimeoutsType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"create": tftypes.String,
+		"read":   tftypes.String,
+		"update": tftypes.String,
 	}}
 }
 
@@ -83,6 +100,7 @@ func hnObj(overrides map[string]tftypes.Value) tftypes.Value {
 		"reboot_pending": tftypes.NewValue(tftypes.Bool, false),
 		"machine_id":     tftypes.NewValue(tftypes.String, nil),
 		"force":          tftypes.NewValue(tftypes.Bool, false),
+		"timeouts":       tftypes.NewValue(hnTimeoutsType(), nil),
 	}
 	for k, v := range overrides {
 		base[k] = v
@@ -112,8 +130,8 @@ func TestHostnameMetadata(t *testing.T) {
 }
 
 func TestHostnameSchema_HasRequiredAttributes(t *testing.T) {
-	s := windowsHostnameSchemaDefinition()
-	want := []string{"id", "name", "current_name", "pending_name", "reboot_pending", "machine_id", "force"}
+	s := windowsHostnameSchemaDefinition(context.Background())
+	want := []string{"id", "name", "current_name", "pending_name", "reboot_pending", "machine_id", "force", "timeouts"}
 	for _, k := range want {
 		if _, ok := s.Attributes[k]; !ok {
 			t.Errorf("schema missing attribute %q", k)
@@ -246,7 +264,7 @@ func TestHostnameConfigure_Valid(t *testing.T) {
 
 func TestHostnameImportState(t *testing.T) {
 	r := &windowsHostnameResource{}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	resp := &resource.ImportStateResponse{
 		State: tfsdk.State{Schema: schemaDef, Raw: hnObj(nil)},
 	}
@@ -274,7 +292,7 @@ func TestHostnameCreate_HappyPath(t *testing.T) {
 		createOut: fakeHnState("guid-1", "OLD-NAME", "NEW-NAME", true),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	plan := tfsdk.Plan{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -313,7 +331,7 @@ func TestHostnameCreate_PermissionDenied(t *testing.T) {
 		),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	plan := tfsdk.Plan{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -340,7 +358,7 @@ func TestHostnameCreate_DomainJoined(t *testing.T) {
 		),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	plan := tfsdk.Plan{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -366,7 +384,7 @@ func TestHostnameRead_HappyPath(t *testing.T) {
 		readOut: fakeHnState("guid-1", "MYHOST", "MYHOST", false),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	prior := tfsdk.State{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -389,6 +407,86 @@ func TestHostnameRead_HappyPath(t *testing.T) {
 	}
 }
 
+// TestHostnameTimeouts checks that every handler runs under a deadline: the
+// timeouts block wins, then the provider default_command_timeout, then
+// hostnameDefaultTimeout.
+func TestHostnameTimeouts(t *testing.T) {
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
+	withTimeouts := func(v map[string]tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(hnTimeoutsType(), map[string]tftypes.Value{
+			"create": v["create"], "read": v["read"], "update": v["update"],
+		})
+	}
+	str := func(v any) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+	cases := []struct {
+		name        string
+		provDefault time.Duration
+		timeouts    tftypes.Value
+		want        time.Duration
+	}{
+		{"built-in default", 0, tftypes.NewValue(hnTimeoutsType(), nil), hostnameDefaultTimeout},
+		{"provider default", 2 * time.Minute, tftypes.NewValue(hnTimeoutsType(), nil), 2 * time.Minute},
+		{"timeouts block", 2 * time.Minute, withTimeouts(map[string]tftypes.Value{
+			"create": str("30s"), "read": str("30s"), "update": str("30s"),
+		}), 30 * time.Second},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeHostnameClient{
+				createOut: fakeHnState("guid-1", "MYHOST", "MYHOST", false),
+				readOut:   fakeHnState("guid-1", "MYHOST", "MYHOST", false),
+				updateOut: fakeHnState("guid-1", "MYHOST", "MYHOST", false),
+			}
+			r := &windowsHostnameResource{hn: fake, defaultTimeout: tc.provDefault}
+			raw := hnObj(map[string]tftypes.Value{
+				"id":         str("guid-1"),
+				"machine_id": str("guid-1"),
+				"name":       str("MYHOST"),
+				"timeouts":   tc.timeouts,
+			})
+			check := func(op string) {
+				t.Helper()
+				if fake.deadline.IsZero() {
+					t.Fatalf("%s: client called without a deadline", op)
+				}
+				got := time.Until(fake.deadline)
+				if got > tc.want || got < tc.want-time.Minute/2 {
+					t.Errorf("%s: deadline in %v, want about %v", op, got, tc.want)
+				}
+				fake.deadline = time.Time{}
+			}
+
+			cresp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: hnObj(nil)}}
+			r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaDef, Raw: raw}}, cresp)
+			check("create")
+
+			state := tfsdk.State{Schema: schemaDef, Raw: raw}
+			r.Read(context.Background(), resource.ReadRequest{State: state},
+				&resource.ReadResponse{State: tfsdk.State{Schema: schemaDef, Raw: raw.Copy()}})
+			check("read")
+
+			r.Update(context.Background(), resource.UpdateRequest{Plan: tfsdk.Plan{Schema: schemaDef, Raw: raw}, State: state},
+				&resource.UpdateResponse{State: tfsdk.State{Schema: schemaDef, Raw: raw.Copy()}})
+			check("update")
+
+			var m windowsHostnameModel
+			cresp.State.Get(context.Background(), &m)
+			if !m.Timeouts.Object.Equal(mustHnTimeouts(t, schemaDef, raw).Object) {
+				t.Errorf("timeouts not preserved in state: %+v", m.Timeouts)
+			}
+		})
+	}
+}
+
+func mustHnTimeouts(t *testing.T, s schema.Schema, raw tftypes.Value) timeouts.Value {
+	t.Helper()
+	var m windowsHostnameModel
+	if diags := (tfsdk.Plan{Schema: s, Raw: raw}).Get(context.Background(), &m); diags.HasError() {
+		t.Fatalf("decode: %v", diags)
+	}
+	return m.Timeouts
+}
+
 func TestHostnameRead_MachineMismatch_RemovesResource(t *testing.T) {
 	// EC-10: machine replaced → remove from state.
 	fake := &fakeHostnameClient{
@@ -400,7 +498,7 @@ func TestHostnameRead_MachineMismatch_RemovesResource(t *testing.T) {
 		),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	prior := tfsdk.State{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -432,7 +530,7 @@ func TestHostnameRead_DomainJoined_ReturnsError(t *testing.T) {
 		),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	prior := tfsdk.State{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -456,7 +554,7 @@ func TestHostnameRead_FallbackToID_WhenMachineIDEmpty(t *testing.T) {
 		readOut: fakeHnState("guid-1", "MYHOST", "MYHOST", false),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	prior := tfsdk.State{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -483,7 +581,7 @@ func TestHostnameRead_GenericError(t *testing.T) {
 		),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	prior := tfsdk.State{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -510,7 +608,7 @@ func TestHostnameUpdate_HappyPath(t *testing.T) {
 		updateOut: fakeHnState("guid-1", "OLD-NAME", "NEW-NAME", true),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	plan := tfsdk.Plan{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -553,7 +651,7 @@ func TestHostnameUpdate_MachineMismatch(t *testing.T) {
 		),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	plan := tfsdk.Plan{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -584,7 +682,7 @@ func TestHostnameUpdate_FallbackToIDWhenMachineIDEmpty(t *testing.T) {
 		updateOut: fakeHnState("guid-1", "OLD-NAME", "NEW-NAME", true),
 	}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	plan := tfsdk.Plan{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -617,7 +715,7 @@ func TestHostnameUpdate_FallbackToIDWhenMachineIDEmpty(t *testing.T) {
 func TestHostnameDelete_NoOp(t *testing.T) {
 	fake := &fakeHostnameClient{deleteErr: nil}
 	r := &windowsHostnameResource{hn: fake}
-	schemaDef := windowsHostnameSchemaDefinition()
+	schemaDef := windowsHostnameSchemaDefinition(context.Background())
 	state := tfsdk.State{
 		Schema: schemaDef,
 		Raw: hnObj(map[string]tftypes.Value{
@@ -738,6 +836,22 @@ func TestMaybeWarnRebootPending_WhenNotPending(t *testing.T) {
 	}
 }
 
+func TestMaybeWarnRebootPending_DNSHostnameOnly(t *testing.T) {
+	var diags diag.Diagnostics
+	m := windowsHostnameModel{
+		RebootPending: types.BoolValue(true),
+		CurrentName:   types.StringValue("WIN01"),
+		PendingName:   types.StringValue("win01"),
+	}
+	maybeWarnRebootPending(&diags, m, m)
+	if len(diags) != 1 || diags[0].Severity() != diag.SeverityWarning {
+		t.Fatalf("expected one warning, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail(), "NV Hostname") {
+		t.Errorf("warning should point at the DNS host name: %s", diags[0].Detail())
+	}
+}
+
 // -----------------------------------------------------------------------------
 // addHostnameDiag helper
 // -----------------------------------------------------------------------------
@@ -88,6 +88,9 @@ func NewWindowsLegacyPackageResource() resource.Resource {
 // windowsLegacyPackageResource is the TPF resource type for windows_legacy_package.
 type windowsLegacyPackageResource struct {
 	lp winclient.LegacyPackageClient
+	// defaultTimeout is the provider default_command_timeout (0 = unset,
+	// lpDefaultTimeout applies).
+	defaultTimeout time.Duration
 }
 
 // ---------------------------------------------------------------------------
@@ -373,6 +376,7 @@ func (r *windowsLegacyPackageResource) Configure(_ context.Context, req resource
 		return
 	}
 	r.lp = winclient.NewLegacyPackageClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
 }
 
 // ConfigValidators enforces cross-attribute spec rules:
@@ -405,7 +409,7 @@ func (r *windowsLegacyPackageResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	createTimeout, dt := plan.Timeouts.Create(ctx, lpDefaultTimeout)
+	createTimeout, dt := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, lpDefaultTimeout))
 	resp.Diagnostics.Append(dt...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -505,7 +509,7 @@ func (r *windowsLegacyPackageResource) Update(ctx context.Context, req resource.
 		return
 	}
 
-	updateTimeout, dt := plan.Timeouts.Update(ctx, lpDefaultTimeout)
+	updateTimeout, dt := plan.Timeouts.Update(ctx, operationTimeout(r.defaultTimeout, lpDefaultTimeout))
 	resp.Diagnostics.Append(dt...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -566,7 +570,7 @@ func (r *windowsLegacyPackageResource) Delete(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	deleteTimeout, dt := state.Timeouts.Delete(ctx, lpDefaultTimeout)
+	deleteTimeout, dt := state.Timeouts.Delete(ctx, operationTimeout(r.defaultTimeout, lpDefaultTimeout))
 	resp.Diagnostics.Append(dt...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -13,6 +13,9 @@
 //     renames on case-only differences (EC-4, ADR-LG-4).
 //   - Import accepts either a group name or a SID string; auto-detected by
 //     "S-" prefix (EC-10, ADR-LG-6).
+//   - members is read-only: the client returns the group and its members in a
+//     single WinRM round trip, so reporting them costs nothing extra. It is
+//     never used to add or remove members (ADR-LG-3).
 package provider
 
 import (
@@ -23,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -70,6 +74,14 @@ type windowsLocalGroupModel struct {
 	Name        types.String `tfsdk:"name"`
 	Description types.String `tfsdk:"description"`
 	SID         types.String `tfsdk:"sid"`
+	Members     types.List   `tfsdk:"members"`
+}
+
+// localGroupMemberAttrTypes is the attr.Type map for a members element.
+var localGroupMemberAttrTypes = map[string]attr.Type{
+	"sid":              types.StringType,
+	"name":             types.StringType,
+	"principal_source": types.StringType,
 }
 
 // ---------------------------------------------------------------------------
@@ -174,7 +186,7 @@ func (r *windowsLocalGroupResource) Schema(
 }
 
 // windowsLocalGroupSchemaDefinition returns the complete TPF schema for the
-// windows_local_group resource (4 attributes, validators, plan modifiers,
+// windows_local_group resource (5 attributes, validators, plan modifiers,
 // defaults).
 //
 // Canonical usage:
@@ -275,6 +287,34 @@ func windowsLocalGroupSchemaDefinition() schema.Schema {
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+
+			// members — computed only, read in the same round trip as the group
+			"members": schema.ListNestedAttribute{
+				Computed: true,
+				Description: "Current members of the group, sorted by SID. Read-only: this resource " +
+					"never adds or removes members.",
+				MarkdownDescription: "Current members of the group, sorted by `sid`. **Read-only**: " +
+					"reported for visibility and for use in other expressions, but this resource " +
+					"never adds or removes members (ADR-LG-3). Members whose account no longer " +
+					"resolves (orphaned SIDs) are listed with `name` equal to `sid`.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"sid": schema.StringAttribute{
+							Computed:    true,
+							Description: "Security Identifier of the member.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Member name in DOMAIN\\name form, or the SID when it cannot be resolved.",
+						},
+						"principal_source": schema.StringAttribute{
+							Computed: true,
+							Description: "Where the account is defined: Local, ActiveDirectory, " +
+								"MicrosoftAccount, AzureAD or Unknown.",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -518,13 +558,23 @@ func (r *windowsLocalGroupResource) ImportState(
 // ---------------------------------------------------------------------------
 
 // stateFromGroup converts a *winclient.GroupState into a windowsLocalGroupModel
-// with both id and sid set to the group SID (ADR-LG-1).
+// with both id and sid set to the group SID (ADR-LG-1). A nil Members slice
+// becomes an empty list.
 func stateFromGroup(gs *winclient.GroupState) windowsLocalGroupModel {
+	members := make([]attr.Value, 0, len(gs.Members))
+	for _, m := range gs.Members {
+		members = append(members, types.ObjectValueMust(localGroupMemberAttrTypes, map[string]attr.Value{
+			"sid":              types.StringValue(m.SID),
+			"name":             types.StringValue(m.Name),
+			"principal_source": types.StringValue(m.PrincipalSource),
+		}))
+	}
 	return windowsLocalGroupModel{
 		ID:          types.StringValue(gs.SID),
 		Name:        types.StringValue(gs.Name),
 		Description: types.StringValue(gs.Description),
 		SID:         types.StringValue(gs.SID),
+		Members:     types.ListValueMust(types.ObjectType{AttrTypes: localGroupMemberAttrTypes}, members),
 	}
 }
 
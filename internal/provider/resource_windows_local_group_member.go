@@ -507,31 +507,15 @@ func (r *windowsLocalGroupMemberResource) ImportState(
 	}
 	groupSID := grpState.SID
 
-	// Step 2: List all group members (with orphan fallback).
-	members, listErr := r.member.List(ctx, groupSID)
-	if listErr != nil {
+	// Step 2: Find the requested member, matching on its resolved SID so a
+	// bare local name never matches a domain account of the same short name.
+	found, findErr := r.member.Find(ctx, groupSID, memberStr)
+	if findErr != nil {
 		addLocalGroupMemberDiag(&resp.Diagnostics,
-			"Cannot import windows_local_group_member: list members failed", listErr)
+			"Cannot import windows_local_group_member: list members failed", findErr)
 		return
 	}
 
-	// Step 3: Find the requested member by SID or display name.
-	memberIsSID := strings.HasPrefix(memberStr, "S-")
-	var found *winclient.LocalGroupMemberState
-	for _, m := range members {
-		if memberIsSID {
-			if strings.EqualFold(m.MemberSID, memberStr) {
-				found = m
-				break
-			}
-		} else {
-			if strings.EqualFold(m.MemberName, memberStr) {
-				found = m
-				break
-			}
-		}
-	}
-
 	if found == nil {
 		resp.Diagnostics.AddError(
 			"Cannot import windows_local_group_member: member not found",
@@ -83,6 +83,20 @@ func (f *fakeLocalGroupMemberClient) List(_ context.Context, _ string) ([]*wincl
 	return f.listOut, f.listErr
 }
 
+// Find matches listOut on SID or full name; the SID resolution it performs
+// in winclient is covered there.
+func (f *fakeLocalGroupMemberClient) Find(_ context.Context, _ string, member string) (*winclient.LocalGroupMemberState, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	for _, m := range f.listOut {
+		if strings.EqualFold(m.MemberSID, member) || strings.EqualFold(m.MemberName, member) {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
 // ---------------------------------------------------------------------------
 // tftypes helpers for the local_group_member schema
 // ---------------------------------------------------------------------------
@@ -82,12 +82,19 @@ func (f *fakeLocalGroupClient) ImportBySID(_ context.Context, _ string) (*wincli
 // tftypes helpers for local group schema
 // -----------------------------------------------------------------------------
 
+var lgMemberObjectType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"sid":              tftypes.String,
+	"name":             tftypes.String,
+	"principal_source": tftypes.String,
+}}
+
 func localGroupObjectType() tftypes.Object {
 	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
 		"id":          tftypes.String,
 		"name":        tftypes.String,
 		"description": tftypes.String,
 		"sid":         tftypes.String,
+		"members":     tftypes.List{ElementType: lgMemberObjectType},
 	}}
 }
 
@@ -98,6 +105,7 @@ func lgObj(overrides map[string]tftypes.Value) tftypes.Value {
 		"name":        tftypes.NewValue(tftypes.String, nil),
 		"description": tftypes.NewValue(tftypes.String, ""),
 		"sid":         tftypes.NewValue(tftypes.String, nil),
+		"members":     tftypes.NewValue(tftypes.List{ElementType: lgMemberObjectType}, nil),
 	}
 	for k, v := range overrides {
 		base[k] = v
@@ -150,7 +158,7 @@ func TestLocalGroupMetadata(t *testing.T) {
 
 func TestLocalGroupSchema_HasRequiredAttributes(t *testing.T) {
 	s := windowsLocalGroupSchemaDefinition()
-	want := []string{"id", "name", "description", "sid"}
+	want := []string{"id", "name", "description", "sid", "members"}
 	for _, k := range want {
 		if _, ok := s.Attributes[k]; !ok {
 			t.Errorf("schema missing attribute %q", k)
@@ -430,6 +438,40 @@ func TestStateFromGroup(t *testing.T) {
 	}
 }
 
+func TestStateFromGroup_Members(t *testing.T) {
+	m := stateFromGroup(&winclient.GroupState{Name: "X", SID: "S-1-5-21-9-8-7-1001"})
+	if m.Members.IsNull() || len(m.Members.Elements()) != 0 {
+		t.Errorf("nil Members must become an empty list, got %v", m.Members)
+	}
+
+	gs := &winclient.GroupState{Name: "X", SID: "S-1-5-21-9-8-7-1001", Members: []winclient.GroupMember{
+		{SID: "S-1-5-21-9-8-7-1002", Name: `HOST\bob`, PrincipalSource: "Local"},
+		{SID: "S-1-5-21-1-1-1-1105", Name: "S-1-5-21-1-1-1-1105", PrincipalSource: "Unknown"},
+	}}
+	var got []struct {
+		SID             string `tfsdk:"sid"`
+		Name            string `tfsdk:"name"`
+		PrincipalSource string `tfsdk:"principal_source"`
+	}
+	if d := stateFromGroup(gs).Members.ElementsAs(context.Background(), &got, false); d.HasError() {
+		t.Fatalf("ElementsAs: %v", d)
+	}
+	if len(got) != 2 || got[0].Name != `HOST\bob` || got[0].PrincipalSource != "Local" || got[1].Name != got[1].SID {
+		t.Errorf("members = %+v", got)
+	}
+}
+
+func TestLocalGroupSchema_MembersIsComputedOnly(t *testing.T) {
+	s := windowsLocalGroupSchemaDefinition()
+	a, ok := s.Attributes["members"].(rschema.ListNestedAttribute)
+	if !ok {
+		t.Fatal("members must be a ListNestedAttribute")
+	}
+	if !a.Computed || a.Optional || a.Required {
+		t.Errorf("members must be Computed only, got %+v", a)
+	}
+}
+
 func TestStateFromGroup_IDEqualsSID(t *testing.T) {
 	// ADR-LG-1: ID must equal SID in all cases.
 	gs := &winclient.GroupState{Name: "X", Description: "", SID: "S-1-5-21-9-8-7-500"}
@@ -0,0 +1,326 @@
+// Package provider: windows_local_security_policy resource implementation.
+//
+// This file contains the TPF schema, model and CRUD + ImportState handlers
+// for the windows_local_security_policy resource. All WinRM interaction is
+// delegated to winclient.SecurityPolicyClient (internal/winclient).
+//
+// The local security policy is a host-wide singleton, so the resource ID is
+// the constant "local_security_policy". Only the settings present in the
+// configuration are managed: a null attribute is never written and stays
+// null in state, so settings owned by a baseline GPO or another tool are
+// left alone.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                     = (*windowsLocalSecurityPolicyResource)(nil)
+	_ resource.ResourceWithConfigure        = (*windowsLocalSecurityPolicyResource)(nil)
+	_ resource.ResourceWithImportState      = (*windowsLocalSecurityPolicyResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*windowsLocalSecurityPolicyResource)(nil)
+)
+
+// localSecurityPolicyID is the fixed resource ID: a host has a single local
+// security policy.
+const localSecurityPolicyID = "local_security_policy"
+
+// NewWindowsLocalSecurityPolicyResource is the constructor registered in
+// provider.go.
+func NewWindowsLocalSecurityPolicyResource() resource.Resource {
+	return &windowsLocalSecurityPolicyResource{}
+}
+
+// windowsLocalSecurityPolicyResource is the TPF resource type for
+// windows_local_security_policy.
+type windowsLocalSecurityPolicyResource struct {
+	sp winclient.WindowsSecurityPolicyClient
+}
+
+// windowsLocalSecurityPolicyModel is the Terraform state/plan model for the
+// windows_local_security_policy resource.
+type windowsLocalSecurityPolicyModel struct {
+	ID                     types.String `tfsdk:"id"`
+	MinimumPasswordLength  types.Int64  `tfsdk:"minimum_password_length"`
+	PasswordHistorySize    types.Int64  `tfsdk:"password_history_size"`
+	LockoutThreshold       types.Int64  `tfsdk:"lockout_threshold"`
+	LockoutDurationMinutes types.Int64  `tfsdk:"lockout_duration_minutes"`
+}
+
+// Metadata sets the resource type name ("windows_local_security_policy").
+func (r *windowsLocalSecurityPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_local_security_policy"
+}
+
+// Schema returns the complete TPF schema.
+func (r *windowsLocalSecurityPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsLocalSecurityPolicySchemaDefinition()
+}
+
+// ConfigValidators returns the resource-level cross-field validators.
+func (r *windowsLocalSecurityPolicyResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("minimum_password_length"),
+			path.MatchRoot("password_history_size"),
+			path.MatchRoot("lockout_threshold"),
+			path.MatchRoot("lockout_duration_minutes"),
+		),
+		lockoutDurationValidator{},
+	}
+}
+
+// lockoutDurationValidator rejects a lockout duration next to
+// lockout_threshold = 0: Windows drops the duration while lockout is off, so
+// it would never read back.
+type lockoutDurationValidator struct{}
+
+func (v lockoutDurationValidator) Description(_ context.Context) string {
+	return "lockout_duration_minutes requires a lockout_threshold other than 0."
+}
+
+func (v lockoutDurationValidator) MarkdownDescription(_ context.Context) string {
+	return "`lockout_duration_minutes` requires a `lockout_threshold` other than `0`."
+}
+
+func (v lockoutDurationValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var threshold, duration types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("lockout_threshold"), &threshold)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("lockout_duration_minutes"), &duration)...)
+	if resp.Diagnostics.HasError() || duration.IsNull() || threshold.IsNull() || threshold.IsUnknown() {
+		return
+	}
+	if threshold.ValueInt64() == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("lockout_duration_minutes"), "Lockout duration without lockout",
+			"lockout_threshold = 0 turns account lockout off, and Windows discards the lockout duration while it is off. "+
+				"Remove lockout_duration_minutes, or set lockout_threshold to the number of failed logons that locks an account.")
+	}
+}
+
+// windowsLocalSecurityPolicySchemaDefinition returns the resource schema.
+// Extracted into a function so it can be unit-tested independently of the
+// resource type.
+func windowsLocalSecurityPolicySchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Manages account policy settings of the local security policy on a remote Windows host via WinRM, " +
+			"using `secedit /export` to read and `secedit /configure` to write.\n\n" +
+			"Only the attributes set in the configuration are managed. Settings that are left out are neither written nor " +
+			"tracked, so a baseline applied by another tool keeps its values. Declare at most one " +
+			"`windows_local_security_policy` per host.\n\n" +
+			"~> On a domain member, a domain Group Policy that defines the same setting overrides the local policy. " +
+			"The apply then fails with a `not_applied` error instead of leaving a permanent diff.\n\n" +
+			"Destroying the resource leaves the policy as it is: Windows keeps no \"previous\" value to go back to.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Always " + localSecurityPolicyID + ".",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"minimum_password_length": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Minimum password length in characters (MinimumPasswordLength), 0 to 128. Values above 14 need the RelaxMinimumPasswordLengthLimits policy on Windows Server 2022 / Windows 10 2004 and later.",
+				MarkdownDescription: "Minimum password length in characters (`MinimumPasswordLength`), `0` to `128`. Values above `14` need the *Relax minimum password length limits* policy, available on Windows Server 2022 and Windows 10 2004 and later.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 128),
+				},
+			},
+			"password_history_size": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Number of previous passwords remembered and refused on change (PasswordHistorySize), 0 to 24.",
+				MarkdownDescription: "Number of previous passwords remembered and refused on change (`PasswordHistorySize`), `0` to `24`.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 24),
+				},
+			},
+			"lockout_threshold": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Failed logons before an account is locked out (LockoutBadCount), 0 to 999. 0 turns lockout off.",
+				MarkdownDescription: "Failed logons before an account is locked out (`LockoutBadCount`), `0` to `999`. `0` turns lockout off.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 999),
+				},
+			},
+			"lockout_duration_minutes": schema.Int64Attribute{
+				Optional: true,
+				Description: "Minutes a locked-out account stays locked (LockoutDuration), 1 to 99999, or -1 to keep it locked until an " +
+					"administrator unlocks it. Requires a lockout_threshold other than 0. \"Reset account lockout counter after\" " +
+					"is lowered to this value when it is longer.",
+				MarkdownDescription: "Minutes a locked-out account stays locked (`LockoutDuration`), `1` to `99999`, or `-1` to keep it locked " +
+					"until an administrator unlocks it. Requires a `lockout_threshold` other than `0`. Windows does not allow " +
+					"*Reset account lockout counter after* to exceed the duration, so it is lowered to this value when it is longer.",
+				Validators: []validator.Int64{
+					int64validator.Between(-1, 99999),
+					int64validator.NoneOf(0),
+				},
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsLocalSecurityPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.sp = winclient.NewSecurityPolicyClient(c)
+}
+
+// ImportState lets `terraform import windows_local_security_policy.this
+// local_security_policy` adopt the host's policy. Nothing is managed until
+// the configuration sets it, so the first apply after import writes every
+// configured setting once.
+func (r *windowsLocalSecurityPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != localSecurityPolicyID {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("windows_local_security_policy is a per-host singleton; import it with the ID %q, got %q.", localSecurityPolicyID, req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), localSecurityPolicyID)...)
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+// Create writes the configured settings.
+func (r *windowsLocalSecurityPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsLocalSecurityPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.set(ctx, plan, &resp.State, &resp.Diagnostics, "Create windows_local_security_policy failed")
+}
+
+// Read refreshes the managed settings. Attributes that are null in state
+// are not managed and stay null.
+func (r *windowsLocalSecurityPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsLocalSecurityPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.sp.Read(ctx)
+	if err != nil {
+		addSecurityPolicyDiag(&resp.Diagnostics, "Read windows_local_security_policy failed", err)
+		return
+	}
+	final := modelFromSecurityPolicyState(live, state)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Update writes the configured settings. A setting removed from the
+// configuration is left at its current value and no longer managed.
+func (r *windowsLocalSecurityPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan windowsLocalSecurityPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.set(ctx, plan, &resp.State, &resp.Diagnostics, "Update windows_local_security_policy failed")
+}
+
+// Delete only removes the resource from state: the policy keeps its current
+// values.
+func (r *windowsLocalSecurityPolicyResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	tflog.Info(ctx, "windows_local_security_policy removed from state; the host's policy is left unchanged")
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// set applies plan through the client and stores the observed state. Shared
+// by Create and Update.
+func (r *windowsLocalSecurityPolicyResource) set(ctx context.Context, plan windowsLocalSecurityPolicyModel, state *tfsdk.State, diags *diag.Diagnostics, summary string) {
+	live, err := r.sp.Set(ctx, winclient.SecurityPolicyInput{
+		MinimumPasswordLength:  int64Ptr(plan.MinimumPasswordLength),
+		PasswordHistorySize:    int64Ptr(plan.PasswordHistorySize),
+		LockoutThreshold:       int64Ptr(plan.LockoutThreshold),
+		LockoutDurationMinutes: int64Ptr(plan.LockoutDurationMinutes),
+	})
+	if err != nil {
+		addSecurityPolicyDiag(diags, summary, err)
+		return
+	}
+	final := modelFromSecurityPolicyState(live, plan)
+	diags.Append(state.Set(ctx, &final)...)
+}
+
+// int64Ptr returns nil for a null or unknown value.
+func int64Ptr(v types.Int64) *int64 {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	n := v.ValueInt64()
+	return &n
+}
+
+// modelFromSecurityPolicyState projects a winclient.SecurityPolicyState onto
+// the managed attributes of prior: a null attribute stays null, and a managed
+// one takes the live value, or null when the export omits it.
+func modelFromSecurityPolicyState(live *winclient.SecurityPolicyState, prior windowsLocalSecurityPolicyModel) windowsLocalSecurityPolicyModel {
+	pick := func(managed types.Int64, v *int64) types.Int64 {
+		if managed.IsNull() || v == nil {
+			return types.Int64Null()
+		}
+		return types.Int64Value(*v)
+	}
+	return windowsLocalSecurityPolicyModel{
+		ID:                     types.StringValue(localSecurityPolicyID),
+		MinimumPasswordLength:  pick(prior.MinimumPasswordLength, live.MinimumPasswordLength),
+		PasswordHistorySize:    pick(prior.PasswordHistorySize, live.PasswordHistorySize),
+		LockoutThreshold:       pick(prior.LockoutThreshold, live.LockoutThreshold),
+		LockoutDurationMinutes: pick(prior.LockoutDurationMinutes, live.LockoutDurationMinutes),
+	}
+}
+
+// addSecurityPolicyDiag converts a *winclient.SecurityPolicyError into a TPF
+// diagnostic.
+func addSecurityPolicyDiag(diags *diag.Diagnostics, summary string, err error) {
+	var se *winclient.SecurityPolicyError
+	if errors.As(err, &se) {
+		detail := se.Message
+		if len(se.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range se.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if se.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", se.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
@@ -0,0 +1,231 @@
+// Package provider — unit tests for the windows_local_security_policy
+// resource.
+//
+// They exercise the schema, the validators and the CRUD handlers without
+// touching WinRM, using a fakeSecurityPolicyClient injected into
+// windowsLocalSecurityPolicyResource.sp.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// -----------------------------------------------------------------------------
+// Fake WindowsSecurityPolicyClient
+// -----------------------------------------------------------------------------
+
+type fakeSecurityPolicyClient struct {
+	setIn   *winclient.SecurityPolicyInput
+	setErr  error
+	live    winclient.SecurityPolicyState
+	readErr error
+}
+
+func (f *fakeSecurityPolicyClient) Set(_ context.Context, in winclient.SecurityPolicyInput) (*winclient.SecurityPolicyState, error) {
+	f.setIn = &in
+	if f.setErr != nil {
+		return nil, f.setErr
+	}
+	st := f.live
+	return &st, nil
+}
+func (f *fakeSecurityPolicyClient) Read(_ context.Context) (*winclient.SecurityPolicyState, error) {
+	if f.readErr != nil {
+		return nil, f.readErr
+	}
+	st := f.live
+	return &st, nil
+}
+
+func secpolPtr(v int64) *int64 { return &v }
+
+func secpolObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":                       tftypes.String,
+		"minimum_password_length":  tftypes.Number,
+		"password_history_size":    tftypes.Number,
+		"lockout_threshold":        tftypes.Number,
+		"lockout_duration_minutes": tftypes.Number,
+	}}
+}
+
+func secpolObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{}
+	for k, t := range secpolObjectType().AttributeTypes {
+		base[k] = tftypes.NewValue(t, nil)
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(secpolObjectType(), base)
+}
+
+func secpolNum(v any) tftypes.Value { return tftypes.NewValue(tftypes.Number, v) }
+
+// -----------------------------------------------------------------------------
+// Metadata + validators
+// -----------------------------------------------------------------------------
+
+func TestLocalSecurityPolicyMetadata(t *testing.T) {
+	r := &windowsLocalSecurityPolicyResource{}
+	resp := &resource.MetadataResponse{}
+	r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_local_security_policy" {
+		t.Errorf("TypeName = %q, want windows_local_security_policy", resp.TypeName)
+	}
+}
+
+func TestLockoutDurationValidator(t *testing.T) {
+	s := windowsLocalSecurityPolicySchemaDefinition()
+	cases := []struct {
+		name      string
+		threshold any
+		duration  any
+		wantErr   bool
+	}{
+		{"lockout with duration", 10, 30, false},
+		{"forever", 5, -1, false},
+		{"duration, threshold unmanaged", nil, 30, false},
+		{"threshold only", 0, nil, false},
+		{"duration with lockout off", 0, 30, true},
+		{"unknown threshold", tftypes.UnknownValue, 30, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &resource.ValidateConfigResponse{}
+			lockoutDurationValidator{}.ValidateResource(context.Background(), resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: s, Raw: secpolObj(map[string]tftypes.Value{
+					"lockout_threshold":        secpolNum(tc.threshold),
+					"lockout_duration_minutes": secpolNum(tc.duration),
+				})},
+			}, resp)
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("HasError = %v, want %v: %v", resp.Diagnostics.HasError(), tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+func TestLocalSecurityPolicyCreate_OnlyManagedSettings(t *testing.T) {
+	fake := &fakeSecurityPolicyClient{live: winclient.SecurityPolicyState{
+		MinimumPasswordLength: secpolPtr(14), PasswordHistorySize: secpolPtr(5),
+		LockoutThreshold: secpolPtr(10), LockoutDurationMinutes: secpolPtr(30),
+	}}
+	r := &windowsLocalSecurityPolicyResource{sp: fake}
+	s := windowsLocalSecurityPolicySchemaDefinition()
+	plan := secpolObj(map[string]tftypes.Value{
+		"id":                      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"minimum_password_length": secpolNum(14),
+		"lockout_threshold":       secpolNum(10),
+	})
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: secpolObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: plan}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	in := fake.setIn
+	if in == nil || in.MinimumPasswordLength == nil || *in.MinimumPasswordLength != 14 ||
+		in.LockoutThreshold == nil || *in.LockoutThreshold != 10 ||
+		in.PasswordHistorySize != nil || in.LockoutDurationMinutes != nil {
+		t.Errorf("set input = %+v", in)
+	}
+	var m windowsLocalSecurityPolicyModel
+	resp.State.Get(context.Background(), &m)
+	if m.ID.ValueString() != localSecurityPolicyID || m.MinimumPasswordLength.ValueInt64() != 14 ||
+		m.LockoutThreshold.ValueInt64() != 10 || !m.PasswordHistorySize.IsNull() || !m.LockoutDurationMinutes.IsNull() {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestLocalSecurityPolicyCreate_NotApplied(t *testing.T) {
+	fake := &fakeSecurityPolicyClient{setErr: winclient.NewSecurityPolicyError(winclient.SecurityPolicyErrorNotApplied,
+		"did not take effect", nil, map[string]string{"exit_code": "0"})}
+	r := &windowsLocalSecurityPolicyResource{sp: fake}
+	s := windowsLocalSecurityPolicySchemaDefinition()
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: secpolObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: secpolObj(map[string]tftypes.Value{
+		"minimum_password_length": secpolNum(14),
+	})}}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error")
+	}
+	d := resp.Diagnostics.Errors()[0].Detail()
+	for _, want := range []string{"did not take effect", "Kind: not_applied", "exit_code = 0"} {
+		if !strings.Contains(d, want) {
+			t.Errorf("detail missing %q: %s", want, d)
+		}
+	}
+}
+
+func TestLocalSecurityPolicyRead_Drift(t *testing.T) {
+	fake := &fakeSecurityPolicyClient{live: winclient.SecurityPolicyState{
+		MinimumPasswordLength: secpolPtr(8), PasswordHistorySize: secpolPtr(24), LockoutThreshold: secpolPtr(0),
+	}}
+	r := &windowsLocalSecurityPolicyResource{sp: fake}
+	s := windowsLocalSecurityPolicySchemaDefinition()
+	state := tfsdk.State{Schema: s, Raw: secpolObj(map[string]tftypes.Value{
+		"id":                       tftypes.NewValue(tftypes.String, localSecurityPolicyID),
+		"minimum_password_length":  secpolNum(14),
+		"lockout_threshold":        secpolNum(10),
+		"lockout_duration_minutes": secpolNum(30),
+	})}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: s, Raw: state.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var m windowsLocalSecurityPolicyModel
+	resp.State.Get(context.Background(), &m)
+	if m.MinimumPasswordLength.ValueInt64() != 8 || m.LockoutThreshold.ValueInt64() != 0 {
+		t.Errorf("managed settings must show the live value: %+v", m)
+	}
+	if !m.PasswordHistorySize.IsNull() {
+		t.Errorf("unmanaged password_history_size must stay null: %+v", m)
+	}
+	if !m.LockoutDurationMinutes.IsNull() {
+		t.Errorf("a duration absent from the export must read as null: %+v", m)
+	}
+}
+
+func TestLocalSecurityPolicyDelete_LeavesPolicy(t *testing.T) {
+	fake := &fakeSecurityPolicyClient{}
+	r := &windowsLocalSecurityPolicyResource{sp: fake}
+	s := windowsLocalSecurityPolicySchemaDefinition()
+	resp := &resource.DeleteResponse{}
+	r.Delete(context.Background(), resource.DeleteRequest{State: tfsdk.State{Schema: s, Raw: secpolObj(map[string]tftypes.Value{
+		"minimum_password_length": secpolNum(14),
+	})}}, resp)
+	if resp.Diagnostics.HasError() || fake.setIn != nil {
+		t.Errorf("Delete must not touch the host: set = %+v, diags = %v", fake.setIn, resp.Diagnostics)
+	}
+}
+
+func TestLocalSecurityPolicyImportState(t *testing.T) {
+	r := &windowsLocalSecurityPolicyResource{}
+	s := windowsLocalSecurityPolicySchemaDefinition()
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: secpolObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: localSecurityPolicyID}, resp)
+	var m windowsLocalSecurityPolicyModel
+	resp.State.Get(context.Background(), &m)
+	if resp.Diagnostics.HasError() || m.ID.ValueString() != localSecurityPolicyID {
+		t.Errorf("state = %+v, diags = %v", m, resp.Diagnostics)
+	}
+
+	resp = &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: secpolObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "secpol"}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for an unknown ID")
+	}
+}
@@ -10,9 +10,15 @@
 //   - Built-in accounts (RID 500/501/503/504) cannot be destroyed (ADR-LU-2, EC-2).
 //   - Password is Sensitive only (ADR-LU-3, TPF v1.13.0 constraint); injected via
 //     stdin, never in script body or logs.
-//   - Import accepts SID ("S-" prefix) or SAM name (EC-11).
+//   - Import accepts a SID string or a SAM name (EC-11); names that merely
+//     start with "S-" are imported by name.
 //   - account_never_expires=true conflicts with account_expires (EC-14).
 //   - account_expires must be in the future at Create time (EC-13).
+//   - change_password_at_logon sets the SAM "password expired" flag; it is a
+//     write-time action, not read back, and conflicts with
+//     password_never_expires = true.
+//   - groups, when set, is the authoritative set of local groups the user is a
+//     direct member of; when null, memberships are left alone.
 package provider
 
 import (
@@ -31,10 +37,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -81,6 +89,11 @@ type windowsLocalUserResource struct {
 // At most one of the two attributes may be set per the ConfigValidator on
 // the resource. Both share the same `password_wo_version` mechanism for
 // rotation detection during Update.
+//
+// A third path, `generate_password`, has the provider generate the password
+// and expose it as the Sensitive computed `generated_password`, persisted in
+// state like `password`. It excludes the other two and is only regenerated
+// when `generated_password_length` changes (generatedPasswordPlanModifier).
 type windowsLocalUserModel struct {
 	ID                       types.String `tfsdk:"id"`
 	SID                      types.String `tfsdk:"sid"`
@@ -90,14 +103,19 @@ type windowsLocalUserModel struct {
 	Password                 types.String `tfsdk:"password"`
 	PasswordWO               types.String `tfsdk:"password_wo"`
 	PasswordWoVersion        types.Int64  `tfsdk:"password_wo_version"`
+	GeneratePassword         types.Bool   `tfsdk:"generate_password"`
+	GeneratedPasswordLength  types.Int64  `tfsdk:"generated_password_length"`
+	GeneratedPassword        types.String `tfsdk:"generated_password"`
 	Enabled                  types.Bool   `tfsdk:"enabled"`
 	PasswordNeverExpires     types.Bool   `tfsdk:"password_never_expires"`
 	UserMayNotChangePassword types.Bool   `tfsdk:"user_may_not_change_password"`
+	ChangePasswordAtLogon    types.Bool   `tfsdk:"change_password_at_logon"`
 	AccountNeverExpires      types.Bool   `tfsdk:"account_never_expires"`
 	AccountExpires           types.String `tfsdk:"account_expires"`
 	LastLogon                types.String `tfsdk:"last_logon"`
 	PasswordLastSet          types.String `tfsdk:"password_last_set"`
 	PrincipalSource          types.String `tfsdk:"principal_source"`
+	Groups                   types.Set    `tfsdk:"groups"`
 }
 
 // ---------------------------------------------------------------------------
@@ -192,6 +210,37 @@ func (rfc3339Validator) ValidateString(
 	}
 }
 
+// accountExpiresNever is the account_expires value meaning "never expires".
+const accountExpiresNever = "never"
+
+// accountExpiresValidator accepts an RFC3339 timestamp or "never".
+type accountExpiresValidator struct{}
+
+// Description returns a plain-text description.
+func (accountExpiresValidator) Description(_ context.Context) string {
+	return "must be a valid RFC3339 timestamp (e.g. \"2027-12-31T23:59:59Z\") or \"never\""
+}
+
+// MarkdownDescription returns a Markdown description.
+func (v accountExpiresValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString validates account_expires.
+func (accountExpiresValidator) ValidateString(
+	ctx context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if req.ConfigValue.ValueString() == accountExpiresNever {
+		return
+	}
+	rfc3339Validator{}.ValidateString(ctx, req, resp)
+}
+
 // accountExpiresConflictValidator checks that account_expires is not set when
 // account_never_expires=true (EC-14, ADR-LU-8).
 //
@@ -231,6 +280,18 @@ func (accountExpiresConflictValidator) ValidateString(
 		return
 	}
 
+	if val == accountExpiresNever {
+		if !neverExpires.IsNull() && !neverExpires.IsUnknown() && !neverExpires.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Conflicting attributes (EC-14)",
+				"account_expires = \"never\" contradicts account_never_expires = false; "+
+					"remove account_never_expires or set a timestamp.",
+			)
+		}
+		return
+	}
+
 	if !neverExpires.IsNull() && !neverExpires.IsUnknown() && neverExpires.ValueBool() {
 		resp.Diagnostics.AddAttributeError(
 			req.Path,
@@ -241,6 +302,149 @@ func (accountExpiresConflictValidator) ValidateString(
 	}
 }
 
+// changePasswordAtLogonConflictValidator rejects change_password_at_logon =
+// true together with password_never_expires = true: Windows refuses to expire
+// a password that never expires.
+type changePasswordAtLogonConflictValidator struct{}
+
+// Description returns a plain-text description.
+func (changePasswordAtLogonConflictValidator) Description(_ context.Context) string {
+	return "change_password_at_logon = true is mutually exclusive with password_never_expires = true"
+}
+
+// MarkdownDescription returns a Markdown description.
+func (v changePasswordAtLogonConflictValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateBool enforces the mutual exclusion when both are true.
+func (changePasswordAtLogonConflictValidator) ValidateBool(
+	ctx context.Context,
+	req validator.BoolRequest,
+	resp *validator.BoolResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || !req.ConfigValue.ValueBool() {
+		return
+	}
+
+	var neverExpires types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_never_expires"), &neverExpires)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !neverExpires.IsNull() && !neverExpires.IsUnknown() && neverExpires.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Conflicting attributes",
+			"change_password_at_logon = true requires the password to expire; "+
+				"remove password_never_expires or set it to false.",
+		)
+	}
+}
+
+// Bounds of generated_password_length. 127 characters is the longest
+// password net.exe and the LocalAccounts cmdlets accept for a SAM account.
+const (
+	localUserGeneratedPasswordMinLength = 12
+	localUserGeneratedPasswordMaxLength = 127
+)
+
+// generatePasswordConflictValidator rejects generate_password = true
+// together with password or password_wo: the account can only have one
+// source of password.
+type generatePasswordConflictValidator struct{}
+
+// Description returns a plain-text description.
+func (generatePasswordConflictValidator) Description(_ context.Context) string {
+	return "generate_password = true is mutually exclusive with password and password_wo"
+}
+
+// MarkdownDescription returns a Markdown description.
+func (v generatePasswordConflictValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateBool enforces the mutual exclusion when generate_password is true.
+func (generatePasswordConflictValidator) ValidateBool(
+	ctx context.Context,
+	req validator.BoolRequest,
+	resp *validator.BoolResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || !req.ConfigValue.ValueBool() {
+		return
+	}
+
+	for _, attr := range []string{"password", "password_wo"} {
+		var v types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attr), &v)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !v.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Conflicting attributes",
+				fmt.Sprintf("generate_password = true generates the password; remove %s or set "+
+					"generate_password to false.", attr),
+			)
+		}
+	}
+}
+
+// generatedPasswordPlanModifier keeps generated_password stable across plans.
+// The value is only planned as unknown, which makes Create or Update generate
+// a new one, when generation is being turned on or generated_password_length
+// changes. With generate_password = false it is planned as null.
+type generatedPasswordPlanModifier struct{}
+
+// Description returns a plain-text description.
+func (generatedPasswordPlanModifier) Description(_ context.Context) string {
+	return "regenerates the password only when generate_password is turned on or generated_password_length changes"
+}
+
+// MarkdownDescription returns a Markdown description.
+func (m generatedPasswordPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// PlanModifyString implements planmodifier.String.
+func (generatedPasswordPlanModifier) PlanModifyString(
+	ctx context.Context,
+	req planmodifier.StringRequest,
+	resp *planmodifier.StringResponse,
+) {
+	var generate types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("generate_password"), &generate)...)
+	if resp.Diagnostics.HasError() || generate.IsUnknown() {
+		return
+	}
+	if !generate.ValueBool() {
+		resp.PlanValue = types.StringNull()
+		return
+	}
+	// Create, or generation turned on for an existing user: leave unknown.
+	if req.State.Raw.IsNull() || req.StateValue.IsNull() {
+		return
+	}
+
+	var planLength, stateLength types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("generated_password_length"), &planLength)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("generated_password_length"), &stateLength)...)
+	if resp.Diagnostics.HasError() || planLength.IsUnknown() || !planLength.Equal(stateLength) {
+		return
+	}
+	resp.PlanValue = req.StateValue
+}
+
+// generateLocalUserPassword returns a password of the given length with at
+// least one character from each of the four classes, which satisfies the
+// Windows complexity policy.
+func generateLocalUserPassword(length int64) (string, error) {
+	return generatePassword(int(length), []string{
+		passwordUpperChars, passwordLowerChars, passwordNumericChars, passwordSpecialChars,
+	})
+}
+
 // ---------------------------------------------------------------------------
 // Metadata / Schema / Configure
 // ---------------------------------------------------------------------------
@@ -383,6 +587,40 @@ func windowsLocalUserSchemaDefinition() schema.Schema {
 					int64validator.AtLeast(1),
 				},
 			},
+			"generate_password": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				MarkdownDescription: "Generate the password in the provider instead of taking it from `password` or " +
+					"`password_wo`. The generated password has `generated_password_length` characters with at least " +
+					"one upper-case letter, lower-case letter, digit and symbol, and is exposed as " +
+					"`generated_password`. It is only regenerated when `generated_password_length` changes; " +
+					"`password_wo_version` has no effect while this is `true`. Mutually exclusive with `password` " +
+					"and `password_wo`. Default `false`.",
+				Validators: []validator.Bool{
+					generatePasswordConflictValidator{},
+				},
+			},
+			"generated_password_length": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(passwordDefaultLength),
+				MarkdownDescription: fmt.Sprintf("Length of the generated password, between %d and %d. Changing it "+
+					"generates and applies a new password. Only used with `generate_password = true`. Default `%d`.",
+					localUserGeneratedPasswordMinLength, localUserGeneratedPasswordMaxLength, passwordDefaultLength),
+				Validators: []validator.Int64{
+					int64validator.Between(localUserGeneratedPasswordMinLength, localUserGeneratedPasswordMaxLength),
+				},
+			},
+			"generated_password": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				MarkdownDescription: "The password generated when `generate_password = true`, `null` otherwise. " +
+					"**Persisted in `terraform.tfstate`** so it can be handed to other resources or outputs.",
+				PlanModifiers: []planmodifier.String{
+					generatedPasswordPlanModifier{},
+				},
+			},
 
 			// ---- Account state & flags ----
 			"enabled": schema.BoolAttribute{
@@ -407,6 +645,19 @@ func windowsLocalUserSchemaDefinition() schema.Schema {
 					"Maps to -UserMayNotChangePassword (double-negative Windows semantics: " +
 					"true = cannot change, false = can change).",
 			},
+			"change_password_at_logon": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "When true, the user must change their password at next logon. Applied on " +
+					"Create, when the attribute changes to true, and again after every provider-initiated " +
+					"password rotation. Windows clears the flag once the user changes the password; this " +
+					"is not reported as drift. Setting it back to false clears a pending flag. " +
+					"Mutually exclusive with password_never_expires = true.",
+				Validators: []validator.Bool{
+					changePasswordAtLogonConflictValidator{},
+				},
+			},
 			"account_never_expires": schema.BoolAttribute{
 				Optional: true,
 				Computed: true,
@@ -418,15 +669,28 @@ func windowsLocalUserSchemaDefinition() schema.Schema {
 			"account_expires": schema.StringAttribute{
 				Optional: true,
 				MarkdownDescription: "RFC3339 timestamp at which the account expires " +
-					"(e.g. `\"2027-12-31T23:59:59Z\"`). When set, `account_never_expires` must " +
-					"be `false`. Must be in the future at Create time (EC-13). " +
-					"At Update time, past values are forwarded to Windows without blocking.",
+					"(e.g. `\"2027-12-31T23:59:59Z\"`), or `\"never\"`. A timestamp requires " +
+					"`account_never_expires = false` and must be in the future at Create time (EC-13); " +
+					"at Update time, past values are forwarded to Windows without blocking. " +
+					"`\"never\"` maps to `-AccountNeverExpires` and must not be combined with " +
+					"`account_never_expires = false`.",
 				Validators: []validator.String{
-					rfc3339Validator{},
+					accountExpiresValidator{},
 					accountExpiresConflictValidator{},
 				},
 			},
 
+			// ---- Group membership ----
+			"groups": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				MarkdownDescription: "Names of the local groups the user is a direct member of. When set, the list " +
+					"is authoritative: the user is added with `Add-LocalGroupMember` and removed from any other " +
+					"local group with `Remove-LocalGroupMember`, and memberships added outside Terraform show up " +
+					"as drift. Names are compared case-insensitively. When omitted, memberships are not managed. " +
+					"Do not also manage the same user's membership with `windows_local_group_member`.",
+			},
+
 			// ---- Computed / read-only ----
 			"last_logon": schema.StringAttribute{
 				Computed: true,
@@ -512,6 +776,7 @@ func (r *windowsLocalUserResource) Create(
 ) {
 	var plan windowsLocalUserModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(configPasswordWO(ctx, req.Config, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -544,17 +809,27 @@ func (r *windowsLocalUserResource) Create(
 
 	// Require password at Create time. Accept either the legacy `password`
 	// attribute or the WriteOnly `password_wo` (mutually exclusive — see
-	// ConfigValidators). The framework populates both fields of `plan` from
-	// the user configuration; for `password_wo` the value is dropped from
-	// state by resp.State.Set() automatically.
+	// ConfigValidators). `password` comes from the plan; `password_wo` was
+	// copied from the configuration above, and the framework drops it from
+	// state again in resp.State.Set().
 	password, attrPath := effectiveLocalUserPassword(plan)
+	if plan.GeneratePassword.ValueBool() {
+		generated, err := generateLocalUserPassword(plan.GeneratedPasswordLength.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Generate windows_local_user password failed", err.Error())
+			return
+		}
+		password = generated
+		plan.GeneratedPassword = types.StringValue(generated)
+	}
 	if password == "" {
 		resp.Diagnostics.AddAttributeError(
 			attrPath,
 			"password is required at Create time",
 			"Windows requires a password for local user accounts. "+
 				"Set either `password` (deprecated, persisted in state) or "+
-				"`password_wo` (WriteOnly, never persisted) in your configuration.",
+				"`password_wo` (WriteOnly, never persisted) in your configuration, "+
+				"or set `generate_password = true`.",
 		)
 		return
 	}
@@ -568,15 +843,60 @@ func (r *windowsLocalUserResource) Create(
 	}
 
 	next := stateFromUser(us)
+	keepAccountExpires(plan.AccountExpires, &next)
 	next.Password = plan.Password
 	// PasswordWO is intentionally NOT copied: WriteOnly attributes are
 	// dropped from state by the framework. Setting it on `next` would be a
 	// no-op but is omitted for clarity.
 	next.PasswordWoVersion = plan.PasswordWoVersion
+	setGeneratedPassword(plan, &next)
+
+	if plan.ChangePasswordAtLogon.ValueBool() {
+		if err := r.user.SetPasswordExpired(ctx, us.SID, true); err != nil {
+			// The account exists: record it with the flag unset so the next
+			// apply retries.
+			resp.Diagnostics.Append(resp.State.Set(ctx, &next)...)
+			addLocalUserDiag(&resp.Diagnostics, "Set change_password_at_logon on windows_local_user failed", err)
+			return
+		}
+		next.ChangePasswordAtLogon = plan.ChangePasswordAtLogon
+	}
+
+	if !plan.Groups.IsNull() && !plan.Groups.IsUnknown() {
+		groups, diags := localUserGroups(ctx, plan.Groups)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if err := r.user.AddToGroups(ctx, us.SID, groups); err != nil {
+			// The account exists: record it (with groups unset) so the next
+			// apply retries the memberships instead of re-creating the user.
+			resp.Diagnostics.Append(resp.State.Set(ctx, &next)...)
+			addLocalUserDiag(&resp.Diagnostics, "Add windows_local_user to groups failed", err)
+			return
+		}
+		next.Groups = plan.Groups
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &next)...)
 }
 
+// configPasswordWO copies `password_wo` from the configuration into m. The
+// framework nulls WriteOnly attributes in the plan and in prior state, so the
+// configuration is the only place the value exists during apply. An absent
+// configuration (unit tests that only build a plan) leaves m unchanged.
+func configPasswordWO(ctx context.Context, cfg tfsdk.Config, m *windowsLocalUserModel) diag.Diagnostics {
+	if cfg.Raw.IsNull() {
+		return nil
+	}
+	var pw types.String
+	diags := cfg.GetAttribute(ctx, path.Root("password_wo"), &pw)
+	if !diags.HasError() {
+		m.PasswordWO = pw
+	}
+	return diags
+}
+
 // effectiveLocalUserPassword returns the plaintext password to use for
 // Create / Update along with the schema path that holds it (used for
 // targeted diagnostics). At most one of `password` and `password_wo` may
@@ -641,10 +961,17 @@ func (r *windowsLocalUserResource) Read(
 	}
 
 	next := stateFromUser(us)
+	keepAccountExpires(state.AccountExpires, &next)
 
 	// Preserve sensitive/write-only fields (ADR-LU-3): Windows cannot return them.
 	next.Password = state.Password
 	next.PasswordWoVersion = state.PasswordWoVersion
+	setGeneratedPassword(state, &next)
+	// change_password_at_logon is a write-time action: Windows clears the
+	// flag when the user changes the password, which is not drift.
+	if !state.ChangePasswordAtLogon.IsNull() {
+		next.ChangePasswordAtLogon = state.ChangePasswordAtLogon
+	}
 
 	// EC-4 / ADR-LU: case-insensitive name normalisation.
 	// Keep prior state name when Windows casing differs only in case.
@@ -653,6 +980,19 @@ func (r *windowsLocalUserResource) Read(
 		next.Name = types.StringValue(priorName)
 	}
 
+	// Memberships are only read back when managed, so that drift is
+	// reported against the configured set.
+	if !state.Groups.IsNull() {
+		actual, err := r.user.Groups(ctx, sid)
+		if err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "Read windows_local_user groups failed", err)
+			return
+		}
+		var diags diag.Diagnostics
+		next.Groups, diags = localUserGroupsState(ctx, state.Groups, actual)
+		resp.Diagnostics.Append(diags...)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &next)...)
 }
 
@@ -664,7 +1004,10 @@ func (r *windowsLocalUserResource) Read(
 //  1. Rename (if name changed, case-insensitive check).
 //  2. Set-LocalUser (scalar attributes).
 //  3. SetPassword (if password_wo_version changed or password value changed).
-//  4. Enable / Disable (if enabled changed).
+//  4. Password-expired flag (change_password_at_logon), after any rotation
+//     since setting a password clears it.
+//  5. Enable / Disable (if enabled changed).
+//  6. Group membership (if groups is set), diffed against the host.
 //
 // All steps use -SID throughout. After all steps, state is refreshed via Read.
 func (r *windowsLocalUserResource) Update(
@@ -673,6 +1016,7 @@ func (r *windowsLocalUserResource) Update(
 	var plan, prior windowsLocalUserModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	resp.Diagnostics.Append(configPasswordWO(ctx, req.Config, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -724,11 +1068,29 @@ func (r *windowsLocalUserResource) Update(
 	//      in plan). Covers post-import recovery (EC-11) for the legacy
 	//      attribute. The WriteOnly equivalent is also covered through
 	//      version bumping.
+	//
+	// With generate_password = true the only signal is an unknown
+	// generated_password, planned by generatedPasswordPlanModifier when
+	// generation is turned on or generated_password_length changes.
 	needsPasswordRotation := !plan.PasswordWoVersion.Equal(prior.PasswordWoVersion) ||
 		(!plan.Password.IsNull() && !plan.Password.Equal(prior.Password)) ||
 		(!plan.Password.IsNull() && prior.Password.IsNull())
+	if plan.GeneratePassword.ValueBool() {
+		needsPasswordRotation = plan.GeneratedPassword.IsUnknown()
+	}
 
-	if needsPasswordRotation {
+	if needsPasswordRotation && plan.GeneratePassword.ValueBool() {
+		generated, err := generateLocalUserPassword(plan.GeneratedPasswordLength.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Generate windows_local_user password failed", err.Error())
+			return
+		}
+		if err := r.user.SetPassword(ctx, sid, generated); err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "SetPassword windows_local_user failed", err)
+			return
+		}
+		plan.GeneratedPassword = types.StringValue(generated)
+	} else if needsPasswordRotation {
 		pw, attrPath := effectiveLocalUserPassword(plan)
 		if pw == "" {
 			resp.Diagnostics.AddAttributeError(
@@ -746,7 +1108,22 @@ func (r *windowsLocalUserResource) Update(
 		}
 	}
 
-	// Step 4: Enable / Disable if enabled changed.
+	// Step 4: change_password_at_logon. Re-apply after a rotation, which
+	// resets the flag on the host.
+	switch {
+	case plan.ChangePasswordAtLogon.ValueBool() && (!prior.ChangePasswordAtLogon.ValueBool() || needsPasswordRotation):
+		if err := r.user.SetPasswordExpired(ctx, sid, true); err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "Set change_password_at_logon on windows_local_user failed", err)
+			return
+		}
+	case !plan.ChangePasswordAtLogon.ValueBool() && prior.ChangePasswordAtLogon.ValueBool():
+		if err := r.user.SetPasswordExpired(ctx, sid, false); err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "Clear change_password_at_logon on windows_local_user failed", err)
+			return
+		}
+	}
+
+	// Step 5: Enable / Disable if enabled changed.
 	if !plan.Enabled.Equal(prior.Enabled) {
 		var err error
 		if plan.Enabled.ValueBool() {
@@ -760,6 +1137,32 @@ func (r *windowsLocalUserResource) Update(
 		}
 	}
 
+	// Step 6: Group membership. The diff is taken against the host rather
+	// than prior state so that memberships changed outside Terraform are
+	// converged too. A null plan stops managing memberships without
+	// removing any.
+	if !plan.Groups.IsNull() && !plan.Groups.IsUnknown() {
+		want, diags := localUserGroups(ctx, plan.Groups)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		actual, err := r.user.Groups(ctx, sid)
+		if err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "Read windows_local_user groups failed", err)
+			return
+		}
+		add, remove := diffLocalUserGroups(want, actual)
+		if err := r.user.AddToGroups(ctx, sid, add); err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "Add windows_local_user to groups failed", err)
+			return
+		}
+		if err := r.user.RemoveFromGroups(ctx, sid, remove); err != nil {
+			addLocalUserDiag(&resp.Diagnostics, "Remove windows_local_user from groups failed", err)
+			return
+		}
+	}
+
 	// Refresh state after all mutations.
 	us, err := r.user.Read(ctx, sid)
 	if err != nil {
@@ -772,8 +1175,13 @@ func (r *windowsLocalUserResource) Update(
 	}
 
 	next := stateFromUser(us)
+	keepAccountExpires(plan.AccountExpires, &next)
 	next.Password = plan.Password
 	next.PasswordWoVersion = plan.PasswordWoVersion
+	setGeneratedPassword(plan, &next)
+
+	next.ChangePasswordAtLogon = plan.ChangePasswordAtLogon
+	next.Groups = plan.Groups
 
 	// EC-4: if name was equal (case-fold), keep plan name to avoid diff.
 	if strings.EqualFold(us.Name, plan.Name.ValueString()) {
@@ -823,7 +1231,8 @@ func (r *windowsLocalUserResource) Delete(
 // ImportState handles terraform import for windows_local_user.
 //
 // The import ID may be either:
-//   - A SID string (auto-detected: starts with "S-") → ImportBySID (EC-11)
+//   - A SID string (auto-detected with winclient.IsSIDString, e.g.
+//     S-1-5-21-…-1001) → ImportBySID (EC-11)
 //   - A SAM account name (all other values) → ImportByName (EC-11)
 //
 // After import, password is null. The operator MUST set the password in HCL
@@ -836,7 +1245,7 @@ func (r *windowsLocalUserResource) ImportState(
 	var us *winclient.UserState
 	var err error
 
-	if strings.HasPrefix(importID, "S-") {
+	if winclient.IsSIDString(importID) {
 		us, err = r.user.ImportBySID(ctx, importID)
 		if err != nil {
 			addLocalUserDiag(&resp.Diagnostics,
@@ -864,6 +1273,7 @@ func (r *windowsLocalUserResource) ImportState(
 	// Password and PasswordWoVersion are null after import (EC-11, ADR-LU-3).
 	next.Password = types.StringNull()
 	next.PasswordWoVersion = types.Int64Null()
+	setGeneratedPassword(windowsLocalUserModel{}, &next)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &next)...)
 }
@@ -885,10 +1295,12 @@ func stateFromUser(us *winclient.UserState) windowsLocalUserModel {
 		Enabled:                  types.BoolValue(us.Enabled),
 		PasswordNeverExpires:     types.BoolValue(us.PasswordNeverExpires),
 		UserMayNotChangePassword: types.BoolValue(us.UserMayNotChangePassword),
+		ChangePasswordAtLogon:    types.BoolValue(false),
 		AccountNeverExpires:      types.BoolValue(us.AccountNeverExpires),
 		LastLogon:                types.StringValue(us.LastLogon),
 		PasswordLastSet:          types.StringValue(us.PasswordLastSet),
 		PrincipalSource:          types.StringValue(us.PrincipalSource),
+		Groups:                   types.SetNull(types.StringType),
 	}
 
 	if us.AccountExpires != "" {
@@ -901,8 +1313,110 @@ func stateFromUser(us *winclient.UserState) windowsLocalUserModel {
 	return m
 }
 
+// setGeneratedPassword copies the generate_password settings and the
+// generated password from src into next: none of them can be read back
+// from Windows. Null settings (state written before the attributes existed)
+// are replaced by their defaults, and a generated password that is still
+// unknown is recorded as null.
+func setGeneratedPassword(src windowsLocalUserModel, next *windowsLocalUserModel) {
+	next.GeneratePassword = src.GeneratePassword
+	if next.GeneratePassword.IsNull() {
+		next.GeneratePassword = types.BoolValue(false)
+	}
+	next.GeneratedPasswordLength = src.GeneratedPasswordLength
+	if next.GeneratedPasswordLength.IsNull() {
+		next.GeneratedPasswordLength = types.Int64Value(passwordDefaultLength)
+	}
+	next.GeneratedPassword = src.GeneratedPassword
+	if next.GeneratedPassword.IsUnknown() {
+		next.GeneratedPassword = types.StringNull()
+	}
+}
+
+// keepAccountExpires carries the configured spelling of account_expires
+// into next when it describes what Windows reports: "never" for an account
+// that never expires, or a timestamp in another offset for the same instant.
+// Without it, both would show as a perpetual diff.
+func keepAccountExpires(configured types.String, next *windowsLocalUserModel) {
+	if configured.IsNull() || configured.IsUnknown() {
+		return
+	}
+	want := configured.ValueString()
+	if want == accountExpiresNever {
+		if next.AccountNeverExpires.ValueBool() {
+			next.AccountExpires = configured
+		}
+		return
+	}
+	if next.AccountExpires.IsNull() {
+		return
+	}
+	wt, err1 := time.Parse(time.RFC3339, want)
+	gt, err2 := time.Parse(time.RFC3339, next.AccountExpires.ValueString())
+	if err1 == nil && err2 == nil && wt.Equal(gt) {
+		next.AccountExpires = configured
+	}
+}
+
+// localUserGroups extracts the group names from a groups set.
+func localUserGroups(ctx context.Context, set types.Set) ([]string, diag.Diagnostics) {
+	var groups []string
+	diags := set.ElementsAs(ctx, &groups, false)
+	return groups, diags
+}
+
+// localUserGroupsState builds the groups value for state from the groups
+// reported by the host, keeping the configured spelling of names that differ
+// only in case.
+func localUserGroupsState(ctx context.Context, configured types.Set, actual []string) (types.Set, diag.Diagnostics) {
+	known, diags := localUserGroups(ctx, configured)
+	out := make([]string, 0, len(actual))
+	for _, a := range actual {
+		name := a
+		for _, k := range known {
+			if strings.EqualFold(k, a) {
+				name = k
+				break
+			}
+		}
+		out = append(out, name)
+	}
+	set, d := types.SetValueFrom(ctx, types.StringType, out)
+	diags.Append(d...)
+	return set, diags
+}
+
+// diffLocalUserGroups returns the groups in want the user is not yet in and
+// the groups in actual that are not wanted, comparing names case-insensitively.
+func diffLocalUserGroups(want, actual []string) (add, remove []string) {
+	contains := func(list []string, name string) bool {
+		for _, n := range list {
+			if strings.EqualFold(n, name) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, w := range want {
+		if !contains(actual, w) {
+			add = append(add, w)
+		}
+	}
+	for _, a := range actual {
+		if !contains(want, a) {
+			remove = append(remove, a)
+		}
+	}
+	return add, remove
+}
+
 // planToUserInput converts a plan/state model into a winclient.UserInput.
+// account_expires = "never" is sent as -AccountNeverExpires.
 func planToUserInput(m windowsLocalUserModel) winclient.UserInput {
+	if m.AccountExpires.ValueString() == accountExpiresNever {
+		m.AccountNeverExpires = types.BoolValue(true)
+		m.AccountExpires = types.StringNull()
+	}
 	return winclient.UserInput{
 		Name:                     m.Name.ValueString(),
 		FullName:                 m.FullName.ValueString(),
@@ -20,10 +20,13 @@
 //	scalarAttrsChanged: changed / unchanged
 //	addLocalUserDiag: both error paths
 //	Schema: password sensitive, name required, sid computed
+//	change_password_at_logon: conflict validator, create/update/read handling
+//	groups: add on create, drift on read, diff on update
 package provider
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -32,6 +35,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -60,15 +64,27 @@ type fakeLocalUserClient struct {
 	importByNameErr error
 	importBySIDOut  *winclient.UserState
 	importBySIDErr  error
+	groupsOut       []string
+	groupsErr       error
+	addGroupsErr    error
+	removeGroupsErr error
+	setExpiredErr   error
 
 	// Call capture
+	lastRenameSID      string
 	lastRenameNewName  string
 	lastSetPasswordSID string
+	lastSetPassword    string
+	createPassword     string
 	enableCalled       bool
 	disableCalled      bool
+	addedGroups        []string
+	removedGroups      []string
+	passwordExpired    []bool
 }
 
-func (f *fakeLocalUserClient) Create(_ context.Context, _ winclient.UserInput, _ string) (*winclient.UserState, error) {
+func (f *fakeLocalUserClient) Create(_ context.Context, _ winclient.UserInput, password string) (*winclient.UserState, error) {
+	f.createPassword = password
 	return f.createOut, f.createErr
 }
 func (f *fakeLocalUserClient) Read(_ context.Context, _ string) (*winclient.UserState, error) {
@@ -77,14 +93,20 @@ func (f *fakeLocalUserClient) Read(_ context.Context, _ string) (*winclient.User
 func (f *fakeLocalUserClient) Update(_ context.Context, _ string, _ winclient.UserInput) (*winclient.UserState, error) {
 	return f.updateOut, f.updateErr
 }
-func (f *fakeLocalUserClient) Rename(_ context.Context, _ string, newName string) error {
+func (f *fakeLocalUserClient) Rename(_ context.Context, sid string, newName string) error {
+	f.lastRenameSID = sid
 	f.lastRenameNewName = newName
 	return f.renameErr
 }
-func (f *fakeLocalUserClient) SetPassword(_ context.Context, sid string, _ string) error {
+func (f *fakeLocalUserClient) SetPassword(_ context.Context, sid string, password string) error {
 	f.lastSetPasswordSID = sid
+	f.lastSetPassword = password
 	return f.setPasswordErr
 }
+func (f *fakeLocalUserClient) SetPasswordExpired(_ context.Context, _ string, expired bool) error {
+	f.passwordExpired = append(f.passwordExpired, expired)
+	return f.setExpiredErr
+}
 func (f *fakeLocalUserClient) Enable(_ context.Context, _ string) error {
 	f.enableCalled = true
 	return f.enableErr
@@ -102,6 +124,17 @@ func (f *fakeLocalUserClient) ImportByName(_ context.Context, _ string) (*wincli
 func (f *fakeLocalUserClient) ImportBySID(_ context.Context, _ string) (*winclient.UserState, error) {
 	return f.importBySIDOut, f.importBySIDErr
 }
+func (f *fakeLocalUserClient) Groups(_ context.Context, _ string) ([]string, error) {
+	return f.groupsOut, f.groupsErr
+}
+func (f *fakeLocalUserClient) AddToGroups(_ context.Context, _ string, groups []string) error {
+	f.addedGroups = append(f.addedGroups, groups...)
+	return f.addGroupsErr
+}
+func (f *fakeLocalUserClient) RemoveFromGroups(_ context.Context, _ string, groups []string) error {
+	f.removedGroups = append(f.removedGroups, groups...)
+	return f.removeGroupsErr
+}
 
 // ---------------------------------------------------------------------------
 // tftypes helpers for local_user schema
@@ -117,14 +150,19 @@ func localUserObjectType() tftypes.Object {
 		"password":                     tftypes.String,
 		"password_wo":                  tftypes.String,
 		"password_wo_version":          tftypes.Number,
+		"generate_password":            tftypes.Bool,
+		"generated_password_length":    tftypes.Number,
+		"generated_password":           tftypes.String,
 		"enabled":                      tftypes.Bool,
 		"password_never_expires":       tftypes.Bool,
 		"user_may_not_change_password": tftypes.Bool,
+		"change_password_at_logon":     tftypes.Bool,
 		"account_never_expires":        tftypes.Bool,
 		"account_expires":              tftypes.String,
 		"last_logon":                   tftypes.String,
 		"password_last_set":            tftypes.String,
 		"principal_source":             tftypes.String,
+		"groups":                       tftypes.Set{ElementType: tftypes.String},
 	}}
 }
 
@@ -139,14 +177,19 @@ func luObj(overrides map[string]tftypes.Value) tftypes.Value {
 		"password":                     tftypes.NewValue(tftypes.String, "P@ssw0rd!"),
 		"password_wo":                  tftypes.NewValue(tftypes.String, nil),
 		"password_wo_version":          tftypes.NewValue(tftypes.Number, nil),
+		"generate_password":            tftypes.NewValue(tftypes.Bool, false),
+		"generated_password_length":    tftypes.NewValue(tftypes.Number, 24),
+		"generated_password":           tftypes.NewValue(tftypes.String, nil),
 		"enabled":                      tftypes.NewValue(tftypes.Bool, true),
 		"password_never_expires":       tftypes.NewValue(tftypes.Bool, false),
 		"user_may_not_change_password": tftypes.NewValue(tftypes.Bool, false),
+		"change_password_at_logon":     tftypes.NewValue(tftypes.Bool, false),
 		"account_never_expires":        tftypes.NewValue(tftypes.Bool, true),
 		"account_expires":              tftypes.NewValue(tftypes.String, nil),
 		"last_logon":                   tftypes.NewValue(tftypes.String, nil),
 		"password_last_set":            tftypes.NewValue(tftypes.String, nil),
 		"principal_source":             tftypes.NewValue(tftypes.String, nil),
+		"groups":                       tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, nil),
 	}
 	for k, v := range overrides {
 		base[k] = v
@@ -211,7 +254,8 @@ func TestLocalUserSchema_HasRequiredAttributes(t *testing.T) {
 	s := windowsLocalUserSchemaDefinition()
 	want := []string{
 		"id", "sid", "name", "full_name", "description", "password",
-		"password_wo", "password_wo_version", "enabled", "password_never_expires",
+		"password_wo", "password_wo_version", "generate_password", "generated_password_length",
+		"generated_password", "enabled", "password_never_expires",
 		"user_may_not_change_password", "account_never_expires",
 		"account_expires", "last_logon", "password_last_set", "principal_source",
 	}
@@ -553,10 +597,92 @@ func TestAccountExpiresConflictValidator_NeverExpiresFalse_WithExpires_OK(t *tes
 	}
 }
 
+func TestAccountExpiresConflictValidator_Never(t *testing.T) {
+	v := accountExpiresConflictValidator{}
+	s := windowsLocalUserSchemaDefinition()
+	for _, tc := range []struct {
+		neverExpires any
+		wantErr      bool
+	}{
+		{nil, false},
+		{true, false},
+		{false, true},
+	} {
+		cfg := tfsdk.Config{Schema: s, Raw: luObj(map[string]tftypes.Value{
+			"account_never_expires": tftypes.NewValue(tftypes.Bool, tc.neverExpires),
+			"account_expires":       tftypes.NewValue(tftypes.String, "never"),
+		})}
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("account_expires"),
+			ConfigValue: types.StringValue("never"),
+			Config:      cfg,
+		}, resp)
+		if resp.Diagnostics.HasError() != tc.wantErr {
+			t.Errorf("account_never_expires=%v: HasError = %v, want %v", tc.neverExpires, resp.Diagnostics.HasError(), tc.wantErr)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// accountExpiresValidator / keepAccountExpires
+// ---------------------------------------------------------------------------
+
+func TestAccountExpiresValidator(t *testing.T) {
+	v := accountExpiresValidator{}
+	for in, wantErr := range map[string]bool{
+		"never":                false,
+		"2027-12-31T23:59:59Z": false,
+		"Never":                true,
+		"2027/12/31":           true,
+	} {
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path: path.Root("account_expires"), ConfigValue: types.StringValue(in),
+		}, resp)
+		if resp.Diagnostics.HasError() != wantErr {
+			t.Errorf("%q: HasError = %v, want %v", in, resp.Diagnostics.HasError(), wantErr)
+		}
+	}
+}
+
+func TestKeepAccountExpires(t *testing.T) {
+	never := windowsLocalUserModel{AccountNeverExpires: types.BoolValue(true), AccountExpires: types.StringNull()}
+	keepAccountExpires(types.StringValue("never"), &never)
+	if never.AccountExpires.ValueString() != "never" {
+		t.Errorf("never: AccountExpires = %v", never.AccountExpires)
+	}
+
+	expiring := windowsLocalUserModel{AccountNeverExpires: types.BoolValue(false), AccountExpires: types.StringValue("2027-12-31T22:00:00Z")}
+	keepAccountExpires(types.StringValue("never"), &expiring)
+	if expiring.AccountExpires.ValueString() != "2027-12-31T22:00:00Z" {
+		t.Errorf("drift from never must surface: AccountExpires = %v", expiring.AccountExpires)
+	}
+	keepAccountExpires(types.StringValue("2028-01-01T00:00:00+02:00"), &expiring)
+	if expiring.AccountExpires.ValueString() != "2028-01-01T00:00:00+02:00" {
+		t.Errorf("same instant: AccountExpires = %v", expiring.AccountExpires)
+	}
+	keepAccountExpires(types.StringValue("2029-01-01T00:00:00Z"), &expiring)
+	if expiring.AccountExpires.ValueString() != "2028-01-01T00:00:00+02:00" {
+		t.Errorf("different instant must not be masked: AccountExpires = %v", expiring.AccountExpires)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // planToUserInput
 // ---------------------------------------------------------------------------
 
+func TestPlanToUserInput_AccountExpiresNever(t *testing.T) {
+	input := planToUserInput(windowsLocalUserModel{
+		Name:                types.StringValue("svc"),
+		AccountNeverExpires: types.BoolValue(true),
+		AccountExpires:      types.StringValue("never"),
+	})
+	if !input.AccountNeverExpires || input.AccountExpires != "" {
+		t.Errorf("input = %+v, want -AccountNeverExpires and no date", input)
+	}
+}
+
 func TestPlanToUserInput(t *testing.T) {
 	m := windowsLocalUserModel{
 		Name:                     types.StringValue("alice"),
@@ -774,6 +900,152 @@ func TestLocalUserCreate_HappyPath(t *testing.T) {
 	}
 }
 
+func luGroupsValue(names ...string) tftypes.Value {
+	elems := make([]tftypes.Value, len(names))
+	for i, n := range names {
+		elems[i] = tftypes.NewValue(tftypes.String, n)
+	}
+	return tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elems)
+}
+
+func luGroupsOf(t *testing.T, st tfsdk.State) []string {
+	t.Helper()
+	var m windowsLocalUserModel
+	if d := st.Get(context.Background(), &m); d.HasError() {
+		t.Fatalf("State.Get: %v", d)
+	}
+	if m.Groups.IsNull() {
+		return nil
+	}
+	var out []string
+	m.Groups.ElementsAs(context.Background(), &out, false)
+	return out
+}
+
+func TestLocalUserCreate_AddsGroups(t *testing.T) {
+	fake := &fakeLocalUserClient{createOut: okUserState("alice", "S-1-5-21-111-222-333-1001")}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	plan := tfsdk.Plan{Schema: s, Raw: luObj(map[string]tftypes.Value{"groups": luGroupsValue("Users")})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", luDiagDetails(resp.Diagnostics))
+	}
+	if strings.Join(fake.addedGroups, ",") != "Users" {
+		t.Errorf("AddToGroups got %v", fake.addedGroups)
+	}
+	if got := luGroupsOf(t, resp.State); len(got) != 1 || got[0] != "Users" {
+		t.Errorf("state groups = %v", got)
+	}
+}
+
+func TestLocalUserCreate_GroupFailureKeepsUser(t *testing.T) {
+	fake := &fakeLocalUserClient{
+		createOut:    okUserState("alice", "S-1-5-21-111-222-333-1001"),
+		addGroupsErr: winclient.NewLocalUserError(winclient.LocalUserErrorGroupNotFound, "Nope: group not found", nil, nil),
+	}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	plan := tfsdk.Plan{Schema: s, Raw: luObj(map[string]tftypes.Value{"groups": luGroupsValue("Nope")})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for the failed membership")
+	}
+	if resp.State.Raw.IsNull() {
+		t.Fatal("the created user must be kept in state")
+	}
+	if got := luGroupsOf(t, resp.State); got != nil {
+		t.Errorf("groups must be left unset so the next apply retries, got %v", got)
+	}
+}
+
+func TestLocalUserRead_GroupsDrift(t *testing.T) {
+	fake := &fakeLocalUserClient{
+		readOut:   okUserState("alice", "S-1-5-21-111-222-333-1001"),
+		groupsOut: []string{"Administrators", "USERS"},
+	}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	st := tfsdk.State{Schema: s, Raw: luObj(map[string]tftypes.Value{
+		"sid":    tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+		"groups": luGroupsValue("Users"),
+	})}
+	resp := &resource.ReadResponse{State: st}
+	r.Read(context.Background(), resource.ReadRequest{State: st}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", luDiagDetails(resp.Diagnostics))
+	}
+	got := luGroupsOf(t, resp.State)
+	if len(got) != 2 || !strings.Contains(strings.Join(got, ","), "Administrators") ||
+		!strings.Contains(strings.Join(got, ","), "Users") {
+		t.Errorf("state groups = %v, want the configured spelling plus the drifted group", got)
+	}
+}
+
+func TestLocalUserRead_UnmanagedGroupsNotQueried(t *testing.T) {
+	fake := &fakeLocalUserClient{
+		readOut:   okUserState("alice", "S-1-5-21-111-222-333-1001"),
+		groupsErr: winclient.NewLocalUserError(winclient.LocalUserErrorUnknown, "must not be called", nil, nil),
+	}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	st := tfsdk.State{Schema: s, Raw: luObj(map[string]tftypes.Value{
+		"sid": tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+	})}
+	resp := &resource.ReadResponse{State: st}
+	r.Read(context.Background(), resource.ReadRequest{State: st}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", luDiagDetails(resp.Diagnostics))
+	}
+}
+
+func TestLocalUserUpdate_GroupsDiff(t *testing.T) {
+	fake := &fakeLocalUserClient{
+		readOut:   okUserState("alice", "S-1-5-21-111-222-333-1001"),
+		groupsOut: []string{"users", "Backup Operators"},
+	}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	base := map[string]tftypes.Value{
+		"sid": tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+		"id":  tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+	}
+	rawState := luObj(map[string]tftypes.Value{"sid": base["sid"], "id": base["id"], "groups": luGroupsValue("Users")})
+	rawPlan := luObj(map[string]tftypes.Value{"sid": base["sid"], "id": base["id"], "groups": luGroupsValue("Users", "Remote Desktop Users")})
+
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: rawState}}
+	r.Update(context.Background(), resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: s, Raw: rawPlan},
+		State: tfsdk.State{Schema: s, Raw: rawState},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", luDiagDetails(resp.Diagnostics))
+	}
+	if strings.Join(fake.addedGroups, ",") != "Remote Desktop Users" {
+		t.Errorf("added = %v", fake.addedGroups)
+	}
+	if strings.Join(fake.removedGroups, ",") != "Backup Operators" {
+		t.Errorf("removed = %v", fake.removedGroups)
+	}
+	if got := luGroupsOf(t, resp.State); len(got) != 2 {
+		t.Errorf("state groups = %v", got)
+	}
+}
+
+func TestDiffLocalUserGroups(t *testing.T) {
+	add, remove := diffLocalUserGroups([]string{"Users", "Administrators"}, []string{"USERS", "Guests"})
+	if strings.Join(add, ",") != "Administrators" || strings.Join(remove, ",") != "Guests" {
+		t.Errorf("add = %v, remove = %v", add, remove)
+	}
+}
+
 func TestLocalUserCreate_EC1_AlreadyExists(t *testing.T) {
 	fake := &fakeLocalUserClient{
 		createErr: winclient.NewLocalUserError(winclient.LocalUserErrorAlreadyExists,
@@ -945,6 +1217,23 @@ func TestLocalUserUpdate_Rename(t *testing.T) {
 	if fake.lastRenameNewName != "alice-new" {
 		t.Errorf("Rename called with %q, want alice-new", fake.lastRenameNewName)
 	}
+	if fake.lastRenameSID != "S-1-5-21-111-222-333-1001" {
+		t.Errorf("Rename must be keyed by the stored SID, got %q", fake.lastRenameSID)
+	}
+
+	// The rename is in place: the account keeps its SID, so id and sid in the
+	// new state are unchanged while name follows the plan.
+	var got windowsLocalUserModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("State.Get: %v", luDiagDetails(resp.Diagnostics))
+	}
+	if got.SID.ValueString() != "S-1-5-21-111-222-333-1001" || got.ID.ValueString() != "S-1-5-21-111-222-333-1001" {
+		t.Errorf("SID changed across rename: id=%q sid=%q", got.ID.ValueString(), got.SID.ValueString())
+	}
+	if got.Name.ValueString() != "alice-new" {
+		t.Errorf("name = %q, want alice-new", got.Name.ValueString())
+	}
 }
 
 func TestLocalUserUpdate_PasswordRotation(t *testing.T) {
@@ -982,6 +1271,348 @@ func TestLocalUserUpdate_PasswordRotation(t *testing.T) {
 	}
 }
 
+// TestLocalUserCreate_PasswordWOFromConfig reproduces a real apply: the
+// framework nulls the WriteOnly password_wo in the plan, so Create must take
+// it from the configuration.
+func TestLocalUserCreate_PasswordWOFromConfig(t *testing.T) {
+	fake := &fakeLocalUserClient{createOut: okUserState("alice", "S-1-5-21-111-222-333-1001")}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	noPassword := map[string]tftypes.Value{
+		"password":            tftypes.NewValue(tftypes.String, nil),
+		"password_wo_version": tftypes.NewValue(tftypes.Number, 1),
+	}
+	withWO := map[string]tftypes.Value{
+		"password":            tftypes.NewValue(tftypes.String, nil),
+		"password_wo":         tftypes.NewValue(tftypes.String, "Wr1te-0nly!"),
+		"password_wo_version": tftypes.NewValue(tftypes.Number, 1),
+	}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	r.Create(context.Background(), resource.CreateRequest{
+		Plan:   tfsdk.Plan{Schema: s, Raw: luObj(noPassword)},
+		Config: tfsdk.Config{Schema: s, Raw: luObj(withWO)},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", luDiagDetails(resp.Diagnostics))
+	}
+	if fake.createPassword != "Wr1te-0nly!" {
+		t.Errorf("Create password = %q, want the password_wo value from config", fake.createPassword)
+	}
+	var got windowsLocalUserModel
+	resp.State.Get(context.Background(), &got)
+	if !got.Password.IsNull() || got.PasswordWoVersion.ValueInt64() != 1 {
+		t.Errorf("state password = %v, password_wo_version = %v", got.Password, got.PasswordWoVersion)
+	}
+}
+
+func TestLocalUserUpdate_PasswordWORotationFromConfig(t *testing.T) {
+	fake := &fakeLocalUserClient{readOut: okUserState("alice", "S-1-5-21-111-222-333-1001")}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	common := func(version int, wo any) tftypes.Value {
+		return luObj(map[string]tftypes.Value{
+			"sid":                 tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+			"id":                  tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+			"password":            tftypes.NewValue(tftypes.String, nil),
+			"password_wo":         tftypes.NewValue(tftypes.String, wo),
+			"password_wo_version": tftypes.NewValue(tftypes.Number, version),
+		})
+	}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: common(1, nil)}}
+	r.Update(context.Background(), resource.UpdateRequest{
+		Plan:   tfsdk.Plan{Schema: s, Raw: common(2, nil)},
+		State:  tfsdk.State{Schema: s, Raw: common(1, nil)},
+		Config: tfsdk.Config{Schema: s, Raw: common(2, "R0tated-Secret!")},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", luDiagDetails(resp.Diagnostics))
+	}
+	if fake.lastSetPassword != "R0tated-Secret!" {
+		t.Errorf("SetPassword password = %q, want the password_wo value from config", fake.lastSetPassword)
+	}
+}
+
+func TestGenerateLocalUserPassword_Complexity(t *testing.T) {
+	for _, n := range []int64{localUserGeneratedPasswordMinLength, 24, localUserGeneratedPasswordMaxLength} {
+		pw, err := generateLocalUserPassword(n)
+		if err != nil {
+			t.Fatalf("generateLocalUserPassword(%d): %v", n, err)
+		}
+		if int64(len(pw)) != n {
+			t.Errorf("len = %d, want %d", len(pw), n)
+		}
+		for _, class := range []string{passwordUpperChars, passwordLowerChars, passwordNumericChars, passwordSpecialChars} {
+			if !strings.ContainsAny(pw, class) {
+				t.Errorf("%q has no character from %q", pw, class)
+			}
+		}
+	}
+}
+
+func TestGeneratePasswordConflictValidator(t *testing.T) {
+	s := windowsLocalUserSchemaDefinition()
+	cases := []struct {
+		name     string
+		generate bool
+		password any
+		wo       any
+		wantErr  bool
+	}{
+		{name: "generate alone", generate: true},
+		{name: "generate with password", generate: true, password: "P@ssw0rd!", wantErr: true},
+		{name: "generate with password_wo", generate: true, wo: "P@ssw0rd!", wantErr: true},
+		{name: "password without generate", password: "P@ssw0rd!"},
+	}
+	for _, c := range cases {
+		cfg := tfsdk.Config{Schema: s, Raw: luObj(map[string]tftypes.Value{
+			"generate_password": tftypes.NewValue(tftypes.Bool, c.generate),
+			"password":          tftypes.NewValue(tftypes.String, c.password),
+			"password_wo":       tftypes.NewValue(tftypes.String, c.wo),
+		})}
+		resp := &validator.BoolResponse{}
+		generatePasswordConflictValidator{}.ValidateBool(context.Background(), validator.BoolRequest{
+			Path:        path.Root("generate_password"),
+			ConfigValue: types.BoolValue(c.generate),
+			Config:      cfg,
+		}, resp)
+		if resp.Diagnostics.HasError() != c.wantErr {
+			t.Errorf("%s: HasError = %v, want %v", c.name, resp.Diagnostics.HasError(), c.wantErr)
+		}
+	}
+}
+
+func TestGeneratedPasswordPlanModifier(t *testing.T) {
+	s := windowsLocalUserSchemaDefinition()
+	generated := func(generate bool, length int, pw any) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"password":                  tftypes.NewValue(tftypes.String, nil),
+			"generate_password":         tftypes.NewValue(tftypes.Bool, generate),
+			"generated_password_length": tftypes.NewValue(tftypes.Number, length),
+			"generated_password":        tftypes.NewValue(tftypes.String, pw),
+		}
+	}
+	unknownPW := tftypes.UnknownValue
+	cases := []struct {
+		name        string
+		plan, state map[string]tftypes.Value // state nil = create
+		want        types.String
+	}{
+		{name: "create", plan: generated(true, 24, unknownPW), want: types.StringUnknown()},
+		{name: "create without generation", plan: generated(false, 24, unknownPW), want: types.StringNull()},
+		{name: "unchanged", plan: generated(true, 24, unknownPW), state: generated(true, 24, "Old-Secret-1"),
+			want: types.StringValue("Old-Secret-1")},
+		{name: "length changed", plan: generated(true, 32, unknownPW), state: generated(true, 24, "Old-Secret-1"),
+			want: types.StringUnknown()},
+		{name: "turned on", plan: generated(true, 24, unknownPW), state: generated(false, 24, nil),
+			want: types.StringUnknown()},
+		{name: "turned off", plan: generated(false, 24, unknownPW), state: generated(true, 24, "Old-Secret-1"),
+			want: types.StringNull()},
+	}
+	for _, c := range cases {
+		req := planmodifier.StringRequest{
+			Path:      path.Root("generated_password"),
+			Plan:      tfsdk.Plan{Schema: s, Raw: luObj(c.plan)},
+			PlanValue: types.StringUnknown(),
+			State:     tfsdk.State{Schema: s, Raw: tftypes.NewValue(localUserObjectType(), nil)},
+		}
+		if c.state != nil {
+			req.State.Raw = luObj(c.state)
+			var sv types.String
+			req.State.GetAttribute(context.Background(), path.Root("generated_password"), &sv)
+			req.StateValue = sv
+		}
+		resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+		generatedPasswordPlanModifier{}.PlanModifyString(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() || !resp.PlanValue.Equal(c.want) {
+			t.Errorf("%s: PlanValue = %v (%v), want %v", c.name, resp.PlanValue, resp.Diagnostics, c.want)
+		}
+	}
+}
+
+func TestLocalUserCreate_GeneratePassword(t *testing.T) {
+	fake := &fakeLocalUserClient{createOut: okUserState("svc_app", "S-1-5-21-111-222-333-1002")}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: luObj(map[string]tftypes.Value{
+		"name":                      tftypes.NewValue(tftypes.String, "svc_app"),
+		"password":                  tftypes.NewValue(tftypes.String, nil),
+		"generate_password":         tftypes.NewValue(tftypes.Bool, true),
+		"generated_password_length": tftypes.NewValue(tftypes.Number, 32),
+		"generated_password":        tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", luDiagDetails(resp.Diagnostics))
+	}
+	var got windowsLocalUserModel
+	resp.State.Get(context.Background(), &got)
+	if len(fake.createPassword) != 32 || got.GeneratedPassword.ValueString() != fake.createPassword {
+		t.Errorf("Create password = %q, generated_password = %q", fake.createPassword, got.GeneratedPassword.ValueString())
+	}
+}
+
+func TestLocalUserUpdate_GeneratedPassword(t *testing.T) {
+	s := windowsLocalUserSchemaDefinition()
+	obj := func(length int, pw any, woVersion any) tftypes.Value {
+		return luObj(map[string]tftypes.Value{
+			"sid":                       tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1002"),
+			"id":                        tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1002"),
+			"password":                  tftypes.NewValue(tftypes.String, nil),
+			"password_wo_version":       tftypes.NewValue(tftypes.Number, woVersion),
+			"generate_password":         tftypes.NewValue(tftypes.Bool, true),
+			"generated_password_length": tftypes.NewValue(tftypes.Number, length),
+			"generated_password":        tftypes.NewValue(tftypes.String, pw),
+		})
+	}
+	cases := []struct {
+		name      string
+		plan      tftypes.Value
+		wantReset bool
+	}{
+		{name: "length changed", plan: obj(40, tftypes.UnknownValue, nil), wantReset: true},
+		// password_wo_version has no effect while generating.
+		{name: "version bump only", plan: obj(24, "Old-Secret-1", 2)},
+	}
+	for _, c := range cases {
+		fake := &fakeLocalUserClient{readOut: okUserState("alice", "S-1-5-21-111-222-333-1002")}
+		r := &windowsLocalUserResource{user: fake}
+		state := obj(24, "Old-Secret-1", nil)
+		resp := &resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: state}}
+		r.Update(context.Background(), resource.UpdateRequest{
+			Plan:  tfsdk.Plan{Schema: s, Raw: c.plan},
+			State: tfsdk.State{Schema: s, Raw: state},
+		}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: Update: %v", c.name, luDiagDetails(resp.Diagnostics))
+		}
+		var got windowsLocalUserModel
+		resp.State.Get(context.Background(), &got)
+		if c.wantReset {
+			if len(fake.lastSetPassword) != 40 || got.GeneratedPassword.ValueString() != fake.lastSetPassword {
+				t.Errorf("%s: SetPassword = %q, generated_password = %q", c.name, fake.lastSetPassword, got.GeneratedPassword.ValueString())
+			}
+			continue
+		}
+		if fake.lastSetPasswordSID != "" || got.GeneratedPassword.ValueString() != "Old-Secret-1" {
+			t.Errorf("%s: SetPassword called for %q, generated_password = %q", c.name, fake.lastSetPasswordSID, got.GeneratedPassword.ValueString())
+		}
+	}
+}
+
+func TestChangePasswordAtLogonConflictValidator(t *testing.T) {
+	s := windowsLocalUserSchemaDefinition()
+	cases := []struct {
+		cpal, pne bool
+		wantErr   bool
+	}{
+		{cpal: true, pne: true, wantErr: true},
+		{cpal: true, pne: false},
+		{cpal: false, pne: true},
+	}
+	for _, c := range cases {
+		cfg := tfsdk.Config{Schema: s, Raw: luObj(map[string]tftypes.Value{
+			"change_password_at_logon": tftypes.NewValue(tftypes.Bool, c.cpal),
+			"password_never_expires":   tftypes.NewValue(tftypes.Bool, c.pne),
+		})}
+		resp := &validator.BoolResponse{}
+		changePasswordAtLogonConflictValidator{}.ValidateBool(context.Background(), validator.BoolRequest{
+			Path:        path.Root("change_password_at_logon"),
+			ConfigValue: types.BoolValue(c.cpal),
+			Config:      cfg,
+		}, resp)
+		if resp.Diagnostics.HasError() != c.wantErr {
+			t.Errorf("cpal=%v pne=%v: HasError = %v, want %v", c.cpal, c.pne, resp.Diagnostics.HasError(), c.wantErr)
+		}
+	}
+}
+
+func TestLocalUserCreate_ChangePasswordAtLogon(t *testing.T) {
+	fake := &fakeLocalUserClient{createOut: okUserState("alice", "S-1-5-21-111-222-333-1001")}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	plan := tfsdk.Plan{Schema: s, Raw: luObj(map[string]tftypes.Value{
+		"change_password_at_logon": tftypes.NewValue(tftypes.Bool, true),
+	})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", luDiagDetails(resp.Diagnostics))
+	}
+	if len(fake.passwordExpired) != 1 || !fake.passwordExpired[0] {
+		t.Errorf("SetPasswordExpired calls = %v, want [true]", fake.passwordExpired)
+	}
+	var got windowsLocalUserModel
+	resp.State.Get(context.Background(), &got)
+	if !got.ChangePasswordAtLogon.ValueBool() {
+		t.Error("change_password_at_logon must be true in state")
+	}
+}
+
+func TestLocalUserUpdate_ChangePasswordAtLogon(t *testing.T) {
+	sid := tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001")
+	cases := []struct {
+		name              string
+		prior, plan       bool
+		priorVer, planVer int
+		want              []bool
+	}{
+		{name: "enable", prior: false, plan: true, priorVer: 1, planVer: 1, want: []bool{true}},
+		{name: "clear", prior: true, plan: false, priorVer: 1, planVer: 1, want: []bool{false}},
+		{name: "unchanged", prior: true, plan: true, priorVer: 1, planVer: 1, want: nil},
+		{name: "rotation re-applies", prior: true, plan: true, priorVer: 1, planVer: 2, want: []bool{true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fake := &fakeLocalUserClient{readOut: okUserState("alice", "S-1-5-21-111-222-333-1001")}
+			r := &windowsLocalUserResource{user: fake}
+			s := windowsLocalUserSchemaDefinition()
+			rawPlan := luObj(map[string]tftypes.Value{
+				"sid": sid, "id": sid,
+				"change_password_at_logon": tftypes.NewValue(tftypes.Bool, c.plan),
+				"password_wo_version":      tftypes.NewValue(tftypes.Number, c.planVer),
+			})
+			rawState := luObj(map[string]tftypes.Value{
+				"sid": sid, "id": sid,
+				"change_password_at_logon": tftypes.NewValue(tftypes.Bool, c.prior),
+				"password_wo_version":      tftypes.NewValue(tftypes.Number, c.priorVer),
+			})
+			resp := &resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: rawState}}
+			r.Update(context.Background(), resource.UpdateRequest{
+				Plan:  tfsdk.Plan{Schema: s, Raw: rawPlan},
+				State: tfsdk.State{Schema: s, Raw: rawState},
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Update: %v", luDiagDetails(resp.Diagnostics))
+			}
+			if fmt.Sprint(fake.passwordExpired) != fmt.Sprint(c.want) {
+				t.Errorf("SetPasswordExpired calls = %v, want %v", fake.passwordExpired, c.want)
+			}
+		})
+	}
+}
+
+func TestLocalUserRead_KeepsChangePasswordAtLogon(t *testing.T) {
+	fake := &fakeLocalUserClient{readOut: okUserState("alice", "S-1-5-21-111-222-333-1001")}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	st := tfsdk.State{Schema: s, Raw: luObj(map[string]tftypes.Value{
+		"sid":                      tftypes.NewValue(tftypes.String, "S-1-5-21-111-222-333-1001"),
+		"change_password_at_logon": tftypes.NewValue(tftypes.Bool, true),
+	})}
+	resp := &resource.ReadResponse{State: st}
+	r.Read(context.Background(), resource.ReadRequest{State: st}, resp)
+	var got windowsLocalUserModel
+	resp.State.Get(context.Background(), &got)
+	if resp.Diagnostics.HasError() || !got.ChangePasswordAtLogon.ValueBool() {
+		t.Errorf("Read must keep change_password_at_logon from state: %v", luDiagDetails(resp.Diagnostics))
+	}
+}
+
 func TestLocalUserUpdate_Enable(t *testing.T) {
 	fake := &fakeLocalUserClient{
 		readOut: okUserState("alice", "S-1-5-21-111-222-333-1001"),
@@ -1148,6 +1779,26 @@ func TestLocalUserImportState_ByName(t *testing.T) {
 	}
 }
 
+func TestLocalUserImportState_NameWithSPrefix(t *testing.T) {
+	fake := &fakeLocalUserClient{
+		importByNameOut: okUserState("S-Backup", "S-1-5-21-111-222-333-1005"),
+		importBySIDErr:  winclient.NewLocalUserError(winclient.LocalUserErrorNotFound, "must not be called", nil, nil),
+	}
+	r := &windowsLocalUserResource{user: fake}
+	s := windowsLocalUserSchemaDefinition()
+
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: s}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "S-Backup"}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("a name starting with S- must be imported by name: %v", luDiagDetails(resp.Diagnostics))
+	}
+	var m windowsLocalUserModel
+	resp.State.Get(context.Background(), &m)
+	if m.ID.ValueString() != "S-1-5-21-111-222-333-1005" {
+		t.Errorf("id = %q, want the SID", m.ID.ValueString())
+	}
+}
+
 func TestLocalUserImportState_BySID_NotFound(t *testing.T) {
 	fake := &fakeLocalUserClient{
 		importBySIDErr: winclient.NewLocalUserError(winclient.LocalUserErrorNotFound,
@@ -0,0 +1,406 @@
+// Package provider: windows_network_adapter_ip resource implementation.
+//
+// windows_network_adapter_ip assigns a static IPv4 address (and optionally
+// the default gateway) to a network interface. WinRM interaction is
+// delegated to winclient.NetIPAddressClient
+// (internal/winclient/net_ip_address.go), which also handles the WinRM
+// connection dropping when the address being changed is the one the
+// provider is connected through.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// networkAdapterIPDefaultTimeout is the fallback per-operation timeout when
+// the user does not provide a `timeouts {}` block. It bounds the wait for
+// the host on reconnect_host after a dropped connection.
+const networkAdapterIPDefaultTimeout = 10 * time.Minute
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                = (*windowsNetworkAdapterIPResource)(nil)
+	_ resource.ResourceWithConfigure   = (*windowsNetworkAdapterIPResource)(nil)
+	_ resource.ResourceWithImportState = (*windowsNetworkAdapterIPResource)(nil)
+)
+
+// NewWindowsNetworkAdapterIPResource is the constructor registered in
+// provider.go.
+func NewWindowsNetworkAdapterIPResource() resource.Resource {
+	return &windowsNetworkAdapterIPResource{}
+}
+
+// windowsNetworkAdapterIPResource is the TPF resource type for
+// windows_network_adapter_ip.
+type windowsNetworkAdapterIPResource struct {
+	ip winclient.WindowsNetIPAddressClient
+	// defaultTimeout is the provider-level default_command_timeout; zero
+	// means networkAdapterIPDefaultTimeout.
+	defaultTimeout time.Duration
+}
+
+// windowsNetworkAdapterIPModel is the Terraform state/plan model for
+// windows_network_adapter_ip.
+type windowsNetworkAdapterIPModel struct {
+	ID             types.String   `tfsdk:"id"`
+	InterfaceAlias types.String   `tfsdk:"interface_alias"`
+	IPAddress      types.String   `tfsdk:"ip_address"`
+	PrefixLength   types.Int64    `tfsdk:"prefix_length"`
+	DefaultGateway types.String   `tfsdk:"default_gateway"`
+	ReconnectHost  types.String   `tfsdk:"reconnect_host"`
+	InterfaceIndex types.Int64    `tfsdk:"interface_index"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+}
+
+// ipv4AddressValidator requires a dotted-quad IPv4 address.
+type ipv4AddressValidator struct{}
+
+func (ipv4AddressValidator) Description(_ context.Context) string {
+	return "must be an IPv4 address such as 192.0.2.10"
+}
+
+func (v ipv4AddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (ipv4AddressValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if a, err := netip.ParseAddr(req.ConfigValue.ValueString()); err != nil || !a.Is4() {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid IPv4 address",
+			fmt.Sprintf("Expected an IPv4 address such as 192.0.2.10, got %q.", req.ConfigValue.ValueString()))
+	}
+}
+
+// Metadata sets the resource type name ("windows_network_adapter_ip").
+func (r *windowsNetworkAdapterIPResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_adapter_ip"
+}
+
+// Schema returns the complete TPF schema.
+func (r *windowsNetworkAdapterIPResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsNetworkAdapterIPSchemaDefinition(ctx)
+}
+
+// windowsNetworkAdapterIPSchemaDefinition returns the
+// windows_network_adapter_ip schema.
+func windowsNetworkAdapterIPSchemaDefinition(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Assigns a static IPv4 address to a network interface of the remote Windows host " +
+			"(`New-NetIPAddress`), and optionally sets the interface's default gateway. DHCP is disabled on the " +
+			"interface when the address is created; destroying the resource removes the address but does not " +
+			"re-enable DHCP.\n\n" +
+			"~> **Changing the address you are connected through drops the connection.** Disabling DHCP on create " +
+			"and changing `ip_address` both remove the address the provider may be using, and the WinRM connection " +
+			"breaks mid-apply. Set `reconnect_host` to the address the host answers on afterwards (usually the new " +
+			"`ip_address`): the provider then confirms the change through it and treats the drop as success. " +
+			"Without `reconnect_host` the apply fails with `connection_lost` even though the change may have been " +
+			"made. Either way, update the provider's `host` before the next plan.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Resource identifier: <interface_alias>/<ip_address>.",
+				MarkdownDescription: "Resource identifier: `<interface_alias>/<ip_address>`.",
+			},
+			"interface_alias": schema.StringAttribute{
+				Required:            true,
+				Description:         "Alias of the network interface, e.g. Ethernet. ForceNew.",
+				MarkdownDescription: "Alias of the network interface as shown by `Get-NetAdapter`, e.g. `Ethernet`. **ForceNew.**",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 256),
+				},
+			},
+			"ip_address": schema.StringAttribute{
+				Required: true,
+				Description: "Static IPv4 address. Changing it adds the new address before removing the old one; " +
+					"see reconnect_host when the old one is the address the provider connects to.",
+				MarkdownDescription: "Static IPv4 address. Changing it adds the new address before removing the old one; " +
+					"see `reconnect_host` when the old one is the address the provider connects to.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"prefix_length": schema.Int64Attribute{
+				Required:            true,
+				Description:         "Subnet prefix length, e.g. 24 for 255.255.255.0.",
+				MarkdownDescription: "Subnet prefix length, e.g. `24` for `255.255.255.0`.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 32),
+				},
+			},
+			"default_gateway": schema.StringAttribute{
+				Optional: true,
+				Description: "IPv4 default gateway of the interface. When set it replaces the interface's other default " +
+					"routes. When unset the interface's default routes are left alone.",
+				MarkdownDescription: "IPv4 default gateway of the interface. When set it replaces the interface's other " +
+					"default routes. When unset the interface's default routes are left alone.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"reconnect_host": schema.StringAttribute{
+				Optional: true,
+				Description: "Address to confirm the change through when the WinRM connection drops while it is " +
+					"applied, usually the new ip_address. WinRM settings other than the host are those of the provider.",
+				MarkdownDescription: "Address to confirm the change through when the WinRM connection drops while it " +
+					"is applied, usually the new `ip_address`. WinRM settings other than the host are those of the " +
+					"provider. The wait is bounded by the `timeouts` block (default 10 minutes).",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"interface_index": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Index of the interface (ifIndex).",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsNetworkAdapterIPResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.ip = winclient.NewNetIPAddressClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
+}
+
+// ImportState accepts "<interface_alias>/<ip_address>". The alias may itself
+// contain "/", so the ID is split at the last one.
+func (r *windowsNetworkAdapterIPResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	i := strings.LastIndex(req.ID, "/")
+	if i <= 0 || i == len(req.ID)-1 {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("Expected <interface_alias>/<ip_address> (e.g. Ethernet/192.0.2.10), got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("interface_alias"), req.ID[:i])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ip_address"), req.ID[i+1:])...)
+}
+
+// Create assigns the address.
+func (r *windowsNetworkAdapterIPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsNetworkAdapterIPModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, networkAdapterIPDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	in := netIPInputFromModel(plan)
+	tflog.Info(ctx, "windows_network_adapter_ip Create", map[string]interface{}{
+		"interface_alias": in.InterfaceAlias, "ip_address": in.IPAddress, "prefix_length": in.PrefixLength,
+	})
+
+	st, err := r.ip.Create(ctx, in)
+	if err != nil {
+		addNetIPAddressDiag(&resp.Diagnostics, "Create windows_network_adapter_ip failed", err)
+		return
+	}
+	if st == nil {
+		resp.Diagnostics.AddError("Create windows_network_adapter_ip failed",
+			fmt.Sprintf("Address %s was not reported on %q after it was created.", in.IPAddress, in.InterfaceAlias))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, modelFromNetIPState(st, plan))...)
+}
+
+// Read refreshes state. An address that is gone is removed from state so the
+// next apply assigns it again.
+func (r *windowsNetworkAdapterIPResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsNetworkAdapterIPModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	alias, ip := state.InterfaceAlias.ValueString(), state.IPAddress.ValueString()
+	st, err := r.ip.Read(ctx, alias, ip)
+	if err != nil {
+		addNetIPAddressDiag(&resp.Diagnostics, "Read windows_network_adapter_ip failed", err)
+		return
+	}
+	if st == nil {
+		tflog.Info(ctx, "windows_network_adapter_ip Read: address not found, removing from state", map[string]interface{}{
+			"interface_alias": alias, "ip_address": ip,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, modelFromNetIPState(st, state))...)
+}
+
+// Update moves the interface to the planned address, prefix and gateway.
+// interface_alias is ForceNew.
+func (r *windowsNetworkAdapterIPResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, prior windowsNetworkAdapterIPModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.IPAddress.Equal(prior.IPAddress) && plan.PrefixLength.Equal(prior.PrefixLength) &&
+		plan.DefaultGateway.Equal(prior.DefaultGateway) {
+		// Only reconnect_host or timeouts changed: nothing to do on the host.
+		final := prior
+		final.ReconnectHost = plan.ReconnectHost
+		final.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+		return
+	}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, operationTimeout(r.defaultTimeout, networkAdapterIPDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	in := netIPInputFromModel(plan)
+	tflog.Info(ctx, "windows_network_adapter_ip Update", map[string]interface{}{
+		"interface_alias": in.InterfaceAlias, "from": prior.IPAddress.ValueString(), "to": in.IPAddress,
+	})
+
+	st, err := r.ip.Update(ctx, netIPInputFromModel(prior), in)
+	if err != nil {
+		addNetIPAddressDiag(&resp.Diagnostics, "Update windows_network_adapter_ip failed", err)
+		return
+	}
+	if st == nil {
+		resp.Diagnostics.AddError("Update windows_network_adapter_ip failed",
+			fmt.Sprintf("Address %s was not reported on %q after the update.", in.IPAddress, in.InterfaceAlias))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, modelFromNetIPState(st, plan))...)
+}
+
+// Delete removes the address and the managed default route. Idempotent: an
+// address that is already gone is success.
+func (r *windowsNetworkAdapterIPResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsNetworkAdapterIPModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, operationTimeout(r.defaultTimeout, networkAdapterIPDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	in := netIPInputFromModel(state)
+	tflog.Info(ctx, "windows_network_adapter_ip Delete", map[string]interface{}{
+		"interface_alias": in.InterfaceAlias, "ip_address": in.IPAddress,
+	})
+	if err := r.ip.Delete(ctx, in); err != nil {
+		addNetIPAddressDiag(&resp.Diagnostics, "Delete windows_network_adapter_ip failed", err)
+	}
+}
+
+// netIPInputFromModel builds the winclient input from a plan or state model.
+func netIPInputFromModel(m windowsNetworkAdapterIPModel) winclient.NetIPAddressInput {
+	return winclient.NetIPAddressInput{
+		InterfaceAlias: m.InterfaceAlias.ValueString(),
+		IPAddress:      m.IPAddress.ValueString(),
+		PrefixLength:   m.PrefixLength.ValueInt64(),
+		DefaultGateway: m.DefaultGateway.ValueString(),
+		ReconnectHost:  m.ReconnectHost.ValueString(),
+	}
+}
+
+// modelFromNetIPState projects st onto a model, keeping the configuration-only
+// fields from prior. default_gateway is only refreshed when it is managed
+// (non-null in prior): it keeps its value while that gateway is still one of
+// the interface's default routes and otherwise shows the lowest-metric one,
+// or "" when there is none, as drift.
+func modelFromNetIPState(st *winclient.NetIPAddressState, prior windowsNetworkAdapterIPModel) *windowsNetworkAdapterIPModel {
+	out := &windowsNetworkAdapterIPModel{
+		ID:             types.StringValue(st.InterfaceAlias + "/" + st.IPAddress),
+		InterfaceAlias: types.StringValue(st.InterfaceAlias),
+		IPAddress:      types.StringValue(st.IPAddress),
+		PrefixLength:   types.Int64Value(st.PrefixLength),
+		DefaultGateway: prior.DefaultGateway,
+		ReconnectHost:  prior.ReconnectHost,
+		InterfaceIndex: types.Int64Value(st.InterfaceIndex),
+		Timeouts:       prior.Timeouts,
+	}
+	if gw := prior.DefaultGateway.ValueString(); !prior.DefaultGateway.IsNull() && !slices.Contains(st.DefaultGateways, gw) {
+		out.DefaultGateway = types.StringValue("")
+		if len(st.DefaultGateways) > 0 {
+			out.DefaultGateway = types.StringValue(st.DefaultGateways[0])
+		}
+	}
+	return out
+}
+
+// addNetIPAddressDiag converts a winclient.NetIPAddressError into a TPF
+// diagnostic.
+func addNetIPAddressDiag(diags *diag.Diagnostics, summary string, err error) {
+	var ne *winclient.NetIPAddressError
+	if errors.As(err, &ne) {
+		detail := ne.Message
+		if len(ne.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range ne.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if ne.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", ne.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
@@ -0,0 +1,294 @@
+// Package provider — unit tests for windows_network_adapter_ip.
+//
+// CRUD handlers are driven via a fakeNetIPAddressClient injected into
+// windowsNetworkAdapterIPResource.ip, so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeNetIPAddressClient struct {
+	readOut   *winclient.NetIPAddressState
+	readErr   error
+	out       *winclient.NetIPAddressState
+	err       error
+	createIn  winclient.NetIPAddressInput
+	updateOld winclient.NetIPAddressInput
+	updateIn  winclient.NetIPAddressInput
+	deleteIn  winclient.NetIPAddressInput
+	calls     int
+}
+
+func (f *fakeNetIPAddressClient) Read(_ context.Context, _, _ string) (*winclient.NetIPAddressState, error) {
+	return f.readOut, f.readErr
+}
+func (f *fakeNetIPAddressClient) Create(_ context.Context, in winclient.NetIPAddressInput) (*winclient.NetIPAddressState, error) {
+	f.calls++
+	f.createIn = in
+	return f.out, f.err
+}
+func (f *fakeNetIPAddressClient) Update(_ context.Context, prior, in winclient.NetIPAddressInput) (*winclient.NetIPAddressState, error) {
+	f.calls++
+	f.updateOld, f.updateIn = prior, in
+	return f.out, f.err
+}
+func (f *fakeNetIPAddressClient) Delete(_ context.Context, in winclient.NetIPAddressInput) error {
+	f.calls++
+	f.deleteIn = in
+	return f.err
+}
+
+func nipTimeoutsType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"create": tftypes.String,
+		"update": tftypes.String,
+		"delete": tftypes.String,
+	}}
+}
+
+func nipObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":              tftypes.String,
+		"interface_alias": tftypes.String,
+		"ip_address":      tftypes.String,
+		"prefix_length":   tftypes.Number,
+		"default_gateway": tftypes.String,
+		"reconnect_host":  tftypes.String,
+		"interface_index": tftypes.Number,
+		"timeouts":        nipTimeoutsType(),
+	}}
+}
+
+func nipObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "Ethernet/10.0.0.5"),
+		"interface_alias": tftypes.NewValue(tftypes.String, "Ethernet"),
+		"ip_address":      tftypes.NewValue(tftypes.String, "10.0.0.5"),
+		"prefix_length":   tftypes.NewValue(tftypes.Number, 24),
+		"default_gateway": tftypes.NewValue(tftypes.String, "10.0.0.1"),
+		"reconnect_host":  tftypes.NewValue(tftypes.String, nil),
+		"interface_index": tftypes.NewValue(tftypes.Number, 4),
+		"timeouts":        tftypes.NewValue(nipTimeoutsType(), nil),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(nipObjectType(), base)
+}
+
+func nipState(ip string, gateways ...string) *winclient.NetIPAddressState {
+	return &winclient.NetIPAddressState{
+		InterfaceAlias: "Ethernet", InterfaceIndex: 4, IPAddress: ip, PrefixLength: 24,
+		PrefixOrigin: "Manual", DefaultGateways: gateways,
+	}
+}
+
+func TestNetworkAdapterIPSchema(t *testing.T) {
+	s := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	for _, name := range []string{"id", "interface_alias", "ip_address", "prefix_length", "default_gateway",
+		"reconnect_host", "interface_index", "timeouts"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !strings.Contains(s.MarkdownDescription, "drops the connection") {
+		t.Error("description must warn about the dropped connection")
+	}
+}
+
+func TestIPv4AddressValidator(t *testing.T) {
+	v := ipv4AddressValidator{}
+	for in, wantErr := range map[string]bool{"10.0.0.5": false, "10.0.0.256": true, "2001:db8::1": true, "host": true} {
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path: path.Root("ip_address"), ConfigValue: types.StringValue(in),
+		}, resp)
+		if resp.Diagnostics.HasError() != wantErr {
+			t.Errorf("%q: HasError = %v, want %v", in, resp.Diagnostics.HasError(), wantErr)
+		}
+	}
+}
+
+func TestNetworkAdapterIPCreate_Handler(t *testing.T) {
+	fake := &fakeNetIPAddressClient{out: nipState("10.0.0.5", "10.0.0.1")}
+	r := &windowsNetworkAdapterIPResource{ip: fake}
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	plan := tfsdk.Plan{Schema: schemaDef, Raw: nipObj(map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"interface_index": tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+		"reconnect_host":  tftypes.NewValue(tftypes.String, "10.0.0.5"),
+	})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: tftypes.NewValue(nipObjectType(), nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	want := winclient.NetIPAddressInput{InterfaceAlias: "Ethernet", IPAddress: "10.0.0.5", PrefixLength: 24,
+		DefaultGateway: "10.0.0.1", ReconnectHost: "10.0.0.5"}
+	if fake.createIn != want {
+		t.Errorf("Create input = %+v, want %+v", fake.createIn, want)
+	}
+	var got windowsNetworkAdapterIPModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "Ethernet/10.0.0.5" || got.InterfaceIndex.ValueInt64() != 4 || got.DefaultGateway.ValueString() != "10.0.0.1" {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestNetworkAdapterIPCreate_Handler_ConnectionLost(t *testing.T) {
+	fake := &fakeNetIPAddressClient{err: winclient.NewNetIPAddressError(winclient.NetIPAddressErrorConnectionLost,
+		"the WinRM connection to 10.0.0.9 dropped", nil, nil)}
+	r := &windowsNetworkAdapterIPResource{ip: fake}
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: tftypes.NewValue(nipObjectType(), nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaDef, Raw: nipObj(nil)}}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: connection_lost") {
+		t.Errorf("expected connection_lost diag, got %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("an unconfirmed change must not be recorded in state")
+	}
+}
+
+func TestNetworkAdapterIPRead_Handler(t *testing.T) {
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	prior := tfsdk.State{Schema: schemaDef, Raw: nipObj(nil)}
+
+	cases := map[string]struct {
+		out    *winclient.NetIPAddressState
+		wantGW string
+	}{
+		"in sync":         {nipState("10.0.0.5", "10.0.0.254", "10.0.0.1"), "10.0.0.1"},
+		"gateway drifted": {nipState("10.0.0.5", "10.0.0.254"), "10.0.0.254"},
+		"gateway removed": {nipState("10.0.0.5"), ""},
+		"address gone":    {nil, ""},
+	}
+	for name, tc := range cases {
+		r := &windowsNetworkAdapterIPResource{ip: &fakeNetIPAddressClient{readOut: tc.out}}
+		resp := &resource.ReadResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+		r.Read(context.Background(), resource.ReadRequest{State: prior}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: diags: %v", name, resp.Diagnostics)
+		}
+		if tc.out == nil {
+			if !resp.State.Raw.IsNull() {
+				t.Errorf("%s: resource must be removed from state", name)
+			}
+			continue
+		}
+		var got windowsNetworkAdapterIPModel
+		resp.State.Get(context.Background(), &got)
+		if got.DefaultGateway.ValueString() != tc.wantGW {
+			t.Errorf("%s: default_gateway = %q, want %q", name, got.DefaultGateway.ValueString(), tc.wantGW)
+		}
+	}
+
+	// An unmanaged gateway stays null whatever the host reports.
+	unmanaged := tfsdk.State{Schema: schemaDef, Raw: nipObj(map[string]tftypes.Value{
+		"default_gateway": tftypes.NewValue(tftypes.String, nil),
+	})}
+	r := &windowsNetworkAdapterIPResource{ip: &fakeNetIPAddressClient{readOut: nipState("10.0.0.5", "10.0.0.254")}}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: schemaDef, Raw: unmanaged.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: unmanaged}, resp)
+	var got windowsNetworkAdapterIPModel
+	resp.State.Get(context.Background(), &got)
+	if !got.DefaultGateway.IsNull() {
+		t.Errorf("unmanaged default_gateway = %v, want null", got.DefaultGateway)
+	}
+}
+
+func TestNetworkAdapterIPUpdate_Handler(t *testing.T) {
+	fake := &fakeNetIPAddressClient{out: nipState("10.0.0.6", "10.0.0.1")}
+	r := &windowsNetworkAdapterIPResource{ip: fake}
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	prior := tfsdk.State{Schema: schemaDef, Raw: nipObj(nil)}
+	plan := tfsdk.Plan{Schema: schemaDef, Raw: nipObj(map[string]tftypes.Value{
+		"ip_address":     tftypes.NewValue(tftypes.String, "10.0.0.6"),
+		"reconnect_host": tftypes.NewValue(tftypes.String, "10.0.0.6"),
+	})}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: prior}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if fake.updateOld.IPAddress != "10.0.0.5" || fake.updateIn.IPAddress != "10.0.0.6" || fake.updateIn.ReconnectHost != "10.0.0.6" {
+		t.Errorf("Update(%+v, %+v)", fake.updateOld, fake.updateIn)
+	}
+	var got windowsNetworkAdapterIPModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "Ethernet/10.0.0.6" {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestNetworkAdapterIPUpdate_Handler_ReconnectHostOnly(t *testing.T) {
+	fake := &fakeNetIPAddressClient{}
+	r := &windowsNetworkAdapterIPResource{ip: fake}
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	prior := tfsdk.State{Schema: schemaDef, Raw: nipObj(nil)}
+	plan := tfsdk.Plan{Schema: schemaDef, Raw: nipObj(map[string]tftypes.Value{
+		"reconnect_host": tftypes.NewValue(tftypes.String, "10.0.0.5"),
+	})}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: prior}, resp)
+	if resp.Diagnostics.HasError() || fake.calls != 0 {
+		t.Fatalf("diags: %v, host calls = %d", resp.Diagnostics, fake.calls)
+	}
+	var got windowsNetworkAdapterIPModel
+	resp.State.Get(context.Background(), &got)
+	if got.ReconnectHost.ValueString() != "10.0.0.5" {
+		t.Errorf("reconnect_host = %v", got.ReconnectHost)
+	}
+}
+
+func TestNetworkAdapterIPDelete_Handler(t *testing.T) {
+	fake := &fakeNetIPAddressClient{}
+	r := &windowsNetworkAdapterIPResource{ip: fake}
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+	state := tfsdk.State{Schema: schemaDef, Raw: nipObj(nil)}
+	resp := &resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if fake.deleteIn.IPAddress != "10.0.0.5" || fake.deleteIn.DefaultGateway != "10.0.0.1" {
+		t.Errorf("Delete input = %+v", fake.deleteIn)
+	}
+}
+
+func TestNetworkAdapterIPImportState(t *testing.T) {
+	r := &windowsNetworkAdapterIPResource{}
+	schemaDef := windowsNetworkAdapterIPSchemaDefinition(context.Background())
+
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: schemaDef, Raw: tftypes.NewValue(nipObjectType(), nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "vEthernet (LAN/1)/10.0.0.5"}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var alias, ip types.String
+	resp.State.GetAttribute(context.Background(), path.Root("interface_alias"), &alias)
+	resp.State.GetAttribute(context.Background(), path.Root("ip_address"), &ip)
+	if alias.ValueString() != "vEthernet (LAN/1)" || ip.ValueString() != "10.0.0.5" {
+		t.Errorf("imported alias = %q, ip = %q", alias.ValueString(), ip.ValueString())
+	}
+
+	for _, id := range []string{"10.0.0.5", "Ethernet/", "/10.0.0.5"} {
+		resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: schemaDef, Raw: tftypes.NewValue(nipObjectType(), nil)}}
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: id}, resp)
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("%q: expected an invalid import ID error", id)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Package provider: windows_optional_feature resource implementation.
+//
+// This file contains the TPF schema, model, and CRUD + ImportState handlers
+// for the windows_optional_feature resource, the client-SKU counterpart of
+// windows_feature. WinRM interaction is delegated to
+// winclient.OptionalFeatureClient (internal/winclient/optional_feature.go).
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// optionalFeatureDefaultTimeout is the fallback per-operation timeout when
+// the user does not provide a `timeouts {}` block. Enabling a feature whose
+// payload must be downloaded (e.g. NetFx3) can take many minutes.
+const optionalFeatureDefaultTimeout = 30 * time.Minute
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                = (*windowsOptionalFeatureResource)(nil)
+	_ resource.ResourceWithConfigure   = (*windowsOptionalFeatureResource)(nil)
+	_ resource.ResourceWithImportState = (*windowsOptionalFeatureResource)(nil)
+)
+
+// NewWindowsOptionalFeatureResource is the constructor registered in provider.go.
+func NewWindowsOptionalFeatureResource() resource.Resource {
+	return &windowsOptionalFeatureResource{}
+}
+
+// windowsOptionalFeatureResource is the TPF resource type for
+// windows_optional_feature.
+type windowsOptionalFeatureResource struct {
+	feat winclient.WindowsOptionalFeatureClient
+	// defaultTimeout is the provider-level default_command_timeout; zero
+	// means optionalFeatureDefaultTimeout.
+	defaultTimeout time.Duration
+}
+
+// windowsOptionalFeatureModel is the Terraform state/plan model for
+// windows_optional_feature.
+type windowsOptionalFeatureModel struct {
+	ID             types.String   `tfsdk:"id"`
+	Name           types.String   `tfsdk:"name"`
+	All            types.Bool     `tfsdk:"all"`
+	Source         types.String   `tfsdk:"source"`
+	LimitAccess    types.Bool     `tfsdk:"limit_access"`
+	RemovePayload  types.Bool     `tfsdk:"remove_payload"`
+	DisplayName    types.String   `tfsdk:"display_name"`
+	Description    types.String   `tfsdk:"description"`
+	State          types.String   `tfsdk:"state"`
+	InstallState   types.String   `tfsdk:"install_state"`
+	RestartPending types.Bool     `tfsdk:"restart_pending"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata sets the resource type name ("windows_optional_feature").
+func (r *windowsOptionalFeatureResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_optional_feature"
+}
+
+// Schema returns the complete TPF schema.
+func (r *windowsOptionalFeatureResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsOptionalFeatureSchemaDefinition(ctx)
+}
+
+// windowsOptionalFeatureSchemaDefinition returns the windows_optional_feature
+// schema. name and all are ForceNew: DISM cannot undo the parent features
+// that -All enabled.
+func windowsOptionalFeatureSchemaDefinition(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Enables a Windows optional feature on a remote host via WinRM and the DISM cmdlets " +
+			"(`Get/Enable/Disable-WindowsOptionalFeature -Online`). Use it on client SKUs (Windows 10/11), which have " +
+			"no `Install-WindowsFeature`; it also works on Server for features only exposed through DISM.\n\n" +
+			"The cmdlets run with `-NoRestart`: when a reboot is needed a warning is emitted and `restart_pending` " +
+			"is set; use `windows_reboot` to restart the host. A feature disabled outside Terraform is removed from " +
+			"state on refresh, so the next apply enables it again.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Resource identifier; equals the feature name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				Description:         "DISM feature name (e.g. Microsoft-Windows-Subsystem-Linux, NetFx3, TelnetClient). Case-sensitive as listed by Get-WindowsOptionalFeature. ForceNew.",
+				MarkdownDescription: "DISM feature name (e.g. `Microsoft-Windows-Subsystem-Linux`, `NetFx3`, `TelnetClient`), as listed by `Get-WindowsOptionalFeature -Online`. **ForceNew.**",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`),
+						"must start with an alphanumeric character and contain only [A-Za-z0-9._-]",
+					),
+					stringvalidator.LengthBetween(1, 256),
+				},
+			},
+			"all": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "Also enable the parent features this feature depends on (-All). Default false. ForceNew.",
+				MarkdownDescription: "Also enable the parent features this feature depends on (`-All`). Default `false`. **ForceNew.**",
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Payload location passed as -Source: an SxS folder (e.g. D:\\sources\\sxs), a mounted image or a WIM path. Needed when the payload was removed and Windows Update is unavailable.",
+				MarkdownDescription: "Payload location passed as `-Source`: an SxS folder (e.g. `D:\\sources\\sxs`), a mounted image or a WIM path. Needed when the payload was removed (`install_state = \"Removed\"`) and Windows Update cannot be used.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"limit_access": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "Do not contact Windows Update for the payload (-LimitAccess). Default false.",
+				MarkdownDescription: "Do not contact Windows Update for the payload (`-LimitAccess`). Default `false`. Set it on air-gapped hosts together with `source`.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"remove_payload": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "On destroy, also remove the feature payload from the image (-Remove). Default false.",
+				MarkdownDescription: "On destroy, also remove the feature payload from the image (`-Remove`), leaving it `DisabledWithPayloadRemoved`. Default `false`.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"display_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Display name reported by Get-WindowsOptionalFeature.",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Description reported by Get-WindowsOptionalFeature.",
+			},
+			"state": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Raw DISM state: Enabled, EnablePending, Disabled, DisablePending or DisabledWithPayloadRemoved.",
+				MarkdownDescription: "Raw DISM state: `Enabled`, `EnablePending`, `Disabled`, `DisablePending` or `DisabledWithPayloadRemoved`.",
+			},
+			"install_state": schema.StringAttribute{
+				Computed:            true,
+				Description:         "state in the vocabulary of windows_feature: Installed, InstallPending, Available, UninstallPending or Removed.",
+				MarkdownDescription: "`state` in the vocabulary of `windows_feature`: `Installed`, `InstallPending`, `Available`, `UninstallPending` or `Removed`.",
+			},
+			"restart_pending": schema.BoolAttribute{
+				Computed:    true,
+				Description: "True when the last operation reported RestartNeeded or the feature is in a pending state.",
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsOptionalFeatureResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.feat = winclient.NewOptionalFeatureClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
+}
+
+// ImportState lets `terraform import windows_optional_feature.foo NetFx3` work.
+func (r *windowsOptionalFeatureResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
+
+// Create enables the feature and persists the observed state.
+func (r *windowsOptionalFeatureResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsOptionalFeatureModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, optionalFeatureDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	in := winclient.OptionalFeatureInput{
+		Name:        plan.Name.ValueString(),
+		All:         plan.All.ValueBool(),
+		Source:      plan.Source.ValueString(),
+		LimitAccess: plan.LimitAccess.ValueBool(),
+	}
+	tflog.Debug(ctx, "windows_optional_feature Create", map[string]interface{}{
+		"name": in.Name, "all": in.All, "limit_access": in.LimitAccess,
+	})
+
+	info, result, err := r.feat.Enable(ctx, in)
+	if err != nil {
+		addOptionalFeatureDiag(&resp.Diagnostics, "Create windows_optional_feature failed", err)
+		return
+	}
+	if info == nil {
+		resp.Diagnostics.AddError("Create windows_optional_feature failed",
+			fmt.Sprintf("Optional feature %q was not reported by the host after enabling it.", in.Name))
+		return
+	}
+	final := modelFromOptionalFeature(info, plan)
+	applyOptionalFeatureResult(&resp.Diagnostics, &final, result, "Enable")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Read refreshes Terraform state from the live host. A feature that is gone
+// or no longer enabled is removed from state so the next apply enables it.
+func (r *windowsOptionalFeatureResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsOptionalFeatureModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := state.Name.ValueString()
+	if name == "" {
+		name = state.ID.ValueString()
+	}
+	tflog.Debug(ctx, "windows_optional_feature Read", map[string]interface{}{"name": name})
+	info, err := r.feat.Read(ctx, name)
+	if err != nil {
+		addOptionalFeatureDiag(&resp.Diagnostics, "Read windows_optional_feature failed", err)
+		return
+	}
+	if info == nil || !info.Enabled {
+		tflog.Info(ctx, "windows_optional_feature Read: feature not enabled, removing from state", map[string]interface{}{
+			"name": name,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	final := modelFromOptionalFeature(info, state)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Update persists in-place changes. name and all are ForceNew, and source,
+// limit_access and remove_payload only matter when the feature is enabled or
+// destroyed, so nothing runs on the host.
+func (r *windowsOptionalFeatureResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, prior windowsOptionalFeatureModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	final := prior
+	final.Source = plan.Source
+	final.LimitAccess = plan.LimitAccess
+	final.RemovePayload = plan.RemovePayload
+	final.Timeouts = plan.Timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Delete disables the feature. Idempotent: a feature that is already
+// disabled or unknown is success.
+func (r *windowsOptionalFeatureResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsOptionalFeatureModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, operationTimeout(r.defaultTimeout, optionalFeatureDefaultTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+	name := state.Name.ValueString()
+	if name == "" {
+		name = state.ID.ValueString()
+	}
+	in := winclient.OptionalFeatureInput{Name: name, RemovePayload: state.RemovePayload.ValueBool()}
+	tflog.Debug(ctx, "windows_optional_feature Delete", map[string]interface{}{
+		"name": name, "remove_payload": in.RemovePayload,
+	})
+	_, result, err := r.feat.Disable(ctx, in)
+	if err != nil {
+		if winclient.IsOptionalFeatureError(err, winclient.OptionalFeatureErrorNotFound) {
+			return
+		}
+		addOptionalFeatureDiag(&resp.Diagnostics, "Delete windows_optional_feature failed", err)
+		return
+	}
+	if result != nil && result.RestartNeeded {
+		resp.Diagnostics.AddWarning(
+			"Reboot required after disable",
+			fmt.Sprintf("Disable-WindowsOptionalFeature reported RestartNeeded for feature %q. Reboot the target host to complete removal.", name),
+		)
+	}
+}
+
+// modelFromOptionalFeature projects a winclient.OptionalFeatureInfo onto a
+// windowsOptionalFeatureModel, preserving the desired-input fields from prior.
+func modelFromOptionalFeature(info *winclient.OptionalFeatureInfo, prior windowsOptionalFeatureModel) windowsOptionalFeatureModel {
+	out := windowsOptionalFeatureModel{
+		ID:             types.StringValue(info.Name),
+		Name:           types.StringValue(info.Name),
+		All:            prior.All,
+		Source:         prior.Source,
+		LimitAccess:    prior.LimitAccess,
+		RemovePayload:  prior.RemovePayload,
+		DisplayName:    types.StringValue(info.DisplayName),
+		Description:    types.StringValue(info.Description),
+		State:          types.StringValue(info.State),
+		InstallState:   types.StringValue(info.InstallState),
+		RestartPending: types.BoolValue(info.RestartPending),
+		Timeouts:       prior.Timeouts,
+	}
+	if out.All.IsNull() || out.All.IsUnknown() {
+		out.All = types.BoolValue(false)
+	}
+	if out.LimitAccess.IsNull() || out.LimitAccess.IsUnknown() {
+		out.LimitAccess = types.BoolValue(false)
+	}
+	if out.RemovePayload.IsNull() || out.RemovePayload.IsUnknown() {
+		out.RemovePayload = types.BoolValue(false)
+	}
+	return out
+}
+
+// applyOptionalFeatureResult records a needed reboot and warns about it: the
+// DISM cmdlets never restart the host themselves.
+func applyOptionalFeatureResult(diags *diag.Diagnostics, m *windowsOptionalFeatureModel, result *winclient.OptionalFeatureResult, op string) {
+	if result == nil || !result.RestartNeeded {
+		return
+	}
+	m.RestartPending = types.BoolValue(true)
+	diags.AddWarning(
+		"Reboot required",
+		fmt.Sprintf("%s-WindowsOptionalFeature reported RestartNeeded for feature %q. The feature is fully "+
+			"available after the target host restarts; use windows_reboot or reboot it out-of-band.",
+			op, m.Name.ValueString()),
+	)
+}
+
+// addOptionalFeatureDiag converts a winclient.OptionalFeatureError into a TPF
+// diagnostic.
+func addOptionalFeatureDiag(diags *diag.Diagnostics, summary string, err error) {
+	var oe *winclient.OptionalFeatureError
+	if errors.As(err, &oe) {
+		detail := oe.Message
+		if len(oe.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range oe.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if oe.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", oe.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
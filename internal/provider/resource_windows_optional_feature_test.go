@@ -0,0 +1,255 @@
+// Package provider — unit tests for windows_optional_feature.
+//
+// CRUD handlers are driven via a fakeOptionalFeatureClient injected into
+// windowsOptionalFeatureResource.feat, so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeOptionalFeatureClient struct {
+	readOut    *winclient.OptionalFeatureInfo
+	readErr    error
+	enableIn   winclient.OptionalFeatureInput
+	enableOut  *winclient.OptionalFeatureInfo
+	enableRes  *winclient.OptionalFeatureResult
+	enableErr  error
+	disableIn  winclient.OptionalFeatureInput
+	disableRes *winclient.OptionalFeatureResult
+	disableErr error
+	calls      int
+}
+
+func (f *fakeOptionalFeatureClient) Read(_ context.Context, _ string) (*winclient.OptionalFeatureInfo, error) {
+	return f.readOut, f.readErr
+}
+func (f *fakeOptionalFeatureClient) Enable(_ context.Context, in winclient.OptionalFeatureInput) (*winclient.OptionalFeatureInfo, *winclient.OptionalFeatureResult, error) {
+	f.calls++
+	f.enableIn = in
+	return f.enableOut, f.enableRes, f.enableErr
+}
+func (f *fakeOptionalFeatureClient) Disable(_ context.Context, in winclient.OptionalFeatureInput) (*winclient.OptionalFeatureInfo, *winclient.OptionalFeatureResult, error) {
+	f.calls++
+	f.disableIn = in
+	return nil, f.disableRes, f.disableErr
+}
+
+func ofTimeoutsType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"create": tftypes.String,
+		"update": tftypes.String,
+		"delete": tftypes.String,
+	}}
+}
+
+func ofObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":              tftypes.String,
+		"name":            tftypes.String,
+		"all":             tftypes.Bool,
+		"source":          tftypes.String,
+		"limit_access":    tftypes.Bool,
+		"remove_payload":  tftypes.Bool,
+		"display_name":    tftypes.String,
+		"description":     tftypes.String,
+		"state":           tftypes.String,
+		"install_state":   tftypes.String,
+		"restart_pending": tftypes.Bool,
+		"timeouts":        ofTimeoutsType(),
+	}}
+}
+
+func ofObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, nil),
+		"name":            tftypes.NewValue(tftypes.String, "NetFx3"),
+		"all":             tftypes.NewValue(tftypes.Bool, false),
+		"source":          tftypes.NewValue(tftypes.String, nil),
+		"limit_access":    tftypes.NewValue(tftypes.Bool, false),
+		"remove_payload":  tftypes.NewValue(tftypes.Bool, false),
+		"display_name":    tftypes.NewValue(tftypes.String, nil),
+		"description":     tftypes.NewValue(tftypes.String, nil),
+		"state":           tftypes.NewValue(tftypes.String, nil),
+		"install_state":   tftypes.NewValue(tftypes.String, nil),
+		"restart_pending": tftypes.NewValue(tftypes.Bool, nil),
+		"timeouts":        tftypes.NewValue(ofTimeoutsType(), nil),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(ofObjectType(), base)
+}
+
+func ofInfo(state string) *winclient.OptionalFeatureInfo {
+	return &winclient.OptionalFeatureInfo{
+		Name: "NetFx3", DisplayName: ".NET Framework 3.5", Description: "includes .NET 2.0 and 3.0",
+		State: state, InstallState: map[string]string{"Enabled": "Installed", "EnablePending": "InstallPending", "Disabled": "Available"}[state],
+		Enabled: state == "Enabled" || state == "EnablePending", RestartPending: state == "EnablePending",
+	}
+}
+
+func TestOptionalFeatureSchema(t *testing.T) {
+	s := windowsOptionalFeatureSchemaDefinition(context.Background())
+	for _, name := range []string{"id", "name", "all", "source", "limit_access", "remove_payload",
+		"display_name", "description", "state", "install_state", "restart_pending", "timeouts"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !s.Attributes["name"].IsRequired() || !s.Attributes["state"].IsComputed() {
+		t.Error("name must be required and state computed")
+	}
+}
+
+func TestOptionalFeatureCreate_Handler(t *testing.T) {
+	fake := &fakeOptionalFeatureClient{
+		enableOut: ofInfo("EnablePending"),
+		enableRes: &winclient.OptionalFeatureResult{RestartNeeded: true},
+	}
+	r := &windowsOptionalFeatureResource{feat: fake}
+	schemaDef := windowsOptionalFeatureSchemaDefinition(context.Background())
+	plan := tfsdk.Plan{Schema: schemaDef, Raw: ofObj(map[string]tftypes.Value{
+		"all":          tftypes.NewValue(tftypes.Bool, true),
+		"source":       tftypes.NewValue(tftypes.String, `D:\sources\sxs`),
+		"limit_access": tftypes.NewValue(tftypes.Bool, true),
+	})}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: ofObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	want := winclient.OptionalFeatureInput{Name: "NetFx3", All: true, Source: `D:\sources\sxs`, LimitAccess: true}
+	if fake.enableIn != want {
+		t.Errorf("Enable input = %+v, want %+v", fake.enableIn, want)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a reboot warning, got %v", resp.Diagnostics)
+	}
+	var got windowsOptionalFeatureModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "NetFx3" || got.State.ValueString() != "EnablePending" ||
+		got.InstallState.ValueString() != "InstallPending" || !got.RestartPending.ValueBool() {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestOptionalFeatureCreate_Handler_SourceMissing(t *testing.T) {
+	fake := &fakeOptionalFeatureClient{enableErr: winclient.NewOptionalFeatureError(
+		winclient.OptionalFeatureErrorSourceMissing, "The source files could not be found.", nil,
+		map[string]string{"name": "NetFx3"})}
+	r := &windowsOptionalFeatureResource{feat: fake}
+	schemaDef := windowsOptionalFeatureSchemaDefinition(context.Background())
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaDef, Raw: ofObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaDef, Raw: ofObj(nil)}}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: source_missing") {
+		t.Errorf("expected source_missing diag, got %v", resp.Diagnostics)
+	}
+}
+
+func TestOptionalFeatureRead_Handler(t *testing.T) {
+	schemaDef := windowsOptionalFeatureSchemaDefinition(context.Background())
+	prior := tfsdk.State{Schema: schemaDef, Raw: ofObj(map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "NetFx3"),
+		"remove_payload": tftypes.NewValue(tftypes.Bool, true),
+	})}
+
+	for state, wantRemoved := range map[string]bool{"Enabled": false, "EnablePending": false, "Disabled": true, "": true} {
+		fake := &fakeOptionalFeatureClient{readOut: ofInfo(state)}
+		if state == "" {
+			fake.readOut = nil
+		}
+		r := &windowsOptionalFeatureResource{feat: fake}
+		resp := &resource.ReadResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+		r.Read(context.Background(), resource.ReadRequest{State: prior}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%q: diags: %v", state, resp.Diagnostics)
+		}
+		if resp.State.Raw.IsNull() != wantRemoved {
+			t.Errorf("%q: removed = %v, want %v", state, resp.State.Raw.IsNull(), wantRemoved)
+		}
+		if !wantRemoved {
+			var got windowsOptionalFeatureModel
+			resp.State.Get(context.Background(), &got)
+			if !got.RemovePayload.ValueBool() || got.DisplayName.ValueString() != ".NET Framework 3.5" {
+				t.Errorf("%q: state = %+v", state, got)
+			}
+		}
+	}
+}
+
+func TestOptionalFeatureUpdate_Handler_NoHostCalls(t *testing.T) {
+	fake := &fakeOptionalFeatureClient{}
+	r := &windowsOptionalFeatureResource{feat: fake}
+	schemaDef := windowsOptionalFeatureSchemaDefinition(context.Background())
+	stateVals := map[string]tftypes.Value{
+		"id":            tftypes.NewValue(tftypes.String, "NetFx3"),
+		"state":         tftypes.NewValue(tftypes.String, "Enabled"),
+		"install_state": tftypes.NewValue(tftypes.String, "Installed"),
+	}
+	prior := tfsdk.State{Schema: schemaDef, Raw: ofObj(stateVals)}
+	stateVals["remove_payload"] = tftypes.NewValue(tftypes.Bool, true)
+	plan := tfsdk.Plan{Schema: schemaDef, Raw: ofObj(stateVals)}
+
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: prior}, resp)
+	if resp.Diagnostics.HasError() || fake.calls != 0 {
+		t.Fatalf("diags: %v, host calls = %d", resp.Diagnostics, fake.calls)
+	}
+	var got windowsOptionalFeatureModel
+	resp.State.Get(context.Background(), &got)
+	if !got.RemovePayload.ValueBool() || got.State.ValueString() != "Enabled" {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestOptionalFeatureDelete_Handler(t *testing.T) {
+	schemaDef := windowsOptionalFeatureSchemaDefinition(context.Background())
+	state := tfsdk.State{Schema: schemaDef, Raw: ofObj(map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "NetFx3"),
+		"remove_payload": tftypes.NewValue(tftypes.Bool, true),
+	})}
+
+	fake := &fakeOptionalFeatureClient{disableRes: &winclient.OptionalFeatureResult{RestartNeeded: true}}
+	r := &windowsOptionalFeatureResource{feat: fake}
+	resp := &resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("diags = %v, want one reboot warning", resp.Diagnostics)
+	}
+	if fake.disableIn.Name != "NetFx3" || !fake.disableIn.RemovePayload {
+		t.Errorf("Disable input = %+v", fake.disableIn)
+	}
+
+	fake = &fakeOptionalFeatureClient{disableErr: winclient.NewOptionalFeatureError(
+		winclient.OptionalFeatureErrorNotFound, "gone", nil, nil)}
+	r = &windowsOptionalFeatureResource{feat: fake}
+	resp = &resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("a vanished feature must not fail Delete: %v", resp.Diagnostics)
+	}
+}
+
+func TestOptionalFeatureImportState(t *testing.T) {
+	r := &windowsOptionalFeatureResource{}
+	schemaDef := windowsOptionalFeatureSchemaDefinition(context.Background())
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: schemaDef, Raw: tftypes.NewValue(ofObjectType(), nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "TelnetClient"}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var got windowsOptionalFeatureModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "TelnetClient" || got.Name.ValueString() != "TelnetClient" {
+		t.Errorf("state = %+v", got)
+	}
+}
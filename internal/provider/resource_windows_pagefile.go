@@ -0,0 +1,359 @@
+// Package provider: windows_pagefile resource implementation.
+//
+// This file contains the TPF schema, model and CRUD + ImportState handlers
+// for the windows_pagefile resource. All WinRM interaction is delegated to
+// winclient.PagefileClient (internal/winclient).
+//
+// The resource ID is the upper-case drive ("C:"). Pagefile changes only take
+// effect at the next boot; like windows_hostname, the resource never reboots
+// and reports reboot_pending instead.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                     = (*windowsPagefileResource)(nil)
+	_ resource.ResourceWithConfigure        = (*windowsPagefileResource)(nil)
+	_ resource.ResourceWithImportState      = (*windowsPagefileResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*windowsPagefileResource)(nil)
+)
+
+// NewWindowsPagefileResource is the constructor registered in provider.go.
+func NewWindowsPagefileResource() resource.Resource { return &windowsPagefileResource{} }
+
+// windowsPagefileResource is the TPF resource type for windows_pagefile.
+type windowsPagefileResource struct {
+	pf winclient.WindowsPagefileClient
+}
+
+// windowsPagefileModel is the Terraform state/plan model for the
+// windows_pagefile resource.
+type windowsPagefileModel struct {
+	ID               types.String `tfsdk:"id"`
+	Drive            types.String `tfsdk:"drive"`
+	AutomaticManaged types.Bool   `tfsdk:"automatic_managed"`
+	InitialSizeMB    types.Int64  `tfsdk:"initial_size_mb"`
+	MaximumSizeMB    types.Int64  `tfsdk:"maximum_size_mb"`
+	CurrentSizeMB    types.Int64  `tfsdk:"current_size_mb"`
+	RebootPending    types.Bool   `tfsdk:"reboot_pending"`
+}
+
+// pagefileDriveRegex matches a drive letter with its colon.
+var pagefileDriveRegex = regexp.MustCompile(`^[A-Za-z]:$`)
+
+// Metadata sets the resource type name ("windows_pagefile").
+func (r *windowsPagefileResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pagefile"
+}
+
+// Schema returns the complete TPF schema.
+func (r *windowsPagefileResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsPagefileSchemaDefinition()
+}
+
+// ConfigValidators returns the resource-level cross-field validators.
+func (r *windowsPagefileResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{pagefileSizesValidator{}}
+}
+
+// pagefileSizesValidator checks the size combination at plan time: both
+// sizes or neither, none while automatic_managed is true, and initial not
+// above maximum.
+type pagefileSizesValidator struct{}
+
+func (v pagefileSizesValidator) Description(_ context.Context) string {
+	return "initial_size_mb and maximum_size_mb are set together, not with automatic_managed, and initial does not exceed maximum."
+}
+
+func (v pagefileSizesValidator) MarkdownDescription(_ context.Context) string {
+	return "`initial_size_mb` and `maximum_size_mb` are set together, not with `automatic_managed`, and initial does not exceed maximum."
+}
+
+func (v pagefileSizesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var auto types.Bool
+	var initial, maximum types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("automatic_managed"), &auto)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("initial_size_mb"), &initial)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("maximum_size_mb"), &maximum)...)
+	if resp.Diagnostics.HasError() || initial.IsUnknown() || maximum.IsUnknown() {
+		return
+	}
+	if initial.IsNull() != maximum.IsNull() {
+		missing := "maximum_size_mb"
+		if initial.IsNull() {
+			missing = "initial_size_mb"
+		}
+		resp.Diagnostics.AddAttributeError(path.Root(missing), "Incomplete pagefile size",
+			"initial_size_mb and maximum_size_mb must be set together. Omit both for a pagefile whose size Windows manages.")
+		return
+	}
+	if initial.IsNull() {
+		return
+	}
+	if auto.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("initial_size_mb"), "Conflicting pagefile settings",
+			"automatic_managed = true lets Windows size every pagefile, so initial_size_mb and maximum_size_mb cannot be set. "+
+				"Remove them, or set automatic_managed = false.")
+		return
+	}
+	if initial.ValueInt64() > maximum.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(path.Root("initial_size_mb"), "Invalid pagefile size",
+			fmt.Sprintf("initial_size_mb (%d) must not exceed maximum_size_mb (%d).", initial.ValueInt64(), maximum.ValueInt64()))
+	}
+}
+
+// windowsPagefileSchemaDefinition returns the resource schema. Extracted
+// into a function so it can be unit-tested independently of the resource
+// type.
+func windowsPagefileSchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Configures the pagefile of a drive on a remote Windows host via WinRM + PowerShell, using the CIM classes " +
+			"`Win32_PageFileSetting` and `Win32_ComputerSystem` (`AutomaticManagedPagefile`).\n\n" +
+			"Pagefile changes take effect at the next boot. This resource never reboots the host; it reports `reboot_pending` " +
+			"so a downstream `windows_reboot` can orchestrate the reboot.\n\n" +
+			"`automatic_managed` is machine-wide: declare one `windows_pagefile` with `automatic_managed = true`, or one per drive " +
+			"with it `false`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Upper-case drive, e.g. C:.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"drive": schema.StringAttribute{
+				Required:            true,
+				Description:         "Drive letter with its colon, e.g. C:. The drive must exist.",
+				MarkdownDescription: "Drive letter with its colon, e.g. `C:`. The drive must exist. Changing it replaces the resource.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(pagefileDriveRegex, "must be a drive letter with its colon, e.g. C:"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"automatic_managed": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				Description:         "Let Windows manage the size of every pagefile (Win32_ComputerSystem.AutomaticManagedPagefile). Machine-wide. Default: false.",
+				MarkdownDescription: "Let Windows manage the size of every pagefile (`Win32_ComputerSystem.AutomaticManagedPagefile`). Machine-wide, not per drive. Default: `false`. Read reports whether Windows currently manages the pagefile.",
+			},
+			"initial_size_mb": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Initial pagefile size in MB. Set together with maximum_size_mb; omit both for a system managed size on this drive.",
+				MarkdownDescription: "Initial pagefile size in MB. Set together with `maximum_size_mb`; omit both for a system managed size on this drive. Not allowed with `automatic_managed = true`.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 0xFFFFFFFF),
+				},
+			},
+			"maximum_size_mb": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Maximum pagefile size in MB. At least initial_size_mb.",
+				MarkdownDescription: "Maximum pagefile size in MB. At least `initial_size_mb`.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 0xFFFFFFFF),
+				},
+			},
+			"current_size_mb": schema.Int64Attribute{
+				Computed:            true,
+				Description:         "Allocated size of the pagefile in use on this drive (Win32_PageFileUsage), or null when none is in use.",
+				MarkdownDescription: "Allocated size of the pagefile in use on this drive (`Win32_PageFileUsage`), or `null` when none is in use.",
+			},
+			"reboot_pending": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "True when the pagefile in use does not match the configuration yet, so a reboot is needed.",
+				MarkdownDescription: "True when the pagefile in use does not match the configuration yet, so a reboot is needed to apply it. Clears itself after the reboot.",
+			},
+		},
+	}
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsPagefileResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.pf = winclient.NewPagefileClient(c)
+}
+
+// ImportState accepts the drive ("C:"). drive keeps the spelling of the
+// import ID, so it should match the configuration.
+func (r *windowsPagefileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !pagefileDriveRegex.MatchString(req.ID) {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("Expected a drive letter with its colon (e.g. C:), got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), strings.ToUpper(req.ID))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("drive"), req.ID)...)
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+// Create applies the pagefile configuration.
+func (r *windowsPagefileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsPagefileModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.set(ctx, plan, &resp.State, &resp.Diagnostics, "Create windows_pagefile failed")
+}
+
+// Read refreshes the pagefile state. A drive that no longer exists, or a
+// custom pagefile setting removed out-of-band, removes the resource from
+// state.
+func (r *windowsPagefileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsPagefileModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	live, err := r.pf.Read(ctx, state.Drive.ValueString())
+	if err != nil {
+		addPagefileDiag(&resp.Diagnostics, "Read windows_pagefile failed", err)
+		return
+	}
+	if live == nil || (!state.AutomaticManaged.ValueBool() && !live.AutomaticManaged && !live.Configured) {
+		tflog.Warn(ctx, "windows_pagefile drive or setting not found — removing from state", map[string]interface{}{
+			"drive": state.Drive.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	final := modelFromPagefileState(live, state)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &final)...)
+}
+
+// Update re-applies the pagefile configuration.
+func (r *windowsPagefileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan windowsPagefileModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.set(ctx, plan, &resp.State, &resp.Diagnostics, "Update windows_pagefile failed")
+}
+
+// Delete removes the drive's pagefile setting. With automatic_managed = true
+// there is nothing of the resource's own to remove: automatic management is
+// the Windows default and is left on.
+func (r *windowsPagefileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsPagefileModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.AutomaticManaged.ValueBool() {
+		return
+	}
+	if err := r.pf.Delete(ctx, state.Drive.ValueString()); err != nil {
+		addPagefileDiag(&resp.Diagnostics, "Delete windows_pagefile failed", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// set applies plan through the client and stores the observed state, warning
+// when a reboot is needed. Shared by Create and Update.
+func (r *windowsPagefileResource) set(ctx context.Context, plan windowsPagefileModel, state *tfsdk.State, diags *diag.Diagnostics, summary string) {
+	live, err := r.pf.Set(ctx, winclient.PagefileInput{
+		Drive:            plan.Drive.ValueString(),
+		AutomaticManaged: plan.AutomaticManaged.ValueBool(),
+		InitialSizeMB:    plan.InitialSizeMB.ValueInt64(),
+		MaximumSizeMB:    plan.MaximumSizeMB.ValueInt64(),
+	})
+	if err != nil {
+		addPagefileDiag(diags, summary, err)
+		return
+	}
+	final := modelFromPagefileState(live, plan)
+	if live.PendingReboot {
+		diags.AddWarning("Reboot pending to apply pagefile change",
+			fmt.Sprintf("The pagefile configuration of %s is saved but takes effect at the next boot. "+
+				"Reboot the target host to apply it; windows_pagefile never reboots automatically.", live.Drive))
+	}
+	diags.Append(state.Set(ctx, &final)...)
+}
+
+// modelFromPagefileState projects a winclient.PagefileState onto a
+// windowsPagefileModel. drive keeps the configured spelling; the sizes are
+// null unless a custom size is configured on the drive.
+func modelFromPagefileState(live *winclient.PagefileState, prior windowsPagefileModel) windowsPagefileModel {
+	m := windowsPagefileModel{
+		ID:               types.StringValue(live.Drive),
+		Drive:            prior.Drive,
+		AutomaticManaged: types.BoolValue(live.AutomaticManaged),
+		InitialSizeMB:    types.Int64Null(),
+		MaximumSizeMB:    types.Int64Null(),
+		CurrentSizeMB:    types.Int64Null(),
+		RebootPending:    types.BoolValue(live.PendingReboot),
+	}
+	if m.Drive.IsNull() || m.Drive.IsUnknown() {
+		m.Drive = types.StringValue(live.Drive)
+	}
+	if live.Configured && live.InitialSizeMB > 0 {
+		m.InitialSizeMB = types.Int64Value(live.InitialSizeMB)
+		m.MaximumSizeMB = types.Int64Value(live.MaximumSizeMB)
+	}
+	if live.Active {
+		m.CurrentSizeMB = types.Int64Value(live.CurrentSizeMB)
+	}
+	return m
+}
+
+// addPagefileDiag converts a *winclient.PagefileError into a TPF diagnostic.
+func addPagefileDiag(diags *diag.Diagnostics, summary string, err error) {
+	var pe *winclient.PagefileError
+	if errors.As(err, &pe) {
+		detail := pe.Message
+		if len(pe.Context) > 0 {
+			detail += "\n\nContext:"
+			for k, v := range pe.Context {
+				detail += fmt.Sprintf("\n  %s = %s", k, v)
+			}
+		}
+		if pe.Kind != "" {
+			detail += fmt.Sprintf("\n\nKind: %s", pe.Kind)
+		}
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(summary, err.Error())
+}
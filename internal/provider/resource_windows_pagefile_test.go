@@ -0,0 +1,242 @@
+// Package provider — unit tests for the windows_pagefile resource.
+//
+// They exercise the schema, the size validator and the CRUD handlers
+// without touching WinRM, using a fakePagefileClient injected into
+// windowsPagefileResource.pf.
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// -----------------------------------------------------------------------------
+// Fake WindowsPagefileClient
+// -----------------------------------------------------------------------------
+
+type fakePagefileClient struct {
+	setIn     winclient.PagefileInput
+	setOut    *winclient.PagefileState
+	setErr    error
+	readOut   *winclient.PagefileState
+	readErr   error
+	deleteErr error
+	deleted   string
+}
+
+func (f *fakePagefileClient) Set(_ context.Context, in winclient.PagefileInput) (*winclient.PagefileState, error) {
+	f.setIn = in
+	return f.setOut, f.setErr
+}
+func (f *fakePagefileClient) Read(_ context.Context, _ string) (*winclient.PagefileState, error) {
+	return f.readOut, f.readErr
+}
+func (f *fakePagefileClient) Delete(_ context.Context, drive string) error {
+	f.deleted = drive
+	return f.deleteErr
+}
+
+func pfObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":                tftypes.String,
+		"drive":             tftypes.String,
+		"automatic_managed": tftypes.Bool,
+		"initial_size_mb":   tftypes.Number,
+		"maximum_size_mb":   tftypes.Number,
+		"current_size_mb":   tftypes.Number,
+		"reboot_pending":    tftypes.Bool,
+	}}
+}
+
+func pfObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{}
+	for k, t := range pfObjectType().AttributeTypes {
+		base[k] = tftypes.NewValue(t, nil)
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(pfObjectType(), base)
+}
+
+func pfValues(auto bool, initial, maximum any) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"id":                tftypes.NewValue(tftypes.String, "C:"),
+		"drive":             tftypes.NewValue(tftypes.String, "c:"),
+		"automatic_managed": tftypes.NewValue(tftypes.Bool, auto),
+		"initial_size_mb":   tftypes.NewValue(tftypes.Number, initial),
+		"maximum_size_mb":   tftypes.NewValue(tftypes.Number, maximum),
+	}
+}
+
+func pfRead(t *testing.T, fake *fakePagefileClient, prior map[string]tftypes.Value) (*resource.ReadResponse, windowsPagefileModel) {
+	t.Helper()
+	r := &windowsPagefileResource{pf: fake}
+	s := windowsPagefileSchemaDefinition()
+	state := tfsdk.State{Schema: s, Raw: pfObj(prior)}
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: s, Raw: state.Raw.Copy()}}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+	var m windowsPagefileModel
+	if !resp.State.Raw.IsNull() {
+		resp.State.Get(context.Background(), &m)
+	}
+	return resp, m
+}
+
+// -----------------------------------------------------------------------------
+// Metadata + validator
+// -----------------------------------------------------------------------------
+
+func TestPagefileMetadata(t *testing.T) {
+	r := &windowsPagefileResource{}
+	resp := &resource.MetadataResponse{}
+	r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "windows"}, resp)
+	if resp.TypeName != "windows_pagefile" {
+		t.Errorf("TypeName = %q, want windows_pagefile", resp.TypeName)
+	}
+}
+
+func TestPagefileSizesValidator(t *testing.T) {
+	s := windowsPagefileSchemaDefinition()
+	cases := []struct {
+		name    string
+		auto    bool
+		initial any
+		maximum any
+		wantErr bool
+	}{
+		{"custom size", false, 4096, 8192, false},
+		{"system managed size", false, nil, nil, false},
+		{"automatic", true, nil, nil, false},
+		{"only initial", false, 4096, nil, true},
+		{"only maximum", false, nil, 8192, true},
+		{"sizes with automatic", true, 4096, 8192, true},
+		{"initial above maximum", false, 8192, 4096, true},
+		{"unknown maximum", false, 4096, tftypes.UnknownValue, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &resource.ValidateConfigResponse{}
+			pagefileSizesValidator{}.ValidateResource(context.Background(), resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: s, Raw: pfObj(pfValues(tc.auto, tc.initial, tc.maximum))},
+			}, resp)
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("HasError = %v, want %v: %v", resp.Diagnostics.HasError(), tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CRUD
+// -----------------------------------------------------------------------------
+
+func TestPagefileCreate_WarnsWhenRebootPending(t *testing.T) {
+	fake := &fakePagefileClient{setOut: &winclient.PagefileState{
+		Drive: "C:", Configured: true, InitialSizeMB: 4096, MaximumSizeMB: 8192,
+		Active: true, CurrentSizeMB: 2048, PendingReboot: true,
+	}}
+	r := &windowsPagefileResource{pf: fake}
+	s := windowsPagefileSchemaDefinition()
+	plan := pfValues(false, 4096, 8192)
+	plan["id"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	plan["current_size_mb"] = tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)
+	plan["reboot_pending"] = tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue)
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: pfObj(nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: pfObj(plan)}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if fake.setIn.Drive != "c:" || fake.setIn.InitialSizeMB != 4096 || fake.setIn.MaximumSizeMB != 8192 || fake.setIn.AutomaticManaged {
+		t.Errorf("set input = %+v", fake.setIn)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("warnings = %d, want 1", resp.Diagnostics.WarningsCount())
+	}
+	var m windowsPagefileModel
+	resp.State.Get(context.Background(), &m)
+	if m.ID.ValueString() != "C:" || m.Drive.ValueString() != "c:" || m.CurrentSizeMB.ValueInt64() != 2048 || !m.RebootPending.ValueBool() {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestPagefileRead_AutomaticDriftKeepsResource(t *testing.T) {
+	_, m := pfRead(t, &fakePagefileClient{readOut: &winclient.PagefileState{
+		Drive: "C:", AutomaticManaged: true, Active: true, CurrentSizeMB: 1280,
+	}}, pfValues(false, 4096, 8192))
+	if !m.AutomaticManaged.ValueBool() || !m.InitialSizeMB.IsNull() || m.CurrentSizeMB.ValueInt64() != 1280 {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestPagefileRead_Removed(t *testing.T) {
+	cases := map[string]*winclient.PagefileState{
+		"drive gone":      nil,
+		"setting removed": {Drive: "C:", Active: true, CurrentSizeMB: 4096, PendingReboot: true},
+	}
+	for name, live := range cases {
+		resp, _ := pfRead(t, &fakePagefileClient{readOut: live}, pfValues(false, 4096, 8192))
+		if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+			t.Errorf("%s: state should be removed (diags: %v)", name, resp.Diagnostics)
+		}
+	}
+}
+
+func TestPagefileRead_SystemManagedSize(t *testing.T) {
+	_, m := pfRead(t, &fakePagefileClient{readOut: &winclient.PagefileState{
+		Drive: "C:", Configured: true, Active: true, CurrentSizeMB: 1280,
+	}}, pfValues(false, nil, nil))
+	if m.AutomaticManaged.ValueBool() || !m.InitialSizeMB.IsNull() || !m.MaximumSizeMB.IsNull() || m.RebootPending.ValueBool() {
+		t.Errorf("state = %+v", m)
+	}
+}
+
+func TestPagefileDelete(t *testing.T) {
+	s := windowsPagefileSchemaDefinition()
+	for _, auto := range []bool{false, true} {
+		fake := &fakePagefileClient{}
+		r := &windowsPagefileResource{pf: fake}
+		var initial, maximum any
+		if !auto {
+			initial, maximum = 4096, 8192
+		}
+		resp := &resource.DeleteResponse{}
+		r.Delete(context.Background(), resource.DeleteRequest{
+			State: tfsdk.State{Schema: s, Raw: pfObj(pfValues(auto, initial, maximum))},
+		}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		want := "c:"
+		if auto {
+			want = ""
+		}
+		if fake.deleted != want {
+			t.Errorf("automatic_managed=%v: deleted %q, want %q", auto, fake.deleted, want)
+		}
+	}
+}
+
+func TestPagefileImportState(t *testing.T) {
+	r := &windowsPagefileResource{}
+	s := windowsPagefileSchemaDefinition()
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: pfObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "d:"}, resp)
+	var m windowsPagefileModel
+	resp.State.Get(context.Background(), &m)
+	if resp.Diagnostics.HasError() || m.ID.ValueString() != "D:" || m.Drive.ValueString() != "d:" {
+		t.Errorf("state = %+v, diags = %v", m, resp.Diagnostics)
+	}
+
+	resp = &resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: pfObj(nil)}}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: `C:\pagefile.sys`}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for a path")
+	}
+}
@@ -0,0 +1,370 @@
+// Package provider: windows_powershell_script resource implementation.
+//
+// windows_powershell_script is an escape hatch for operations that have no
+// dedicated resource, modelled on null_resource: the user supplies the
+// scripts for each lifecycle step and is responsible for making them
+// idempotent. create_script runs on create, update_script (when set) on an
+// in-place change of the scripts, delete_script (when set) on destroy, and
+// read_script (when set) on every refresh. The JSON printed by read_script
+// is recorded as the computed result; printing nothing means the object is
+// gone and the resource is removed from state. WinRM interaction is
+// delegated to winclient.PowerShellScriptClient
+// (internal/winclient/powershell_script.go).
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource              = (*windowsPowerShellScriptResource)(nil)
+	_ resource.ResourceWithConfigure = (*windowsPowerShellScriptResource)(nil)
+)
+
+// powerShellScriptDefaultTimeout bounds each script run when neither
+// command_timeout nor the provider's default_command_timeout is set.
+const powerShellScriptDefaultTimeout = 5 * time.Minute
+
+// NewWindowsPowerShellScriptResource is the constructor registered in provider.go.
+func NewWindowsPowerShellScriptResource() resource.Resource {
+	return &windowsPowerShellScriptResource{}
+}
+
+// windowsPowerShellScriptResource is the TPF resource type for
+// windows_powershell_script.
+type windowsPowerShellScriptResource struct {
+	client         winclient.WindowsPowerShellScriptClient
+	defaultTimeout time.Duration
+}
+
+// windowsPowerShellScriptModel is the Terraform state/plan model for
+// windows_powershell_script.
+type windowsPowerShellScriptModel struct {
+	ID             types.String `tfsdk:"id"`
+	CreateScript   types.String `tfsdk:"create_script"`
+	ReadScript     types.String `tfsdk:"read_script"`
+	UpdateScript   types.String `tfsdk:"update_script"`
+	DeleteScript   types.String `tfsdk:"delete_script"`
+	Triggers       types.Map    `tfsdk:"triggers"`
+	CommandTimeout types.String `tfsdk:"command_timeout"`
+	Result         types.String `tfsdk:"result"`
+}
+
+// windowsPowerShellScriptSchemaDefinition returns the schema.Schema for
+// windows_powershell_script.
+func windowsPowerShellScriptSchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Runs user-supplied PowerShell scripts on the remote host for operations that have no " +
+			"dedicated resource, in the spirit of `null_resource`. `create_script` runs on create, `update_script` " +
+			"on an in-place change, `delete_script` on destroy and `read_script` on every refresh; the JSON " +
+			"`read_script` prints is exposed as `result`.\n\n" +
+			"~> **The scripts must be idempotent.** The provider cannot tell what a script did: a create that " +
+			"fails half-way is re-run in full on the next apply.\n\n" +
+			"A script fails when it throws, exits with a non-zero code or ends with `$?` False. " +
+			"`$ErrorActionPreference` is `Stop`. The script's stderr is shown in the error.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Random identifier assigned on create.",
+			},
+			"create_script": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceWithoutUpdateScript,
+						"Replaces the resource when update_script is not set.",
+						"Replaces the resource when `update_script` is not set."),
+				},
+				MarkdownDescription: "Script run when the resource is created. When `update_script` is not set, " +
+					"changing it replaces the resource (runs `delete_script`, then `create_script` again).",
+			},
+			"read_script": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Script run after create and update and on every refresh. It must print a " +
+					"single JSON value (e.g. `... | ConvertTo-Json -Compress`) and nothing else; send logging to " +
+					"`Write-Verbose` or `Write-Warning`, not `Write-Host`. The value is stored in `result`. " +
+					"Printing nothing (or `null`) means the object no longer exists: the resource is removed from " +
+					"state and created again on the next apply.",
+			},
+			"update_script": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Script run in place when `create_script` or `update_script` changes. " +
+					"When not set, changing `create_script` replaces the resource.",
+			},
+			"delete_script": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Script run when the resource is destroyed. When not set, destroying only " +
+					"removes the resource from state.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Arbitrary map of values that, when changed, replace the resource. " +
+					"**ForceNew.**",
+			},
+			"command_timeout": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					rebootTimeoutValidator{},
+				},
+				MarkdownDescription: "Time limit for each script run, as a Go duration string (e.g. `10m`). " +
+					"Defaults to the provider's `default_command_timeout`, or `5m`.",
+			},
+			"result": schema.StringAttribute{
+				Computed: true,
+				MarkdownDescription: "Compact JSON printed by `read_script`, or `null` when `read_script` is " +
+					"not set. Decode it with `jsondecode()`.",
+			},
+		},
+	}
+}
+
+// requiresReplaceWithoutUpdateScript replaces the resource on a
+// create_script change unless update_script can apply it in place.
+func requiresReplaceWithoutUpdateScript(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var update types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("update_script"), &update)...)
+	resp.RequiresReplace = update.IsNull()
+}
+
+// Metadata sets the resource type name ("windows_powershell_script").
+func (r *windowsPowerShellScriptResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_powershell_script"
+}
+
+// Schema returns the full TPF schema for windows_powershell_script.
+func (r *windowsPowerShellScriptResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsPowerShellScriptSchemaDefinition()
+}
+
+// Configure extracts the shared *winclient.Client from provider data and
+// constructs the PowerShellScriptClient.
+func (r *windowsPowerShellScriptResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.client = winclient.NewPowerShellScriptClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
+}
+
+// Create runs create_script, then read_script to record result.
+func (r *windowsPowerShellScriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsPowerShellScriptModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout := r.commandTimeout(plan)
+
+	tflog.Info(ctx, "windows_powershell_script Create: running create_script", map[string]interface{}{
+		"command_timeout": timeout.String(),
+	})
+	if err := r.run(ctx, timeout, "create", plan.CreateScript.ValueString()); err != nil {
+		addPowerShellScriptDiag(&resp.Diagnostics, "create_script failed", err)
+		return
+	}
+
+	result, err := r.read(ctx, timeout, plan)
+	if err != nil {
+		addPowerShellScriptDiag(&resp.Diagnostics, "read_script failed after create", err)
+		return
+	}
+	if !plan.ReadScript.IsNull() && result.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("read_script"), "read_script found nothing after create",
+			"create_script succeeded but read_script printed nothing, which means the object does not exist. "+
+				"Make read_script print the object's state as JSON once create_script has run.")
+		return
+	}
+
+	id, err := newPowerShellScriptID()
+	if err != nil {
+		resp.Diagnostics.AddError("Create windows_powershell_script failed", err.Error())
+		return
+	}
+	plan.ID = types.StringValue(id)
+	plan.Result = result
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read runs read_script, when set, and removes the resource from state when
+// it prints nothing.
+func (r *windowsPowerShellScriptResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsPowerShellScriptModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.ReadScript.IsNull() {
+		return
+	}
+
+	result, err := r.read(ctx, r.commandTimeout(state), state)
+	if err != nil {
+		addPowerShellScriptDiag(&resp.Diagnostics, "read_script failed", err)
+		return
+	}
+	if result.IsNull() {
+		tflog.Info(ctx, "windows_powershell_script Read: read_script printed nothing, removing from state", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.Result = result
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update runs update_script when create_script or update_script changed
+// (without update_script, a create_script change is a replacement), then
+// refreshes result.
+func (r *windowsPowerShellScriptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state windowsPowerShellScriptModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout := r.commandTimeout(plan)
+
+	scriptsChanged := !plan.CreateScript.Equal(state.CreateScript) || !plan.UpdateScript.Equal(state.UpdateScript)
+	if scriptsChanged && !plan.UpdateScript.IsNull() {
+		tflog.Info(ctx, "windows_powershell_script Update: running update_script", map[string]interface{}{
+			"id": state.ID.ValueString(), "command_timeout": timeout.String(),
+		})
+		if err := r.run(ctx, timeout, "update", plan.UpdateScript.ValueString()); err != nil {
+			addPowerShellScriptDiag(&resp.Diagnostics, "update_script failed", err)
+			return
+		}
+	}
+
+	result, err := r.read(ctx, timeout, plan)
+	if err != nil {
+		addPowerShellScriptDiag(&resp.Diagnostics, "read_script failed after update", err)
+		return
+	}
+	plan.ID = state.ID
+	plan.Result = result
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete runs delete_script, when set.
+func (r *windowsPowerShellScriptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsPowerShellScriptModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.DeleteScript.IsNull() {
+		tflog.Debug(ctx, "windows_powershell_script Delete: no delete_script, nothing to do on the host")
+		return
+	}
+	if err := r.run(ctx, r.commandTimeout(state), "delete", state.DeleteScript.ValueString()); err != nil {
+		addPowerShellScriptDiag(&resp.Diagnostics, "delete_script failed", err)
+	}
+}
+
+// commandTimeout returns command_timeout, or the provider default, or
+// powerShellScriptDefaultTimeout. The value was validated at plan time.
+func (r *windowsPowerShellScriptResource) commandTimeout(m windowsPowerShellScriptModel) time.Duration {
+	if !m.CommandTimeout.IsNull() && !m.CommandTimeout.IsUnknown() {
+		if d, err := time.ParseDuration(m.CommandTimeout.ValueString()); err == nil && d > 0 {
+			return d
+		}
+	}
+	return operationTimeout(r.defaultTimeout, powerShellScriptDefaultTimeout)
+}
+
+// run executes one lifecycle script bounded by timeout.
+func (r *windowsPowerShellScriptResource) run(ctx context.Context, timeout time.Duration, op, script string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err := r.client.Run(ctx, op, script)
+	return err
+}
+
+// read runs read_script, when set, and returns its JSON output. The result
+// is null when read_script is not set or printed nothing.
+func (r *windowsPowerShellScriptResource) read(ctx context.Context, timeout time.Duration, m windowsPowerShellScriptModel) (types.String, error) {
+	if m.ReadScript.IsNull() {
+		return types.StringNull(), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := r.client.Read(ctx, m.ReadScript.ValueString())
+	if err != nil || out == "" {
+		return types.StringNull(), err
+	}
+	return types.StringValue(out), nil
+}
+
+// newPowerShellScriptID returns a random 16-byte hex identifier.
+func newPowerShellScriptID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("crypto/rand: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// addPowerShellScriptDiag adds a Terraform diagnostic from a
+// PowerShellScriptClient error. The script's stderr and stdout are shown in
+// full (as truncated by the client) rather than as Context entries, since
+// they usually span several lines.
+func addPowerShellScriptDiag(diags *diag.Diagnostics, summary string, err error) {
+	var pe *winclient.PowerShellScriptError
+	if !errors.As(err, &pe) {
+		diags.AddError(summary, err.Error())
+		return
+	}
+	detail := pe.Message
+	if pe.Cause != nil {
+		detail += ": " + pe.Cause.Error()
+	}
+	if pe.Kind == winclient.PowerShellScriptErrorTimeout {
+		detail += ". Increase command_timeout if the script needs longer."
+	}
+	for _, stream := range []string{"stderr", "stdout"} {
+		if s := strings.TrimSpace(pe.Context[stream]); s != "" {
+			detail += fmt.Sprintf("\n\n%s:\n%s", stream, s)
+		}
+	}
+	detail += fmt.Sprintf("\n\nKind: %s", pe.Kind)
+	diags.AddError(summary, detail)
+}
@@ -0,0 +1,308 @@
+// Package provider — unit tests for the windows_powershell_script resource.
+//
+// A fakePowerShellScriptClient is injected into
+// windowsPowerShellScriptResource.client, so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakePowerShellScriptClient struct {
+	runErr  error
+	readOut string
+	readErr error
+
+	ops         []string
+	scripts     []string
+	lastTimeout time.Duration
+}
+
+func (f *fakePowerShellScriptClient) Run(ctx context.Context, op, script string) (string, error) {
+	f.ops = append(f.ops, op)
+	f.scripts = append(f.scripts, script)
+	if dl, ok := ctx.Deadline(); ok {
+		f.lastTimeout = time.Until(dl).Round(time.Minute)
+	}
+	return "", f.runErr
+}
+
+func (f *fakePowerShellScriptClient) Read(_ context.Context, script string) (string, error) {
+	f.ops = append(f.ops, "read")
+	f.scripts = append(f.scripts, script)
+	return f.readOut, f.readErr
+}
+
+func psObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":              tftypes.String,
+		"create_script":   tftypes.String,
+		"read_script":     tftypes.String,
+		"update_script":   tftypes.String,
+		"delete_script":   tftypes.String,
+		"triggers":        tftypes.Map{ElementType: tftypes.String},
+		"command_timeout": tftypes.String,
+		"result":          tftypes.String,
+	}}
+}
+
+func psObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "0f3c2a"),
+		"create_script":   tftypes.NewValue(tftypes.String, "New-Item -ItemType Directory C:\\App -Force"),
+		"read_script":     tftypes.NewValue(tftypes.String, "Get-Item C:\\App | Select-Object FullName | ConvertTo-Json"),
+		"update_script":   tftypes.NewValue(tftypes.String, nil),
+		"delete_script":   tftypes.NewValue(tftypes.String, "Remove-Item C:\\App -Recurse"),
+		"triggers":        tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+		"command_timeout": tftypes.NewValue(tftypes.String, nil),
+		"result":          tftypes.NewValue(tftypes.String, `{"FullName":"C:\\App"}`),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(psObjectType(), base)
+}
+
+func psPlan(overrides map[string]tftypes.Value) tfsdk.Plan {
+	return tfsdk.Plan{Raw: psObj(overrides), Schema: windowsPowerShellScriptSchemaDefinition()}
+}
+
+func psState(overrides map[string]tftypes.Value) tfsdk.State {
+	return tfsdk.State{Raw: psObj(overrides), Schema: windowsPowerShellScriptSchemaDefinition()}
+}
+
+func psEmptyState() tfsdk.State {
+	return tfsdk.State{Schema: windowsPowerShellScriptSchemaDefinition(), Raw: tftypes.NewValue(psObjectType(), nil)}
+}
+
+func psUnknownComputed() map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"result": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}
+}
+
+func TestPowerShellScriptSchema(t *testing.T) {
+	s := windowsPowerShellScriptSchemaDefinition()
+	for _, name := range []string{"id", "create_script", "read_script", "update_script", "delete_script", "triggers", "command_timeout", "result"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !s.Attributes["create_script"].IsRequired() || !s.Attributes["result"].IsComputed() {
+		t.Error("create_script must be required and result computed")
+	}
+}
+
+func TestPowerShellScriptCreateScript_ReplaceWithoutUpdateScript(t *testing.T) {
+	for _, c := range []struct {
+		update any
+		want   bool
+	}{{update: nil, want: true}, {update: "Set-Content C:\\App\\v 2", want: false}} {
+		resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+		requiresReplaceWithoutUpdateScript(context.Background(), planmodifier.StringRequest{
+			Path: path.Root("create_script"),
+			Plan: psPlan(map[string]tftypes.Value{"update_script": tftypes.NewValue(tftypes.String, c.update)}),
+		}, resp)
+		if resp.Diagnostics.HasError() || resp.RequiresReplace != c.want {
+			t.Errorf("update_script = %v: RequiresReplace = %v, want %v", c.update, resp.RequiresReplace, c.want)
+		}
+	}
+}
+
+func TestPowerShellScriptCreate(t *testing.T) {
+	fake := &fakePowerShellScriptClient{readOut: `{"FullName":"C:\\App"}`}
+	r := &windowsPowerShellScriptResource{client: fake, defaultTimeout: 20 * time.Minute}
+
+	resp := &resource.CreateResponse{State: psEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: psPlan(psUnknownComputed())}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if strings.Join(fake.ops, ",") != "create,read" || !strings.HasPrefix(fake.scripts[0], "New-Item") {
+		t.Errorf("ops = %v, scripts = %v", fake.ops, fake.scripts)
+	}
+	if fake.lastTimeout != 20*time.Minute {
+		t.Errorf("timeout = %s, want the provider default", fake.lastTimeout)
+	}
+	var got windowsPowerShellScriptModel
+	resp.State.Get(context.Background(), &got)
+	if len(got.ID.ValueString()) != 32 || got.Result.ValueString() != `{"FullName":"C:\\App"}` {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestPowerShellScriptCreate_WithoutReadScript(t *testing.T) {
+	fake := &fakePowerShellScriptClient{}
+	r := &windowsPowerShellScriptResource{client: fake}
+
+	overrides := psUnknownComputed()
+	overrides["read_script"] = tftypes.NewValue(tftypes.String, nil)
+	overrides["command_timeout"] = tftypes.NewValue(tftypes.String, "45m")
+	resp := &resource.CreateResponse{State: psEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: psPlan(overrides)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if strings.Join(fake.ops, ",") != "create" || fake.lastTimeout != 45*time.Minute {
+		t.Errorf("ops = %v, timeout = %s", fake.ops, fake.lastTimeout)
+	}
+	var got windowsPowerShellScriptModel
+	resp.State.Get(context.Background(), &got)
+	if !got.Result.IsNull() {
+		t.Errorf("result = %v, want null", got.Result)
+	}
+}
+
+func TestPowerShellScriptCreate_ScriptFailureShowsStderr(t *testing.T) {
+	fake := &fakePowerShellScriptClient{runErr: winclient.NewPowerShellScriptError(
+		winclient.PowerShellScriptErrorFailed, "create script failed with exit code 1: Access to the path is denied.", nil,
+		map[string]string{"stderr": "Access to the path is denied.\r\nAt line:1 char:1\r\n", "exit_code": "1"})}
+	r := &windowsPowerShellScriptResource{client: fake}
+
+	resp := &resource.CreateResponse{State: psEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: psPlan(psUnknownComputed())}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error")
+	}
+	detail := resp.Diagnostics[0].Detail()
+	if !strings.Contains(detail, "stderr:\nAccess to the path is denied.\r\nAt line:1 char:1") || !strings.Contains(detail, "Kind: script_failed") {
+		t.Errorf("detail = %q", detail)
+	}
+	if !resp.State.Raw.IsNull() || len(fake.ops) != 1 {
+		t.Errorf("a failed create must not be recorded or read back, ops = %v", fake.ops)
+	}
+}
+
+func TestPowerShellScriptCreate_ReadFindsNothing(t *testing.T) {
+	r := &windowsPowerShellScriptResource{client: &fakePowerShellScriptClient{}}
+	resp := &resource.CreateResponse{State: psEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: psPlan(psUnknownComputed())}, resp)
+	if !resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("diagnostics = %v, want an error and no state", resp.Diagnostics)
+	}
+}
+
+func TestPowerShellScriptRead(t *testing.T) {
+	fake := &fakePowerShellScriptClient{readOut: `{"FullName":"C:\\App","Mode":"d----"}`}
+	r := &windowsPowerShellScriptResource{client: fake}
+
+	resp := &resource.ReadResponse{State: psState(nil)}
+	r.Read(context.Background(), resource.ReadRequest{State: psState(nil)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	var got windowsPowerShellScriptModel
+	resp.State.Get(context.Background(), &got)
+	if got.Result.ValueString() != fake.readOut {
+		t.Errorf("result = %v", got.Result)
+	}
+
+	fake.readOut = ""
+	resp = &resource.ReadResponse{State: psState(nil)}
+	r.Read(context.Background(), resource.ReadRequest{State: psState(nil)}, resp)
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("empty read_script output must remove the resource: %v", resp.Diagnostics)
+	}
+}
+
+func TestPowerShellScriptRead_NoReadScript(t *testing.T) {
+	fake := &fakePowerShellScriptClient{}
+	r := &windowsPowerShellScriptResource{client: fake}
+	noRead := map[string]tftypes.Value{
+		"read_script": tftypes.NewValue(tftypes.String, nil),
+		"result":      tftypes.NewValue(tftypes.String, nil),
+	}
+	resp := &resource.ReadResponse{State: psState(noRead)}
+	r.Read(context.Background(), resource.ReadRequest{State: psState(noRead)}, resp)
+	if resp.Diagnostics.HasError() || resp.State.Raw.IsNull() || len(fake.ops) != 0 {
+		t.Errorf("Read without read_script: %v, ops = %v", resp.Diagnostics, fake.ops)
+	}
+}
+
+func TestPowerShellScriptUpdate(t *testing.T) {
+	cases := []struct {
+		name    string
+		plan    map[string]tftypes.Value
+		wantOps string
+	}{
+		{
+			name: "create_script changed with update_script",
+			plan: map[string]tftypes.Value{
+				"create_script": tftypes.NewValue(tftypes.String, "New-Item -ItemType Directory C:\\App2 -Force"),
+				"update_script": tftypes.NewValue(tftypes.String, "Rename-Item C:\\App C:\\App2"),
+			},
+			wantOps: "update,read",
+		},
+		{
+			name:    "only command_timeout changed",
+			plan:    map[string]tftypes.Value{"command_timeout": tftypes.NewValue(tftypes.String, "1h")},
+			wantOps: "read",
+		},
+	}
+	for _, c := range cases {
+		fake := &fakePowerShellScriptClient{readOut: `{"FullName":"C:\\App2"}`}
+		r := &windowsPowerShellScriptResource{client: fake}
+		c.plan["result"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+
+		resp := &resource.UpdateResponse{State: psState(nil)}
+		r.Update(context.Background(), resource.UpdateRequest{Plan: psPlan(c.plan), State: psState(nil)}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: Update: %v", c.name, resp.Diagnostics)
+		}
+		if got := strings.Join(fake.ops, ","); got != c.wantOps {
+			t.Errorf("%s: ops = %s, want %s", c.name, got, c.wantOps)
+		}
+		var got windowsPowerShellScriptModel
+		resp.State.Get(context.Background(), &got)
+		if got.ID.ValueString() != "0f3c2a" || got.Result.ValueString() != `{"FullName":"C:\\App2"}` {
+			t.Errorf("%s: state = %+v", c.name, got)
+		}
+	}
+}
+
+func TestPowerShellScriptDelete(t *testing.T) {
+	fake := &fakePowerShellScriptClient{}
+	r := &windowsPowerShellScriptResource{client: fake}
+	resp := &resource.DeleteResponse{State: psState(nil)}
+	r.Delete(context.Background(), resource.DeleteRequest{State: psState(nil)}, resp)
+	if resp.Diagnostics.HasError() || strings.Join(fake.ops, ",") != "delete" || !strings.HasPrefix(fake.scripts[0], "Remove-Item") {
+		t.Errorf("Delete: %v, ops = %v", resp.Diagnostics, fake.ops)
+	}
+
+	fake = &fakePowerShellScriptClient{}
+	r = &windowsPowerShellScriptResource{client: fake}
+	noDelete := map[string]tftypes.Value{"delete_script": tftypes.NewValue(tftypes.String, nil)}
+	resp = &resource.DeleteResponse{State: psState(noDelete)}
+	r.Delete(context.Background(), resource.DeleteRequest{State: psState(noDelete)}, resp)
+	if resp.Diagnostics.HasError() || len(fake.ops) != 0 {
+		t.Errorf("Delete without delete_script: %v, ops = %v", resp.Diagnostics, fake.ops)
+	}
+}
+
+func TestPowerShellScriptCommandTimeout(t *testing.T) {
+	r := &windowsPowerShellScriptResource{}
+	if got := r.commandTimeout(windowsPowerShellScriptModel{CommandTimeout: types.StringNull()}); got != powerShellScriptDefaultTimeout {
+		t.Errorf("default = %s", got)
+	}
+	r.defaultTimeout = time.Hour
+	if got := r.commandTimeout(windowsPowerShellScriptModel{CommandTimeout: types.StringValue("90s")}); got != 90*time.Second {
+		t.Errorf("explicit = %s", got)
+	}
+	if got := r.commandTimeout(windowsPowerShellScriptModel{CommandTimeout: types.StringNull()}); got != time.Hour {
+		t.Errorf("provider default = %s", got)
+	}
+}
@@ -0,0 +1,236 @@
+// Package provider: windows_reboot resource implementation.
+//
+// windows_reboot restarts the host on create (and again whenever triggers
+// change, which forces replacement) and blocks until the host is reachable
+// with a new boot time. It owns nothing on the host: Read and Delete are
+// no-ops. WinRM interaction is delegated to winclient.RebootClientImpl
+// (internal/winclient/reboot.go).
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource              = (*windowsRebootResource)(nil)
+	_ resource.ResourceWithConfigure = (*windowsRebootResource)(nil)
+)
+
+// defaultRebootTimeout is the default for the timeout attribute.
+const defaultRebootTimeout = "15m"
+
+// NewWindowsRebootResource is the constructor registered in provider.go.
+func NewWindowsRebootResource() resource.Resource {
+	return &windowsRebootResource{}
+}
+
+// windowsRebootResource is the TPF resource type for windows_reboot.
+type windowsRebootResource struct {
+	client winclient.RebootClient
+}
+
+// windowsRebootModel is the Terraform state/plan model for windows_reboot.
+type windowsRebootModel struct {
+	ID       types.String `tfsdk:"id"`
+	Triggers types.Map    `tfsdk:"triggers"`
+	Timeout  types.String `tfsdk:"timeout"`
+	BootTime types.String `tfsdk:"boot_time"`
+}
+
+// rebootTimeoutValidator requires a positive Go duration string.
+type rebootTimeoutValidator struct{}
+
+func (rebootTimeoutValidator) Description(_ context.Context) string {
+	return "must be a positive Go duration string, e.g. 15m or 1h"
+}
+
+func (v rebootTimeoutValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (rebootTimeoutValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if d, err := time.ParseDuration(req.ConfigValue.ValueString()); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid timeout",
+			fmt.Sprintf("Expected a positive Go duration string such as 15m or 1h, got %q.", req.ConfigValue.ValueString()))
+	}
+}
+
+// windowsRebootSchemaDefinition returns the schema.Schema for windows_reboot.
+func windowsRebootSchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Restarts the remote Windows host (`Restart-Computer -Force`) and waits until it is " +
+			"reachable again with a new boot time. The reboot happens when the resource is created and whenever " +
+			"`triggers` changes; destroying the resource does nothing on the host.\n\n" +
+			"Dropped connections while the host goes down and refused connections while it is off are expected " +
+			"and retried until `timeout` elapses.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "The boot time recorded by the reboot that created this resource instance.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Arbitrary map of values that, when changed, reboot the host again. " +
+					"Reference attributes of upstream resources (e.g. a feature's `id`) to reboot after they change. " +
+					"**ForceNew.**",
+			},
+			"timeout": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultRebootTimeout),
+				Validators: []validator.String{
+					rebootTimeoutValidator{},
+				},
+				MarkdownDescription: "How long to wait for the host to come back, as a Go duration string " +
+					"(e.g. `30m`). Covers the restart request, the shutdown and the boot. Default: `" + defaultRebootTimeout + "`.",
+			},
+			"boot_time": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Last boot time of the host after the reboot, RFC 3339 in UTC.",
+			},
+		},
+	}
+}
+
+// Metadata sets the resource type name ("windows_reboot").
+func (r *windowsRebootResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_reboot"
+}
+
+// Schema returns the full TPF schema for windows_reboot.
+func (r *windowsRebootResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsRebootSchemaDefinition()
+}
+
+// Configure extracts the shared *winclient.Client from provider data and
+// constructs the RebootClient.
+func (r *windowsRebootResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.client = winclient.NewRebootClient(c)
+}
+
+// Create reboots the host and waits for it to come back.
+func (r *windowsRebootResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsRebootModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, err := time.ParseDuration(plan.Timeout.ValueString())
+	if err != nil || timeout <= 0 {
+		resp.Diagnostics.AddAttributeError(pathAttr("timeout"), "Invalid timeout",
+			fmt.Sprintf("Expected a positive Go duration string such as 15m or 1h, got %q.", plan.Timeout.ValueString()))
+		return
+	}
+
+	tflog.Info(ctx, "windows_reboot Create: restarting host", map[string]interface{}{
+		"timeout": timeout.String(),
+	})
+
+	info, err := r.client.Reboot(ctx, timeout)
+	if err != nil {
+		addRebootDiag(&resp.Diagnostics, err)
+		return
+	}
+
+	tflog.Info(ctx, "windows_reboot Create: host is back", map[string]interface{}{
+		"hostname": info.Hostname, "boot_time": info.BootTime.Format(time.RFC3339),
+	})
+
+	bootTime := types.StringValue(info.BootTime.UTC().Format(time.RFC3339))
+	plan.ID = bootTime
+	plan.BootTime = bootTime
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the recorded state: a later reboot by someone else is not drift.
+func (r *windowsRebootResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsRebootModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only records a timeout change; triggers is ForceNew, so the host is
+// not rebooted.
+func (r *windowsRebootResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state windowsRebootModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = state.ID
+	plan.BootTime = state.BootTime
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state.
+func (r *windowsRebootResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	tflog.Debug(ctx, "windows_reboot Delete: nothing to do on the host")
+}
+
+// addRebootDiag adds a Terraform diagnostic from a RebootClient error.
+func addRebootDiag(diags *diag.Diagnostics, err error) {
+	var re *winclient.RebootError
+	if !errors.As(err, &re) {
+		diags.AddError("Reboot failed", err.Error())
+		return
+	}
+	switch re.Kind {
+	case winclient.RebootErrorTimeout:
+		diags.AddAttributeError(pathAttr("timeout"), "Reboot failed: host did not come back in time",
+			re.Error()+". Increase timeout if the host needs longer to boot (e.g. while installing updates).")
+	case winclient.RebootErrorPermission:
+		diags.AddError("Reboot failed: permission denied",
+			re.Message+" (the WinRM account needs the SeRemoteShutdownPrivilege, held by local Administrators).")
+	default:
+		if winclient.TransportFailureKind(err) == winclient.FailureAuth {
+			diags.AddError("Reboot failed: host rejected the credentials", re.Error())
+			return
+		}
+		diags.AddError("Reboot failed", re.Error())
+	}
+}
@@ -0,0 +1,62 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for windows_reboot.
+//
+// Requires TF_ACC=1, WINDOWS_HOST / WINDOWS_USERNAME / WINDOWS_PASSWORD and,
+// because the target host really restarts (twice), an explicit
+// WINDOWS_ACC_ALLOW_REBOOT=1 opt-in.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsReboot
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccRebootPreCheck(t *testing.T) {
+	t.Helper()
+	testAccEnvVarPreCheck(t)
+	if os.Getenv("WINDOWS_ACC_ALLOW_REBOOT") != "1" {
+		t.Skip("WINDOWS_ACC_ALLOW_REBOOT not set to 1; skipping test that restarts the target host")
+	}
+}
+
+func testAccRebootConfig(trigger string) string {
+	return `
+resource "windows_reboot" "test" {
+  triggers = {
+    run = "` + trigger + `"
+  }
+  timeout = "20m"
+}
+`
+}
+
+// TestAccWindowsReboot_Basic — reboot on create, no-op plan, reboot again
+// when triggers change.
+func TestAccWindowsReboot_Basic(t *testing.T) {
+	testAccRebootPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRebootConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("windows_reboot.test", "boot_time"),
+					resource.TestCheckResourceAttrPair("windows_reboot.test", "id", "windows_reboot.test", "boot_time"),
+				),
+			},
+			{
+				Config:   testAccRebootConfig("1"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccRebootConfig("2"),
+				Check:  resource.TestCheckResourceAttrSet("windows_reboot.test", "boot_time"),
+			},
+		},
+	})
+}
@@ -0,0 +1,184 @@
+// Package provider — unit tests for the windows_reboot resource.
+//
+// A fakeRebootClient is injected into windowsRebootResource.client, so no
+// WinRM connection (or reboot) is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeRebootClient struct {
+	rebootOut *winclient.BootInfo
+	rebootErr error
+
+	rebootCalls int
+	lastTimeout time.Duration
+}
+
+func (f *fakeRebootClient) BootTime(_ context.Context) (*winclient.BootInfo, error) {
+	return f.rebootOut, nil
+}
+
+func (f *fakeRebootClient) Reboot(_ context.Context, timeout time.Duration) (*winclient.BootInfo, error) {
+	f.rebootCalls++
+	f.lastTimeout = timeout
+	return f.rebootOut, f.rebootErr
+}
+
+func rbObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":        tftypes.String,
+		"triggers":  tftypes.Map{ElementType: tftypes.String},
+		"timeout":   tftypes.String,
+		"boot_time": tftypes.String,
+	}}
+}
+
+func rbObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "2026-10-17T09:30:12Z"),
+		"triggers": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"feature": tftypes.NewValue(tftypes.String, "Web-Server"),
+		}),
+		"timeout":   tftypes.NewValue(tftypes.String, "15m"),
+		"boot_time": tftypes.NewValue(tftypes.String, "2026-10-17T09:30:12Z"),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(rbObjectType(), base)
+}
+
+func rbPlan(overrides map[string]tftypes.Value) tfsdk.Plan {
+	return tfsdk.Plan{Raw: rbObj(overrides), Schema: windowsRebootSchemaDefinition()}
+}
+
+func rbState(overrides map[string]tftypes.Value) tfsdk.State {
+	return tfsdk.State{Raw: rbObj(overrides), Schema: windowsRebootSchemaDefinition()}
+}
+
+func rbEmptyState() tfsdk.State {
+	return tfsdk.State{Schema: windowsRebootSchemaDefinition(), Raw: tftypes.NewValue(rbObjectType(), nil)}
+}
+
+func rbUnknownComputed() map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"boot_time": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}
+}
+
+func TestRebootSchema(t *testing.T) {
+	s := windowsRebootSchemaDefinition()
+	for _, name := range []string{"id", "triggers", "timeout", "boot_time"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !s.Attributes["triggers"].IsOptional() || !s.Attributes["boot_time"].IsComputed() {
+		t.Error("triggers must be optional and boot_time computed")
+	}
+}
+
+func TestRebootTimeoutValidator(t *testing.T) {
+	v := rebootTimeoutValidator{}
+	for in, wantErr := range map[string]bool{"15m": false, "1h30m": false, "0s": true, "-5m": true, "15": true} {
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path: path.Root("timeout"), ConfigValue: types.StringValue(in),
+		}, resp)
+		if resp.Diagnostics.HasError() != wantErr {
+			t.Errorf("%q: HasError = %v, want %v", in, resp.Diagnostics.HasError(), wantErr)
+		}
+	}
+}
+
+func TestRebootCreate(t *testing.T) {
+	fake := &fakeRebootClient{rebootOut: &winclient.BootInfo{
+		Hostname: "WIN01", BootTime: time.Date(2026, 10, 17, 9, 30, 12, 500, time.UTC),
+	}}
+	r := &windowsRebootResource{client: fake}
+
+	overrides := rbUnknownComputed()
+	overrides["timeout"] = tftypes.NewValue(tftypes.String, "30m")
+	resp := &resource.CreateResponse{State: rbEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: rbPlan(overrides)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if fake.rebootCalls != 1 || fake.lastTimeout != 30*time.Minute {
+		t.Errorf("Reboot calls = %d, timeout = %s", fake.rebootCalls, fake.lastTimeout)
+	}
+	var got windowsRebootModel
+	resp.State.Get(context.Background(), &got)
+	if got.BootTime.ValueString() != "2026-10-17T09:30:12Z" || got.ID.ValueString() != got.BootTime.ValueString() {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestRebootCreate_Timeout(t *testing.T) {
+	fake := &fakeRebootClient{rebootErr: winclient.NewRebootError(
+		winclient.RebootErrorTimeout, "host did not come back within 15m0s", nil, nil)}
+	r := &windowsRebootResource{client: fake}
+
+	resp := &resource.CreateResponse{State: rbEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: rbPlan(rbUnknownComputed())}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Increase timeout") {
+		t.Errorf("diagnostics = %v, want a timeout error", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("a failed reboot must not be recorded in state")
+	}
+}
+
+func TestRebootUpdate_DoesNotReboot(t *testing.T) {
+	fake := &fakeRebootClient{}
+	r := &windowsRebootResource{client: fake}
+
+	resp := &resource.UpdateResponse{State: rbState(nil)}
+	r.Update(context.Background(), resource.UpdateRequest{
+		Plan: rbPlan(map[string]tftypes.Value{
+			"timeout":   tftypes.NewValue(tftypes.String, "1h"),
+			"boot_time": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+		State: rbState(nil),
+	}, resp)
+	if resp.Diagnostics.HasError() || fake.rebootCalls != 0 {
+		t.Fatalf("Update: %v, reboot calls = %d", resp.Diagnostics, fake.rebootCalls)
+	}
+	var got windowsRebootModel
+	resp.State.Get(context.Background(), &got)
+	if got.Timeout.ValueString() != "1h" || got.BootTime.ValueString() != "2026-10-17T09:30:12Z" {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestRebootReadAndDelete_NoHostCalls(t *testing.T) {
+	fake := &fakeRebootClient{}
+	r := &windowsRebootResource{client: fake}
+
+	readResp := &resource.ReadResponse{State: rbState(nil)}
+	r.Read(context.Background(), resource.ReadRequest{State: rbState(nil)}, readResp)
+	if readResp.Diagnostics.HasError() || readResp.State.Raw.IsNull() {
+		t.Errorf("Read: %v", readResp.Diagnostics)
+	}
+
+	delResp := &resource.DeleteResponse{State: rbState(nil)}
+	r.Delete(context.Background(), resource.DeleteRequest{State: rbState(nil)}, delResp)
+	if delResp.Diagnostics.HasError() || fake.rebootCalls != 0 {
+		t.Errorf("Delete: %v, reboot calls = %d", delResp.Diagnostics, fake.rebootCalls)
+	}
+}
@@ -0,0 +1,334 @@
+// Package provider: windows_registry_key resource implementation.
+//
+// Manages the existence of a registry key, independently of its values
+// (windows_registry_value). WinRM interaction is delegated to
+// winclient.RegistryKeyClientImpl (internal/winclient/registry_key.go).
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                = (*windowsRegistryKeyResource)(nil)
+	_ resource.ResourceWithConfigure   = (*windowsRegistryKeyResource)(nil)
+	_ resource.ResourceWithImportState = (*windowsRegistryKeyResource)(nil)
+)
+
+// NewWindowsRegistryKeyResource is the constructor registered in provider.go.
+func NewWindowsRegistryKeyResource() resource.Resource {
+	return &windowsRegistryKeyResource{}
+}
+
+// windowsRegistryKeyResource is the TPF resource type for windows_registry_key.
+type windowsRegistryKeyResource struct {
+	client winclient.RegistryKeyClient
+}
+
+// windowsRegistryKeyModel is the Terraform state/plan model for windows_registry_key.
+type windowsRegistryKeyModel struct {
+	ID              types.String `tfsdk:"id"`
+	Path            types.String `tfsdk:"path"`
+	AllowExisting   types.Bool   `tfsdk:"allow_existing"`
+	DeleteRecursive types.Bool   `tfsdk:"delete_recursive"`
+	Exists          types.Bool   `tfsdk:"exists"`
+}
+
+// registryKeyHives maps every accepted hive spelling to the abbreviation the
+// winclient scripts expect.
+var registryKeyHives = map[string]string{
+	"HKLM": "HKLM", "HKEY_LOCAL_MACHINE": "HKLM",
+	"HKCU": "HKCU", "HKEY_CURRENT_USER": "HKCU",
+	"HKCR": "HKCR", "HKEY_CLASSES_ROOT": "HKCR",
+	"HKU": "HKU", "HKEY_USERS": "HKU",
+	"HKCC": "HKCC", "HKEY_CURRENT_CONFIG": "HKCC",
+}
+
+// parseRegistryKeyPath splits a full key path into hive abbreviation and
+// subkey path. Both the PowerShell drive form (HKLM:\SOFTWARE\Foo) and the
+// plain form (HKLM\SOFTWARE\Foo, HKEY_LOCAL_MACHINE\SOFTWARE\Foo) are
+// accepted. The hive root alone is rejected.
+func parseRegistryKeyPath(p string) (hive, subPath string, err error) {
+	i := strings.IndexByte(p, '\\')
+	if i < 0 {
+		return "", "", fmt.Errorf("registry key path %q must be of the form HIVE\\path (e.g. HKLM:\\SOFTWARE\\MyApp)", p)
+	}
+	hive, ok := registryKeyHives[strings.ToUpper(strings.TrimSuffix(p[:i], ":"))]
+	if !ok {
+		return "", "", fmt.Errorf("unknown hive %q in registry key path %q; must be one of HKLM, HKCU, HKCR, HKU, HKCC", p[:i], p)
+	}
+	subPath = strings.TrimSuffix(p[i+1:], "\\")
+	if subPath == "" {
+		return "", "", fmt.Errorf("registry key path %q names a hive root, which cannot be managed", p)
+	}
+	if strings.HasPrefix(subPath, "\\") || strings.Contains(subPath, "\\\\") {
+		return "", "", fmt.Errorf("registry key path %q contains an empty key name", p)
+	}
+	return hive, subPath, nil
+}
+
+// registryKeyPathValidator validates the path attribute with parseRegistryKeyPath.
+type registryKeyPathValidator struct{}
+
+func (registryKeyPathValidator) Description(_ context.Context) string {
+	return "must be a registry key path below a hive, e.g. HKLM:\\SOFTWARE\\MyApp"
+}
+
+func (v registryKeyPathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (registryKeyPathValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, _, err := parseRegistryKeyPath(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid registry key path", err.Error())
+	}
+}
+
+// windowsRegistryKeySchemaDefinition returns the schema.Schema for windows_registry_key.
+func windowsRegistryKeySchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Manages a Windows registry key on a remote host via WinRM. " +
+			"Only the key's existence is managed; use `windows_registry_value` for the values it holds.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "The key path as configured. Identical to the import ID.",
+			},
+			"path": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					registryKeyPathValidator{},
+				},
+				MarkdownDescription: "Full key path, e.g. `HKLM:\\SOFTWARE\\MyApp` or `HKLM\\SOFTWARE\\MyApp`. " +
+					"Hives: HKLM, HKCU, HKCR, HKU, HKCC (long names accepted). Missing parent keys are created. " +
+					"**ForceNew.**",
+			},
+			"allow_existing": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				MarkdownDescription: "When `true`, Create adopts a key that already exists instead of failing. " +
+					"An adopted key is deleted on destroy like any other. Default: `false`.",
+			},
+			"delete_recursive": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				MarkdownDescription: "When `true`, destroy also removes all child keys. When `false` (default), " +
+					"destroy fails if the key has child keys; its values are always removed with it.",
+			},
+			"exists": schema.BoolAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Whether the key exists on the host. Always `true` in state: a key deleted " +
+					"out of band is removed from state on refresh and recreated on the next apply.",
+			},
+		},
+	}
+}
+
+// Metadata sets the resource type name ("windows_registry_key").
+func (r *windowsRegistryKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_key"
+}
+
+// Schema returns the full TPF schema for windows_registry_key.
+func (r *windowsRegistryKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsRegistryKeySchemaDefinition()
+}
+
+// Configure extracts the shared *winclient.Client from provider data and
+// constructs the RegistryKeyClient.
+func (r *windowsRegistryKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.client = winclient.NewRegistryKeyClient(c)
+}
+
+// Create creates the key, or adopts it when allow_existing is set.
+func (r *windowsRegistryKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsRegistryKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hive, subPath, err := parseRegistryKeyPath(plan.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(pathAttr("path"), "Invalid registry key path", err.Error())
+		return
+	}
+
+	tflog.Debug(ctx, "windows_registry_key Create", map[string]interface{}{
+		"hive": hive, "path": subPath, "allow_existing": plan.AllowExisting.ValueBool(),
+	})
+
+	st, err := r.client.Create(ctx, winclient.RegistryKeyInput{
+		Hive: hive, Path: subPath, AllowExisting: plan.AllowExisting.ValueBool(),
+	})
+	if err != nil {
+		addRegistryKeyDiag(&resp.Diagnostics, "Create", err)
+		return
+	}
+	if st.Existed {
+		tflog.Info(ctx, "windows_registry_key adopted an existing key", map[string]interface{}{
+			"hive": hive, "path": subPath, "subkey_count": st.SubKeyCount, "value_count": st.ValueCount,
+		})
+	}
+
+	plan.ID = plan.Path
+	plan.Exists = types.BoolValue(true)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read removes the resource from state when the key no longer exists.
+func (r *windowsRegistryKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsRegistryKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hive, subPath, err := parseRegistryKeyPath(state.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Malformed resource state", err.Error())
+		return
+	}
+
+	st, err := r.client.Read(ctx, hive, subPath)
+	if err != nil {
+		addRegistryKeyDiag(&resp.Diagnostics, "Read", err)
+		return
+	}
+	if st == nil {
+		tflog.Debug(ctx, "windows_registry_key Read: key gone, removing from state", map[string]interface{}{
+			"hive": hive, "path": subPath,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.Exists = types.BoolValue(true)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only records allow_existing / delete_recursive changes; path is
+// ForceNew, so nothing on the host changes.
+func (r *windowsRegistryKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state windowsRegistryKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = state.ID
+	plan.Exists = state.Exists
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the key. Idempotent: a missing key is a no-op.
+func (r *windowsRegistryKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state windowsRegistryKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hive, subPath, err := parseRegistryKeyPath(state.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Malformed resource state", err.Error())
+		return
+	}
+
+	tflog.Debug(ctx, "windows_registry_key Delete", map[string]interface{}{
+		"hive": hive, "path": subPath, "recursive": state.DeleteRecursive.ValueBool(),
+	})
+
+	if err := r.client.Delete(ctx, hive, subPath, state.DeleteRecursive.ValueBool()); err != nil {
+		addRegistryKeyDiag(&resp.Diagnostics, "Delete", err)
+	}
+}
+
+// ImportState imports a key by its full path (e.g. HKLM:\SOFTWARE\MyApp).
+// Importing a key that does not exist is an error.
+func (r *windowsRegistryKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	hive, subPath, err := parseRegistryKeyPath(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	st, err := r.client.Read(ctx, hive, subPath)
+	if err != nil {
+		addRegistryKeyDiag(&resp.Diagnostics, "ImportState", err)
+		return
+	}
+	if st == nil {
+		resp.Diagnostics.AddError("Import failed: key not found",
+			fmt.Sprintf("Registry key %s\\%s does not exist on the target host.", hive, subPath))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &windowsRegistryKeyModel{
+		ID:              types.StringValue(req.ID),
+		Path:            types.StringValue(req.ID),
+		AllowExisting:   types.BoolValue(false),
+		DeleteRecursive: types.BoolValue(false),
+		Exists:          types.BoolValue(true),
+	})...)
+}
+
+// addRegistryKeyDiag adds a Terraform diagnostic from a RegistryKeyClient error.
+func addRegistryKeyDiag(diags *diag.Diagnostics, op string, err error) {
+	var rke *winclient.RegistryKeyError
+	if !errors.As(err, &rke) {
+		diags.AddError(fmt.Sprintf("Registry key %s failed", op), err.Error())
+		return
+	}
+	switch rke.Kind {
+	case winclient.RegistryKeyErrorAlreadyExists:
+		diags.AddError(fmt.Sprintf("Registry key %s failed: key already exists", op),
+			rke.Message+" — set allow_existing = true to adopt it, or import it with terraform import.")
+	case winclient.RegistryKeyErrorHasSubKeys:
+		diags.AddError(fmt.Sprintf("Registry key %s failed: key has child keys", op),
+			rke.Message+". Set delete_recursive = true (and apply) to remove the key with its children.")
+	case winclient.RegistryKeyErrorPermission:
+		diags.AddError(fmt.Sprintf("Registry key %s failed: permission denied", op),
+			rke.Message+" (Local Administrator on the target host is required for HKLM/HKCR/HKU/HKCC).")
+	case winclient.RegistryKeyErrorInvalidInput:
+		diags.AddError(fmt.Sprintf("Registry key %s failed: invalid input", op), rke.Message)
+	default:
+		diags.AddError(fmt.Sprintf("Registry key %s failed", op), rke.Error())
+	}
+}
@@ -0,0 +1,73 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for windows_registry_key.
+//
+// Requires TF_ACC=1, WINDOWS_HOST / WINDOWS_USERNAME / WINDOWS_PASSWORD and
+// Local Administrator rights on the target (the keys live under HKLM). The
+// keys are prefixed with TF_ACC_ and removed on destroy.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsRegistryKey_Basic — create + idempotency + import.
+func TestAccWindowsRegistryKey_Basic(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+
+	cfg := `
+resource "windows_registry_key" "test" {
+  path = "HKLM:\\SOFTWARE\\TF_ACC_RegistryKey\\Child"
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windows_registry_key.test", "exists", "true"),
+					resource.TestCheckResourceAttr("windows_registry_key.test", "id", `HKLM:\SOFTWARE\TF_ACC_RegistryKey\Child`),
+				),
+			},
+			{
+				Config:   cfg,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "windows_registry_key.test",
+				ImportState:       true,
+				ImportStateId:     `HKLM:\SOFTWARE\TF_ACC_RegistryKey\Child`,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccWindowsRegistryKey_DeleteRecursive — a key with a child key is
+// destroyed when delete_recursive is set.
+func TestAccWindowsRegistryKey_DeleteRecursive(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "windows_registry_key" "parent" {
+  path             = "HKLM:\\SOFTWARE\\TF_ACC_RegistryKeyTree"
+  delete_recursive = true
+}
+
+resource "windows_registry_key" "child" {
+  path       = "HKLM:\\SOFTWARE\\TF_ACC_RegistryKeyTree\\Child"
+  depends_on = [windows_registry_key.parent]
+}
+`,
+				Check: resource.TestCheckResourceAttr("windows_registry_key.parent", "delete_recursive", "true"),
+			},
+		},
+	})
+}
@@ -0,0 +1,243 @@
+// Package provider — unit tests for the windows_registry_key resource.
+//
+// A fakeRegistryKeyClient is injected into windowsRegistryKeyResource.client,
+// so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeRegistryKeyClient struct {
+	createOut *winclient.RegistryKeyState
+	createErr error
+	readOut   *winclient.RegistryKeyState
+	readErr   error
+	deleteErr error
+
+	lastCreate    winclient.RegistryKeyInput
+	lastReadHive  string
+	lastReadPath  string
+	lastRecursive bool
+	deleteCalled  bool
+}
+
+func (f *fakeRegistryKeyClient) Create(_ context.Context, in winclient.RegistryKeyInput) (*winclient.RegistryKeyState, error) {
+	f.lastCreate = in
+	return f.createOut, f.createErr
+}
+
+func (f *fakeRegistryKeyClient) Read(_ context.Context, hive, p string) (*winclient.RegistryKeyState, error) {
+	f.lastReadHive, f.lastReadPath = hive, p
+	return f.readOut, f.readErr
+}
+
+func (f *fakeRegistryKeyClient) Delete(_ context.Context, _, _ string, recursive bool) error {
+	f.deleteCalled = true
+	f.lastRecursive = recursive
+	return f.deleteErr
+}
+
+func rkObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":               tftypes.String,
+		"path":             tftypes.String,
+		"allow_existing":   tftypes.Bool,
+		"delete_recursive": tftypes.Bool,
+		"exists":           tftypes.Bool,
+	}}
+}
+
+func rkObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id":               tftypes.NewValue(tftypes.String, `HKLM:\SOFTWARE\TF`),
+		"path":             tftypes.NewValue(tftypes.String, `HKLM:\SOFTWARE\TF`),
+		"allow_existing":   tftypes.NewValue(tftypes.Bool, false),
+		"delete_recursive": tftypes.NewValue(tftypes.Bool, false),
+		"exists":           tftypes.NewValue(tftypes.Bool, true),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(rkObjectType(), base)
+}
+
+func rkPlan(overrides map[string]tftypes.Value) tfsdk.Plan {
+	return tfsdk.Plan{Raw: rkObj(overrides), Schema: windowsRegistryKeySchemaDefinition()}
+}
+
+func rkState(overrides map[string]tftypes.Value) tfsdk.State {
+	return tfsdk.State{Raw: rkObj(overrides), Schema: windowsRegistryKeySchemaDefinition()}
+}
+
+func rkEmptyState() tfsdk.State {
+	return tfsdk.State{Schema: windowsRegistryKeySchemaDefinition(), Raw: tftypes.NewValue(rkObjectType(), nil)}
+}
+
+func TestParseRegistryKeyPath(t *testing.T) {
+	ok := map[string][2]string{
+		`HKLM:\SOFTWARE\TF`:                 {"HKLM", `SOFTWARE\TF`},
+		`HKLM\SOFTWARE\TF\`:                 {"HKLM", `SOFTWARE\TF`},
+		`hkcu:\Software\X`:                  {"HKCU", `Software\X`},
+		`HKEY_LOCAL_MACHINE\SOFTWARE\A B`:   {"HKLM", `SOFTWARE\A B`},
+		`HKEY_USERS\.DEFAULT\Software\Test`: {"HKU", `.DEFAULT\Software\Test`},
+	}
+	for in, want := range ok {
+		hive, sub, err := parseRegistryKeyPath(in)
+		if err != nil || hive != want[0] || sub != want[1] {
+			t.Errorf("parseRegistryKeyPath(%q) = %q, %q, %v; want %q, %q", in, hive, sub, err, want[0], want[1])
+		}
+	}
+	for _, in := range []string{"", "HKLM", `HKLM:\`, `HKXX\SOFTWARE`, `HKLM\\SOFTWARE`, `HKLM\SOFTWARE\\X`, `SOFTWARE\X`} {
+		if _, _, err := parseRegistryKeyPath(in); err == nil {
+			t.Errorf("parseRegistryKeyPath(%q): expected an error", in)
+		}
+	}
+}
+
+func TestRegistryKeyPathValidator(t *testing.T) {
+	v := registryKeyPathValidator{}
+	for in, wantErr := range map[string]bool{`HKLM:\SOFTWARE\TF`: false, `HKLM:\`: true} {
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path: path.Root("path"), ConfigValue: types.StringValue(in),
+		}, resp)
+		if resp.Diagnostics.HasError() != wantErr {
+			t.Errorf("%q: HasError = %v, want %v", in, resp.Diagnostics.HasError(), wantErr)
+		}
+	}
+	resp := &validator.StringResponse{}
+	v.ValidateString(context.Background(), validator.StringRequest{ConfigValue: types.StringUnknown()}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Error("unknown value must not be validated")
+	}
+}
+
+func TestRegistryKeySchema(t *testing.T) {
+	s := windowsRegistryKeySchemaDefinition()
+	for _, name := range []string{"id", "path", "allow_existing", "delete_recursive", "exists"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !s.Attributes["path"].IsRequired() || !s.Attributes["exists"].IsComputed() {
+		t.Error("path must be required and exists computed")
+	}
+}
+
+func TestRegistryKeyCreate(t *testing.T) {
+	fake := &fakeRegistryKeyClient{createOut: &winclient.RegistryKeyState{Hive: "HKLM", Path: `SOFTWARE\TF`}}
+	r := &windowsRegistryKeyResource{client: fake}
+
+	resp := &resource.CreateResponse{State: rkEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: rkPlan(map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"exists": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+	})}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if fake.lastCreate.Hive != "HKLM" || fake.lastCreate.Path != `SOFTWARE\TF` || fake.lastCreate.AllowExisting {
+		t.Errorf("Create input = %+v", fake.lastCreate)
+	}
+	var got windowsRegistryKeyModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != `HKLM:\SOFTWARE\TF` || !got.Exists.ValueBool() {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestRegistryKeyCreate_AlreadyExists(t *testing.T) {
+	fake := &fakeRegistryKeyClient{createErr: winclient.NewRegistryKeyError(
+		winclient.RegistryKeyErrorAlreadyExists, `registry key already exists: HKLM\SOFTWARE\TF`, nil, nil)}
+	r := &windowsRegistryKeyResource{client: fake}
+
+	resp := &resource.CreateResponse{State: rkEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: rkPlan(nil)}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "allow_existing") {
+		t.Errorf("diagnostics = %v, want an already-exists error pointing at allow_existing", resp.Diagnostics)
+	}
+
+	fake = &fakeRegistryKeyClient{createOut: &winclient.RegistryKeyState{Hive: "HKLM", Path: `SOFTWARE\TF`, Existed: true}}
+	r = &windowsRegistryKeyResource{client: fake}
+	resp = &resource.CreateResponse{State: rkEmptyState()}
+	r.Create(context.Background(), resource.CreateRequest{Plan: rkPlan(map[string]tftypes.Value{
+		"allow_existing": tftypes.NewValue(tftypes.Bool, true),
+	})}, resp)
+	if resp.Diagnostics.HasError() || !fake.lastCreate.AllowExisting {
+		t.Errorf("allow_existing: %v, input = %+v", resp.Diagnostics, fake.lastCreate)
+	}
+}
+
+func TestRegistryKeyRead_MissingRemovesResource(t *testing.T) {
+	r := &windowsRegistryKeyResource{client: &fakeRegistryKeyClient{}}
+	resp := &resource.ReadResponse{State: rkState(nil)}
+	r.Read(context.Background(), resource.ReadRequest{State: rkState(nil)}, resp)
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("Read of a missing key: diags = %v, state null = %v", resp.Diagnostics, resp.State.Raw.IsNull())
+	}
+}
+
+func TestRegistryKeyDelete(t *testing.T) {
+	fake := &fakeRegistryKeyClient{deleteErr: winclient.NewRegistryKeyError(
+		winclient.RegistryKeyErrorHasSubKeys, "registry key has 2 child key(s)", nil, nil)}
+	r := &windowsRegistryKeyResource{client: fake}
+
+	resp := &resource.DeleteResponse{State: rkState(nil)}
+	r.Delete(context.Background(), resource.DeleteRequest{State: rkState(nil)}, resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "delete_recursive") {
+		t.Errorf("diagnostics = %v, want a has-subkeys error pointing at delete_recursive", resp.Diagnostics)
+	}
+
+	fake = &fakeRegistryKeyClient{}
+	r = &windowsRegistryKeyResource{client: fake}
+	resp = &resource.DeleteResponse{State: rkState(nil)}
+	r.Delete(context.Background(), resource.DeleteRequest{State: rkState(map[string]tftypes.Value{
+		"delete_recursive": tftypes.NewValue(tftypes.Bool, true),
+	})}, resp)
+	if resp.Diagnostics.HasError() || !fake.deleteCalled || !fake.lastRecursive {
+		t.Errorf("recursive delete: %v, called = %v, recursive = %v", resp.Diagnostics, fake.deleteCalled, fake.lastRecursive)
+	}
+}
+
+func TestRegistryKeyImportState(t *testing.T) {
+	fake := &fakeRegistryKeyClient{readOut: &winclient.RegistryKeyState{Hive: "HKLM", Path: `SOFTWARE\TF`}}
+	r := &windowsRegistryKeyResource{client: fake}
+
+	resp := &resource.ImportStateResponse{State: rkEmptyState()}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: `HKEY_LOCAL_MACHINE\SOFTWARE\TF`}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("ImportState: %v", resp.Diagnostics)
+	}
+	if fake.lastReadHive != "HKLM" || fake.lastReadPath != `SOFTWARE\TF` {
+		t.Errorf("Read(%q, %q)", fake.lastReadHive, fake.lastReadPath)
+	}
+	var got windowsRegistryKeyModel
+	resp.State.Get(context.Background(), &got)
+	if got.Path.ValueString() != `HKEY_LOCAL_MACHINE\SOFTWARE\TF` || got.DeleteRecursive.ValueBool() {
+		t.Errorf("imported state = %+v", got)
+	}
+
+	r = &windowsRegistryKeyResource{client: &fakeRegistryKeyClient{}}
+	resp = &resource.ImportStateResponse{State: rkEmptyState()}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: `HKLM:\SOFTWARE\Missing`}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("importing a missing key must fail")
+	}
+	resp = &resource.ImportStateResponse{State: rkEmptyState()}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "not-a-path"}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("a malformed import ID must fail")
+	}
+}
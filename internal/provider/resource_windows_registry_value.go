@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -61,6 +62,7 @@ type windowsRegistryValueModel struct {
 	ValueStrings               types.List   `tfsdk:"value_strings"`
 	ValueBinary                types.String `tfsdk:"value_binary"`
 	ExpandEnvironmentVariables types.Bool   `tfsdk:"expand_environment_variables"`
+	CreateKeyIfMissing         types.Bool   `tfsdk:"create_key_if_missing"`
 }
 
 // ---------------------------------------------------------------------------
@@ -90,13 +92,6 @@ func (r *windowsRegistryValueResource) ConfigValidators(_ context.Context) []res
 // Regex: ^[^\\\x00]+(\\[^\\\x00]+)*$
 var registryPathRegex = regexp.MustCompile(`^[^\\\x00]+(\\[^\\\x00]+)*$`)
 
-// knownHiveAbbreviations used to detect hive prefix in path (EC-6b).
-var knownHiveAbbreviations = []string{
-	"HKLM", "HKCU", "HKCR", "HKU", "HKCC",
-	"HKEY_LOCAL_MACHINE", "HKEY_CURRENT_USER", "HKEY_CLASSES_ROOT",
-	"HKEY_USERS", "HKEY_CURRENT_CONFIG",
-}
-
 // windowsRegistryValueSchemaDefinition returns the schema.Schema for windows_registry_value.
 func windowsRegistryValueSchemaDefinition() schema.Schema {
 	return schema.Schema{
@@ -172,7 +167,12 @@ func windowsRegistryValueSchemaDefinition() schema.Schema {
 			"value_strings": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
-				Description: "Multi-string value for REG_MULTI_SZ. Empty list [] is valid (EC-10).",
+				Validators: []validator.List{
+					// An empty element ends a REG_MULTI_SZ, so it would not
+					// survive a read back.
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				Description: "Multi-string value for REG_MULTI_SZ. Empty list [] is valid (EC-10); empty elements are not.",
 			},
 			"value_binary": schema.StringAttribute{
 				Optional: true,
@@ -191,6 +191,12 @@ func windowsRegistryValueSchemaDefinition() schema.Schema {
 				Default:     booldefault.StaticBool(false),
 				Description: "When true, Read returns expanded REG_EXPAND_SZ values. Only valid with type=REG_EXPAND_SZ (CV-7).",
 			},
+			"create_key_if_missing": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "When true (default), a missing parent key is created on write. When false, the write fails if the key does not exist, so a typo in path cannot silently create a stray key.",
+			},
 		},
 	}
 }
@@ -210,12 +216,20 @@ func (hiveEnumValidator) ValidateString(_ context.Context, req validator.StringR
 	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
 		return
 	}
-	switch strings.ToUpper(req.ConfigValue.ValueString()) {
+	val := req.ConfigValue.ValueString()
+	upper := strings.ToUpper(val)
+	switch upper {
 	case "HKLM", "HKCU", "HKCR", "HKU", "HKCC":
-	default:
+		return
+	}
+	// Suggest the abbreviation for the long name or the PowerShell drive form.
+	if abbr, ok := registryKeyHives[strings.TrimSuffix(upper, ":")]; ok {
 		resp.Diagnostics.AddAttributeError(req.Path, "Invalid registry hive",
-			fmt.Sprintf("hive must be one of HKLM, HKCU, HKCR, HKU, HKCC (case-insensitive); got %q (EC-6a).", req.ConfigValue.ValueString()))
+			fmt.Sprintf("hive %q is not accepted; use %q instead (EC-6a).", val, abbr))
+		return
 	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid registry hive",
+		fmt.Sprintf("hive must be one of HKLM, HKCU, HKCR, HKU, HKCC (case-insensitive); got %q (EC-6a).", val))
 }
 
 // hiveNormalizePlanModifier normalises the hive to uppercase in the plan (ADR-RV-6).
@@ -249,13 +263,11 @@ func (registryPathValidator) ValidateString(_ context.Context, req validator.Str
 		return
 	}
 	val := req.ConfigValue.ValueString()
-	upper := strings.ToUpper(val)
-	for _, hive := range knownHiveAbbreviations {
-		if upper == hive || strings.HasPrefix(upper, hive+"\\") {
-			resp.Diagnostics.AddAttributeError(req.Path, "Invalid registry path — hive prefix detected",
-				fmt.Sprintf("path %q must not include the hive prefix (EC-6b). Use the hive attribute instead.", val))
-			return
-		}
+	first, rest, _ := strings.Cut(val, "\\")
+	if abbr, ok := registryKeyHives[strings.TrimSuffix(strings.ToUpper(first), ":")]; ok {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid registry path — hive prefix detected",
+			fmt.Sprintf("path %q must not include the hive prefix (EC-6b). Set hive = %q and path = %q instead.", val, abbr, rest))
+		return
 	}
 	if !registryPathRegex.MatchString(val) {
 		resp.Diagnostics.AddAttributeError(req.Path, "Invalid registry path",
@@ -651,6 +663,7 @@ func (r *windowsRegistryValueResource) ImportState(ctx context.Context, req reso
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
 	// Defaults for computed/optional fields so Read can populate them.
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("expand_environment_variables"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("create_key_if_missing"), true)...)
 }
 
 // ---------------------------------------------------------------------------
@@ -670,6 +683,7 @@ func rvModelToInput(m *windowsRegistryValueModel) (winclient.RegistryValueInput,
 		Name:                       m.Name.ValueString(),
 		Kind:                       winclient.RegistryValueKind(m.Type.ValueString()),
 		ExpandEnvironmentVariables: m.ExpandEnvironmentVariables.ValueBool(),
+		FailIfKeyMissing:           !m.CreateKeyIfMissing.IsNull() && !m.CreateKeyIfMissing.ValueBool(),
 	}
 
 	switch input.Kind {
@@ -762,6 +776,11 @@ func addRVDiag(diags *diag.Diagnostics, op string, err error) {
 			)
 		case winclient.RegistryValueErrorInvalidInput:
 			diags.AddError(fmt.Sprintf("Registry value %s failed: invalid input", op), rve.Message)
+		case winclient.RegistryValueErrorNotFound:
+			diags.AddError(
+				fmt.Sprintf("Registry value %s failed: key not found", op),
+				rve.Message+" — create the key first or set create_key_if_missing = true.",
+			)
 		default:
 			diags.AddError(fmt.Sprintf("Registry value %s failed", op), rve.Error())
 		}
@@ -93,6 +93,7 @@ func registryValueObjectType() tftypes.Object {
 		"value_strings":                tftypes.List{ElementType: tftypes.String},
 		"value_binary":                 tftypes.String,
 		"expand_environment_variables": tftypes.Bool,
+		"create_key_if_missing":        tftypes.Bool,
 	}}
 }
 
@@ -108,6 +109,7 @@ func rvObjBase() map[string]tftypes.Value {
 		"value_strings":                tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
 		"value_binary":                 tftypes.NewValue(tftypes.String, nil),
 		"expand_environment_variables": tftypes.NewValue(tftypes.Bool, false),
+		"create_key_if_missing":        tftypes.NewValue(tftypes.Bool, true),
 	}
 }
 
@@ -176,7 +178,7 @@ func TestRegistryValueSchema_HasRequiredAttributes(t *testing.T) {
 	for _, k := range []string{
 		"id", "hive", "path", "name", "type",
 		"value_string", "value_strings", "value_binary",
-		"expand_environment_variables",
+		"expand_environment_variables", "create_key_if_missing",
 	} {
 		if _, ok := s.Attributes[k]; !ok {
 			t.Errorf("schema missing attribute %q", k)
@@ -235,6 +237,22 @@ func TestHiveEnumValidator_InvalidHive(t *testing.T) {
 	}
 }
 
+func TestHiveEnumValidator_SuggestsAbbreviation(t *testing.T) {
+	v := hiveEnumValidator{}
+	for hive, want := range map[string]string{
+		"HKEY_LOCAL_MACHINE": `use "HKLM"`,
+		"hkey_users":         `use "HKU"`,
+		"HKCU:":              `use "HKCU"`,
+	} {
+		req := validator.StringRequest{Path: path.Root("hive"), ConfigValue: types.StringValue(hive)}
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), req, resp)
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), want) {
+			t.Errorf("hive %q: diags = %v, want %s", hive, resp.Diagnostics, want)
+		}
+	}
+}
+
 func TestHiveEnumValidator_NullUnknown(t *testing.T) {
 	v := hiveEnumValidator{}
 	// Null — no error
@@ -291,6 +309,8 @@ func TestRegistryPathValidator_HivePrefix(t *testing.T) {
 		`hklm\SOFTWARE`,
 		`HKEY_LOCAL_MACHINE\SOFTWARE`,
 		`HKCU\SOFTWARE`,
+		`HKLM:\SOFTWARE\MyApp`,
+		`hkcu:`,
 	} {
 		req := validator.StringRequest{Path: path.Root("path"), ConfigValue: types.StringValue(p)}
 		resp := &validator.StringResponse{}
@@ -301,6 +321,17 @@ func TestRegistryPathValidator_HivePrefix(t *testing.T) {
 	}
 }
 
+func TestRegistryPathValidator_HivePrefixSuggestion(t *testing.T) {
+	v := registryPathValidator{}
+	req := validator.StringRequest{Path: path.Root("path"), ConfigValue: types.StringValue(`HKEY_LOCAL_MACHINE\SOFTWARE\MyApp`)}
+	resp := &validator.StringResponse{}
+	v.ValidateString(context.Background(), req, resp)
+	want := `Set hive = "HKLM" and path = "SOFTWARE\\MyApp" instead`
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), want) {
+		t.Errorf("diags = %v, want %s", resp.Diagnostics, want)
+	}
+}
+
 func TestRegistryPathValidator_InvalidPaths(t *testing.T) {
 	v := registryPathValidator{}
 	// Leading backslash, trailing backslash, empty, double backslash
@@ -640,6 +671,34 @@ func TestCV4_REG_MULTI_SZ_EmptyList(t *testing.T) {
 	}
 }
 
+func TestRegistryValueSchema_ValueStringsRejectsEmptyElement(t *testing.T) {
+	s := windowsRegistryValueSchemaDefinition()
+	a := s.Attributes["value_strings"].(interface{ ListValidators() []validator.List })
+	for _, tc := range []struct {
+		elems   []string
+		wantErr bool
+	}{
+		{[]string{"a", "b"}, false},
+		{[]string{}, false},
+		{[]string{"a", ""}, true},
+	} {
+		vals := make([]attr.Value, len(tc.elems))
+		for i, e := range tc.elems {
+			vals[i] = types.StringValue(e)
+		}
+		resp := &validator.ListResponse{}
+		for _, v := range a.ListValidators() {
+			v.ValidateList(context.Background(), validator.ListRequest{
+				Path:        path.Root("value_strings"),
+				ConfigValue: types.ListValueMust(types.StringType, vals),
+			}, resp)
+		}
+		if resp.Diagnostics.HasError() != tc.wantErr {
+			t.Errorf("%q: HasError = %v, want %v", tc.elems, resp.Diagnostics.HasError(), tc.wantErr)
+		}
+	}
+}
+
 func TestCV4_REG_MULTI_SZ_MissingValueStrings(t *testing.T) {
 	v := registryValueTypeDataValidator{}
 	req := buildRVValidateReq(t, map[string]tftypes.Value{
@@ -1322,6 +1381,36 @@ func TestRegistryValueCreate_HappyPath_REG_SZ(t *testing.T) {
 	}
 }
 
+func TestRegistryValueCreate_CreateKeyIfMissingFalse(t *testing.T) {
+	fake := &fakeRegistryValueClient{
+		setErr: winclient.NewRegistryValueError(winclient.RegistryValueErrorNotFound,
+			`registry key does not exist: HKLM\SOFTWARE\MyApp`, nil, nil),
+	}
+	r := &windowsRegistryValueResource{client: fake}
+	s := windowsRegistryValueSchemaDefinition()
+
+	rawPlan := rvObj(map[string]tftypes.Value{"create_key_if_missing": tftypes.NewValue(tftypes.Bool, false)})
+	req := resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: rawPlan}}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	r.Create(context.Background(), req, resp)
+
+	if !fake.lastSetInput.FailIfKeyMissing {
+		t.Error("create_key_if_missing=false must set FailIfKeyMissing")
+	}
+	if !resp.Diagnostics.HasError() || !strings.Contains(rvDiagSummaries(resp.Diagnostics)[0], "create_key_if_missing") {
+		t.Errorf("expected a key-not-found diag, got %v", rvDiagSummaries(resp.Diagnostics))
+	}
+
+	// The default keeps creating missing keys.
+	fake = &fakeRegistryValueClient{setOut: okRVState(winclient.RegistryValueKindString)}
+	r = &windowsRegistryValueResource{client: fake}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: rvObj(nil)}},
+		&resource.CreateResponse{State: tfsdk.State{Schema: s}})
+	if fake.lastSetInput.FailIfKeyMissing {
+		t.Error("default create_key_if_missing must not set FailIfKeyMissing")
+	}
+}
+
 func TestRegistryValueCreate_EC3_TypeConflict(t *testing.T) {
 	fake := &fakeRegistryValueClient{
 		setErr: winclient.NewRegistryValueError(winclient.RegistryValueErrorTypeConflict,
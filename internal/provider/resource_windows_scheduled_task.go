@@ -59,6 +59,9 @@ func NewWindowsScheduledTaskResource() resource.Resource { return &windowsSchedu
 // windowsScheduledTaskResource is the TPF resource for windows_scheduled_task.
 type windowsScheduledTaskResource struct {
 	stClient winclient.ScheduledTaskClient
+	// defaultTimeout is the provider default_command_timeout (0 = unset,
+	// stDefaultTimeout applies).
+	defaultTimeout time.Duration
 }
 
 // ---------------------------------------------------------------------------
@@ -638,6 +641,7 @@ func (r *windowsScheduledTaskResource) Configure(_ context.Context, req resource
 		return
 	}
 	r.stClient = winclient.NewScheduledTaskClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
 }
 
 // ---------------------------------------------------------------------------
@@ -652,7 +656,7 @@ func (r *windowsScheduledTaskResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	createTimeout, dt := plan.Timeouts.Create(ctx, stDefaultTimeout)
+	createTimeout, dt := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, stDefaultTimeout))
 	resp.Diagnostics.Append(dt...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -732,7 +736,7 @@ func (r *windowsScheduledTaskResource) Update(ctx context.Context, req resource.
 		return
 	}
 
-	updateTimeout, dt := plan.Timeouts.Update(ctx, stDefaultTimeout)
+	updateTimeout, dt := plan.Timeouts.Update(ctx, operationTimeout(r.defaultTimeout, stDefaultTimeout))
 	resp.Diagnostics.Append(dt...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -800,7 +804,7 @@ func (r *windowsScheduledTaskResource) Delete(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	deleteTimeout, dt := state.Timeouts.Delete(ctx, stDefaultTimeout)
+	deleteTimeout, dt := state.Timeouts.Delete(ctx, operationTimeout(r.defaultTimeout, stDefaultTimeout))
 	resp.Diagnostics.Append(dt...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -13,7 +13,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -21,11 +24,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
@@ -51,6 +56,11 @@ type windowsServiceResource struct {
 // receive a service_password (EC-11). Case-insensitive.
 var builtinAccountRe = regexp.MustCompile(`(?i)^(LocalSystem$|NT AUTHORITY\\)`)
 
+// passwordlessAccountRe matches non-built-in accounts that still take no
+// password: virtual service accounts (NT SERVICE\<svc>) and (group) managed
+// service accounts, whose names end in "$" (EC-15). Case-insensitive.
+var passwordlessAccountRe = regexp.MustCompile(`(?i)^NT SERVICE\\|\$$`)
+
 // windowsServiceModel is the Terraform state/plan model for windows_service.
 //
 // service_password is included (Sensitive: true) but is never populated from a
@@ -62,10 +72,17 @@ type windowsServiceModel struct {
 	DisplayName    types.String `tfsdk:"display_name"`
 	Description    types.String `tfsdk:"description"`
 	BinaryPath     types.String `tfsdk:"binary_path"`
+	Arguments      types.String `tfsdk:"arguments"`
 	StartType      types.String `tfsdk:"start_type"`
 	Status         types.String `tfsdk:"status"`
 	CurrentStatus  types.String `tfsdk:"current_status"`
 	ServiceAccount types.String `tfsdk:"service_account"`
+	// StateTimeoutSeconds bounds the wait for status (and, on start, for each
+	// stopped dependency). Null uses the client-timeout default.
+	StateTimeoutSeconds types.Int64 `tfsdk:"state_timeout_seconds"`
+	// ReadRetries bounds the extra attempts of a refresh that fails with a
+	// transient error. Null means no retries.
+	ReadRetries types.Int64 `tfsdk:"read_retries"`
 	// ServicePassword is the legacy state-persisted password (Sensitive).
 	// DEPRECATED in favour of ServicePasswordWO (Tier 3, TPF v1.14+).
 	ServicePassword types.String `tfsdk:"service_password"`
@@ -74,6 +91,42 @@ type windowsServiceModel struct {
 	// resp.State.Set(). Mutually exclusive with ServicePassword.
 	ServicePasswordWO types.String `tfsdk:"service_password_wo"`
 	Dependencies      types.List   `tfsdk:"dependencies"`
+	SidType           types.String `tfsdk:"sid_type"`
+	// Recovery is the failure-action configuration. Null means unmanaged.
+	Recovery types.Object `tfsdk:"recovery"`
+}
+
+// windowsServiceRecoveryModel is the recovery nested attribute.
+type windowsServiceRecoveryModel struct {
+	FirstAction         types.String `tfsdk:"first_action"`
+	SecondAction        types.String `tfsdk:"second_action"`
+	SubsequentActions   types.String `tfsdk:"subsequent_actions"`
+	ResetPeriodSeconds  types.Int64  `tfsdk:"reset_period_seconds"`
+	RestartDelaySeconds types.Int64  `tfsdk:"restart_delay_seconds"`
+	Command             types.String `tfsdk:"command"`
+}
+
+// serviceRecoveryAttrTypes is the attr.Type map of the recovery object.
+var serviceRecoveryAttrTypes = map[string]attr.Type{
+	"first_action":          types.StringType,
+	"second_action":         types.StringType,
+	"subsequent_actions":    types.StringType,
+	"reset_period_seconds":  types.Int64Type,
+	"restart_delay_seconds": types.Int64Type,
+	"command":               types.StringType,
+}
+
+// serviceRecoveryActionAttribute is the schema of one recovery action.
+func serviceRecoveryActionAttribute(which string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString("None"),
+		MarkdownDescription: "Action on the " + which + ": `None`, `Restart` (the service), `Run` (`command`) or `Reboot` (the host). Default: `None`.",
+		Validators: []validator.String{
+			stringvalidator.OneOf("None", "Restart", "Run", "Reboot"),
+		},
+	}
 }
 
 // Metadata sets the resource type name.
@@ -118,7 +171,10 @@ func windowsServiceSchemaDefinition() schema.Schema {
 			"display_name": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "Human-readable display name shown in services.msc. Defaults to name if omitted.",
+				Description: "Human-readable display name shown in services.msc. Defaults to name if omitted; when unset, the value read from the host is kept and never re-applied.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"description": schema.StringAttribute{
 				Optional:    true,
@@ -126,14 +182,28 @@ func windowsServiceSchemaDefinition() schema.Schema {
 			},
 			"binary_path": schema.StringAttribute{
 				Required:    true,
-				Description: "Full path to the service executable including any arguments. ForceNew.",
+				Description: "Full path to the service executable including any arguments. ForceNew. When arguments is set, this must be the bare executable path. Outer double-quotes are ignored when comparing with the registered path.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceUnlessQuotingOnly,
+						"Changing binary_path replaces the service, unless only its outer double-quotes change.",
+						"Changing `binary_path` replaces the service, unless only its outer double-quotes change."),
 				},
 				Validators: []validator.String{
 					stringvalidator.LengthBetween(1, 32767),
 				},
 			},
+			"arguments": schema.StringAttribute{
+				Optional: true,
+				Description: "Start-up arguments appended to binary_path. When set, binary_path must be the bare " +
+					"executable: it is double-quoted and joined with these arguments, so paths and arguments " +
+					"containing spaces are passed intact. Read splits the registered command line back into " +
+					"binary_path and arguments for drift detection. Updated in place.",
+				MarkdownDescription: "Start-up arguments appended to `binary_path`. When set, `binary_path` must be the bare " +
+					"executable: it is double-quoted and joined with these arguments, so paths and arguments " +
+					"containing spaces are passed intact. Read splits the registered command line back into " +
+					"`binary_path` and `arguments` for drift detection. Updated in place. When omitted, " +
+					"`binary_path` is registered verbatim (legacy behaviour).",
+			},
 			"start_type": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -150,6 +220,16 @@ func windowsServiceSchemaDefinition() schema.Schema {
 					stringvalidator.OneOf("Running", "Stopped", "Paused"),
 				},
 			},
+			"state_timeout_seconds": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Seconds to wait for the service to reach `status`. When `status = \"Running\"`, " +
+					"services it depends on that are not running are started first and each gets the same bound. " +
+					"Defaults to the provider `timeout` (30 when that is under 10). Must be between 1 and 3600.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 3600),
+				},
+			},
+			"read_retries": readRetriesAttribute("service"),
 			"current_status": schema.StringAttribute{
 				Computed:    true,
 				Description: "Observed runtime state from the last Read (Running, Stopped, Paused).",
@@ -157,7 +237,10 @@ func windowsServiceSchemaDefinition() schema.Schema {
 			"service_account": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "Account under which the service runs. Defaults to LocalSystem.",
+				Description: "Account under which the service runs. Defaults to LocalSystem; when unset, the account read from the host is kept and never re-applied.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"service_password": schema.StringAttribute{
 				Optional:           true,
@@ -185,6 +268,53 @@ func windowsServiceSchemaDefinition() schema.Schema {
 				Computed:    true,
 				Description: "Ordered list of short service names this service depends on.",
 			},
+			"sid_type": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				MarkdownDescription: "Service SID type, as set by `sc.exe sidtype`. One of: `None`, `Unrestricted`, " +
+					"`Restricted`. With `Unrestricted` or `Restricted` the service gets its own `NT SERVICE\\<name>` " +
+					"SID that can be granted ACLs. When unset, the value read from the host is kept and never re-applied.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("None", "Unrestricted", "Restricted"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"recovery": schema.SingleNestedAttribute{
+				Optional: true,
+				MarkdownDescription: "Failure actions: what the SCM does when the service process ends unexpectedly " +
+					"(the Recovery tab of services.msc). Applied with `sc.exe failure` and read back with " +
+					"`sc.exe qfailure`. When omitted, the failure actions on the host are left untouched; removing " +
+					"the block clears them.",
+				Attributes: map[string]schema.Attribute{
+					"first_action":       serviceRecoveryActionAttribute("first failure"),
+					"second_action":      serviceRecoveryActionAttribute("second failure"),
+					"subsequent_actions": serviceRecoveryActionAttribute("third and later failures"),
+					"reset_period_seconds": schema.Int64Attribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             int64default.StaticInt64(86400),
+						MarkdownDescription: "Seconds without a failure after which the failure count is reset. Default: `86400` (one day).",
+						Validators: []validator.Int64{
+							int64validator.Between(0, 4294967295),
+						},
+					},
+					"restart_delay_seconds": schema.Int64Attribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             int64default.StaticInt64(60),
+						MarkdownDescription: "Seconds to wait before each action runs. Default: `60`.",
+						Validators: []validator.Int64{
+							int64validator.Between(0, 4294967),
+						},
+					},
+					"command": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Command line run by a `Run` action. Required when any action is `Run`.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -216,6 +346,7 @@ func (r *windowsServiceResource) Configure(_ context.Context, req resource.Confi
 //     configuration block. Without this, an operator could silently leak
 //     plaintext via the legacy field while believing they were on the
 //     WriteOnly path.
+//   - serviceRecoveryValidator: a Run recovery action requires a command.
 func (r *windowsServiceResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
 	return []resource.ConfigValidator{
 		serviceAccountPasswordValidator{},
@@ -223,6 +354,7 @@ func (r *windowsServiceResource) ConfigValidators(_ context.Context) []resource.
 			path.MatchRoot("service_password"),
 			path.MatchRoot("service_password_wo"),
 		),
+		serviceRecoveryValidator{},
 	}
 }
 
@@ -258,6 +390,8 @@ func (r *windowsServiceResource) Create(ctx context.Context, req resource.Create
 
 	deps, diags := listToStrings(ctx, plan.Dependencies)
 	resp.Diagnostics.Append(diags...)
+	recovery, diags := serviceRecoveryChange(ctx, plan.Recovery, types.ObjectNull(serviceRecoveryAttrTypes))
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -265,6 +399,8 @@ func (r *windowsServiceResource) Create(ctx context.Context, req resource.Create
 	input := winclient.ServiceInput{
 		Name:            plan.Name.ValueString(),
 		BinaryPath:      plan.BinaryPath.ValueString(),
+		Arguments:       serviceArguments(plan.Arguments, types.StringNull()),
+		SidType:         serviceSidTypeChange(plan.SidType, types.StringNull()),
 		DisplayName:     plan.DisplayName.ValueString(),
 		Description:     plan.Description.ValueString(),
 		StartType:       plan.StartType.ValueString(),
@@ -272,6 +408,8 @@ func (r *windowsServiceResource) Create(ctx context.Context, req resource.Create
 		ServiceAccount:  plan.ServiceAccount.ValueString(),
 		ServicePassword: effectiveServicePassword(plan),
 		Dependencies:    deps,
+		StateTimeout:    serviceStateTimeout(plan.StateTimeoutSeconds),
+		Recovery:        recovery,
 	}
 
 	state, err := r.svc.Create(ctx, input)
@@ -300,7 +438,7 @@ func (r *windowsServiceResource) Read(ctx context.Context, req resource.ReadRequ
 
 	tflog.Debug(ctx, "windows_service Read", map[string]interface{}{"name": name})
 
-	obs, err := r.svc.Read(ctx, name)
+	obs, err := r.svc.Read(withReadRetries(ctx, state.ReadRetries), name)
 	if err != nil {
 		addServiceDiag(&resp.Diagnostics, "Read windows_service failed", err)
 		return
@@ -339,6 +477,12 @@ func (r *windowsServiceResource) Update(ctx context.Context, req resource.Update
 		deps = d
 	}
 
+	recovery, diags := serviceRecoveryChange(ctx, plan.Recovery, prior.Recovery)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	name := plan.Name.ValueString()
 	if name == "" {
 		name = prior.Name.ValueString()
@@ -354,13 +498,18 @@ func (r *windowsServiceResource) Update(ctx context.Context, req resource.Update
 
 	input := winclient.ServiceInput{
 		Name:            name,
-		DisplayName:     plan.DisplayName.ValueString(),
+		BinaryPath:      plan.BinaryPath.ValueString(),
+		Arguments:       serviceArguments(plan.Arguments, prior.Arguments),
+		DisplayName:     serviceDisplayNameChange(plan.DisplayName, prior.DisplayName),
+		SidType:         serviceSidTypeChange(plan.SidType, prior.SidType),
 		Description:     plan.Description.ValueString(),
 		StartType:       plan.StartType.ValueString(),
 		DesiredStatus:   plan.Status.ValueString(),
-		ServiceAccount:  plan.ServiceAccount.ValueString(),
+		ServiceAccount:  serviceAccountChange(plan, prior),
 		ServicePassword: effectiveServicePassword(plan),
 		Dependencies:    deps,
+		StateTimeout:    serviceStateTimeout(plan.StateTimeoutSeconds),
+		Recovery:        recovery,
 	}
 
 	state, err := r.svc.Update(ctx, name, input)
@@ -410,6 +559,153 @@ func listToStrings(ctx context.Context, list types.List) ([]string, diagsType) {
 // framework diagnostics type avoids importing it at every helper site.
 type diagsType = diag.Diagnostics
 
+// unquotedServiceBinaryPath strips a symmetric pair of outer double-quotes
+// from a binary_path value. A value with inner quotes is a full command line
+// and is returned unchanged.
+func unquotedServiceBinaryPath(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' && !strings.Contains(v[1:len(v)-1], `"`) {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// requiresReplaceUnlessQuotingOnly replaces the service when binary_path
+// changes, except when the change only adds or removes outer quotes: the
+// registered command line is the same.
+func requiresReplaceUnlessQuotingOnly(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = unquotedServiceBinaryPath(req.PlanValue.ValueString()) != unquotedServiceBinaryPath(req.StateValue.ValueString())
+}
+
+// serviceArguments maps the arguments attribute onto ServiceInput.Arguments.
+// It returns nil (command line not managed) only when arguments is null in
+// both the plan and the prior state; removing arguments from the config
+// yields a pointer to "" so the command line is reset to the executable.
+func serviceArguments(planned, prior types.String) *string {
+	if planned.IsNull() || planned.IsUnknown() {
+		if prior.IsNull() {
+			return nil
+		}
+		empty := ""
+		return &empty
+	}
+	v := planned.ValueString()
+	return &v
+}
+
+// serviceDisplayNameChange returns the display name Update should apply, or
+// "" to leave it untouched. display_name is Optional+Computed, so an unset
+// value plans as the prior (read-back) value or as unknown; only a known
+// value that differs from state is a real change.
+func serviceDisplayNameChange(planned, prior types.String) string {
+	if planned.IsNull() || planned.IsUnknown() || planned.Equal(prior) {
+		return ""
+	}
+	return planned.ValueString()
+}
+
+// serviceSidTypeChange returns the SID type Update should apply, or "" to
+// leave it untouched. Like display_name, an unset sid_type plans as the prior
+// read-back value or as unknown, so only a known value that differs counts.
+func serviceSidTypeChange(planned, prior types.String) string {
+	if planned.IsNull() || planned.IsUnknown() || planned.Equal(prior) {
+		return ""
+	}
+	return planned.ValueString()
+}
+
+// serviceAccountChange returns the logon account Update should apply, or ""
+// to leave it untouched. service_account is Optional+Computed, so an unset
+// value plans as the prior read-back value (e.g. LocalSystem) or as unknown;
+// re-applying it would issue sc.exe obj= / Set-Service -Credential with no
+// password. A configured account is applied when it differs from state, or
+// when a password is being sent (rotation needs the account alongside it).
+func serviceAccountChange(plan, prior windowsServiceModel) string {
+	planned := plan.ServiceAccount
+	if planned.IsNull() || planned.IsUnknown() || planned.ValueString() == "" {
+		return ""
+	}
+	if effectiveServicePassword(plan) != "" || !strings.EqualFold(planned.ValueString(), prior.ServiceAccount.ValueString()) {
+		return planned.ValueString()
+	}
+	return ""
+}
+
+// serviceRecoveryChange returns the failure actions Create/Update should
+// apply, or nil to leave them untouched: recovery is unset in both the plan
+// and the prior state, unknown, or unchanged. Removing recovery from the
+// configuration yields all-None actions, which clears them.
+func serviceRecoveryChange(ctx context.Context, planned, prior types.Object) (*winclient.ServiceRecovery, diagsType) {
+	if planned.IsUnknown() {
+		return nil, nil
+	}
+	if planned.IsNull() {
+		if prior.IsNull() || prior.IsUnknown() {
+			return nil, nil
+		}
+		return &winclient.ServiceRecovery{FirstAction: "None", SecondAction: "None", SubsequentActions: "None"}, nil
+	}
+	if planned.Equal(prior) {
+		return nil, nil
+	}
+	var m windowsServiceRecoveryModel
+	diags := planned.As(ctx, &m, basetypes.ObjectAsOptions{})
+	return &winclient.ServiceRecovery{
+		FirstAction:         m.FirstAction.ValueString(),
+		SecondAction:        m.SecondAction.ValueString(),
+		SubsequentActions:   m.SubsequentActions.ValueString(),
+		ResetPeriodSeconds:  m.ResetPeriodSeconds.ValueInt64(),
+		RestartDelaySeconds: m.RestartDelaySeconds.ValueInt64(),
+		Command:             m.Command.ValueString(),
+	}, diags
+}
+
+// serviceRecoveryObject projects the observed failure actions onto the
+// recovery attribute. It stays null when unmanaged (null prior), and keeps
+// the prior value when qfailure could not be read. With no action configured
+// the SCM has no meaningful delay or reset period, so those keep their prior
+// values, and an empty command stays null when it was not configured.
+func serviceRecoveryObject(r *winclient.ServiceRecovery, prior types.Object) types.Object {
+	if prior.IsNull() {
+		return types.ObjectNull(serviceRecoveryAttrTypes)
+	}
+	if r == nil {
+		if prior.IsUnknown() {
+			return types.ObjectNull(serviceRecoveryAttrTypes)
+		}
+		return prior
+	}
+	attrs := map[string]attr.Value{
+		"first_action":          types.StringValue(r.FirstAction),
+		"second_action":         types.StringValue(r.SecondAction),
+		"subsequent_actions":    types.StringValue(r.SubsequentActions),
+		"reset_period_seconds":  types.Int64Value(r.ResetPeriodSeconds),
+		"restart_delay_seconds": types.Int64Value(r.RestartDelaySeconds),
+		"command":               types.StringValue(r.Command),
+	}
+	pa := prior.Attributes()
+	noActions := r.FirstAction == "None" && r.SecondAction == "None" && r.SubsequentActions == "None"
+	for _, k := range []string{"reset_period_seconds", "restart_delay_seconds"} {
+		if v, ok := pa[k]; ok && noActions && !v.IsNull() && !v.IsUnknown() {
+			attrs[k] = v
+		}
+	}
+	if v, ok := pa["command"]; r.Command == "" && (!ok || v.IsNull() || v.IsUnknown()) {
+		attrs["command"] = types.StringNull()
+	}
+	obj, _ := types.ObjectValue(serviceRecoveryAttrTypes, attrs)
+	return obj
+}
+
+// serviceStateTimeout converts state_timeout_seconds to the winclient wait;
+// null or unknown yields 0 (client default).
+func serviceStateTimeout(v types.Int64) time.Duration {
+	if v.IsNull() || v.IsUnknown() {
+		return 0
+	}
+	return time.Duration(v.ValueInt64()) * time.Second
+}
+
 // modelFromState projects an observed ServiceState onto a windowsServiceModel,
 // preserving the desired-state fields (status, service_password) from prior.
 func modelFromState(s *winclient.ServiceState, prior windowsServiceModel) windowsServiceModel {
@@ -423,6 +719,22 @@ func modelFromState(s *winclient.ServiceState, prior windowsServiceModel) window
 		ServiceAccount: types.StringValue(s.ServiceAccount),
 	}
 
+	// arguments: when unmanaged (null), binary_path keeps the whole command
+	// line as before; otherwise expose the split executable and arguments.
+	if prior.Arguments.IsNull() {
+		out.Arguments = types.StringNull()
+	} else {
+		out.BinaryPath = types.StringValue(s.Executable)
+		out.Arguments = types.StringValue(s.Arguments)
+	}
+
+	// binary_path: keep the prior spelling when it only differs from the
+	// observed path by outer quotes (EC-14 strips them on read).
+	if !prior.BinaryPath.IsNull() && !prior.BinaryPath.IsUnknown() &&
+		unquotedServiceBinaryPath(prior.BinaryPath.ValueString()) == unquotedServiceBinaryPath(out.BinaryPath.ValueString()) {
+		out.BinaryPath = prior.BinaryPath
+	}
+
 	// description: preserve null-ness if the prior value was null AND Windows
 	// returns empty (avoids spurious "" <-> null diffs).
 	if prior.Description.IsNull() && s.Description == "" {
@@ -431,8 +743,11 @@ func modelFromState(s *winclient.ServiceState, prior windowsServiceModel) window
 		out.Description = types.StringValue(s.Description)
 	}
 
-	// status is desired state (never observed).
+	// status, state_timeout_seconds and read_retries are desired state
+	// (never observed).
 	out.Status = prior.Status
+	out.StateTimeoutSeconds = prior.StateTimeoutSeconds
+	out.ReadRetries = prior.ReadRetries
 
 	// service_password is never read from Windows (SS6). Carry the prior
 	// state value through unchanged on the legacy attribute.
@@ -451,6 +766,17 @@ func modelFromState(s *winclient.ServiceState, prior windowsServiceModel) window
 	}
 	depList, _ := types.ListValue(types.StringType, depVals)
 	out.Dependencies = depList
+
+	// sid_type: keep the prior value when qsidtype could not be read.
+	out.SidType = types.StringValue(s.SidType)
+	if s.SidType == "" {
+		out.SidType = prior.SidType
+		if out.SidType.IsUnknown() {
+			out.SidType = types.StringNull()
+		}
+	}
+
+	out.Recovery = serviceRecoveryObject(s.Recovery, prior.Recovery)
 	return out
 }
 
@@ -486,18 +812,20 @@ func addServiceDiag(diags *diag.Diagnostics, summary string, err error) {
 //   - EC-4: service_password requires a non-null, non-empty service_account.
 //   - EC-11: service_password must not be paired with a built-in account
 //     (LocalSystem, NT AUTHORITY\*).
+//   - EC-15: a configured non-built-in service_account requires a password,
+//     except virtual (NT SERVICE\*) and managed ($-suffixed) accounts.
 type serviceAccountPasswordValidator struct{}
 
 var _ resource.ConfigValidator = serviceAccountPasswordValidator{}
 
 // Description returns a plain-text description.
 func (v serviceAccountPasswordValidator) Description(_ context.Context) string {
-	return "service_password requires a non-empty, non-built-in service_account (EC-4, EC-11)."
+	return "service_password requires a non-empty, non-built-in service_account, and a non-built-in service_account requires a password (EC-4, EC-11, EC-15)."
 }
 
 // MarkdownDescription returns a Markdown description.
 func (v serviceAccountPasswordValidator) MarkdownDescription(_ context.Context) string {
-	return "`service_password` requires a non-empty, non-built-in `service_account` (EC-4, EC-11)."
+	return "`service_password` requires a non-empty, non-built-in `service_account`, and a non-built-in `service_account` requires a password (EC-4, EC-11, EC-15)."
 }
 
 // ValidateResource applies the rules at plan time.
@@ -517,6 +845,22 @@ func (v serviceAccountPasswordValidator) ValidateResource(ctx context.Context, r
 
 	pwAttr, set := credentialAttrSet(data)
 	if !set {
+		// EC-15: an account that needs a password but has none would make
+		// every Create/Update fail inside the SCM (error 1069 at start).
+		// An unknown password (e.g. from an ephemeral resource) is assumed set.
+		acct := data.ServiceAccount
+		if acct.IsNull() || acct.IsUnknown() || acct.ValueString() == "" ||
+			data.ServicePassword.IsUnknown() || data.ServicePasswordWO.IsUnknown() {
+			return
+		}
+		if a := acct.ValueString(); !builtinAccountRe.MatchString(a) && !passwordlessAccountRe.MatchString(a) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("service_account"),
+				"service_account requires a password (EC-15)",
+				"service_account '"+a+"' is not a built-in, virtual (NT SERVICE\\*) or managed ($) account. "+
+					"Set service_password_wo (or the deprecated service_password), or omit service_account to keep the account currently configured on the host.",
+			)
+		}
 		return
 	}
 
@@ -538,6 +882,50 @@ func (v serviceAccountPasswordValidator) ValidateResource(ctx context.Context, r
 	}
 }
 
+// -----------------------------------------------------------------------------
+// serviceRecoveryValidator
+// -----------------------------------------------------------------------------
+
+// serviceRecoveryValidator rejects a Run recovery action without a command;
+// sc.exe would accept it and the action would fail at the first crash.
+type serviceRecoveryValidator struct{}
+
+var _ resource.ConfigValidator = serviceRecoveryValidator{}
+
+// Description returns a plain-text description.
+func (serviceRecoveryValidator) Description(_ context.Context) string {
+	return "recovery.command is required when a recovery action is Run."
+}
+
+// MarkdownDescription returns a Markdown description.
+func (serviceRecoveryValidator) MarkdownDescription(_ context.Context) string {
+	return "`recovery.command` is required when a recovery action is `Run`."
+}
+
+// ValidateResource applies the rule at plan time.
+func (serviceRecoveryValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var obj types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("recovery"), &obj)...)
+	if resp.Diagnostics.HasError() || obj.IsNull() || obj.IsUnknown() {
+		return
+	}
+	var m windowsServiceRecoveryModel
+	resp.Diagnostics.Append(obj.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() || m.Command.IsUnknown() || m.Command.ValueString() != "" {
+		return
+	}
+	for _, a := range []types.String{m.FirstAction, m.SecondAction, m.SubsequentActions} {
+		if a.ValueString() == "Run" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("recovery").AtName("command"),
+				"recovery.command is required",
+				"A recovery action is Run but recovery.command is not set. Set the command line to run on failure.",
+			)
+			return
+		}
+	}
+}
+
 // credentialAttrSet returns ("service_password_wo", true) or
 // ("service_password", true) when the corresponding attribute is set in
 // configuration. Returns ("", false) when neither is set. The order of
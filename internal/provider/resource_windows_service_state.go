@@ -0,0 +1,288 @@
+// Package provider: windows_service_state resource implementation.
+//
+// windows_service_state adopts a service that already exists (typically one
+// installed by an MSI or shipped with Windows) and manages only its runtime
+// state and start type. The service registration is never created, changed
+// or removed: destroying the resource only drops it from state. WinRM
+// interaction is delegated to winclient.ServiceClient.SetRuntime
+// (internal/winclient/service_runtime.go).
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource                     = (*windowsServiceStateResource)(nil)
+	_ resource.ResourceWithConfigure        = (*windowsServiceStateResource)(nil)
+	_ resource.ResourceWithImportState      = (*windowsServiceStateResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*windowsServiceStateResource)(nil)
+)
+
+// NewWindowsServiceStateResource is the constructor registered in provider.go.
+func NewWindowsServiceStateResource() resource.Resource { return &windowsServiceStateResource{} }
+
+// windowsServiceStateResource is the TPF resource type for
+// windows_service_state.
+type windowsServiceStateResource struct {
+	svc winclient.WindowsServiceStateClient
+}
+
+// windowsServiceStateModel is the Terraform state/plan model for
+// windows_service_state.
+type windowsServiceStateModel struct {
+	ID                  types.String `tfsdk:"id"`
+	Name                types.String `tfsdk:"name"`
+	State               types.String `tfsdk:"state"`
+	StartType           types.String `tfsdk:"start_type"`
+	StateTimeoutSeconds types.Int64  `tfsdk:"state_timeout_seconds"`
+	CurrentStatus       types.String `tfsdk:"current_status"`
+}
+
+// windowsServiceStateSchemaDefinition returns the schema.Schema for
+// windows_service_state.
+func windowsServiceStateSchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Manages the runtime state and start type of an existing Windows service, without " +
+			"owning the service itself. Use it for services installed by something else (an MSI, a Windows " +
+			"feature) where `windows_service` would try to create them.\n\n" +
+			"Only `state` and `start_type` are changed, and only when set. Destroying the resource leaves the " +
+			"service as it is. At least one of `state` or `start_type` must be set.\n\n" +
+			"~> `state = \"Stopped\"` uses `Stop-Service -Force`, which also stops services that depend on this one.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Equal to name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Short name of the existing service (e.g. `Spooler`). **ForceNew.**",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 256),
+				},
+			},
+			"state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Desired runtime state: `Running` or `Stopped`. Null leaves the runtime state alone.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("Running", "Stopped"),
+				},
+			},
+			"start_type": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Desired start type: `Automatic`, `AutomaticDelayedStart`, `Manual` or `Disabled`. " +
+					"Null leaves the start type alone. Applied before `state`, so a `Disabled` service can be " +
+					"re-enabled and started in one apply.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("Automatic", "AutomaticDelayedStart", "Manual", "Disabled"),
+				},
+			},
+			"state_timeout_seconds": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Seconds to wait for the service to reach `state`. Defaults to the provider " +
+					"`timeout` (30 when that is under 10). Must be between 1 and 3600.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 3600),
+				},
+			},
+			"current_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Observed runtime state from the last apply or refresh (Running, Stopped, Paused).",
+			},
+		},
+	}
+}
+
+// Metadata sets the resource type name ("windows_service_state").
+func (r *windowsServiceStateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_state"
+}
+
+// Schema returns the full TPF schema for windows_service_state.
+func (r *windowsServiceStateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsServiceStateSchemaDefinition()
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsServiceStateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.svc = winclient.NewServiceClient(c)
+}
+
+// ConfigValidators requires something to manage and rejects a Disabled
+// service that should be Running.
+func (r *windowsServiceStateResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("state"),
+			path.MatchRoot("start_type"),
+		),
+		disabledRunningValidator{},
+	}
+}
+
+// disabledRunningValidator rejects start_type = "Disabled" with
+// state = "Running": the SCM refuses to start a disabled service.
+type disabledRunningValidator struct{}
+
+func (v disabledRunningValidator) Description(_ context.Context) string {
+	return "state Running requires a start_type other than Disabled."
+}
+
+func (v disabledRunningValidator) MarkdownDescription(_ context.Context) string {
+	return "`state = \"Running\"` requires a `start_type` other than `Disabled`."
+}
+
+func (v disabledRunningValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var state, startType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("state"), &state)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("start_type"), &startType)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.ValueString() == "Running" && startType.ValueString() == "Disabled" {
+		resp.Diagnostics.AddAttributeError(path.Root("state"), "Disabled service cannot be Running",
+			"Windows refuses to start a service whose start type is Disabled. Set start_type to Manual, "+
+				"Automatic or AutomaticDelayedStart, or set state to Stopped.")
+	}
+}
+
+// ImportState populates id and name from the import argument. The following
+// Read fills state and start_type from the host, since an imported resource
+// has neither yet.
+func (r *windowsServiceStateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
+
+// Create adopts the service and applies the configured start type and state.
+func (r *windowsServiceStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsServiceStateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.apply(ctx, plan, &resp.State, &resp.Diagnostics, "Create windows_service_state failed")
+}
+
+// Read refreshes current_status and reports drift on the managed attributes
+// only. Returns RemoveResource() when the service no longer exists.
+func (r *windowsServiceStateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsServiceStateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.Name.ValueString()
+	if name == "" {
+		name = state.ID.ValueString()
+	}
+	tflog.Debug(ctx, "windows_service_state Read", map[string]interface{}{"name": name})
+
+	obs, err := r.svc.Read(ctx, name)
+	if err != nil {
+		addServiceDiag(&resp.Diagnostics, "Read windows_service_state failed", err)
+		return
+	}
+	if obs == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	imported := state.State.IsNull() && state.StartType.IsNull()
+	state.ID = types.StringValue(obs.Name)
+	state.Name = types.StringValue(obs.Name)
+	state.CurrentStatus = types.StringValue(obs.CurrentStatus)
+	if !state.State.IsNull() || imported {
+		state.State = types.StringValue(obs.CurrentStatus)
+	}
+	if !state.StartType.IsNull() || imported {
+		state.StartType = types.StringValue(obs.StartType)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update applies the planned start type and state.
+func (r *windowsServiceStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan windowsServiceStateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.apply(ctx, plan, &resp.State, &resp.Diagnostics, "Update windows_service_state failed")
+}
+
+// Delete only removes the resource from state; the service keeps its current
+// state and start type.
+func (r *windowsServiceStateResource) Delete(ctx context.Context, req resource.DeleteRequest, _ *resource.DeleteResponse) {
+	var state windowsServiceStateModel
+	req.State.Get(ctx, &state)
+	tflog.Debug(ctx, "windows_service_state Delete: leaving the service untouched", map[string]interface{}{
+		"name": state.Name.ValueString(),
+	})
+}
+
+// apply is shared by Create and Update: it calls SetRuntime with the managed
+// attributes of plan and persists plan with the observed current_status.
+func (r *windowsServiceStateResource) apply(ctx context.Context, plan windowsServiceStateModel, st *tfsdk.State, diags *diag.Diagnostics, summary string) {
+	name := plan.Name.ValueString()
+	in := winclient.ServiceRuntimeInput{
+		StartType:     plan.StartType.ValueString(),
+		DesiredStatus: plan.State.ValueString(),
+		StateTimeout:  serviceStateTimeout(plan.StateTimeoutSeconds),
+	}
+	tflog.Debug(ctx, "windows_service_state apply", map[string]interface{}{
+		"name": name, "start_type": in.StartType, "desired_status": in.DesiredStatus,
+	})
+
+	obs, err := r.svc.SetRuntime(ctx, name, in)
+	if err != nil {
+		if winclient.IsServiceError(err, winclient.ServiceErrorNotFound) {
+			diags.AddError(summary,
+				fmt.Sprintf("Service %q does not exist on the target host. windows_service_state only manages "+
+					"existing services; use windows_service to create one.", name))
+			return
+		}
+		addServiceDiag(diags, summary, err)
+		return
+	}
+
+	plan.ID = types.StringValue(obs.Name)
+	plan.CurrentStatus = types.StringValue(obs.CurrentStatus)
+	diags.Append(st.Set(ctx, &plan)...)
+}
@@ -0,0 +1,71 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for windows_service_state.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsServiceState
+//
+// The adopted service is a disposable windows_service pointing at cmd.exe,
+// so only Stopped is exercised: cmd.exe never answers the SCM and cannot be
+// brought to Running.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccServiceStateConfig(startType string) string {
+	return `
+resource "windows_service" "target" {
+  name        = "tf-acc-svc-state"
+  binary_path = "C:\\Windows\\System32\\cmd.exe"
+  start_type  = "Manual"
+
+  lifecycle {
+    ignore_changes = [start_type]
+  }
+}
+
+resource "windows_service_state" "test" {
+  name       = windows_service.target.name
+  state      = "Stopped"
+  start_type = "` + startType + `"
+}
+`
+}
+
+// TestAccWindowsServiceState_Basic — adopt, no-op plan, change start type,
+// import.
+func TestAccWindowsServiceState_Basic(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceStateConfig("Manual"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windows_service_state.test", "id", "tf-acc-svc-state"),
+					resource.TestCheckResourceAttr("windows_service_state.test", "current_status", "Stopped"),
+				),
+			},
+			{
+				Config:   testAccServiceStateConfig("Manual"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccServiceStateConfig("Disabled"),
+				Check:  resource.TestCheckResourceAttr("windows_service_state.test", "start_type", "Disabled"),
+			},
+			{
+				ResourceName:            "windows_service_state.test",
+				ImportState:             true,
+				ImportStateId:           "tf-acc-svc-state",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"state_timeout_seconds"},
+			},
+		},
+	})
+}
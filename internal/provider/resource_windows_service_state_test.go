@@ -0,0 +1,225 @@
+// Package provider — unit tests for the windows_service_state resource.
+//
+// A fakeServiceStateClient is injected into windowsServiceStateResource.svc,
+// so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeServiceStateClient struct {
+	obs *winclient.ServiceState
+	err error
+
+	setCalls int
+	lastIn   winclient.ServiceRuntimeInput
+}
+
+func (f *fakeServiceStateClient) Read(_ context.Context, _ string) (*winclient.ServiceState, error) {
+	return f.obs, f.err
+}
+
+func (f *fakeServiceStateClient) SetRuntime(_ context.Context, _ string, in winclient.ServiceRuntimeInput) (*winclient.ServiceState, error) {
+	f.setCalls++
+	f.lastIn = in
+	return f.obs, f.err
+}
+
+func svcStateObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":                    tftypes.String,
+		"name":                  tftypes.String,
+		"state":                 tftypes.String,
+		"start_type":            tftypes.String,
+		"state_timeout_seconds": tftypes.Number,
+		"current_status":        tftypes.String,
+	}}
+}
+
+func svcStateObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id":                    tftypes.NewValue(tftypes.String, "Spooler"),
+		"name":                  tftypes.NewValue(tftypes.String, "Spooler"),
+		"state":                 tftypes.NewValue(tftypes.String, nil),
+		"start_type":            tftypes.NewValue(tftypes.String, nil),
+		"state_timeout_seconds": tftypes.NewValue(tftypes.Number, nil),
+		"current_status":        tftypes.NewValue(tftypes.String, "Running"),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(svcStateObjectType(), base)
+}
+
+func svcStateCreate(t *testing.T, r *windowsServiceStateResource, overrides map[string]tftypes.Value) (*resource.CreateResponse, windowsServiceStateModel) {
+	t.Helper()
+	overrides["id"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	overrides["current_status"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	s := windowsServiceStateSchemaDefinition()
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(svcStateObjectType(), nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: svcStateObj(overrides)}}, resp)
+	var got windowsServiceStateModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+func svcStateRead(t *testing.T, r *windowsServiceStateResource, overrides map[string]tftypes.Value) (*resource.ReadResponse, windowsServiceStateModel) {
+	t.Helper()
+	s := windowsServiceStateSchemaDefinition()
+	st := tfsdk.State{Schema: s, Raw: svcStateObj(overrides)}
+	resp := &resource.ReadResponse{State: st}
+	r.Read(context.Background(), resource.ReadRequest{State: st}, resp)
+	var got windowsServiceStateModel
+	if !resp.Diagnostics.HasError() && !resp.State.Raw.IsNull() {
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+func TestServiceStateSchema(t *testing.T) {
+	r := &windowsServiceStateResource{}
+	mr := &resource.MetadataResponse{}
+	r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "windows"}, mr)
+	if mr.TypeName != "windows_service_state" {
+		t.Errorf("TypeName = %q", mr.TypeName)
+	}
+	s := windowsServiceStateSchemaDefinition()
+	for _, k := range []string{"state", "start_type", "state_timeout_seconds"} {
+		if a := s.Attributes[k]; !a.IsOptional() || a.IsComputed() {
+			t.Errorf("%q must be Optional only, so an unset value is left alone", k)
+		}
+	}
+	if !s.Attributes["name"].IsRequired() || !s.Attributes["current_status"].IsComputed() {
+		t.Error("name must be required and current_status computed")
+	}
+	if len(r.ConfigValidators(context.Background())) != 2 {
+		t.Error("expected the AtLeastOneOf and Disabled/Running validators")
+	}
+}
+
+func TestServiceStateDisabledRunningValidator(t *testing.T) {
+	s := windowsServiceStateSchemaDefinition()
+	for _, tc := range []struct {
+		state, startType string
+		wantErr          bool
+	}{
+		{"Running", "Disabled", true},
+		{"Stopped", "Disabled", false},
+		{"Running", "Manual", false},
+	} {
+		cfg := tfsdk.Config{Schema: s, Raw: svcStateObj(map[string]tftypes.Value{
+			"state":      tftypes.NewValue(tftypes.String, tc.state),
+			"start_type": tftypes.NewValue(tftypes.String, tc.startType),
+		})}
+		resp := &resource.ValidateConfigResponse{}
+		disabledRunningValidator{}.ValidateResource(context.Background(), resource.ValidateConfigRequest{Config: cfg}, resp)
+		if resp.Diagnostics.HasError() != tc.wantErr {
+			t.Errorf("%s/%s: diags = %v, want error %v", tc.state, tc.startType, resp.Diagnostics, tc.wantErr)
+		}
+	}
+}
+
+func TestServiceStateCreate(t *testing.T) {
+	fake := &fakeServiceStateClient{obs: &winclient.ServiceState{Name: "Spooler", StartType: "Manual", CurrentStatus: "Stopped"}}
+	r := &windowsServiceStateResource{svc: fake}
+	resp, got := svcStateCreate(t, r, map[string]tftypes.Value{
+		"state":                 tftypes.NewValue(tftypes.String, "Stopped"),
+		"start_type":            tftypes.NewValue(tftypes.String, "Manual"),
+		"state_timeout_seconds": tftypes.NewValue(tftypes.Number, 90),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	want := winclient.ServiceRuntimeInput{StartType: "Manual", DesiredStatus: "Stopped", StateTimeout: 90 * time.Second}
+	if fake.setCalls != 1 || fake.lastIn != want {
+		t.Errorf("SetRuntime called %d times with %+v", fake.setCalls, fake.lastIn)
+	}
+	if got.ID.ValueString() != "Spooler" || got.CurrentStatus.ValueString() != "Stopped" {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestServiceStateCreate_MissingService(t *testing.T) {
+	fake := &fakeServiceStateClient{err: winclient.NewServiceError(winclient.ServiceErrorNotFound, "service 'Nope' does not exist", nil, nil)}
+	r := &windowsServiceStateResource{svc: fake}
+	resp, _ := svcStateCreate(t, r, map[string]tftypes.Value{
+		"name":  tftypes.NewValue(tftypes.String, "Nope"),
+		"state": tftypes.NewValue(tftypes.String, "Running"),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "use windows_service to create one") {
+		t.Errorf("missing service must give a clear error: %v", resp.Diagnostics)
+	}
+}
+
+func TestServiceStateRead_DriftOnManagedAttributesOnly(t *testing.T) {
+	fake := &fakeServiceStateClient{obs: &winclient.ServiceState{Name: "Spooler", StartType: "Disabled", CurrentStatus: "Stopped"}}
+	r := &windowsServiceStateResource{svc: fake}
+	resp, got := svcStateRead(t, r, map[string]tftypes.Value{
+		"state": tftypes.NewValue(tftypes.String, "Running"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if got.State.ValueString() != "Stopped" || got.CurrentStatus.ValueString() != "Stopped" {
+		t.Errorf("state drift not reported: %+v", got)
+	}
+	if !got.StartType.IsNull() {
+		t.Errorf("unmanaged start_type must stay null, got %q", got.StartType.ValueString())
+	}
+}
+
+func TestServiceStateRead_ImportAndGone(t *testing.T) {
+	fake := &fakeServiceStateClient{obs: &winclient.ServiceState{Name: "Spooler", StartType: "Automatic", CurrentStatus: "Running"}}
+	r := &windowsServiceStateResource{svc: fake}
+	_, got := svcStateRead(t, r, map[string]tftypes.Value{
+		"name":           tftypes.NewValue(tftypes.String, nil),
+		"current_status": tftypes.NewValue(tftypes.String, nil),
+	})
+	if got.Name.ValueString() != "Spooler" || got.State.ValueString() != "Running" || got.StartType.ValueString() != "Automatic" {
+		t.Errorf("import must populate both managed attributes: %+v", got)
+	}
+
+	fake.obs = nil
+	resp, _ := svcStateRead(t, r, map[string]tftypes.Value{"state": tftypes.NewValue(tftypes.String, "Running")})
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Errorf("a deleted service must be removed from state: %v", resp.Diagnostics)
+	}
+}
+
+func TestServiceStateDelete_LeavesServiceAlone(t *testing.T) {
+	fake := &fakeServiceStateClient{}
+	r := &windowsServiceStateResource{svc: fake}
+	s := windowsServiceStateSchemaDefinition()
+	st := tfsdk.State{Schema: s, Raw: svcStateObj(map[string]tftypes.Value{"state": tftypes.NewValue(tftypes.String, "Running")})}
+	resp := &resource.DeleteResponse{State: st}
+	r.Delete(context.Background(), resource.DeleteRequest{State: st}, resp)
+	if resp.Diagnostics.HasError() || fake.setCalls != 0 {
+		t.Errorf("Delete must not touch the service: calls=%d diags=%v", fake.setCalls, resp.Diagnostics)
+	}
+}
+
+func TestServiceStateConfigure(t *testing.T) {
+	r := &windowsServiceStateResource{}
+	resp := &resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: "nope"}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("wrong type must produce error")
+	}
+	resp = &resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: &winclient.Client{}}, resp)
+	if resp.Diagnostics.HasError() || r.svc == nil {
+		t.Errorf("correct type must configure client: %v", resp.Diagnostics)
+	}
+}
@@ -18,15 +18,22 @@ package provider
 import (
 	"context"
 	"errors"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
 	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
@@ -49,7 +56,7 @@ func TestMetadata(t *testing.T) {
 func TestSchema_HasRequiredAttributes(t *testing.T) {
 	s := windowsServiceSchemaDefinition()
 	wantAttrs := []string{
-		"id", "name", "display_name", "description", "binary_path",
+		"id", "name", "display_name", "description", "binary_path", "arguments",
 		"start_type", "status", "current_status", "service_account",
 		"service_password", "service_password_wo", "dependencies",
 	}
@@ -140,31 +147,41 @@ func buildValidatorConfig(t *testing.T, account, password *string) tfsdk.Config
 	}
 
 	obj := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
-		"id":                  tftypes.String,
-		"name":                tftypes.String,
-		"display_name":        tftypes.String,
-		"description":         tftypes.String,
-		"binary_path":         tftypes.String,
-		"start_type":          tftypes.String,
-		"status":              tftypes.String,
-		"current_status":      tftypes.String,
-		"service_account":     tftypes.String,
-		"service_password":    tftypes.String,
-		"service_password_wo": tftypes.String,
-		"dependencies":        tftypes.List{ElementType: tftypes.String},
+		"id":                    tftypes.String,
+		"name":                  tftypes.String,
+		"display_name":          tftypes.String,
+		"description":           tftypes.String,
+		"binary_path":           tftypes.String,
+		"arguments":             tftypes.String,
+		"start_type":            tftypes.String,
+		"status":                tftypes.String,
+		"current_status":        tftypes.String,
+		"service_account":       tftypes.String,
+		"service_password":      tftypes.String,
+		"service_password_wo":   tftypes.String,
+		"dependencies":          tftypes.List{ElementType: tftypes.String},
+		"state_timeout_seconds": tftypes.Number,
+		"read_retries":          tftypes.Number,
+		"sid_type":              tftypes.String,
+		"recovery":              serviceRecoveryObjectType(),
 	}}, map[string]tftypes.Value{
-		"id":                  tftypes.NewValue(tftypes.String, nil),
-		"name":                tftypes.NewValue(tftypes.String, "svc"),
-		"display_name":        tftypes.NewValue(tftypes.String, nil),
-		"description":         tftypes.NewValue(tftypes.String, nil),
-		"binary_path":         tftypes.NewValue(tftypes.String, `C:\x.exe`),
-		"start_type":          tftypes.NewValue(tftypes.String, nil),
-		"status":              tftypes.NewValue(tftypes.String, nil),
-		"current_status":      tftypes.NewValue(tftypes.String, nil),
-		"service_account":     val(account),
-		"service_password":    val(password),
-		"service_password_wo": tftypes.NewValue(tftypes.String, nil),
-		"dependencies":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"id":                    tftypes.NewValue(tftypes.String, nil),
+		"name":                  tftypes.NewValue(tftypes.String, "svc"),
+		"display_name":          tftypes.NewValue(tftypes.String, nil),
+		"description":           tftypes.NewValue(tftypes.String, nil),
+		"binary_path":           tftypes.NewValue(tftypes.String, `C:\x.exe`),
+		"arguments":             tftypes.NewValue(tftypes.String, nil),
+		"start_type":            tftypes.NewValue(tftypes.String, nil),
+		"status":                tftypes.NewValue(tftypes.String, nil),
+		"current_status":        tftypes.NewValue(tftypes.String, nil),
+		"service_account":       val(account),
+		"service_password":      val(password),
+		"service_password_wo":   tftypes.NewValue(tftypes.String, nil),
+		"dependencies":          tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"state_timeout_seconds": tftypes.NewValue(tftypes.Number, nil),
+		"read_retries":          tftypes.NewValue(tftypes.Number, nil),
+		"sid_type":              tftypes.NewValue(tftypes.String, nil),
+		"recovery":              tftypes.NewValue(serviceRecoveryObjectType(), nil),
 	})
 
 	return tfsdk.Config{
@@ -238,6 +255,29 @@ func TestValidator_PasswordWithDomainAccount_OK(t *testing.T) {
 	}
 }
 
+func TestValidator_AccountWithoutPassword_EC15(t *testing.T) {
+	cases := map[string]bool{
+		"DOMAIN\\svc-app":              true,
+		".\\svcuser":                   true,
+		"LocalSystem":                  false,
+		"NT AUTHORITY\\NetworkService": false,
+		"NT SERVICE\\MyApp":            false,
+		"DOMAIN\\gmsa-app$":            false,
+	}
+	for acct, wantErr := range cases {
+		a := acct
+		resp := &resource.ValidateConfigResponse{}
+		serviceAccountPasswordValidator{}.ValidateResource(context.Background(),
+			resource.ValidateConfigRequest{Config: buildValidatorConfig(t, &a, nil)}, resp)
+		if resp.Diagnostics.HasError() != wantErr {
+			t.Errorf("%s: error = %v, want %v (%s)", acct, resp.Diagnostics.HasError(), wantErr, diagSummary(resp.Diagnostics))
+		}
+		if wantErr && !strings.Contains(diagSummary(resp.Diagnostics), "EC-15") {
+			t.Errorf("%s: error should reference EC-15: %s", acct, diagSummary(resp.Diagnostics))
+		}
+	}
+}
+
 func diagSummary(d diag.Diagnostics) string {
 	var b strings.Builder
 	for _, x := range d {
@@ -294,6 +334,92 @@ func TestModelFromState_DescriptionSetWhenObserved(t *testing.T) {
 	}
 }
 
+func TestModelFromState_ArgumentsUnmanagedKeepsCommandLine(t *testing.T) {
+	s := &winclient.ServiceState{
+		Name: "svc", BinaryPath: `C:\app.exe --legacy`,
+		Executable: `C:\app.exe`, Arguments: "--legacy",
+	}
+	got := modelFromState(s, windowsServiceModel{Arguments: types.StringNull()})
+	if got.BinaryPath.ValueString() != `C:\app.exe --legacy` || !got.Arguments.IsNull() {
+		t.Errorf("unmanaged arguments: binary_path=%q arguments=%v", got.BinaryPath.ValueString(), got.Arguments)
+	}
+}
+
+func TestModelFromState_ArgumentsManagedSplitsCommandLine(t *testing.T) {
+	s := &winclient.ServiceState{
+		Name: "svc", BinaryPath: `"C:\Program Files\App\app.exe" --port 8080`,
+		Executable: `C:\Program Files\App\app.exe`, Arguments: "--port 8080",
+	}
+	got := modelFromState(s, windowsServiceModel{Arguments: types.StringValue("--port 80")})
+	if got.BinaryPath.ValueString() != `C:\Program Files\App\app.exe` {
+		t.Errorf("binary_path = %q", got.BinaryPath.ValueString())
+	}
+	if got.Arguments.ValueString() != "--port 8080" {
+		t.Errorf("arguments must reflect the live value for drift detection, got %q", got.Arguments.ValueString())
+	}
+}
+
+func TestModelFromState_QuotedBinaryPathKept(t *testing.T) {
+	s := &winclient.ServiceState{
+		Name: "svc", BinaryPath: `"C:\Program Files\App\app.exe" --port 8080`,
+		Executable: `C:\Program Files\App\app.exe`, Arguments: "--port 8080",
+	}
+	prior := windowsServiceModel{
+		BinaryPath: types.StringValue(`"C:\Program Files\App\app.exe"`),
+		Arguments:  types.StringValue("--port 8080"),
+	}
+	if got := modelFromState(s, prior); got.BinaryPath != prior.BinaryPath {
+		t.Errorf("quoted binary_path must be kept, got %q", got.BinaryPath.ValueString())
+	}
+
+	// Legacy: EC-14 strips the quotes of a bare quoted path.
+	s = &winclient.ServiceState{Name: "svc", BinaryPath: `C:\app.exe`, Executable: `C:\app.exe`}
+	prior = windowsServiceModel{BinaryPath: types.StringValue(`"C:\app.exe"`), Arguments: types.StringNull()}
+	if got := modelFromState(s, prior); got.BinaryPath != prior.BinaryPath {
+		t.Errorf("legacy quoted binary_path must be kept, got %q", got.BinaryPath.ValueString())
+	}
+
+	// A different path is drift.
+	prior.BinaryPath = types.StringValue(`"C:\other.exe"`)
+	if got := modelFromState(s, prior); got.BinaryPath.ValueString() != `C:\app.exe` {
+		t.Errorf("changed binary_path must be reported, got %q", got.BinaryPath.ValueString())
+	}
+}
+
+func TestServiceBinaryPath_ReplaceUnlessQuotingOnly(t *testing.T) {
+	for _, c := range []struct {
+		state, plan string
+		want        bool
+	}{
+		{`C:\app.exe`, `"C:\app.exe"`, false},
+		{`"C:\Program Files\app.exe"`, `C:\Program Files\app.exe`, false},
+		{`C:\app.exe`, `C:\other.exe`, true},
+		{`"C:\app.exe" -v`, `C:\app.exe -v`, true},
+	} {
+		resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+		requiresReplaceUnlessQuotingOnly(context.Background(), planmodifier.StringRequest{
+			Path:       path.Root("binary_path"),
+			StateValue: types.StringValue(c.state),
+			PlanValue:  types.StringValue(c.plan),
+		}, resp)
+		if resp.RequiresReplace != c.want {
+			t.Errorf("%q -> %q: RequiresReplace = %v, want %v", c.state, c.plan, resp.RequiresReplace, c.want)
+		}
+	}
+}
+
+func TestServiceArguments(t *testing.T) {
+	if serviceArguments(types.StringNull(), types.StringNull()) != nil {
+		t.Error("null plan and prior must leave the command line unmanaged")
+	}
+	if got := serviceArguments(types.StringNull(), types.StringValue("-v")); got == nil || *got != "" {
+		t.Errorf("removing arguments must reset to the bare executable, got %v", got)
+	}
+	if got := serviceArguments(types.StringValue("-v"), types.StringNull()); got == nil || *got != "-v" {
+		t.Errorf("planned arguments must be passed through, got %v", got)
+	}
+}
+
 func TestAddServiceDiag_StructuredError(t *testing.T) {
 	diags := &diag.Diagnostics{}
 	se := winclient.NewServiceError(
@@ -373,11 +499,12 @@ func TestConfigure_ValidClient(t *testing.T) {
 func TestConfigValidators(t *testing.T) {
 	r := &windowsServiceResource{}
 	vs := r.ConfigValidators(context.Background())
-	// Tier 3: 2 validators are now expected.
+	// 3 validators are expected.
 	//   - serviceAccountPasswordValidator (EC-4 / EC-11)
 	//   - resourcevalidator.Conflicting(service_password, service_password_wo)
-	if len(vs) != 2 {
-		t.Fatalf("expected 2 validators, got %d", len(vs))
+	//   - serviceRecoveryValidator (Run needs a command)
+	if len(vs) != 3 {
+		t.Fatalf("expected 3 validators, got %d", len(vs))
 	}
 	// First validator must remain the serviceAccountPasswordValidator —
 	// downstream tests assert on its diagnostics by type.
@@ -390,6 +517,9 @@ func TestConfigValidators(t *testing.T) {
 	if !strings.Contains(desc, "service_password") || !strings.Contains(desc, "service_password_wo") {
 		t.Errorf("validator[1] description must reference both service_password and service_password_wo, got: %q", desc)
 	}
+	if _, ok := vs[2].(serviceRecoveryValidator); !ok {
+		t.Errorf("validator[2] type = %T, want serviceRecoveryValidator", vs[2])
+	}
 }
 
 func TestNewWindowsServiceResource_NotNil(t *testing.T) {
@@ -474,37 +604,71 @@ func (f *fakeSvcClient) PauseService(_ context.Context, _ string) error { f.paus
 // objectType mirrors the resource schema as a tftypes.Object shape.
 func serviceObjectType() tftypes.Object {
 	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
-		"id":                  tftypes.String,
-		"name":                tftypes.String,
-		"display_name":        tftypes.String,
-		"description":         tftypes.String,
-		"binary_path":         tftypes.String,
-		"start_type":          tftypes.String,
-		"status":              tftypes.String,
-		"current_status":      tftypes.String,
-		"service_account":     tftypes.String,
-		"service_password":    tftypes.String,
-		"service_password_wo": tftypes.String,
-		"dependencies":        tftypes.List{ElementType: tftypes.String},
+		"id":                    tftypes.String,
+		"name":                  tftypes.String,
+		"display_name":          tftypes.String,
+		"description":           tftypes.String,
+		"binary_path":           tftypes.String,
+		"arguments":             tftypes.String,
+		"start_type":            tftypes.String,
+		"status":                tftypes.String,
+		"current_status":        tftypes.String,
+		"service_account":       tftypes.String,
+		"service_password":      tftypes.String,
+		"service_password_wo":   tftypes.String,
+		"dependencies":          tftypes.List{ElementType: tftypes.String},
+		"state_timeout_seconds": tftypes.Number,
+		"read_retries":          tftypes.Number,
+		"sid_type":              tftypes.String,
+		"recovery":              serviceRecoveryObjectType(),
+	}}
+}
+
+// serviceRecoveryObjectType mirrors the recovery nested attribute.
+func serviceRecoveryObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"first_action":          tftypes.String,
+		"second_action":         tftypes.String,
+		"subsequent_actions":    tftypes.String,
+		"reset_period_seconds":  tftypes.Number,
+		"restart_delay_seconds": tftypes.Number,
+		"command":               tftypes.String,
 	}}
 }
 
+// svcRecovery builds a recovery object value; command nil is null.
+func svcRecovery(first, second, subsequent string, reset, delay int64, command any) tftypes.Value {
+	return tftypes.NewValue(serviceRecoveryObjectType(), map[string]tftypes.Value{
+		"first_action":          tftypes.NewValue(tftypes.String, first),
+		"second_action":         tftypes.NewValue(tftypes.String, second),
+		"subsequent_actions":    tftypes.NewValue(tftypes.String, subsequent),
+		"reset_period_seconds":  tftypes.NewValue(tftypes.Number, reset),
+		"restart_delay_seconds": tftypes.NewValue(tftypes.Number, delay),
+		"command":               tftypes.NewValue(tftypes.String, command),
+	})
+}
+
 // svcObj builds a tftypes.Value for the service model, with nil entries
 // represented as null.
 func svcObj(overrides map[string]tftypes.Value) tftypes.Value {
 	base := map[string]tftypes.Value{
-		"id":                  tftypes.NewValue(tftypes.String, nil),
-		"name":                tftypes.NewValue(tftypes.String, nil),
-		"display_name":        tftypes.NewValue(tftypes.String, nil),
-		"description":         tftypes.NewValue(tftypes.String, nil),
-		"binary_path":         tftypes.NewValue(tftypes.String, nil),
-		"start_type":          tftypes.NewValue(tftypes.String, nil),
-		"status":              tftypes.NewValue(tftypes.String, nil),
-		"current_status":      tftypes.NewValue(tftypes.String, nil),
-		"service_account":     tftypes.NewValue(tftypes.String, nil),
-		"service_password":    tftypes.NewValue(tftypes.String, nil),
-		"service_password_wo": tftypes.NewValue(tftypes.String, nil),
-		"dependencies":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"id":                    tftypes.NewValue(tftypes.String, nil),
+		"name":                  tftypes.NewValue(tftypes.String, nil),
+		"display_name":          tftypes.NewValue(tftypes.String, nil),
+		"description":           tftypes.NewValue(tftypes.String, nil),
+		"binary_path":           tftypes.NewValue(tftypes.String, nil),
+		"arguments":             tftypes.NewValue(tftypes.String, nil),
+		"start_type":            tftypes.NewValue(tftypes.String, nil),
+		"status":                tftypes.NewValue(tftypes.String, nil),
+		"current_status":        tftypes.NewValue(tftypes.String, nil),
+		"service_account":       tftypes.NewValue(tftypes.String, nil),
+		"service_password":      tftypes.NewValue(tftypes.String, nil),
+		"service_password_wo":   tftypes.NewValue(tftypes.String, nil),
+		"dependencies":          tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"state_timeout_seconds": tftypes.NewValue(tftypes.Number, nil),
+		"read_retries":          tftypes.NewValue(tftypes.Number, nil),
+		"sid_type":              tftypes.NewValue(tftypes.String, nil),
+		"recovery":              tftypes.NewValue(serviceRecoveryObjectType(), nil),
 	}
 	for k, v := range overrides {
 		base[k] = v
@@ -550,6 +714,65 @@ func TestCreate_Handler_HappyPath(t *testing.T) {
 	}
 }
 
+func TestCreate_Handler_StateTimeout(t *testing.T) {
+	fake := &fakeSvcClient{createOut: stateOK()}
+	r := &windowsServiceResource{svc: fake}
+	schemaDef := windowsServiceSchemaDefinition()
+	plan := tfsdk.Plan{
+		Schema: schemaDef,
+		Raw: svcObj(map[string]tftypes.Value{
+			"name":                  tftypes.NewValue(tftypes.String, "svc"),
+			"binary_path":           tftypes.NewValue(tftypes.String, `C:\svc.exe`),
+			"status":                tftypes.NewValue(tftypes.String, "Running"),
+			"state_timeout_seconds": tftypes.NewValue(tftypes.Number, 120),
+		}),
+	}
+	resp := &resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaDef, Raw: svcObj(nil)},
+	}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	if fake.createIn.StateTimeout != 120*time.Second {
+		t.Errorf("StateTimeout = %v, want 2m0s", fake.createIn.StateTimeout)
+	}
+	var got windowsServiceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if got.StateTimeoutSeconds.ValueInt64() != 120 {
+		t.Errorf("state_timeout_seconds not carried to state: %v", got.StateTimeoutSeconds)
+	}
+}
+
+func TestCreate_Handler_DependencyFailedNamesDependency(t *testing.T) {
+	fake := &fakeSvcClient{createErr: winclient.NewServiceError(
+		winclient.ServiceErrorDependencyFailed, "dependency 'Tcpip' of service 'svc' is Disabled and cannot be started",
+		nil, map[string]string{"dependency": "Tcpip"})}
+	r := &windowsServiceResource{svc: fake}
+	schemaDef := windowsServiceSchemaDefinition()
+	plan := tfsdk.Plan{
+		Schema: schemaDef,
+		Raw: svcObj(map[string]tftypes.Value{
+			"name":        tftypes.NewValue(tftypes.String, "svc"),
+			"binary_path": tftypes.NewValue(tftypes.String, `C:\x.exe`),
+			"status":      tftypes.NewValue(tftypes.String, "Running"),
+		}),
+	}
+	resp := &resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaDef, Raw: svcObj(nil)},
+	}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected error diag from dependency_failed")
+	}
+	detail := resp.Diagnostics.Errors()[0].Detail()
+	if !strings.Contains(detail, "dependency = Tcpip") || !strings.Contains(detail, "Kind: dependency_failed") {
+		t.Errorf("detail should name the dependency: %s", detail)
+	}
+}
+
 func TestCreate_Handler_ClientError(t *testing.T) {
 	fake := &fakeSvcClient{createErr: winclient.NewServiceError(
 		winclient.ServiceErrorAlreadyExists, "service 'svc' exists", nil, nil)}
@@ -595,6 +818,33 @@ func TestRead_Handler_HappyPath(t *testing.T) {
 	}
 }
 
+func TestRead_Handler_KeepsReadRetries(t *testing.T) {
+	fake := &fakeSvcClient{readOut: stateOK()}
+	r := &windowsServiceResource{svc: fake}
+
+	schemaDef := windowsServiceSchemaDefinition()
+	priorState := tfsdk.State{
+		Schema: schemaDef,
+		Raw: svcObj(map[string]tftypes.Value{
+			"id":           tftypes.NewValue(tftypes.String, "svc"),
+			"name":         tftypes.NewValue(tftypes.String, "svc"),
+			"read_retries": tftypes.NewValue(tftypes.Number, 3),
+		}),
+	}
+	resp := &resource.ReadResponse{
+		State: tfsdk.State{Schema: schemaDef, Raw: priorState.Raw.Copy()},
+	}
+	r.Read(context.Background(), resource.ReadRequest{State: priorState}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("diags: %v", resp.Diagnostics)
+	}
+	var got windowsServiceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if got.ReadRetries.ValueInt64() != 3 {
+		t.Errorf("read_retries not carried to state: %v", got.ReadRetries)
+	}
+}
+
 func TestRead_Handler_NotFound_RemovesResource(t *testing.T) {
 	// client returns (nil, nil) → handler must call RemoveResource.
 	fake := &fakeSvcClient{readOut: nil, readErr: nil}
@@ -679,6 +929,175 @@ func TestUpdate_Handler_HappyPath(t *testing.T) {
 	}
 }
 
+// An unset display_name plans as the prior read-back value (UseStateForUnknown)
+// and must not be re-applied on every Update.
+func TestUpdate_Handler_UnsetDisplayNameNotReapplied(t *testing.T) {
+	schemaDef := windowsServiceSchemaDefinition()
+	for _, planned := range []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "Read Back Name"),
+		tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	} {
+		fake := &fakeSvcClient{updateOut: stateOK()}
+		r := &windowsServiceResource{svc: fake}
+		plan := tfsdk.Plan{
+			Schema: schemaDef,
+			Raw: svcObj(map[string]tftypes.Value{
+				"name":         tftypes.NewValue(tftypes.String, "svc"),
+				"binary_path":  tftypes.NewValue(tftypes.String, `C:\svc.exe`),
+				"display_name": planned,
+				"start_type":   tftypes.NewValue(tftypes.String, "Manual"),
+			}),
+		}
+		priorState := tfsdk.State{
+			Schema: schemaDef,
+			Raw: svcObj(map[string]tftypes.Value{
+				"id":           tftypes.NewValue(tftypes.String, "svc"),
+				"name":         tftypes.NewValue(tftypes.String, "svc"),
+				"binary_path":  tftypes.NewValue(tftypes.String, `C:\svc.exe`),
+				"display_name": tftypes.NewValue(tftypes.String, "Read Back Name"),
+			}),
+		}
+		resp := &resource.UpdateResponse{
+			State: tfsdk.State{Schema: schemaDef, Raw: priorState.Raw.Copy()},
+		}
+		r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: priorState}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		if fake.updateIn.DisplayName != "" {
+			t.Errorf("planned %v: DisplayName = %q, want \"\" (no change)", planned, fake.updateIn.DisplayName)
+		}
+		if fake.updateIn.StartType != "Manual" {
+			t.Errorf("StartType = %q, want Manual", fake.updateIn.StartType)
+		}
+	}
+}
+
+func TestServiceDisplayNameChange(t *testing.T) {
+	prior := types.StringValue("Spooler Service")
+	cases := []struct {
+		planned types.String
+		want    string
+	}{
+		{types.StringValue("Spooler Service"), ""},
+		{types.StringUnknown(), ""},
+		{types.StringNull(), ""},
+		{types.StringValue("Print Spooler"), "Print Spooler"},
+	}
+	for _, c := range cases {
+		if got := serviceDisplayNameChange(c.planned, prior); got != c.want {
+			t.Errorf("serviceDisplayNameChange(%v) = %q, want %q", c.planned, got, c.want)
+		}
+	}
+}
+
+func TestServiceSidTypeChange(t *testing.T) {
+	prior := types.StringValue("None")
+	cases := []struct {
+		planned types.String
+		want    string
+	}{
+		{types.StringValue("None"), ""},
+		{types.StringUnknown(), ""},
+		{types.StringNull(), ""},
+		{types.StringValue("Restricted"), "Restricted"},
+	}
+	for _, c := range cases {
+		if got := serviceSidTypeChange(c.planned, prior); got != c.want {
+			t.Errorf("serviceSidTypeChange(%v) = %q, want %q", c.planned, got, c.want)
+		}
+	}
+}
+
+func TestModelFromState_SidType(t *testing.T) {
+	st := stateOK()
+	st.SidType = "Unrestricted"
+	if got := modelFromState(st, windowsServiceModel{}).SidType.ValueString(); got != "Unrestricted" {
+		t.Errorf("sid_type = %q, want Unrestricted", got)
+	}
+	// qsidtype unreadable: keep the prior value rather than planning a change.
+	st.SidType = ""
+	if got := modelFromState(st, windowsServiceModel{SidType: types.StringValue("Restricted")}).SidType; got.ValueString() != "Restricted" {
+		t.Errorf("sid_type = %v, want prior Restricted", got)
+	}
+	if got := modelFromState(st, windowsServiceModel{SidType: types.StringUnknown()}).SidType; !got.IsNull() {
+		t.Errorf("unknown prior must become null, got %v", got)
+	}
+}
+
+// An unset service_account plans as the prior read-back value
+// (UseStateForUnknown) and must not trigger a credential update.
+func TestUpdate_Handler_UnsetServiceAccountNotReapplied(t *testing.T) {
+	schemaDef := windowsServiceSchemaDefinition()
+	for _, planned := range []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "LocalSystem"),
+		tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	} {
+		fake := &fakeSvcClient{updateOut: stateOK()}
+		r := &windowsServiceResource{svc: fake}
+		plan := tfsdk.Plan{
+			Schema: schemaDef,
+			Raw: svcObj(map[string]tftypes.Value{
+				"name":            tftypes.NewValue(tftypes.String, "svc"),
+				"binary_path":     tftypes.NewValue(tftypes.String, `C:\svc.exe`),
+				"service_account": planned,
+				"start_type":      tftypes.NewValue(tftypes.String, "Manual"),
+			}),
+		}
+		priorState := tfsdk.State{
+			Schema: schemaDef,
+			Raw: svcObj(map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "svc"),
+				"name":            tftypes.NewValue(tftypes.String, "svc"),
+				"binary_path":     tftypes.NewValue(tftypes.String, `C:\svc.exe`),
+				"service_account": tftypes.NewValue(tftypes.String, "LocalSystem"),
+			}),
+		}
+		resp := &resource.UpdateResponse{
+			State: tfsdk.State{Schema: schemaDef, Raw: priorState.Raw.Copy()},
+		}
+		r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: priorState}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("diags: %v", resp.Diagnostics)
+		}
+		if fake.updateIn.ServiceAccount != "" || fake.updateIn.ServicePassword != "" {
+			t.Errorf("planned %v: account/password = %q/%q, want no credential change",
+				planned, fake.updateIn.ServiceAccount, fake.updateIn.ServicePassword)
+		}
+	}
+}
+
+func TestServiceAccountChange(t *testing.T) {
+	prior := windowsServiceModel{ServiceAccount: types.StringValue(`CORP\svc-app`)}
+	cases := []struct {
+		account, password types.String
+		want              string
+	}{
+		{types.StringValue(`CORP\svc-app`), types.StringNull(), ""},
+		{types.StringValue(`corp\SVC-APP`), types.StringNull(), ""},
+		{types.StringUnknown(), types.StringNull(), ""},
+		{types.StringNull(), types.StringNull(), ""},
+		{types.StringValue(`CORP\svc-app`), types.StringValue("rotated"), `CORP\svc-app`},
+		{types.StringValue("LocalSystem"), types.StringNull(), "LocalSystem"},
+	}
+	for _, c := range cases {
+		plan := windowsServiceModel{ServiceAccount: c.account, ServicePassword: types.StringNull(), ServicePasswordWO: c.password}
+		if got := serviceAccountChange(plan, prior); got != c.want {
+			t.Errorf("serviceAccountChange(%v, pw=%v) = %q, want %q", c.account, c.password, got, c.want)
+		}
+	}
+}
+
+func TestServiceSchema_DisplayNameUsesStateForUnknown(t *testing.T) {
+	attr, ok := windowsServiceSchemaDefinition().Attributes["display_name"].(rschema.StringAttribute)
+	if !ok {
+		t.Fatal("display_name must be a StringAttribute")
+	}
+	if !attr.Optional || !attr.Computed || len(attr.PlanModifiers) == 0 {
+		t.Errorf("display_name must be Optional+Computed with UseStateForUnknown, got %+v", attr)
+	}
+}
+
 func TestDelete_Handler_HappyPath(t *testing.T) {
 	fake := &fakeSvcClient{}
 	r := &windowsServiceResource{svc: fake}
@@ -747,3 +1166,114 @@ func TestImportState_Handler(t *testing.T) {
 		t.Errorf("import state = id=%q name=%q", m.ID.ValueString(), m.Name.ValueString())
 	}
 }
+
+// -----------------------------------------------------------------------------
+// recovery
+// -----------------------------------------------------------------------------
+
+func TestRecoveryValidator_RunRequiresCommand(t *testing.T) {
+	s := windowsServiceSchemaDefinition()
+	cases := []struct {
+		name     string
+		recovery tftypes.Value
+		wantErr  bool
+	}{
+		{"unset", tftypes.NewValue(serviceRecoveryObjectType(), nil), false},
+		{"restart only", svcRecovery("Restart", "Restart", "None", 86400, 60, nil), false},
+		{"run with command", svcRecovery("Restart", "Run", "Run", 86400, 60, `C:\ops\notify.cmd`), false},
+		{"run without command", svcRecovery("Restart", "Restart", "Run", 86400, 60, nil), true},
+		{"run with unknown command", svcRecovery("Run", "None", "None", 86400, 60, tftypes.UnknownValue), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &resource.ValidateConfigResponse{}
+			serviceRecoveryValidator{}.ValidateResource(context.Background(), resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: s, Raw: svcObj(map[string]tftypes.Value{"recovery": tc.recovery})},
+			}, resp)
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("HasError = %v, want %v: %v", resp.Diagnostics.HasError(), tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestUpdate_Handler_Recovery(t *testing.T) {
+	schemaDef := windowsServiceSchemaDefinition()
+	base := map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, "svc"),
+		"name":        tftypes.NewValue(tftypes.String, "svc"),
+		"binary_path": tftypes.NewValue(tftypes.String, `C:\svc.exe`),
+		"start_type":  tftypes.NewValue(tftypes.String, "Automatic"),
+	}
+	with := func(rec tftypes.Value) tftypes.Value {
+		m := map[string]tftypes.Value{"recovery": rec}
+		for k, v := range base {
+			m[k] = v
+		}
+		return svcObj(m)
+	}
+	none := tftypes.NewValue(serviceRecoveryObjectType(), nil)
+	restart := svcRecovery("Restart", "Restart", "None", 86400, 60, nil)
+	cases := []struct {
+		name         string
+		prior, plan  tftypes.Value
+		observed     *winclient.ServiceRecovery
+		want         *winclient.ServiceRecovery
+		wantNullRead bool
+	}{
+		{"unmanaged", none, none, &winclient.ServiceRecovery{FirstAction: "Restart", SecondAction: "None", SubsequentActions: "None"}, nil, true},
+		{"unchanged", restart, restart, &winclient.ServiceRecovery{FirstAction: "Restart", SecondAction: "Restart", SubsequentActions: "None", ResetPeriodSeconds: 86400, RestartDelaySeconds: 60}, nil, false},
+		{"set", none, restart, &winclient.ServiceRecovery{FirstAction: "Restart", SecondAction: "Restart", SubsequentActions: "None", ResetPeriodSeconds: 86400, RestartDelaySeconds: 60},
+			&winclient.ServiceRecovery{FirstAction: "Restart", SecondAction: "Restart", SubsequentActions: "None", ResetPeriodSeconds: 86400, RestartDelaySeconds: 60}, false},
+		{"removed clears", restart, none, &winclient.ServiceRecovery{FirstAction: "None", SecondAction: "None", SubsequentActions: "None"},
+			&winclient.ServiceRecovery{FirstAction: "None", SecondAction: "None", SubsequentActions: "None"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := stateOK()
+			out.Recovery = tc.observed
+			fake := &fakeSvcClient{updateOut: out}
+			r := &windowsServiceResource{svc: fake}
+			prior := tfsdk.State{Schema: schemaDef, Raw: with(tc.prior)}
+			resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaDef, Raw: prior.Raw.Copy()}}
+			r.Update(context.Background(), resource.UpdateRequest{Plan: tfsdk.Plan{Schema: schemaDef, Raw: with(tc.plan)}, State: prior}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("diags: %v", resp.Diagnostics)
+			}
+			if !reflect.DeepEqual(fake.updateIn.Recovery, tc.want) {
+				t.Errorf("Recovery input = %+v, want %+v", fake.updateIn.Recovery, tc.want)
+			}
+			var m windowsServiceModel
+			resp.State.Get(context.Background(), &m)
+			if m.Recovery.IsNull() != tc.wantNullRead {
+				t.Errorf("recovery null = %v, want %v", m.Recovery.IsNull(), tc.wantNullRead)
+			}
+		})
+	}
+}
+
+func TestServiceRecoveryObject(t *testing.T) {
+	ctx := context.Background()
+	prior, _ := types.ObjectValueFrom(ctx, serviceRecoveryAttrTypes, windowsServiceRecoveryModel{
+		FirstAction: types.StringValue("None"), SecondAction: types.StringValue("None"), SubsequentActions: types.StringValue("None"),
+		ResetPeriodSeconds: types.Int64Value(86400), RestartDelaySeconds: types.Int64Value(60), Command: types.StringNull(),
+	})
+	// No actions on the host: reset period and delay keep the configured values.
+	got := serviceRecoveryObject(&winclient.ServiceRecovery{FirstAction: "None", SecondAction: "None", SubsequentActions: "None"}, prior)
+	if !got.Equal(prior) {
+		t.Errorf("no actions: got %v, want %v", got, prior)
+	}
+	// qfailure unreadable: keep prior.
+	if got := serviceRecoveryObject(nil, prior); !got.Equal(prior) {
+		t.Errorf("unreadable: got %v", got)
+	}
+	// Drift is reported.
+	got = serviceRecoveryObject(&winclient.ServiceRecovery{
+		FirstAction: "Reboot", SecondAction: "None", SubsequentActions: "None", ResetPeriodSeconds: 0, RestartDelaySeconds: 1, Command: "x.cmd",
+	}, prior)
+	var m windowsServiceRecoveryModel
+	got.As(ctx, &m, basetypes.ObjectAsOptions{})
+	if m.FirstAction.ValueString() != "Reboot" || m.ResetPeriodSeconds.ValueInt64() != 0 || m.RestartDelaySeconds.ValueInt64() != 1 || m.Command.ValueString() != "x.cmd" {
+		t.Errorf("drift: got %+v", m)
+	}
+}
@@ -0,0 +1,236 @@
+// Package provider: windows_wait_for_service resource implementation.
+//
+// windows_wait_for_service blocks on create (and again whenever name, status
+// or triggers change, which forces replacement) until a service reaches the
+// desired status, so dependent resources do not race a service that is
+// still starting. Like windows_reboot it owns nothing on the host: Read and
+// Delete are no-ops. WinRM interaction is delegated to
+// winclient.ServiceClient.WaitForStatus (internal/winclient/service_wait.go).
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+// Framework interface assertions.
+var (
+	_ resource.Resource              = (*windowsWaitForServiceResource)(nil)
+	_ resource.ResourceWithConfigure = (*windowsWaitForServiceResource)(nil)
+)
+
+// defaultWaitForServiceTimeout is the default for the timeout attribute.
+const defaultWaitForServiceTimeout = "5m"
+
+// NewWindowsWaitForServiceResource is the constructor registered in
+// provider.go.
+func NewWindowsWaitForServiceResource() resource.Resource {
+	return &windowsWaitForServiceResource{}
+}
+
+// windowsWaitForServiceResource is the TPF resource type for
+// windows_wait_for_service.
+type windowsWaitForServiceResource struct {
+	client winclient.WindowsServiceWaiter
+}
+
+// windowsWaitForServiceModel is the Terraform state/plan model for
+// windows_wait_for_service.
+type windowsWaitForServiceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	Status     types.String `tfsdk:"status"`
+	Timeout    types.String `tfsdk:"timeout"`
+	Triggers   types.Map    `tfsdk:"triggers"`
+	LastStatus types.String `tfsdk:"last_status"`
+}
+
+// windowsWaitForServiceSchemaDefinition returns the schema.Schema for
+// windows_wait_for_service.
+func windowsWaitForServiceSchemaDefinition() schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Waits until a Windows service reaches `status`, polling `Get-Service` every few " +
+			"seconds. The wait happens when the resource is created and whenever `name`, `status` or `triggers` " +
+			"changes; destroying the resource does nothing on the host.\n\n" +
+			"A service that does not exist yet (e.g. still being installed by a feature or package) is polled " +
+			"again, as are transient connection errors. If `timeout` elapses first, the apply fails with the " +
+			"last observed status.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Equal to `name`.",
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 256),
+				},
+				MarkdownDescription: "Short name of the service to wait for (e.g. `W3SVC`). **ForceNew.**",
+			},
+			"status": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("Running"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("Running", "Stopped", "Paused"),
+				},
+				MarkdownDescription: "Status to wait for: `Running`, `Stopped` or `Paused`. Default: `Running`. **ForceNew.**",
+			},
+			"timeout": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultWaitForServiceTimeout),
+				Validators: []validator.String{
+					rebootTimeoutValidator{},
+				},
+				MarkdownDescription: "How long to wait for the service, as a Go duration string (e.g. `10m`). " +
+					"Default: `" + defaultWaitForServiceTimeout + "`.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Arbitrary map of values that, when changed, wait again. Reference attributes " +
+					"of upstream resources (e.g. a service's `id`) to wait after they change. **ForceNew.**",
+			},
+			"last_status": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Status observed when the wait ended; equal to `status` after a successful apply.",
+			},
+		},
+	}
+}
+
+// Metadata sets the resource type name ("windows_wait_for_service").
+func (r *windowsWaitForServiceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wait_for_service"
+}
+
+// Schema returns the full TPF schema for windows_wait_for_service.
+func (r *windowsWaitForServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = windowsWaitForServiceSchemaDefinition()
+}
+
+// Configure extracts the shared *winclient.Client from provider data.
+func (r *windowsWaitForServiceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*winclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data type",
+			fmt.Sprintf("Expected *winclient.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.client = winclient.NewServiceClient(c)
+}
+
+// Create waits for the service to reach the desired status.
+func (r *windowsWaitForServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan windowsWaitForServiceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, err := time.ParseDuration(plan.Timeout.ValueString())
+	if err != nil || timeout <= 0 {
+		resp.Diagnostics.AddAttributeError(pathAttr("timeout"), "Invalid timeout",
+			fmt.Sprintf("Expected a positive Go duration string such as 5m or 1h, got %q.", plan.Timeout.ValueString()))
+		return
+	}
+
+	name, status := plan.Name.ValueString(), plan.Status.ValueString()
+	tflog.Info(ctx, "windows_wait_for_service Create: waiting for service", map[string]interface{}{
+		"name": name, "status": status, "timeout": timeout.String(),
+	})
+
+	got, err := r.client.WaitForStatus(ctx, name, status, timeout)
+	if err != nil {
+		addWaitForServiceDiag(&resp.Diagnostics, err)
+		return
+	}
+
+	plan.ID = plan.Name
+	plan.LastStatus = types.StringValue(got)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the recorded state: the service leaving the status later is not
+// drift, the wait already served its purpose.
+func (r *windowsWaitForServiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state windowsWaitForServiceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only records a timeout change; every other attribute is ForceNew,
+// so the wait is not run again.
+func (r *windowsWaitForServiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state windowsWaitForServiceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = state.ID
+	plan.LastStatus = state.LastStatus
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state.
+func (r *windowsWaitForServiceResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	tflog.Debug(ctx, "windows_wait_for_service Delete: nothing to do on the host")
+}
+
+// addWaitForServiceDiag adds a Terraform diagnostic from a WaitForStatus
+// error. A timeout is attached to the timeout attribute.
+func addWaitForServiceDiag(diags *diag.Diagnostics, err error) {
+	var se *winclient.ServiceError
+	if errors.As(err, &se) && se.Kind == winclient.ServiceErrorTimeout {
+		detail := se.Message + "."
+		if se.Context["last_status"] == winclient.ServiceStatusNotFound {
+			detail += " The service does not exist on the host; check name, or make this resource depend on " +
+				"the resource that installs it."
+		} else {
+			detail += " Increase timeout if the service needs longer, or check its event log entries."
+		}
+		diags.AddAttributeError(pathAttr("timeout"), "Service did not reach the desired status in time", detail)
+		return
+	}
+	addServiceDiag(diags, "Wait for service failed", err)
+}
@@ -0,0 +1,65 @@
+//go:build acceptance
+
+// Package provider — acceptance tests for windows_wait_for_service.
+//
+// Requires: TF_ACC=1, WINDOWS_HOST, WINDOWS_USERNAME, WINDOWS_PASSWORD.
+// Run with: go test -tags acceptance ./internal/provider/ -run TestAccWindowsWaitForService
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccWindowsWaitForService_Basic — wait for a service that is always
+// running (RPC endpoint mapper), then a no-op plan.
+func TestAccWindowsWaitForService_Basic(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+
+	cfg := `
+resource "windows_wait_for_service" "test" {
+  name    = "RpcSs"
+  timeout = "1m"
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("windows_wait_for_service.test", "id", "RpcSs"),
+					resource.TestCheckResourceAttr("windows_wait_for_service.test", "status", "Running"),
+					resource.TestCheckResourceAttr("windows_wait_for_service.test", "last_status", "Running"),
+				),
+			},
+			{
+				Config:   cfg,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccWindowsWaitForService_Timeout — a service that does not exist times
+// out with the last observed status in the error.
+func TestAccWindowsWaitForService_Timeout(t *testing.T) {
+	testAccEnvVarPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "windows_wait_for_service" "test" {
+  name    = "tf-acc-no-such-service"
+  timeout = "15s"
+}
+`,
+				ExpectError: regexp.MustCompile(`last observed status: NotFound`),
+			},
+		},
+	})
+}
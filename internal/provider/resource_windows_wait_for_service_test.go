@@ -0,0 +1,162 @@
+// Package provider — unit tests for the windows_wait_for_service resource.
+//
+// A fakeServiceWaiter is injected into windowsWaitForServiceResource.client,
+// so no WinRM connection is required.
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+type fakeServiceWaiter struct {
+	out string
+	err error
+
+	calls       int
+	lastName    string
+	lastStatus  string
+	lastTimeout time.Duration
+}
+
+func (f *fakeServiceWaiter) WaitForStatus(_ context.Context, name, status string, timeout time.Duration) (string, error) {
+	f.calls++
+	f.lastName, f.lastStatus, f.lastTimeout = name, status, timeout
+	return f.out, f.err
+}
+
+func wfsObjectType() tftypes.Object {
+	return tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":          tftypes.String,
+		"name":        tftypes.String,
+		"status":      tftypes.String,
+		"timeout":     tftypes.String,
+		"triggers":    tftypes.Map{ElementType: tftypes.String},
+		"last_status": tftypes.String,
+	}}
+}
+
+func wfsObj(overrides map[string]tftypes.Value) tftypes.Value {
+	base := map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, "W3SVC"),
+		"name":        tftypes.NewValue(tftypes.String, "W3SVC"),
+		"status":      tftypes.NewValue(tftypes.String, "Running"),
+		"timeout":     tftypes.NewValue(tftypes.String, "5m"),
+		"triggers":    tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+		"last_status": tftypes.NewValue(tftypes.String, "Running"),
+	}
+	for k, v := range overrides {
+		base[k] = v
+	}
+	return tftypes.NewValue(wfsObjectType(), base)
+}
+
+func wfsState(overrides map[string]tftypes.Value) tfsdk.State {
+	return tfsdk.State{Raw: wfsObj(overrides), Schema: windowsWaitForServiceSchemaDefinition()}
+}
+
+func wfsCreate(t *testing.T, r *windowsWaitForServiceResource, overrides map[string]tftypes.Value) (*resource.CreateResponse, windowsWaitForServiceModel) {
+	t.Helper()
+	overrides["id"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	overrides["last_status"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	s := windowsWaitForServiceSchemaDefinition()
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(wfsObjectType(), nil)}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: wfsObj(overrides)}}, resp)
+	var got windowsWaitForServiceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	}
+	return resp, got
+}
+
+func TestWaitForServiceSchema(t *testing.T) {
+	s := windowsWaitForServiceSchemaDefinition()
+	for _, name := range []string{"id", "name", "status", "timeout", "triggers", "last_status"} {
+		if _, ok := s.Attributes[name]; !ok {
+			t.Errorf("schema missing attribute %q", name)
+		}
+	}
+	if !s.Attributes["name"].IsRequired() || !s.Attributes["last_status"].IsComputed() {
+		t.Error("name must be required and last_status computed")
+	}
+}
+
+func TestWaitForServiceCreate(t *testing.T) {
+	fake := &fakeServiceWaiter{out: "Stopped"}
+	r := &windowsWaitForServiceResource{client: fake}
+	resp, got := wfsCreate(t, r, map[string]tftypes.Value{
+		"status":  tftypes.NewValue(tftypes.String, "Stopped"),
+		"timeout": tftypes.NewValue(tftypes.String, "90s"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if fake.calls != 1 || fake.lastName != "W3SVC" || fake.lastStatus != "Stopped" || fake.lastTimeout != 90*time.Second {
+		t.Errorf("WaitForStatus called %d times with %q %q %s", fake.calls, fake.lastName, fake.lastStatus, fake.lastTimeout)
+	}
+	if got.ID.ValueString() != "W3SVC" || got.LastStatus.ValueString() != "Stopped" {
+		t.Errorf("state = %+v", got)
+	}
+}
+
+func TestWaitForServiceCreate_Timeout(t *testing.T) {
+	for last, hint := range map[string]string{
+		"StartPending":                  "Increase timeout",
+		winclient.ServiceStatusNotFound: "does not exist on the host",
+	} {
+		fake := &fakeServiceWaiter{err: winclient.NewServiceError(winclient.ServiceErrorTimeout,
+			`service "W3SVC" did not reach Running within 5m0s (last observed status: `+last+`)`, nil,
+			map[string]string{"last_status": last})}
+		r := &windowsWaitForServiceResource{client: fake}
+		resp, _ := wfsCreate(t, r, map[string]tftypes.Value{})
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "last observed status: "+last) ||
+			!strings.Contains(resp.Diagnostics[0].Detail(), hint) {
+			t.Errorf("%s: diagnostics = %v", last, resp.Diagnostics)
+		}
+		if !resp.State.Raw.IsNull() {
+			t.Error("a failed wait must not be recorded in state")
+		}
+	}
+
+	fake := &fakeServiceWaiter{err: winclient.NewServiceError(winclient.ServiceErrorPermission, "Access is denied", nil, nil)}
+	resp, _ := wfsCreate(t, &windowsWaitForServiceResource{client: fake}, map[string]tftypes.Value{})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Kind: permission_denied") {
+		t.Errorf("diagnostics = %v", resp.Diagnostics)
+	}
+}
+
+func TestWaitForServiceUpdateReadDelete_NoHostCalls(t *testing.T) {
+	fake := &fakeServiceWaiter{}
+	r := &windowsWaitForServiceResource{client: fake}
+
+	s := windowsWaitForServiceSchemaDefinition()
+	upd := &resource.UpdateResponse{State: wfsState(nil)}
+	r.Update(context.Background(), resource.UpdateRequest{
+		Plan: tfsdk.Plan{Schema: s, Raw: wfsObj(map[string]tftypes.Value{
+			"timeout":     tftypes.NewValue(tftypes.String, "10m"),
+			"last_status": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		})},
+		State: wfsState(nil),
+	}, upd)
+	var got windowsWaitForServiceModel
+	upd.State.Get(context.Background(), &got)
+	if upd.Diagnostics.HasError() || got.Timeout.ValueString() != "10m" || got.LastStatus.ValueString() != "Running" {
+		t.Errorf("Update: %v, state = %+v", upd.Diagnostics, got)
+	}
+
+	readResp := &resource.ReadResponse{State: wfsState(nil)}
+	r.Read(context.Background(), resource.ReadRequest{State: wfsState(nil)}, readResp)
+	delResp := &resource.DeleteResponse{State: wfsState(nil)}
+	r.Delete(context.Background(), resource.DeleteRequest{State: wfsState(nil)}, delResp)
+	if readResp.Diagnostics.HasError() || delResp.Diagnostics.HasError() || fake.calls != 0 {
+		t.Errorf("Read/Delete must not touch the host: calls=%d", fake.calls)
+	}
+}
@@ -57,6 +57,9 @@ func NewWindowsWingetPackageResource() resource.Resource {
 // windowsWingetPackageResource is the TPF resource type for windows_winget_package.
 type windowsWingetPackageResource struct {
 	wp winclient.WingetPackageClient
+	// defaultTimeout is the provider default_command_timeout (0 = unset,
+	// wpDefaultTimeout applies).
+	defaultTimeout time.Duration
 }
 
 // ---------------------------------------------------------------------------
@@ -244,6 +247,7 @@ func (r *windowsWingetPackageResource) Configure(_ context.Context, req resource
 		return
 	}
 	r.wp = winclient.NewWingetPackageClient(c)
+	r.defaultTimeout = c.DefaultCommandTimeout()
 }
 
 // ConfigValidators returns resource-level cross-field validators.
@@ -267,7 +271,7 @@ func (r *windowsWingetPackageResource) Create(ctx context.Context, req resource.
 	}
 
 	// Apply per-operation timeout (defaults to wpDefaultTimeout when unset).
-	createTimeout, diags := plan.Timeouts.Create(ctx, wpDefaultTimeout)
+	createTimeout, diags := plan.Timeouts.Create(ctx, operationTimeout(r.defaultTimeout, wpDefaultTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -369,7 +373,7 @@ func (r *windowsWingetPackageResource) Update(ctx context.Context, req resource.
 		return
 	}
 
-	updateTimeout, diags := plan.Timeouts.Update(ctx, wpDefaultTimeout)
+	updateTimeout, diags := plan.Timeouts.Update(ctx, operationTimeout(r.defaultTimeout, wpDefaultTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -429,7 +433,7 @@ func (r *windowsWingetPackageResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
-	deleteTimeout, diags := state.Timeouts.Delete(ctx, wpDefaultTimeout)
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, operationTimeout(r.defaultTimeout, wpDefaultTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
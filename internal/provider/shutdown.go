@@ -0,0 +1,111 @@
+// Package provider: graceful shutdown of the configured WinRM clients.
+//
+// Terraform may configure the provider more than once in a process (one
+// Configure per provider alias), so every client built by Configure is
+// registered here, keyed by the provider instance that built it. A second
+// Configure of the same instance retires the client it replaces: that client
+// refuses new runs and is closed once its in-flight runs return.
+//
+// main starts Shutdown through ShutdownOnDone as soon as the plugin receives
+// SIGTERM, while providerserver.Serve is still serving, so the grace window
+// covers the RPCs in flight instead of starting after they are gone, and
+// once Serve returns. SIGINT does not drain: Terraform still needs the
+// clients to finish an interrupted apply.
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kfrlabs/terraform-provider-windows/internal/winclient"
+)
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[*windowsProvider]*winclient.Client{}
+	// retiring holds the clients replaced by a re-Configure until their
+	// in-flight runs have returned.
+	retiring = map[*winclient.Client]struct{}{}
+)
+
+// registerClient records the client p's Configure built so Shutdown can
+// drain it. A client p registered before is retired.
+func registerClient(p *windowsProvider, c *winclient.Client) {
+	clientsMu.Lock()
+	old := clients[p]
+	clients[p] = c
+	if old != nil && old != c {
+		retiring[old] = struct{}{}
+	}
+	clientsMu.Unlock()
+
+	if old != nil && old != c {
+		go retireClient(old)
+	}
+}
+
+// retireClient closes a replaced client once its runs have returned. The
+// runs are bounded by their own timeouts; Shutdown cancels them sooner.
+func retireClient(c *winclient.Client) {
+	_ = c.CloseGraceful(context.Background())
+	clientsMu.Lock()
+	delete(retiring, c)
+	clientsMu.Unlock()
+}
+
+// Shutdown gracefully closes every client configured in this process: new
+// runs are refused, in-flight runs are given until ctx ends to finish, and
+// any still running then are cancelled. The returned error joins the
+// per-client close errors.
+func Shutdown(ctx context.Context) error {
+	clientsMu.Lock()
+	list := make([]*winclient.Client, 0, len(clients)+len(retiring))
+	for _, c := range clients {
+		list = append(list, c)
+	}
+	for c := range retiring {
+		list = append(list, c)
+	}
+	clients = map[*windowsProvider]*winclient.Client{}
+	clientsMu.Unlock()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for _, c := range list {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.CloseGraceful(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// ShutdownOnDone runs Shutdown, bounded by grace, once ctx is done. The
+// returned function waits for that Shutdown and returns its error; it may be
+// called more than once.
+func ShutdownOnDone(ctx context.Context, grace time.Duration) func() error {
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), grace)
+		defer cancel()
+		err = Shutdown(sctx)
+	}()
+	return func() error {
+		<-done
+		return err
+	}
+}
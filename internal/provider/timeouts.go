@@ -0,0 +1,15 @@
+// Package provider: shared per-operation timeout defaults.
+package provider
+
+import "time"
+
+// operationTimeout returns the fallback passed to timeouts.Value
+// Create/Update/Delete: the provider-level default_command_timeout when it is
+// set, otherwise the resource's built-in default. An explicit timeouts {}
+// block in the resource still takes precedence over both.
+func operationTimeout(providerDefault, builtin time.Duration) time.Duration {
+	if providerDefault > 0 {
+		return providerDefault
+	}
+	return builtin
+}
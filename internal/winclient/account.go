@@ -0,0 +1,237 @@
+// Package winclient: SID <-> account name translation over WinRM.
+//
+// AccountClient is the concrete WindowsAccountClient backing the
+// windows_account_translate data source. Translation runs on the remote host
+// through [System.Security.Principal.SecurityIdentifier] and NTAccount, so
+// local accounts and the domains the host trusts resolve as they would for
+// any program on that host. IdentityNotMappedException (an orphaned or
+// unknown SID or name) is caught and reported as not_found.
+//
+// Security invariants:
+//   - The SID or name is interpolated only through psQuote.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Compile-time assertion: AccountClient satisfies WindowsAccountClient.
+var _ WindowsAccountClient = (*AccountClient)(nil)
+
+// AccountClient is the PowerShell/WinRM-backed WindowsAccountClient.
+type AccountClient struct {
+	c *Client
+}
+
+// NewAccountClient wraps the given WinRM Client.
+func NewAccountClient(c *Client) *AccountClient { return &AccountClient{c: c} }
+
+// runAccountPowerShell is the package-level indirection used by
+// AccountClient. Tests may override it; production code must not.
+var runAccountPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// accountPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type accountPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// accountPayload mirrors the object emitted by Resolve-Account.
+type accountPayload struct {
+	SID         string `json:"sid"`
+	AccountName string `json:"account_name"`
+	Domain      string `json:"domain"`
+	AccountType string `json:"account_type"`
+}
+
+// psAccountHeader prepends Emit-OK/Emit-Err, Classify-Account and
+// Resolve-Account. Resolve-Account translates a SID (-Sid) or a name (-Name)
+// to the SecurityIdentifier, then back to the canonical NTAccount, and looks
+// up the SID_NAME_USE in Win32_Account: first among local accounts by SID
+// (cheap), then by Domain and Name. A failed type lookup only leaves
+// account_type as Unknown.
+const psAccountHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Classify-Account([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied') { return 'permission_denied' }
+  return 'unknown'
+}
+function Get-SidTypeName([int]$t) {
+  switch ($t) {
+    1 { 'User' } 2 { 'Group' } 3 { 'Domain' } 4 { 'Alias' } 5 { 'WellKnownGroup' }
+    6 { 'DeletedAccount' } 7 { 'Invalid' } 9 { 'Computer' } default { 'Unknown' }
+  }
+}
+function Resolve-Account([string]$Sid, [string]$Name) {
+  try {
+    if ($Sid) {
+      $sidObj = New-Object System.Security.Principal.SecurityIdentifier($Sid)
+    } else {
+      $sidObj = (New-Object System.Security.Principal.NTAccount($Name)).Translate([System.Security.Principal.SecurityIdentifier])
+    }
+    $acct = [string]$sidObj.Translate([System.Security.Principal.NTAccount]).Value
+  } catch [System.Security.Principal.IdentityNotMappedException] {
+    Emit-Err 'not_found' $_.Exception.Message @{ sid = $Sid; account_name = $Name }
+    return
+  } catch [System.ArgumentException] {
+    Emit-Err 'invalid_parameter' $_.Exception.Message @{ sid = $Sid; account_name = $Name }
+    return
+  } catch {
+    $msg = $_.Exception.Message
+    if ($_.Exception.InnerException -is [System.Security.Principal.IdentityNotMappedException]) {
+      Emit-Err 'not_found' $msg @{ sid = $Sid; account_name = $Name }
+      return
+    }
+    Emit-Err (Classify-Account $msg) $msg @{ sid = $Sid; account_name = $Name }
+    return
+  }
+  $domain = ''
+  $short  = $acct
+  $i = $acct.IndexOf('\')
+  if ($i -ge 0) { $domain = $acct.Substring(0, $i); $short = $acct.Substring($i + 1) }
+  $type = 'Unknown'
+  try {
+    $wql = { param($s) $s.Replace('\', '\\').Replace("'", "\'") }
+    $a = Get-CimInstance -ClassName Win32_Account -Filter ("SID='" + (& $wql $sidObj.Value) + "' AND LocalAccount=True") -ErrorAction Stop | Select-Object -First 1
+    if (-not $a -and $domain) {
+      $a = Get-CimInstance -ClassName Win32_Account -Filter ("Domain='" + (& $wql $domain) + "' AND Name='" + (& $wql $short) + "'") -ErrorAction Stop | Select-Object -First 1
+    }
+    if ($a) { $type = Get-SidTypeName ([int]$a.SIDType) }
+  } catch { }
+  Emit-OK ([ordered]@{
+    sid          = [string]$sidObj.Value
+    account_name = $acct
+    domain       = $domain
+    account_type = $type
+  })
+}
+`
+
+// runAccountEnvelope executes script (prepended with psAccountHeader) and
+// parses the JSON envelope. Cancellation maps to AccountErrorTimeout; other
+// transport failures to AccountErrorUnknown.
+func (a *AccountClient) runAccountEnvelope(ctx context.Context, op, key, script string) (*accountPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psAccountHeader + "\n" + script
+	stdout, stderr, err := runAccountPowerShell(ctx, a.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"key":       key,
+		"host":      a.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewAccountError(AccountErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewAccountError(AccountErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewAccountError(AccountErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp accountPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewAccountError(AccountErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewAccountError(mapAccountKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapAccountKind translates a PS-side "kind" string to a typed
+// AccountErrorKind. Unknown values fall through to AccountErrorUnknown.
+func mapAccountKind(k string) AccountErrorKind {
+	switch k {
+	case string(AccountErrorNotFound),
+		string(AccountErrorInvalidParameter),
+		string(AccountErrorPermission),
+		string(AccountErrorTimeout):
+		return AccountErrorKind(k)
+	default:
+		return AccountErrorUnknown
+	}
+}
+
+// lookup runs Resolve-Account with the given parameter and decodes the
+// result.
+func (a *AccountClient) lookup(ctx context.Context, op, param, key string) (*Account, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, NewAccountError(AccountErrorInvalidParameter, op+": value is empty", nil, nil)
+	}
+	script := fmt.Sprintf("Resolve-Account %s %s\n", param, psQuote(key))
+	resp, err := retryTransientRead(ctx, func() (*accountPSResponse, error) {
+		return a.runAccountEnvelope(ctx, op, key, script)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var pl accountPayload
+	if jerr := json.Unmarshal(resp.Data, &pl); jerr != nil || pl.SID == "" {
+		return nil, NewAccountError(AccountErrorUnknown,
+			"failed to parse account payload", jerr,
+			map[string]string{"key": key, "host": a.c.cfg.Host})
+	}
+	if pl.AccountType == "" {
+		pl.AccountType = "Unknown"
+	}
+	return &Account{
+		SID:         pl.SID,
+		AccountName: pl.AccountName,
+		Domain:      pl.Domain,
+		AccountType: pl.AccountType,
+	}, nil
+}
+
+// LookupSID implements WindowsAccountClient.LookupSID.
+func (a *AccountClient) LookupSID(ctx context.Context, sid string) (*Account, error) {
+	return a.lookup(ctx, "lookup_sid", "-Sid", sid)
+}
+
+// LookupName implements WindowsAccountClient.LookupName.
+func (a *AccountClient) LookupName(ctx context.Context, name string) (*Account, error) {
+	return a.lookup(ctx, "lookup_name", "-Name", name)
+}
@@ -0,0 +1,113 @@
+// Package winclient — unit tests for AccountClient.
+//
+// These tests stub the package-level seam runAccountPowerShell to inject
+// scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubAccountRun replaces runAccountPowerShell for the duration of a test and
+// returns a restore function (typically deferred).
+func stubAccountRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runAccountPowerShell
+	runAccountPowerShell = fn
+	return func() { runAccountPowerShell = prev }
+}
+
+func accountStdout(out string, script *string) func(context.Context, *Client, string) (string, string, error) {
+	return func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if script != nil {
+			*script = s
+		}
+		return out + "\n", "", nil
+	}
+}
+
+func TestAccountLookupSID_HappyPath(t *testing.T) {
+	var script string
+	defer stubAccountRun(accountStdout(`{"ok":true,"data":{"sid":"S-1-5-32-544","account_name":"BUILTIN\\Administrators","domain":"BUILTIN","account_type":"Alias"}}`, &script))()
+	got, err := NewAccountClient(newLouTestClient(t)).LookupSID(context.Background(), "S-1-5-32-544")
+	if err != nil {
+		t.Fatalf("LookupSID: %v", err)
+	}
+	want := Account{SID: "S-1-5-32-544", AccountName: `BUILTIN\Administrators`, Domain: "BUILTIN", AccountType: "Alias"}
+	if *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+	if !strings.Contains(script, "Resolve-Account -Sid 'S-1-5-32-544'") {
+		t.Errorf("script does not resolve by SID:\n%s", script)
+	}
+}
+
+func TestAccountLookupName_QuotesAndDefaultsType(t *testing.T) {
+	var script string
+	defer stubAccountRun(accountStdout(`{"ok":true,"data":{"sid":"S-1-1-0","account_name":"Everyone","domain":"","account_type":""}}`, &script))()
+	got, err := NewAccountClient(newLouTestClient(t)).LookupName(context.Background(), "O'Brien")
+	if err != nil {
+		t.Fatalf("LookupName: %v", err)
+	}
+	if got.AccountType != "Unknown" || got.Domain != "" {
+		t.Errorf("unexpected account: %+v", got)
+	}
+	if !strings.Contains(script, "Resolve-Account -Name 'O''Brien'") {
+		t.Errorf("name not quoted:\n%s", script)
+	}
+}
+
+func TestAccountLookup_NotMapped(t *testing.T) {
+	defer stubAccountRun(accountStdout(`{"ok":false,"kind":"not_found","message":"Some or all identity references could not be translated.","context":{"sid":"S-1-5-21-1-2-3-1001"}}`, nil))()
+	_, err := NewAccountClient(newLouTestClient(t)).LookupSID(context.Background(), "S-1-5-21-1-2-3-1001")
+	if !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("want not_found, got %v", err)
+	}
+	var ae *AccountError
+	if !errors.As(err, &ae) || ae.Context["sid"] != "S-1-5-21-1-2-3-1001" || ae.Context["host"] != "win01" {
+		t.Errorf("context not merged: %+v", ae)
+	}
+}
+
+func TestAccountLookup_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		out  string
+		err  error
+		want AccountErrorKind
+	}{
+		{"invalid", `{"ok":false,"kind":"invalid_parameter","message":"Value was invalid."}`, nil, AccountErrorInvalidParameter},
+		{"odd kind", `{"ok":false,"kind":"bogus","message":"x"}`, nil, AccountErrorUnknown},
+		{"no envelope", "garbage", nil, AccountErrorUnknown},
+		{"bad payload", `{"ok":true,"data":{}}`, nil, AccountErrorUnknown},
+		{"transport", "", errors.New("connection refused"), AccountErrorUnknown},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer stubAccountRun(func(context.Context, *Client, string) (string, string, error) {
+				return tc.out, "", tc.err
+			})()
+			_, err := NewAccountClient(newLouTestClient(t)).LookupSID(context.Background(), "S-1-5-18")
+			if !IsAccountError(err, tc.want) {
+				t.Errorf("want %s, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestAccountLookup_EmptyAndCancelled(t *testing.T) {
+	defer stubAccountRun(func(context.Context, *Client, string) (string, string, error) {
+		return "", "", errors.New("canceled")
+	})()
+	ac := NewAccountClient(newLouTestClient(t))
+	if _, err := ac.LookupName(context.Background(), "  "); !IsAccountError(err, AccountErrorInvalidParameter) {
+		t.Errorf("empty name: want invalid_parameter, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ac.LookupSID(ctx, "S-1-5-18"); !IsAccountError(err, AccountErrorTimeout) {
+		t.Errorf("cancelled: want timeout, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package winclient: types for the windows_account_translate data source.
+//
+// Account is one Windows security principal as both a SID and an account
+// name. AccountErrorKind / AccountError follow the same shape as
+// PendingRebootError.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AccountErrorKind categorises errors returned by WindowsAccountClient.
+type AccountErrorKind string
+
+const (
+	// AccountErrorNotFound: the SID or name does not map to an account
+	// (IdentityNotMappedException), e.g. a deleted domain account.
+	AccountErrorNotFound AccountErrorKind = "not_found"
+	// AccountErrorInvalidParameter: the SID is not well formed.
+	AccountErrorInvalidParameter AccountErrorKind = "invalid_parameter"
+	AccountErrorPermission       AccountErrorKind = "permission_denied"
+	AccountErrorTimeout          AccountErrorKind = "timeout"
+	AccountErrorUnknown          AccountErrorKind = "unknown"
+)
+
+// AccountError is the structured error type returned by
+// WindowsAccountClient.
+type AccountError struct {
+	Kind    AccountErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *AccountError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_account_translate [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_account_translate [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *AccountError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *AccountError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*AccountError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewAccountError constructs a *AccountError.
+func NewAccountError(kind AccountErrorKind, msg string, cause error, ctx map[string]string) *AccountError {
+	return &AccountError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsAccountError reports whether err is a *AccountError of the given kind.
+func IsAccountError(err error, kind AccountErrorKind) bool {
+	var ae *AccountError
+	if errors.As(err, &ae) {
+		return ae.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrAccountNotFound         = &AccountError{Kind: AccountErrorNotFound}
+	ErrAccountInvalidParameter = &AccountError{Kind: AccountErrorInvalidParameter}
+	ErrAccountPermission       = &AccountError{Kind: AccountErrorPermission}
+	ErrAccountTimeout          = &AccountError{Kind: AccountErrorTimeout}
+	ErrAccountUnknown          = &AccountError{Kind: AccountErrorUnknown}
+)
+
+// Account is a Windows security principal resolved on the remote host.
+type Account struct {
+	// SID is the security identifier (e.g. S-1-5-32-544).
+	SID string
+	// AccountName is the NTAccount form, DOMAIN\name, or the bare name for
+	// principals without a domain part (e.g. Everyone).
+	AccountName string
+	// Domain is the part before the backslash; empty when there is none.
+	Domain string
+	// AccountType is the SID_NAME_USE of the account as reported by
+	// Win32_Account.SIDType: User, Group, Domain, Alias, WellKnownGroup,
+	// DeletedAccount, Invalid, Computer, or Unknown when it could not be
+	// determined.
+	AccountType string
+}
+
+// WindowsAccountClient is the contract for the windows_account_translate
+// data source.
+type WindowsAccountClient interface {
+	// LookupSID resolves a SID to its account. Unmappable SIDs return
+	// AccountErrorNotFound; malformed ones AccountErrorInvalidParameter.
+	LookupSID(ctx context.Context, sid string) (*Account, error)
+	// LookupName resolves an account name (DOMAIN\name, name@domain or a
+	// bare name) to its account, with AccountName in canonical DOMAIN\name
+	// form. Unknown names return AccountErrorNotFound.
+	LookupName(ctx context.Context, name string) (*Account, error)
+}
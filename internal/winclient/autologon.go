@@ -0,0 +1,290 @@
+// Package winclient: Winlogon automatic logon over WinRM.
+//
+// AutologonClient is the concrete WindowsAutologonClient backing the
+// windows_autologon resource. It manages the documented Winlogon values
+// (AutoAdminLogon, DefaultUserName, DefaultDomainName, DefaultPassword,
+// AutoLogonCount) under HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Winlogon.
+//
+// Security invariants:
+//   - The input, including the password, is sent as a JSON document on stdin
+//     through Client.RunPowerShellWithInput; nothing is interpolated into the
+//     script body and the password never appears in error context.
+//   - Read never returns DefaultPassword, only whether it is present.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Compile-time assertion: AutologonClient satisfies WindowsAutologonClient.
+var _ WindowsAutologonClient = (*AutologonClient)(nil)
+
+// AutologonClient is the PowerShell/WinRM-backed WindowsAutologonClient.
+type AutologonClient struct {
+	c *Client
+}
+
+// NewAutologonClient wraps the given WinRM Client.
+func NewAutologonClient(c *Client) *AutologonClient { return &AutologonClient{c: c} }
+
+// runAutologonPowerShell is the package-level indirection used by
+// AutologonClient. Tests may override it; production code must not.
+var runAutologonPowerShell = func(ctx context.Context, c *Client, script, stdin string) (string, string, error) {
+	return c.RunPowerShellWithInput(ctx, script, stdin)
+}
+
+// autologonPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type autologonPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// autologonPayload is the stdin document consumed by psSetAutologon.
+type autologonPayload struct {
+	Username       string `json:"username"`
+	Domain         string `json:"domain"`
+	Password       string `json:"password"`
+	AutoLogonCount *int64 `json:"auto_logon_count"`
+}
+
+// autologonStatePayload is the "data" object emitted by Get-AlState.
+type autologonStatePayload struct {
+	Enabled        bool   `json:"enabled"`
+	Username       string `json:"username"`
+	Domain         string `json:"domain"`
+	AutoLogonCount *int64 `json:"auto_logon_count"`
+	PasswordSet    bool   `json:"password_set"`
+}
+
+// psAutologonHeader prepends Emit-OK/Emit-Err, Classify-Autologon and the
+// shared Winlogon helpers.
+const psAutologonHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+$WarningPreference     = 'SilentlyContinue'
+
+$AlKey = 'HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Winlogon'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Classify-Autologon([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'Requested registry access is not allowed' -or $Msg -match 'UnauthorizedAccess') { return 'permission_denied' }
+  return 'unknown'
+}
+function Read-AlInput {
+  $raw = [Console]::In.ReadToEnd()
+  if ([string]::IsNullOrWhiteSpace($raw)) { return [pscustomobject]@{} }
+  return ($raw | ConvertFrom-Json)
+}
+function Get-AlState {
+  $p = Get-ItemProperty -LiteralPath $AlKey
+  $names = @($p.PSObject.Properties | ForEach-Object { $_.Name })
+  $count = $null
+  if ($names -contains 'AutoLogonCount') { $count = [int64]$p.AutoLogonCount }
+  return [ordered]@{
+    enabled          = ([string]$p.AutoAdminLogon -eq '1')
+    username         = [string]$p.DefaultUserName
+    domain           = [string]$p.DefaultDomainName
+    auto_logon_count = $count
+    password_set     = ($names -contains 'DefaultPassword')
+  }
+}
+`
+
+// psSetAutologon writes the Winlogon values from the stdin document. An
+// empty domain defaults to the local computer name, which is what Winlogon
+// expects for a local account. AutoAdminLogon is written last so a failure
+// part-way never leaves automatic logon enabled with stale credentials.
+const psSetAutologon = `
+try {
+  $cfg = Read-AlInput
+  $domain = [string]$cfg.domain
+  if (-not $domain) { $domain = $env:COMPUTERNAME }
+  New-ItemProperty -LiteralPath $AlKey -Name 'DefaultUserName'   -Value ([string]$cfg.username) -PropertyType String -Force | Out-Null
+  New-ItemProperty -LiteralPath $AlKey -Name 'DefaultDomainName' -Value $domain                 -PropertyType String -Force | Out-Null
+  New-ItemProperty -LiteralPath $AlKey -Name 'DefaultPassword'   -Value ([string]$cfg.password) -PropertyType String -Force | Out-Null
+  if ($null -ne $cfg.auto_logon_count) {
+    New-ItemProperty -LiteralPath $AlKey -Name 'AutoLogonCount' -Value ([int]$cfg.auto_logon_count) -PropertyType DWord -Force | Out-Null
+  } else {
+    Remove-ItemProperty -LiteralPath $AlKey -Name 'AutoLogonCount' -ErrorAction SilentlyContinue
+  }
+  New-ItemProperty -LiteralPath $AlKey -Name 'AutoAdminLogon' -Value '1' -PropertyType String -Force | Out-Null
+  Emit-OK (Get-AlState)
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Autologon $msg) $msg @{}
+}
+`
+
+// psReadAutologon reports the current configuration.
+const psReadAutologon = `
+try {
+  Emit-OK (Get-AlState)
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Autologon $msg) $msg @{}
+}
+`
+
+// psDisableAutologon removes AutoAdminLogon and the stored credential.
+// DefaultUserName and DefaultDomainName are left alone: Winlogon also uses
+// them to pre-fill the logon screen.
+const psDisableAutologon = `
+try {
+  foreach ($n in @('AutoAdminLogon', 'DefaultPassword', 'AutoLogonCount')) {
+    Remove-ItemProperty -LiteralPath $AlKey -Name $n -ErrorAction SilentlyContinue
+  }
+  $left = @('AutoAdminLogon', 'DefaultPassword') | Where-Object {
+    $null -ne (Get-ItemProperty -LiteralPath $AlKey -Name $_ -ErrorAction SilentlyContinue)
+  }
+  if ($left) {
+    Emit-Err 'permission_denied' ('could not remove ' + ($left -join ', ')) @{}
+    return
+  }
+  Emit-OK $null
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Autologon $msg) $msg @{}
+}
+`
+
+// runAutologonEnvelope executes script (prepended with psAutologonHeader)
+// with stdin and parses the JSON envelope. Cancellation maps to
+// AutologonErrorTimeout; other transport failures to AutologonErrorUnknown.
+func (a *AutologonClient) runAutologonEnvelope(ctx context.Context, op, script, stdin string) (*autologonPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psAutologonHeader + "\n" + script
+	stdout, stderr, err := runAutologonPowerShell(ctx, a.c, full, stdin)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      a.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewAutologonError(AutologonErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewAutologonError(AutologonErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewAutologonError(AutologonErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp autologonPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewAutologonError(AutologonErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewAutologonError(mapAutologonKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapAutologonKind translates a PS-side "kind" string to a typed
+// AutologonErrorKind. Unknown values fall through to AutologonErrorUnknown.
+func mapAutologonKind(k string) AutologonErrorKind {
+	switch k {
+	case string(AutologonErrorPermission),
+		string(AutologonErrorTimeout),
+		string(AutologonErrorInvalidParameter):
+		return AutologonErrorKind(k)
+	default:
+		return AutologonErrorUnknown
+	}
+}
+
+// parseAutologonState decodes the "data" object of a Set/Read envelope.
+func (a *AutologonClient) parseAutologonState(resp *autologonPSResponse) (*AutologonState, error) {
+	var p autologonStatePayload
+	if jerr := json.Unmarshal(resp.Data, &p); jerr != nil {
+		return nil, NewAutologonError(AutologonErrorUnknown,
+			"failed to parse Winlogon state", jerr,
+			map[string]string{"host": a.c.cfg.Host})
+	}
+	return &AutologonState{
+		Enabled:        p.Enabled,
+		Username:       p.Username,
+		Domain:         p.Domain,
+		AutoLogonCount: p.AutoLogonCount,
+		PasswordSet:    p.PasswordSet,
+	}, nil
+}
+
+// Set writes the automatic-logon configuration and returns the observed
+// state.
+func (a *AutologonClient) Set(ctx context.Context, in AutologonInput) (*AutologonState, error) {
+	if strings.TrimSpace(in.Username) == "" {
+		return nil, NewAutologonError(AutologonErrorInvalidParameter,
+			"username must not be empty", nil, map[string]string{"host": a.c.cfg.Host})
+	}
+	if in.AutoLogonCount != nil && *in.AutoLogonCount < 1 {
+		return nil, NewAutologonError(AutologonErrorInvalidParameter,
+			fmt.Sprintf("auto_logon_count must be at least 1, got %d", *in.AutoLogonCount),
+			nil, map[string]string{"host": a.c.cfg.Host})
+	}
+	stdin, err := json.Marshal(autologonPayload{
+		Username:       in.Username,
+		Domain:         in.Domain,
+		Password:       in.Password,
+		AutoLogonCount: in.AutoLogonCount,
+	})
+	if err != nil {
+		return nil, NewAutologonError(AutologonErrorUnknown,
+			"failed to encode input", err, map[string]string{"host": a.c.cfg.Host})
+	}
+	resp, err := a.runAutologonEnvelope(ctx, "set", psSetAutologon, string(stdin))
+	if err != nil {
+		return nil, err
+	}
+	return a.parseAutologonState(resp)
+}
+
+// Read returns the current automatic-logon configuration.
+func (a *AutologonClient) Read(ctx context.Context) (*AutologonState, error) {
+	resp, err := a.runAutologonEnvelope(ctx, "read", psReadAutologon, "")
+	if err != nil {
+		return nil, err
+	}
+	return a.parseAutologonState(resp)
+}
+
+// Disable removes AutoAdminLogon, DefaultPassword and AutoLogonCount.
+func (a *AutologonClient) Disable(ctx context.Context) error {
+	_, err := a.runAutologonEnvelope(ctx, "disable", psDisableAutologon, "")
+	return err
+}
@@ -0,0 +1,191 @@
+// Package winclient — unit tests for AutologonClient.
+//
+// These tests stub the package-level seam runAutologonPowerShell to inject
+// scripted stdout/stderr/err triples and to capture the stdin document.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newAlTestClient(t *testing.T) *AutologonClient {
+	t.Helper()
+	c, err := New(Config{
+		Host:     "win01",
+		Username: "u",
+		Password: "p",
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return NewAutologonClient(c)
+}
+
+// stubAlRun replaces runAutologonPowerShell for the duration of a test and
+// returns a restore function (typically deferred).
+func stubAlRun(fn func(ctx context.Context, c *Client, script, stdin string) (string, string, error)) func() {
+	prev := runAutologonPowerShell
+	runAutologonPowerShell = fn
+	return func() { runAutologonPowerShell = prev }
+}
+
+func alOK(t *testing.T, data any) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"ok": true, "data": data})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b) + "\n"
+}
+
+func TestAutologonError_IsAndHelper(t *testing.T) {
+	err := NewAutologonError(AutologonErrorPermission, "denied", errors.New("inner"), nil)
+	if !errors.Is(err, ErrAutologonPermission) {
+		t.Error("errors.Is should match on Kind")
+	}
+	if errors.Is(err, ErrAutologonUnknown) {
+		t.Error("errors.Is should not match a different Kind")
+	}
+	if !IsAutologonError(err, AutologonErrorPermission) {
+		t.Error("IsAutologonError should match")
+	}
+	if errors.Unwrap(err).Error() != "inner" {
+		t.Error("Unwrap should return cause")
+	}
+}
+
+func TestMapAutologonKind(t *testing.T) {
+	cases := map[string]AutologonErrorKind{
+		"permission_denied": AutologonErrorPermission,
+		"timeout":           AutologonErrorTimeout,
+		"invalid_parameter": AutologonErrorInvalidParameter,
+		"bogus":             AutologonErrorUnknown,
+	}
+	for in, want := range cases {
+		if got := mapAutologonKind(in); got != want {
+			t.Errorf("mapAutologonKind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAutologonSet_PasswordOnStdinOnly(t *testing.T) {
+	a := newAlTestClient(t)
+	var script, stdin string
+	defer stubAlRun(func(_ context.Context, _ *Client, s, in string) (string, string, error) {
+		script, stdin = s, in
+		return alOK(t, map[string]any{
+			"enabled": true, "username": "kiosk", "domain": "WIN01",
+			"auto_logon_count": 3, "password_set": true,
+		}), "", nil
+	})()
+
+	count := int64(3)
+	st, err := a.Set(context.Background(), AutologonInput{
+		Username: "kiosk", Password: "S3cret!pw", AutoLogonCount: &count,
+	})
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if strings.Contains(script, "S3cret!pw") {
+		t.Error("password must not appear in the script body")
+	}
+	var p map[string]any
+	if err := json.Unmarshal([]byte(stdin), &p); err != nil {
+		t.Fatalf("stdin: %v", err)
+	}
+	if p["password"] != "S3cret!pw" || p["username"] != "kiosk" || p["auto_logon_count"] != float64(3) {
+		t.Errorf("stdin payload = %v", p)
+	}
+	for _, want := range []string{"'DefaultPassword'", "'AutoLogonCount'", "'AutoAdminLogon' -Value '1'", "$env:COMPUTERNAME"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	if !st.Enabled || st.Domain != "WIN01" || st.AutoLogonCount == nil || *st.AutoLogonCount != 3 || !st.PasswordSet {
+		t.Errorf("state = %+v", st)
+	}
+}
+
+func TestAutologonSet_Validation(t *testing.T) {
+	a := newAlTestClient(t)
+	defer stubAlRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		t.Fatal("no remote call expected")
+		return "", "", nil
+	})()
+	if _, err := a.Set(context.Background(), AutologonInput{Username: " "}); !IsAutologonError(err, AutologonErrorInvalidParameter) {
+		t.Errorf("empty username: %v", err)
+	}
+	zero := int64(0)
+	if _, err := a.Set(context.Background(), AutologonInput{Username: "u", AutoLogonCount: &zero}); !IsAutologonError(err, AutologonErrorInvalidParameter) {
+		t.Errorf("zero count: %v", err)
+	}
+}
+
+func TestAutologonRead_NullCount(t *testing.T) {
+	a := newAlTestClient(t)
+	defer stubAlRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return alOK(t, map[string]any{
+			"enabled": false, "username": "kiosk", "domain": "WIN01",
+			"auto_logon_count": nil, "password_set": false,
+		}), "", nil
+	})()
+	st, err := a.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if st.Enabled || st.AutoLogonCount != nil || st.PasswordSet {
+		t.Errorf("state = %+v", st)
+	}
+}
+
+func TestAutologonDisable_RemovesCredential(t *testing.T) {
+	a := newAlTestClient(t)
+	var script string
+	defer stubAlRun(func(_ context.Context, _ *Client, s, _ string) (string, string, error) {
+		script = s
+		return `{"ok":true,"data":null}` + "\n", "", nil
+	})()
+	if err := a.Disable(context.Background()); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if !strings.Contains(script, "@('AutoAdminLogon', 'DefaultPassword', 'AutoLogonCount')") {
+		t.Errorf("disable script should remove AutoAdminLogon/DefaultPassword/AutoLogonCount:\n%s", script)
+	}
+}
+
+func TestAutologonEnvelope_Errors(t *testing.T) {
+	a := newAlTestClient(t)
+
+	restore := stubAlRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return `{"ok":false,"kind":"permission_denied","message":"Requested registry access is not allowed."}` + "\n", "", nil
+	})
+	_, err := a.Read(context.Background())
+	restore()
+	if !errors.Is(err, ErrAutologonPermission) {
+		t.Errorf("expected permission_denied, got %v", err)
+	}
+
+	restore = stubAlRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return "", "boom", errors.New("transport")
+	})
+	_, err = a.Read(context.Background())
+	restore()
+	if !errors.Is(err, ErrAutologonUnknown) {
+		t.Errorf("expected unknown, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer stubAlRun(func(ctx context.Context, _ *Client, _, _ string) (string, string, error) {
+		return "", "", ctx.Err()
+	})()
+	if err := a.Disable(ctx); !errors.Is(err, ErrAutologonTimeout) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+}
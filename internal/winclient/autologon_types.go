@@ -0,0 +1,122 @@
+// Package winclient: types for the windows_autologon resource.
+//
+// AutologonState is the Winlogon automatic-logon configuration read from
+// HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Winlogon.
+// AutologonErrorKind / AutologonError follow the same shape as FeatureError
+// so the resource layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AutologonErrorKind categorises errors returned by WindowsAutologonClient.
+type AutologonErrorKind string
+
+const (
+	AutologonErrorPermission       AutologonErrorKind = "permission_denied"
+	AutologonErrorTimeout          AutologonErrorKind = "timeout"
+	AutologonErrorInvalidParameter AutologonErrorKind = "invalid_parameter"
+	AutologonErrorUnknown          AutologonErrorKind = "unknown"
+)
+
+// AutologonError is the structured error type returned by
+// WindowsAutologonClient.
+type AutologonError struct {
+	Kind    AutologonErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *AutologonError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_autologon [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_autologon [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *AutologonError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *AutologonError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*AutologonError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewAutologonError constructs a *AutologonError.
+func NewAutologonError(kind AutologonErrorKind, msg string, cause error, ctx map[string]string) *AutologonError {
+	return &AutologonError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsAutologonError reports whether err is a *AutologonError of the given
+// kind.
+func IsAutologonError(err error, kind AutologonErrorKind) bool {
+	var ae *AutologonError
+	if errors.As(err, &ae) {
+		return ae.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrAutologonPermission       = &AutologonError{Kind: AutologonErrorPermission}
+	ErrAutologonTimeout          = &AutologonError{Kind: AutologonErrorTimeout}
+	ErrAutologonInvalidParameter = &AutologonError{Kind: AutologonErrorInvalidParameter}
+	ErrAutologonUnknown          = &AutologonError{Kind: AutologonErrorUnknown}
+)
+
+// AutologonInput is the desired automatic-logon configuration.
+type AutologonInput struct {
+	// Username is written to DefaultUserName.
+	Username string
+	// Domain is written to DefaultDomainName. Empty means the local
+	// computer name.
+	Domain string
+	// Password is written to DefaultPassword. It is sent over stdin only and
+	// never appears in the script body or error context.
+	Password string
+	// AutoLogonCount, when non-nil, is written to AutoLogonCount. Windows
+	// decrements it on every automatic logon and, when it reaches zero,
+	// clears AutoAdminLogon and deletes DefaultPassword. Nil removes the
+	// value (unlimited automatic logons).
+	AutoLogonCount *int64
+}
+
+// AutologonState is the observed automatic-logon configuration.
+type AutologonState struct {
+	// Enabled is true when AutoAdminLogon = "1".
+	Enabled bool
+	// Username is DefaultUserName ("" when unset).
+	Username string
+	// Domain is DefaultDomainName ("" when unset).
+	Domain string
+	// AutoLogonCount is the remaining AutoLogonCount; nil when the value is
+	// absent.
+	AutoLogonCount *int64
+	// PasswordSet is true when a DefaultPassword value exists. The password
+	// itself is never read back.
+	PasswordSet bool
+}
+
+// WindowsAutologonClient is the contract for the windows_autologon resource.
+type WindowsAutologonClient interface {
+	// Set writes the Winlogon values and enables automatic logon.
+	Set(ctx context.Context, in AutologonInput) (*AutologonState, error)
+	// Read returns the current configuration.
+	Read(ctx context.Context) (*AutologonState, error)
+	// Disable clears AutoAdminLogon and removes DefaultPassword and
+	// AutoLogonCount. It is idempotent.
+	Disable(ctx context.Context) error
+}
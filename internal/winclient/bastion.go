@@ -0,0 +1,596 @@
+// Package winclient: WinRM through an SSH bastion (jump host).
+//
+// Hosts in private subnets used to need a hand-maintained tunnel. With
+// Config.Bastion set, every WinRM connection is opened as a direct-tcpip
+// channel of one SSH session to the bastion, so the HTTP transport's
+// connection reuse works unchanged: each pooled connection is just another
+// channel on the same session. The session is opened on first use and
+// re-opened if the bastion drops it.
+//
+// Every pooled WinRM connection is a channel of that one session, so a dead
+// session poisons all of them at once: the HTTP transport keeps re-using
+// channels that will never answer. A run that fails at the transport level
+// therefore probes the session (checkSession), and a session that does not
+// answer is closed, which closes every channel on it so the transport
+// discards them instead of re-idling them.
+//
+// Firewalls and NAT gateways between the provider and the bastion silently
+// forget idle flows, and a session they dropped is only noticed when the
+// next WinRM request hangs on it. A keepalive@openssh.com request is
+// therefore sent every KeepaliveInterval; after bastionKeepaliveMaxFailures
+// consecutive unanswered requests the session is closed, so the next Dial
+// opens a fresh one.
+//
+// Trust is split the same way as without a bastion: the bastion is verified
+// by its SSH host key (BastionConfig.HostKey), and the Windows host by TLS
+// when use_https is set. The bastion only forwards bytes and never sees the
+// WinRM credentials in clear over HTTPS.
+package winclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// BastionConfig describes the SSH jump host WinRM connections are tunnelled
+// through. At least one of Password and KeyPath is required.
+type BastionConfig struct {
+	Host     string
+	Port     int // default 22
+	Username string
+	Password string
+	// KeyPath is a private key file (PEM or OpenSSH format).
+	KeyPath string
+	// KeyPassphrase decrypts KeyPath when the key is passphrase-protected.
+	KeyPassphrase string
+	// HostKey pins the bastion's host key: its public key in authorized_keys
+	// format ("ssh-ed25519 AAAA...") or its fingerprint, either SHA256
+	// ("SHA256:...") or legacy MD5 ("MD5:aa:bb:..."). Several pins may be
+	// given one per line; any of them matching is enough. Required unless
+	// InsecureIgnoreHostKey is set.
+	HostKey string
+	// InsecureIgnoreHostKey accepts any host key from the bastion. It is
+	// only honoured when HostKey is empty.
+	InsecureIgnoreHostKey bool
+	// KeepaliveInterval is the delay between keepalive requests on the
+	// session. Zero means DefaultBastionKeepaliveInterval; a negative value
+	// disables keepalives.
+	KeepaliveInterval time.Duration
+	// Ciphers, KeyExchanges and MACs replace the algorithms offered to the
+	// bastion, in preference order; empty keeps the x/crypto/ssh defaults.
+	// Every name must be implemented by x/crypto/ssh. Algorithms it marks
+	// insecure are accepted, for old servers; most of them (aes128-cbc,
+	// diffie-hellman-group1-sha1, ...) are only offered when listed here.
+	Ciphers      []string
+	KeyExchanges []string
+	MACs         []string
+	// HostKeyAlgorithms replaces the host key algorithms accepted from the
+	// bastion, in preference order (e.g. "ssh-ed25519"). A server with
+	// several host keys presents the one of the first algorithm both sides
+	// support, so this picks which key HostKey has to match; empty keeps the
+	// x/crypto/ssh default, which prefers ECDSA and RSA over Ed25519.
+	HostKeyAlgorithms []string
+}
+
+// DefaultBastionKeepaliveInterval is the keepalive interval used when
+// BastionConfig.KeepaliveInterval is zero.
+const DefaultBastionKeepaliveInterval = 30 * time.Second
+
+// bastionKeepaliveMaxFailures is the number of consecutive failed or
+// unanswered keepalives after which the session is considered dead.
+const bastionKeepaliveMaxFailures = 3
+
+// bastionProbeTimeout bounds the keepalive that checks a session before it
+// is re-used after a failure. A variable so tests can shorten it.
+var bastionProbeTimeout = 5 * time.Second
+
+// errKeepaliveTimeout reports a keepalive request that got no reply within
+// the keepalive interval.
+var errKeepaliveTimeout = errors.New("keepalive not answered")
+
+// bastionAuthError is a bastion failure that retrying cannot fix: rejected
+// credentials or a host key mismatch. classifyTransportError maps it to
+// FailureAuth so it counts towards the authentication latch.
+type bastionAuthError struct {
+	addr string
+	err  error
+}
+
+func (e *bastionAuthError) Error() string {
+	return fmt.Sprintf("winclient: bastion %s: %v", e.addr, e.err)
+}
+
+func (e *bastionAuthError) Unwrap() error { return e.err }
+
+// bastionDialer opens TCP connections to the Windows host through the
+// bastion's SSH session. Dial matches the signature winrm.Parameters.Dial
+// expects.
+type bastionDialer struct {
+	addr      string
+	cfg       *ssh.ClientConfig
+	keepalive time.Duration // <= 0: disabled
+
+	mu     sync.Mutex
+	client *ssh.Client
+	// dialing is the session dial in progress, if any. Dials that need a
+	// session meanwhile wait for it instead of opening their own.
+	dialing *bastionDial
+	// closed is set by close; no session is opened after it.
+	closed bool
+	// keepers tracks the keepSessionAlive goroutines so close can wait for
+	// them to exit.
+	keepers sync.WaitGroup
+}
+
+// bastionDial is a session dial shared by the Dials waiting on it. err is
+// set before done is closed.
+type bastionDial struct {
+	done chan struct{}
+	err  error
+}
+
+// newBastionDialer validates b and prepares the SSH client configuration. It
+// reads the key file but does not connect.
+func newBastionDialer(b *BastionConfig, timeout time.Duration) (*bastionDialer, error) {
+	if strings.TrimSpace(b.Host) == "" {
+		return nil, fmt.Errorf("winclient: bastion host is required")
+	}
+	if b.Username == "" {
+		return nil, fmt.Errorf("winclient: bastion username is required")
+	}
+	if b.Password == "" && b.KeyPath == "" {
+		return nil, fmt.Errorf("winclient: bastion needs a password or a key path")
+	}
+	port := b.Port
+	if port == 0 {
+		port = 22
+	}
+
+	var auth []ssh.AuthMethod
+	if b.KeyPath != "" {
+		pem, err := os.ReadFile(b.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("winclient: read bastion key: %w", err)
+		}
+		var signer ssh.Signer
+		if b.KeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(pem, []byte(b.KeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(pem)
+		}
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("winclient: bastion key %s is passphrase-protected; set bastion_key_passphrase", b.KeyPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("winclient: parse bastion key %s: %w", b.KeyPath, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if b.Password != "" {
+		auth = append(auth, ssh.Password(b.Password))
+	}
+
+	var hostKey ssh.HostKeyCallback
+	switch {
+	case strings.TrimSpace(b.HostKey) != "":
+		pins, err := parseHostKeyPins(b.HostKey)
+		if err != nil {
+			return nil, fmt.Errorf("winclient: parse bastion host key: %w", err)
+		}
+		hostKey = fixedHostKey(pins)
+	case b.InsecureIgnoreHostKey:
+		hostKey = ssh.InsecureIgnoreHostKey() //nolint:gosec // explicit opt-in
+	default:
+		return nil, fmt.Errorf("winclient: bastion host key is required; set bastion_host_key, or bastion_insecure_ignore_host_key to skip verification")
+	}
+
+	supported, insecure := ssh.SupportedAlgorithms(), ssh.InsecureAlgorithms()
+	for _, a := range []struct {
+		kind  string
+		names []string
+		known [][]string
+	}{
+		{"cipher", b.Ciphers, [][]string{supported.Ciphers, insecure.Ciphers}},
+		{"key exchange", b.KeyExchanges, [][]string{supported.KeyExchanges, insecure.KeyExchanges}},
+		{"MAC", b.MACs, [][]string{supported.MACs, insecure.MACs}},
+		{"host key algorithm", b.HostKeyAlgorithms, [][]string{supported.HostKeys, insecure.HostKeys}},
+	} {
+		if err := checkAlgorithms(a.kind, a.names, a.known...); err != nil {
+			return nil, err
+		}
+	}
+
+	keepalive := b.KeepaliveInterval
+	if keepalive == 0 {
+		keepalive = DefaultBastionKeepaliveInterval
+	}
+
+	return &bastionDialer{
+		addr:      dialAddress(b.Host, port),
+		keepalive: keepalive,
+		cfg: &ssh.ClientConfig{
+			Config: ssh.Config{
+				Ciphers:      slices.Clone(b.Ciphers),
+				KeyExchanges: slices.Clone(b.KeyExchanges),
+				MACs:         slices.Clone(b.MACs),
+			},
+			User:              b.Username,
+			Auth:              auth,
+			HostKeyCallback:   hostKey,
+			HostKeyAlgorithms: slices.Clone(b.HostKeyAlgorithms),
+			Timeout:           timeout,
+		},
+	}, nil
+}
+
+// checkAlgorithms rejects a name x/crypto/ssh does not implement. The
+// library drops unknown names silently, which would surface as a confusing
+// "no common algorithm" handshake failure instead of a configuration error.
+func checkAlgorithms(kind string, names []string, known ...[]string) error {
+	for _, name := range names {
+		found := false
+		for _, k := range known {
+			if slices.Contains(k, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("winclient: unsupported bastion %s %q; supported: %s",
+				kind, name, strings.Join(slices.Concat(known...), ", "))
+		}
+	}
+	return nil
+}
+
+// hostKeyPin is one entry of BastionConfig.HostKey: a full public key, or
+// a fingerprint with its algorithm prefix.
+type hostKeyPin struct {
+	key ssh.PublicKey
+	md5 bool
+	// fingerprint is "SHA256:<unpadded base64>" or, for md5, the lower-case
+	// colon-separated hex digest without the "MD5:" prefix.
+	fingerprint string
+}
+
+// parseHostKeyPins parses the non-empty lines of s as host key pins. A line
+// starting with "MD5:" is compared with ssh.FingerprintLegacyMD5, one
+// starting with "SHA256:" with ssh.FingerprintSHA256, and anything else must
+// be an authorized_keys entry.
+func parseHostKeyPins(s string) ([]hostKeyPin, error) {
+	var pins []hostKeyPin
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		switch {
+		case hasPrefixFold(line, "MD5:"):
+			fp := strings.ToLower(line[len("MD5:"):])
+			if !md5FingerprintRe.MatchString(fp) {
+				return nil, fmt.Errorf("MD5 fingerprint %q must be 16 colon-separated hex bytes", line)
+			}
+			pins = append(pins, hostKeyPin{md5: true, fingerprint: fp})
+		case hasPrefixFold(line, "SHA256:"):
+			// ssh-keygen prints the digest unpadded; accept a padded copy too.
+			digest := strings.TrimRight(line[len("SHA256:"):], "=")
+			if raw, err := base64.RawStdEncoding.DecodeString(digest); err != nil || len(raw) != sha256.Size {
+				return nil, fmt.Errorf("SHA256 fingerprint %q must be 32 base64-encoded bytes", line)
+			}
+			pins = append(pins, hostKeyPin{fingerprint: "SHA256:" + digest})
+		default:
+			key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+			if err != nil {
+				return nil, err
+			}
+			pins = append(pins, hostKeyPin{key: key})
+		}
+	}
+	return pins, nil
+}
+
+var md5FingerprintRe = regexp.MustCompile(`^[0-9a-f]{2}(:[0-9a-f]{2}){15}$`)
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// matches reports whether got is the key p pins.
+func (p hostKeyPin) matches(got ssh.PublicKey) bool {
+	switch {
+	case p.key != nil:
+		return bytes.Equal(got.Marshal(), p.key.Marshal())
+	case p.md5:
+		return ssh.FingerprintLegacyMD5(got) == p.fingerprint
+	default:
+		return ssh.FingerprintSHA256(got) == p.fingerprint
+	}
+}
+
+func (p hostKeyPin) String() string {
+	switch {
+	case p.key != nil:
+		return p.key.Type() + " " + ssh.FingerprintSHA256(p.key)
+	case p.md5:
+		return "MD5:" + p.fingerprint
+	default:
+		return p.fingerprint
+	}
+}
+
+// fixedHostKey accepts only a key matching one of pins, returning a mismatch
+// the classifier treats as an authentication failure. The presented key is
+// reported with its SHA256 fingerprint, and its MD5 one too when an MD5 pin
+// is configured, so it can be compared with what was pinned.
+func fixedHostKey(pins []hostKeyPin) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, got ssh.PublicKey) error {
+		gotFP := ssh.FingerprintSHA256(got)
+		want := make([]string, 0, len(pins))
+		for _, p := range pins {
+			if p.matches(got) {
+				return nil
+			}
+			if p.md5 {
+				gotFP = ssh.FingerprintSHA256(got) + " MD5:" + ssh.FingerprintLegacyMD5(got)
+			}
+			want = append(want, p.String())
+		}
+		return &bastionAuthError{addr: hostname, err: fmt.Errorf("host key mismatch: got %s %s, want %s",
+			got.Type(), gotFP, strings.Join(want, " or "))}
+	}
+}
+
+// errHostKeyCaptured aborts the handshake in GetHostKeyFingerprint once the
+// server has presented its host key.
+var errHostKeyCaptured = errors.New("host key captured")
+
+// GetHostKeyFingerprint connects to the SSH server at host:port (port 0 means
+// 22) and returns the host key it presents: its SHA256 fingerprint as printed
+// by ssh-keygen -l ("SHA256:...") and the key in authorized_keys format, the
+// form BastionConfig.HostKey expects. The handshake is abandoned as soon as
+// the key is known, so no credentials are needed or sent. hostKeyAlgorithms,
+// when given, is offered as BastionConfig.HostKeyAlgorithms is, so the key
+// returned is the one a bastion connection will be shown.
+//
+// The key is taken on trust; compare the fingerprint with one obtained out of
+// band before pinning it.
+func GetHostKeyFingerprint(ctx context.Context, host string, port int, hostKeyAlgorithms ...string) (fingerprint, authorizedKey string, err error) {
+	if port == 0 {
+		port = 22
+	}
+	addr := dialAddress(host, port)
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", "", fmt.Errorf("winclient: host key of %s: %w", addr, err)
+	}
+	defer nc.Close()
+	// Cut the handshake short when ctx ends. Setting the conn deadline to
+	// ctx's own would race it: the read could time out before ctx.Err() is
+	// set, reporting an i/o timeout instead of the context error.
+	stop := context.AfterFunc(ctx, func() { _ = nc.SetDeadline(time.Now()) })
+	defer stop()
+
+	var key ssh.PublicKey
+	cfg := &ssh.ClientConfig{
+		HostKeyCallback: func(_ string, _ net.Addr, k ssh.PublicKey) error {
+			key = k
+			return errHostKeyCaptured
+		},
+		HostKeyAlgorithms: hostKeyAlgorithms,
+	}
+	_, _, _, err = ssh.NewClientConn(nc, addr, cfg)
+	if key == nil {
+		if err == nil {
+			err = errors.New("server presented no host key")
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return "", "", fmt.Errorf("winclient: host key of %s: %w", addr, err)
+	}
+	return ssh.FingerprintSHA256(key), strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))), nil
+}
+
+// Dial opens network/addr through the bastion. If the channel cannot be
+// opened because the session itself has died, the session is re-opened once.
+func (d *bastionDialer) Dial(network, addr string) (net.Conn, error) {
+	for attempt := 0; ; attempt++ {
+		client, err := d.session()
+		if err != nil {
+			return nil, err
+		}
+		conn, err := client.Dial(network, addr)
+		if err == nil {
+			return conn, nil
+		}
+		if attempt > 0 || alive(client) {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: fmt.Errorf("via bastion %s: %w", d.addr, err)}
+		}
+		d.drop(client)
+	}
+}
+
+// session returns the open SSH session, connecting if there is none. The
+// SSH handshake runs without d.mu held, so close, drop and checkSession are
+// not held up by a slow bastion; Dials that arrive during it wait for its
+// outcome and share its error.
+func (d *bastionDialer) session() (*ssh.Client, error) {
+	for {
+		d.mu.Lock()
+		if d.client != nil {
+			client := d.client
+			d.mu.Unlock()
+			return client, nil
+		}
+		if d.closed {
+			d.mu.Unlock()
+			return nil, ErrClientClosed
+		}
+		if call := d.dialing; call != nil {
+			d.mu.Unlock()
+			<-call.done
+			if call.err != nil {
+				return nil, call.err
+			}
+			// The session may already have been dropped; look again.
+			continue
+		}
+		call := &bastionDial{done: make(chan struct{})}
+		d.dialing = call
+		d.mu.Unlock()
+
+		client, err := d.dialSession()
+
+		d.mu.Lock()
+		d.dialing = nil
+		if err == nil && d.closed {
+			_ = client.Close()
+			err = ErrClientClosed
+		}
+		if err == nil {
+			d.client = client
+			if d.keepalive > 0 {
+				d.keepers.Add(1)
+				go func() {
+					defer d.keepers.Done()
+					d.keepSessionAlive(client)
+				}()
+			}
+		}
+		call.err = err
+		close(call.done)
+		d.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+}
+
+// dialSession opens a new SSH session to the bastion.
+func (d *bastionDialer) dialSession() (*ssh.Client, error) {
+	client, err := ssh.Dial("tcp", d.addr, d.cfg)
+	if err != nil {
+		var authErr *bastionAuthError
+		if errors.As(err, &authErr) {
+			return nil, authErr
+		}
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return nil, &bastionAuthError{addr: d.addr, err: err}
+		}
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("bastion %s: %w", d.addr, err)}
+	}
+	return client, nil
+}
+
+// keepSessionAlive sends a keepalive on client every d.keepalive until the
+// session closes, dropping it after bastionKeepaliveMaxFailures consecutive
+// failures. A "false" reply from a server that does not know the request
+// type still proves the session is alive.
+func (d *bastionDialer) keepSessionAlive(client *ssh.Client) {
+	closed := make(chan struct{})
+	go func() {
+		_ = client.Wait()
+		close(closed)
+	}()
+
+	t := time.NewTicker(d.keepalive)
+	defer t.Stop()
+	failures := 0
+	for {
+		select {
+		case <-closed:
+			return
+		case <-t.C:
+		}
+		if err := sendKeepalive(client, d.keepalive); err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		if failures >= bastionKeepaliveMaxFailures {
+			d.drop(client)
+			return
+		}
+	}
+}
+
+// sendKeepalive sends one keepalive@openssh.com request and waits up to
+// timeout for the reply. A half-open connection never replies, so the wait
+// cannot be left to SendRequest alone.
+func sendKeepalive(client *ssh.Client, timeout time.Duration) error {
+	res := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		res <- err
+	}()
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case err := <-res:
+		return err
+	case <-t.C:
+		return errKeepaliveTimeout
+	}
+}
+
+// alive reports whether client answers a keepalive within
+// bastionProbeTimeout.
+func alive(client *ssh.Client) bool {
+	return sendKeepalive(client, bastionProbeTimeout) == nil
+}
+
+// checkSession probes the current session and drops it when it does not
+// answer. It reports whether a live session remains; false when there was
+// none to begin with.
+func (d *bastionDialer) checkSession() bool {
+	d.mu.Lock()
+	client := d.client
+	d.mu.Unlock()
+	if client == nil {
+		return false
+	}
+	if alive(client) {
+		return true
+	}
+	d.drop(client)
+	return false
+}
+
+// close closes the session, which closes every channel on it, and waits for
+// its keepalive goroutine to exit. Later Dials fail with ErrClientClosed.
+func (d *bastionDialer) close() {
+	d.mu.Lock()
+	d.closed = true
+	if d.client != nil {
+		_ = d.client.Close()
+		d.client = nil
+	}
+	d.mu.Unlock()
+	d.keepers.Wait()
+}
+
+// drop closes client if it is still the current session.
+func (d *bastionDialer) drop(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client == client {
+		_ = d.client.Close()
+		d.client = nil
+	}
+}
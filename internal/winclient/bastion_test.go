@@ -0,0 +1,874 @@
+// Package winclient — unit tests for the SSH bastion dialer and
+// Client.IsConnected, run against an in-process SSH server that forwards
+// direct-tcpip channels.
+package winclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testBastion is a minimal SSH server accepting password "pw" for user "u"
+// and forwarding direct-tcpip channels.
+type testBastion struct {
+	addr       string
+	hostKey    ssh.PublicKey
+	handshakes atomic.Int32
+	keepalives atomic.Int32
+	// stalled leaves global requests unanswered, like a session whose TCP
+	// flow a firewall has silently dropped.
+	stalled atomic.Bool
+}
+
+// startTestBastion starts an in-process SSH server accepting user "u" with
+// password "pw". opts adjust its ServerConfig, e.g. to restrict algorithms.
+func startTestBastion(t *testing.T, opts ...func(*ssh.ServerConfig)) *testBastion {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pw []byte) (*ssh.Permissions, error) {
+			if c.User() == "u" && string(pw) == "pw" {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	cfg.AddHostKey(signer)
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	b := &testBastion{addr: ln.Addr().String(), hostKey: signer.PublicKey()}
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(nc, cfg)
+		}
+	}()
+	return b
+}
+
+func (b *testBastion) serve(nc net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+	if err != nil {
+		nc.Close()
+		return
+	}
+	b.handshakes.Add(1)
+	go func() {
+		for r := range reqs {
+			if r.Type == "keepalive@openssh.com" {
+				b.keepalives.Add(1)
+			}
+			if !b.stalled.Load() && r.WantReply {
+				r.Reply(false, nil)
+			}
+		}
+	}()
+	for nch := range chans {
+		if nch.ChannelType() != "direct-tcpip" {
+			nch.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		// RFC 4254 7.2: host string, port uint32, originator string, port.
+		data := nch.ExtraData()
+		n := binary.BigEndian.Uint32(data)
+		host := string(data[4 : 4+n])
+		port := binary.BigEndian.Uint32(data[4+n:])
+		target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+		if err != nil {
+			nch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		ch, creqs, err := nch.Accept()
+		if err != nil {
+			target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(creqs)
+		go func() {
+			io.Copy(ch, target)
+			ch.Close()
+		}()
+		go func() {
+			io.Copy(target, ch)
+			target.Close()
+		}()
+	}
+}
+
+// startEchoServer stands in for the WinRM listener behind the bastion.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(c, c)
+				c.Close()
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func bastionCfg(t *testing.T, b *testBastion, password string) *BastionConfig {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(b.addr)
+	p, _ := strconv.Atoi(port)
+	return &BastionConfig{Host: host, Port: p, Username: "u", Password: password,
+		HostKey: string(ssh.MarshalAuthorizedKey(b.hostKey))}
+}
+
+func TestBastionDialer_TunnelsAndReusesSession(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	d, err := newBastionDialer(bastionCfg(t, b, "pw"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("newBastionDialer: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		conn, err := d.Dial("tcp", target)
+		if err != nil {
+			t.Fatalf("Dial %d: %v", i, err)
+		}
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+			t.Fatalf("echo = %q, %v", buf, err)
+		}
+		conn.Close()
+	}
+	if n := b.handshakes.Load(); n != 1 {
+		t.Errorf("bastion handshakes = %d, want 1 (connections share one session)", n)
+	}
+
+	// A refused target is a dial failure and must not tear the session down.
+	if _, err := d.Dial("tcp", "127.0.0.1:1"); classifyTransportError(err) != FailureDial {
+		t.Errorf("refused target: kind = %q (%v), want dial", classifyTransportError(err), err)
+	}
+	if n := b.handshakes.Load(); n != 1 {
+		t.Errorf("refused target re-opened the session: handshakes = %d", n)
+	}
+}
+
+func TestBastionDialer_ReconnectsDroppedSession(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	d, err := newBastionDialer(bastionCfg(t, b, "pw"), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := d.Dial("tcp", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	d.client.Close() // the bastion dropped us
+
+	conn, err = d.Dial("tcp", target)
+	if err != nil {
+		t.Fatalf("Dial after drop: %v", err)
+	}
+	conn.Close()
+	if n := b.handshakes.Load(); n != 2 {
+		t.Errorf("handshakes = %d, want 2", n)
+	}
+}
+
+func TestBastionDialer_KeepaliveKeepsHealthySession(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	cfg := bastionCfg(t, b, "pw")
+	cfg.KeepaliveInterval = 10 * time.Millisecond
+	d, err := newBastionDialer(cfg, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := d.Dial("tcp", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for b.keepalives.Load() < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := b.keepalives.Load(); n < 5 {
+		t.Fatalf("keepalives = %d, want at least 5", n)
+	}
+	d.mu.Lock()
+	alive := d.client != nil
+	d.mu.Unlock()
+	if !alive {
+		t.Error("a session answering keepalives must not be dropped")
+	}
+}
+
+func TestBastionDialer_KeepaliveDropsDeadSession(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	cfg := bastionCfg(t, b, "pw")
+	cfg.KeepaliveInterval = 10 * time.Millisecond
+	d, err := newBastionDialer(cfg, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := d.Dial("tcp", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	b.stalled.Store(true)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		d.mu.Lock()
+		dropped := d.client == nil
+		d.mu.Unlock()
+		if dropped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("session with unanswered keepalives was not dropped")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	b.stalled.Store(false)
+	conn, err = d.Dial("tcp", target)
+	if err != nil {
+		t.Fatalf("Dial after keepalive drop: %v", err)
+	}
+	conn.Close()
+	if n := b.handshakes.Load(); n != 2 {
+		t.Errorf("handshakes = %d, want 2", n)
+	}
+}
+
+// shortBastionProbe shortens bastionProbeTimeout for the duration of a test.
+func shortBastionProbe(t *testing.T) {
+	t.Helper()
+	prev := bastionProbeTimeout
+	bastionProbeTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { bastionProbeTimeout = prev })
+}
+
+func TestBastionDialer_CheckSessionDropsDeadSession(t *testing.T) {
+	shortBastionProbe(t)
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	cfg := bastionCfg(t, b, "pw")
+	cfg.KeepaliveInterval = -1
+	d, err := newBastionDialer(cfg, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.checkSession() {
+		t.Error("checkSession without a session must report false")
+	}
+	pooled, err := d.Dial("tcp", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pooled.Close()
+	if !d.checkSession() {
+		t.Fatal("a session answering keepalives must be kept")
+	}
+
+	b.stalled.Store(true)
+	if d.checkSession() {
+		t.Fatal("a session that does not answer must be dropped")
+	}
+	// Channels of the dropped session are closed, so the HTTP transport
+	// discards them instead of re-using them.
+	pooled.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := pooled.Read(make([]byte, 1)); err == nil || errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("read on a channel of the dropped session = %v, want it closed", err)
+	}
+
+	b.stalled.Store(false)
+	conn, err := d.Dial("tcp", target)
+	if err != nil {
+		t.Fatalf("Dial after drop: %v", err)
+	}
+	conn.Close()
+	if n := b.handshakes.Load(); n != 2 {
+		t.Errorf("handshakes = %d, want 2", n)
+	}
+}
+
+func TestClient_IsConnected(t *testing.T) {
+	shortBastionProbe(t)
+	target := startEchoServer(t)
+	host, portStr, _ := net.SplitHostPort(target)
+	port, _ := strconv.Atoi(portStr)
+
+	direct, err := New(Config{Host: host, Port: port, Username: "u", Password: "p", Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !direct.IsConnected(context.Background()) {
+		t.Error("direct: listener is up, want connected")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	down, err := New(Config{Host: "127.0.0.1", Port: closed, Username: "u", Password: "p", Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if down.IsConnected(context.Background()) {
+		t.Error("direct: closed port, want not connected")
+	}
+
+	b := startTestBastion(t)
+	bcfg := bastionCfg(t, b, "pw")
+	bcfg.KeepaliveInterval = -1
+	via, err := New(Config{Host: host, Port: port, Username: "u", Password: "p", Timeout: 2 * time.Second, Bastion: bcfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !via.IsConnected(context.Background()) {
+		t.Fatal("bastion: want connected")
+	}
+	b.stalled.Store(true)
+	if !via.IsConnected(context.Background()) {
+		t.Error("bastion: a dead session must be replaced, want connected")
+	}
+	if n := b.handshakes.Load(); n != 2 {
+		t.Errorf("handshakes = %d, want 2 (dead session re-opened)", n)
+	}
+}
+
+func TestClient_FailedRunDropsDeadBastionSession(t *testing.T) {
+	shortBastionProbe(t)
+	b := startTestBastion(t)
+	// The echo server answers the WinRM request with garbage, so the run
+	// fails at the transport level.
+	host, portStr, _ := net.SplitHostPort(startEchoServer(t))
+	port, _ := strconv.Atoi(portStr)
+	cfg := bastionCfg(t, b, "pw")
+	cfg.KeepaliveInterval = -1
+	c, err := New(Config{Host: host, Port: port, Username: "u", Password: "p", AuthType: "basic",
+		Timeout: 2 * time.Second, Bastion: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.stalled.Store(true)
+	if _, _, err := c.RunPowerShell(context.Background(), "Write-Output 1"); err == nil {
+		t.Fatal("expected the run to fail")
+	}
+	c.bastion.mu.Lock()
+	dropped := c.bastion.client == nil
+	c.bastion.mu.Unlock()
+	if !dropped {
+		t.Error("a failed run must drop a bastion session that does not answer")
+	}
+}
+
+// startGatedBastion is startTestBastion with password checks that block
+// until release is closed. entered receives a value as each check starts.
+func startGatedBastion(t *testing.T) (b *testBastion, entered chan struct{}, release chan struct{}) {
+	t.Helper()
+	entered, release = make(chan struct{}, 8), make(chan struct{})
+	b = startTestBastion(t, func(s *ssh.ServerConfig) {
+		check := s.PasswordCallback
+		s.PasswordCallback = func(c ssh.ConnMetadata, pw []byte) (*ssh.Permissions, error) {
+			entered <- struct{}{}
+			<-release
+			return check(c, pw)
+		}
+	})
+	return b, entered, release
+}
+
+func TestBastionDialer_HandshakeSharedAndUnlocked(t *testing.T) {
+	b, entered, release := startGatedBastion(t)
+	target := startEchoServer(t)
+	d, err := newBastionDialer(bastionCfg(t, b, "pw"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("newBastionDialer: %v", err)
+	}
+	defer d.close()
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			conn, err := d.Dial("tcp", target)
+			if err == nil {
+				conn.Close()
+			}
+			errs <- err
+		}()
+	}
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the bastion handshake did not start")
+	}
+
+	checked := make(chan bool)
+	go func() { checked <- d.checkSession() }()
+	select {
+	case live := <-checked:
+		if live {
+			t.Error("checkSession reported a session before the handshake ended")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("checkSession waited for the bastion handshake")
+	}
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Dial: %v", err)
+		}
+	}
+	if n := b.handshakes.Load(); n != 1 {
+		t.Errorf("handshakes = %d, want one session shared by the concurrent dials", n)
+	}
+}
+
+func TestBastionDialer_CloseDuringHandshake(t *testing.T) {
+	b, entered, release := startGatedBastion(t)
+	target := startEchoServer(t)
+	d, err := newBastionDialer(bastionCfg(t, b, "pw"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("newBastionDialer: %v", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := d.Dial("tcp", target)
+		errs <- err
+	}()
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the bastion handshake did not start")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		d.close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close waited for the bastion handshake")
+	}
+
+	close(release)
+	if err := <-errs; !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Dial after close = %v, want ErrClientClosed", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		t.Error("a session opened after close was kept")
+	}
+}
+
+func TestBastionDialer_KeepaliveDisabled(t *testing.T) {
+	d, err := newBastionDialer(&BastionConfig{Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true, KeepaliveInterval: -1}, time.Second)
+	if err != nil || d.keepalive > 0 {
+		t.Errorf("keepalive = %s, %v; want disabled", d.keepalive, err)
+	}
+	d, err = newBastionDialer(&BastionConfig{Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true}, time.Second)
+	if err != nil || d.keepalive != DefaultBastionKeepaliveInterval {
+		t.Errorf("keepalive = %s, %v; want the default", d.keepalive, err)
+	}
+}
+
+func TestBastionDialer_AuthFailuresAreLatchable(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+
+	d, err := newBastionDialer(bastionCfg(t, b, "wrong"), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Dial("tcp", target); classifyTransportError(err) != FailureAuth {
+		t.Errorf("bad password: kind = %q (%v), want auth", classifyTransportError(err), err)
+	}
+
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := ssh.NewSignerFromKey(other)
+	cfg := bastionCfg(t, b, "pw")
+	cfg.HostKey = string(ssh.MarshalAuthorizedKey(otherSigner.PublicKey()))
+	d, err = newBastionDialer(cfg, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = d.Dial("tcp", target)
+	if classifyTransportError(err) != FailureAuth || !strings.Contains(err.Error(), "host key mismatch") {
+		t.Errorf("host key mismatch: kind = %q (%v), want auth", classifyTransportError(err), err)
+	}
+}
+
+func TestBastionDialer_FingerprintPins(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := ssh.NewSignerFromKey(other)
+	otherKey := otherSigner.PublicKey()
+
+	sha := ssh.FingerprintSHA256(b.hostKey)
+	md5 := ssh.FingerprintLegacyMD5(b.hostKey)
+	cases := []struct {
+		name    string
+		hostKey string
+		ok      bool
+	}{
+		{"sha256", sha, true},
+		{"sha256 padded", sha + "=", true},
+		{"md5", "MD5:" + md5, true},
+		{"md5 upper case", "md5:" + strings.ToUpper(md5), true},
+		{"sha256 mismatch", ssh.FingerprintSHA256(otherKey), false},
+		{"md5 mismatch", "MD5:" + ssh.FingerprintLegacyMD5(otherKey), false},
+		{"one of several", "MD5:" + ssh.FingerprintLegacyMD5(otherKey) + "\n\n" + sha + "\n", true},
+		{"key and md5 of another host", string(ssh.MarshalAuthorizedKey(otherKey)) + "MD5:" + ssh.FingerprintLegacyMD5(otherKey), false},
+		{"unpinned, verification skipped", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := bastionCfg(t, b, "pw")
+			cfg.HostKey = tc.hostKey
+			cfg.InsecureIgnoreHostKey = tc.hostKey == ""
+			d, err := newBastionDialer(cfg, 5*time.Second)
+			if err != nil {
+				t.Fatalf("newBastionDialer: %v", err)
+			}
+			conn, err := d.Dial("tcp", target)
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("Dial: %v", err)
+				}
+				conn.Close()
+				return
+			}
+			if classifyTransportError(err) != FailureAuth || !strings.Contains(err.Error(), "host key mismatch: got ssh-ed25519 "+sha) {
+				t.Fatalf("kind = %q (%v), want auth host key mismatch", classifyTransportError(err), err)
+			}
+			if strings.Contains(tc.hostKey, "MD5:") && !strings.Contains(err.Error(), "MD5:"+md5) {
+				t.Errorf("mismatch against an MD5 pin must show the MD5 fingerprint: %v", err)
+			}
+		})
+	}
+}
+
+// TestBastionDialer_EncryptedKey checks that passphrase-protected RSA (legacy
+// PEM) and Ed25519 (OpenSSH) keys authenticate with KeyPassphrase, and that
+// a missing or wrong passphrase fails with a clear error.
+func TestBastionDialer_EncryptedKey(t *testing.T) {
+	target := startEchoServer(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//nolint:staticcheck // legacy PEM encryption is what old keys use
+	rsaBlock, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), []byte("s3cret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edBlock, err := ssh.MarshalPrivateKeyWithPassphrase(edKey, "", []byte("s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		key   any
+		block *pem.Block
+	}{
+		"rsa pem":         {rsaKey, rsaBlock},
+		"ed25519 openssh": {edKey, edBlock},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer, err := ssh.NewSignerFromKey(tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authorized := signer.PublicKey().Marshal()
+			b := startTestBastion(t, func(s *ssh.ServerConfig) {
+				s.PublicKeyCallback = func(c ssh.ConnMetadata, k ssh.PublicKey) (*ssh.Permissions, error) {
+					if c.User() == "u" && string(k.Marshal()) == string(authorized) {
+						return nil, nil
+					}
+					return nil, io.EOF
+				}
+			})
+			keyPath := filepath.Join(t.TempDir(), "id")
+			if err := os.WriteFile(keyPath, pem.EncodeToMemory(tc.block), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := bastionCfg(t, b, "")
+			cfg.KeyPath = keyPath
+			if _, err := newBastionDialer(cfg, 5*time.Second); err == nil || !strings.Contains(err.Error(), "set bastion_key_passphrase") {
+				t.Errorf("encrypted key without passphrase: %v", err)
+			}
+			cfg.KeyPassphrase = "wrong"
+			if _, err := newBastionDialer(cfg, 5*time.Second); err == nil {
+				t.Error("wrong passphrase must be rejected")
+			}
+
+			cfg.KeyPassphrase = "s3cret"
+			d, err := newBastionDialer(cfg, 5*time.Second)
+			if err != nil {
+				t.Fatalf("newBastionDialer: %v", err)
+			}
+			conn, err := d.Dial("tcp", target)
+			if err != nil {
+				t.Fatalf("Dial with the decrypted key: %v", err)
+			}
+			conn.Close()
+		})
+	}
+}
+
+func TestNewBastionDialer_Validation(t *testing.T) {
+	cases := map[string]*BastionConfig{
+		"no host":                {Username: "u", Password: "p"},
+		"no username":            {Host: "jump", Password: "p"},
+		"no credential":          {Host: "jump", Username: "u"},
+		"no host key":            {Host: "jump", Username: "u", Password: "p"},
+		"missing key":            {Host: "jump", Username: "u", KeyPath: "/nonexistent/id_ed25519"},
+		"bad host key":           {Host: "jump", Username: "u", Password: "p", HostKey: "not a key"},
+		"short md5":              {Host: "jump", Username: "u", Password: "p", HostKey: "MD5:aa:bb:cc"},
+		"bad sha256":             {Host: "jump", Username: "u", Password: "p", HostKey: "SHA256:not*base64"},
+		"bad cipher":             {Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true, Ciphers: []string{"blowfish-cbc"}},
+		"bad kex":                {Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true, KeyExchanges: []string{"curve25519"}},
+		"bad mac":                {Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true, MACs: []string{"hmac-md5"}},
+		"bad host key algorithm": {Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true, HostKeyAlgorithms: []string{"ssh-ed448"}},
+	}
+	for name, cfg := range cases {
+		if _, err := newBastionDialer(cfg, time.Second); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := New(Config{Host: "win01", Username: "u", Password: "p", Bastion: &BastionConfig{Host: "jump"}}); err == nil {
+		t.Error("New must reject an invalid bastion")
+	}
+}
+
+// TestBastionDialer_Algorithms checks that Ciphers and KeyExchanges let the client
+// reach a bastion restricted to algorithms x/crypto/ssh does not offer by
+// default.
+func TestBastionDialer_Algorithms(t *testing.T) {
+	target := startEchoServer(t)
+	cases := []struct {
+		name   string
+		server ssh.Config
+		client func(*BastionConfig)
+	}{
+		{"legacy cipher", ssh.Config{Ciphers: []string{"aes128-cbc"}},
+			func(c *BastionConfig) { c.Ciphers = []string{"aes128-ctr", "aes128-cbc"} }},
+		{"legacy key exchange", ssh.Config{KeyExchanges: []string{"diffie-hellman-group1-sha1"}},
+			func(c *BastionConfig) { c.KeyExchanges = []string{"curve25519-sha256", "diffie-hellman-group1-sha1"} }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := startTestBastion(t, func(s *ssh.ServerConfig) { s.Config = tc.server })
+
+			d, err := newBastionDialer(bastionCfg(t, b, "pw"), 5*time.Second)
+			if err != nil {
+				t.Fatalf("newBastionDialer: %v", err)
+			}
+			if _, err := d.Dial("tcp", target); err == nil || !strings.Contains(err.Error(), "no common algorithm") {
+				t.Fatalf("defaults must not negotiate with the restricted bastion, got %v", err)
+			}
+
+			cfg := bastionCfg(t, b, "pw")
+			tc.client(cfg)
+			d, err = newBastionDialer(cfg, 5*time.Second)
+			if err != nil {
+				t.Fatalf("newBastionDialer: %v", err)
+			}
+			conn, err := d.Dial("tcp", target)
+			if err != nil {
+				t.Fatalf("Dial with %s: %v", tc.name, err)
+			}
+			conn.Close()
+		})
+	}
+}
+
+// TestBastionDialer_HostKeyAlgorithms checks that HostKeyAlgorithms picks
+// which of a bastion's host keys is negotiated, so an Ed25519 pin matches a
+// bastion that also has an ECDSA key (preferred by default).
+func TestBastionDialer_HostKeyAlgorithms(t *testing.T) {
+	target := startEchoServer(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecSigner, err := ssh.NewSignerFromKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := startTestBastion(t, func(s *ssh.ServerConfig) { s.AddHostKey(ecSigner) })
+
+	d, err := newBastionDialer(bastionCfg(t, b, "pw"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("newBastionDialer: %v", err)
+	}
+	if _, err := d.Dial("tcp", target); err == nil || !strings.Contains(err.Error(), ssh.FingerprintSHA256(ecSigner.PublicKey())) {
+		t.Fatalf("by default the ECDSA key must be negotiated and fail the Ed25519 pin, got %v", err)
+	}
+
+	cfg := bastionCfg(t, b, "pw")
+	cfg.HostKeyAlgorithms = []string{ssh.KeyAlgoED25519}
+	d, err = newBastionDialer(cfg, 5*time.Second)
+	if err != nil {
+		t.Fatalf("newBastionDialer: %v", err)
+	}
+	conn, err := d.Dial("tcp", target)
+	if err != nil {
+		t.Fatalf("Dial with ssh-ed25519 preferred: %v", err)
+	}
+	conn.Close()
+
+	host, portStr, _ := net.SplitHostPort(b.addr)
+	port, _ := strconv.Atoi(portStr)
+	fp, _, err := GetHostKeyFingerprint(context.Background(), host, port, ssh.KeyAlgoED25519)
+	if err != nil || fp != ssh.FingerprintSHA256(b.hostKey) {
+		t.Errorf("GetHostKeyFingerprint with ssh-ed25519 = %q, %v; want the Ed25519 key", fp, err)
+	}
+}
+
+func TestNewBastionDialer_IPv6Host(t *testing.T) {
+	for _, host := range []string{"2001:db8::1", "[2001:db8::1]"} {
+		d, err := newBastionDialer(&BastionConfig{Host: host, Port: 2222, Username: "u", Password: "p", InsecureIgnoreHostKey: true}, time.Second)
+		if err != nil {
+			t.Fatalf("%q: %v", host, err)
+		}
+		if d.addr != "[2001:db8::1]:2222" {
+			t.Errorf("%q: addr = %q, want [2001:db8::1]:2222", host, d.addr)
+		}
+	}
+}
+
+func TestGetHostKeyFingerprint(t *testing.T) {
+	b := startTestBastion(t)
+	host, portStr, _ := net.SplitHostPort(b.addr)
+	port, _ := strconv.Atoi(portStr)
+
+	fp, key, err := GetHostKeyFingerprint(context.Background(), host, port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ssh.FingerprintSHA256(b.hostKey); fp != want {
+		t.Errorf("fingerprint = %q, want %q", fp, want)
+	}
+	if !strings.HasPrefix(fp, "SHA256:") || strings.HasSuffix(key, "\n") {
+		t.Errorf("fingerprint = %q, key = %q", fp, key)
+	}
+
+	// The returned key pins the bastion for a normal connection.
+	cfg := bastionCfg(t, b, "pw")
+	cfg.HostKey = key
+	d, err := newBastionDialer(cfg, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := d.Dial("tcp", startEchoServer(t))
+	if err != nil {
+		t.Fatalf("Dial with captured host key: %v", err)
+	}
+	conn.Close()
+}
+
+func TestGetHostKeyFingerprint_Errors(t *testing.T) {
+	// A closed port.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().(*net.TCPAddr)
+	ln.Close()
+	if _, _, err := GetHostKeyFingerprint(context.Background(), "127.0.0.1", addr.Port); err == nil {
+		t.Error("closed port: expected an error")
+	}
+
+	// A server that accepts but never speaks SSH: ctx bounds the wait.
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	go func() {
+		for {
+			c, err := silent.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err = GetHostKeyFingerprint(ctx, "127.0.0.1", silent.Addr().(*net.TCPAddr).Port)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("silent server: err = %v, want deadline exceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("silent server: ctx deadline not honoured")
+	}
+}
@@ -0,0 +1,413 @@
+// Package winclient: PFX import into the Windows certificate store over WinRM.
+//
+// CertificateClient is the concrete WindowsCertificateClient backing the
+// windows_certificate resource.
+//
+// Security invariants:
+//   - The PFX and its password are sent as a JSON document on stdin through
+//     Client.RunPowerShellWithInput; nothing secret is interpolated into the
+//     script body or copied into error context.
+//   - Import-PfxCertificate only reads files, so the PFX is written to a
+//     per-run directory under the WinRM user's %TEMP%. The script overwrites
+//     the file with zeros and removes the directory in a finally block. If
+//     the script dies before getting there (cancellation, timeout, transport
+//     loss) the directory is removed again on a fresh context; see
+//     temp_artifacts.go.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Compile-time assertion: CertificateClient satisfies WindowsCertificateClient.
+var _ WindowsCertificateClient = (*CertificateClient)(nil)
+
+// CertificateClient is the PowerShell/WinRM-backed WindowsCertificateClient.
+type CertificateClient struct {
+	c *Client
+}
+
+// NewCertificateClient wraps the given WinRM Client.
+func NewCertificateClient(c *Client) *CertificateClient { return &CertificateClient{c: c} }
+
+// runCertificatePowerShell is the package-level indirection used by
+// CertificateClient. Tests may override it; production code must not.
+var runCertificatePowerShell = func(ctx context.Context, c *Client, script, stdin string) (string, string, error) {
+	return c.RunPowerShellWithInput(ctx, script, stdin)
+}
+
+// certStagingRoot is the %TEMP%-relative directory holding per-run PFX
+// staging directories.
+const certStagingRoot = "windows_certificate"
+
+// certThumbprintRe matches a SHA-1 thumbprint.
+var certThumbprintRe = regexp.MustCompile(`^[0-9A-Fa-f]{40}$`)
+
+// certStoreNameRe matches a store name. It keeps the Cert:\ path from being
+// steered elsewhere with separators or wildcards.
+var certStoreNameRe = regexp.MustCompile(`^[A-Za-z0-9 ._-]+$`)
+
+// certPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type certPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// certImportPayload is the stdin document consumed by psImportCertificate.
+type certImportPayload struct {
+	PFXBase64     string `json:"pfx_base64"`
+	Password      string `json:"password"`
+	StoreLocation string `json:"store_location"`
+	StoreName     string `json:"store_name"`
+	StagingDir    string `json:"staging_dir"`
+}
+
+// certStatePayload is the "data" object emitted by Get-CertData.
+type certStatePayload struct {
+	Thumbprint    string `json:"thumbprint"`
+	Subject       string `json:"subject"`
+	Issuer        string `json:"issuer"`
+	FriendlyName  string `json:"friendly_name"`
+	NotBefore     string `json:"not_before"`
+	NotAfter      string `json:"not_after"`
+	HasPrivateKey bool   `json:"has_private_key"`
+	StoreLocation string `json:"store_location"`
+	StoreName     string `json:"store_name"`
+}
+
+// psCertificateHeader prepends Emit-OK/Emit-Err, Classify-Cert and the
+// shared certificate helpers.
+const psCertificateHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+$WarningPreference     = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Classify-Cert([string]$Msg) {
+  if ($Msg -match 'password') { return 'invalid_password' }
+  if ($Msg -match 'Access is denied' -or $Msg -match 'UnauthorizedAccess') { return 'permission_denied' }
+  if ($Msg -match 'ASN1' -or $Msg -match 'bad data' -or $Msg -match 'Cannot find the requested object' -or $Msg -match 'not a valid') { return 'invalid_pfx' }
+  return 'unknown'
+}
+function Get-CertStorePath([string]$Location, [string]$Store) {
+  return 'Cert:\' + $Location + '\' + $Store
+}
+function Find-Cert([string]$StorePath, [string]$Thumbprint) {
+  if (-not (Test-Path -LiteralPath $StorePath)) { return $null }
+  return Get-ChildItem -LiteralPath $StorePath | Where-Object { $_.Thumbprint -eq $Thumbprint } | Select-Object -First 1
+}
+function Get-CertData($Cert, [string]$Location, [string]$Store) {
+  return [ordered]@{
+    thumbprint      = [string]$Cert.Thumbprint
+    subject         = [string]$Cert.Subject
+    issuer          = [string]$Cert.Issuer
+    friendly_name   = [string]$Cert.FriendlyName
+    not_before      = $Cert.NotBefore.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+    not_after       = $Cert.NotAfter.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+    has_private_key = [bool]$Cert.HasPrivateKey
+    store_location  = $Location
+    store_name      = $Store
+  }
+}
+`
+
+// psImportCertificate stages the PFX from the stdin document, imports it and
+// wipes the staged copy. A PFX with a chain imports every certificate into
+// the same store; the one holding a private key is reported.
+const psImportCertificate = `
+try {
+  $cfg = [Console]::In.ReadToEnd() | ConvertFrom-Json
+  $storePath = Get-CertStorePath $cfg.store_location $cfg.store_name
+  if (-not (Test-Path -LiteralPath $storePath)) {
+    Emit-Err 'invalid_parameter' ('certificate store ' + $storePath + ' does not exist') @{ store = $storePath }
+    return
+  }
+  $dir = Join-Path $env:TEMP ([string]$cfg.staging_dir)
+  $file = Join-Path $dir 'import.pfx'
+  $bytes = $null
+  try {
+    try {
+      $bytes = [Convert]::FromBase64String([string]$cfg.pfx_base64)
+    } catch {
+      Emit-Err 'invalid_pfx' 'pfx_base64 is not valid base64' @{}
+      return
+    }
+    New-Item -ItemType Directory -Path $dir -Force | Out-Null
+    [IO.File]::WriteAllBytes($file, $bytes)
+    $pw = New-Object System.Security.SecureString
+    foreach ($ch in ([string]$cfg.password).ToCharArray()) { $pw.AppendChar($ch) }
+    $certs = @(Import-PfxCertificate -FilePath $file -CertStoreLocation $storePath -Password $pw)
+    if ($certs.Count -eq 0) {
+      Emit-Err 'invalid_pfx' 'the PFX contains no certificate' @{}
+      return
+    }
+    $leaf = $certs | Where-Object { $_.HasPrivateKey } | Select-Object -First 1
+    if (-not $leaf) { $leaf = $certs[0] }
+    Emit-OK (Get-CertData $leaf $cfg.store_location $cfg.store_name)
+  } finally {
+    if ($bytes) { [Array]::Clear($bytes, 0, $bytes.Length) }
+    if (Test-Path -LiteralPath $file) {
+      $fs = [IO.File]::Open($file, 'Open', 'Write')
+      try {
+        $fs.Write((New-Object byte[] $fs.Length), 0, $fs.Length)
+        $fs.Flush($true)
+      } finally { $fs.Dispose() }
+    }
+    Remove-Item -LiteralPath $dir -Recurse -Force -ErrorAction SilentlyContinue
+  }
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Cert $msg) $msg @{}
+}
+`
+
+// psReadCertificate reports the certificate, or null when it is absent. The
+// location, store and thumbprint have been validated and quoted Go-side.
+const psReadCertificate = `
+try {
+  $c = Find-Cert (Get-CertStorePath %[1]s %[2]s) %[3]s
+  if (-not $c) { Emit-OK $null; return }
+  Emit-OK (Get-CertData $c %[1]s %[2]s)
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Cert $msg) $msg @{}
+}
+`
+
+// psDeleteCertificate removes the certificate and, with -DeleteKey, its
+// private key container, so no orphaned key is left on disk.
+const psDeleteCertificate = `
+try {
+  $c = Find-Cert (Get-CertStorePath %[1]s %[2]s) %[3]s
+  if ($c) {
+    if ($c.HasPrivateKey) {
+      Remove-Item -LiteralPath $c.PSPath -DeleteKey -Force
+    } else {
+      Remove-Item -LiteralPath $c.PSPath -Force
+    }
+  }
+  Emit-OK $null
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Cert $msg) $msg @{}
+}
+`
+
+// runCertEnvelope executes script (prepended with psCertificateHeader) with
+// stdin and parses the JSON envelope. Cancellation maps to
+// CertificateErrorTimeout; other transport failures to CertificateErrorUnknown.
+func (cc *CertificateClient) runCertEnvelope(ctx context.Context, op, script, stdin string) (*certPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psCertificateHeader + "\n" + script
+	stdout, stderr, err := runCertificatePowerShell(ctx, cc.c, full, stdin)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      cc.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewCertificateError(CertificateErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewCertificateError(CertificateErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewCertificateError(CertificateErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp certPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewCertificateError(CertificateErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewCertificateError(mapCertificateKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapCertificateKind translates a PS-side "kind" string to a typed
+// CertificateErrorKind. Unknown values fall through to CertificateErrorUnknown.
+func mapCertificateKind(k string) CertificateErrorKind {
+	switch k {
+	case string(CertificateErrorInvalidPFX),
+		string(CertificateErrorInvalidPassword),
+		string(CertificateErrorInvalidParameter),
+		string(CertificateErrorPermission),
+		string(CertificateErrorTimeout):
+		return CertificateErrorKind(k)
+	default:
+		return CertificateErrorUnknown
+	}
+}
+
+// canonicalStoreLocation returns the canonical spelling of location, or ""
+// when it is not one of CertificateStoreLocations.
+func canonicalStoreLocation(location string) string {
+	for _, l := range CertificateStoreLocations {
+		if strings.EqualFold(l, location) {
+			return l
+		}
+	}
+	return ""
+}
+
+// validateCertStore checks location and store and returns the canonical
+// location.
+func (cc *CertificateClient) validateCertStore(location, store string) (string, error) {
+	loc := canonicalStoreLocation(location)
+	if loc == "" {
+		return "", NewCertificateError(CertificateErrorInvalidParameter,
+			fmt.Sprintf("store_location must be one of %s, got %q", strings.Join(CertificateStoreLocations, ", "), location),
+			nil, map[string]string{"host": cc.c.cfg.Host})
+	}
+	if !certStoreNameRe.MatchString(store) {
+		return "", NewCertificateError(CertificateErrorInvalidParameter,
+			fmt.Sprintf("invalid store_name %q", store), nil, map[string]string{"host": cc.c.cfg.Host})
+	}
+	return loc, nil
+}
+
+// certLookupScript validates a (location, store, thumbprint) triple and
+// renders tmpl with the quoted values.
+func (cc *CertificateClient) certLookupScript(tmpl, location, store, thumbprint string) (string, error) {
+	loc, err := cc.validateCertStore(location, store)
+	if err != nil {
+		return "", err
+	}
+	if !certThumbprintRe.MatchString(thumbprint) {
+		return "", NewCertificateError(CertificateErrorInvalidParameter,
+			fmt.Sprintf("invalid thumbprint %q: expected 40 hex characters", thumbprint),
+			nil, map[string]string{"host": cc.c.cfg.Host})
+	}
+	return fmt.Sprintf(tmpl, psQuote(loc), psQuote(store), psQuote(strings.ToUpper(thumbprint))), nil
+}
+
+// parseCertificateState decodes the "data" object of an Import/Read
+// envelope; a null object yields (nil, nil).
+func (cc *CertificateClient) parseCertificateState(resp *certPSResponse) (*CertificateState, error) {
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return nil, nil
+	}
+	var p certStatePayload
+	if jerr := json.Unmarshal(resp.Data, &p); jerr != nil {
+		return nil, NewCertificateError(CertificateErrorUnknown,
+			"failed to parse certificate state", jerr,
+			map[string]string{"host": cc.c.cfg.Host})
+	}
+	return &CertificateState{
+		Thumbprint:    strings.ToUpper(p.Thumbprint),
+		Subject:       p.Subject,
+		Issuer:        p.Issuer,
+		FriendlyName:  p.FriendlyName,
+		NotBefore:     p.NotBefore,
+		NotAfter:      p.NotAfter,
+		HasPrivateKey: p.HasPrivateKey,
+		StoreLocation: p.StoreLocation,
+		StoreName:     p.StoreName,
+	}, nil
+}
+
+// Import stages and imports the PFX and returns the imported certificate.
+func (cc *CertificateClient) Import(ctx context.Context, in CertificateInput) (*CertificateState, error) {
+	loc, err := cc.validateCertStore(in.StoreLocation, in.StoreName)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.PFXBase64) == "" {
+		return nil, NewCertificateError(CertificateErrorInvalidPFX,
+			"pfx_base64 must not be empty", nil, map[string]string{"host": cc.c.cfg.Host})
+	}
+	cc.c.sweepTempArtifacts(ctx)
+
+	payload := certImportPayload{
+		PFXBase64:     in.PFXBase64,
+		Password:      in.Password,
+		StoreLocation: loc,
+		StoreName:     in.StoreName,
+		StagingDir:    certStagingRoot + `\imp_` + newTempToken(),
+	}
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return nil, NewCertificateError(CertificateErrorUnknown,
+			"failed to encode import input", err, map[string]string{"host": cc.c.cfg.Host})
+	}
+	resp, err := cc.runCertEnvelope(ctx, "import", psImportCertificate, string(stdin))
+	if err != nil {
+		// A nil envelope means the script never reported back, so its
+		// finally block cannot be trusted to have run.
+		if resp == nil {
+			cc.c.cleanupTempArtifact(ctx, payload.StagingDir)
+		}
+		return nil, err
+	}
+	st, err := cc.parseCertificateState(resp)
+	if err == nil && st == nil {
+		err = NewCertificateError(CertificateErrorUnknown,
+			"import reported no certificate", nil, map[string]string{"host": cc.c.cfg.Host})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Read returns the certificate, or (nil, nil) when it is not in the store.
+func (cc *CertificateClient) Read(ctx context.Context, location, store, thumbprint string) (*CertificateState, error) {
+	script, err := cc.certLookupScript(psReadCertificate, location, store, thumbprint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cc.runCertEnvelope(ctx, "read", script, "")
+	if err != nil {
+		return nil, err
+	}
+	return cc.parseCertificateState(resp)
+}
+
+// Delete removes the certificate and its private key; a missing certificate
+// is not an error.
+func (cc *CertificateClient) Delete(ctx context.Context, location, store, thumbprint string) error {
+	script, err := cc.certLookupScript(psDeleteCertificate, location, store, thumbprint)
+	if err != nil {
+		return err
+	}
+	_, err = cc.runCertEnvelope(ctx, "delete", script, "")
+	return err
+}
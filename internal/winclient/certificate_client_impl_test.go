@@ -0,0 +1,277 @@
+// Package winclient — unit tests for CertificateClient.
+//
+// These tests stub the package-level seams runCertificatePowerShell and
+// runTempCleanupPowerShell to inject scripted stdout/stderr/err triples and
+// to capture the stdin document and cleanup runs.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testThumbprint = "3F2A9C1D4B5E6F708192A3B4C5D6E7F809A1B2C3"
+
+func newCertTestClient(t *testing.T) *CertificateClient {
+	t.Helper()
+	c, err := New(Config{
+		Host:     "win01",
+		Username: "u",
+		Password: "p",
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return NewCertificateClient(c)
+}
+
+// stubCertRun replaces runCertificatePowerShell for the duration of a test
+// and returns a restore function (typically deferred).
+func stubCertRun(fn func(ctx context.Context, c *Client, script, stdin string) (string, string, error)) func() {
+	prev := runCertificatePowerShell
+	runCertificatePowerShell = fn
+	return func() { runCertificatePowerShell = prev }
+}
+
+func certOK(t *testing.T, data any) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"ok": true, "data": data})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b) + "\n"
+}
+
+func certErr(t *testing.T, kind, msg string) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"ok": false, "kind": kind, "message": msg, "context": map[string]string{}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b) + "\n"
+}
+
+func fakeCertData() map[string]any {
+	return map[string]any{
+		"thumbprint": strings.ToLower(testThumbprint), "subject": "CN=www.example.com",
+		"issuer": "CN=Example CA", "friendly_name": "", "not_before": "2026-01-01T00:00:00Z",
+		"not_after": "2027-01-01T00:00:00Z", "has_private_key": true,
+		"store_location": "LocalMachine", "store_name": "My",
+	}
+}
+
+func TestCertificateError_IsAndHelper(t *testing.T) {
+	err := NewCertificateError(CertificateErrorInvalidPassword, "bad password", errors.New("inner"), nil)
+	if !errors.Is(err, ErrCertificateInvalidPassword) {
+		t.Error("errors.Is should match on Kind")
+	}
+	if errors.Is(err, ErrCertificateUnknown) {
+		t.Error("errors.Is should not match a different Kind")
+	}
+	if !IsCertificateError(err, CertificateErrorInvalidPassword) {
+		t.Error("IsCertificateError should match")
+	}
+	if !errors.Is(NewCertificateError(CertificateErrorPermission, "denied", nil, nil), ErrAccessDenied) {
+		t.Error("permission_denied should be classed ErrAccessDenied")
+	}
+	if errors.Unwrap(err).Error() != "inner" {
+		t.Error("Unwrap should return cause")
+	}
+}
+
+func TestMapCertificateKind(t *testing.T) {
+	cases := map[string]CertificateErrorKind{
+		"invalid_pfx":       CertificateErrorInvalidPFX,
+		"invalid_password":  CertificateErrorInvalidPassword,
+		"invalid_parameter": CertificateErrorInvalidParameter,
+		"permission_denied": CertificateErrorPermission,
+		"timeout":           CertificateErrorTimeout,
+		"bogus":             CertificateErrorUnknown,
+	}
+	for in, want := range cases {
+		if got := mapCertificateKind(in); got != want {
+			t.Errorf("mapCertificateKind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCertificateImport_SecretsOnStdinOnly(t *testing.T) {
+	cc := newCertTestClient(t)
+	var script, stdin string
+	defer stubCertRun(func(_ context.Context, _ *Client, s, in string) (string, string, error) {
+		script, stdin = s, in
+		return certOK(t, fakeCertData()), "", nil
+	})()
+
+	st, err := cc.Import(context.Background(), CertificateInput{
+		PFXBase64: "TUlJS1BGWA==", Password: "Pfx!pw", StoreLocation: "localmachine", StoreName: "My",
+	})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if strings.Contains(script, "TUlJS1BGWA==") || strings.Contains(script, "Pfx!pw") {
+		t.Error("PFX and password must not appear in the script body")
+	}
+	var p certImportPayload
+	if err := json.Unmarshal([]byte(stdin), &p); err != nil {
+		t.Fatalf("stdin: %v", err)
+	}
+	if p.PFXBase64 != "TUlJS1BGWA==" || p.Password != "Pfx!pw" || p.StoreLocation != "LocalMachine" || p.StoreName != "My" {
+		t.Errorf("stdin payload = %+v", p)
+	}
+	if !strings.HasPrefix(p.StagingDir, certStagingRoot+`\imp_`) {
+		t.Errorf("staging dir = %q", p.StagingDir)
+	}
+	for _, want := range []string{"Import-PfxCertificate", "finally", "Remove-Item -LiteralPath $dir -Recurse -Force", "$fs.Flush($true)"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	if st.Thumbprint != testThumbprint || st.Subject != "CN=www.example.com" || !st.HasPrivateKey ||
+		st.NotAfter != "2027-01-01T00:00:00Z" || st.StoreLocation != "LocalMachine" {
+		t.Errorf("state = %+v", st)
+	}
+}
+
+func TestCertificateImport_Validation(t *testing.T) {
+	cc := newCertTestClient(t)
+	defer stubCertRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		t.Fatal("no remote call expected")
+		return "", "", nil
+	})()
+	cases := map[string]CertificateInput{
+		"bad location": {PFXBase64: "AA==", StoreLocation: "Machine", StoreName: "My"},
+		"bad store":    {PFXBase64: "AA==", StoreLocation: "LocalMachine", StoreName: `My\..\Root`},
+		"wildcard":     {PFXBase64: "AA==", StoreLocation: "LocalMachine", StoreName: "*"},
+	}
+	for name, in := range cases {
+		if _, err := cc.Import(context.Background(), in); !IsCertificateError(err, CertificateErrorInvalidParameter) {
+			t.Errorf("%s: err = %v, want invalid_parameter", name, err)
+		}
+	}
+	_, err := cc.Import(context.Background(), CertificateInput{StoreLocation: "LocalMachine", StoreName: "My"})
+	if !IsCertificateError(err, CertificateErrorInvalidPFX) {
+		t.Errorf("empty PFX: err = %v, want invalid_pfx", err)
+	}
+}
+
+func TestCertificateImport_EmitErrLeavesCleanupToScript(t *testing.T) {
+	cc := newCertTestClient(t)
+	defer stubCertRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return certErr(t, "invalid_password", "The PFX file you are trying to import requires either a different password"), "", nil
+	})()
+	cleanups := 0
+	defer stubTempCleanup(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		cleanups++
+		return `{"failed":[]}`, "", nil
+	})()
+
+	_, err := cc.Import(context.Background(), CertificateInput{PFXBase64: "AA==", Password: "x", StoreLocation: "LocalMachine", StoreName: "My"})
+	if !errors.Is(err, ErrCertificateInvalidPassword) {
+		t.Fatalf("err = %v, want invalid_password", err)
+	}
+	if strings.Contains(err.Error(), "AA==") {
+		t.Error("error must not carry the PFX")
+	}
+	if cleanups != 0 {
+		t.Errorf("cleanups = %d, want 0: the script reported back, so its finally ran", cleanups)
+	}
+}
+
+func TestCertificateImport_CancelledRemovesStagedPFX(t *testing.T) {
+	cc := newCertTestClient(t)
+	var staged string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer stubCertRun(func(_ context.Context, _ *Client, _, in string) (string, string, error) {
+		var p certImportPayload
+		_ = json.Unmarshal([]byte(in), &p)
+		staged = p.StagingDir
+		cancel()
+		return "", "", context.Canceled
+	})()
+	var cleanupScript string
+	defer stubTempCleanup(func(ctx context.Context, _ *Client, s string) (string, string, error) {
+		if ctx.Err() != nil {
+			t.Error("cleanup must run on a fresh context")
+		}
+		cleanupScript = s
+		return `{"failed":[]}`, "", nil
+	})()
+
+	_, err := cc.Import(ctx, CertificateInput{PFXBase64: "AA==", StoreLocation: "LocalMachine", StoreName: "My"})
+	if !IsCertificateError(err, CertificateErrorTimeout) {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+	if staged == "" || !strings.Contains(cleanupScript, psQuote(staged)) {
+		t.Errorf("cleanup did not target the staging dir %q:\n%s", staged, cleanupScript)
+	}
+	if left := cc.c.pendingTempArtifacts(); len(left) != 0 {
+		t.Errorf("pending = %v", left)
+	}
+}
+
+func TestCertificateRead(t *testing.T) {
+	cc := newCertTestClient(t)
+	var script string
+	out := certOK(t, fakeCertData())
+	defer stubCertRun(func(_ context.Context, _ *Client, s, in string) (string, string, error) {
+		script = s
+		if in != "" {
+			t.Errorf("Read must not send stdin, got %q", in)
+		}
+		return out, "", nil
+	})()
+
+	st, err := cc.Read(context.Background(), "LocalMachine", "My", strings.ToLower(testThumbprint))
+	if err != nil || st == nil || st.Thumbprint != testThumbprint {
+		t.Fatalf("Read = %+v, %v", st, err)
+	}
+	if !strings.Contains(script, "Find-Cert (Get-CertStorePath 'LocalMachine' 'My') '"+testThumbprint+"'") {
+		t.Errorf("script does not look up the upper-cased thumbprint:\n%s", script)
+	}
+
+	out = certOK(t, nil)
+	st, err = cc.Read(context.Background(), "LocalMachine", "My", testThumbprint)
+	if err != nil || st != nil {
+		t.Errorf("missing certificate: Read = %+v, %v; want nil, nil", st, err)
+	}
+
+	if _, err := cc.Read(context.Background(), "LocalMachine", "My", "abc"); !IsCertificateError(err, CertificateErrorInvalidParameter) {
+		t.Errorf("short thumbprint: err = %v, want invalid_parameter", err)
+	}
+}
+
+func TestCertificateDelete(t *testing.T) {
+	cc := newCertTestClient(t)
+	var script string
+	defer stubCertRun(func(_ context.Context, _ *Client, s, _ string) (string, string, error) {
+		script = s
+		return certOK(t, nil), "", nil
+	})()
+
+	if err := cc.Delete(context.Background(), "CurrentUser", "WebHosting", testThumbprint); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, want := range []string{"Get-CertStorePath 'CurrentUser' 'WebHosting'", "-DeleteKey"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestCertificateRun_TransportError(t *testing.T) {
+	cc := newCertTestClient(t)
+	defer stubCertRun(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return "", "boom", errors.New("connection refused")
+	})()
+	_, err := cc.Read(context.Background(), "LocalMachine", "My", testThumbprint)
+	var ce *CertificateError
+	if !errors.As(err, &ce) || ce.Kind != CertificateErrorUnknown || ce.Context["stderr"] != "boom" {
+		t.Errorf("err = %#v", err)
+	}
+}
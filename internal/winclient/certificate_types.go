@@ -0,0 +1,134 @@
+// Package winclient: types for the windows_certificate resource.
+//
+// CertificateErrorKind / CertificateError follow the same shape as
+// FeatureError so the resource layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CertificateErrorKind categorises errors returned by WindowsCertificateClient.
+type CertificateErrorKind string
+
+const (
+	// CertificateErrorInvalidPFX: the PFX is not valid base64 or not a
+	// PKCS#12 file Windows can read.
+	CertificateErrorInvalidPFX CertificateErrorKind = "invalid_pfx"
+	// CertificateErrorInvalidPassword: the PFX password is wrong.
+	CertificateErrorInvalidPassword CertificateErrorKind = "invalid_password"
+	// CertificateErrorInvalidParameter: store location, store name or
+	// thumbprint rejected before anything ran.
+	CertificateErrorInvalidParameter CertificateErrorKind = "invalid_parameter"
+	CertificateErrorPermission       CertificateErrorKind = "permission_denied"
+	CertificateErrorTimeout          CertificateErrorKind = "timeout"
+	CertificateErrorUnknown          CertificateErrorKind = "unknown"
+)
+
+// CertificateError is the structured error type returned by
+// WindowsCertificateClient. Context never carries the PFX or its password.
+type CertificateError struct {
+	Kind    CertificateErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *CertificateError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_certificate [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_certificate [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *CertificateError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *CertificateError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*CertificateError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewCertificateError constructs a *CertificateError.
+func NewCertificateError(kind CertificateErrorKind, msg string, cause error, ctx map[string]string) *CertificateError {
+	return &CertificateError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsCertificateError reports whether err is a *CertificateError of the given
+// kind.
+func IsCertificateError(err error, kind CertificateErrorKind) bool {
+	var ce *CertificateError
+	if errors.As(err, &ce) {
+		return ce.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrCertificateInvalidPFX       = &CertificateError{Kind: CertificateErrorInvalidPFX}
+	ErrCertificateInvalidPassword  = &CertificateError{Kind: CertificateErrorInvalidPassword}
+	ErrCertificateInvalidParameter = &CertificateError{Kind: CertificateErrorInvalidParameter}
+	ErrCertificatePermission       = &CertificateError{Kind: CertificateErrorPermission}
+	ErrCertificateTimeout          = &CertificateError{Kind: CertificateErrorTimeout}
+	ErrCertificateUnknown          = &CertificateError{Kind: CertificateErrorUnknown}
+)
+
+// CertificateStoreLocations are the accepted store locations.
+var CertificateStoreLocations = []string{"LocalMachine", "CurrentUser"}
+
+// CertificateInput describes a PFX to import.
+type CertificateInput struct {
+	// PFXBase64 is the PKCS#12 file, base64-encoded. It is sent over stdin
+	// only and never appears in the script body or error context.
+	PFXBase64 string
+	// Password protects the PFX; empty for a PFX without one. Sent over
+	// stdin only.
+	Password string
+	// StoreLocation is LocalMachine or CurrentUser.
+	StoreLocation string
+	// StoreName is the store under StoreLocation ("My", "Root",
+	// "WebHosting", ...).
+	StoreName string
+}
+
+// CertificateState is the observed certificate in its store.
+type CertificateState struct {
+	// Thumbprint is the upper-case SHA-1 hex thumbprint.
+	Thumbprint    string
+	Subject       string
+	Issuer        string
+	FriendlyName  string
+	NotBefore     string // RFC3339, UTC
+	NotAfter      string // RFC3339, UTC
+	HasPrivateKey bool
+	StoreLocation string
+	StoreName     string
+}
+
+// WindowsCertificateClient is the contract for the windows_certificate
+// resource.
+type WindowsCertificateClient interface {
+	// Import writes the PFX to a private temp file, imports it with
+	// Import-PfxCertificate and removes the file on every exit path. When
+	// the PFX holds a chain, the returned state is the certificate with a
+	// private key (the leaf).
+	Import(ctx context.Context, in CertificateInput) (*CertificateState, error)
+
+	// Read returns the certificate with the given thumbprint, or (nil, nil)
+	// when it is not in the store.
+	Read(ctx context.Context, location, store, thumbprint string) (*CertificateState, error)
+
+	// Delete removes the certificate and its private key. It is idempotent.
+	Delete(ctx context.Context, location, store, thumbprint string) error
+}
@@ -10,7 +10,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf16"
 
@@ -22,6 +28,48 @@ import (
 type Client struct {
 	cfg   Config
 	winrm *winrm.Client
+	// bastion is the SSH bastion dialer, nil without Config.Bastion.
+	bastion *bastionDialer
+
+	// tempMu guards orphanTemp: %TEMP%-relative paths of remote artifacts a
+	// cancelled operation could not remove (see temp_artifacts.go).
+	tempMu     sync.Mutex
+	orphanTemp []string
+
+	// healthMu guards the failure counters, the authentication failures in
+	// a row and the latch they set, and the dial back-off window (see
+	// health.go).
+	healthMu     sync.Mutex
+	stats        ConnectionStats
+	authFailures int
+	authErr      *TransportError
+	authRetryAt  time.Time
+	dialBackoff  time.Duration
+	dialRetryAt  time.Time
+
+	// featureMu guards the windows_feature read cache and its generation,
+	// bumped by every Install/Uninstall (see feature_cache.go).
+	featureMu    sync.Mutex
+	featureCache map[string]*featureCacheEntry
+	featureGen   uint64
+
+	// featureBatch and userBatch are the Config.ReadBatching snapshots (see
+	// read_batch.go).
+	featureBatch readBatch[*FeatureInfo]
+	userBatch    readBatch[*UserState]
+
+	// dials counts the connections opened to the host, for
+	// CommandResult.NewConnections (see observer.go).
+	dials atomic.Int64
+
+	// lifeMu guards the in-flight run count and the shutdown state used by
+	// CloseGraceful (see shutdown.go).
+	lifeMu   sync.Mutex
+	closing  bool
+	inflight int
+	drained  chan struct{}
+	stopCtx  context.Context
+	stopRuns context.CancelFunc
 }
 
 // New creates and validates a new WinRM Client from the given Config.
@@ -49,15 +97,42 @@ func New(cfg Config) (*Client, error) {
 	if cfg.AuthType == "" {
 		cfg.AuthType = "ntlm"
 	}
+	if cfg.PowerShellPath == "" {
+		cfg.PowerShellPath = DefaultPowerShellPath
+	}
+	if strings.ContainsAny(cfg.PowerShellPath, "\"\r\n") {
+		return nil, fmt.Errorf("winclient: powershell path %q must not contain quotes or line breaks", cfg.PowerShellPath)
+	}
+
+	if cfg.RunAs != nil {
+		if err := cfg.RunAs.validate(); err != nil {
+			return nil, err
+		}
+	}
 
-	endpoint := winrm.NewEndpoint(cfg.Host, cfg.Port, cfg.UseHTTPS, cfg.Insecure, nil, nil, nil, cfg.Timeout)
+	endpoint := winrm.NewEndpoint(endpointHost(cfg.Host), cfg.Port, cfg.UseHTTPS, cfg.Insecure, nil, nil, nil, cfg.Timeout)
 
-	params := winrm.DefaultParameters
+	// winrm.DefaultParameters is a shared pointer: copy it, or the timeout,
+	// bastion dialer and transport of one client leak into every other.
+	params := *winrm.DefaultParameters
 	params.Timeout = fmt.Sprintf("PT%.0fS", cfg.Timeout.Seconds())
 
+	cl := &Client{cfg: cfg}
+	// The default matches winrm's own; wrapping it counts new connections.
+	dial := (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).Dial
+	if cfg.Bastion != nil {
+		var err error
+		if cl.bastion, err = newBastionDialer(cfg.Bastion, cfg.Timeout); err != nil {
+			return nil, err
+		}
+		dial = cl.bastion.Dial
+	}
+	params.Dial = cl.countDials(dial)
+
 	switch cfg.AuthType {
 	case "ntlm":
-		params.TransportDecorator = func() winrm.Transporter { return &winrm.ClientNTLM{} }
+		dial := params.Dial
+		params.TransportDecorator = func() winrm.Transporter { return winrm.NewClientNTLMWithDial(dial) }
 	case "basic":
 		// default transporter (basic auth over HTTP(S))
 	case "kerberos":
@@ -66,58 +141,68 @@ func New(cfg Config) (*Client, error) {
 		return nil, fmt.Errorf("winclient: unsupported auth_type %q", cfg.AuthType)
 	}
 
-	c, err := winrm.NewClientWithParameters(endpoint, cfg.Username, cfg.Password, params)
+	c, err := winrm.NewClientWithParameters(endpoint, cfg.Username, cfg.Password, &params)
 	if err != nil {
 		return nil, fmt.Errorf("winclient: create winrm client: %w", err)
 	}
+	cl.winrm = c
+	return cl, nil
+}
+
+// endpointHost returns host in the form winrm.Endpoint expects. The endpoint
+// URL is built as "scheme://host:port/wsman" without bracketing, so a bare
+// IPv6 literal (2001:db8::1) would produce an unparseable address; it is
+// wrapped in brackets here, with any zone ID percent-encoded as RFC 6874
+// requires. Hostnames, IPv4 addresses and already-bracketed literals are
+// returned unchanged.
+func endpointHost(host string) string {
+	if strings.HasPrefix(host, "[") {
+		return host
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !addr.Is6() {
+		return host
+	}
+	if zone := addr.Zone(); zone != "" {
+		return "[" + addr.WithZone("").String() + "%25" + url.PathEscape(zone) + "]"
+	}
+	return "[" + addr.String() + "]"
+}
 
-	return &Client{cfg: cfg, winrm: c}, nil
+// dialAddress returns the "host:port" address net.Dial expects for host,
+// which may be a hostname, an IPv4 address, or an IPv6 literal with or
+// without brackets. A bracketed literal may carry its zone ID percent-encoded
+// as for endpointHost ("[fe80::1%25eth0]"); it is decoded back to "%eth0".
+func dialAddress(host string, port int) string {
+	h := host
+	if strings.HasPrefix(h, "[") && strings.HasSuffix(h, "]") {
+		h = strings.Replace(h[1:len(h)-1], "%25", "%", 1)
+	}
+	return net.JoinHostPort(h, strconv.Itoa(port))
 }
 
 // Config returns a shallow copy of the client configuration (password
 // included — callers must not log it).
 func (c *Client) Config() Config { return c.cfg }
 
+// DefaultCommandTimeout returns Config.DefaultCommandTimeout: the provider
+// default for long-running resource operations, or 0 when unset.
+func (c *Client) DefaultCommandTimeout() time.Duration { return c.cfg.DefaultCommandTimeout }
+
 // RunPowerShell executes the given PowerShell script on the remote host and
 // returns its stdout and stderr. It honours the provided context for
 // cancellation.
 //
 // The script is not placed on the command line: only a fixed bootstrap is
 // passed via -EncodedCommand, and the real script (UTF-16LE base64) is streamed
-// on stdin, so the command line stays a constant ~600 chars regardless of script
+// on stdin, so the command line stays a constant ~900 chars regardless of script
 // size. This keeps us under Windows' ~8191-char command-line limit (#39) while
 // preserving exact UTF-16LE fidelity for non-ASCII values.
+//
+// Transport failures are returned as *TransportError, classified as dial,
+// auth or command failures (see health.go).
 func (c *Client) RunPowerShell(ctx context.Context, script string) (string, string, error) {
-	if c == nil || c.winrm == nil {
-		return "", "", fmt.Errorf("winclient: nil client")
-	}
-
-	cmd := bootstrapCommand()
-
-	var stdout, stderr bytes.Buffer
-	type result struct {
-		code int
-		err  error
-	}
-	done := make(chan result, 1)
-	stdin := composeStdin(script, "")
-	go func() {
-		code, err := c.winrm.RunWithContextWithInput(ctx, cmd, &stdout, &stderr, stdin)
-		done <- result{code: code, err: err}
-	}()
-
-	select {
-	case <-ctx.Done():
-		return stdout.String(), stderr.String(), ctx.Err()
-	case r := <-done:
-		if r.err != nil {
-			return stdout.String(), stderr.String(), fmt.Errorf("winclient: powershell run: %w", r.err)
-		}
-		if r.code != 0 {
-			return stdout.String(), stderr.String(), fmt.Errorf("winclient: powershell exited with code %d", r.code)
-		}
-		return stdout.String(), stderr.String(), nil
-	}
+	return c.run(ctx, script, "")
 }
 
 // RunPowerShellWithInput executes the given PowerShell script with the supplied
@@ -129,53 +214,178 @@ func (c *Client) RunPowerShell(ctx context.Context, script string) (string, stri
 // from the first stdin line, then the script itself reads the caller's input
 // from the remainder via [Console]::In.ReadLine() / ReadToEnd().
 func (c *Client) RunPowerShellWithInput(ctx context.Context, script, stdin string) (string, string, error) {
+	return c.run(ctx, script, stdin)
+}
+
+// run executes script through the bootstrap command, with input as the rest
+// of stdin, and records the outcome in the connection statistics. With
+// Config.RunAs the script runs under that account (see runas.go). CLIXML
+// error records on stderr are decoded to text (see clixml.go) and the
+// bootstrap's status line is removed; a script that failed without writing a
+// JSON envelope is reported as a *TransportError carrying the script's exit
+// code.
+func (c *Client) run(ctx context.Context, script, input string) (string, string, error) {
+	stdin, obs := c.observeRun(ctx, c.runStdin(script, input))
+	stdout, stderr, err := c.runCommand(ctx, c.commandLine(), stdin, obs)
+	stderr, status, ok := c.splitRunStatus(ParseCLIXMLError(stderr))
+	if err == nil && ok && status.failed() && extractLastJSONLine(stdout) == "" {
+		err = c.recordScriptFailure(status)
+	}
+	obs.done(err)
+	return stdout, stderr, err
+}
+
+// runCommand executes cmd with stdin (nil for none), records the outcome in
+// the connection statistics and in obs, which the caller completes with the
+// error it returns (see observer.go).
+func (c *Client) runCommand(ctx context.Context, cmd string, stdin io.Reader, obs *runObservation) (string, string, error) {
 	if c == nil || c.winrm == nil {
 		return "", "", fmt.Errorf("winclient: nil client")
 	}
+	callerCtx := ctx
+	ctx, release, err := c.beginRun(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	defer release()
+	if err := c.beforeRun(ctx); err != nil {
+		return "", "", err
+	}
+	obs.sending()
 
-	cmd := bootstrapCommand()
-
-	var stdout, stderr bytes.Buffer
+	// The buffers are read below as soon as ctx is done, while the winrm
+	// copy goroutines may still be writing to them until the remote command
+	// acknowledges the terminate signal, hence the locking.
+	var stdout, stderr lockedBuffer
 	type result struct {
 		code int
 		err  error
 	}
 	done := make(chan result, 1)
-	stdinReader := composeStdin(script, stdin)
 	go func() {
-		code, err := c.winrm.RunWithContextWithInput(ctx, cmd, &stdout, &stderr, stdinReader)
+		code, err := c.winrm.RunWithContextWithInput(ctx, cmd, &stdout, &stderr, stdin)
 		done <- result{code: code, err: err}
 	}()
 
 	select {
 	case <-ctx.Done():
-		return stdout.String(), stderr.String(), ctx.Err()
+		c.recordRun(ctx, nil, 0)
+		go c.checkBastionSession()
+		err := ctx.Err()
+		if c.forcedByClose(callerCtx) {
+			err = fmt.Errorf("%w: run interrupted after the close deadline", ErrClientClosed)
+		}
+		out, errOut := stdout.String(), stderr.String()
+		obs.received(out, errOut)
+		return out, errOut, err
 	case r := <-done:
 		if r.err != nil {
-			return stdout.String(), stderr.String(), fmt.Errorf("winclient: powershell run: %w", r.err)
-		}
-		if r.code != 0 {
-			return stdout.String(), stderr.String(), fmt.Errorf("winclient: powershell exited with code %d", r.code)
+			c.checkBastionSession()
 		}
-		return stdout.String(), stderr.String(), nil
+		out, errOut := stdout.String(), stderr.String()
+		obs.received(out, errOut)
+		return out, errOut, c.recordRun(ctx, r.err, r.code)
 	}
 }
 
+// lockedBuffer is a bytes.Buffer that is safe for concurrent Write and
+// String calls.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 // psBootstrap is the constant script passed via -EncodedCommand. It reads a
 // single base64 (UTF-16LE) line from stdin, decodes it to the real script, and
 // executes it. Because the large payload travels on stdin rather than the
-// command line, the command line stays a fixed ~600 chars — well under Windows'
+// command line, the command line stays a fixed ~900 chars — well under Windows'
 // ~8191-char limit (#39). Errors are surfaced by the invoked script's own JSON
 // envelope; a failed decode throws (ErrorActionPreference=Stop) and exits non-zero.
+//
+// $PSDefaultParameterValues is reset before the script runs so that a
+// host-side default (e.g. '*:ComputerName' set by a management-server session
+// configuration or module) can never silently retarget Get-Service,
+// Install-WindowsFeature, Get-LocalUser & co. to another machine: every
+// cmdlet acts on the host the WinRM session is connected to.
+//
+// After the script returns, the bootstrap writes a status line with
+// $LASTEXITCODE and $? to stderr (see script_status.go). The process itself
+// still exits 0, so scripts that check a native command's exit code and
+// report it in their envelope keep working.
 const psBootstrap = `$ErrorActionPreference='Stop'
+$global:PSDefaultParameterValues=@{}
 $b64=[Console]::In.ReadLine()
 $code=[Text.Encoding]::Unicode.GetString([Convert]::FromBase64String($b64))
-& ([ScriptBlock]::Create($code))`
+$global:LASTEXITCODE=0
+& ([ScriptBlock]::Create($code))
+$s=$?
+[Console]::Error.WriteLine("` + scriptStatusPrefix + `$LASTEXITCODE $s")`
 
-// bootstrapCommand builds the fixed powershell.exe invocation. It does not
-// depend on the script being run, so its length is constant.
-func bootstrapCommand() string {
-	return fmt.Sprintf("powershell.exe -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand %s", encodePowerShell(psBootstrap))
+// bootstrapCommand builds the fixed invocation of the PowerShell executable
+// exe (powershell.exe or pwsh.exe accept the same flags). It does not depend
+// on the script being run, so its length is constant. A path containing
+// spaces (C:\Program Files\PowerShell\7\pwsh.exe) is quoted.
+func bootstrapCommand(exe string) string {
+	return quoteExecutable(exe) + " " + bootstrapArgs()
+}
+
+// bootstrapArgs returns the flags bootstrapCommand passes to the executable.
+func bootstrapArgs() string {
+	return "-NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand " + encodePowerShell(psBootstrap)
+}
+
+// rawBootstrapCommand is bootstrapCommand without -EncodedCommand, for
+// Config.DisableEncodedCommand: the bootstrap is passed as plain -Command
+// text, on one line and without double quotes, so it reads as-is in process
+// creation logs and cannot be split by the shell that starts it. The script
+// itself still travels base64-framed on stdin; script block logging records
+// it decoded either way.
+func rawBootstrapCommand(exe string) string {
+	return quoteExecutable(exe) + " " + rawBootstrapArgs()
+}
+
+// rawBootstrapArgs returns the flags rawBootstrapCommand passes to the
+// executable.
+func rawBootstrapArgs() string {
+	body := strings.NewReplacer(
+		`("`+scriptStatusPrefix+`$LASTEXITCODE $s")`, `('`+scriptStatusPrefix+`'+$LASTEXITCODE+' '+$s)`,
+		"\n", "; ",
+	).Replace(psBootstrap)
+	return `-NoProfile -NonInteractive -ExecutionPolicy Bypass -Command "` + body + `"`
+}
+
+// quoteExecutable quotes exe when it contains spaces or tabs.
+func quoteExecutable(exe string) string {
+	if strings.ContainsAny(exe, " \t") {
+		return `"` + exe + `"`
+	}
+	return exe
+}
+
+// commandLine returns the bootstrap invocation for the client's
+// configuration.
+func (c *Client) commandLine() string {
+	return quoteExecutable(c.cfg.PowerShellPath) + " " + c.commandArgs()
+}
+
+// commandArgs returns the bootstrap flags for the client's configuration.
+func (c *Client) commandArgs() string {
+	if c.cfg.DisableEncodedCommand {
+		return rawBootstrapArgs()
+	}
+	return bootstrapArgs()
 }
 
 // composeStdin lays out the stdin stream the bootstrap expects: the base64
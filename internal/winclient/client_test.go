@@ -2,12 +2,24 @@ package winclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 	"unicode/utf16"
+
+	"github.com/masterzen/winrm"
 )
 
 // decodePowerShell reverses encodePowerShell: base64 -> UTF-16LE -> string.
@@ -40,25 +52,70 @@ func TestEncodePowerShellRoundTrip(t *testing.T) {
 	}
 }
 
+// TestEncodePowerShellKnownVectors pins encodePowerShell to the stdlib
+// base64 of hand-written UTF-16LE bytes, including a surrogate pair and the
+// padding cases, so a regression in either step is caught without relying on
+// the round trip.
+func TestEncodePowerShellKnownVectors(t *testing.T) {
+	cases := []struct {
+		script string
+		utf16  []byte
+	}{
+		{"A", []byte{0x41, 0x00}},                      // "==" padding
+		{"AB", []byte{0x41, 0x00, 0x42, 0x00}},         // "=" padding
+		{"é€", []byte{0xE9, 0x00, 0xAC, 0x20}},         // BMP, non-ASCII
+		{"\U0001F600", []byte{0x3D, 0xD8, 0x00, 0xDE}}, // surrogate pair
+		{"Get-Date", []byte("G\x00e\x00t\x00-\x00D\x00a\x00t\x00e\x00")},
+	}
+	for _, tc := range cases {
+		if got, want := encodePowerShell(tc.script), base64.StdEncoding.EncodeToString(tc.utf16); got != want {
+			t.Errorf("encodePowerShell(%q) = %q, want %q", tc.script, got, want)
+		}
+	}
+	if got := encodePowerShell("Get-Date"); got != "RwBlAHQALQBEAGEAdABlAA==" {
+		t.Errorf("encodePowerShell(Get-Date) = %q", got)
+	}
+}
+
 // TestBootstrapCommandConstantLength is the core regression guard for #39: the
 // command line must be small and independent of the script size, since the
 // script no longer rides on the command line.
 func TestBootstrapCommandConstantLength(t *testing.T) {
-	cmd := bootstrapCommand()
+	cmd := bootstrapCommand(DefaultPowerShellPath)
 	if len(cmd) >= 1000 {
 		t.Fatalf("bootstrap command unexpectedly long: %d chars", len(cmd))
 	}
 	// A second call must be identical: the bootstrap does not embed the script.
-	if cmd2 := bootstrapCommand(); cmd != cmd2 {
+	if cmd2 := bootstrapCommand(DefaultPowerShellPath); cmd != cmd2 {
 		t.Fatalf("bootstrapCommand is not deterministic")
 	}
 }
 
+// TestBootstrapResetsDefaultParameterValues guards against host-side
+// $PSDefaultParameterValues (e.g. '*:ComputerName') retargeting cmdlets: the
+// bootstrap must reset them before it invokes the decoded script.
+func TestBootstrapResetsDefaultParameterValues(t *testing.T) {
+	const encFlag = "-EncodedCommand "
+	cmd := bootstrapCommand(DefaultPowerShellPath)
+	i := strings.Index(cmd, encFlag)
+	if i < 0 {
+		t.Fatalf("bootstrap command has no %q: %s", encFlag, cmd)
+	}
+	script := decodePowerShell(t, cmd[i+len(encFlag):])
+	reset := strings.Index(script, "$global:PSDefaultParameterValues=@{}")
+	if reset < 0 {
+		t.Fatalf("bootstrap does not reset $PSDefaultParameterValues:\n%s", script)
+	}
+	if invoke := strings.Index(script, "[ScriptBlock]::Create"); invoke < reset {
+		t.Errorf("$PSDefaultParameterValues must be reset before the script is invoked:\n%s", script)
+	}
+}
+
 func TestBootstrapCommandExcludesScript(t *testing.T) {
 	// A large script that would blow past Windows' ~8191-char command-line
 	// limit if inlined as -EncodedCommand (base64 of UTF-16LE ~= 2.7x).
 	large := strings.Repeat("Get-Service -Name 'svc';", 4000) // ~96 KB
-	cmd := bootstrapCommand()
+	cmd := bootstrapCommand(DefaultPowerShellPath)
 	if strings.Contains(cmd, encodePowerShell(large)) {
 		t.Fatal("bootstrap command must not contain the script payload")
 	}
@@ -67,6 +124,82 @@ func TestBootstrapCommandExcludesScript(t *testing.T) {
 	}
 }
 
+func TestBootstrapCommandExecutable(t *testing.T) {
+	cases := map[string]string{
+		DefaultPowerShellPath:                    "powershell.exe -NoProfile ",
+		"pwsh.exe":                               "pwsh.exe -NoProfile ",
+		`C:\Program Files\PowerShell\7\pwsh.exe`: `"C:\Program Files\PowerShell\7\pwsh.exe" -NoProfile `,
+	}
+	for exe, prefix := range cases {
+		if cmd := bootstrapCommand(exe); !strings.HasPrefix(cmd, prefix) {
+			t.Errorf("bootstrapCommand(%q) = %q, want prefix %q", exe, cmd[:60], prefix)
+		}
+	}
+}
+
+// TestRawBootstrapCommand checks the DisableEncodedCommand invocation: the
+// same bootstrap, on one line, with no double quote that would end the
+// -Command argument early.
+func TestRawBootstrapCommand(t *testing.T) {
+	const prefix = `powershell.exe -NoProfile -NonInteractive -ExecutionPolicy Bypass -Command "`
+	cmd := rawBootstrapCommand(DefaultPowerShellPath)
+	if !strings.HasPrefix(cmd, prefix) || !strings.HasSuffix(cmd, `"`) {
+		t.Fatalf("unexpected command: %s", cmd)
+	}
+	body := cmd[len(prefix) : len(cmd)-1]
+	if strings.ContainsAny(body, "\"\r\n") || strings.Contains(cmd, "-EncodedCommand") {
+		t.Errorf("raw bootstrap must be one line without double quotes: %s", body)
+	}
+	for _, want := range []string{
+		"$global:PSDefaultParameterValues=@{}; ",
+		"& ([ScriptBlock]::Create($code)); ",
+		"[Console]::Error.WriteLine('" + scriptStatusPrefix + "'+$LASTEXITCODE+' '+$s)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("raw bootstrap missing %q: %s", want, body)
+		}
+	}
+	if got := strings.Count(body, "; ") + 1; got != strings.Count(psBootstrap, "\n")+1 {
+		t.Errorf("raw bootstrap has %d statements, want one per psBootstrap line", got)
+	}
+
+	if cmd := rawBootstrapCommand(`C:\Program Files\PowerShell\7\pwsh.exe`); !strings.HasPrefix(cmd, `"C:\Program Files\PowerShell\7\pwsh.exe" -NoProfile `) {
+		t.Errorf("path with spaces not quoted: %s", cmd)
+	}
+
+	c := &Client{cfg: Config{PowerShellPath: DefaultPowerShellPath}}
+	if got := c.commandLine(); got != bootstrapCommand(DefaultPowerShellPath) {
+		t.Errorf("default commandLine must be encoded: %s", got)
+	}
+	c.cfg.DisableEncodedCommand = true
+	if got := c.commandLine(); got != cmd {
+		t.Errorf("DisableEncodedCommand commandLine = %s", got)
+	}
+}
+
+func TestNew_PowerShellPath(t *testing.T) {
+	c, err := New(Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil || c.Config().PowerShellPath != DefaultPowerShellPath {
+		t.Fatalf("default PowerShellPath: %v, %q", err, c.Config().PowerShellPath)
+	}
+	if _, err := New(Config{Host: "win01", Username: "u", Password: "p", PowerShellPath: `pwsh" & calc`}); err == nil {
+		t.Error("a path containing quotes must be rejected")
+	}
+}
+
+func TestNew_DoesNotShareDefaultParameters(t *testing.T) {
+	before := *winrm.DefaultParameters
+	_, err := New(Config{Host: "win01", Username: "u", Password: "p", Timeout: 7 * time.Second,
+		Bastion: &BastionConfig{Host: "jump", Username: "u", Password: "p", InsecureIgnoreHostKey: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := winrm.DefaultParameters
+	if after.Timeout != before.Timeout || after.Dial != nil || after.TransportDecorator != nil {
+		t.Errorf("New modified winrm.DefaultParameters: %+v", *after)
+	}
+}
+
 func TestComposeStdinLayout(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -96,3 +229,146 @@ func TestComposeStdinLayout(t *testing.T) {
 		})
 	}
 }
+
+func TestEndpointHost(t *testing.T) {
+	cases := map[string]string{
+		"win01":                   "win01",
+		"win01.corp.example":      "win01.corp.example",
+		"192.0.2.10":              "192.0.2.10",
+		"2001:db8::1":             "[2001:db8::1]",
+		"::1":                     "[::1]",
+		"[2001:db8::1]":           "[2001:db8::1]",
+		"fe80::1%eth0":            "[fe80::1%25eth0]",
+		"2001:0db8:0000::0001":    "[2001:db8::1]",
+		"::ffff:192.0.2.10":       "[::ffff:192.0.2.10]",
+		"not:an:address:at:all:x": "not:an:address:at:all:x",
+	}
+	for in, want := range cases {
+		if got := endpointHost(in); got != want {
+			t.Errorf("endpointHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestEndpointHost_ParsesAsURL builds the WS-Man URL the same way
+// winrm.Endpoint does and checks that an IPv6 literal survives a round trip.
+func TestEndpointHost_ParsesAsURL(t *testing.T) {
+	for _, host := range []string{"2001:db8::1", "fe80::1%eth0", "win01"} {
+		raw := fmt.Sprintf("http://%s:%d/wsman", endpointHost(host), 5985)
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", raw, err)
+		}
+		if u.Hostname() != host || u.Port() != "5985" {
+			t.Errorf("%q: Hostname=%q Port=%q, want %q 5985", raw, u.Hostname(), u.Port(), host)
+		}
+	}
+}
+
+func TestDialAddress(t *testing.T) {
+	cases := []struct {
+		host string
+		port int
+		want string
+	}{
+		{"win01", 5985, "win01:5985"},
+		{"192.0.2.10", 5986, "192.0.2.10:5986"},
+		{"2001:db8::1", 5986, "[2001:db8::1]:5986"},
+		{"[2001:db8::1]", 2222, "[2001:db8::1]:2222"},
+		{"fe80::1%eth0", 22, "[fe80::1%eth0]:22"},
+		{"[fe80::1%25eth0]", 22, "[fe80::1%eth0]:22"},
+	}
+	for _, tc := range cases {
+		if got := dialAddress(tc.host, tc.port); got != tc.want {
+			t.Errorf("dialAddress(%q, %d) = %q, want %q", tc.host, tc.port, got, tc.want)
+		}
+	}
+}
+
+// TestIsConnected_IPv6Literal dials a listener on the IPv6 loopback through
+// an unbracketed literal and a custom port.
+func TestIsConnected_IPv6Literal(t *testing.T) {
+	ln, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+	for _, host := range []string{"::1", "[::1]"} {
+		c, err := New(Config{Host: host, Port: port, Username: "u", Password: "p", Timeout: 2 * time.Second})
+		if err != nil {
+			t.Fatalf("New(%q): %v", host, err)
+		}
+		if !c.IsConnected(context.Background()) {
+			t.Errorf("IsConnected(%q, %d) = false, want true", host, port)
+		}
+	}
+}
+
+// TestRunPowerShell_ReturnsOnCancel points the client at a WinRM endpoint that
+// never answers and checks that cancelling the context releases the caller
+// instead of waiting for the HTTP timeout.
+func TestRunPowerShell_ReturnsOnCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		srv.Close()
+	})
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	p, _ := strconv.Atoi(port)
+	c, err := New(Config{Host: host, Port: p, Username: "u", Password: "p", AuthType: "basic", Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, _, err = c.RunPowerShell(ctx, "Start-Sleep 3600")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("RunPowerShell returned %v after cancellation", d)
+	}
+	if n := c.InFlight(); n != 0 {
+		t.Errorf("InFlight = %d after cancellation, want 0", n)
+	}
+}
+
+// TestLockedBuffer_ConcurrentWriteAndRead mirrors runCommand reading the
+// output buffers while the winrm copy goroutines are still writing; run it
+// with -race.
+func TestLockedBuffer_ConcurrentWriteAndRead(t *testing.T) {
+	var b lockedBuffer
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(&b, "line %d\n", i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = b.String()
+	}
+	wg.Wait()
+	if !strings.HasSuffix(b.String(), "line 999\n") {
+		t.Errorf("buffer lost writes: %q", b.String())
+	}
+}
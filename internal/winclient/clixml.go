@@ -0,0 +1,135 @@
+// Package winclient: CLIXML decoding of PowerShell stderr.
+//
+// powershell.exe started with -EncodedCommand and a redirected stderr does
+// not write error records as text: it serialises them as CLIXML, a "#< CLIXML"
+// marker line followed by an <Objs> document in which every line of the
+// error is an <S S="Error"> element with CR/LF escaped as _x000D__x000A_.
+// Progress records ("Preparing modules for first use.") land in the same
+// document. Passed through as-is, that blob is what users would see in
+// diagnostics. Client.run therefore decodes stderr with ParseCLIXMLError
+// before anything else looks at it.
+package winclient
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// clixmlMarker precedes every CLIXML document on stderr.
+const clixmlMarker = "#< CLIXML"
+
+// ParseCLIXMLError replaces every CLIXML document in stderr with the text of
+// its error records, one line per record line, and drops progress and other
+// non-error records. Text outside the documents (such as the bootstrap status
+// line) is kept unchanged. stderr without the marker is returned as-is; a
+// document that cannot be parsed is kept raw so nothing is lost.
+func ParseCLIXMLError(stderr string) string {
+	if !strings.Contains(stderr, clixmlMarker) {
+		return stderr
+	}
+	var out strings.Builder
+	rest := stderr
+	for {
+		i := strings.Index(rest, clixmlMarker)
+		if i < 0 {
+			break
+		}
+		out.WriteString(rest[:i])
+		rest = strings.TrimLeft(rest[i+len(clixmlMarker):], "\r\n")
+		end := strings.Index(rest, "</Objs>")
+		if !strings.HasPrefix(rest, "<Objs") || end < 0 {
+			// Marker without a (complete) document: keep what follows.
+			continue
+		}
+		doc := rest[:end+len("</Objs>")]
+		rest = strings.TrimLeft(rest[len(doc):], "\r\n")
+		text, ok := clixmlErrorText(doc)
+		if !ok {
+			text = doc
+		}
+		if text != "" {
+			out.WriteString(text)
+			if !strings.HasSuffix(text, "\n") {
+				out.WriteByte('\n')
+			}
+		}
+	}
+	if rest != "" && out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+		out.WriteByte('\n')
+	}
+	out.WriteString(rest)
+	return out.String()
+}
+
+// clixmlErrorText concatenates the <S S="Error"> strings of a CLIXML <Objs>
+// document, decoding the _xHHHH_ escapes and folding CRLF to LF. ok is false
+// when doc is not well-formed XML.
+func clixmlErrorText(doc string) (string, bool) {
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	var b strings.Builder
+	inError := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inError = t.Name.Local == "S" && clixmlAttr(t, "S") == "Error"
+		case xml.EndElement:
+			inError = false
+		case xml.CharData:
+			if inError {
+				b.Write(t)
+			}
+		}
+	}
+	return strings.ReplaceAll(decodeCLIXMLEscapes(b.String()), "\r\n", "\n"), true
+}
+
+// clixmlAttr returns the value of the attribute named name, or "".
+func clixmlAttr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// decodeCLIXMLEscapes decodes the _xHHHH_ escapes PowerShell uses for
+// control characters and other code units that XML cannot carry. They are
+// UTF-16 code units, so consecutive escapes may form a surrogate pair.
+func decodeCLIXMLEscapes(s string) string {
+	if !strings.Contains(s, "_x") {
+		return s
+	}
+	var out strings.Builder
+	var units []uint16
+	flush := func() {
+		if len(units) > 0 {
+			out.WriteString(string(utf16.Decode(units)))
+			units = units[:0]
+		}
+	}
+	for i := 0; i < len(s); {
+		if len(s)-i >= 7 && s[i] == '_' && s[i+1] == 'x' && s[i+6] == '_' {
+			if v, err := strconv.ParseUint(s[i+2:i+6], 16, 16); err == nil {
+				units = append(units, uint16(v))
+				i += 7
+				continue
+			}
+		}
+		flush()
+		out.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return out.String()
+}
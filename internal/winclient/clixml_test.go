@@ -0,0 +1,78 @@
+// Package winclient — unit tests for CLIXML stderr decoding.
+package winclient
+
+import "testing"
+
+// clixmlSample is stderr as captured from `Get-Item C:\Missing` run through
+// the bootstrap: a progress record, the error record and the status line.
+const clixmlSample = "#< CLIXML\r\n" +
+	`<Objs Version="1.1.0.1" xmlns="http://schemas.microsoft.com/powershell/2004/04">` +
+	`<Obj S="progress" RefId="0"><TN RefId="0"><T>System.Management.Automation.PSCustomObject</T><T>System.Object</T></TN>` +
+	`<MS><I64 N="SourceId">1</I64><PR N="Record"><AV>Preparing modules for first use.</AV><AI>0</AI><Nil />` +
+	`<PI>-1</PI><PC>-1</PC><T>Completed</T><SR>-1</SR><SD> </SD></PR></MS></Obj>` +
+	`<S S="Error">Get-Item : Cannot find path 'C:\Missing' because it does not exist._x000D__x000A_</S>` +
+	`<S S="Error">At line:1 char:1_x000D__x000A_</S>` +
+	`<S S="Error">+ Get-Item C:\Missing_x000D__x000A_</S>` +
+	`<S S="Error">    + CategoryInfo          : ObjectNotFound: (C:\Missing:String) [Get-Item], ItemNotFoundException_x000D__x000A_</S>` +
+	"</Objs>\r\n" +
+	"#winclient-status 1 False\r\n"
+
+func TestParseCLIXMLError(t *testing.T) {
+	cases := []struct {
+		name, in, want string
+	}{
+		{
+			name: "error record and status line",
+			in:   clixmlSample,
+			want: "Get-Item : Cannot find path 'C:\\Missing' because it does not exist.\n" +
+				"At line:1 char:1\n" +
+				"+ Get-Item C:\\Missing\n" +
+				"    + CategoryInfo          : ObjectNotFound: (C:\\Missing:String) [Get-Item], ItemNotFoundException\n" +
+				"#winclient-status 1 False\r\n",
+		},
+		{
+			name: "progress only",
+			in: "#< CLIXML\r\n<Objs Version=\"1.1.0.1\" xmlns=\"http://schemas.microsoft.com/powershell/2004/04\">" +
+				"<Obj S=\"progress\" RefId=\"0\"><MS><I64 N=\"SourceId\">1</I64></MS></Obj></Objs>" +
+				"#winclient-status 0 True\r\n",
+			want: "#winclient-status 0 True\r\n",
+		},
+		{
+			name: "entities and non-ASCII",
+			in: "#< CLIXML\n<Objs Version=\"1.1.0.1\" xmlns=\"http://schemas.microsoft.com/powershell/2004/04\">" +
+				"<S S=\"Error\">Le service &lt;Spooler&gt; n'a pas démarré &amp; _xD83D__xDE00__x000A_</S></Objs>",
+			want: "Le service <Spooler> n'a pas démarré & 😀\n",
+		},
+		{
+			name: "text before the document is kept",
+			in:   "WARNING: legacy\n" + clixmlSample,
+			want: "WARNING: legacy\nGet-Item : Cannot find path 'C:\\Missing' because it does not exist.\n" +
+				"At line:1 char:1\n+ Get-Item C:\\Missing\n" +
+				"    + CategoryInfo          : ObjectNotFound: (C:\\Missing:String) [Get-Item], ItemNotFoundException\n" +
+				"#winclient-status 1 False\r\n",
+		},
+		{name: "plain text unchanged", in: "boom\r\n#winclient-status 1 True\r\n", want: "boom\r\n#winclient-status 1 True\r\n"},
+		{
+			name: "malformed document kept raw",
+			in:   "#< CLIXML\r\n<Objs><S S=\"Error\">broken</Objs>",
+			want: "<Objs><S S=\"Error\">broken</Objs>\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ParseCLIXMLError(c.in); got != c.want {
+				t.Errorf("ParseCLIXMLError =\n%q\nwant\n%q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseCLIXMLError_StatusLineStillParsed(t *testing.T) {
+	rest, st, ok := splitScriptStatus(ParseCLIXMLError(clixmlSample))
+	if !ok || st.ExitCode != 1 || st.Succeeded {
+		t.Fatalf("status = %+v, ok = %v", st, ok)
+	}
+	if want := "Get-Item : Cannot find path 'C:\\Missing' because it does not exist.\n"; rest[:len(want)] != want {
+		t.Errorf("rest = %q", rest)
+	}
+}
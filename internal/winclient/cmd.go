@@ -0,0 +1,63 @@
+// Package winclient: cmd.exe command path for text-output tools.
+//
+// netsh, sc.exe, w32tm and similar tools write through the console code
+// page, which is the OEM code page (437, 850, 932, ...) unless changed.
+// Captured from PowerShell, that output is decoded with the wrong code page
+// and localized text (accented service names, translated field labels)
+// arrives mangled. RunCmd runs a single cmd.exe command line directly, and
+// with CmdOptions.UTF8 switches the code page to 65001 first, so the tool
+// emits UTF-8. The captured text is then normalised (BOM stripped, CRLF
+// folded to LF, invalid bytes replaced) before it is returned.
+package winclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CmdOptions tunes RunCmd.
+type CmdOptions struct {
+	// UTF8 runs "chcp 65001" before the command so console-code-page tools
+	// emit UTF-8 rather than the OEM code page.
+	UTF8 bool
+}
+
+// cmdUTF8Prefix switches the console to UTF-8. The chcp banner is discarded;
+// if chcp itself fails the command does not run.
+const cmdUTF8Prefix = "chcp 65001 >nul && "
+
+// RunCmd runs command with cmd.exe /c on the remote host and returns its
+// normalised stdout and stderr. command must be a single line; it is placed
+// on the command line as-is, so callers quote arguments for cmd.exe. A
+// non-zero exit code is returned as a *TransportError of kind
+// FailureCommand, like RunPowerShell.
+func (c *Client) RunCmd(ctx context.Context, command string, opts CmdOptions) (string, string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", "", fmt.Errorf("winclient: cmd: command is empty")
+	}
+	if strings.ContainsAny(command, "\r\n") {
+		return "", "", fmt.Errorf("winclient: cmd: command must be a single line")
+	}
+	_, obs := c.observeRun(ctx, nil)
+	stdout, stderr, err := c.runCommand(ctx, cmdCommandLine(command, opts), nil, obs)
+	obs.done(err)
+	return normalizeCmdOutput(stdout), normalizeCmdOutput(stderr), err
+}
+
+// cmdCommandLine builds the cmd.exe invocation for RunCmd.
+func cmdCommandLine(command string, opts CmdOptions) string {
+	if opts.UTF8 {
+		command = cmdUTF8Prefix + command
+	}
+	return "cmd.exe /d /c " + command
+}
+
+// normalizeCmdOutput strips a leading UTF-8 BOM, folds CRLF to LF and
+// replaces invalid UTF-8 (e.g. OEM bytes from a command run without UTF8)
+// with U+FFFD so the result is always valid text.
+func normalizeCmdOutput(s string) string {
+	s = strings.TrimPrefix(s, "\uFEFF")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ToValidUTF8(s, "\uFFFD")
+}
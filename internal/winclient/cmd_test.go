@@ -0,0 +1,44 @@
+// Package winclient — unit tests for the cmd.exe command path.
+package winclient
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCmdCommandLine(t *testing.T) {
+	if got := cmdCommandLine(`sc.exe qc "My Svc"`, CmdOptions{}); got != `cmd.exe /d /c sc.exe qc "My Svc"` {
+		t.Errorf("plain = %q", got)
+	}
+	want := `cmd.exe /d /c chcp 65001 >nul && netsh advfirewall show allprofiles`
+	if got := cmdCommandLine("netsh advfirewall show allprofiles", CmdOptions{UTF8: true}); got != want {
+		t.Errorf("utf8 = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeCmdOutput(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"\uFEFFSERVICE_NAME: x\r\n", "SERVICE_NAME: x\n"},
+		{"Dienstname: Überwachung\r\n\r\n", "Dienstname: Überwachung\n\n"},
+		// CP850 "Ü" (0x9A) read without chcp 65001 is not valid UTF-8.
+		{"Dienst \x9Aberwachung", "Dienst \uFFFDberwachung"},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		if got := normalizeCmdOutput(tc.in); got != tc.want {
+			t.Errorf("normalizeCmdOutput(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRunCmd_RejectsEmptyAndMultiline(t *testing.T) {
+	c := newHealthTestClient(t)
+	for _, cmd := range []string{"", "  ", "sc.exe qc a\r\nsc.exe qc b", "echo a\necho b"} {
+		if _, _, err := c.RunCmd(context.Background(), cmd, CmdOptions{UTF8: true}); err == nil {
+			t.Errorf("RunCmd(%q) should fail before reaching the transport", cmd)
+		}
+	}
+	if st := c.ConnectionStats(); st.Runs != 0 {
+		t.Errorf("rejected commands must not count as runs: %+v", st)
+	}
+}
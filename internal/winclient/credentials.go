@@ -20,8 +20,36 @@ type Config struct {
 	Insecure bool
 	AuthType string // basic | ntlm | kerberos
 	Timeout  time.Duration
+	// DefaultCommandTimeout is the provider-wide default for resource
+	// operations that honour a timeouts {} block (feature installs, package
+	// installs, ...). Zero leaves each resource's built-in default in place.
+	DefaultCommandTimeout time.Duration
+	// Bastion, when set, tunnels every WinRM connection through an SSH jump
+	// host (see bastion.go).
+	Bastion *BastionConfig
+	// PowerShellPath is the PowerShell executable every script runs under,
+	// e.g. "pwsh.exe" or a full path on hosts that only ship PowerShell 7.
+	// Empty means DefaultPowerShellPath.
+	PowerShellPath string
+	// ReadBatching serves local user and feature Reads from one snapshot
+	// per kind instead of one call per object (see read_batch.go).
+	ReadBatching bool
+	// DisableEncodedCommand starts PowerShell with the bootstrap as plain
+	// -Command text instead of -EncodedCommand, for hosts whose policy
+	// blocks or flags encoded commands (see rawBootstrapCommand).
+	DisableEncodedCommand bool
+	// RunAs, when set, runs every script under another account than the
+	// WinRM login (see runas.go).
+	RunAs *RunAsConfig
+	// Observer, when set, is told about every run with its operation,
+	// duration, byte counts and new connections (see observer.go).
+	Observer CommandObserver
 }
 
+// DefaultPowerShellPath is the executable used when Config.PowerShellPath is
+// empty: Windows PowerShell 5.1, present on every supported Windows version.
+const DefaultPowerShellPath = "powershell.exe"
+
 // Environment variable names used as fallback when provider attributes are
 // unset.
 const (
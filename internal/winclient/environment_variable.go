@@ -228,6 +228,7 @@ type evReadData struct {
 
 // runScript executes a PS script and parses the JSON envelope.
 func (e *EnvVarClientImpl) runScript(ctx context.Context, op, script string) (*evPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	stdout, stderr, err := runEnvVarPowerShell(ctx, e.c, script)
 	if err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
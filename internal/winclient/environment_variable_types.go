@@ -98,8 +98,11 @@ func (e *EnvVarError) Error() string {
 // Unwrap returns the underlying cause for errors chain walking.
 func (e *EnvVarError) Unwrap() error { return e.Cause }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 func (e *EnvVarError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*EnvVarError)
 	if !ok {
 		return false
@@ -0,0 +1,107 @@
+// Package winclient: provider-wide error classes.
+//
+// Every client returns its own structured error (FeatureError, ServiceError,
+// ...) whose Kind is specific to that resource, and transport failures come
+// back as *TransportError. Callers that do not care which resource failed
+// (retry loops, "gone from the host" handling, diagnostics) can instead test
+// against the small set of ErrorClass sentinels below:
+//
+//	if errors.Is(err, winclient.ErrNotFound) { resp.State.RemoveResource(ctx) }
+//
+// errors.Is works through the whole chain, so a resource error whose Cause
+// is a dial failure matches ErrUnreachable. Classify returns the single class
+// of an error for callers that switch on it.
+package winclient
+
+import "errors"
+
+// ErrorClass is a resource-independent failure category. The exported
+// sentinels are the only values; compare them with errors.Is.
+type ErrorClass struct {
+	name string
+}
+
+// Error implements the error interface.
+func (c *ErrorClass) Error() string { return "winclient: " + c.name }
+
+// Error classes usable with errors.Is on any error returned by this package.
+var (
+	// ErrNotFound: the object does not exist on the host (not_found and the
+	// resource-specific *_not_found kinds).
+	ErrNotFound = &ErrorClass{name: "not found"}
+	// ErrAlreadyExists: the object exists and the operation would create it.
+	ErrAlreadyExists = &ErrorClass{name: "already exists"}
+	// ErrAccessDenied: the account lacks rights on the host, or the
+	// transport rejected the credentials or the TLS certificate.
+	ErrAccessDenied = &ErrorClass{name: "access denied"}
+	// ErrUnreachable: the host could not be reached or the connection was
+	// lost mid-operation.
+	ErrUnreachable = &ErrorClass{name: "host unreachable"}
+	// ErrTransient: the host answered but a subsystem was not ready yet
+	// (text matches TransientErrorPatterns); the same call usually succeeds
+	// a few seconds later.
+	ErrTransient = &ErrorClass{name: "transient failure"}
+)
+
+// errorClasses lists the classes in the order Classify tries them.
+var errorClasses = []*ErrorClass{ErrNotFound, ErrAlreadyExists, ErrAccessDenied, ErrUnreachable, ErrTransient}
+
+// kindClasses maps per-resource error kinds to their class. Kinds not
+// listed have no class of their own.
+var kindClasses = map[string]*ErrorClass{
+	"not_found":             ErrNotFound,
+	"group_not_found":       ErrNotFound,
+	"member_not_found":      ErrNotFound,
+	"interface_not_found":   ErrNotFound,
+	"already_exists":        ErrAlreadyExists,
+	"member_already_exists": ErrAlreadyExists,
+	"already_installed":     ErrAlreadyExists,
+	"permission_denied":     ErrAccessDenied,
+	"unreachable":           ErrUnreachable,
+	"connection_lost":       ErrUnreachable,
+}
+
+// matchesKind reports whether a per-resource error err of the given kind
+// belongs to c. It backs the Is methods of the per-resource error types.
+func (c *ErrorClass) matchesKind(kind string, err error) bool {
+	if kindClasses[kind] == c {
+		return true
+	}
+	return c == ErrTransient && isTransientError(err)
+}
+
+// Is lets errors.Is(err, ErrUnreachable) and friends see through transport
+// failures: dial errors are unreachable, authentication and TLS errors are
+// access denied, and command failures are transient when their text says so.
+func (e *TransportError) Is(target error) bool {
+	c, ok := target.(*ErrorClass)
+	if !ok {
+		return false
+	}
+	switch e.Kind {
+	case FailureDial:
+		return c == ErrUnreachable
+	case FailureAuth:
+		return c == ErrAccessDenied
+	}
+	return c == ErrTransient && isTransientError(e)
+}
+
+// Classify returns the class of err, or nil when err is nil or fits none.
+// Errors that are not typed by this package (plain fmt.Errorf wrapping, for
+// instance) are still classed ErrTransient when their text matches
+// TransientErrorPatterns.
+func Classify(err error) *ErrorClass {
+	if err == nil {
+		return nil
+	}
+	for _, c := range errorClasses {
+		if errors.Is(err, c) {
+			return c
+		}
+	}
+	if isTransientError(err) {
+		return ErrTransient
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Package winclient — unit tests for the provider-wide error classes.
+package winclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	dial := &TransportError{Kind: FailureDial, Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	cases := []struct {
+		name string
+		err  error
+		want *ErrorClass
+	}{
+		{"nil", nil, nil},
+		{"feature not found", NewFeatureError(FeatureErrorNotFound, "no such feature", nil, nil), ErrNotFound},
+		{"service not found wrapped", fmt.Errorf("read: %w", NewServiceError(ServiceErrorNotFound, "gone", nil, nil)), ErrNotFound},
+		{"group member not found", NewLocalGroupMemberError(LocalGroupMemberErrorGroupNotFound, "no group", nil, nil), ErrNotFound},
+		{"local user exists", NewLocalUserError(LocalUserErrorAlreadyExists, "exists", nil, nil), ErrAlreadyExists},
+		{"winget package already installed", NewWingetPackageError(WingetPackageErrorAlreadyInstalled, "installed", nil, nil), ErrAlreadyExists},
+		{"registry permission", NewRegistryKeyError(RegistryKeyErrorPermission, "denied", nil, nil), ErrAccessDenied},
+		{"legacy package permission", &LegacyPackageError{Kind: "permission_denied", Message: "denied"}, ErrAccessDenied},
+		{"hostname unreachable", NewHostnameError(HostnameErrorUnreachable, "down", nil, nil), ErrUnreachable},
+		{"dial failure", dial, ErrUnreachable},
+		{"dial failure under resource error", NewFeatureError(FeatureErrorUnknown, "transport failed", dial, nil), ErrUnreachable},
+		{"auth failure", &TransportError{Kind: FailureAuth, Err: errors.New("http error 401")}, ErrAccessDenied},
+		{"transient text in resource error", NewFeatureError(FeatureErrorUnknown, "The RPC server is unavailable.", nil, nil), ErrTransient},
+		{"transient command failure", &TransportError{Kind: FailureCommand, Err: errors.New("The device is not ready.")}, ErrTransient},
+		{"transient plain error", errors.New("Provider load failure"), ErrTransient},
+		{"not found wins over transient text", NewFeatureError(FeatureErrorNotFound, "not ready", nil, nil), ErrNotFound},
+		{"unclassified kind", NewFeatureError(FeatureErrorSourceMissing, "no source", nil, nil), nil},
+		{"unclassified command failure", &TransportError{Kind: FailureCommand, Err: errors.New("exit 1"), ExitCode: 1}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Classify(c.err); got != c.want {
+				t.Errorf("Classify = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestErrorClass_KindSentinelsUnaffected(t *testing.T) {
+	err := NewFeatureError(FeatureErrorNotFound, "no such feature", nil, nil)
+	if !errors.Is(err, ErrFeatureNotFound) || errors.Is(err, ErrFeaturePermission) {
+		t.Error("kind sentinels must still match by Kind")
+	}
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrAccessDenied) {
+		t.Error("class sentinels must match by class")
+	}
+	if errors.Is(ErrNotFound, ErrFeatureNotFound) {
+		t.Error("a class must not match a kind sentinel")
+	}
+}
@@ -15,8 +15,10 @@ package winclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Compile-time assertion: FeatureClient satisfies WindowsFeatureClient.
@@ -75,6 +77,36 @@ function Test-PendingReboot {
   return $false
 }
 
+# Get-FeatureComponents walks the SubFeatures tree of $F through $Lookup,
+# which resolves a list of names to feature objects. A sub-feature whose
+# payload was removed is skipped: -IncludeAllSubFeature cannot install it
+# without a source. Management tools are the -Mgmt- and RSAT- entries of the
+# tree; they count as installed when any of them is, since
+# -IncludeManagementTools does not add every one of them.
+function Get-FeatureComponents($F, [scriptblock]$Lookup) {
+  $allSub = $true; $tools = 0; $toolsInstalled = 0
+  $seen = @{}
+  $queue = @($F.SubFeatures | Where-Object { $_ })
+  while ($queue.Count -gt 0) {
+    $next = @()
+    foreach ($s in @(& $Lookup $queue)) {
+      if (-not $s -or $seen.ContainsKey([string]$s.Name)) { continue }
+      $seen[[string]$s.Name] = $true
+      $next += @($s.SubFeatures | Where-Object { $_ })
+      $state = [string]$s.InstallState
+      if ($state -eq 'Removed') { continue }
+      $present = ($state -eq 'Installed' -or $state -eq 'InstallPending')
+      if (-not $present) { $allSub = $false }
+      if ([string]$s.Name -match '-Mgmt-|^RSAT-') {
+        $tools++
+        if ($present) { $toolsInstalled++ }
+      }
+    }
+    $queue = $next
+  }
+  return [ordered]@{ all_sub = $allSub; mgmt = ($tools -eq 0 -or $toolsInstalled -gt 0) }
+}
+
 function Ensure-FeatureCmdlets {
   if (-not (Get-Command Install-WindowsFeature -ErrorAction SilentlyContinue)) {
     Emit-Err 'unsupported_sku' 'Install-WindowsFeature is not available on this host. The ServerManager module ships with Windows Server only; on client SKUs use Enable-WindowsOptionalFeature instead.' @{}
@@ -101,23 +133,28 @@ var runFeaturePowerShell = func(ctx context.Context, c *Client, script string) (
 // runFeatureEnvelope executes script (prepended with psFeatureHeader) and
 // parses the JSON envelope.
 func (f *FeatureClient) runFeatureEnvelope(ctx context.Context, op, name, script string) (*featurePSResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := psFeatureHeader + "\n" + script
+	start := time.Now()
 	stdout, stderr, err := runFeaturePowerShell(ctx, f.c, full)
 	if err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
-			return nil, NewFeatureError(FeatureErrorTimeout,
-				fmt.Sprintf("operation %q on feature %q timed out or was cancelled (consider increasing provider timeout for long installs such as Web-Server)", op, name),
-				ctxErr,
-				map[string]string{"operation": op, "name": name, "host": f.c.cfg.Host})
+			elapsed := time.Since(start).Round(time.Second)
+			msg := fmt.Sprintf("operation %q on feature %q was cancelled after %s", op, name, elapsed)
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				msg = fmt.Sprintf("operation %q on feature %q timed out after %s (raise the resource's timeouts block or the provider's default_command_timeout for long installs such as Web-Server)", op, name, elapsed)
+			}
+			return nil, NewFeatureError(FeatureErrorTimeout, msg, ctxErr,
+				map[string]string{"operation": op, "name": name, "host": f.c.cfg.Host, "elapsed": elapsed.String()})
 		}
 		return nil, NewFeatureError(FeatureErrorUnknown,
 			fmt.Sprintf("powershell transport error during %q", op),
 			err,
-			map[string]string{
+			withExitCode(map[string]string{
 				"operation": op, "name": name, "host": f.c.cfg.Host,
 				"stderr": truncate(stderr, 2048),
 				"stdout": truncate(stdout, 2048),
-			})
+			}, err))
 	}
 
 	line := extractLastJSONLine(stdout)
@@ -178,6 +215,10 @@ type featureDataPayload struct {
 	Installed      bool   `json:"installed"`
 	InstallState   string `json:"install_state"`
 	RestartPending bool   `json:"restart_pending"`
+	// The component flags are only emitted by the read scripts; a missing
+	// key reads as "complete" so other payloads never look like drift.
+	AllSubFeaturesInstalled  *bool `json:"all_sub_features_installed"`
+	ManagementToolsInstalled *bool `json:"management_tools_installed"`
 }
 
 // installDataPayload mirrors the JSON returned by Install/Uninstall scripts.
@@ -186,6 +227,8 @@ type installDataPayload struct {
 	RestartNeeded bool                `json:"restart_needed"`
 	Success       bool                `json:"success"`
 	ExitCode      string              `json:"exit_code"`
+	// AlreadyInstalled is only emitted by the install script.
+	AlreadyInstalled bool `json:"already_installed"`
 }
 
 func toFeatureInfo(d *featureDataPayload) *FeatureInfo {
@@ -193,12 +236,14 @@ func toFeatureInfo(d *featureDataPayload) *FeatureInfo {
 		return nil
 	}
 	return &FeatureInfo{
-		Name:           d.Name,
-		DisplayName:    d.DisplayName,
-		Description:    d.Description,
-		Installed:      d.Installed,
-		InstallState:   d.InstallState,
-		RestartPending: d.RestartPending,
+		Name:                     d.Name,
+		DisplayName:              d.DisplayName,
+		Description:              d.Description,
+		Installed:                d.Installed,
+		InstallState:             d.InstallState,
+		RestartPending:           d.RestartPending,
+		AllSubFeaturesInstalled:  d.AllSubFeaturesInstalled == nil || *d.AllSubFeaturesInstalled,
+		ManagementToolsInstalled: d.ManagementToolsInstalled == nil || *d.ManagementToolsInstalled,
 	}
 }
 
@@ -215,6 +260,10 @@ function Read-Feature([string]$Name) {
   }
   if (-not $f) { Emit-OK $null; return }
   $pending = Test-PendingReboot
+  $comp = [ordered]@{ all_sub = $true; mgmt = $true }
+  if ($f.InstallState -eq 'Installed') {
+    $comp = Get-FeatureComponents $f { param($n) Get-WindowsFeature -Name $n -ErrorAction SilentlyContinue }
+  }
   Emit-OK ([ordered]@{
     name           = [string]$f.Name
     display_name   = [string]$f.DisplayName
@@ -222,17 +271,27 @@ function Read-Feature([string]$Name) {
     installed      = ($f.InstallState -eq 'Installed')
     install_state  = [string]$f.InstallState
     restart_pending = [bool]$pending
+    all_sub_features_installed = [bool]$comp.all_sub
+    management_tools_installed = [bool]$comp.mgmt
   })
 }
 `
 
-// Read implements WindowsFeatureClient.Read.
+// Read implements WindowsFeatureClient.Read. Transient failures are retried
+// as configured by WithReadRetries. With Config.ReadBatching the feature is
+// looked up in the batched snapshot, unless ctx comes from
+// WithoutReadBatching (see read_batch.go).
 func (f *FeatureClient) Read(ctx context.Context, name string) (*FeatureInfo, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, NewFeatureError(FeatureErrorInvalidParameter, "feature name is empty", nil, nil)
 	}
+	if f.c.readBatching(ctx) {
+		return f.readBatched(ctx, name)
+	}
 	script := psFeatureReadBody + "\nRead-Feature -Name " + psQuote(name) + "\n"
-	resp, err := f.runFeatureEnvelope(ctx, "read", name, script)
+	resp, err := retryTransientRead(ctx, func() (*featurePSResponse, error) {
+		return f.runFeatureEnvelope(ctx, "read", name, script)
+	})
 	if err != nil {
 		if IsFeatureError(err, FeatureErrorNotFound) {
 			return nil, nil
@@ -251,9 +310,18 @@ func (f *FeatureClient) Read(ctx context.Context, name string) (*FeatureInfo, er
 
 // psFeatureInstallBody installs a feature and emits the post-state plus the
 // install result. Pre-checks for InstallState=Removed without -Source.
+//
+// With -NoWU the servicing policy UseWindowsUpdate=2 ("never download payload
+// from Windows Update") is set for the duration of the install and restored
+// afterwards, so a bad -Source fails fast instead of hanging on a Windows
+// Update fallback the host cannot reach.
 const psFeatureInstallBody = `
 Ensure-FeatureCmdlets
-function Run-Install([string]$Name, [bool]$IncludeSub, [bool]$IncludeMgmt, [string]$Source, [bool]$Restart) {
+function Run-Install([string]$Name, [bool]$IncludeSub, [bool]$IncludeMgmt, [string]$Source, [bool]$Restart, [bool]$NoWU, [string]$LogPath) {
+  if ($NoWU -and [string]::IsNullOrEmpty($Source)) {
+    Emit-Err 'invalid_parameter' ("Feature '" + $Name + "': use_windows_update=false requires a 'source' path.") @{ name = $Name }
+    return
+  }
   try {
     $cur = Get-WindowsFeature -Name $Name -ErrorAction Stop
   } catch {
@@ -274,12 +342,37 @@ function Run-Install([string]$Name, [bool]$IncludeSub, [bool]$IncludeMgmt, [stri
   if ($IncludeMgmt) { $params['IncludeManagementTools'] = $true }
   if ($Restart)     { $params['Restart'] = $true }
   if (-not [string]::IsNullOrEmpty($Source)) { $params['Source'] = $Source }
+  if (-not [string]::IsNullOrEmpty($LogPath)) { $params['LogPath'] = $LogPath }
+  $svcKey = 'HKLM:\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\Servicing'
+  $hadKey = Test-Path -LiteralPath $svcKey
+  $prevWU = $null
   try {
+    if ($NoWU) {
+      if ($hadKey) {
+        $prevWU = (Get-ItemProperty -LiteralPath $svcKey -Name UseWindowsUpdate -ErrorAction SilentlyContinue).UseWindowsUpdate
+      } else {
+        New-Item -Path $svcKey -Force | Out-Null
+      }
+      New-ItemProperty -LiteralPath $svcKey -Name UseWindowsUpdate -PropertyType DWord -Value 2 -Force | Out-Null
+    }
     $r = Install-WindowsFeature @params
   } catch {
     $msg = $_.Exception.Message
-    Emit-Err (Classify-Feature $msg) $msg @{ name = $Name; phase = 'install' }
+    $ctx = @{ name = $Name; phase = 'install' }
+    if ($NoWU) { $ctx['use_windows_update'] = 'false' }
+    if (-not [string]::IsNullOrEmpty($LogPath)) { $ctx['log_path'] = $LogPath }
+    Emit-Err (Classify-Feature $msg) $msg $ctx
     return
+  } finally {
+    if ($NoWU) {
+      if (-not $hadKey) {
+        Remove-Item -LiteralPath $svcKey -Recurse -Force -ErrorAction SilentlyContinue
+      } elseif ($null -ne $prevWU) {
+        New-ItemProperty -LiteralPath $svcKey -Name UseWindowsUpdate -PropertyType DWord -Value $prevWU -Force | Out-Null
+      } else {
+        Remove-ItemProperty -LiteralPath $svcKey -Name UseWindowsUpdate -ErrorAction SilentlyContinue
+      }
+    }
   }
   $restartNeeded = $false
   $exitCode = ''
@@ -300,6 +393,7 @@ function Run-Install([string]$Name, [bool]$IncludeSub, [bool]$IncludeMgmt, [stri
     restart_needed = [bool]$restartNeeded
     success = [bool]$success
     exit_code = [string]$exitCode
+    already_installed = ($cur.InstallState -eq 'Installed')
   })
 }
 `
@@ -309,14 +403,23 @@ func (f *FeatureClient) Install(ctx context.Context, in FeatureInput) (*FeatureI
 	if strings.TrimSpace(in.Name) == "" {
 		return nil, nil, NewFeatureError(FeatureErrorInvalidParameter, "feature name is empty", nil, nil)
 	}
-	call := fmt.Sprintf("Run-Install -Name %s -IncludeSub:$%s -IncludeMgmt:$%s -Source %s -Restart:$%s",
+	if in.SkipWindowsUpdate && strings.TrimSpace(in.Source) == "" {
+		return nil, nil, NewFeatureError(FeatureErrorInvalidParameter,
+			"use_windows_update=false requires a source path", nil, map[string]string{"name": in.Name})
+	}
+	call := fmt.Sprintf("Run-Install -Name %s -IncludeSub:$%s -IncludeMgmt:$%s -Source %s -Restart:$%s -NoWU:$%s -LogPath %s",
 		psQuote(in.Name),
 		psBool(in.IncludeSubFeatures),
 		psBool(in.IncludeManagementTools),
 		psQuote(in.Source),
 		psBool(in.Restart),
+		psBool(in.SkipWindowsUpdate),
+		psQuote(in.LogPath),
 	)
 	script := psFeatureInstallBody + "\n" + call + "\n"
+	// Drop cached reads once the run ends, whatever its outcome: a failed or
+	// cancelled run may still have changed the feature.
+	defer f.c.invalidateFeatureCache()
 	resp, err := f.runFeatureEnvelope(ctx, "install", in.Name, script)
 	if err != nil {
 		return nil, nil, err
@@ -326,16 +429,17 @@ func (f *FeatureClient) Install(ctx context.Context, in FeatureInput) (*FeatureI
 		return nil, nil, NewFeatureError(FeatureErrorUnknown, "failed to parse install payload", jerr, map[string]string{"name": in.Name})
 	}
 	return toFeatureInfo(payload.Feature), &InstallResult{
-		RestartNeeded: payload.RestartNeeded,
-		Success:       payload.Success,
-		ExitCode:      payload.ExitCode,
+		RestartNeeded:    payload.RestartNeeded,
+		Success:          payload.Success,
+		ExitCode:         payload.ExitCode,
+		AlreadyInstalled: payload.AlreadyInstalled,
 	}, nil
 }
 
 // psFeatureUninstallBody uninstalls a feature and reports post-state.
 const psFeatureUninstallBody = `
 Ensure-FeatureCmdlets
-function Run-Uninstall([string]$Name, [bool]$IncludeMgmt, [bool]$Restart) {
+function Run-Uninstall([string]$Name, [bool]$IncludeMgmt, [bool]$Restart, [string]$LogPath) {
   try {
     $cur = Get-WindowsFeature -Name $Name -ErrorAction Stop
   } catch {
@@ -355,11 +459,14 @@ function Run-Uninstall([string]$Name, [bool]$IncludeMgmt, [bool]$Restart) {
   $params = @{ Name = $Name; ErrorAction = 'Stop' }
   if ($IncludeMgmt) { $params['IncludeManagementTools'] = $true }
   if ($Restart)     { $params['Restart'] = $true }
+  if (-not [string]::IsNullOrEmpty($LogPath)) { $params['LogPath'] = $LogPath }
   try {
     $r = Uninstall-WindowsFeature @params
   } catch {
     $msg = $_.Exception.Message
-    Emit-Err (Classify-Feature $msg) $msg @{ name = $Name; phase = 'uninstall' }
+    $ctx = @{ name = $Name; phase = 'uninstall' }
+    if (-not [string]::IsNullOrEmpty($LogPath)) { $ctx['log_path'] = $LogPath }
+    Emit-Err (Classify-Feature $msg) $msg $ctx
     return
   }
   $restartNeeded = $false
@@ -394,12 +501,14 @@ func (f *FeatureClient) Uninstall(ctx context.Context, in FeatureInput) (*Featur
 	if strings.TrimSpace(in.Name) == "" {
 		return nil, nil, NewFeatureError(FeatureErrorInvalidParameter, "feature name is empty", nil, nil)
 	}
-	call := fmt.Sprintf("Run-Uninstall -Name %s -IncludeMgmt:$%s -Restart:$%s",
+	call := fmt.Sprintf("Run-Uninstall -Name %s -IncludeMgmt:$%s -Restart:$%s -LogPath %s",
 		psQuote(in.Name),
 		psBool(in.IncludeManagementTools),
 		psBool(in.Restart),
+		psQuote(in.LogPath),
 	)
 	script := psFeatureUninstallBody + "\n" + call + "\n"
+	defer f.c.invalidateFeatureCache()
 	resp, err := f.runFeatureEnvelope(ctx, "uninstall", in.Name, script)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,95 @@
+// Package winclient: short-lived cache for windows_feature reads.
+//
+// A configuration that references the same feature from several
+// windows_feature data sources used to pay one Get-WindowsFeature round trip
+// per reference. ReadCached coalesces them: concurrent reads of the same
+// name share one in-flight call, and a result is reused for featureCacheTTL.
+// Any Install or Uninstall through the same Client drops every entry, so a
+// data source read after a windows_feature mutation in the same apply always
+// sees the new state. Errors are never cached.
+package winclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// featureCacheTTL bounds how long a successful read is reused. Tests may
+// shorten it.
+var featureCacheTTL = 30 * time.Second
+
+// featureCacheEntry is one cached or in-flight feature read. done is closed
+// once info, err and reuse are set; reuse is false for failed reads and for
+// reads that raced an invalidation.
+type featureCacheEntry struct {
+	done  chan struct{}
+	info  *FeatureInfo
+	err   error
+	reuse bool
+	at    time.Time
+}
+
+// ReadCached implements WindowsFeatureClient.ReadCached.
+func (f *FeatureClient) ReadCached(ctx context.Context, name string) (*FeatureInfo, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	c := f.c
+	for {
+		c.featureMu.Lock()
+		e, ok := c.featureCache[key]
+		if !ok {
+			break
+		}
+		c.featureMu.Unlock()
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, NewFeatureError(FeatureErrorTimeout,
+				fmt.Sprintf("read of feature %q was cancelled while waiting for a concurrent read", name),
+				ctx.Err(), map[string]string{"operation": "read", "name": name, "host": c.cfg.Host})
+		}
+		if e.reuse && time.Since(e.at) < featureCacheTTL {
+			return e.info, nil
+		}
+		// Expired, failed or stale: drop it (unless already replaced) and
+		// look again.
+		c.featureMu.Lock()
+		if c.featureCache[key] == e {
+			delete(c.featureCache, key)
+		}
+		c.featureMu.Unlock()
+	}
+
+	e := &featureCacheEntry{done: make(chan struct{})}
+	if c.featureCache == nil {
+		c.featureCache = map[string]*featureCacheEntry{}
+	}
+	c.featureCache[key] = e
+	gen := c.featureGen
+	c.featureMu.Unlock()
+
+	info, err := f.Read(ctx, name)
+
+	c.featureMu.Lock()
+	e.info, e.err, e.at = info, err, time.Now()
+	e.reuse = err == nil && gen == c.featureGen
+	if !e.reuse && c.featureCache[key] == e {
+		delete(c.featureCache, key)
+	}
+	close(e.done)
+	c.featureMu.Unlock()
+	return info, err
+}
+
+// invalidateFeatureCache drops every cached feature read, including the
+// batched snapshot (see read_batch.go). Install and Uninstall call it because
+// either can change sub-features and management tools as well as the named
+// feature.
+func (c *Client) invalidateFeatureCache() {
+	c.featureMu.Lock()
+	c.featureGen++
+	c.featureCache = nil
+	c.featureMu.Unlock()
+	c.featureBatch.invalidate()
+}
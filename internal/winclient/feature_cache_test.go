@@ -0,0 +1,116 @@
+// Package winclient — unit tests for the windows_feature read cache.
+package winclient
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFeatureReadCached_ReusesResult(t *testing.T) {
+	var calls atomic.Int32
+	defer stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls.Add(1)
+		return featOK(t, fakeFeatureData("DNS", "Installed")), "", nil
+	})()
+	f := NewFeatureClient(newFeatTestClient(t))
+
+	for _, name := range []string{"DNS", "dns", " DNS "} {
+		info, err := f.ReadCached(context.Background(), name)
+		if err != nil || info == nil || !info.Installed {
+			t.Fatalf("ReadCached(%q) = %+v, %v", name, info, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("host reads = %d, want 1", n)
+	}
+}
+
+func TestFeatureReadCached_CoalescesConcurrentReads(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls.Add(1)
+		<-release
+		return featOK(t, fakeFeatureData("DNS", "Installed")), "", nil
+	})()
+	f := NewFeatureClient(newFeatTestClient(t))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if info, err := f.ReadCached(context.Background(), "DNS"); err != nil || info == nil {
+				t.Errorf("ReadCached = %+v, %v", info, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("host reads = %d, want 1", n)
+	}
+}
+
+func TestFeatureReadCached_InstallInvalidates(t *testing.T) {
+	state := "Available"
+	reads := 0
+	defer stubFeatRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		if strings.Contains(script, "Run-Install") {
+			state = "Installed"
+			return featOK(t, fakeInstallData("DNS", state, false, "Success")), "", nil
+		}
+		reads++
+		return featOK(t, fakeFeatureData("DNS", state)), "", nil
+	})()
+	f := NewFeatureClient(newFeatTestClient(t))
+
+	if info, _ := f.ReadCached(context.Background(), "DNS"); info.Installed {
+		t.Fatal("expected Available before install")
+	}
+	if _, _, err := f.Install(context.Background(), FeatureInput{Name: "DNS"}); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	info, err := f.ReadCached(context.Background(), "DNS")
+	if err != nil || !info.Installed {
+		t.Errorf("read after install must see Installed, got %+v, %v", info, err)
+	}
+	if reads != 2 {
+		t.Errorf("host reads = %d, want 2", reads)
+	}
+}
+
+func TestFeatureReadCached_ErrorsAndExpiryNotReused(t *testing.T) {
+	prev := featureCacheTTL
+	featureCacheTTL = 10 * time.Millisecond
+	defer func() { featureCacheTTL = prev }()
+
+	calls := 0
+	defer stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls++
+		if calls == 1 {
+			return featErr(t, "permission_denied", "Access is denied"), "", nil
+		}
+		return featOK(t, fakeFeatureData("DNS", "Installed")), "", nil
+	})()
+	f := NewFeatureClient(newFeatTestClient(t))
+
+	if _, err := f.ReadCached(context.Background(), "DNS"); !IsFeatureError(err, FeatureErrorPermission) {
+		t.Fatalf("first read: %v", err)
+	}
+	if _, err := f.ReadCached(context.Background(), "DNS"); err != nil {
+		t.Fatalf("a failed read must not be cached: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := f.ReadCached(context.Background(), "DNS"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("host reads = %d, want 3", calls)
+	}
+}
@@ -211,6 +211,29 @@ func TestRunFeatureEnvelope_Timeout_EC8(t *testing.T) {
 	}
 }
 
+func TestRunFeatureEnvelope_DeadlineNamesFeatureAndTimeouts(t *testing.T) {
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	})
+	defer restore()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	f := NewFeatureClient(newFeatTestClient(t))
+	_, err := f.Read(ctx, "Web-Server")
+	var fe *FeatureError
+	if !errors.As(err, &fe) || fe.Kind != FeatureErrorTimeout {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if !strings.Contains(fe.Message, `"Web-Server" timed out after`) || !strings.Contains(fe.Message, "timeouts block") {
+		t.Errorf("message = %q", fe.Message)
+	}
+	if fe.Context["name"] != "Web-Server" || fe.Context["elapsed"] == "" {
+		t.Errorf("context = %+v", fe.Context)
+	}
+}
+
 func TestRunFeatureEnvelope_TransportError(t *testing.T) {
 	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
 		return "stdout-junk", "stderr-junk", errors.New("winrm: tcp reset")
@@ -223,6 +246,19 @@ func TestRunFeatureEnvelope_TransportError(t *testing.T) {
 	}
 }
 
+func TestRunFeatureEnvelope_ExitCodeInContext(t *testing.T) {
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		return "", "", &TransportError{Kind: FailureCommand, ExitCode: 1,
+			Err: errors.New("winclient: powershell exited with code 1")}
+	})
+	defer restore()
+	_, err := NewFeatureClient(newFeatTestClient(t)).Read(context.Background(), "Web-Server")
+	var fe *FeatureError
+	if !errors.As(err, &fe) || fe.Context["exit_code"] != "1" {
+		t.Errorf("expected exit_code 1 in context, got %v", err)
+	}
+}
+
 func TestRunFeatureEnvelope_NoJSON(t *testing.T) {
 	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
 		return "WARNING: no JSON\n", "", nil
@@ -348,6 +384,47 @@ func TestFeatureRead_UnsupportedSKU_EC9(t *testing.T) {
 	}
 }
 
+func TestFeatureRead_Components(t *testing.T) {
+	var captured string
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		d := fakeFeatureData("Web-Server", "Installed")
+		d["all_sub_features_installed"] = false
+		d["management_tools_installed"] = true
+		return featOK(t, d), "", nil
+	})
+	f := NewFeatureClient(newFeatTestClient(t))
+	info, err := f.Read(context.Background(), "Web-Server")
+	restore()
+	if err != nil {
+		t.Fatalf("Read err: %v", err)
+	}
+	if info.AllSubFeaturesInstalled || !info.ManagementToolsInstalled {
+		t.Errorf("unexpected components: %+v", info)
+	}
+	for _, want := range []string{"Get-FeatureComponents $f", "$state -eq 'Removed'", "'-Mgmt-|^RSAT-'"} {
+		if !strings.Contains(captured, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	if strings.Contains(captured, "WhatIf") {
+		t.Error("Read must not run Install-WindowsFeature -WhatIf")
+	}
+
+	// Payloads without the flags (install, uninstall) never read as drift.
+	restore = stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		return featOK(t, fakeFeatureData("Web-Server", "Installed")), "", nil
+	})
+	defer restore()
+	info, err = f.Read(context.Background(), "Web-Server")
+	if err != nil {
+		t.Fatalf("Read err: %v", err)
+	}
+	if !info.AllSubFeaturesInstalled || !info.ManagementToolsInstalled {
+		t.Errorf("missing flags must default to complete: %+v", info)
+	}
+}
+
 func TestFeatureRead_UnparseablePayload(t *testing.T) {
 	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
 		return `{"ok":true,"data":"not-an-object"}` + "\n", "", nil
@@ -400,6 +477,92 @@ func TestFeatureInstall_HappyPath(t *testing.T) {
 	}
 }
 
+func TestFeatureInstall_AlreadyInstalled(t *testing.T) {
+	var captured string
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		d := fakeInstallData("Web-Server", "Installed", false, "NoChangeNeeded")
+		d["already_installed"] = true
+		return featOK(t, d), "", nil
+	})
+	defer restore()
+
+	f := NewFeatureClient(newFeatTestClient(t))
+	_, result, err := f.Install(context.Background(), FeatureInput{Name: "Web-Server"})
+	if err != nil {
+		t.Fatalf("Install err: %v", err)
+	}
+	if result == nil || !result.AlreadyInstalled {
+		t.Errorf("AlreadyInstalled not propagated: %+v", result)
+	}
+	if !strings.Contains(captured, "already_installed = ($cur.InstallState -eq 'Installed')") {
+		t.Error("install script must report the pre-install state")
+	}
+}
+
+func TestFeatureInstall_NoWindowsUpdate(t *testing.T) {
+	var captured string
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return featOK(t, fakeInstallData("NET-Framework-Core", "Installed", false, "Success")), "", nil
+	})
+	defer restore()
+
+	f := NewFeatureClient(newFeatTestClient(t))
+	if _, _, err := f.Install(context.Background(), FeatureInput{
+		Name: "NET-Framework-Core", Source: `D:\sources\sxs`, SkipWindowsUpdate: true,
+	}); err != nil {
+		t.Fatalf("Install err: %v", err)
+	}
+	if !strings.Contains(captured, "-NoWU:$true") {
+		t.Errorf("script missing -NoWU:$true: %s", captured)
+	}
+	if !strings.Contains(captured, "UseWindowsUpdate -PropertyType DWord -Value 2") {
+		t.Errorf("script should set the servicing policy: %s", captured)
+	}
+}
+
+func TestFeatureInstall_SourceAndLogPath(t *testing.T) {
+	var captured string
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return featOK(t, fakeInstallData("NET-Framework-Core", "Installed", false, "Success")), "", nil
+	})
+	defer restore()
+
+	f := NewFeatureClient(newFeatTestClient(t))
+	if _, _, err := f.Install(context.Background(), FeatureInput{
+		Name: "NET-Framework-Core", Source: `D:\sources\sxs`, LogPath: `C:\Windows\Logs\netfx3.log`,
+	}); err != nil {
+		t.Fatalf("Install err: %v", err)
+	}
+	if !strings.Contains(captured, `-Source 'D:\sources\sxs'`) ||
+		!strings.Contains(captured, `-LogPath 'C:\Windows\Logs\netfx3.log'`) {
+		t.Errorf("script missing -Source / -LogPath: %s", captured)
+	}
+	if !strings.Contains(captured, "$params['LogPath'] = $LogPath") {
+		t.Errorf("script should pass LogPath to Install-WindowsFeature: %s", captured)
+	}
+}
+
+func TestFeatureInstall_NoWindowsUpdateRequiresSource(t *testing.T) {
+	called := false
+	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		called = true
+		return "", "", nil
+	})
+	defer restore()
+
+	f := NewFeatureClient(newFeatTestClient(t))
+	_, _, err := f.Install(context.Background(), FeatureInput{Name: "NET-Framework-Core", SkipWindowsUpdate: true})
+	if !IsFeatureError(err, FeatureErrorInvalidParameter) {
+		t.Errorf("expected invalid_parameter, got %v", err)
+	}
+	if called {
+		t.Error("no script should run without a source")
+	}
+}
+
 func TestFeatureInstall_RestartNeeded_EC4(t *testing.T) {
 	restore := stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
 		d := fakeInstallData("Web-Server", "Installed", true, "SuccessRestartRequired")
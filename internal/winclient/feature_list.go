@@ -0,0 +1,93 @@
+// Package winclient: installed Windows feature enumeration over WinRM.
+//
+// FeatureClient.ListInstalled backs the windows_installed_features data
+// source. Each element uses the same JSON keys as the single-feature read
+// script (name, display_name, install_state) plus the tree position (depth,
+// parent), so both paths decode through featureDataPayload.
+//
+// Security invariants:
+//   - The name filter is interpolated only through psQuote.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Compile-time assertion: FeatureClient satisfies WindowsFeatureLister.
+var _ WindowsFeatureLister = (*FeatureClient)(nil)
+
+// installedFeaturePayload is one entry of the "features" array emitted by
+// psFeatureListInstalledBody.
+type installedFeaturePayload struct {
+	featureDataPayload
+	Depth  int    `json:"depth"`
+	Parent string `json:"parent"`
+}
+
+// psFeatureListInstalledBody enumerates installed features matching
+// @@NAME@@. The array is wrapped in an object so ConvertTo-Json keeps it an
+// array for a single match; ListInstalled still accepts a bare object.
+const psFeatureListInstalledBody = `
+Ensure-FeatureCmdlets
+try {
+  $nameFilter = @@NAME@@
+  $rows = @(Get-WindowsFeature -ErrorAction Stop | Where-Object {
+      $_.Installed -and ($nameFilter -eq '' -or $_.Name -like $nameFilter)
+    } | ForEach-Object {
+      [ordered]@{
+        name          = [string]$_.Name
+        display_name  = [string]$_.DisplayName
+        install_state = [string]$_.InstallState
+        depth         = [int]$_.Depth
+        parent        = [string]$_.Parent
+      }
+    })
+  Emit-OK ([ordered]@{ features = $rows })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Feature $msg) $msg @{ name_filter = $nameFilter }
+}
+`
+
+// ListInstalled implements WindowsFeatureLister.ListInstalled.
+func (f *FeatureClient) ListInstalled(ctx context.Context, namePattern string) ([]InstalledFeature, error) {
+	script := strings.NewReplacer("@@NAME@@", psQuote(namePattern)).Replace(psFeatureListInstalledBody)
+	resp, err := f.runFeatureEnvelope(ctx, "list_installed", namePattern, script)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := decodeInstalledFeatures(resp.Data)
+	if err != nil {
+		return nil, NewFeatureError(FeatureErrorUnknown, "failed to parse installed feature list", err,
+			map[string]string{"operation": "list_installed", "host": f.c.cfg.Host})
+	}
+
+	out := make([]InstalledFeature, 0, len(payload))
+	for _, p := range payload {
+		out = append(out, InstalledFeature{
+			Name:         p.Name,
+			DisplayName:  p.DisplayName,
+			InstallState: p.InstallState,
+			Depth:        p.Depth,
+			Parent:       p.Parent,
+		})
+	}
+	return out, nil
+}
+
+// decodeInstalledFeatures extracts the "features" list from the
+// ListInstalled envelope data, accepting the collapsed shapes ConvertTo-Json
+// can produce (see normalizeJSONArray).
+func decodeInstalledFeatures(data json.RawMessage) ([]installedFeaturePayload, error) {
+	var wrapper struct {
+		Features jsonList[installedFeaturePayload] `json:"features"`
+	}
+	if len(data) > 0 && string(data) != "null" {
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, err
+		}
+	}
+	return wrapper.Features, nil
+}
@@ -0,0 +1,85 @@
+// Package winclient — unit tests for FeatureClient.ListInstalled.
+package winclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFeatureListInstalled_FiltersAndDecodes(t *testing.T) {
+	var script string
+	defer stubFeatRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return featOK(t, map[string]any{"features": []map[string]any{
+			{"name": "Web-Server", "display_name": "Web Server (IIS)", "install_state": "Installed", "depth": 1, "parent": ""},
+			{"name": "Web-WebServer", "display_name": "Web Server", "install_state": "Installed", "depth": 2, "parent": "Web-Server"},
+		}}), "", nil
+	})()
+
+	got, err := NewFeatureClient(newFeatTestClient(t)).ListInstalled(context.Background(), "Web-*")
+	if err != nil {
+		t.Fatalf("ListInstalled: %v", err)
+	}
+	if len(got) != 2 || got[0].Depth != 1 || got[0].Parent != "" || got[1].Parent != "Web-Server" || got[1].DisplayName != "Web Server" {
+		t.Errorf("ListInstalled = %+v", got)
+	}
+	for _, want := range []string{"$nameFilter = 'Web-*'", "Get-WindowsFeature", "$_.Installed", "Ensure-FeatureCmdlets"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestFeatureListInstalled_QuotesFilter(t *testing.T) {
+	var script string
+	defer stubFeatRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return featOK(t, map[string]any{"features": []any{}}), "", nil
+	})()
+
+	if _, err := NewFeatureClient(newFeatTestClient(t)).ListInstalled(context.Background(), "x'; Restart-Computer; '"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(script, "$nameFilter = 'x''; Restart-Computer; '''") {
+		t.Error("name filter was not quoted")
+	}
+}
+
+func TestDecodeInstalledFeatures_Shapes(t *testing.T) {
+	cases := map[string]int{
+		`null`:                               0,
+		`{"features":null}`:                  0,
+		`{"features":[]}`:                    0,
+		`{"features":{"name":"Web-Server"}}`: 1,
+		`{"features":[{"name":"a"},{"name":"b","depth":2,"parent":"a"}]}`: 2,
+	}
+	for in, want := range cases {
+		got, err := decodeInstalledFeatures([]byte(in))
+		if err != nil || len(got) != want {
+			t.Errorf("decode(%s) = %d entries, %v; want %d", in, len(got), err, want)
+		}
+	}
+}
+
+func TestFeatureListInstalled_EmptyAndErrors(t *testing.T) {
+	f := NewFeatureClient(newFeatTestClient(t))
+
+	restore := stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return featOK(t, map[string]any{"features": nil}), "", nil
+	})
+	got, err := f.ListInstalled(context.Background(), "nomatch*")
+	restore()
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("no match: %#v, %v; want empty non-nil slice", got, err)
+	}
+
+	restore = stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return featErr(t, "unsupported_sku", "Install-WindowsFeature is not available on this host."), "", nil
+	})
+	_, err = f.ListInstalled(context.Background(), "")
+	restore()
+	if !IsFeatureError(err, FeatureErrorUnsupportedSKU) {
+		t.Errorf("err = %v, want unsupported_sku", err)
+	}
+}
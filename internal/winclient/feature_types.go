@@ -45,8 +45,11 @@ func (e *FeatureError) Error() string {
 // Unwrap returns the underlying cause.
 func (e *FeatureError) Unwrap() error { return e.Cause }
 
-// Is matches by Kind only.
+// Is matches by Kind, or by ErrorClass (see errors.go).
 func (e *FeatureError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*FeatureError)
 	if !ok {
 		return false
@@ -94,6 +97,26 @@ type FeatureInfo struct {
 	InstallState string
 	// RestartPending is true when the OS exposes a pending reboot flag.
 	RestartPending bool
+	// AllSubFeaturesInstalled is false when Read finds an installed feature
+	// with a sub-feature that is not installed. Sub-features whose payload
+	// was removed (InstallState "Removed") are ignored.
+	AllSubFeaturesInstalled bool
+	// ManagementToolsInstalled is false when Read finds an installed feature
+	// whose sub-feature tree holds management tools (-Mgmt-, RSAT-) none of
+	// which is installed.
+	ManagementToolsInstalled bool
+}
+
+// InstalledFeature is one entry returned by WindowsFeatureLister.ListInstalled.
+type InstalledFeature struct {
+	// Name, DisplayName and InstallState are as in FeatureInfo.
+	Name         string
+	DisplayName  string
+	InstallState string
+	// Depth is the feature's level in the role/feature tree (1 = top level).
+	Depth int
+	// Parent is the Name of the parent feature, empty for a top-level one.
+	Parent string
 }
 
 // InstallResult is the side-channel returned by Install/Uninstall.
@@ -104,6 +127,10 @@ type InstallResult struct {
 	Success bool
 	// ExitCode is the cmdlet ExitCode (often "Success", "NoChangeNeeded", "SuccessRestartRequired").
 	ExitCode string
+	// AlreadyInstalled is true when Install found the feature already in
+	// InstallState=Installed before running Install-WindowsFeature, i.e. the
+	// feature was adopted rather than installed. Always false for Uninstall.
+	AlreadyInstalled bool
 }
 
 // FeatureInput carries the desired configuration for Install/Uninstall.
@@ -113,6 +140,12 @@ type FeatureInput struct {
 	IncludeManagementTools bool
 	Source                 string
 	Restart                bool
+	// SkipWindowsUpdate forbids Install from falling back to Windows Update
+	// for the feature payload; Source must then be set.
+	SkipWindowsUpdate bool
+	// LogPath, when set, is passed as -LogPath so the servicing stack writes
+	// a detailed log on the host (e.g. C:\Windows\Logs\feature.log).
+	LogPath string
 }
 
 // WindowsFeatureClient is the contract for the windows_feature resource.
@@ -121,6 +154,12 @@ type WindowsFeatureClient interface {
 	// feature does not exist on the target host (drift removal).
 	Read(ctx context.Context, name string) (*FeatureInfo, error)
 
+	// ReadCached is Read through a short-lived per-client cache: concurrent
+	// and repeated reads of the same name share one round trip. Any Install
+	// or Uninstall on the same client invalidates it. Used by the data source;
+	// the resource always reads fresh state.
+	ReadCached(ctx context.Context, name string) (*FeatureInfo, error)
+
 	// Install installs the feature with the given options. Returns the
 	// observed FeatureInfo plus the InstallResult for restart_pending.
 	Install(ctx context.Context, in FeatureInput) (*FeatureInfo, *InstallResult, error)
@@ -129,3 +168,12 @@ type WindowsFeatureClient interface {
 	// honoured; Source / IncludeSubFeatures are ignored.
 	Uninstall(ctx context.Context, in FeatureInput) (*FeatureInfo, *InstallResult, error)
 }
+
+// WindowsFeatureLister is the contract for the windows_installed_features
+// data source.
+type WindowsFeatureLister interface {
+	// ListInstalled returns the installed features whose name matches the
+	// PowerShell wildcard namePattern (every installed feature when empty),
+	// in Get-WindowsFeature tree order. No match yields an empty slice.
+	ListInstalled(ctx context.Context, namePattern string) ([]InstalledFeature, error)
+}
@@ -370,6 +370,7 @@ func buildFirewallParams(op, name, policyStore string, input FirewallRuleInput)
 // the parsed psResponse envelope. Transport/PS-level errors that bypass
 // Emit-Err are translated into *FirewallRuleError.
 func (c *FirewallRuleClient) runFirewallEnvelope(ctx context.Context, op, name, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := frPsHeader + "\n" + script
 	stdout, stderr, err := runPowerShell(ctx, c.c, full)
 	if err != nil {
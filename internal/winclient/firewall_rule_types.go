@@ -67,8 +67,11 @@ func (e *FirewallRuleError) Error() string {
 // Unwrap returns the underlying cause for errors.As / errors.Is chain walking.
 func (e *FirewallRuleError) Unwrap() error { return e.Cause }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 func (e *FirewallRuleError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*FirewallRuleError)
 	if !ok {
 		return false
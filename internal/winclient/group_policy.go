@@ -0,0 +1,240 @@
+// Package winclient: Group Policy refresh (gpupdate) over WinRM.
+//
+// GroupPolicyClient is the concrete WindowsGroupPolicyClient backing the
+// windows_group_policy_refresh resource. One script runs gpupdate /force and
+// emits its exit code and console output; parseGPUpdateOutput decides from
+// both whether the refresh failed, so a failure is caught on hosts whose
+// gpupdate messages are not in English (exit code) as well as when gpupdate
+// exits 0 after a partial failure (output).
+//
+// Security invariants:
+//   - Target is checked against the GroupPolicyTarget* constants and
+//     WaitSeconds is an integer; nothing else is interpolated.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Compile-time assertion: GroupPolicyClient satisfies
+// WindowsGroupPolicyClient.
+var _ WindowsGroupPolicyClient = (*GroupPolicyClient)(nil)
+
+// GroupPolicyClient is the PowerShell/WinRM-backed WindowsGroupPolicyClient.
+type GroupPolicyClient struct {
+	c *Client
+}
+
+// NewGroupPolicyClient wraps the given WinRM Client.
+func NewGroupPolicyClient(c *Client) *GroupPolicyClient { return &GroupPolicyClient{c: c} }
+
+// runGroupPolicyPowerShell is the package-level indirection used by
+// GroupPolicyClient. Tests may override it; production code must not.
+var runGroupPolicyPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// groupPolicyPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type groupPolicyPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// gpupdatePayload mirrors the object emitted by psGPUpdate.
+type gpupdatePayload struct {
+	ExitCode    int    `json:"exit_code"`
+	Output      string `json:"output"`
+	RefreshedAt string `json:"refreshed_at"`
+}
+
+// psGroupPolicyHeader prepends Emit-OK/Emit-Err and Classify-GroupPolicy.
+const psGroupPolicyHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Classify-GroupPolicy([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied') { return 'permission_denied' }
+  return 'unknown'
+}
+`
+
+// psGPUpdate runs gpupdate with the @@ARGS@@ argument list. "N" is piped to
+// stdin so a "OK to log off? / restart?" prompt is declined instead of
+// waiting forever. Native stderr is merged into the output, which must not
+// trip $ErrorActionPreference = 'Stop' on Windows PowerShell 5.1.
+const psGPUpdate = `
+try {
+  $gpArgs = @@ARGS@@
+  $ErrorActionPreference = 'Continue'
+  $out = ('N', 'N' | & gpupdate.exe @gpArgs 2>&1 | Out-String)
+  $code = $LASTEXITCODE
+  $ErrorActionPreference = 'Stop'
+  Emit-OK ([ordered]@{
+    exit_code    = [int]$code
+    output       = [string]$out
+    refreshed_at = (Get-Date).ToUniversalTime().ToString('o')
+  })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-GroupPolicy $msg) $msg @{}
+}
+`
+
+// gpupdateFailureMarkers are the (lower-case) English gpupdate lines that
+// report a failed refresh even when the exit code is 0.
+var gpupdateFailureMarkers = []string{
+	"could not be updated successfully",
+	"processing of group policy failed",
+}
+
+// runGroupPolicyEnvelope executes script (prepended with
+// psGroupPolicyHeader) and parses the JSON envelope. Cancellation maps to
+// GroupPolicyErrorTimeout; other transport failures to
+// GroupPolicyErrorUnknown.
+func (g *GroupPolicyClient) runGroupPolicyEnvelope(ctx context.Context, op, script string) (*groupPolicyPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psGroupPolicyHeader + "\n" + script
+	stdout, stderr, err := runGroupPolicyPowerShell(ctx, g.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      g.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewGroupPolicyError(GroupPolicyErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewGroupPolicyError(GroupPolicyErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewGroupPolicyError(GroupPolicyErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp groupPolicyPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewGroupPolicyError(GroupPolicyErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewGroupPolicyError(mapGroupPolicyKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapGroupPolicyKind translates a PS-side "kind" string to a typed
+// GroupPolicyErrorKind. Unknown values fall through to
+// GroupPolicyErrorUnknown.
+func mapGroupPolicyKind(k string) GroupPolicyErrorKind {
+	switch k {
+	case string(GroupPolicyErrorPermission),
+		string(GroupPolicyErrorTimeout):
+		return GroupPolicyErrorKind(k)
+	default:
+		return GroupPolicyErrorUnknown
+	}
+}
+
+// gpupdateArgs renders the gpupdate argument list for in.
+func gpupdateArgs(in GroupPolicyRefreshInput) ([]string, error) {
+	args := []string{"/force"}
+	switch in.Target {
+	case GroupPolicyTargetBoth:
+	case GroupPolicyTargetComputer, GroupPolicyTargetUser:
+		args = append(args, "/target:"+in.Target)
+	default:
+		return nil, fmt.Errorf("target must be %q or %q, got %q", GroupPolicyTargetComputer, GroupPolicyTargetUser, in.Target)
+	}
+	wait := in.WaitSeconds
+	if wait < 0 {
+		wait = -1
+	}
+	return append(args, fmt.Sprintf("/wait:%d", wait)), nil
+}
+
+// Refresh implements WindowsGroupPolicyClient.Refresh.
+func (g *GroupPolicyClient) Refresh(ctx context.Context, in GroupPolicyRefreshInput) (*GroupPolicyRefresh, error) {
+	args, err := gpupdateArgs(in)
+	if err != nil {
+		return nil, NewGroupPolicyError(GroupPolicyErrorInvalidParameter, err.Error(), nil, map[string]string{"host": g.c.cfg.Host})
+	}
+	script := strings.NewReplacer("@@ARGS@@", psQuoteList(args)).Replace(psGPUpdate)
+	resp, err := g.runGroupPolicyEnvelope(ctx, "refresh", script)
+	if err != nil {
+		return nil, err
+	}
+	var pl gpupdatePayload
+	if jerr := json.Unmarshal(resp.Data, &pl); jerr != nil {
+		return nil, NewGroupPolicyError(GroupPolicyErrorUnknown,
+			"failed to parse gpupdate result", jerr,
+			map[string]string{"host": g.c.cfg.Host})
+	}
+	return parseGPUpdateOutput(pl, g.c.cfg.Host, strings.Join(args, " "))
+}
+
+// parseGPUpdateOutput turns a gpupdate run into a GroupPolicyRefresh, or a
+// GroupPolicyErrorRefreshFailed error carrying the output when the exit code
+// is non-zero or the output contains a failure line.
+func parseGPUpdateOutput(pl gpupdatePayload, host, args string) (*GroupPolicyRefresh, error) {
+	out := strings.TrimSpace(pl.Output)
+	lower := strings.ToLower(out)
+	failed := pl.ExitCode != 0
+	for _, m := range gpupdateFailureMarkers {
+		if strings.Contains(lower, m) {
+			failed = true
+		}
+	}
+	if failed {
+		return nil, NewGroupPolicyError(GroupPolicyErrorRefreshFailed,
+			fmt.Sprintf("gpupdate %s reported a failure (exit code %d): %s", args, pl.ExitCode, truncate(out, 2048)),
+			nil, map[string]string{"host": host, "exit_code": fmt.Sprint(pl.ExitCode)})
+	}
+	at, err := time.Parse(time.RFC3339Nano, pl.RefreshedAt)
+	if err != nil {
+		return nil, NewGroupPolicyError(GroupPolicyErrorUnknown,
+			fmt.Sprintf("invalid refresh time %q", pl.RefreshedAt), err, map[string]string{"host": host})
+	}
+	return &GroupPolicyRefresh{
+		RefreshedAt:     at.UTC(),
+		Output:          out,
+		LogoffRequired:  strings.Contains(lower, "log off") || strings.Contains(lower, "logoff"),
+		RestartRequired: strings.Contains(lower, "restart"),
+	}, nil
+}
@@ -0,0 +1,161 @@
+// Package winclient — unit tests for GroupPolicyClient.
+//
+// These tests stub the package-level seam runGroupPolicyPowerShell to inject
+// scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubGroupPolicyRun replaces runGroupPolicyPowerShell for the duration of a
+// test and returns a restore function (typically deferred).
+func stubGroupPolicyRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runGroupPolicyPowerShell
+	runGroupPolicyPowerShell = fn
+	return func() { runGroupPolicyPowerShell = prev }
+}
+
+func gpupdateStdout(t *testing.T, code int, output string, script *string) func(context.Context, *Client, string) (string, string, error) {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"ok": true, "data": map[string]any{
+		"exit_code": code, "output": output, "refreshed_at": "2026-10-17T09:30:00.1234567Z",
+	}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if script != nil {
+			*script = s
+		}
+		return "Updating policy...\n" + string(b) + "\n", "", nil
+	}
+}
+
+const gpupdateOKOutput = `Updating policy...
+
+Computer Policy update has completed successfully.
+User Policy update has completed successfully.
+`
+
+func TestGroupPolicyRefresh_HappyPath(t *testing.T) {
+	var script string
+	defer stubGroupPolicyRun(gpupdateStdout(t, 0, gpupdateOKOutput, &script))()
+	got, err := NewGroupPolicyClient(newLouTestClient(t)).Refresh(context.Background(),
+		GroupPolicyRefreshInput{Target: GroupPolicyTargetComputer, WaitSeconds: 120})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	want := time.Date(2026, 10, 17, 9, 30, 0, 123456700, time.UTC)
+	if !got.RefreshedAt.Equal(want) || got.LogoffRequired || got.RestartRequired ||
+		!strings.HasPrefix(got.Output, "Updating policy...") {
+		t.Errorf("unexpected result: %+v", got)
+	}
+	for _, want := range []string{"@('/force','/target:Computer','/wait:120')", "'N', 'N' | & gpupdate.exe @gpArgs"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestGpupdateArgs(t *testing.T) {
+	cases := []struct {
+		in   GroupPolicyRefreshInput
+		want string
+	}{
+		{GroupPolicyRefreshInput{WaitSeconds: 600}, "/force /wait:600"},
+		{GroupPolicyRefreshInput{Target: GroupPolicyTargetUser}, "/force /target:User /wait:0"},
+		{GroupPolicyRefreshInput{WaitSeconds: -5}, "/force /wait:-1"},
+	}
+	for _, tc := range cases {
+		got, err := gpupdateArgs(tc.in)
+		if err != nil || strings.Join(got, " ") != tc.want {
+			t.Errorf("gpupdateArgs(%+v) = %v, %v; want %q", tc.in, got, err, tc.want)
+		}
+	}
+	if _, err := gpupdateArgs(GroupPolicyRefreshInput{Target: "Both"}); err == nil {
+		t.Error("unknown target must be rejected")
+	}
+}
+
+func TestGroupPolicyRefresh_InvalidTarget(t *testing.T) {
+	defer stubGroupPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		t.Fatal("no remote call expected")
+		return "", "", nil
+	})()
+	_, err := NewGroupPolicyClient(newLouTestClient(t)).Refresh(context.Background(), GroupPolicyRefreshInput{Target: "computer; calc"})
+	if !IsGroupPolicyError(err, GroupPolicyErrorInvalidParameter) {
+		t.Errorf("expected invalid_parameter, got %v", err)
+	}
+}
+
+func TestParseGPUpdateOutput(t *testing.T) {
+	cases := []struct {
+		name            string
+		code            int
+		output          string
+		failed          bool
+		logoff, restart bool
+	}{
+		{"success", 0, gpupdateOKOutput, false, false, false},
+		{"failure line with exit 0", 0, "Computer policy could not be updated successfully. The following errors were encountered:\n" +
+			"The processing of Group Policy failed. Windows could not resolve the computer name.", true, false, false},
+		{"non-English output, non-zero exit", 1, "La mise à jour de la stratégie a échoué.", true, false, false},
+		{"logoff prompt declined", 0, gpupdateOKOutput + "Certain User policies are enabled that can only run during logon.\nOK to log off? (Y/N)", false, true, false},
+		{"restart prompt declined", 0, gpupdateOKOutput + "Certain Computer policies are enabled that can only run during startup.\nOK to restart? (Y/N)", false, false, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseGPUpdateOutput(gpupdatePayload{ExitCode: tc.code, Output: tc.output, RefreshedAt: "2026-10-17T09:30:00Z"}, "win01", "/force /wait:600")
+			if tc.failed {
+				if !errors.Is(err, ErrGroupPolicyRefreshFailed) {
+					t.Fatalf("expected refresh_failed, got %v", err)
+				}
+				var ge *GroupPolicyError
+				errors.As(err, &ge)
+				if !strings.Contains(ge.Message, strings.TrimSpace(tc.output)) || ge.Context["host"] != "win01" {
+					t.Errorf("failure must carry the gpupdate output and host: %+v", ge)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.LogoffRequired != tc.logoff || got.RestartRequired != tc.restart {
+				t.Errorf("logoff=%v restart=%v, want %v/%v", got.LogoffRequired, got.RestartRequired, tc.logoff, tc.restart)
+			}
+		})
+	}
+}
+
+func TestGroupPolicyRefresh_PermissionDenied(t *testing.T) {
+	defer stubGroupPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return `{"ok":false,"kind":"permission_denied","message":"Access is denied."}` + "\n", "", nil
+	})()
+	_, err := NewGroupPolicyClient(newLouTestClient(t)).Refresh(context.Background(), GroupPolicyRefreshInput{})
+	if !errors.Is(err, ErrGroupPolicyPermission) {
+		t.Fatalf("expected permission_denied, got %v", err)
+	}
+	var ge *GroupPolicyError
+	errors.As(err, &ge)
+	if ge.Context["host"] != "win01" || ge.Context["operation"] != "refresh" {
+		t.Errorf("context = %v", ge.Context)
+	}
+}
+
+func TestGroupPolicyRefresh_Timeout(t *testing.T) {
+	defer stubGroupPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.DeadlineExceeded
+	})()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewGroupPolicyClient(newLouTestClient(t)).Refresh(ctx, GroupPolicyRefreshInput{})
+	if !IsGroupPolicyError(err, GroupPolicyErrorTimeout) {
+		t.Errorf("expected timeout on cancelled ctx, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package winclient: types for the windows_group_policy_refresh resource.
+//
+// GroupPolicyRefresh is the outcome of one gpupdate /force run.
+// GroupPolicyErrorKind / GroupPolicyError follow the same shape as
+// RebootError.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GroupPolicyErrorKind categorises errors returned by
+// WindowsGroupPolicyClient.
+type GroupPolicyErrorKind string
+
+const (
+	// GroupPolicyErrorRefreshFailed: gpupdate ran but reported that policy
+	// could not be applied (non-zero exit code or a failure line).
+	GroupPolicyErrorRefreshFailed    GroupPolicyErrorKind = "refresh_failed"
+	GroupPolicyErrorPermission       GroupPolicyErrorKind = "permission_denied"
+	GroupPolicyErrorTimeout          GroupPolicyErrorKind = "timeout"
+	GroupPolicyErrorInvalidParameter GroupPolicyErrorKind = "invalid_parameter"
+	GroupPolicyErrorUnknown          GroupPolicyErrorKind = "unknown"
+)
+
+// GroupPolicyError is the structured error type returned by
+// WindowsGroupPolicyClient.
+type GroupPolicyError struct {
+	Kind    GroupPolicyErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *GroupPolicyError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_group_policy_refresh [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_group_policy_refresh [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *GroupPolicyError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *GroupPolicyError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*GroupPolicyError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewGroupPolicyError constructs a *GroupPolicyError.
+func NewGroupPolicyError(kind GroupPolicyErrorKind, msg string, cause error, ctx map[string]string) *GroupPolicyError {
+	return &GroupPolicyError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsGroupPolicyError reports whether err is a *GroupPolicyError of the given
+// kind.
+func IsGroupPolicyError(err error, kind GroupPolicyErrorKind) bool {
+	var ge *GroupPolicyError
+	if errors.As(err, &ge) {
+		return ge.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrGroupPolicyRefreshFailed    = &GroupPolicyError{Kind: GroupPolicyErrorRefreshFailed}
+	ErrGroupPolicyPermission       = &GroupPolicyError{Kind: GroupPolicyErrorPermission}
+	ErrGroupPolicyTimeout          = &GroupPolicyError{Kind: GroupPolicyErrorTimeout}
+	ErrGroupPolicyInvalidParameter = &GroupPolicyError{Kind: GroupPolicyErrorInvalidParameter}
+	ErrGroupPolicyUnknown          = &GroupPolicyError{Kind: GroupPolicyErrorUnknown}
+)
+
+// Group policy refresh targets accepted by GroupPolicyRefreshInput.Target.
+const (
+	// GroupPolicyTargetBoth refreshes computer and user policy (no /target).
+	GroupPolicyTargetBoth = ""
+	// GroupPolicyTargetComputer refreshes computer policy only.
+	GroupPolicyTargetComputer = "Computer"
+	// GroupPolicyTargetUser refreshes the WinRM user's policy only.
+	GroupPolicyTargetUser = "User"
+)
+
+// GroupPolicyRefreshInput carries the gpupdate options.
+type GroupPolicyRefreshInput struct {
+	// Target is one of the GroupPolicyTarget* constants.
+	Target string
+	// WaitSeconds is passed as /wait:N: how long gpupdate waits for policy
+	// processing before returning (processing then continues in the
+	// background). Zero returns at once; negative waits indefinitely.
+	WaitSeconds int64
+}
+
+// GroupPolicyRefresh is the outcome of a successful gpupdate run.
+type GroupPolicyRefresh struct {
+	// RefreshedAt is the host's UTC clock when gpupdate returned.
+	RefreshedAt time.Time
+	// Output is the console output of gpupdate, trimmed.
+	Output string
+	// LogoffRequired / RestartRequired are true when gpupdate reported that
+	// some settings only apply at the next logon or boot.
+	LogoffRequired  bool
+	RestartRequired bool
+}
+
+// WindowsGroupPolicyClient is the contract for the
+// windows_group_policy_refresh resource.
+type WindowsGroupPolicyClient interface {
+	// Refresh runs gpupdate /force and returns GroupPolicyErrorRefreshFailed
+	// when gpupdate reports that policy could not be applied.
+	Refresh(ctx context.Context, in GroupPolicyRefreshInput) (*GroupPolicyRefresh, error)
+}
@@ -0,0 +1,329 @@
+// Package winclient: transport failure classification and connection health.
+//
+// A failed RunPowerShell call used to surface as a bare error, so "host
+// down", "credentials rejected" and "the script failed" all looked alike.
+// Every run is now classified and counted:
+//
+//   - dial:    the TCP connection could not be opened (DNS failure, refused,
+//     connect timeout). Later runs back off exponentially (1s..30s) instead
+//     of hammering a host that is rebooting or unreachable.
+//   - auth:    the WinRM listener answered but rejected the credentials
+//     (HTTP 401) or the TLS certificate did not verify. A single rejection
+//     can be transient (a domain host still rejoining after a reboot, a
+//     listener restarting after an address change), so the failure is only
+//     latched after authLatchThreshold rejections in a row. While latched,
+//     runs fail fast with the same error rather than re-sending bad
+//     credentials and risking an account lockout; once authLatchDuration has
+//     passed, one run is let through to try again. Any run that gets past
+//     authentication clears the latch. An SSH bastion that rejects its
+//     credentials or presents the wrong host key counts the same way.
+//   - command: the shell was reached but the run failed (non-zero exit code,
+//     SOAP fault, connection lost mid-command). Counted, never latched.
+//
+// Context cancellation is counted separately and is never classified.
+//
+// With an SSH bastion, a run that fails at the transport level or is cut
+// short also probes the bastion session, and a session that no longer
+// answers is dropped (see bastion.go). IsConnected runs the same probe on
+// demand and then checks that the WinRM listener accepts a connection.
+package winclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+)
+
+// FailureKind classifies a transport failure.
+type FailureKind string
+
+const (
+	// FailureDial means the host could not be reached.
+	FailureDial FailureKind = "dial"
+	// FailureAuth means the host rejected the credentials or the TLS handshake.
+	FailureAuth FailureKind = "auth"
+	// FailureCommand means the host was reached but the command failed.
+	FailureCommand FailureKind = "command"
+)
+
+// Back-off bounds applied after a dial failure. Tests may shorten them.
+var (
+	dialBackoffMin = 1 * time.Second
+	dialBackoffMax = 30 * time.Second
+)
+
+// authLatchThreshold is the number of authentication failures in a row
+// after which runs fail fast; authLatchDuration is how long they do before
+// one run is let through again. Tests may change them.
+var (
+	authLatchThreshold = 3
+	authLatchDuration  = 1 * time.Minute
+)
+
+// TransportError is returned by RunPowerShell and RunPowerShellWithInput
+// when the run fails for a reason other than context cancellation. Error()
+// is the underlying message unchanged.
+type TransportError struct {
+	Kind FailureKind
+	Err  error
+	// ExitCode is the remote process or script exit code when the run
+	// failed because of it, otherwise 0.
+	ExitCode int
+	// Latched is set on an authentication failure that made the Client
+	// refuse further runs for a while (see beforeRun).
+	Latched bool
+}
+
+// Error implements error.
+func (e *TransportError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// TransportFailureKind returns the FailureKind of err, or "" when err is nil
+// or not a *TransportError.
+func TransportFailureKind(err error) FailureKind {
+	var te *TransportError
+	if errors.As(err, &te) {
+		return te.Kind
+	}
+	return ""
+}
+
+// AuthFailureLatched reports whether err is an authentication failure the
+// Client has latched: retrying now would fail fast, so callers polling a
+// host should give up. A single rejected run is not latched.
+func AuthFailureLatched(err error) bool {
+	var te *TransportError
+	return errors.As(err, &te) && te.Kind == FailureAuth && te.Latched
+}
+
+// CommandExitCode returns the exit code carried by a *TransportError in
+// err's chain, or 0 when there is none.
+func CommandExitCode(err error) int {
+	var te *TransportError
+	if errors.As(err, &te) {
+		return te.ExitCode
+	}
+	return 0
+}
+
+// ConnectionStats is a snapshot of the run outcomes recorded by a Client.
+type ConnectionStats struct {
+	// Runs counts every RunPowerShell / RunPowerShellWithInput attempt that
+	// reached the transport (fail-fast auth rejections are not counted).
+	Runs uint64
+	// DialFailures, AuthFailures and CommandFailures count failed runs by
+	// FailureKind.
+	DialFailures    uint64
+	AuthFailures    uint64
+	CommandFailures uint64
+	// Cancelled counts runs abandoned because their context ended.
+	Cancelled uint64
+	// LastFailureKind and LastFailure describe the most recent classified
+	// failure. Empty when none has occurred.
+	LastFailureKind FailureKind
+	LastFailure     string
+}
+
+// ConnectionStats returns a snapshot of the client's run outcomes.
+func (c *Client) ConnectionStats() ConnectionStats {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	return c.stats
+}
+
+// httpAuthStatusRe matches the status-code errors masterzen/winrm returns for
+// a rejected request: "http error 401: ..." or "http response error: 401 - ...".
+var httpAuthStatusRe = regexp.MustCompile(`http (response )?error:? 401\b`)
+
+// classifyTransportError maps an error returned by the WinRM library to a
+// FailureKind.
+func classifyTransportError(err error) FailureKind {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &dnsErr):
+		return FailureDial
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return FailureDial
+	}
+
+	var bastionAuth *bastionAuthError
+	if errors.As(err, &bastionAuth) {
+		return FailureAuth
+	}
+
+	var unknownCA x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var verifyErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &unknownCA), errors.As(err, &hostErr), errors.As(err, &invalidCert),
+		errors.As(err, &verifyErr):
+		return FailureAuth
+	case httpAuthStatusRe.MatchString(err.Error()):
+		return FailureAuth
+	}
+	return FailureCommand
+}
+
+// beforeRun fails fast while an authentication failure is latched and
+// waits out any dial back-off window, honouring ctx. Once the latch window
+// has passed, one run is let through and the window restarts for the
+// others.
+func (c *Client) beforeRun(ctx context.Context) error {
+	c.healthMu.Lock()
+	authErr, wait := c.authErr, time.Until(c.dialRetryAt)
+	if authErr != nil {
+		now := time.Now()
+		if now.Before(c.authRetryAt) {
+			c.healthMu.Unlock()
+			return authErr
+		}
+		c.authRetryAt = now.Add(authLatchDuration)
+	}
+	c.healthMu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// recordRun updates the statistics for one finished run and returns the
+// error the caller should see: nil on success, the raw runErr when ctx
+// ended, otherwise a *TransportError.
+func (c *Client) recordRun(ctx context.Context, runErr error, code int) error {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.stats.Runs++
+
+	if ctx.Err() != nil {
+		c.stats.Cancelled++
+		if runErr != nil {
+			return fmt.Errorf("winclient: powershell run: %w", runErr)
+		}
+		return nil
+	}
+
+	var te *TransportError
+	switch {
+	case runErr != nil:
+		te = &TransportError{Kind: classifyTransportError(runErr), Err: fmt.Errorf("winclient: powershell run: %w", runErr)}
+	case code != 0:
+		te = &TransportError{Kind: FailureCommand, Err: fmt.Errorf("winclient: powershell exited with code %d", code), ExitCode: code}
+	default:
+		c.dialBackoff, c.dialRetryAt = 0, time.Time{}
+		c.clearAuthLatch()
+		return nil
+	}
+
+	switch te.Kind {
+	case FailureDial:
+		c.stats.DialFailures++
+		c.dialBackoff *= 2
+		if c.dialBackoff < dialBackoffMin {
+			c.dialBackoff = dialBackoffMin
+		}
+		if c.dialBackoff > dialBackoffMax {
+			c.dialBackoff = dialBackoffMax
+		}
+		c.dialRetryAt = time.Now().Add(c.dialBackoff)
+	case FailureAuth:
+		c.stats.AuthFailures++
+		c.authFailures++
+		if c.authFailures >= authLatchThreshold {
+			te.Latched = true
+			c.authErr, c.authRetryAt = te, time.Now().Add(authLatchDuration)
+		}
+	default:
+		c.stats.CommandFailures++
+		// The host answered and accepted the credentials, so it is
+		// reachable again.
+		c.dialBackoff, c.dialRetryAt = 0, time.Time{}
+		c.clearAuthLatch()
+	}
+	c.stats.LastFailureKind, c.stats.LastFailure = te.Kind, te.Error()
+	return te
+}
+
+// clearAuthLatch forgets earlier authentication failures after a run got
+// past authentication. healthMu must be held.
+func (c *Client) clearAuthLatch() {
+	c.authFailures, c.authErr, c.authRetryAt = 0, nil, time.Time{}
+}
+
+// checkBastionSession probes the bastion session after a failed run so a
+// dead one is dropped before the next run re-uses one of its channels. A
+// no-op without a bastion.
+func (c *Client) checkBastionSession() {
+	if c.bastion != nil {
+		c.bastion.checkSession()
+	}
+}
+
+// IsConnected reports whether a run could reach the host now: a TCP
+// connection to the WinRM listener opens within ctx and Config.Timeout,
+// through the bastion when one is configured. A bastion session that does
+// not answer is dropped first, along with every pooled connection on it,
+// and a fresh one is opened. It runs no command and does not touch the
+// connection statistics or the dial back-off.
+func (c *Client) IsConnected(ctx context.Context) bool {
+	if c == nil || c.winrm == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	addr := dialAddress(c.cfg.Host, c.cfg.Port)
+	dial := (&net.Dialer{}).DialContext
+	if c.bastion != nil {
+		c.bastion.checkSession()
+		dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialWithContext(ctx, func() (net.Conn, error) { return c.bastion.Dial(network, addr) })
+		}
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// dialWithContext runs dial, returning early when ctx ends. A connection
+// that arrives after that is closed.
+func dialWithContext(ctx context.Context, dial func() (net.Conn, error)) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	res := make(chan result, 1)
+	go func() {
+		conn, err := dial()
+		res <- result{conn, err}
+	}()
+	select {
+	case r := <-res:
+		return r.conn, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-res; r.conn != nil {
+				_ = r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
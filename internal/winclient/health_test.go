@@ -0,0 +1,194 @@
+// Package winclient — unit tests for transport failure classification,
+// the auth failure latch and the dial back-off.
+package winclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func newHealthTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := New(Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestClassifyTransportError(t *testing.T) {
+	dial := &url.Error{Op: "Post", URL: "http://win01:5985/wsman", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused"),
+	}}
+	cases := []struct {
+		name string
+		err  error
+		want FailureKind
+	}{
+		{"dial refused", fmt.Errorf("unknown error %w", dial), FailureDial},
+		{"dns", fmt.Errorf("unknown error %w", &net.DNSError{Err: "no such host", Name: "win01"}), FailureDial},
+		{"ntlm 401", errors.New("http response error: 401 - invalid content type"), FailureAuth},
+		{"basic 401", errors.New("http error 401: "), FailureAuth},
+		{"tls unknown CA", fmt.Errorf("unknown error %w", x509.UnknownAuthorityError{}), FailureAuth},
+		{"tls record header is not auth", fmt.Errorf("unknown error %w", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}), FailureCommand},
+		{"read reset mid-command", fmt.Errorf("unknown error %w", &net.OpError{Op: "read", Err: errors.New("connection reset")}), FailureCommand},
+		{"soap fault", errors.New("http error 500: <s:Fault>"), FailureCommand},
+		{"4010 is not 401", errors.New("http error 4010: x"), FailureCommand},
+	}
+	for _, tc := range cases {
+		if got := classifyTransportError(tc.err); got != tc.want {
+			t.Errorf("%s: kind = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRecordRun_CountsAndWraps(t *testing.T) {
+	c := newHealthTestClient(t)
+	ctx := context.Background()
+
+	if err := c.recordRun(ctx, nil, 0); err != nil {
+		t.Fatalf("success must return nil, got %v", err)
+	}
+	err := c.recordRun(ctx, nil, 1)
+	if TransportFailureKind(err) != FailureCommand || err.Error() != "winclient: powershell exited with code 1" {
+		t.Errorf("exit code: err = %v (kind %q)", err, TransportFailureKind(err))
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.recordRun(cctx, context.Canceled, 1); TransportFailureKind(err) != "" || !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled run must not be classified: %v", err)
+	}
+
+	st := c.ConnectionStats()
+	if st.Runs != 3 || st.CommandFailures != 1 || st.Cancelled != 1 || st.LastFailureKind != FailureCommand {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestAuthFailureIsLatched(t *testing.T) {
+	c := newHealthTestClient(t)
+	var last error
+	for i := 1; i <= authLatchThreshold; i++ {
+		last = c.recordRun(context.Background(), errors.New("http error 401: "), 0)
+		if TransportFailureKind(last) != FailureAuth {
+			t.Fatalf("kind = %q", TransportFailureKind(last))
+		}
+		if latched := AuthFailureLatched(last); latched != (i == authLatchThreshold) {
+			t.Fatalf("failure %d: latched = %v", i, latched)
+		}
+		if i < authLatchThreshold {
+			if err := c.beforeRun(context.Background()); err != nil {
+				t.Fatalf("failure %d must not latch yet, beforeRun = %v", i, err)
+			}
+		}
+	}
+	if err := c.beforeRun(context.Background()); err != last {
+		t.Errorf("beforeRun must fail fast with the latched error, got %v", err)
+	}
+	// RunPowerShell itself must not reach the transport.
+	if _, _, err := c.RunPowerShell(context.Background(), "Get-Date"); !AuthFailureLatched(err) {
+		t.Errorf("RunPowerShell after auth failure = %v", err)
+	}
+	if st := c.ConnectionStats(); st.Runs != uint64(authLatchThreshold) || st.AuthFailures != uint64(authLatchThreshold) {
+		t.Errorf("fail-fast rejections must not count as runs: %+v", st)
+	}
+}
+
+func TestAuthFailureLatch_ResetByAuthenticatedRun(t *testing.T) {
+	for name, outcome := range map[string]struct {
+		err  error
+		code int
+	}{
+		"success":         {nil, 0},
+		"command failure": {nil, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := newHealthTestClient(t)
+			for i := 1; i < authLatchThreshold; i++ {
+				c.recordRun(context.Background(), errors.New("http error 401: "), 0)
+			}
+			c.recordRun(context.Background(), outcome.err, outcome.code)
+			// The count starts over: one more rejection must not latch.
+			if err := c.recordRun(context.Background(), errors.New("http error 401: "), 0); AuthFailureLatched(err) {
+				t.Errorf("rejection after an authenticated run latched: %v", err)
+			}
+			if err := c.beforeRun(context.Background()); err != nil {
+				t.Errorf("beforeRun = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestAuthFailureLatch_Expires(t *testing.T) {
+	prev := authLatchDuration
+	authLatchDuration = 20 * time.Millisecond
+	defer func() { authLatchDuration = prev }()
+
+	c := newHealthTestClient(t)
+	for i := 0; i < authLatchThreshold; i++ {
+		c.recordRun(context.Background(), errors.New("http error 401: "), 0)
+	}
+	if err := c.beforeRun(context.Background()); !AuthFailureLatched(err) {
+		t.Fatalf("expected the latch, got %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := c.beforeRun(context.Background()); err != nil {
+		t.Fatalf("one run must be let through once the latch expires, got %v", err)
+	}
+	if err := c.beforeRun(context.Background()); !AuthFailureLatched(err) {
+		t.Errorf("only one run may be let through per window, got %v", err)
+	}
+	if err := c.recordRun(context.Background(), nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.beforeRun(context.Background()); err != nil {
+		t.Errorf("a successful run must clear the latch, got %v", err)
+	}
+}
+
+func TestDialFailureBacksOff(t *testing.T) {
+	prevMin, prevMax := dialBackoffMin, dialBackoffMax
+	dialBackoffMin, dialBackoffMax = 20*time.Millisecond, 40*time.Millisecond
+	defer func() { dialBackoffMin, dialBackoffMax = prevMin, prevMax }()
+
+	c := newHealthTestClient(t)
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("connect: connection refused")}
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond} {
+		if TransportFailureKind(c.recordRun(context.Background(), dialErr, 0)) != FailureDial {
+			t.Fatal("expected dial kind")
+		}
+		if c.dialBackoff != want {
+			t.Errorf("attempt %d: backoff = %v, want %v", i, c.dialBackoff, want)
+		}
+	}
+
+	start := time.Now()
+	if err := c.beforeRun(context.Background()); err != nil {
+		t.Fatalf("beforeRun: %v", err)
+	}
+	if waited := time.Since(start); waited < 10*time.Millisecond {
+		t.Errorf("beforeRun should wait out the back-off, waited %v", waited)
+	}
+
+	c.recordRun(context.Background(), dialErr, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.beforeRun(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("beforeRun must honour ctx, got %v", err)
+	}
+
+	if err := c.recordRun(context.Background(), nil, 0); err != nil || c.dialBackoff != 0 || !c.dialRetryAt.IsZero() {
+		t.Errorf("success must reset the back-off: %v %v", c.dialBackoff, c.dialRetryAt)
+	}
+	if st := c.ConnectionStats(); st.DialFailures != 4 {
+		t.Errorf("DialFailures = %d, want 4", st.DialFailures)
+	}
+}
@@ -89,6 +89,9 @@ type hostnameStatePayload struct {
 	RebootPending bool   `json:"reboot_pending"`
 	PartOfDomain  bool   `json:"part_of_domain"`
 	Domain        string `json:"domain"`
+	Workgroup     string `json:"workgroup"`
+	DNSSuffix     string `json:"dns_suffix"`
+	FQDN          string `json:"fqdn"`
 }
 
 // psHostnameHeader prepends Emit-OK/Emit-Err and Classify-Hostname.
@@ -122,13 +125,33 @@ function Read-HostnameState {
   $pend = (Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ComputerName'       -Name ComputerName -ErrorAction Stop).ComputerName
   $guid = (Get-ItemProperty -Path 'HKLM:\SOFTWARE\Microsoft\Cryptography'                                  -Name MachineGuid  -ErrorAction Stop).MachineGuid
   $rp   = ($act.ToLowerInvariant() -ne $pend.ToLowerInvariant())
+  # Rename-Computer also queues the DNS host name: Tcpip 'NV Hostname' holds
+  # the next value until a reboot copies it into 'Hostname'.
+  $tcp = Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters' -ErrorAction SilentlyContinue
+  if ($tcp -and $tcp.Hostname -and $tcp.'NV Hostname' -and ([string]$tcp.Hostname).ToLowerInvariant() -ne ([string]$tcp.'NV Hostname').ToLowerInvariant()) { $rp = $true }
+  # Win32_ComputerSystem.Domain holds the workgroup name on a workgroup host.
+  $dom = ''; $wg = ''
+  if ($cs.PartOfDomain) { $dom = [string]$cs.Domain } else { $wg = [string]$cs.Workgroup; if (-not $wg) { $wg = [string]$cs.Domain } }
+  # Primary DNS suffix; empty on a workgroup host without one.
+  $sfx = ''
+  if ($tcp) { $sfx = [string]$tcp.Domain }
+  # FQDN from the resolver; fall back to name + suffix when DNS has no entry.
+  $fqdn = ''
+  try { $fqdn = [string][System.Net.Dns]::GetHostEntry([string]$act).HostName } catch { }
+  if (-not $fqdn -or $fqdn -notmatch '\.') {
+    $fqdn = [string]$act
+    if ($sfx) { $fqdn = $fqdn + '.' + $sfx }
+  }
   return [ordered]@{
     machine_id     = [string]$guid
     current_name   = [string]$act
     pending_name   = [string]$pend
     reboot_pending = [bool]$rp
     part_of_domain = [bool]$cs.PartOfDomain
-    domain         = [string]$cs.Domain
+    domain         = $dom
+    workgroup      = $wg
+    dns_suffix     = $sfx
+    fqdn           = $fqdn.ToLowerInvariant()
   }
 }
 `
@@ -137,6 +160,7 @@ function Read-HostnameState {
 // parses the JSON envelope. Transport / cancellation errors that bypass
 // Emit-Err are mapped to HostnameErrorUnreachable / HostnameErrorUnknown.
 func (h *HostnameClient) runHostnameEnvelope(ctx context.Context, op, script string, baseCtx map[string]string) (*hostnamePSResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := psHostnameHeader + "\n" + script
 	stdout, stderr, err := runHostnamePowerShell(ctx, h.c, full)
 
@@ -219,6 +243,9 @@ func payloadToState(p hostnameStatePayload) *HostnameState {
 		RebootPending: p.RebootPending,
 		PartOfDomain:  p.PartOfDomain,
 		Domain:        p.Domain,
+		Workgroup:     p.Workgroup,
+		DNSSuffix:     p.DNSSuffix,
+		FQDN:          p.FQDN,
 	}
 }
 
@@ -305,6 +305,32 @@ func TestHostnameRead_RebootPending_EC3(t *testing.T) {
 	}
 }
 
+// TestHostnameRead_RebootPendingDNSHostname checks that the read script
+// also consults the Tcpip Hostname / NV Hostname pair, which Rename-Computer
+// updates even when the NetBIOS names already agree.
+func TestHostnameRead_RebootPendingDNSHostname(t *testing.T) {
+	var script string
+	restore := stubHnRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		st := hnState("WIN01", "WIN01", "abc-123", false, "")
+		st["reboot_pending"] = true
+		return hnOK(t, st), "", nil
+	})
+	defer restore()
+	st, err := NewHostnameClient(newHnTestClient(t)).Read(context.Background(), "abc-123")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !st.RebootPending {
+		t.Error("reboot_pending reported by the host must be kept when current == pending")
+	}
+	for _, want := range []string{`$tcp.'NV Hostname'`, `([string]$tcp.Hostname).ToLowerInvariant()`, "{ $rp = $true }"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("read script missing %q", want)
+		}
+	}
+}
+
 func TestHostnameRead_MachineMismatch_EC10(t *testing.T) {
 	restore := stubHnRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
 		return hnOK(t, hnState("WIN01", "WIN01", "different-guid", false, "")), "", nil
@@ -525,3 +551,28 @@ func TestPayloadToState(t *testing.T) {
 		t.Errorf("payloadToState mismatch: %+v", s)
 	}
 }
+
+func TestHostnameRead_Identity(t *testing.T) {
+	var script string
+	restore := stubHnRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		st := hnState("WIN01", "WIN01", "abc-123", false, "")
+		st["workgroup"] = "WORKGROUP"
+		st["dns_suffix"] = "lab.example"
+		st["fqdn"] = "win01.lab.example"
+		return hnOK(t, st), "", nil
+	})
+	defer restore()
+	st, err := NewHostnameClient(newHnTestClient(t)).Read(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if st.FQDN != "win01.lab.example" || st.DNSSuffix != "lab.example" || st.Workgroup != "WORKGROUP" || st.Domain != "" {
+		t.Errorf("unexpected identity: %+v", st)
+	}
+	for _, want := range []string{"[System.Net.Dns]::GetHostEntry", `Tcpip\Parameters`, "if ($cs.PartOfDomain) { $dom"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("read script missing %q", want)
+		}
+	}
+}
@@ -100,8 +100,11 @@ func (e *HostnameError) Error() string {
 // Unwrap returns the underlying cause for errors.As / errors.Is chain walking.
 func (e *HostnameError) Unwrap() error { return e.Cause }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 func (e *HostnameError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*HostnameError)
 	if !ok {
 		return false
@@ -183,8 +186,9 @@ type HostnameState struct {
 	// is pending.
 	PendingName string
 
-	// RebootPending is true when PendingName != CurrentName
-	// (case-insensitive comparison done by the client).
+	// RebootPending is true when PendingName != CurrentName, or when the
+	// Tcpip "NV Hostname" (DNS host name for the next boot) differs from
+	// the active "Hostname". Both comparisons are case-insensitive.
 	RebootPending bool
 
 	// PartOfDomain is true when the machine is domain-joined.  v1
@@ -195,6 +199,19 @@ type HostnameState struct {
 	// Domain is the AD domain name when PartOfDomain == true; empty
 	// otherwise.  Surfaced in EC-5 diagnostics.
 	Domain string
+
+	// Workgroup is the workgroup name when PartOfDomain == false; empty
+	// otherwise.
+	Workgroup string
+
+	// DNSSuffix is the primary DNS suffix (Tcpip\Parameters Domain).
+	// Empty on a workgroup host that has none.
+	DNSSuffix string
+
+	// FQDN is the lower-cased fully qualified name resolved with
+	// [System.Net.Dns]::GetHostEntry, falling back to CurrentName plus
+	// DNSSuffix (or CurrentName alone) when DNS has no dotted entry.
+	FQDN string
 }
 
 // ---------------------------------------------------------------------------
@@ -0,0 +1,207 @@
+// Package winclient: installed hotfix enumeration over WinRM.
+//
+// HotfixClient is the concrete WindowsHotfixClient backing the windows_hotfix
+// data source. Get-HotFix (Win32_QuickFixEngineering) is serialised to JSON
+// and the single-result case is normalised through jsonList.
+//
+// Security invariants:
+//   - The hotfix ID is passed through psQuote; the data source additionally
+//     validates it against ^KB\d+$.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Compile-time assertion: HotfixClient satisfies WindowsHotfixClient.
+var _ WindowsHotfixClient = (*HotfixClient)(nil)
+
+// HotfixClient is the PowerShell/WinRM-backed WindowsHotfixClient.
+type HotfixClient struct {
+	c *Client
+}
+
+// NewHotfixClient wraps the given WinRM Client.
+func NewHotfixClient(c *Client) *HotfixClient { return &HotfixClient{c: c} }
+
+// runHotfixPowerShell is the package-level indirection used by HotfixClient.
+// Tests may override it; production code must not.
+var runHotfixPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// hotfixPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type hotfixPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// hotfixPayload is one entry of the "hotfixes" array emitted by
+// psListHotfixes.
+type hotfixPayload struct {
+	HotfixID    string `json:"hotfix_id"`
+	Description string `json:"description"`
+	InstalledOn string `json:"installed_on"`
+	InstalledBy string `json:"installed_by"`
+}
+
+// hotfixListPayload wraps the hotfixes array. ConvertTo-Json unrolls a
+// one-element array into a bare object, hence jsonList.
+type hotfixListPayload struct {
+	Hotfixes jsonList[hotfixPayload] `json:"hotfixes"`
+}
+
+// psHotfixHeader prepends Emit-OK/Emit-Err and Classify-Hotfix.
+const psHotfixHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Classify-Hotfix([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied') { return 'permission_denied' }
+  return 'unknown'
+}
+`
+
+// psListHotfixes lists Get-HotFix, optionally filtered by -Id (%s, already
+// quoted, or $null). Get-HotFix -Id fails with GetHotFixNoEntriesFound when
+// the update is absent; that is reported as an empty list. InstalledOn is a
+// date without a meaningful time of day and is emitted as yyyy-MM-dd.
+const psListHotfixes = `
+try {
+  $id = %s
+  $rows = @()
+  try {
+    if ($id) { $items = @(Get-HotFix -Id $id -ErrorAction Stop) } else { $items = @(Get-HotFix -ErrorAction Stop) }
+  } catch {
+    if ([string]$_.FullyQualifiedErrorId -match 'GetHotFixNoEntriesFound') { $items = @() } else { throw }
+  }
+  foreach ($h in $items) {
+    $on = ''
+    if ($h.InstalledOn) { $on = $h.InstalledOn.ToString('yyyy-MM-dd', [Globalization.CultureInfo]::InvariantCulture) }
+    $rows += [ordered]@{
+      hotfix_id    = [string]$h.HotFixID
+      description  = [string]$h.Description
+      installed_on = $on
+      installed_by = [string]$h.InstalledBy
+    }
+  }
+  Emit-OK ([ordered]@{ hotfixes = @($rows) })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Hotfix $msg) $msg @{}
+}
+`
+
+// runHotfixEnvelope executes script (prepended with psHotfixHeader) and parses
+// the JSON envelope. Cancellation maps to HotfixErrorTimeout; other transport
+// failures to HotfixErrorUnknown.
+func (h *HotfixClient) runHotfixEnvelope(ctx context.Context, op, script string) (*hotfixPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psHotfixHeader + "\n" + script
+	stdout, stderr, err := runHotfixPowerShell(ctx, h.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      h.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewHotfixError(HotfixErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewHotfixError(HotfixErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, withExitCode(baseCtx, err))
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewHotfixError(HotfixErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp hotfixPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewHotfixError(HotfixErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewHotfixError(mapHotfixKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapHotfixKind translates a PS-side "kind" string to a typed
+// HotfixErrorKind. Unknown values fall through to HotfixErrorUnknown.
+func mapHotfixKind(k string) HotfixErrorKind {
+	switch k {
+	case string(HotfixErrorPermission),
+		string(HotfixErrorTimeout):
+		return HotfixErrorKind(k)
+	default:
+		return HotfixErrorUnknown
+	}
+}
+
+// List implements WindowsHotfixClient.List.
+func (h *HotfixClient) List(ctx context.Context, hotfixID string) ([]Hotfix, error) {
+	idArg := "$null"
+	if hotfixID != "" {
+		idArg = psQuote(hotfixID)
+	}
+	resp, err := h.runHotfixEnvelope(ctx, "list", fmt.Sprintf(psListHotfixes, idArg))
+	if err != nil {
+		return nil, err
+	}
+	var p hotfixListPayload
+	if jerr := json.Unmarshal(resp.Data, &p); jerr != nil {
+		return nil, NewHotfixError(HotfixErrorUnknown,
+			"failed to parse hotfix list", jerr,
+			map[string]string{"host": h.c.cfg.Host})
+	}
+	out := make([]Hotfix, 0, len(p.Hotfixes))
+	for _, e := range p.Hotfixes {
+		out = append(out, Hotfix{
+			HotfixID:    e.HotfixID,
+			Description: e.Description,
+			InstalledOn: e.InstalledOn,
+			InstalledBy: e.InstalledBy,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return strings.ToUpper(out[i].HotfixID) < strings.ToUpper(out[j].HotfixID)
+	})
+	return out, nil
+}
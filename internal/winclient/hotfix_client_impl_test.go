@@ -0,0 +1,104 @@
+// Package winclient — unit tests for HotfixClient.
+//
+// These tests stub the package-level seam runHotfixPowerShell to inject
+// scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubHotfixRun replaces runHotfixPowerShell for the duration of a test and
+// returns a restore function (typically deferred).
+func stubHotfixRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runHotfixPowerShell
+	runHotfixPowerShell = fn
+	return func() { runHotfixPowerShell = prev }
+}
+
+func TestHotfixList_All(t *testing.T) {
+	var script string
+	defer stubHotfixRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return okEnvelope(t, map[string]any{"hotfixes": []map[string]any{
+			{"hotfix_id": "KB5034439", "description": "Update", "installed_on": "", "installed_by": ""},
+			{"hotfix_id": "KB5034123", "description": "Security Update", "installed_on": "2026-01-09", "installed_by": `NT AUTHORITY\SYSTEM`},
+		}}), "", nil
+	})()
+
+	got, err := NewHotfixClient(newTestClient(t)).List(context.Background(), "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !strings.Contains(script, "$id = $null") {
+		t.Errorf("unfiltered list must pass $null, script:\n%s", script)
+	}
+	want := []Hotfix{
+		{HotfixID: "KB5034123", Description: "Security Update", InstalledOn: "2026-01-09", InstalledBy: `NT AUTHORITY\SYSTEM`},
+		{HotfixID: "KB5034439", Description: "Update"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("List = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("List[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHotfixList_SingleResultNormalised(t *testing.T) {
+	var script string
+	defer stubHotfixRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		// ConvertTo-Json unrolls a one-element array into a bare object.
+		return okEnvelope(t, map[string]any{"hotfixes": map[string]any{
+			"hotfix_id": "KB5034123", "description": "Security Update", "installed_on": "2026-01-09", "installed_by": "",
+		}}), "", nil
+	})()
+
+	got, err := NewHotfixClient(newTestClient(t)).List(context.Background(), "KB5034123")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !strings.Contains(script, "$id = 'KB5034123'") {
+		t.Errorf("hotfix ID not quoted into script:\n%s", script)
+	}
+	if len(got) != 1 || got[0].HotfixID != "KB5034123" {
+		t.Errorf("List = %+v", got)
+	}
+}
+
+func TestHotfixList_NotInstalled(t *testing.T) {
+	defer stubHotfixRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return okEnvelope(t, map[string]any{"hotfixes": []any{}}), "", nil
+	})()
+
+	got, err := NewHotfixClient(newTestClient(t)).List(context.Background(), "KB1")
+	if err != nil || len(got) != 0 {
+		t.Errorf("List = %+v, %v; want empty, nil", got, err)
+	}
+}
+
+func TestHotfixList_Errors(t *testing.T) {
+	defer stubHotfixRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return errEnvelope(t, "permission_denied", "Access is denied."), "", nil
+	})()
+	_, err := NewHotfixClient(newTestClient(t)).List(context.Background(), "")
+	if !errors.Is(err, ErrHotfixPermission) {
+		t.Errorf("err = %v, want permission_denied", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer stubHotfixRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.Canceled
+	})()
+	_, err = NewHotfixClient(newTestClient(t)).List(ctx, "")
+	if !IsHotfixError(err, HotfixErrorTimeout) {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Package winclient: types for the windows_hotfix data source.
+//
+// Hotfix is one entry of Win32_QuickFixEngineering as reported by
+// Get-HotFix. HotfixErrorKind / HotfixError follow the same shape as
+// SystemInfoError.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// HotfixErrorKind categorises errors returned by WindowsHotfixClient.
+type HotfixErrorKind string
+
+const (
+	HotfixErrorPermission HotfixErrorKind = "permission_denied"
+	HotfixErrorTimeout    HotfixErrorKind = "timeout"
+	HotfixErrorUnknown    HotfixErrorKind = "unknown"
+)
+
+// HotfixError is the structured error type returned by WindowsHotfixClient.
+type HotfixError struct {
+	Kind    HotfixErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *HotfixError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_hotfix [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_hotfix [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *HotfixError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *HotfixError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*HotfixError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewHotfixError constructs a *HotfixError.
+func NewHotfixError(kind HotfixErrorKind, msg string, cause error, ctx map[string]string) *HotfixError {
+	return &HotfixError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsHotfixError reports whether err is a *HotfixError of the given kind.
+func IsHotfixError(err error, kind HotfixErrorKind) bool {
+	var he *HotfixError
+	if errors.As(err, &he) {
+		return he.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrHotfixPermission = &HotfixError{Kind: HotfixErrorPermission}
+	ErrHotfixTimeout    = &HotfixError{Kind: HotfixErrorTimeout}
+	ErrHotfixUnknown    = &HotfixError{Kind: HotfixErrorUnknown}
+)
+
+// Hotfix is one update reported by Get-HotFix (Win32_QuickFixEngineering).
+type Hotfix struct {
+	// HotfixID is the KB identifier, e.g. "KB5034123".
+	HotfixID string
+	// Description is the update category, e.g. "Security Update".
+	Description string
+	// InstalledOn is the installation date as YYYY-MM-DD; "" when the host
+	// does not record one.
+	InstalledOn string
+	// InstalledBy is the account that installed the update; often empty or
+	// "NT AUTHORITY\SYSTEM".
+	InstalledBy string
+}
+
+// WindowsHotfixClient is the contract for the windows_hotfix data source.
+type WindowsHotfixClient interface {
+	// List returns the installed hotfixes sorted by HotfixID. When hotfixID
+	// is non-empty only that hotfix is returned; an update that is not
+	// installed yields an empty slice, not an error.
+	List(ctx context.Context, hotfixID string) ([]Hotfix, error)
+}
@@ -174,6 +174,13 @@ func (e *LegacyPackageError) Error() string {
 // Unwrap returns the underlying cause.
 func (e *LegacyPackageError) Unwrap() error { return e.Cause }
 
+// Is matches by ErrorClass (see errors.go); kinds are compared with
+// IsLegacyPackageError.
+func (e *LegacyPackageError) Is(target error) bool {
+	c, ok := target.(*ErrorClass)
+	return ok && c.matchesKind(e.Kind, e)
+}
+
 // IsLegacyPackageError reports whether err is a *LegacyPackageError with the
 // given kind.
 func IsLegacyPackageError(err error, kind string) bool {
@@ -200,6 +207,17 @@ type readPayload struct {
 	ID string `json:"id"`
 }
 
+// lpStagingRoot is the %TEMP%-relative directory holding per-run download
+// staging directories.
+const lpStagingRoot = "windows_legacy_package"
+
+// lpCreatePayload is the stdin JSON payload consumed by the create script.
+// StagingDir is the %TEMP%-relative directory source_url downloads go to.
+type lpCreatePayload struct {
+	LegacyPackageInput
+	StagingDir string `json:"staging_dir,omitempty"`
+}
+
 // deletePayload is the stdin JSON payload consumed by the delete script.
 type deletePayload struct {
 	ID               string   `json:"id"`
@@ -213,6 +231,8 @@ type deletePayload struct {
 // encoded) and decodes the JSON envelope written to stdout by Emit-OK /
 // Emit-Err. Transport errors are wrapped as LegacyPackageError{Kind:"unknown"}.
 func (l *LegacyPackageClientImpl) runEnvelope(ctx context.Context, op string, payload any, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	l.c.sweepTempArtifacts(ctx)
 	stdin, err := json.Marshal(payload)
 	if err != nil {
 		return nil, &LegacyPackageError{
@@ -308,9 +328,23 @@ func parseLPState(resp *psResponse) (*LegacyPackageState, error) {
 
 // Create runs the installer end-to-end: source resolution, checksum
 // verification, exec, exit-code validation, and state read-back.
+//
+// A source_url download is staged in its own %TEMP% subdirectory which the
+// script removes on every exit path. If the script dies before getting there
+// (cancellation, timeout, transport loss) the directory is removed again on
+// a fresh context; see temp_artifacts.go.
 func (l *LegacyPackageClientImpl) Create(ctx context.Context, in LegacyPackageInput) (*LegacyPackageState, error) {
-	resp, err := l.runEnvelope(ctx, "Create", in, lpCreateBody)
+	payload := lpCreatePayload{LegacyPackageInput: in}
+	if in.SourceURL != "" {
+		payload.StagingDir = lpStagingRoot + `\dl_` + newTempToken()
+	}
+	resp, err := l.runEnvelope(ctx, "Create", payload, lpCreateScript)
 	if err != nil {
+		// A nil envelope means the script never reported back, so its
+		// finally block cannot be trusted to have run.
+		if resp == nil && payload.StagingDir != "" {
+			l.c.cleanupTempArtifact(ctx, payload.StagingDir)
+		}
 		return nil, err
 	}
 	return parseLPState(resp)
@@ -467,6 +501,8 @@ function Invoke-LpProcess {
 //  4. Build the argument list and execute under a timeout.
 //  5. Validate the exit code against valid_exit_codes (default [0,3010]).
 //  6. Read back the state from the Uninstall registry hives.
+//
+// It is always run wrapped in lpCreateScript.
 const lpCreateBody = `
 $cfg = Read-LpInput
 $installerType = [string]$cfg.installer_type
@@ -489,7 +525,9 @@ if ($cfg.source_path) {
     exit 0
   }
 } elseif ($cfg.source_url) {
-  $tmpDir = Join-Path $env:TEMP 'windows_legacy_package'
+  $stagingDir = [string]$cfg.staging_dir
+  if ([string]::IsNullOrEmpty($stagingDir)) { $stagingDir = 'windows_legacy_package\dl_' + [guid]::NewGuid().ToString('N') }
+  $tmpDir = Join-Path $env:TEMP $stagingDir
   New-Item -ItemType Directory -Force -Path $tmpDir | Out-Null
   try { $fname = [System.IO.Path]::GetFileName(([Uri]([string]$cfg.source_url)).AbsolutePath) } catch { $fname = '' }
   if ([string]::IsNullOrEmpty($fname)) { $fname = 'installer_' + [guid]::NewGuid().ToString() + '.bin' }
@@ -663,12 +701,7 @@ if ($valid -notcontains $exitCode) {
   exit 0
 }
 
-# 7) Cleanup downloaded source on success
-if ($downloaded -and (Test-Path -LiteralPath $installerPath)) {
-  Remove-Item -LiteralPath $installerPath -Force -ErrorAction SilentlyContinue
-}
-
-# 8) Read state from Uninstall hives
+# 7) Read state from Uninstall hives
 $searchId = ''
 $searchPattern = ''
 if ($installerType -eq 'msi') { $searchId = $productId } else { $searchPattern = [string]$cfg.display_name_pattern }
@@ -739,6 +772,15 @@ Emit-OK ([ordered]@{
 })
 `
 
+// lpCreateScript wraps lpCreateBody so a source_url download is removed on
+// every exit path, including the early `exit 0` after an Emit-Err (PowerShell
+// runs finally blocks on exit). The log directory is left in place.
+const lpCreateScript = "try {\n" + lpCreateBody + "\n} finally {\n" + `
+  if ($downloaded -and $tmpDir -and (Test-Path -LiteralPath $tmpDir)) {
+    Remove-Item -LiteralPath $tmpDir -Recurse -Force -ErrorAction SilentlyContinue
+  }
+` + "}\n"
+
 // lpDeleteBody uninstalls the package keyed by id.
 //
 // MSI path: msiexec /x <ProductCode> /qn /norestart [+ uninstall_args].
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -78,6 +79,49 @@ function Classify-LG([string]$Msg, [string]$FQEI) {
 }
 `
 
+// lgPsMembersFunc defines Emit-GroupWithMembers, which emits a LocalGroup
+// object together with its members as one envelope:
+//
+//	{"group": <LocalGroup>, "members": [{"SID":..,"Name":..,"PrincipalSource":..,"ObjectClass":..}]}
+//
+// Get-LocalGroupMember throws on groups holding an orphaned SID (a deleted
+// domain account); the Win32_GroupUser fallback mirrors Tier 2 of
+// LocalGroupMemberClient.List. If both fail the member list is empty rather
+// than failing the whole read (ADR-LGM-5).
+const lgPsMembersFunc = `
+function Get-LGMembers($Group) {
+  $sid = $Group.SID.Value
+  try {
+    return @(Get-LocalGroupMember -SID $sid -ErrorAction Stop | ForEach-Object {
+      [ordered]@{ SID = [string]$_.SID.Value; Name = [string]$_.Name; PrincipalSource = [string]$_.PrincipalSource; ObjectClass = [string]$_.ObjectClass }
+    })
+  } catch {}
+  try {
+    $grpWMI = @(Get-WmiObject -Class Win32_Group | Where-Object { $_.SID -eq $sid })
+    if ($grpWMI.Count -eq 0) { return @() }
+    $grpPath = $grpWMI[0].__PATH
+    $out = @()
+    foreach ($rel in @(Get-WmiObject -Class Win32_GroupUser | Where-Object { $_.GroupComponent -eq $grpPath })) {
+      if ($rel.PartComponent -match "\.Domain='([^']+)',Name='([^']+)'") {
+        $dn = $Matches[1] + '\' + $Matches[2]
+        $msid = $dn
+        try {
+          $msid = (New-Object System.Security.Principal.NTAccount($Matches[1], $Matches[2])).Translate(
+                    [System.Security.Principal.SecurityIdentifier]).Value
+        } catch {}
+        $out += [ordered]@{ SID = $msid; Name = $dn; PrincipalSource = 'Unknown'; ObjectClass = 'Unknown' }
+      }
+    }
+    return $out
+  } catch {}
+  return @()
+}
+
+function Emit-GroupWithMembers($Group) {
+  Emit-OK ([ordered]@{ group = $Group; members = @(Get-LGMembers $Group) })
+}
+`
+
 // ---------------------------------------------------------------------------
 // psLocalGroup — JSON shape returned by Get-LocalGroup | ConvertTo-Json
 // ---------------------------------------------------------------------------
@@ -86,13 +130,16 @@ function Classify-LG([string]$Msg, [string]$FQEI) {
 // Get-LocalGroup | ConvertTo-Json -Depth 8 -Compress.
 // The SID property is a SecurityIdentifier object; at depth ≥ 2 it serialises
 // as {"BinaryLength":..., "AccountDomainSid":null, "Value":"S-1-5-21-..."}.
-// We extract only the fields we need.
+// We extract only the fields we need. PrincipalSource is a nullable enum that
+// Windows PowerShell 5.1 serialises as its integer value, so it is kept raw.
 type psLocalGroup struct {
 	Name        string `json:"Name"`
 	Description string `json:"Description"`
 	SID         struct {
 		Value string `json:"Value"`
 	} `json:"SID"`
+	PrincipalSource json.RawMessage `json:"PrincipalSource"`
+	ObjectClass     string          `json:"ObjectClass"`
 }
 
 // ---------------------------------------------------------------------------
@@ -132,6 +179,7 @@ func mapLGKind(k string) LocalGroupErrorKind {
 // cancellation) are translated into *LocalGroupError with
 // Kind=LocalGroupErrorUnknown.
 func (lc *LocalGroupClient) runLGEnvelope(ctx context.Context, op, key, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := lgPsHeader + "\n" + script
 	stdout, stderr, err := runPowerShell(ctx, lc.c, full)
 	if err != nil {
@@ -202,13 +250,78 @@ func parseGroupData(op string, data json.RawMessage) (*GroupState, error) {
 		return nil, NewLocalGroupError(LocalGroupErrorUnknown,
 			fmt.Sprintf("LocalGroup JSON from %q has empty SID.Value", op), nil, nil)
 	}
+	cls := g.ObjectClass
+	if cls == "" {
+		cls = "Group"
+	}
 	return &GroupState{
-		Name:        g.Name,
-		Description: g.Description,
-		SID:         g.SID.Value,
+		Name:            g.Name,
+		Description:     g.Description,
+		SID:             g.SID.Value,
+		PrincipalSource: normalizePrincipalSource(strings.Trim(string(g.PrincipalSource), `"`)),
+		ObjectClass:     cls,
 	}, nil
 }
 
+// psGroupWithMembers is the Emit-GroupWithMembers payload. Members is kept
+// raw because ConvertTo-Json may serialise a one-element array as a bare
+// object and an empty one as null.
+type psGroupWithMembers struct {
+	Group   json.RawMessage `json:"group"`
+	Members json.RawMessage `json:"members"`
+}
+
+// psGroupMember is one element of psGroupWithMembers.Members.
+type psGroupMember struct {
+	SID             string `json:"SID"`
+	Name            string `json:"Name"`
+	PrincipalSource string `json:"PrincipalSource"`
+	ObjectClass     string `json:"ObjectClass"`
+}
+
+// parseGroupWithMembers deserialises an Emit-GroupWithMembers payload into a
+// *GroupState with Members populated (never nil) and sorted by SID.
+func parseGroupWithMembers(op string, data json.RawMessage) (*GroupState, error) {
+	var env psGroupWithMembers
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, NewLocalGroupError(LocalGroupErrorUnknown,
+			fmt.Sprintf("failed to parse group-with-members JSON from %q", op), err, nil)
+	}
+	gs, err := parseGroupData(op, env.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []psGroupMember
+	if err := json.Unmarshal(normalizeJSONArray(env.Members), &raw); err != nil {
+		return nil, NewLocalGroupError(LocalGroupErrorUnknown,
+			fmt.Sprintf("failed to parse member list JSON from %q", op), err, nil)
+	}
+
+	gs.Members = make([]GroupMember, 0, len(raw))
+	for _, m := range raw {
+		if m.SID == "" {
+			continue
+		}
+		name := m.Name
+		if name == "" {
+			name = m.SID
+		}
+		cls := m.ObjectClass
+		if cls == "" {
+			cls = "Unknown"
+		}
+		gs.Members = append(gs.Members, GroupMember{
+			SID:             m.SID,
+			Name:            name,
+			PrincipalSource: normalizePrincipalSource(m.PrincipalSource),
+			ObjectClass:     cls,
+		})
+	}
+	sort.Slice(gs.Members, func(i, j int) bool { return gs.Members[i].SID < gs.Members[j].SID })
+	return gs, nil
+}
+
 // ---------------------------------------------------------------------------
 // Create — EC-1, module guard
 // ---------------------------------------------------------------------------
@@ -222,7 +335,7 @@ func (lc *LocalGroupClient) Create(ctx context.Context, input GroupInput) (*Grou
 	qName := psQuote(input.Name)
 	qDesc := psQuote(input.Description)
 
-	script := fmt.Sprintf(`
+	script := lgPsMembersFunc + fmt.Sprintf(`
 # Module guard (EC-9: requires Windows Server 2016 / Windows 10+)
 if (-not (Get-Command New-LocalGroup -ErrorAction SilentlyContinue)) {
     Emit-Err 'unknown' 'Microsoft.PowerShell.LocalAccounts module not available; requires Windows Server 2016 / Windows 10 or later' @{}
@@ -247,7 +360,7 @@ try {
 # Create the group
 try {
     $group = New-LocalGroup -Name %s -Description %s -ErrorAction Stop
-    Emit-OK $group
+    Emit-GroupWithMembers $group
 } catch {
     $kind = Classify-LG $_.Exception.Message $_.FullyQualifiedErrorId
     Emit-Err $kind $_.Exception.Message @{ name = %s; step = 'new_local_group' }
@@ -258,7 +371,7 @@ try {
 	if err != nil {
 		return nil, err
 	}
-	return parseGroupData("create", resp.Data)
+	return parseGroupWithMembers("create", resp.Data)
 }
 
 // ---------------------------------------------------------------------------
@@ -271,10 +384,10 @@ try {
 func (lc *LocalGroupClient) Read(ctx context.Context, sid string) (*GroupState, error) {
 	qSID := psQuote(sid)
 
-	script := fmt.Sprintf(`
+	script := lgPsMembersFunc + fmt.Sprintf(`
 try {
     $group = Get-LocalGroup -SID %s -ErrorAction Stop
-    Emit-OK $group
+    Emit-GroupWithMembers $group
 } catch {
     $kind = Classify-LG $_.Exception.Message $_.FullyQualifiedErrorId
     Emit-Err $kind $_.Exception.Message @{ sid = %s; step = 'get_local_group' }
@@ -289,7 +402,7 @@ try {
 		}
 		return nil, err
 	}
-	return parseGroupData("read", resp.Data)
+	return parseGroupWithMembers("read", resp.Data)
 }
 
 // ---------------------------------------------------------------------------
@@ -311,7 +424,7 @@ func (lc *LocalGroupClient) Update(ctx context.Context, sid string, input GroupI
 	qName := psQuote(input.Name)
 	qDesc := psQuote(input.Description)
 
-	script := fmt.Sprintf(`
+	script := lgPsMembersFunc + fmt.Sprintf(`
 try {
     # Read current state
     $current = Get-LocalGroup -SID %s -ErrorAction Stop
@@ -327,7 +440,7 @@ try {
 
     # Return refreshed state
     $final = Get-LocalGroup -SID %s -ErrorAction Stop
-    Emit-OK $final
+    Emit-GroupWithMembers $final
 } catch {
     $kind = Classify-LG $_.Exception.Message $_.FullyQualifiedErrorId
     Emit-Err $kind $_.Exception.Message @{ sid = %s; new_name = %s; step = 'update' }
@@ -338,7 +451,7 @@ try {
 	if err != nil {
 		return nil, err
 	}
-	return parseGroupData("update", resp.Data)
+	return parseGroupWithMembers("update", resp.Data)
 }
 
 // ---------------------------------------------------------------------------
@@ -405,10 +518,10 @@ try {
 func (lc *LocalGroupClient) ImportByName(ctx context.Context, name string) (*GroupState, error) {
 	qName := psQuote(name)
 
-	script := fmt.Sprintf(`
+	script := lgPsMembersFunc + fmt.Sprintf(`
 try {
     $group = Get-LocalGroup -Name %s -ErrorAction Stop
-    Emit-OK $group
+    Emit-GroupWithMembers $group
 } catch {
     $kind = Classify-LG $_.Exception.Message $_.FullyQualifiedErrorId
     Emit-Err $kind $_.Exception.Message @{ name = %s; step = 'import_by_name' }
@@ -419,7 +532,7 @@ try {
 	if err != nil {
 		return nil, err
 	}
-	return parseGroupData("import_by_name", resp.Data)
+	return parseGroupWithMembers("import_by_name", resp.Data)
 }
 
 // ---------------------------------------------------------------------------
@@ -432,10 +545,10 @@ try {
 func (lc *LocalGroupClient) ImportBySID(ctx context.Context, sid string) (*GroupState, error) {
 	qSID := psQuote(sid)
 
-	script := fmt.Sprintf(`
+	script := lgPsMembersFunc + fmt.Sprintf(`
 try {
     $group = Get-LocalGroup -SID %s -ErrorAction Stop
-    Emit-OK $group
+    Emit-GroupWithMembers $group
 } catch {
     $kind = Classify-LG $_.Exception.Message $_.FullyQualifiedErrorId
     Emit-Err $kind $_.Exception.Message @{ sid = %s; step = 'import_by_sid' }
@@ -446,5 +559,5 @@ try {
 	if err != nil {
 		return nil, err
 	}
-	return parseGroupData("import_by_sid", resp.Data)
+	return parseGroupWithMembers("import_by_sid", resp.Data)
 }
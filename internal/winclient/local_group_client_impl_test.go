@@ -106,6 +106,15 @@ func fakeGroupData(name, description, sid string) map[string]any {
 	}
 }
 
+// fakeGroupWithMembers wraps a fakeGroupData object in the
+// Emit-GroupWithMembers payload shape. members is always emitted as an array.
+func fakeGroupWithMembers(group map[string]any, members ...map[string]any) map[string]any {
+	if members == nil {
+		members = []map[string]any{}
+	}
+	return map[string]any{"group": group, "members": members}
+}
+
 // -----------------------------------------------------------------------------
 // LocalGroupError type tests
 // -----------------------------------------------------------------------------
@@ -335,7 +344,7 @@ func TestRunLGEnvelope_ClassifiedError_Permission(t *testing.T) {
 
 func TestLocalGroupCreate_HappyPath(t *testing.T) {
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("AppAdmins", "Application admins", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "Application admins", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -399,7 +408,7 @@ func TestLocalGroupCreate_InvalidName_EC6(t *testing.T) {
 
 func TestLocalGroupCreate_EmptyDescription(t *testing.T) {
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("EmptyDesc", "", "S-1-5-21-1-2-3-999")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("EmptyDesc", "", "S-1-5-21-1-2-3-999"))), "", nil
 	})
 	defer restore()
 
@@ -419,7 +428,7 @@ func TestLocalGroupCreate_EmptyDescription(t *testing.T) {
 
 func TestLocalGroupRead_HappyPath(t *testing.T) {
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("AppAdmins", "desc", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "desc", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -479,7 +488,7 @@ func TestLocalGroupRead_WindowsCasingPreserved_EC4(t *testing.T) {
 	// EC-4: Windows returns "AppAdmins" even if we stored "appadmins".
 	// The GroupState carries the Windows casing; provider layer applies EqualFold.
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("AppAdmins", "desc", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "desc", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -501,7 +510,7 @@ func TestLocalGroupRead_WindowsCasingPreserved_EC4(t *testing.T) {
 func TestLocalGroupUpdate_HappyPath_RenameAndDescription(t *testing.T) {
 	// The update PS script reads, renames, sets description, returns final state.
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("NewName", "New Description", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("NewName", "New Description", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -525,7 +534,7 @@ func TestLocalGroupUpdate_HappyPath_RenameAndDescription(t *testing.T) {
 
 func TestLocalGroupUpdate_DescriptionOnly(t *testing.T) {
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("AppAdmins", "Updated description", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "Updated description", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -592,7 +601,7 @@ func TestLocalGroupDelete_BuiltinGuard_EC2(t *testing.T) {
 	// provide a response for that.
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
 		// This covers the Read call inside Delete for the name lookup.
-		return lgOK(t, fakeGroupData("Administrators", "", "S-1-5-32-544")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("Administrators", "", "S-1-5-32-544"))), "", nil
 	})
 	defer restore()
 
@@ -693,7 +702,7 @@ func TestLocalGroupDelete_BuiltinGuard_ReadFailFallback(t *testing.T) {
 
 func TestLocalGroupImportByName_HappyPath_EC10(t *testing.T) {
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("AppAdmins", "Application admins", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "Application admins", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -742,7 +751,7 @@ func TestLocalGroupImportByName_PermissionDenied_EC8(t *testing.T) {
 
 func TestLocalGroupImportBySID_HappyPath_EC10(t *testing.T) {
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
-		return lgOK(t, fakeGroupData("AppAdmins", "desc", "S-1-5-21-111-222-333-1001")), "", nil
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "desc", "S-1-5-21-111-222-333-1001"))), "", nil
 	})
 	defer restore()
 
@@ -802,3 +811,120 @@ func TestPsQuoteFromLGTest(t *testing.T) {
 		}
 	}
 }
+
+// -----------------------------------------------------------------------------
+// Members — single round trip, single-vs-array serialisation
+// -----------------------------------------------------------------------------
+
+func TestLocalGroupRead_MembersInSameRoundTrip(t *testing.T) {
+	calls := 0
+	var script string
+	restore := stubLGRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		calls++
+		script = s
+		return lgOK(t, fakeGroupWithMembers(fakeGroupData("AppAdmins", "desc", "S-1-5-21-1-2-3-1001"),
+			map[string]any{"SID": "S-1-5-21-1-2-3-1002", "Name": `HOST\bob`, "PrincipalSource": "1", "ObjectClass": "User"},
+			map[string]any{"SID": "S-1-5-21-9-9-9-500", "Name": `CORP\alice`, "PrincipalSource": "ActiveDirectory"},
+		)), "", nil
+	})
+	defer restore()
+
+	lc := NewLocalGroupClient(newLGTestClient(t))
+	gs, err := lc.Read(context.Background(), "S-1-5-21-1-2-3-1001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Read made %d WinRM calls, want 1", calls)
+	}
+	if !strings.Contains(script, "Get-LocalGroupMember") || !strings.Contains(script, "Emit-GroupWithMembers $group") {
+		t.Errorf("Read script should fetch members in the same script:\n%s", script)
+	}
+	want := []GroupMember{
+		{SID: "S-1-5-21-1-2-3-1002", Name: `HOST\bob`, PrincipalSource: "Local", ObjectClass: "User"},
+		{SID: "S-1-5-21-9-9-9-500", Name: `CORP\alice`, PrincipalSource: "ActiveDirectory", ObjectClass: "Unknown"},
+	}
+	if len(gs.Members) != len(want) {
+		t.Fatalf("Members = %+v, want %+v", gs.Members, want)
+	}
+	for i := range want {
+		if gs.Members[i] != want[i] {
+			t.Errorf("Members[%d] = %+v, want %+v", i, gs.Members[i], want[i])
+		}
+	}
+}
+
+func TestParseGroupWithMembers_Shapes(t *testing.T) {
+	group := `{"Name":"G","Description":"","SID":{"Value":"S-1-5-21-1-2-3-1001"}}`
+	cases := []struct {
+		name    string
+		members string
+		want    int
+	}{
+		{"array", `[{"SID":"S-1-5-21-1","Name":"A"},{"SID":"S-1-5-21-2","Name":"B"}]`, 2},
+		{"single object", `{"SID":"S-1-5-21-1","Name":"A","PrincipalSource":"Local"}`, 1},
+		{"empty array", `[]`, 0},
+		{"null", `null`, 0},
+		{"absent", ``, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := `{"group":` + group
+			if tc.members != "" {
+				data += `,"members":` + tc.members
+			}
+			data += `}`
+			gs, err := parseGroupWithMembers("read", json.RawMessage(data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gs.Members == nil || len(gs.Members) != tc.want {
+				t.Errorf("Members = %#v, want %d non-nil entries", gs.Members, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseGroupWithMembers_OrphanNameFallsBackToSID(t *testing.T) {
+	data := `{"group":{"Name":"G","SID":{"Value":"S-1-5-21-1-2-3-1001"}},` +
+		`"members":[{"SID":"S-1-5-21-7-7-7-1105","Name":"","PrincipalSource":null},{"SID":"","Name":"ghost"}]}`
+	gs, err := parseGroupWithMembers("read", json.RawMessage(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gs.Members) != 1 {
+		t.Fatalf("entries without a SID must be dropped, got %+v", gs.Members)
+	}
+	if m := gs.Members[0]; m.Name != m.SID || m.PrincipalSource != "Unknown" {
+		t.Errorf("orphan member = %+v", m)
+	}
+}
+
+func TestParseGroupData_PrincipalSourceAndObjectClass(t *testing.T) {
+	cases := []struct {
+		name, group, wantSource, wantClass string
+	}{
+		{"enum as integer", `{"Name":"G","SID":{"Value":"S-1-5-21-1"},"PrincipalSource":1,"ObjectClass":"Group"}`, "Local", "Group"},
+		{"enum as string", `{"Name":"G","SID":{"Value":"S-1-5-21-1"},"PrincipalSource":"Local","ObjectClass":"Group"}`, "Local", "Group"},
+		{"null and absent", `{"Name":"G","SID":{"Value":"S-1-5-21-1"},"PrincipalSource":null}`, "Unknown", "Group"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gs, err := parseGroupData("read", json.RawMessage(tc.group))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gs.PrincipalSource != tc.wantSource || gs.ObjectClass != tc.wantClass {
+				t.Errorf("PrincipalSource, ObjectClass = %q, %q; want %q, %q",
+					gs.PrincipalSource, gs.ObjectClass, tc.wantSource, tc.wantClass)
+			}
+		})
+	}
+}
+
+func TestParseGroupWithMembers_BadGroup(t *testing.T) {
+	_, err := parseGroupWithMembers("read", json.RawMessage(`{"group":{"Name":"G"},"members":[]}`))
+	if !IsLocalGroupError(err, LocalGroupErrorUnknown) {
+		t.Errorf("empty SID must be an unknown error, got %v", err)
+	}
+}
@@ -39,6 +39,7 @@ import (
 // truncate, psResponse, mapLGKind, and parseGroupData from local_group.go
 // and service.go (all in the same package).
 func ResolveGroup(ctx context.Context, c *Client, groupOrSID string) (*GroupState, error) {
+	ctx = withCommandOp(ctx, "resolve_group")
 	q := psQuote(groupOrSID)
 
 	var param string
@@ -103,6 +103,7 @@ func normalizePrincipalSource(s string) string {
 // Transport / PS-level errors that bypass Emit-Err (non-zero exit, context
 // cancellation) are translated into *LocalGroupMemberError with Kind=Unknown.
 func (mc *LocalGroupMemberClient) runLGMEnvelope(ctx context.Context, op, key, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := lgPsHeader + "\n" + script
 	stdout, stderr, err := runPowerShell(ctx, mc.c, full)
 	if err != nil {
@@ -170,12 +171,13 @@ type lgmMember struct {
 	SID             string `json:"SID"`
 	Name            string `json:"Name"`
 	PrincipalSource string `json:"PrincipalSource"`
+	ObjectClass     string `json:"ObjectClass"`
 }
 
 // lgmListData is the JSON shape of the data field in the List response.
 type lgmListData struct {
-	Tier    string      `json:"tier"`
-	Members []lgmMember `json:"members"`
+	Tier    string              `json:"tier"`
+	Members jsonList[lgmMember] `json:"members"`
 }
 
 // List returns all current members of the group identified by groupSID.
@@ -210,7 +212,8 @@ try {
         }
         $srcStr  = try { $m.PrincipalSource.ToString() } catch { 'Unknown' }
         $nameVal = if ($m.Name -and $m.Name.Length -gt 0) { $m.Name } else { $sidVal }
-        $members += [ordered]@{ SID = $sidVal; Name = $nameVal; PrincipalSource = $srcStr }
+        $clsVal  = if ($m.ObjectClass) { [string]$m.ObjectClass } else { 'Unknown' }
+        $members += [ordered]@{ SID = $sidVal; Name = $nameVal; PrincipalSource = $srcStr; ObjectClass = $clsVal }
     }
     $t1Done = $true
 } catch {
@@ -253,10 +256,14 @@ try {
                 $sid   = $ntAcc.Translate(
                              [System.Security.Principal.SecurityIdentifier]).Value
             } catch {}
+            $cls = if ($pc -match '^[^:]*:Win32_Group\.') { 'Group' }
+                   elseif ($pc -match '^[^:]*:Win32_UserAccount\.') { 'User' }
+                   else { 'Unknown' }
             $members += [ordered]@{
                 SID             = $sid
                 Name            = $dn
                 PrincipalSource = 'Unknown'
+                ObjectClass     = $cls
             }
         }
     }
@@ -305,6 +312,7 @@ try {
             SID             = $sid
             Name            = $name
             PrincipalSource = 'Unknown'
+            ObjectClass     = 'Unknown'
         }
     }
     $t3Done = $true
@@ -340,11 +348,16 @@ Emit-OK @{ tier = 'all_failed'; members = @() }
 			name = sid
 		}
 		src := normalizePrincipalSource(m.PrincipalSource)
+		cls := m.ObjectClass
+		if cls == "" {
+			cls = "Unknown"
+		}
 		states = append(states, &LocalGroupMemberState{
 			GroupSID:        groupSID,
 			MemberSID:       sid,
 			MemberName:      name,
 			PrincipalSource: src,
+			ObjectClass:     cls,
 		})
 	}
 	return states, nil
@@ -378,6 +391,47 @@ func (mc *LocalGroupMemberClient) Get(ctx context.Context, groupSID string, memb
 	return nil, nil // membership absent
 }
 
+// ---------------------------------------------------------------------------
+// Find — look a member up by name or SID, matching on SID
+// ---------------------------------------------------------------------------
+
+// Find returns the member of groupSID identified by member, a name in any
+// form accepted by Add (DOMAIN\user, user, user@domain) or a SID.
+//
+// member is resolved to a SID first and matched on SID, so a bare "alice"
+// resolves to the local account and never matches CONTOSO\alice. Only when
+// the name cannot be resolved (e.g. the domain is unreachable) does Find
+// fall back to comparing member with the full member name, case-insensitively.
+//
+// Return semantics match Get: (nil, nil) when the group exists but has no
+// such member.
+func (mc *LocalGroupMemberClient) Find(ctx context.Context, groupSID string, member string) (*LocalGroupMemberState, error) {
+	sid, err := mc.resolveMemberSID(ctx, member)
+	if err != nil && !IsLocalGroupMemberError(err, LocalGroupMemberErrorUnresolvable) {
+		return nil, err
+	}
+	members, err := mc.List(ctx, groupSID)
+	if err != nil {
+		return nil, err
+	}
+	return matchMember(members, member, sid), nil
+}
+
+// matchMember returns the entry of members whose SID equals sid or, when sid
+// is empty (unresolved), whose full name equals member. Short names are
+// never compared: "alice" must not match "CONTOSO\alice".
+func matchMember(members []*LocalGroupMemberState, member, sid string) *LocalGroupMemberState {
+	for _, m := range members {
+		if sid != "" && strings.EqualFold(m.MemberSID, sid) {
+			return m
+		}
+		if sid == "" && strings.EqualFold(m.MemberName, member) {
+			return m
+		}
+	}
+	return nil
+}
+
 // ---------------------------------------------------------------------------
 // resolveMemberSID — translate identity string to SID via NTAccount (EC-3)
 // ---------------------------------------------------------------------------
@@ -285,6 +285,7 @@ func TestLGMList_Primary_HappyPath(t *testing.T) {
 		return lgOK(t, lgmListRespData("primary", []map[string]any{
 			lgmMemberEntry("S-1-5-21-100-200-300-500", "DOMAIN\\alice", "ActiveDirectory"),
 			lgmMemberEntry("S-1-5-21-100-200-300-501", "WIN01\\bob", "Local"),
+			{"SID": "S-1-5-21-100-200-300-1102", "Name": "DOMAIN\\ops", "PrincipalSource": "ActiveDirectory", "ObjectClass": "Group"},
 		})), "", nil
 	})
 	defer restore()
@@ -294,8 +295,11 @@ func TestLGMList_Primary_HappyPath(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(members) != 2 {
-		t.Fatalf("expected 2 members, got %d", len(members))
+	if len(members) != 3 {
+		t.Fatalf("expected 3 members, got %d", len(members))
+	}
+	if members[0].ObjectClass != "Unknown" || members[2].ObjectClass != "Group" {
+		t.Errorf("ObjectClass = %q, %q; want Unknown (absent), Group", members[0].ObjectClass, members[2].ObjectClass)
 	}
 	if members[0].MemberSID != "S-1-5-21-100-200-300-500" {
 		t.Errorf("members[0].MemberSID = %q", members[0].MemberSID)
@@ -327,6 +331,26 @@ func TestLGMList_Empty(t *testing.T) {
 	}
 }
 
+// TestLGMList_SingleMemberCollapsed covers ConvertTo-Json collapsing a
+// one-member collection into a bare object.
+func TestLGMList_SingleMemberCollapsed(t *testing.T) {
+	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return lgOK(t, map[string]any{
+			"tier":    "primary",
+			"members": lgmMemberEntry("S-1-5-21-100-200-300-500", "DOMAIN\\alice", "ActiveDirectory"),
+		}), "", nil
+	})
+	defer restore()
+
+	members, err := lgmNewClient(t).List(context.Background(), "S-1-5-32-544")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 1 || members[0].MemberSID != "S-1-5-21-100-200-300-500" {
+		t.Errorf("members = %+v, want the single collapsed member", members)
+	}
+}
+
 func TestLGMList_WMIFallback_EC6(t *testing.T) {
 	// EC-6: orphaned SID — tier2 WMI fallback.
 	restore := stubLGRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
@@ -1059,3 +1083,85 @@ func TestLGMAdd_PrincipalSourceNormalization(t *testing.T) {
 		t.Errorf("PrincipalSource = %q, want Local (numeric 1 → Local)", state.PrincipalSource)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Find — SID-based matching with full-name fallback
+// ---------------------------------------------------------------------------
+
+// lgmFindMembers is an Administrators group holding a local and a domain
+// account that share the short name "alice".
+func lgmFindMembers(t *testing.T) string {
+	return lgOK(t, lgmListRespData("primary", []map[string]any{
+		lgmMemberEntry("S-1-5-21-100-200-300-500", "CONTOSO\\alice", "ActiveDirectory"),
+		lgmMemberEntry("S-1-5-21-900-800-700-1001", "WIN01\\bob", "Local"),
+	}))
+}
+
+func TestLGMFind_LocalUser(t *testing.T) {
+	restore := stubLGSequence(
+		[3]any{lgOK(t, lgmSIDRespData("S-1-5-21-900-800-700-1001")), "", nil},
+		[3]any{lgmFindMembers(t), "", nil},
+	)
+	defer restore()
+
+	m, err := lgmNewClient(t).Find(context.Background(), "S-1-5-32-544", "bob")
+	if err != nil || m == nil || m.MemberName != "WIN01\\bob" {
+		t.Errorf("Find(bob) = %+v, %v; want WIN01\\bob", m, err)
+	}
+}
+
+func TestLGMFind_DomainUser(t *testing.T) {
+	restore := stubLGSequence(
+		[3]any{lgOK(t, lgmSIDRespData("S-1-5-21-100-200-300-500")), "", nil},
+		[3]any{lgmFindMembers(t), "", nil},
+	)
+	defer restore()
+
+	m, err := lgmNewClient(t).Find(context.Background(), "S-1-5-32-544", "contoso\\ALICE")
+	if err != nil || m == nil || m.MemberSID != "S-1-5-21-100-200-300-500" {
+		t.Errorf("Find(contoso\\ALICE) = %+v, %v; want the domain account", m, err)
+	}
+}
+
+func TestLGMFind_LocalUserSameShortNameAsDomainUser(t *testing.T) {
+	// "alice" resolves to the local account WIN01\alice, which is not in the
+	// group; CONTOSO\alice must not be mistaken for it.
+	restore := stubLGSequence(
+		[3]any{lgOK(t, lgmSIDRespData("S-1-5-21-900-800-700-1002")), "", nil},
+		[3]any{lgmFindMembers(t), "", nil},
+	)
+	defer restore()
+
+	m, err := lgmNewClient(t).Find(context.Background(), "S-1-5-32-544", "alice")
+	if err != nil || m != nil {
+		t.Errorf("Find(alice) = %+v, %v; want no match", m, err)
+	}
+}
+
+func TestLGMFind_UnresolvableFallsBackToFullName(t *testing.T) {
+	restore := stubLGSequence(
+		[3]any{lgErr(t, "member_unresolvable", "The trust relationship failed"), "", nil},
+		[3]any{lgmFindMembers(t), "", nil},
+	)
+	defer restore()
+
+	m, err := lgmNewClient(t).Find(context.Background(), "S-1-5-32-544", "CONTOSO\\alice")
+	if err != nil || m == nil || m.MemberSID != "S-1-5-21-100-200-300-500" {
+		t.Errorf("Find(CONTOSO\\alice) = %+v, %v; want a full-name match", m, err)
+	}
+
+	if got := matchMember([]*LocalGroupMemberState{{MemberSID: "S-1-5-21-100-200-300-500", MemberName: "CONTOSO\\alice"}},
+		"alice", ""); got != nil {
+		t.Errorf("a short name must not match a qualified name, got %+v", got)
+	}
+}
+
+func TestLGMFind_SIDInput(t *testing.T) {
+	restore := stubLGSequence([3]any{lgmFindMembers(t), "", nil})
+	defer restore()
+
+	m, err := lgmNewClient(t).Find(context.Background(), "S-1-5-32-544", "S-1-5-21-900-800-700-1001")
+	if err != nil || m == nil || m.MemberName != "WIN01\\bob" {
+		t.Errorf("Find(SID) = %+v, %v", m, err)
+	}
+}
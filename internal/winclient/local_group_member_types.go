@@ -110,11 +110,14 @@ func (e *LocalGroupMemberError) Error() string {
 // Unwrap returns the underlying cause for errors.As / errors.Is chain walking.
 func (e *LocalGroupMemberError) Unwrap() error { return e.Cause }
 
-// Is implements errors.Is comparison by Kind only, enabling:
+// Is implements errors.Is comparison by Kind or ErrorClass, enabling:
 //
 //	errors.Is(err, ErrLocalGroupMemberAlreadyExists)
 //	// true for any *LocalGroupMemberError{Kind: LocalGroupMemberErrorAlreadyExists}
 func (e *LocalGroupMemberError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*LocalGroupMemberError)
 	if !ok {
 		return false
@@ -222,6 +225,11 @@ type LocalGroupMemberState struct {
 	// PrincipalSource is the account origin: "Local", "ActiveDirectory",
 	// "AzureAD", "MicrosoftAccount", or "Unknown" (orphaned SIDs, EC-6).
 	PrincipalSource string
+
+	// ObjectClass is the member's account type as reported by
+	// Get-LocalGroupMember: "User" or "Group". "Unknown" when only the
+	// net localgroup fallback could list the members.
+	ObjectClass string
 }
 
 // ---------------------------------------------------------------------------
@@ -266,4 +274,9 @@ type ClientLocalGroupMember interface {
 	// orphan-SID fallback (EC-6). On all-tiers failure: returns empty slice.
 	// Returns (nil, ErrLocalGroupMemberGroupNotFound) when the group is absent.
 	List(ctx context.Context, groupSID string) ([]*LocalGroupMemberState, error)
+
+	// Find returns the member identified by a name or SID, matching on the
+	// resolved SID and falling back to a full-name comparison only when the
+	// name cannot be resolved. (nil, nil) when the group has no such member.
+	Find(ctx context.Context, groupSID string, member string) (*LocalGroupMemberState, error)
 }
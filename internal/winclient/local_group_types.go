@@ -102,12 +102,15 @@ func (e *LocalGroupError) Unwrap() error {
 	return e.Cause
 }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 //
 // This allows:
 //
 //	errors.Is(err, ErrLocalGroupNotFound) // true when err.Kind == LocalGroupErrorNotFound
 func (e *LocalGroupError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*LocalGroupError)
 	if !ok {
 		return false
@@ -191,10 +194,11 @@ type GroupInput struct {
 // ---------------------------------------------------------------------------
 
 // GroupState holds the observed state of a Windows local group as returned
-// by Read (Get-LocalGroup -SID <sid> | ConvertTo-Json -Depth 3).
+// by Read (Get-LocalGroup -SID <sid> plus its members, serialised in one
+// ConvertTo-Json -Depth 8 payload).
 //
-// Members are intentionally absent: membership management is out of scope for
-// this resource version (ADR-LG-3).
+// Membership is still not managed by this resource (ADR-LG-3); Members is
+// reported read-only so a single round trip yields everything Read needs.
 type GroupState struct {
 	// Name is the Windows local group name as returned by Get-LocalGroup.
 	// Always stored in the casing that Windows uses (ADR-LG-4).
@@ -209,6 +213,36 @@ type GroupState struct {
 	// Used as the Terraform resource ID and as the -SID parameter for all
 	// subsequent mutating cmdlets.
 	SID string
+
+	// PrincipalSource is the group's origin as reported by Get-LocalGroup,
+	// normally Local (see normalizePrincipalSource).
+	PrincipalSource string
+
+	// ObjectClass is the Get-LocalGroup object class, "Group".
+	ObjectClass string
+
+	// Members lists the current group members, sorted by SID. Nil when the
+	// operation did not fetch membership (ResolveGroup); empty for a group
+	// with no members.
+	Members []GroupMember
+}
+
+// GroupMember is one entry of GroupState.Members.
+type GroupMember struct {
+	// SID is the member's Security Identifier. For an orphaned account whose
+	// SID no longer resolves, Name also holds the SID.
+	SID string
+
+	// Name is the DOMAIN\name form reported by Windows.
+	Name string
+
+	// PrincipalSource is one of Local, ActiveDirectory, MicrosoftAccount,
+	// AzureAD or Unknown (see normalizePrincipalSource).
+	PrincipalSource string
+
+	// ObjectClass is User or Group, or Unknown when the Win32_GroupUser
+	// fallback listed the member.
+	ObjectClass string
 }
 
 // ---------------------------------------------------------------------------
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Compile-time assertion: LocalUserClientImpl satisfies LocalUserClient.
@@ -44,8 +45,43 @@ var runPSInput = func(ctx context.Context, c *Client, script, stdin string) (str
 	return c.RunPowerShellWithInput(ctx, script, stdin)
 }
 
+// samTimeqForever is TIMEQ_FOREVER (0xFFFFFFFF seconds after the Unix epoch),
+// the value the SAM stores in acct_expires for an account that never expires.
+// Some hosts surface it through Get-LocalUser as a real date instead of $null.
+var samTimeqForever = time.Unix(0xFFFFFFFF, 0).UTC()
+
+// filetimeEpochEnd is the day after the FILETIME epoch (1601-01-01 UTC). A
+// zero FILETIME ("never") reads back as 1601-01-01, or as 12/31/1600 once
+// converted to a local time west of UTC; no real account date is that old.
+var filetimeEpochEnd = time.Date(1601, 1, 2, 0, 0, 0, 0, time.UTC)
+
+// isNeverDateSentinel reports whether an RFC3339 account date is one of the
+// values Windows uses for "never" (the FILETIME epoch or DateTime.MaxValue)
+// rather than a real date. Get-UserData already maps them to $null; this is
+// the Go-side guard for hosts or cmdlets that still emit them.
+func isNeverDateSentinel(s string) bool {
+	t, err := time.Parse(time.RFC3339, s)
+	return err == nil && (t.Before(filetimeEpochEnd) || t.Year() >= 9999)
+}
+
+// isAccountExpiresSentinel reports whether an RFC3339 AccountExpires value is
+// a "never expires" sentinel rather than a real expiry date.
+func isAccountExpiresSentinel(s string) bool {
+	t, err := time.Parse(time.RFC3339, s)
+	return (err == nil && !t.Before(samTimeqForever)) || isNeverDateSentinel(s)
+}
+
+// userDate returns the RFC3339 date p points to, or "" when p is nil or a
+// "never" sentinel.
+func userDate(p *string) string {
+	if p == nil || isNeverDateSentinel(*p) {
+		return ""
+	}
+	return *p
+}
+
 // ---------------------------------------------------------------------------
-// PowerShell header — Emit-OK, Emit-Err, Classify-LU, Format-PSDate, Get-UserData
+// PowerShell header — Emit-OK, Emit-Err, Classify-LU, Format-PSDate/Expiry, Get-UserData
 // ---------------------------------------------------------------------------
 
 // luPsHeader is prepended to every local-user script. It defines:
@@ -53,7 +89,9 @@ var runPSInput = func(ctx context.Context, c *Client, script, stdin string) (str
 //   - Emit-OK / Emit-Err : JSON envelope emitters.
 //   - Classify-LU        : maps PowerShell LocalAccounts exception identifiers
 //     to LocalUserErrorKind strings for locale-independent error handling.
-//   - Format-PSDate      : normalises DateTimeOffset/DateTime to RFC3339 or $null.
+//   - Format-PSDate      : normalises DateTimeOffset/DateTime to RFC3339, or
+//     $null for the "never" sentinels (FILETIME epoch, DateTime.MinValue/MaxValue).
+//   - Format-PSExpiry    : Format-PSDate that also maps TIMEQ_FOREVER to $null.
 //   - Get-UserData       : builds the normalised JSON hashtable from a LocalUser object.
 //
 // NOTE: this constant uses a Go raw string (backtick-delimited). PowerShell
@@ -91,10 +129,16 @@ function Format-PSDate($dt) {
   if ($dt -is [DateTimeOffset]) { $d = $dt.UtcDateTime }
   elseif ($dt -is [DateTime]) { $d = $dt.ToUniversalTime() }
   else { return $null }
-  if ($d.Year -le 1 -or $d.Year -ge 9999) { return $null }
+  if ($d.Year -le 1601 -or $d.Year -ge 9999) { return $null }
   return $d.ToString('yyyy-MM-ddTHH:mm:ssZ')
 }
 
+function Format-PSExpiry($dt) {
+  $s = Format-PSDate $dt
+  if ($null -ne $s -and $s -ge '2106-02-07T06:28:15Z') { return $null }
+  return $s
+}
+
 function Get-UserData($User) {
   return [ordered]@{
     Name                  = $User.Name
@@ -103,7 +147,7 @@ function Get-UserData($User) {
     Enabled               = $User.Enabled
     PasswordNeverExpires  = $User.PasswordNeverExpires
     UserMayChangePassword = $User.UserMayChangePassword
-    AccountExpires        = (Format-PSDate $User.AccountExpires)
+    AccountExpires        = (Format-PSExpiry $User.AccountExpires)
     LastLogon             = (Format-PSDate $User.LastLogon)
     PasswordLastSet       = (Format-PSDate $User.PasswordLastSet)
     PrincipalSource       = [string]$User.PrincipalSource
@@ -153,6 +197,8 @@ func mapLUKind(k string) LocalUserErrorKind {
 		return LocalUserErrorPermission
 	case "invalid_name":
 		return LocalUserErrorInvalidName
+	case "group_not_found":
+		return LocalUserErrorGroupNotFound
 	default:
 		return LocalUserErrorUnknown
 	}
@@ -181,8 +227,8 @@ func parseUserData(op string, data json.RawMessage) (*UserState, error) {
 		PrincipalSource:          u.PrincipalSource,
 	}
 
-	// AccountExpires: null ⇒ account never expires.
-	if u.AccountExpires != nil {
+	// AccountExpires: null or the SAM "forever" sentinel ⇒ account never expires.
+	if u.AccountExpires != nil && !isAccountExpiresSentinel(*u.AccountExpires) {
 		st.AccountExpires = *u.AccountExpires
 		st.AccountNeverExpires = false
 	} else {
@@ -190,15 +236,11 @@ func parseUserData(op string, data json.RawMessage) (*UserState, error) {
 		st.AccountNeverExpires = true
 	}
 
-	// LastLogon: null ⇒ empty string (never logged on).
-	if u.LastLogon != nil {
-		st.LastLogon = *u.LastLogon
-	}
+	// LastLogon: null or a sentinel ⇒ empty string (never logged on).
+	st.LastLogon = userDate(u.LastLogon)
 
-	// PasswordLastSet: null ⇒ empty string (not yet set).
-	if u.PasswordLastSet != nil {
-		st.PasswordLastSet = *u.PasswordLastSet
-	}
+	// PasswordLastSet: null or a sentinel ⇒ empty string (not yet set).
+	st.PasswordLastSet = userDate(u.PasswordLastSet)
 
 	return st, nil
 }
@@ -211,6 +253,7 @@ func parseUserData(op string, data json.RawMessage) (*UserState, error) {
 // Emit-OK / Emit-Err JSON envelope. op is a diagnostic label; key is the
 // primary identifier (SID or name) used in error context.
 func (lc *LocalUserClientImpl) runLUEnvelope(ctx context.Context, op, key, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := luPsHeader + "\n" + script
 	stdout, stderr, err := runPowerShell(ctx, lc.c, full)
 	if err != nil {
@@ -236,6 +279,7 @@ func (lc *LocalUserClientImpl) runLUEnvelope(ctx context.Context, op, key, scrip
 // given stdin string (used for password injection) and parses the JSON envelope.
 // The stdin value is NEVER included in error context or logs.
 func (lc *LocalUserClientImpl) runLUEnvelopeWithInput(ctx context.Context, op, key, script, stdin string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := luPsHeader + "\n" + script
 	stdout, stderr, err := runPSInput(ctx, lc.c, full, stdin)
 	if err != nil {
@@ -308,6 +352,8 @@ func (lc *LocalUserClientImpl) parseLUEnvelope(op, key, stdout, stderr string) (
 //  4. Call New-LocalUser with all applicable parameters.
 //  5. Re-read the account via Get-LocalUser -SID to get the full state.
 func (lc *LocalUserClientImpl) Create(ctx context.Context, input UserInput, password string) (*UserState, error) {
+	defer lc.c.invalidateUserBatch()
+
 	qName := psQuote(input.Name)
 	qFullName := psQuote(input.FullName)
 	qDesc := psQuote(input.Description)
@@ -390,7 +436,12 @@ try {
 
 // Read retrieves the current state of the user identified by sid.
 // Returns (nil, nil) when the user does not exist (EC-3 drift detection).
+// With Config.ReadBatching the user is looked up in the batched snapshot,
+// unless ctx comes from WithoutReadBatching (see read_batch.go).
 func (lc *LocalUserClientImpl) Read(ctx context.Context, sid string) (*UserState, error) {
+	if lc.c.readBatching(ctx) {
+		return lc.readBatched(ctx, sid)
+	}
 	qSID := psQuote(sid)
 
 	script := fmt.Sprintf(`
@@ -426,6 +477,8 @@ try {
 // PasswordNeverExpires and UserMayNotChangePassword are always passed as
 // explicit booleans (Set-LocalUser accepts $true/$false for these).
 func (lc *LocalUserClientImpl) Update(ctx context.Context, sid string, input UserInput) (*UserState, error) {
+	defer lc.c.invalidateUserBatch()
+
 	qSID := psQuote(sid)
 	qFullName := psQuote(input.FullName)
 	qDesc := psQuote(input.Description)
@@ -483,6 +536,8 @@ try {
 // Rename renames the user via Rename-LocalUser -SID -NewName.
 // The SID is unchanged; must be called BEFORE Update in the same apply.
 func (lc *LocalUserClientImpl) Rename(ctx context.Context, sid, newName string) error {
+	defer lc.c.invalidateUserBatch()
+
 	qSID := psQuote(sid)
 	qName := psQuote(newName)
 
@@ -508,6 +563,8 @@ try {
 // The password plaintext is injected via stdin and NEVER appears in the script
 // body, WinRM trace logs, or diagnostic output (ADR-LU-3, EC-6).
 func (lc *LocalUserClientImpl) SetPassword(ctx context.Context, sid, password string) error {
+	defer lc.c.invalidateUserBatch()
+
 	qSID := psQuote(sid)
 
 	script := fmt.Sprintf(`
@@ -527,12 +584,44 @@ try {
 	return err
 }
 
+// SetPasswordExpired sets (expired=true) or clears the flag that forces the
+// user to change their password at next logon. The flag lives in the SAM
+// user's PasswordExpired property, reachable through the WinNT ADSI provider;
+// Windows clears it once the user changes the password.
+func (lc *LocalUserClientImpl) SetPasswordExpired(ctx context.Context, sid string, expired bool) error {
+	defer lc.c.invalidateUserBatch()
+
+	qSID := psQuote(sid)
+	flag := 0
+	if expired {
+		flag = 1
+	}
+
+	script := fmt.Sprintf(`
+try {
+    $user = Get-LocalUser -SID %s -ErrorAction Stop
+    $adsi = [ADSI]("WinNT://$env:COMPUTERNAME/" + $user.Name + ",user")
+    $adsi.PasswordExpired = %d
+    $adsi.SetInfo()
+    Emit-OK @{ password_expired = [bool]%d }
+} catch {
+    $kind = Classify-LU $_.Exception.Message $_.FullyQualifiedErrorId
+    Emit-Err $kind $_.Exception.Message @{ sid = %s; step = 'set_password_expired' }
+}
+`, qSID, flag, flag, qSID)
+
+	_, err := lc.runLUEnvelope(ctx, "set_password_expired", sid, script)
+	return err
+}
+
 // ---------------------------------------------------------------------------
 // Enable / Disable
 // ---------------------------------------------------------------------------
 
 // Enable enables the account via Enable-LocalUser -SID.
 func (lc *LocalUserClientImpl) Enable(ctx context.Context, sid string) error {
+	defer lc.c.invalidateUserBatch()
+
 	qSID := psQuote(sid)
 	script := fmt.Sprintf(`
 try {
@@ -549,6 +638,8 @@ try {
 
 // Disable disables the account via Disable-LocalUser -SID.
 func (lc *LocalUserClientImpl) Disable(ctx context.Context, sid string) error {
+	defer lc.c.invalidateUserBatch()
+
 	qSID := psQuote(sid)
 	script := fmt.Sprintf(`
 try {
@@ -574,6 +665,8 @@ try {
 // WDAGUtilityAccount), returns ErrLocalUserBuiltinAccount immediately without
 // calling Remove-LocalUser (EC-2, ADR-LU-2). This check is immune to renames.
 func (lc *LocalUserClientImpl) Delete(ctx context.Context, sid string) error {
+	defer lc.c.invalidateUserBatch()
+
 	// EC-2: built-in RID guard.
 	parts := strings.Split(sid, "-")
 	if len(parts) > 0 {
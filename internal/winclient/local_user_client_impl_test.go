@@ -15,7 +15,7 @@
 //	EC-10 Invalid name → invalid_name
 //	parseLUEnvelope: missing JSON, malformed JSON
 //	parseUserData: all fields including null/non-null dates, SID inversion
-//	mapLUKind: all 8 kinds + unknown fallback
+//	mapLUKind: all 9 kinds + unknown fallback
 //	LocalUserError: Error(), Unwrap(), Is(), sentinels, IsLocalUserError()
 //	ResolveLocalUserSID: SID vs name routing
 package winclient
@@ -171,6 +171,7 @@ func TestLocalUserError_Sentinels(t *testing.T) {
 		{ErrLocalUserPasswordPolicy, LocalUserErrorPasswordPolicy},
 		{ErrLocalUserPermission, LocalUserErrorPermission},
 		{ErrLocalUserInvalidName, LocalUserErrorInvalidName},
+		{ErrLocalUserGroupNotFound, LocalUserErrorGroupNotFound},
 		{ErrLocalUserUnknown, LocalUserErrorUnknown},
 	}
 	for _, p := range pairs {
@@ -193,6 +194,7 @@ func TestMapLUKind(t *testing.T) {
 		"password_policy":   LocalUserErrorPasswordPolicy,
 		"permission_denied": LocalUserErrorPermission,
 		"invalid_name":      LocalUserErrorInvalidName,
+		"group_not_found":   LocalUserErrorGroupNotFound,
 		"unknown":           LocalUserErrorUnknown,
 		"":                  LocalUserErrorUnknown,
 		"totally_unknown":   LocalUserErrorUnknown,
@@ -258,6 +260,62 @@ func TestParseUserData_WithAccountExpires(t *testing.T) {
 	}
 }
 
+func TestParseUserData_AccountExpiresForeverSentinel(t *testing.T) {
+	for _, exp := range []string{"2106-02-07T06:28:15Z", "2106-02-07T07:28:15+01:00"} {
+		data := fakeUserData("svc", "S-1-5-21-1-2-3-1004")
+		data["AccountExpires"] = exp
+		raw, _ := json.Marshal(data)
+		us, err := parseUserData("test", json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !us.AccountNeverExpires || us.AccountExpires != "" {
+			t.Errorf("%s: AccountNeverExpires = %v, AccountExpires = %q; want the sentinel read as never", exp, us.AccountNeverExpires, us.AccountExpires)
+		}
+	}
+	if isAccountExpiresSentinel("2106-02-07T06:28:14Z") {
+		t.Error("a date before TIMEQ_FOREVER is a real expiry")
+	}
+}
+
+func TestParseUserData_NeverSentinelsNormalised(t *testing.T) {
+	for _, never := range []string{"1601-01-01T00:00:00Z", "1600-12-31T19:00:00-05:00", "0001-01-01T00:00:00Z", "9999-12-31T23:59:59Z"} {
+		data := fakeUserData("svc", "S-1-5-21-1-2-3-1005")
+		data["AccountExpires"] = never
+		data["LastLogon"] = never
+		data["PasswordLastSet"] = never
+		raw, _ := json.Marshal(data)
+		us, err := parseUserData("test", json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !us.AccountNeverExpires || us.AccountExpires != "" || us.LastLogon != "" || us.PasswordLastSet != "" {
+			t.Errorf("%s: want every date read as never, got %+v", never, us)
+		}
+	}
+
+	data := fakeUserData("svc", "S-1-5-21-1-2-3-1005")
+	data["LastLogon"] = "1601-01-02T00:00:00Z"
+	data["PasswordLastSet"] = "2026-10-17T08:00:00Z"
+	raw, _ := json.Marshal(data)
+	us, _ := parseUserData("test", json.RawMessage(raw))
+	if us.LastLogon != "1601-01-02T00:00:00Z" || us.PasswordLastSet != "2026-10-17T08:00:00Z" {
+		t.Errorf("real dates must be kept: %+v", us)
+	}
+}
+
+func TestLocalUserHeader_NormalisesSentinels(t *testing.T) {
+	for _, want := range []string{
+		"$d.Year -le 1601 -or $d.Year -ge 9999",
+		"AccountExpires        = (Format-PSExpiry $User.AccountExpires)",
+		"'2106-02-07T06:28:15Z'",
+	} {
+		if !strings.Contains(luPsHeader, want) {
+			t.Errorf("luPsHeader missing %q", want)
+		}
+	}
+}
+
 func TestParseUserData_UserMayNotChangePassword_Inversion(t *testing.T) {
 	data := fakeUserData("carol", "S-1-5-21-1-2-3-1003")
 	data["UserMayChangePassword"] = false
@@ -687,7 +745,9 @@ func TestLocalUserClient_Update_PermissionDenied(t *testing.T) {
 func TestLocalUserClient_Rename_HappyPath(t *testing.T) {
 	_, lc := newLUClient(t)
 
-	defer stubLURun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+	var script string
+	defer stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
 		return luOK(t, map[string]any{"renamed": true}), "", nil
 	})()
 
@@ -695,6 +755,10 @@ func TestLocalUserClient_Rename_HappyPath(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Rename() error = %v", err)
 	}
+	// Keyed by SID so the account (profile, group memberships) is preserved.
+	if !strings.Contains(script, "Rename-LocalUser -SID 'S-1-5-21-111-222-333-1001' -NewName 'alice-new'") {
+		t.Errorf("Rename script should target the SID, got:\n%s", script)
+	}
 }
 
 func TestLocalUserClient_Rename_Conflict(t *testing.T) {
@@ -1108,3 +1172,160 @@ func TestResolveLocalUserSID_InvalidJSON(t *testing.T) {
 		t.Errorf("expected unknown for invalid JSON, got: %v", err)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Groups / AddToGroups / RemoveFromGroups
+// ---------------------------------------------------------------------------
+
+func TestLocalUserGroups_SortedAndSingleton(t *testing.T) {
+	_, lc := newLUClient(t)
+	replies := []any{
+		map[string]any{"groups": []string{"Users", "administrators", "Backup Operators"}},
+		map[string]any{"groups": "Users"}, // ConvertTo-Json collapses one-element arrays
+	}
+	defer stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if !strings.Contains(s, "Get-LocalUser -SID 'S-1-5-21-1-2-3-1001'") || !strings.Contains(s, ".Groups()") {
+			t.Errorf("unexpected script: %s", s)
+		}
+		r := replies[0]
+		replies = replies[1:]
+		return luOK(t, r), "", nil
+	})()
+
+	got, err := lc.Groups(context.Background(), "S-1-5-21-1-2-3-1001")
+	if err != nil {
+		t.Fatalf("Groups: %v", err)
+	}
+	if strings.Join(got, ",") != "administrators,Backup Operators,Users" {
+		t.Errorf("Groups = %v", got)
+	}
+	got, err = lc.Groups(context.Background(), "S-1-5-21-1-2-3-1001")
+	if err != nil || len(got) != 1 || got[0] != "Users" {
+		t.Errorf("Groups (singleton) = %v, %v", got, err)
+	}
+}
+
+func TestLocalUserAddToGroups_QuotesGroupsAndIgnoresExisting(t *testing.T) {
+	_, lc := newLUClient(t)
+	var script string
+	defer stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return luOK(t, map[string]any{"groups": 2}), "", nil
+	})()
+
+	if err := lc.AddToGroups(context.Background(), "S-1-5-21-1-2-3-1001", []string{"Users", "O'Brien Ops"}); err != nil {
+		t.Fatalf("AddToGroups: %v", err)
+	}
+	for _, want := range []string{"Add-LocalGroupMember -Group $g", "@('Users','O''Brien Ops')", "'MemberExists'"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestLocalUserAddToGroups_ReportsFailingGroups(t *testing.T) {
+	_, lc := newLUClient(t)
+	defer stubLURun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return luErr(t, "group_not_found",
+			"Add-LocalGroupMember failed for 1 group(s): Nope: Group Nope was not found."), "", nil
+	})()
+
+	err := lc.AddToGroups(context.Background(), "S-1-5-21-1-2-3-1001", []string{"Users", "Nope"})
+	if !errors.Is(err, ErrLocalUserGroupNotFound) || !strings.Contains(err.Error(), "Nope") {
+		t.Errorf("err = %v, want group_not_found naming the group", err)
+	}
+}
+
+func TestLocalUserChangeGroups_EmptyIsNoOp(t *testing.T) {
+	_, lc := newLUClient(t)
+	defer stubLURun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		t.Error("no script may run for an empty group list")
+		return "", "", nil
+	})()
+
+	if err := lc.AddToGroups(context.Background(), "S-1-5-21-1-2-3-1001", nil); err != nil {
+		t.Errorf("AddToGroups: %v", err)
+	}
+	if err := lc.RemoveFromGroups(context.Background(), "S-1-5-21-1-2-3-1001", []string{}); err != nil {
+		t.Errorf("RemoveFromGroups: %v", err)
+	}
+}
+
+func TestLocalUserRemoveFromGroups_IgnoresMissingMembership(t *testing.T) {
+	_, lc := newLUClient(t)
+	var script string
+	defer stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return luOK(t, map[string]any{"groups": 1}), "", nil
+	})()
+
+	if err := lc.RemoveFromGroups(context.Background(), "S-1-5-21-1-2-3-1001", []string{"Users"}); err != nil {
+		t.Fatalf("RemoveFromGroups: %v", err)
+	}
+	if !strings.Contains(script, "Remove-LocalGroupMember") || !strings.Contains(script, "'MemberNotFound|GroupNotFound'") {
+		t.Errorf("unexpected script: %s", script)
+	}
+}
+
+func TestLocalUserSetPasswordExpired(t *testing.T) {
+	_, lc := newLUClient(t)
+	for expired, want := range map[bool]string{true: "PasswordExpired = 1", false: "PasswordExpired = 0"} {
+		var script string
+		restore := stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+			script = s
+			return luOK(t, map[string]any{"password_expired": expired}), "", nil
+		})
+		err := lc.SetPasswordExpired(context.Background(), "S-1-5-21-1-2-3-1001", expired)
+		restore()
+		if err != nil {
+			t.Fatalf("SetPasswordExpired(%v): %v", expired, err)
+		}
+		if !strings.Contains(script, want) || !strings.Contains(script, "SetInfo()") {
+			t.Errorf("SetPasswordExpired(%v) script missing %q", expired, want)
+		}
+	}
+}
+
+func TestLocalUserSetPasswordExpired_NotFound(t *testing.T) {
+	_, lc := newLUClient(t)
+	defer stubLURun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return luErr(t, "not_found", "User S-1-5-21-1-2-3-1001 was not found."), "", nil
+	})()
+
+	err := lc.SetPasswordExpired(context.Background(), "S-1-5-21-1-2-3-1001", true)
+	if !IsLocalUserError(err, LocalUserErrorNotFound) {
+		t.Errorf("err = %v, want not_found", err)
+	}
+}
+
+func TestIsSIDString(t *testing.T) {
+	cases := map[string]bool{
+		"S-1-5-21-1004336348-1177238915-682003330-1001": true,
+		"S-1-5-32-544": true,
+		"s-1-5-18":     true,
+		"S-Backup":     false,
+		"S-1":          false,
+		"S-1-5-":       false,
+		"alice":        false,
+		"":             false,
+	}
+	for in, want := range cases {
+		if got := IsSIDString(in); got != want {
+			t.Errorf("IsSIDString(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestResolveLocalUserSID_NameWithSPrefix(t *testing.T) {
+	c := newLUTestClient(t)
+	defer stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if !strings.Contains(s, "Get-LocalUser -Name 'S-Backup'") {
+			t.Errorf("a name starting with S- must be resolved by name: %s", s)
+		}
+		return luOK(t, fakeUserData("S-Backup", "S-1-5-21-1-2-3-1005")), "", nil
+	})()
+
+	if _, err := ResolveLocalUserSID(context.Background(), c, "S-Backup"); err != nil {
+		t.Fatalf("ResolveLocalUserSID: %v", err)
+	}
+}
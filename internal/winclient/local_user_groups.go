@@ -0,0 +1,113 @@
+// Package winclient — local group membership of a managed local user.
+//
+// These methods back the optional `groups` attribute of windows_local_user.
+// They address the user by SID (ADR-LU-1) and the groups by name, exactly as
+// entered in configuration. Add and remove attempt every group before
+// reporting, so a single missing group does not leave the rest unapplied and
+// the error names every group that failed.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// luGroupsPayload mirrors the JSON object emitted by the Groups script.
+type luGroupsPayload struct {
+	Groups jsonList[string] `json:"groups"`
+}
+
+// Groups returns the local groups the user identified by sid is a direct
+// member of. The WinNT provider is used because Get-LocalGroupMember would
+// require enumerating every group on the host.
+func (lc *LocalUserClientImpl) Groups(ctx context.Context, sid string) ([]string, error) {
+	qSID := psQuote(sid)
+	script := fmt.Sprintf(`
+try {
+    $user = Get-LocalUser -SID %s -ErrorAction Stop
+    $adsi = [ADSI]("WinNT://$env:COMPUTERNAME/" + $user.Name + ",user")
+    $names = @($adsi.Groups() | ForEach-Object {
+        [string]$_.GetType().InvokeMember('Name', 'GetProperty', $null, $_, $null)
+    })
+    Emit-OK @{ groups = $names }
+} catch {
+    $kind = Classify-LU $_.Exception.Message $_.FullyQualifiedErrorId
+    Emit-Err $kind $_.Exception.Message @{ sid = %s; step = 'get_user_groups' }
+}
+`, qSID, qSID)
+
+	resp, err := lc.runLUEnvelope(ctx, "groups", sid, script)
+	if err != nil {
+		return nil, err
+	}
+	var p luGroupsPayload
+	if err := json.Unmarshal(resp.Data, &p); err != nil {
+		return nil, NewLocalUserError(LocalUserErrorUnknown, "failed to parse group list", err,
+			map[string]string{"operation": "groups", "sid": sid})
+	}
+	groups := []string(p.Groups)
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i]) < strings.ToLower(groups[j])
+	})
+	return groups, nil
+}
+
+// AddToGroups adds the user identified by sid to each group in groups.
+func (lc *LocalUserClientImpl) AddToGroups(ctx context.Context, sid string, groups []string) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	return lc.changeGroups(ctx, "add_to_groups", sid, groups, "Add-LocalGroupMember", "MemberExists")
+}
+
+// RemoveFromGroups removes the user identified by sid from each group in groups.
+func (lc *LocalUserClientImpl) RemoveFromGroups(ctx context.Context, sid string, groups []string) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	return lc.changeGroups(ctx, "remove_from_groups", sid, groups, "Remove-LocalGroupMember", "MemberNotFound|GroupNotFound")
+}
+
+// changeGroups runs cmdlet for every group, treating errors whose
+// FullyQualifiedErrorId matches ignoreFQEI as already converged. Failures are
+// collected and reported in a single envelope whose kind is that of the first
+// failing group.
+func (lc *LocalUserClientImpl) changeGroups(ctx context.Context, op, sid string, groups []string, cmdlet, ignoreFQEI string) error {
+	qSID := psQuote(sid)
+	script := fmt.Sprintf(`
+try {
+    $user = Get-LocalUser -SID %s -ErrorAction Stop
+} catch {
+    $kind = Classify-LU $_.Exception.Message $_.FullyQualifiedErrorId
+    Emit-Err $kind $_.Exception.Message @{ sid = %s; step = 'get_local_user' }
+    return
+}
+$failed = @()
+$firstKind = $null
+foreach ($g in %s) {
+    try {
+        %s -Group $g -Member $user -ErrorAction Stop
+    } catch {
+        $fq = [string]$_.FullyQualifiedErrorId
+        if ($fq -match '%s') { continue }
+        if ($fq -match 'GroupNotFound') { $kind = 'group_not_found' }
+        else { $kind = Classify-LU $_.Exception.Message $fq }
+        if ($null -eq $firstKind) { $firstKind = $kind }
+        $failed += ($g + ': ' + $_.Exception.Message)
+    }
+}
+if ($failed.Count -gt 0) {
+    Emit-Err $firstKind ('%s failed for ' + $failed.Count + ' group(s): ' + ($failed -join '; ')) @{
+        sid = %s; step = '%s'; groups_failed = [string]$failed.Count
+    }
+    return
+}
+Emit-OK @{ groups = %d }
+`, qSID, qSID, psQuoteList(groups), cmdlet, ignoreFQEI, cmdlet, qSID, op, len(groups))
+
+	_, err := lc.runLUEnvelope(ctx, op, sid, script)
+	return err
+}
@@ -6,7 +6,7 @@
 // pre-resolve a local user identity without duplicating PowerShell logic.
 //
 // Design invariants:
-//   - If nameOrSID starts with "S-", Get-LocalUser -SID is used.
+//   - If nameOrSID is a SID string (IsSIDString), Get-LocalUser -SID is used.
 //   - Otherwise, Get-LocalUser -Name is used.
 //   - Errors are returned as *LocalUserError (same as local_user.go).
 //   - Does NOT touch existing files (local_group_helpers.go, etc.).
@@ -16,15 +16,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"regexp"
 )
 
+// sidStringRegex matches the string form of a security identifier:
+// revision 1, an identifier authority, and one or more sub-authorities.
+var sidStringRegex = regexp.MustCompile(`^[Ss]-1-\d+(-\d+)+$`)
+
+// IsSIDString reports whether s is a SID string such as
+// S-1-5-21-1004336348-1177238915-682003330-1001. A SAM account name may
+// itself start with "S-" (e.g. "S-Backup"), so a prefix test is not enough.
+func IsSIDString(s string) bool {
+	return sidStringRegex.MatchString(s)
+}
+
 // ResolveLocalUserSID resolves a user-supplied identifier (SAM name or SID
 // string) to a *UserState containing the canonical SID and all observable
 // attributes as returned by Windows.
 //
 // Auto-detection:
-//   - If nameOrSID starts with "S-" → calls Get-LocalUser -SID <nameOrSID>.
+//   - If IsSIDString(nameOrSID) → calls Get-LocalUser -SID <nameOrSID>.
 //   - Otherwise             → calls Get-LocalUser -Name <nameOrSID>.
 //
 // On success, UserState.SID is the stable SID used for all subsequent
@@ -36,10 +47,11 @@ import (
 // Reuses luPsHeader, runPowerShell, extractLastJSONLine, truncate, psResponse,
 // mapLUKind, and parseUserData from local_user.go (same package).
 func ResolveLocalUserSID(ctx context.Context, c *Client, nameOrSID string) (*UserState, error) {
+	ctx = withCommandOp(ctx, "resolve_user_sid")
 	q := psQuote(nameOrSID)
 
 	var param string
-	if strings.HasPrefix(nameOrSID, "S-") {
+	if IsSIDString(nameOrSID) {
 		param = "-SID " + q
 	} else {
 		param = "-Name " + q
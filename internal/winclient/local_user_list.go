@@ -0,0 +1,75 @@
+// Package winclient: local user enumeration over WinRM.
+//
+// LocalUserClientImpl.List backs the windows_local_users data source and the
+// batched Read (see read_batch.go). Every user goes through the same
+// Get-UserData / parseUserData path as a single-user read, so dates and the
+// account-expiry sentinel are reported identically.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Compile-time assertion: LocalUserClientImpl satisfies LocalUserLister.
+var _ LocalUserLister = (*LocalUserClientImpl)(nil)
+
+// psLocalUserListAll emits every local user through Get-UserData. The array
+// is wrapped in an object so ConvertTo-Json keeps it an array for a single
+// user; listUsers still accepts a bare object.
+const psLocalUserListAll = `
+try {
+    $rows = @(Get-LocalUser -ErrorAction Stop | ForEach-Object { Get-UserData $_ })
+    Emit-OK ([ordered]@{ users = $rows })
+} catch {
+    $kind = Classify-LU $_.Exception.Message $_.FullyQualifiedErrorId
+    Emit-Err $kind $_.Exception.Message @{ step = 'get_local_user' }
+}
+`
+
+// List implements LocalUserLister.List.
+func (lc *LocalUserClientImpl) List(ctx context.Context, enabledOnly bool) ([]UserState, error) {
+	users, err := lc.listUsers(ctx, "list")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]UserState, 0, len(users))
+	for _, u := range users {
+		if enabledOnly && !u.Enabled {
+			continue
+		}
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out, nil
+}
+
+// listUsers runs psLocalUserListAll and parses each user.
+func (lc *LocalUserClientImpl) listUsers(ctx context.Context, op string) ([]*UserState, error) {
+	resp, err := lc.runLUEnvelope(ctx, op, "*", psLocalUserListAll)
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Users jsonList[json.RawMessage] `json:"users"`
+	}
+	if len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if jerr := json.Unmarshal(resp.Data, &wrapper); jerr != nil {
+			return nil, NewLocalUserError(LocalUserErrorUnknown, "failed to parse LocalUser list", jerr,
+				map[string]string{"operation": op, "host": lc.c.cfg.Host})
+		}
+	}
+	users := make([]*UserState, 0, len(wrapper.Users))
+	for _, raw := range wrapper.Users {
+		st, err := parseUserData(op, raw)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, st)
+	}
+	return users, nil
+}
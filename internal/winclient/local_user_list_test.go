@@ -0,0 +1,74 @@
+// Package winclient — unit tests for LocalUserClientImpl.List.
+package winclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLocalUserList_SortsFiltersAndDecodes(t *testing.T) {
+	var script string
+	disabled := fakeUserData("Guest", "S-1-5-21-1-501")
+	disabled["Enabled"] = false
+	expiring := fakeUserData("bob", "S-1-5-21-1-1002")
+	expiring["AccountExpires"] = "2027-01-31T00:00:00Z"
+	forever := fakeUserData("Alice", "S-1-5-21-1-1001")
+	forever["AccountExpires"] = "2106-02-07T06:28:15Z"
+	defer stubLURun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return luOK(t, map[string]any{"users": []any{expiring, disabled, forever}}), "", nil
+	})()
+	_, lc := newLUClient(t)
+
+	all, err := lc.List(context.Background(), false)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 3 || all[0].Name != "Alice" || all[1].Name != "bob" || all[2].Name != "Guest" {
+		t.Fatalf("List = %+v", all)
+	}
+	if !all[0].AccountNeverExpires || all[0].AccountExpires != "" {
+		t.Errorf("the SAM forever sentinel must read as never expires: %+v", all[0])
+	}
+	if all[1].AccountNeverExpires || all[1].AccountExpires != "2027-01-31T00:00:00Z" || all[1].PasswordLastSet != "2026-01-01T00:00:00Z" {
+		t.Errorf("dates = %+v", all[1])
+	}
+	for _, want := range []string{"Get-LocalUser -ErrorAction Stop", "Get-UserData $_", "function Format-PSDate"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+
+	enabled, err := lc.List(context.Background(), true)
+	if err != nil || len(enabled) != 2 || enabled[0].Name != "Alice" || enabled[1].Name != "bob" {
+		t.Errorf("List(enabledOnly) = %+v, %v", enabled, err)
+	}
+}
+
+func TestLocalUserList_SingleAndNone(t *testing.T) {
+	for name, data := range map[string]any{
+		"collapsed single": map[string]any{"users": fakeUserData("alice", "S-1-5-21-1-1001")},
+		"empty":            map[string]any{"users": nil},
+	} {
+		restore := stubLURun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+			return luOK(t, data), "", nil
+		})
+		_, lc := newLUClient(t)
+		got, err := lc.List(context.Background(), false)
+		restore()
+		if err != nil || got == nil {
+			t.Errorf("%s: List = %+v, %v", name, got, err)
+		}
+	}
+}
+
+func TestLocalUserList_Error(t *testing.T) {
+	defer stubLURun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return luErr(t, "permission_denied", "Access is denied."), "", nil
+	})()
+	_, lc := newLUClient(t)
+	if _, err := lc.List(context.Background(), false); !IsLocalUserError(err, LocalUserErrorPermission) {
+		t.Errorf("err = %v, want permission_denied", err)
+	}
+}
@@ -5,7 +5,7 @@
 //
 // File layout:
 //
-//	LocalUserErrorKind      — string enum of typed error categories (8 kinds)
+//	LocalUserErrorKind      — string enum of typed error categories (9 kinds)
 //	LocalUserError          — structured error type with Kind, Message, Context, Cause
 //	Sentinel errors          — pre-constructed *LocalUserError values for errors.Is
 //	UserInput               — input parameters for Create/Update operations
@@ -57,6 +57,10 @@ const (
 	// Windows-side validation — defence-in-depth (EC-10).
 	LocalUserErrorInvalidName LocalUserErrorKind = "invalid_name"
 
+	// LocalUserErrorGroupNotFound is returned by AddToGroups when a listed
+	// local group does not exist.
+	LocalUserErrorGroupNotFound LocalUserErrorKind = "group_not_found"
+
 	// LocalUserErrorUnknown is the catch-all for unrecognised PowerShell
 	// errors or unexpected WinRM transport failures.
 	LocalUserErrorUnknown LocalUserErrorKind = "unknown"
@@ -98,8 +102,11 @@ func (e *LocalUserError) Error() string {
 // Unwrap returns the underlying cause for errors.As / errors.Is chain walking.
 func (e *LocalUserError) Unwrap() error { return e.Cause }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 func (e *LocalUserError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*LocalUserError)
 	if !ok {
 		return false
@@ -152,6 +159,9 @@ var ErrLocalUserPermission = &LocalUserError{Kind: LocalUserErrorPermission}
 // ErrLocalUserInvalidName is a sentinel for invalid name (EC-10).
 var ErrLocalUserInvalidName = &LocalUserError{Kind: LocalUserErrorInvalidName}
 
+// ErrLocalUserGroupNotFound is a sentinel for a missing group in AddToGroups.
+var ErrLocalUserGroupNotFound = &LocalUserError{Kind: LocalUserErrorGroupNotFound}
+
 // ErrLocalUserUnknown is a sentinel for unexpected errors.
 var ErrLocalUserUnknown = &LocalUserError{Kind: LocalUserErrorUnknown}
 
@@ -224,6 +234,11 @@ type UserState struct {
 	// AccountNeverExpires is true when AccountExpires == null / zero-date.
 	AccountNeverExpires bool
 
+	// Dates below are RFC3339 in UTC. Every Windows "never" sentinel
+	// (FILETIME epoch 1601-01-01 or 12/31/1600 in local time,
+	// DateTime.MinValue/MaxValue, and TIMEQ_FOREVER for AccountExpires) is
+	// normalised to "".
+
 	// AccountExpires is the RFC3339 expiry timestamp, or "" when the account
 	// never expires (AccountNeverExpires == true).
 	AccountExpires string
@@ -279,6 +294,10 @@ type LocalUserClient interface {
 	// password is injected via stdin (never logged, ADR-LU-3, EC-6).
 	SetPassword(ctx context.Context, sid, password string) error
 
+	// SetPasswordExpired sets or clears the "user must change password at
+	// next logon" flag (ADSI PasswordExpired). Set-LocalUser cannot do this.
+	SetPasswordExpired(ctx context.Context, sid string, expired bool) error
+
 	// Enable enables the account via Enable-LocalUser -SID.
 	Enable(ctx context.Context, sid string) error
 
@@ -295,4 +314,27 @@ type LocalUserClient interface {
 
 	// ImportBySID resolves a user by SID string (SID import path).
 	ImportBySID(ctx context.Context, sid string) (*UserState, error)
+
+	// Groups returns the names of the local groups the user is a direct
+	// member of, sorted case-insensitively.
+	Groups(ctx context.Context, sid string) ([]string, error)
+
+	// AddToGroups adds the user to each named local group via
+	// Add-LocalGroupMember. Existing memberships are not an error. Every
+	// group is attempted; failures are reported together, naming each
+	// failing group.
+	AddToGroups(ctx context.Context, sid string, groups []string) error
+
+	// RemoveFromGroups removes the user from each named local group via
+	// Remove-LocalGroupMember. Missing memberships and groups are not an
+	// error; other failures are reported like AddToGroups.
+	RemoveFromGroups(ctx context.Context, sid string, groups []string) error
+}
+
+// LocalUserLister enumerates local user accounts. It is implemented by
+// LocalUserClientImpl and backs the windows_local_users data source.
+type LocalUserLister interface {
+	// List returns every local user, or only the enabled ones, sorted
+	// case-insensitively by name. No user yields an empty, non-nil slice.
+	List(ctx context.Context, enabledOnly bool) ([]UserState, error)
 }
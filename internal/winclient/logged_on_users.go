@@ -0,0 +1,244 @@
+// Package winclient: interactive session enumeration over WinRM.
+//
+// LoggedOnUsersClient is the concrete WindowsLoggedOnUsersClient backing the
+// windows_logged_on_users data source. Sessions are enumerated with
+// `quser.exe` (query user); the logon time column is parsed with the host's
+// current culture and, when that fails, recovered from the earliest
+// interactive Win32_LogonSession associated with the account through
+// Win32_LoggedOnUser.
+//
+// Security invariants:
+//   - The script takes no user input; nothing is interpolated.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time assertion: LoggedOnUsersClient satisfies WindowsLoggedOnUsersClient.
+var _ WindowsLoggedOnUsersClient = (*LoggedOnUsersClient)(nil)
+
+// LoggedOnUsersClient is the PowerShell/WinRM-backed WindowsLoggedOnUsersClient.
+type LoggedOnUsersClient struct {
+	c *Client
+}
+
+// NewLoggedOnUsersClient wraps the given WinRM Client.
+func NewLoggedOnUsersClient(c *Client) *LoggedOnUsersClient { return &LoggedOnUsersClient{c: c} }
+
+// runLoggedOnUsersPowerShell is the package-level indirection used by
+// LoggedOnUsersClient. Tests may override it; production code must not.
+var runLoggedOnUsersPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// loggedOnUsersPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type loggedOnUsersPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// loggedOnSessionPayload is one entry of the "sessions" array emitted by
+// the List script.
+type loggedOnSessionPayload struct {
+	Username  string `json:"username"`
+	SessionID int64  `json:"session_id"`
+	State     string `json:"state"`
+	LogonTime string `json:"logon_time"`
+}
+
+// psLoggedOnUsersHeader prepends Emit-OK/Emit-Err and Classify-LoggedOnUsers.
+const psLoggedOnUsersHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+$WarningPreference     = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Classify-LoggedOnUsers([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied' -or $Msg -match 'Error 0x00000005') { return 'permission_denied' }
+  return 'unknown'
+}
+`
+
+// psListLoggedOnUsers runs quser.exe and projects each row onto
+// {username, session_id, state, logon_time}.
+//
+// quser exits 1 with "No User exists for *" when nobody is logged on; that
+// is reported as an empty list, not an error. Native stderr is captured with
+// 2>&1 under ErrorActionPreference=Continue because Windows PowerShell 5.1
+// turns redirected native stderr into terminating errors under Stop.
+//
+// The sessions array is wrapped in an object so ConvertTo-Json never unrolls
+// a single-element array into a bare object.
+const psListLoggedOnUsers = `
+try {
+  $starts = @{}
+  try {
+    Get-CimInstance -ClassName Win32_LogonSession -Filter 'LogonType = 2 OR LogonType = 10 OR LogonType = 11' -ErrorAction Stop | ForEach-Object {
+      $ls = $_
+      Get-CimAssociatedInstance -InputObject $ls -Association Win32_LoggedOnUser -ErrorAction SilentlyContinue | ForEach-Object {
+        $k = ([string]$_.Name).ToLowerInvariant()
+        if ($ls.StartTime -and (-not $starts.ContainsKey($k) -or $ls.StartTime -lt $starts[$k])) { $starts[$k] = $ls.StartTime }
+      }
+    }
+  } catch { }
+
+  $prev = $ErrorActionPreference
+  $ErrorActionPreference = 'Continue'
+  $raw = @(& quser.exe 2>&1 | ForEach-Object { [string]$_ })
+  $code = $LASTEXITCODE
+  $ErrorActionPreference = $prev
+
+  $text = ($raw -join "` + "`" + `n")
+  if ($code -ne 0 -and (Classify-LoggedOnUsers $text) -eq 'permission_denied') {
+    Emit-Err 'permission_denied' $text @{ exit_code = [string]$code }
+    return
+  }
+
+  $re = '^[ >](?<user>\S+)\s+(?:(?<sess>\S+)\s+)?(?<id>\d+)\s+(?<state>\S+)\s+(?<idle>\S+)\s+(?<logon>.+?)\s*$'
+  $inv = [Globalization.CultureInfo]::InvariantCulture
+  $rows = @()
+  foreach ($l in ($raw | Select-Object -Skip 1)) {
+    if ($l -notmatch $re) { continue }
+    $user  = $Matches['user']
+    $state = $Matches['state']
+    if ($state -eq 'Disc') { $state = 'Disconnected' }
+    $lt = ''
+    $dt = [datetime]::MinValue
+    if ([datetime]::TryParse($Matches['logon'], [Globalization.CultureInfo]::CurrentCulture, [Globalization.DateTimeStyles]::AssumeLocal, [ref]$dt)) {
+      $lt = $dt.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ', $inv)
+    } elseif ($starts.ContainsKey($user.ToLowerInvariant())) {
+      $lt = $starts[$user.ToLowerInvariant()].ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ', $inv)
+    }
+    $rows += [ordered]@{
+      username   = [string]$user
+      session_id = [int64]$Matches['id']
+      state      = [string]$state
+      logon_time = [string]$lt
+    }
+  }
+  Emit-OK ([ordered]@{ sessions = @($rows) })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-LoggedOnUsers $msg) $msg @{}
+}
+`
+
+// runLoggedOnUsersEnvelope executes script (prepended with
+// psLoggedOnUsersHeader) and parses the JSON envelope. Cancellation maps to
+// LoggedOnUsersErrorTimeout; other transport failures to
+// LoggedOnUsersErrorUnknown.
+func (l *LoggedOnUsersClient) runLoggedOnUsersEnvelope(ctx context.Context, op, script string) (*loggedOnUsersPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psLoggedOnUsersHeader + "\n" + script
+	stdout, stderr, err := runLoggedOnUsersPowerShell(ctx, l.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      l.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewLoggedOnUsersError(LoggedOnUsersErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewLoggedOnUsersError(LoggedOnUsersErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewLoggedOnUsersError(LoggedOnUsersErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp loggedOnUsersPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewLoggedOnUsersError(LoggedOnUsersErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewLoggedOnUsersError(mapLoggedOnUsersKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapLoggedOnUsersKind translates a PS-side "kind" string to a typed
+// LoggedOnUsersErrorKind. Unknown values fall through to
+// LoggedOnUsersErrorUnknown.
+func mapLoggedOnUsersKind(k string) LoggedOnUsersErrorKind {
+	switch k {
+	case string(LoggedOnUsersErrorPermission),
+		string(LoggedOnUsersErrorTimeout):
+		return LoggedOnUsersErrorKind(k)
+	default:
+		return LoggedOnUsersErrorUnknown
+	}
+}
+
+// List enumerates interactive sessions. A host with nobody logged on
+// returns an empty, non-nil slice.
+func (l *LoggedOnUsersClient) List(ctx context.Context) ([]LoggedOnSession, error) {
+	resp, err := l.runLoggedOnUsersEnvelope(ctx, "list", psListLoggedOnUsers)
+	if err != nil {
+		return nil, err
+	}
+	var data struct {
+		Sessions json.RawMessage `json:"sessions"`
+	}
+	if len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if jerr := json.Unmarshal(resp.Data, &data); jerr != nil {
+			return nil, NewLoggedOnUsersError(LoggedOnUsersErrorUnknown,
+				"failed to parse session list", jerr,
+				map[string]string{"host": l.c.cfg.Host})
+		}
+	}
+
+	var payload []loggedOnSessionPayload
+	if jerr := json.Unmarshal(normalizeJSONArray(data.Sessions), &payload); jerr != nil {
+		return nil, NewLoggedOnUsersError(LoggedOnUsersErrorUnknown,
+			"failed to parse session list", jerr,
+			map[string]string{"host": l.c.cfg.Host})
+	}
+
+	out := make([]LoggedOnSession, 0, len(payload))
+	for _, p := range payload {
+		out = append(out, LoggedOnSession{
+			Username:  p.Username,
+			SessionID: p.SessionID,
+			State:     p.State,
+			LogonTime: p.LogonTime,
+		})
+	}
+	return out, nil
+}
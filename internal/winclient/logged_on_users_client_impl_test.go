@@ -0,0 +1,213 @@
+// Package winclient — unit tests for LoggedOnUsersClient.
+//
+// These tests stub the package-level seam runLoggedOnUsersPowerShell to
+// inject scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newLouTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := New(Config{
+		Host:     "win01",
+		Username: "u",
+		Password: "p",
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+// stubLouRun replaces runLoggedOnUsersPowerShell for the duration of a test
+// and returns a restore function (typically deferred).
+func stubLouRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runLoggedOnUsersPowerShell
+	runLoggedOnUsersPowerShell = fn
+	return func() { runLoggedOnUsersPowerShell = prev }
+}
+
+func louStdout(t *testing.T, v any) func(context.Context, *Client, string) (string, string, error) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return string(b) + "\n", "", nil
+	}
+}
+
+func TestLoggedOnUsersError_IsAndHelper(t *testing.T) {
+	err := NewLoggedOnUsersError(LoggedOnUsersErrorPermission, "denied", errors.New("inner"), nil)
+	if !errors.Is(err, ErrLoggedOnUsersPermission) {
+		t.Error("errors.Is should match on Kind")
+	}
+	if errors.Is(err, ErrLoggedOnUsersUnknown) {
+		t.Error("errors.Is should not match a different Kind")
+	}
+	if !IsLoggedOnUsersError(err, LoggedOnUsersErrorPermission) {
+		t.Error("IsLoggedOnUsersError should match")
+	}
+	if !strings.Contains(err.Error(), "inner") {
+		t.Errorf("Error() should include cause, got %q", err.Error())
+	}
+	if errors.Unwrap(err).Error() != "inner" {
+		t.Error("Unwrap should return cause")
+	}
+}
+
+func TestMapLoggedOnUsersKind(t *testing.T) {
+	cases := map[string]LoggedOnUsersErrorKind{
+		"permission_denied": LoggedOnUsersErrorPermission,
+		"timeout":           LoggedOnUsersErrorTimeout,
+		"bogus":             LoggedOnUsersErrorUnknown,
+		"":                  LoggedOnUsersErrorUnknown,
+	}
+	for in, want := range cases {
+		if got := mapLoggedOnUsersKind(in); got != want {
+			t.Errorf("mapLoggedOnUsersKind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoggedOnUsersList_Empty(t *testing.T) {
+	defer stubLouRun(louStdout(t, map[string]any{"ok": true, "data": map[string]any{"sessions": []any{}}}))()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	got, err := l.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestLoggedOnUsersList_NullSessions(t *testing.T) {
+	defer stubLouRun(louStdout(t, map[string]any{"ok": true, "data": map[string]any{"sessions": nil}}))()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	got, err := l.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestLoggedOnUsersList_SingleObject(t *testing.T) {
+	defer stubLouRun(louStdout(t, map[string]any{"ok": true, "data": map[string]any{
+		"sessions": map[string]any{"username": "administrator", "session_id": 1, "state": "Active", "logon_time": "2026-10-17T08:15:00Z"},
+	}}))()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	got, err := l.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := LoggedOnSession{Username: "administrator", SessionID: 1, State: "Active", LogonTime: "2026-10-17T08:15:00Z"}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got %#v, want [%#v]", got, want)
+	}
+}
+
+func TestLoggedOnUsersList_Many(t *testing.T) {
+	defer stubLouRun(louStdout(t, map[string]any{"ok": true, "data": map[string]any{
+		"sessions": []map[string]any{
+			{"username": "administrator", "session_id": 1, "state": "Active", "logon_time": "2026-10-17T08:15:00Z"},
+			{"username": "bob", "session_id": 3, "state": "Disconnected", "logon_time": ""},
+		},
+	}}))()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	got, err := l.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(got))
+	}
+	if got[1].Username != "bob" || got[1].SessionID != 3 || got[1].State != "Disconnected" || got[1].LogonTime != "" {
+		t.Errorf("unexpected second session: %#v", got[1])
+	}
+}
+
+func TestLoggedOnUsersList_PermissionDenied(t *testing.T) {
+	defer stubLouRun(louStdout(t, map[string]any{
+		"ok": false, "kind": "permission_denied", "message": "Error 0x00000005 enumerating sessionnames",
+		"context": map[string]string{"exit_code": "1"},
+	}))()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	_, err := l.List(context.Background())
+	if !IsLoggedOnUsersError(err, LoggedOnUsersErrorPermission) {
+		t.Fatalf("expected permission_denied, got %v", err)
+	}
+	var le *LoggedOnUsersError
+	errors.As(err, &le)
+	if le.Context["exit_code"] != "1" || le.Context["host"] != "win01" {
+		t.Errorf("context should merge PS and base keys, got %v", le.Context)
+	}
+}
+
+func TestLoggedOnUsersList_Timeout(t *testing.T) {
+	defer stubLouRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.Canceled
+	})()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	_, err := l.List(ctx)
+	if !IsLoggedOnUsersError(err, LoggedOnUsersErrorTimeout) {
+		t.Errorf("expected timeout on cancelled ctx, got %v", err)
+	}
+}
+
+func TestLoggedOnUsersList_TransportError(t *testing.T) {
+	defer stubLouRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "junk-out", "junk-err", errors.New("winrm: tcp reset")
+	})()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	_, err := l.List(context.Background())
+	if !IsLoggedOnUsersError(err, LoggedOnUsersErrorUnknown) {
+		t.Fatalf("expected unknown on transport error, got %v", err)
+	}
+	var le *LoggedOnUsersError
+	errors.As(err, &le)
+	if le.Context["stderr"] != "junk-err" {
+		t.Errorf("context[stderr] = %q", le.Context["stderr"])
+	}
+}
+
+func TestLoggedOnUsersList_NoJSON(t *testing.T) {
+	defer stubLouRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "WARNING: nothing here\n", "", nil
+	})()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	_, err := l.List(context.Background())
+	if !IsLoggedOnUsersError(err, LoggedOnUsersErrorUnknown) {
+		t.Errorf("missing JSON envelope should yield unknown, got %v", err)
+	}
+}
+
+func TestLoggedOnUsersList_ScriptShape(t *testing.T) {
+	var captured string
+	defer stubLouRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		captured = script
+		return `{"ok":true,"data":{"sessions":[]}}` + "\n", "", nil
+	})()
+	l := NewLoggedOnUsersClient(newLouTestClient(t))
+	if _, err := l.List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"quser.exe", "Win32_LoggedOnUser", "Emit-OK", "'Disconnected'"} {
+		if !strings.Contains(captured, want) {
+			t.Errorf("script should contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package winclient: types for the windows_logged_on_users data source.
+//
+// LoggedOnSession is one interactive session reported by `quser.exe`
+// (query user). LoggedOnUsersErrorKind / LoggedOnUsersError follow the same
+// shape as FeatureError so the data source layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// LoggedOnUsersErrorKind categorises errors returned by
+// WindowsLoggedOnUsersClient.
+type LoggedOnUsersErrorKind string
+
+const (
+	LoggedOnUsersErrorPermission LoggedOnUsersErrorKind = "permission_denied"
+	LoggedOnUsersErrorTimeout    LoggedOnUsersErrorKind = "timeout"
+	LoggedOnUsersErrorUnknown    LoggedOnUsersErrorKind = "unknown"
+)
+
+// LoggedOnUsersError is the structured error type returned by
+// WindowsLoggedOnUsersClient.
+type LoggedOnUsersError struct {
+	Kind    LoggedOnUsersErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *LoggedOnUsersError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_logged_on_users [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_logged_on_users [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *LoggedOnUsersError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *LoggedOnUsersError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*LoggedOnUsersError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewLoggedOnUsersError constructs a *LoggedOnUsersError.
+func NewLoggedOnUsersError(kind LoggedOnUsersErrorKind, msg string, cause error, ctx map[string]string) *LoggedOnUsersError {
+	return &LoggedOnUsersError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsLoggedOnUsersError reports whether err is a *LoggedOnUsersError of the
+// given kind.
+func IsLoggedOnUsersError(err error, kind LoggedOnUsersErrorKind) bool {
+	var le *LoggedOnUsersError
+	if errors.As(err, &le) {
+		return le.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrLoggedOnUsersPermission = &LoggedOnUsersError{Kind: LoggedOnUsersErrorPermission}
+	ErrLoggedOnUsersTimeout    = &LoggedOnUsersError{Kind: LoggedOnUsersErrorTimeout}
+	ErrLoggedOnUsersUnknown    = &LoggedOnUsersError{Kind: LoggedOnUsersErrorUnknown}
+)
+
+// LoggedOnSession is one interactive (console or RDP) session on the host.
+type LoggedOnSession struct {
+	// Username is the account name as reported by quser (no domain prefix).
+	Username string
+	// SessionID is the Terminal Services session ID.
+	SessionID int64
+	// State is "Active", "Disconnected" or the raw quser state token for
+	// any other (rare) value.
+	State string
+	// LogonTime is the session logon time in UTC RFC3339 ("" when the host
+	// did not report a parseable time).
+	LogonTime string
+}
+
+// WindowsLoggedOnUsersClient is the contract for the windows_logged_on_users
+// data source.
+type WindowsLoggedOnUsersClient interface {
+	// List returns every interactive session on the host. A host with no
+	// interactive sessions yields an empty, non-nil slice and a nil error.
+	List(ctx context.Context) ([]LoggedOnSession, error)
+}
@@ -0,0 +1,449 @@
+// Package winclient: static IPv4 address management over WinRM.
+//
+// NetIPAddressClient is the concrete WindowsNetIPAddressClient backing the
+// windows_network_adapter_ip resource. It wraps the NetTCPIP cmdlets
+// (Get/New/Set/Remove-NetIPAddress, Get/New/Remove-NetRoute for the default
+// gateway).
+//
+// Changing the address the provider is connected through removes that
+// address mid-command, so the WinRM connection can drop before the envelope
+// arrives. Each change therefore runs as a single script that the host
+// finishes on its own, and the disruptive step (removing the old address or
+// disabling DHCP) is ordered as late as possible. A dropped connection is
+// not treated as a failure when a reconnect host is given: the outcome is
+// confirmed by polling Read through a second Client pointed at that host,
+// as reboot.go does for a restarting host. Without one, the drop is
+// reported as NetIPAddressErrorConnectionLost.
+//
+// Security invariants:
+//   - interface alias, addresses and gateway are interpolated only through
+//     psQuote.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Compile-time assertion: NetIPAddressClient satisfies WindowsNetIPAddressClient.
+var _ WindowsNetIPAddressClient = (*NetIPAddressClient)(nil)
+
+// NetIPAddressClient is the PowerShell/WinRM-backed WindowsNetIPAddressClient.
+type NetIPAddressClient struct {
+	c *Client
+}
+
+// NewNetIPAddressClient wraps the given WinRM Client.
+func NewNetIPAddressClient(c *Client) *NetIPAddressClient { return &NetIPAddressClient{c: c} }
+
+// runNetIPPowerShell is the package-level indirection used by
+// NetIPAddressClient. Tests may override it; production code must not.
+var runNetIPPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// netIPReconnectPollInterval is the delay between confirmation reads through
+// the reconnect host. Tests may shorten it.
+var netIPReconnectPollInterval = 5 * time.Second
+
+// netIPPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type netIPPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// netIPPayload mirrors Get-NetIPState.
+type netIPPayload struct {
+	InterfaceAlias  string           `json:"interface_alias"`
+	InterfaceIndex  int64            `json:"interface_index"`
+	IPAddress       string           `json:"ip_address"`
+	PrefixLength    int64            `json:"prefix_length"`
+	PrefixOrigin    string           `json:"prefix_origin"`
+	DefaultGateways jsonList[string] `json:"default_gateways"`
+}
+
+// netIPReadPayload wraps the (possibly null) address returned by Read and
+// the change scripts.
+type netIPReadPayload struct {
+	Address *netIPPayload `json:"address"`
+}
+
+// psNetIPHeader prepends Emit-OK/Emit-Err, Classify-NetIP and the shared
+// helpers.
+//
+// Get-NetIPState returns $null when the address or the interface does not
+// exist (both surface as ObjectNotFound). Set-NetIPGateway makes $New the
+// only default route of the interface; with $New empty it removes only the
+// route through $Old, leaving routes it does not manage alone.
+const psNetIPHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 6 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 6 -Compress))
+}
+function Classify-NetIP([string]$Msg, [string]$Fq) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied' -or $Fq -match 'PermissionDenied') { return 'permission_denied' }
+  if ($Fq -match 'NotFound_InterfaceAlias' -or $Msg -match 'No MSFT_NetIPInterface objects found') { return 'interface_not_found' }
+  if ($Msg -match 'already exists') { return 'already_exists' }
+  if ($Msg -match 'Inconsistent parameters' -or $Msg -match 'parameter is incorrect' -or $Msg -match 'is not a valid') { return 'invalid_parameter' }
+  if ($Fq -match 'NotFound' -or $Msg -match 'No matching') { return 'not_found' }
+  return 'unknown'
+}
+
+function Get-NetIPState([string]$Alias, [string]$IP) {
+  try {
+    $a = Get-NetIPAddress -InterfaceAlias $Alias -IPAddress $IP -AddressFamily IPv4 -ErrorAction Stop | Select-Object -First 1
+  } catch {
+    if ([string]$_.CategoryInfo.Category -eq 'ObjectNotFound') { return $null }
+    throw
+  }
+  $gws = @(Get-NetRoute -InterfaceAlias $Alias -DestinationPrefix '0.0.0.0/0' -AddressFamily IPv4 -ErrorAction SilentlyContinue |
+    Sort-Object RouteMetric | ForEach-Object { [string]$_.NextHop } | Select-Object -Unique)
+  [ordered]@{
+    interface_alias  = [string]$a.InterfaceAlias
+    interface_index  = [int64]$a.InterfaceIndex
+    ip_address       = [string]$a.IPAddress
+    prefix_length    = [int64]$a.PrefixLength
+    prefix_origin    = [string]$a.PrefixOrigin
+    default_gateways = @($gws)
+  }
+}
+
+function Set-NetIPGateway([string]$Alias, [string]$Old, [string]$New) {
+  $hops = @(Get-NetRoute -InterfaceAlias $Alias -DestinationPrefix '0.0.0.0/0' -AddressFamily IPv4 -ErrorAction SilentlyContinue |
+    ForEach-Object { [string]$_.NextHop } | Select-Object -Unique)
+  foreach ($h in $hops) {
+    if ($New -and $h -eq $New) { continue }
+    if ($New -or $h -eq $Old) {
+      Remove-NetRoute -InterfaceAlias $Alias -DestinationPrefix '0.0.0.0/0' -NextHop $h -Confirm:$false -ErrorAction SilentlyContinue
+    }
+  }
+  if ($New -and ($hops -notcontains $New)) {
+    New-NetRoute -InterfaceAlias $Alias -DestinationPrefix '0.0.0.0/0' -AddressFamily IPv4 -NextHop $New -ErrorAction Stop | Out-Null
+  }
+}
+
+function Remove-NetIPIfPresent([string]$Alias, [string]$IP) {
+  try {
+    Remove-NetIPAddress -InterfaceAlias $Alias -IPAddress $IP -AddressFamily IPv4 -Confirm:$false -ErrorAction Stop
+  } catch {
+    if ([string]$_.CategoryInfo.Category -ne 'ObjectNotFound') { throw }
+  }
+}
+`
+
+// psNetIPRead reads one address. Format args: alias, ip.
+const psNetIPRead = `
+try {
+  Emit-OK ([ordered]@{ address = (Get-NetIPState %s %s) })
+} catch {
+  Emit-Err (Classify-NetIP $_.Exception.Message ([string]$_.FullyQualifiedErrorId)) $_.Exception.Message @{}
+}
+`
+
+// psNetIPCreate assigns a static address. DHCP has to be disabled first:
+// New-NetIPAddress rejects a DHCP-enabled interface with "Inconsistent
+// parameters". Format args: alias, ip, prefix length, gateway.
+const psNetIPCreate = `
+$alias = %s; $ip = %s; $len = %d; $gw = %s
+try {
+  $if = Get-NetIPInterface -InterfaceAlias $alias -AddressFamily IPv4 -ErrorAction Stop
+} catch {
+  Emit-Err 'interface_not_found' $_.Exception.Message @{ interface_alias = $alias }
+  return
+}
+try {
+  if (Get-NetIPState $alias $ip) {
+    Emit-Err 'already_exists' ("$ip is already assigned to $alias; import it instead of creating it.") @{ interface_alias = $alias; ip_address = $ip }
+    return
+  }
+  if ([string]$if.Dhcp -eq 'Enabled') { Set-NetIPInterface -InterfaceAlias $alias -AddressFamily IPv4 -Dhcp Disabled -ErrorAction Stop }
+  New-NetIPAddress -InterfaceAlias $alias -IPAddress $ip -PrefixLength $len -AddressFamily IPv4 -ErrorAction Stop | Out-Null
+  if ($gw) { Set-NetIPGateway $alias '' $gw }
+  Emit-OK ([ordered]@{ address = (Get-NetIPState $alias $ip) })
+} catch {
+  Emit-Err (Classify-NetIP $_.Exception.Message ([string]$_.FullyQualifiedErrorId)) $_.Exception.Message @{ interface_alias = $alias; ip_address = $ip }
+}
+`
+
+// psNetIPUpdate moves the interface from the prior configuration to the new
+// one. The new address is added first and the old one removed last, so the
+// host stays reachable on one of them throughout. Format args: alias, old
+// ip, new ip, prefix length, old gateway, new gateway.
+const psNetIPUpdate = `
+$alias = %s; $old = %s; $ip = %s; $len = %d; $oldgw = %s; $gw = %s
+try {
+  $cur = Get-NetIPState $alias $ip
+  if (-not $cur) {
+    if ($ip -eq $old) {
+      Emit-Err 'not_found' ("$ip is no longer assigned to $alias") @{ interface_alias = $alias; ip_address = $ip }
+      return
+    }
+    New-NetIPAddress -InterfaceAlias $alias -IPAddress $ip -PrefixLength $len -AddressFamily IPv4 -ErrorAction Stop | Out-Null
+  } elseif ($cur.prefix_length -ne $len) {
+    Set-NetIPAddress -InterfaceAlias $alias -IPAddress $ip -PrefixLength $len -ErrorAction Stop
+  }
+  if ($gw -or $oldgw) { Set-NetIPGateway $alias $oldgw $gw }
+  if ($ip -ne $old) { Remove-NetIPIfPresent $alias $old }
+  Emit-OK ([ordered]@{ address = (Get-NetIPState $alias $ip) })
+} catch {
+  Emit-Err (Classify-NetIP $_.Exception.Message ([string]$_.FullyQualifiedErrorId)) $_.Exception.Message @{ interface_alias = $alias; ip_address = $ip }
+}
+`
+
+// psNetIPDelete removes the managed default route, then the address. DHCP is
+// not re-enabled. Format args: alias, ip, gateway.
+const psNetIPDelete = `
+$alias = %s; $ip = %s; $gw = %s
+try {
+  if ($gw) { Set-NetIPGateway $alias $gw '' }
+  Remove-NetIPIfPresent $alias $ip
+  Emit-OK ([ordered]@{ removed = $true })
+} catch {
+  Emit-Err (Classify-NetIP $_.Exception.Message ([string]$_.FullyQualifiedErrorId)) $_.Exception.Message @{ interface_alias = $alias; ip_address = $ip }
+}
+`
+
+// runNetIPEnvelope executes script (prepended with psNetIPHeader) and parses
+// the JSON envelope. Cancellation maps to NetIPAddressErrorTimeout; other
+// transport failures to NetIPAddressErrorUnknown wrapping the
+// *TransportError, which connectionDropped inspects.
+func (n *NetIPAddressClient) runNetIPEnvelope(ctx context.Context, op, ip, script string) (*netIPPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psNetIPHeader + "\n" + script
+	stdout, stderr, err := runNetIPPowerShell(ctx, n.c, full)
+
+	baseCtx := map[string]string{
+		"operation":  op,
+		"ip_address": ip,
+		"host":       n.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewNetIPAddressError(NetIPAddressErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewNetIPAddressError(NetIPAddressErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, withExitCode(baseCtx, err))
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewNetIPAddressError(NetIPAddressErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp netIPPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewNetIPAddressError(NetIPAddressErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewNetIPAddressError(mapNetIPKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapNetIPKind translates a PS-side "kind" string to a typed
+// NetIPAddressErrorKind. Unknown values fall through to
+// NetIPAddressErrorUnknown.
+func mapNetIPKind(k string) NetIPAddressErrorKind {
+	switch k {
+	case string(NetIPAddressErrorNotFound),
+		string(NetIPAddressErrorInterfaceNotFound),
+		string(NetIPAddressErrorAlreadyExists),
+		string(NetIPAddressErrorInvalidParameter),
+		string(NetIPAddressErrorPermission):
+		return NetIPAddressErrorKind(k)
+	default:
+		return NetIPAddressErrorUnknown
+	}
+}
+
+// decodeNetIPState parses a netIPReadPayload; a null address yields nil.
+func (n *NetIPAddressClient) decodeNetIPState(op string, data json.RawMessage) (*NetIPAddressState, error) {
+	var p netIPReadPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, NewNetIPAddressError(NetIPAddressErrorUnknown, "failed to parse IP address state", err,
+			map[string]string{"operation": op, "host": n.c.cfg.Host})
+	}
+	if p.Address == nil {
+		return nil, nil
+	}
+	return &NetIPAddressState{
+		InterfaceAlias:  p.Address.InterfaceAlias,
+		InterfaceIndex:  p.Address.InterfaceIndex,
+		IPAddress:       p.Address.IPAddress,
+		PrefixLength:    p.Address.PrefixLength,
+		PrefixOrigin:    p.Address.PrefixOrigin,
+		DefaultGateways: []string(p.Address.DefaultGateways),
+	}, nil
+}
+
+// Read implements WindowsNetIPAddressClient.Read.
+func (n *NetIPAddressClient) Read(ctx context.Context, alias, ip string) (*NetIPAddressState, error) {
+	resp, err := n.runNetIPEnvelope(ctx, "read", ip, fmt.Sprintf(psNetIPRead, psQuote(alias), psQuote(ip)))
+	if err != nil {
+		return nil, err
+	}
+	return n.decodeNetIPState("read", resp.Data)
+}
+
+// Create implements WindowsNetIPAddressClient.Create.
+func (n *NetIPAddressClient) Create(ctx context.Context, in NetIPAddressInput) (*NetIPAddressState, error) {
+	script := fmt.Sprintf(psNetIPCreate, psQuote(in.InterfaceAlias), psQuote(in.IPAddress), in.PrefixLength, psQuote(in.DefaultGateway))
+	resp, err := n.runNetIPEnvelope(ctx, "create", in.IPAddress, script)
+	if err != nil {
+		if connectionDropped(ctx, err) {
+			return n.confirm(ctx, "create", in, err, "")
+		}
+		return nil, err
+	}
+	return n.decodeNetIPState("create", resp.Data)
+}
+
+// Update implements WindowsNetIPAddressClient.Update.
+func (n *NetIPAddressClient) Update(ctx context.Context, prior, in NetIPAddressInput) (*NetIPAddressState, error) {
+	script := fmt.Sprintf(psNetIPUpdate, psQuote(in.InterfaceAlias), psQuote(prior.IPAddress), psQuote(in.IPAddress),
+		in.PrefixLength, psQuote(prior.DefaultGateway), psQuote(in.DefaultGateway))
+	resp, err := n.runNetIPEnvelope(ctx, "update", in.IPAddress, script)
+	if err != nil {
+		if connectionDropped(ctx, err) {
+			gone := ""
+			if prior.IPAddress != in.IPAddress {
+				gone = prior.IPAddress
+			}
+			return n.confirm(ctx, "update", in, err, gone)
+		}
+		return nil, err
+	}
+	return n.decodeNetIPState("update", resp.Data)
+}
+
+// Delete implements WindowsNetIPAddressClient.Delete.
+func (n *NetIPAddressClient) Delete(ctx context.Context, in NetIPAddressInput) error {
+	script := fmt.Sprintf(psNetIPDelete, psQuote(in.InterfaceAlias), psQuote(in.IPAddress), psQuote(in.DefaultGateway))
+	_, err := n.runNetIPEnvelope(ctx, "delete", in.IPAddress, script)
+	if err != nil && connectionDropped(ctx, err) {
+		_, err = n.confirm(ctx, "delete", NetIPAddressInput{
+			InterfaceAlias: in.InterfaceAlias,
+			ReconnectHost:  in.ReconnectHost,
+		}, err, in.IPAddress)
+	}
+	return err
+}
+
+// connectionDropped reports whether err is the WinRM connection failing
+// while a change was applied, as opposed to the change being refused:
+// transport failures other than rejected credentials that did not come with
+// a script exit code. Cancellation is never a drop.
+func connectionDropped(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	kind := TransportFailureKind(err)
+	return kind != "" && kind != FailureAuth && CommandExitCode(err) == 0
+}
+
+// confirm decides the outcome of a change whose connection dropped by
+// polling through in.ReconnectHost until the host reports the desired state:
+// in.IPAddress present with in.PrefixLength and, when set, in.DefaultGateway
+// (skipped when in.IPAddress is empty), and gone absent (skipped when
+// empty). It gives up when ctx ends.
+func (n *NetIPAddressClient) confirm(ctx context.Context, op string, in NetIPAddressInput, dropErr error, gone string) (*NetIPAddressState, error) {
+	errCtx := map[string]string{"operation": op, "ip_address": in.IPAddress, "host": n.c.cfg.Host}
+	if in.ReconnectHost == "" {
+		return nil, NewNetIPAddressError(NetIPAddressErrorConnectionLost,
+			fmt.Sprintf("the WinRM connection to %s dropped while %q was applied, so the result is unknown. "+
+				"Set reconnect_host to the address the host is reachable on after the change", n.c.cfg.Host, op),
+			dropErr, errCtx)
+	}
+	cfg := n.c.cfg
+	cfg.Host = in.ReconnectHost
+	rc, err := New(cfg)
+	if err != nil {
+		return nil, NewNetIPAddressError(NetIPAddressErrorInvalidParameter,
+			fmt.Sprintf("invalid reconnect host %q", in.ReconnectHost), err, errCtx)
+	}
+	r := &NetIPAddressClient{c: rc}
+	errCtx["reconnect_host"] = in.ReconnectHost
+
+	lastErr := dropErr
+	for {
+		st, ok, err := r.confirmed(ctx, in, gone)
+		switch {
+		case err == nil && ok:
+			return st, nil
+		case AuthFailureLatched(err):
+			return nil, err
+		case err != nil && ctx.Err() == nil:
+			lastErr = err
+		}
+
+		t := time.NewTimer(netIPReconnectPollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil, NewNetIPAddressError(NetIPAddressErrorConnectionLost,
+					fmt.Sprintf("waiting for the host on %s was cancelled", in.ReconnectHost), ctx.Err(), errCtx)
+			}
+			return nil, NewNetIPAddressError(NetIPAddressErrorConnectionLost,
+				fmt.Sprintf("the WinRM connection dropped while %q was applied, and the change could not be "+
+					"confirmed through %s before the timeout", op, in.ReconnectHost), lastErr, errCtx)
+		case <-t.C:
+		}
+	}
+}
+
+// confirmed reads the desired and the removed address once; see confirm.
+func (n *NetIPAddressClient) confirmed(ctx context.Context, in NetIPAddressInput, gone string) (*NetIPAddressState, bool, error) {
+	if gone != "" {
+		old, err := n.Read(ctx, in.InterfaceAlias, gone)
+		if err != nil || old != nil {
+			return nil, false, err
+		}
+	}
+	if in.IPAddress == "" {
+		return nil, true, nil
+	}
+	st, err := n.Read(ctx, in.InterfaceAlias, in.IPAddress)
+	if err != nil || st == nil || st.PrefixLength != in.PrefixLength {
+		return nil, false, err
+	}
+	if in.DefaultGateway != "" && !slices.Contains(st.DefaultGateways, in.DefaultGateway) {
+		return nil, false, nil
+	}
+	return st, true, nil
+}
@@ -0,0 +1,194 @@
+// Package winclient — unit tests for NetIPAddressClient.
+//
+// Tests stub the package-level runNetIPPowerShell hook and shorten
+// netIPReconnectPollInterval. The stub sees which Client a script runs on,
+// so runs through the reconnect host are told apart by c.cfg.Host.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubNetIPRun replaces runNetIPPowerShell and netIPReconnectPollInterval for
+// the duration of a test.
+func stubNetIPRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prevRun, prevInterval := runNetIPPowerShell, netIPReconnectPollInterval
+	runNetIPPowerShell, netIPReconnectPollInterval = fn, time.Millisecond
+	return func() { runNetIPPowerShell, netIPReconnectPollInterval = prevRun, prevInterval }
+}
+
+func netIPAddr(ip string, prefix int, gateways ...string) map[string]any {
+	return map[string]any{"address": map[string]any{
+		"interface_alias": "Ethernet", "interface_index": 4, "ip_address": ip,
+		"prefix_length": prefix, "prefix_origin": "Manual", "default_gateways": gateways,
+	}}
+}
+
+var netIPDrop = &TransportError{Kind: FailureCommand, Err: io.ErrUnexpectedEOF}
+
+func TestNetIPRead(t *testing.T) {
+	defer stubNetIPRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if strings.Contains(s, "Get-NetIPState 'Ethernet' '10.0.0.5'") {
+			return okEnvelope(t, netIPAddr("10.0.0.5", 24, "10.0.0.1")), "", nil
+		}
+		return okEnvelope(t, map[string]any{"address": nil}), "", nil
+	})()
+
+	n := NewNetIPAddressClient(newTestClient(t))
+	st, err := n.Read(context.Background(), "Ethernet", "10.0.0.5")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if st.InterfaceIndex != 4 || st.PrefixLength != 24 || len(st.DefaultGateways) != 1 || st.DefaultGateways[0] != "10.0.0.1" {
+		t.Errorf("Read = %+v", st)
+	}
+	if st, err := n.Read(context.Background(), "Ethernet", "10.0.0.9"); st != nil || err != nil {
+		t.Errorf("absent address: %+v, %v; want nil, nil", st, err)
+	}
+}
+
+func TestNetIPCreate(t *testing.T) {
+	var script string
+	defer stubNetIPRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return okEnvelope(t, netIPAddr("10.0.0.5", 24, "10.0.0.1")), "", nil
+	})()
+
+	st, err := NewNetIPAddressClient(newTestClient(t)).Create(context.Background(), NetIPAddressInput{
+		InterfaceAlias: "Ethernet", IPAddress: "10.0.0.5", PrefixLength: 24, DefaultGateway: "10.0.0.1",
+	})
+	if err != nil || st == nil || st.IPAddress != "10.0.0.5" {
+		t.Fatalf("Create = %+v, %v", st, err)
+	}
+	for _, want := range []string{"$alias = 'Ethernet'; $ip = '10.0.0.5'; $len = 24; $gw = '10.0.0.1'", "-Dhcp Disabled", "New-NetIPAddress"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestNetIPCreate_ErrorKinds(t *testing.T) {
+	defer stubNetIPRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return errEnvelope(t, "already_exists", "10.0.0.5 is already assigned to Ethernet"), "", nil
+	})()
+	_, err := NewNetIPAddressClient(newTestClient(t)).Create(context.Background(), NetIPAddressInput{
+		InterfaceAlias: "Ethernet", IPAddress: "10.0.0.5", PrefixLength: 24,
+	})
+	if !errors.Is(err, ErrNetIPAddressAlreadyExists) {
+		t.Errorf("err = %v, want already_exists", err)
+	}
+}
+
+func TestNetIPUpdate_DropWithoutReconnectHost(t *testing.T) {
+	defer stubNetIPRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", netIPDrop
+	})()
+	_, err := NewNetIPAddressClient(newTestClient(t)).Update(context.Background(),
+		NetIPAddressInput{InterfaceAlias: "Ethernet", IPAddress: "10.0.0.5", PrefixLength: 24},
+		NetIPAddressInput{InterfaceAlias: "Ethernet", IPAddress: "10.0.0.6", PrefixLength: 24})
+	if !IsNetIPAddressError(err, NetIPAddressErrorConnectionLost) || !strings.Contains(err.Error(), "reconnect_host") {
+		t.Errorf("err = %v, want connection_lost naming reconnect_host", err)
+	}
+}
+
+func TestNetIPUpdate_DropConfirmedThroughReconnectHost(t *testing.T) {
+	var hosts []string
+	polls := 0
+	defer stubNetIPRun(func(_ context.Context, c *Client, s string) (string, string, error) {
+		hosts = append(hosts, c.cfg.Host)
+		switch {
+		case strings.Contains(s, "Remove-NetIPIfPresent $alias $old"):
+			return "", "", netIPDrop
+		case strings.Contains(s, "Get-NetIPState 'Ethernet' '10.0.0.5'"):
+			// The old address lingers for one poll.
+			polls++
+			if polls == 1 {
+				return okEnvelope(t, netIPAddr("10.0.0.5", 24)), "", nil
+			}
+			return okEnvelope(t, map[string]any{"address": nil}), "", nil
+		default:
+			return okEnvelope(t, netIPAddr("10.0.0.6", 24, "10.0.0.1")), "", nil
+		}
+	})()
+
+	st, err := NewNetIPAddressClient(newTestClient(t)).Update(context.Background(),
+		NetIPAddressInput{InterfaceAlias: "Ethernet", IPAddress: "10.0.0.5", PrefixLength: 24},
+		NetIPAddressInput{InterfaceAlias: "Ethernet", IPAddress: "10.0.0.6", PrefixLength: 24,
+			DefaultGateway: "10.0.0.1", ReconnectHost: "10.0.0.6"})
+	if err != nil || st == nil || st.IPAddress != "10.0.0.6" {
+		t.Fatalf("Update = %+v, %v", st, err)
+	}
+	if hosts[0] != "localhost" {
+		t.Errorf("change ran on %q, want the provider host", hosts[0])
+	}
+	for _, h := range hosts[1:] {
+		if h != "10.0.0.6" {
+			t.Errorf("confirmation read ran on %q, want the reconnect host", h)
+		}
+	}
+	if polls != 2 {
+		t.Errorf("old address polled %d times, want 2", polls)
+	}
+}
+
+func TestNetIPCreate_DropConfirmTimesOut(t *testing.T) {
+	defer stubNetIPRun(func(_ context.Context, c *Client, _ string) (string, string, error) {
+		if c.cfg.Host == "localhost" {
+			return "", "", netIPDrop
+		}
+		return "", "", &TransportError{Kind: FailureDial, Err: errors.New("connection refused")}
+	})()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := NewNetIPAddressClient(newTestClient(t)).Create(ctx, NetIPAddressInput{
+		InterfaceAlias: "Ethernet", IPAddress: "10.0.0.6", PrefixLength: 24, ReconnectHost: "10.0.0.6",
+	})
+	if !IsNetIPAddressError(err, NetIPAddressErrorConnectionLost) || TransportFailureKind(err) != FailureDial {
+		t.Errorf("err = %v, want connection_lost wrapping the last dial failure", err)
+	}
+}
+
+func TestNetIPDelete_DropConfirmed(t *testing.T) {
+	defer stubNetIPRun(func(_ context.Context, c *Client, s string) (string, string, error) {
+		if strings.Contains(s, "Remove-NetIPIfPresent $alias $ip") {
+			return "", "", netIPDrop
+		}
+		return okEnvelope(t, map[string]any{"address": nil}), "", nil
+	})()
+	err := NewNetIPAddressClient(newTestClient(t)).Delete(context.Background(), NetIPAddressInput{
+		InterfaceAlias: "Ethernet", IPAddress: "10.0.0.5", ReconnectHost: "10.0.0.6",
+	})
+	if err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+}
+
+func TestNetIPConnectionDropped(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"eof":         {netIPDrop, true},
+		"dial":        {&TransportError{Kind: FailureDial, Err: errors.New("i/o timeout")}, true},
+		"auth":        {&TransportError{Kind: FailureAuth, Err: errors.New("401")}, false},
+		"script exit": {&TransportError{Kind: FailureCommand, ExitCode: 1, Err: errors.New("exit 1")}, false},
+		"envelope":    {NewNetIPAddressError(NetIPAddressErrorPermission, "Access is denied", nil, nil), false},
+	}
+	for name, tc := range cases {
+		if got := connectionDropped(ctx, tc.err); got != tc.want {
+			t.Errorf("%s: connectionDropped = %v, want %v", name, got, tc.want)
+		}
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if connectionDropped(cancelled, netIPDrop) {
+		t.Error("a cancelled context is never a dropped connection")
+	}
+}
@@ -0,0 +1,139 @@
+// Package winclient: types for the windows_network_adapter_ip resource.
+//
+// NetIPAddressState is the observed state returned by Read;
+// NetIPAddressInput carries the desired configuration. NetIPAddressErrorKind /
+// NetIPAddressError follow the same shape as FeatureError so the resource
+// layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NetIPAddressErrorKind categorises errors returned by
+// WindowsNetIPAddressClient.
+type NetIPAddressErrorKind string
+
+const (
+	NetIPAddressErrorNotFound          NetIPAddressErrorKind = "not_found"
+	NetIPAddressErrorInterfaceNotFound NetIPAddressErrorKind = "interface_not_found"
+	NetIPAddressErrorAlreadyExists     NetIPAddressErrorKind = "already_exists"
+	NetIPAddressErrorInvalidParameter  NetIPAddressErrorKind = "invalid_parameter"
+	NetIPAddressErrorPermission        NetIPAddressErrorKind = "permission_denied"
+	// NetIPAddressErrorConnectionLost means the WinRM connection dropped while
+	// the change was applied, and the outcome could not be confirmed.
+	NetIPAddressErrorConnectionLost NetIPAddressErrorKind = "connection_lost"
+	NetIPAddressErrorTimeout        NetIPAddressErrorKind = "timeout"
+	NetIPAddressErrorUnknown        NetIPAddressErrorKind = "unknown"
+)
+
+// NetIPAddressError is the structured error type returned by
+// WindowsNetIPAddressClient.
+type NetIPAddressError struct {
+	Kind    NetIPAddressErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *NetIPAddressError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_network_adapter_ip [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_network_adapter_ip [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *NetIPAddressError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *NetIPAddressError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*NetIPAddressError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewNetIPAddressError constructs a *NetIPAddressError.
+func NewNetIPAddressError(kind NetIPAddressErrorKind, msg string, cause error, ctx map[string]string) *NetIPAddressError {
+	return &NetIPAddressError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsNetIPAddressError reports whether err is a *NetIPAddressError of the
+// given kind.
+func IsNetIPAddressError(err error, kind NetIPAddressErrorKind) bool {
+	var ne *NetIPAddressError
+	if errors.As(err, &ne) {
+		return ne.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrNetIPAddressNotFound          = &NetIPAddressError{Kind: NetIPAddressErrorNotFound}
+	ErrNetIPAddressInterfaceNotFound = &NetIPAddressError{Kind: NetIPAddressErrorInterfaceNotFound}
+	ErrNetIPAddressAlreadyExists     = &NetIPAddressError{Kind: NetIPAddressErrorAlreadyExists}
+	ErrNetIPAddressInvalidParameter  = &NetIPAddressError{Kind: NetIPAddressErrorInvalidParameter}
+	ErrNetIPAddressPermission        = &NetIPAddressError{Kind: NetIPAddressErrorPermission}
+	ErrNetIPAddressConnectionLost    = &NetIPAddressError{Kind: NetIPAddressErrorConnectionLost}
+	ErrNetIPAddressTimeout           = &NetIPAddressError{Kind: NetIPAddressErrorTimeout}
+	ErrNetIPAddressUnknown           = &NetIPAddressError{Kind: NetIPAddressErrorUnknown}
+)
+
+// NetIPAddressState is the observed state of a static IPv4 address.
+type NetIPAddressState struct {
+	InterfaceAlias string
+	InterfaceIndex int64
+	IPAddress      string
+	PrefixLength   int64
+	// PrefixOrigin is Manual for a static address, Dhcp for a leased one.
+	PrefixOrigin string
+	// DefaultGateways are the next hops of the interface's 0.0.0.0/0 routes,
+	// lowest route metric first. Empty when the interface has none.
+	DefaultGateways []string
+}
+
+// NetIPAddressInput carries the desired configuration of a static address.
+type NetIPAddressInput struct {
+	InterfaceAlias string
+	IPAddress      string
+	PrefixLength   int64
+	// DefaultGateway is the IPv4 next hop of the interface's default route.
+	// Empty leaves the interface's default routes alone.
+	DefaultGateway string
+	// ReconnectHost is the address to confirm the change through when the
+	// WinRM connection drops while it is applied (typically the new
+	// IPAddress). Empty means a dropped connection is reported as
+	// NetIPAddressErrorConnectionLost.
+	ReconnectHost string
+}
+
+// WindowsNetIPAddressClient is the contract for the
+// windows_network_adapter_ip resource.
+type WindowsNetIPAddressClient interface {
+	// Read returns the address ip on the interface alias, or (nil, nil) when
+	// the address or the interface does not exist.
+	Read(ctx context.Context, alias, ip string) (*NetIPAddressState, error)
+
+	// Create assigns the address, disabling DHCP on the interface first, and
+	// sets the default gateway when in.DefaultGateway is non-empty.
+	Create(ctx context.Context, in NetIPAddressInput) (*NetIPAddressState, error)
+
+	// Update moves the interface from prior to in: a new address is added
+	// before the old one is removed, the prefix length is changed in place
+	// and the default route is replaced. InterfaceAlias must not change.
+	Update(ctx context.Context, prior, in NetIPAddressInput) (*NetIPAddressState, error)
+
+	// Delete removes the address and, when in.DefaultGateway is non-empty,
+	// the default route through it. An address that is already gone is
+	// success.
+	Delete(ctx context.Context, in NetIPAddressInput) error
+}
@@ -0,0 +1,179 @@
+// Package winclient: command observation hook.
+//
+// Config.Observer is told when every WinRM run starts and completes, with
+// its duration, the bytes it sent and received and the connections it had
+// to open, so slow operations can be found without guessing. Each run is
+// labelled with the operation that issued it (the "operation" of the error
+// contexts: "read", "install", ...), set by the envelope helpers through
+// withCommandOp. The observer also receives the run's context: in the
+// provider that context carries the Terraform logging fields
+// (tf_resource_type, tf_rpc). Scripts and stdin are never passed to the
+// observer, as stdin may carry secrets.
+package winclient
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// CommandObserver is notified around every WinRM run a Client is asked to
+// make (RunPowerShell, RunPowerShellWithInput, RunCmd), including runs
+// refused before reaching the transport (closed client, latched
+// authentication failure, dial back-off). Methods are called on the calling
+// goroutine and must not block.
+type CommandObserver interface {
+	// OnStart is called when the run is requested.
+	OnStart(ctx context.Context, ev CommandStart)
+	// OnComplete is called once the run has returned, been abandoned or
+	// been refused.
+	OnComplete(ctx context.Context, ev CommandResult)
+}
+
+// CommandStart describes a requested run.
+type CommandStart struct {
+	// Host is the Windows host the run targets.
+	Host string
+	// Op is the operation that issued the run, empty when the caller did
+	// not label it.
+	Op string
+}
+
+// CommandResult describes a finished run.
+type CommandResult struct {
+	// Host and Op are as in CommandStart.
+	Host string
+	Op   string
+	// Sent is false when the run was refused before reaching the transport;
+	// Err then says why.
+	Sent bool
+	// Duration is the time from OnStart to completion.
+	Duration time.Duration
+	// StdinBytes counts the bytes sent on stdin, including the encoded
+	// script; StdoutBytes and StderrBytes count the bytes received.
+	StdinBytes  int64
+	StdoutBytes int64
+	StderrBytes int64
+	// NewConnections counts the connections the client opened to the host
+	// (through the bastion, if any) while the run was in flight. Zero means
+	// the run reused pooled connections. Runs in flight at the same time
+	// share the pool, so a connection opened for one may be counted by all.
+	NewConnections int64
+	// Err is the error returned to the caller: nil, the context error of an
+	// abandoned run, ErrClientClosed, or a *TransportError, including the
+	// FailureCommand of a script that failed without a JSON envelope.
+	Err error
+}
+
+// commandOpKey is the context key of the withCommandOp label.
+type commandOpKey struct{}
+
+// withCommandOp labels the runs made with ctx as op for Config.Observer.
+func withCommandOp(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, commandOpKey{}, op)
+}
+
+// commandOp returns the withCommandOp label of ctx, if any.
+func commandOp(ctx context.Context) string {
+	op, _ := ctx.Value(commandOpKey{}).(string)
+	return op
+}
+
+// countingReader counts the bytes read through it. The count is atomic: an
+// abandoned run is reported while the winrm goroutine may still be reading.
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// countDials wraps dial so every connection it opens is counted in
+// c.dials, for CommandResult.NewConnections.
+func (c *Client) countDials(dial func(network, addr string) (net.Conn, error)) func(network, addr string) (net.Conn, error) {
+	return func(network, addr string) (net.Conn, error) {
+		conn, err := dial(network, addr)
+		if err == nil {
+			c.dials.Add(1)
+		}
+		return conn, err
+	}
+}
+
+// runObservation follows one run for Config.Observer. A nil
+// *runObservation, returned without an observer, ignores every call.
+type runObservation struct {
+	c      *Client
+	ctx    context.Context
+	op     string
+	start  time.Time
+	stdin  *countingReader
+	sent   bool
+	dials  int64
+	stdout int64
+	stderr int64
+}
+
+// observeRun reports the request of a run to Config.Observer and returns
+// the stdin to send and the observation to complete. Without an observer
+// stdin is returned unchanged.
+func (c *Client) observeRun(ctx context.Context, stdin io.Reader) (io.Reader, *runObservation) {
+	obs := c.cfg.Observer
+	if obs == nil {
+		return stdin, nil
+	}
+	o := &runObservation{c: c, ctx: ctx, op: commandOp(ctx)}
+	if stdin != nil {
+		o.stdin = &countingReader{r: stdin}
+		stdin = o.stdin
+	}
+	obs.OnStart(ctx, CommandStart{Host: c.cfg.Host, Op: o.op})
+	o.start = time.Now()
+	return stdin, o
+}
+
+// sending records that the run passed beginRun and beforeRun.
+func (o *runObservation) sending() {
+	if o == nil {
+		return
+	}
+	o.sent = true
+	o.dials = o.c.dials.Load()
+}
+
+// received records the raw output of the run.
+func (o *runObservation) received(stdout, stderr string) {
+	if o == nil {
+		return
+	}
+	o.stdout, o.stderr = int64(len(stdout)), int64(len(stderr))
+}
+
+// done reports the run's outcome, err being the error its caller gets.
+func (o *runObservation) done(err error) {
+	if o == nil {
+		return
+	}
+	ev := CommandResult{
+		Host:        o.c.cfg.Host,
+		Op:          o.op,
+		Sent:        o.sent,
+		Duration:    time.Since(o.start),
+		StdoutBytes: o.stdout,
+		StderrBytes: o.stderr,
+		Err:         err,
+	}
+	if o.sent {
+		ev.NewConnections = o.c.dials.Load() - o.dials
+	}
+	if o.stdin != nil {
+		ev.StdinBytes = o.stdin.n.Load()
+	}
+	o.c.cfg.Observer.OnComplete(o.ctx, ev)
+}
@@ -0,0 +1,235 @@
+// Package winclient — unit tests for the command observation hook.
+package winclient
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records every event it is given.
+type recordingObserver struct {
+	mu      sync.Mutex
+	starts  []CommandStart
+	results []CommandResult
+}
+
+func (o *recordingObserver) OnStart(_ context.Context, ev CommandStart) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.starts = append(o.starts, ev)
+}
+
+func (o *recordingObserver) OnComplete(_ context.Context, ev CommandResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.results = append(o.results, ev)
+}
+
+// newObservedClient returns a client pointed at handler, reporting to obs.
+func newObservedClient(t *testing.T, handler http.HandlerFunc, obs CommandObserver) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	p, _ := strconv.Atoi(port)
+	c, err := New(Config{Host: host, Port: p, Username: "u", Password: "p", AuthType: "basic", Timeout: time.Minute, Observer: obs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestObserver_ReportsFailedRun(t *testing.T) {
+	obs := &recordingObserver{}
+	c := newObservedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		time.Sleep(10 * time.Millisecond)
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}, obs)
+
+	_, _, err := c.RunPowerShell(context.Background(), "Get-Service")
+	if err == nil {
+		t.Fatal("expected the run to fail")
+	}
+	if len(obs.starts) != 1 || len(obs.results) != 1 {
+		t.Fatalf("got %d starts and %d results, want 1 each", len(obs.starts), len(obs.results))
+	}
+	res := obs.results[0]
+	if res.Host != c.cfg.Host || obs.starts[0].Host != c.cfg.Host {
+		t.Errorf("Host = %q / %q, want %q", obs.starts[0].Host, res.Host, c.cfg.Host)
+	}
+	if res.Err != err {
+		t.Errorf("Err = %v, want the error returned to the caller (%v)", res.Err, err)
+	}
+	if !res.Sent || res.NewConnections != 1 {
+		t.Errorf("Sent = %v, NewConnections = %d, want true and 1", res.Sent, res.NewConnections)
+	}
+	if res.Duration < 10*time.Millisecond {
+		t.Errorf("Duration = %v, want at least the server delay", res.Duration)
+	}
+}
+
+func TestObserver_ReportsCancelledRun(t *testing.T) {
+	release := make(chan struct{})
+	obs := &recordingObserver{}
+	c := newObservedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}, obs)
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, _, err := c.RunPowerShell(ctx, "Start-Sleep 3600")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(obs.results) != 1 || !errors.Is(obs.results[0].Err, context.Canceled) {
+		t.Errorf("cancelled run must be reported with its context error: %+v", obs.results)
+	}
+}
+
+func TestObserver_ReportsRefusedRun(t *testing.T) {
+	obs := &recordingObserver{}
+	c := newHealthTestClient(t)
+	c.cfg.Observer = obs
+	for i := 0; i < authLatchThreshold; i++ {
+		c.recordRun(context.Background(), errors.New("http error 401: unauthorized"), 0)
+	}
+
+	_, _, err := c.RunPowerShell(withCommandOp(context.Background(), "read"), "Get-Service")
+	if TransportFailureKind(err) != FailureAuth {
+		t.Fatalf("expected the latched auth failure, got %v", err)
+	}
+	if len(obs.starts) != 1 || len(obs.results) != 1 {
+		t.Fatalf("got %d starts and %d results, want 1 each", len(obs.starts), len(obs.results))
+	}
+	res := obs.results[0]
+	if res.Sent || res.Err != err || res.Op != "read" || res.NewConnections != 0 {
+		t.Errorf("refused run reported as %+v", res)
+	}
+
+	_ = c.CloseGraceful(context.Background())
+	_, _, err = c.RunCmd(context.Background(), "hostname", CmdOptions{})
+	if !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("RunCmd after close = %v, want ErrClientClosed", err)
+	}
+	if len(obs.results) != 2 || obs.results[1].Sent || obs.results[1].Err != err {
+		t.Errorf("run refused by the closed client reported as %+v", obs.results)
+	}
+}
+
+func TestObserver_ReportsScriptFailure(t *testing.T) {
+	obs := &recordingObserver{}
+	c := newObservedClient(t, fakeWinRMHandler(t, "", scriptStatusPrefix+"3 True\n"), obs)
+
+	_, _, err := c.RunPowerShell(withCommandOp(context.Background(), "install"), "exit 3")
+	if CommandExitCode(err) != 3 {
+		t.Fatalf("err = %v, want the script's exit code 3", err)
+	}
+	if len(obs.starts) != 1 || len(obs.results) != 1 {
+		t.Fatalf("got %d starts and %d results, want 1 each", len(obs.starts), len(obs.results))
+	}
+	res := obs.results[0]
+	if res.Err != err {
+		t.Errorf("Err = %v, want the script failure returned to the caller (%v)", res.Err, err)
+	}
+	if !res.Sent || res.Op != "install" || obs.starts[0].Op != "install" {
+		t.Errorf("unexpected result %+v / start %+v", res, obs.starts[0])
+	}
+	if res.StdinBytes == 0 || res.StderrBytes == 0 {
+		t.Errorf("byte counts not recorded: %+v", res)
+	}
+}
+
+func TestObserver_CountsNewConnections(t *testing.T) {
+	obs := &recordingObserver{}
+	c := newObservedClient(t, fakeWinRMHandler(t, `{"ok":true}`, scriptStatusPrefix+"0 True\n"), obs)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.RunPowerShell(context.Background(), "Get-Date"); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+	if len(obs.results) != 2 {
+		t.Fatalf("got %d results, want 2", len(obs.results))
+	}
+	if obs.results[0].NewConnections == 0 {
+		t.Error("the first run must report the connection it opened")
+	}
+	if obs.results[1].NewConnections != 0 {
+		t.Errorf("the second run must reuse the pooled connection, opened %d", obs.results[1].NewConnections)
+	}
+}
+
+func TestCommandOp_SetByEnvelopeHelpers(t *testing.T) {
+	var op string
+	defer stubFeatRun(func(ctx context.Context, _ *Client, _ string) (string, string, error) {
+		op = commandOp(ctx)
+		return featOK(t, fakeFeatureData("Web-Server", "Installed")), "", nil
+	})()
+	if _, err := NewFeatureClient(newFeatTestClient(t)).Read(context.Background(), "Web-Server"); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if op != "read" {
+		t.Errorf("run labelled %q, want %q", op, "read")
+	}
+}
+
+// fakeWinRMHandler answers the WS-Management calls of one winrm run: the
+// command writes stdout and stderr and exits 0.
+func fakeWinRMHandler(t *testing.T, stdout, stderr string) http.HandlerFunc {
+	t.Helper()
+	const envelope = `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" ` +
+		`xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" ` +
+		`xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd" ` +
+		`xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell">` +
+		`<s:Header><a:Action>%s</a:Action>%s</s:Header><s:Body>%s</s:Body></s:Envelope>`
+	const shellNS = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/"
+	b64 := base64.StdEncoding.EncodeToString
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		body := string(raw)
+		w.Header().Set("Content-Type", "application/soap+xml;charset=UTF-8")
+		switch {
+		case strings.Contains(body, "transfer/Create<"):
+			fmt.Fprintf(w, envelope, "http://schemas.xmlsoap.org/ws/2004/09/transfer/CreateResponse",
+				`<w:SelectorSet><w:Selector Name="ShellId">S1</w:Selector></w:SelectorSet>`, "")
+		case strings.Contains(body, "shell/Command<"):
+			fmt.Fprintf(w, envelope, shellNS+"CommandResponse", "",
+				`<rsp:CommandResponse><rsp:CommandId>C1</rsp:CommandId></rsp:CommandResponse>`)
+		case strings.Contains(body, "shell/Receive<"):
+			fmt.Fprintf(w, envelope, shellNS+"ReceiveResponse", "",
+				`<rsp:ReceiveResponse>`+
+					`<rsp:Stream Name="stdout" CommandId="C1">`+b64([]byte(stdout))+`</rsp:Stream>`+
+					`<rsp:Stream Name="stderr" CommandId="C1">`+b64([]byte(stderr))+`</rsp:Stream>`+
+					`<rsp:CommandState CommandId="C1" State="`+shellNS+`CommandState/Done">`+
+					`<rsp:ExitCode>0</rsp:ExitCode></rsp:CommandState></rsp:ReceiveResponse>`)
+		default: // Send, Signal, Delete
+			fmt.Fprintf(w, envelope, "", "", "")
+		}
+	}
+}
+
+func TestCountingReader(t *testing.T) {
+	r := &countingReader{r: strings.NewReader("hello, world")}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if n := r.n.Load(); n != 12 {
+		t.Errorf("counted %d bytes, want 12", n)
+	}
+}
@@ -0,0 +1,381 @@
+// Package winclient: Windows optional feature (DISM) CRUD over WinRM.
+//
+// This file provides OptionalFeatureClient, the concrete
+// WindowsOptionalFeatureClient used by the windows_optional_feature Terraform
+// resource. It is the client-SKU counterpart of FeatureClient: Windows 10/11
+// have no ServerManager module, so features are managed with the DISM
+// cmdlets Get/Enable/Disable-WindowsOptionalFeature -Online. Every script
+// emits a JSON envelope (Emit-OK/Emit-Err) as in feature.go.
+//
+// The cmdlets always run with -NoRestart: rebooting is left to
+// windows_reboot, and a needed reboot is reported instead.
+//
+// Security invariants:
+//   - feature name and source path are interpolated only through psQuote.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Compile-time assertion: OptionalFeatureClient satisfies WindowsOptionalFeatureClient.
+var _ WindowsOptionalFeatureClient = (*OptionalFeatureClient)(nil)
+
+// OptionalFeatureClient is the PowerShell/WinRM-backed WindowsOptionalFeatureClient.
+type OptionalFeatureClient struct {
+	c *Client
+}
+
+// NewOptionalFeatureClient constructs an OptionalFeatureClient wrapping the
+// given WinRM Client.
+func NewOptionalFeatureClient(c *Client) *OptionalFeatureClient { return &OptionalFeatureClient{c: c} }
+
+// psOptionalFeatureHeader prepends Emit-OK/Emit-Err, Classify-OptionalFeature
+// and the shared read helper.
+//
+// Classify-OptionalFeature is best-effort: it matches DISM HRESULTs first
+// because they survive localisation, then English message substrings.
+const psOptionalFeatureHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+$WarningPreference     = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Classify-OptionalFeature([string]$Msg) {
+  if ($Msg -match '0x800f081f' -or $Msg -match '0x800f0906' -or $Msg -match '0x800f0907' -or $Msg -match 'source files could not be found') { return 'source_missing' }
+  if ($Msg -match 'requires elevation' -or $Msg -match 'Access is denied' -or $Msg -match '0x80070005') { return 'permission_denied' }
+  if ($Msg -match 'is unknown' -or $Msg -match '0x800f080c') { return 'not_found' }
+  if ($Msg -match 'is not recognized' -or $Msg -match 'CommandNotFoundException') { return 'unsupported' }
+  if ($Msg -match 'parameter' -and $Msg -match 'invalid') { return 'invalid_parameter' }
+  return 'unknown'
+}
+
+function Ensure-DismCmdlets {
+  if (-not (Get-Command Get-WindowsOptionalFeature -ErrorAction SilentlyContinue)) {
+    Emit-Err 'unsupported' 'Get-WindowsOptionalFeature is not available on this host (the DISM PowerShell module is missing).' @{}
+    exit 0
+  }
+}
+
+function Format-OptionalFeature($f) {
+  [ordered]@{
+    name         = [string]$f.FeatureName
+    display_name = [string]$f.DisplayName
+    description  = [string]$f.Description
+    state        = [string]$f.State
+  }
+}
+`
+
+// optionalFeaturePSResponse is the parsed JSON envelope for optional feature
+// operations.
+type optionalFeaturePSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// runOptionalFeaturePowerShell is the indirection used by
+// OptionalFeatureClient. Tests can override it; production code must not.
+var runOptionalFeaturePowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// runEnvelope executes script (prepended with psOptionalFeatureHeader) and
+// parses the JSON envelope.
+func (o *OptionalFeatureClient) runEnvelope(ctx context.Context, op, name, script string) (*optionalFeaturePSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psOptionalFeatureHeader + "\n" + script
+	start := time.Now()
+	stdout, stderr, err := runOptionalFeaturePowerShell(ctx, o.c, full)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			elapsed := time.Since(start).Round(time.Second)
+			msg := fmt.Sprintf("operation %q on optional feature %q was cancelled after %s", op, name, elapsed)
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				msg = fmt.Sprintf("operation %q on optional feature %q timed out after %s (raise the resource's timeouts block or the provider's default_command_timeout)", op, name, elapsed)
+			}
+			return nil, NewOptionalFeatureError(OptionalFeatureErrorTimeout, msg, ctxErr,
+				map[string]string{"operation": op, "name": name, "host": o.c.cfg.Host, "elapsed": elapsed.String()})
+		}
+		return nil, NewOptionalFeatureError(OptionalFeatureErrorUnknown,
+			fmt.Sprintf("powershell transport error during %q", op), err,
+			map[string]string{
+				"operation": op, "name": name, "host": o.c.cfg.Host,
+				"stderr": truncate(stderr, 2048),
+				"stdout": truncate(stdout, 2048),
+			})
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		return nil, NewOptionalFeatureError(OptionalFeatureErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil,
+			map[string]string{
+				"operation": op, "name": name, "host": o.c.cfg.Host,
+				"stderr": truncate(stderr, 2048),
+				"stdout": truncate(stdout, 2048),
+			})
+	}
+	var resp optionalFeaturePSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		return nil, NewOptionalFeatureError(OptionalFeatureErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr,
+			map[string]string{"operation": op, "name": name, "host": o.c.cfg.Host, "stdout": truncate(stdout, 2048)})
+	}
+	if !resp.OK {
+		kind := mapOptionalFeatureKind(resp.Kind)
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		ctxMap["operation"] = op
+		ctxMap["name"] = name
+		ctxMap["host"] = o.c.cfg.Host
+		msg := resp.Message
+		if kind == OptionalFeatureErrorPermission {
+			msg += " (DISM requires an elevated session: the WinRM account must be a local Administrator.)"
+		}
+		return &resp, NewOptionalFeatureError(kind, msg, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapOptionalFeatureKind translates the PS-side "kind" string to a typed
+// OptionalFeatureErrorKind.
+func mapOptionalFeatureKind(k string) OptionalFeatureErrorKind {
+	switch k {
+	case string(OptionalFeatureErrorNotFound),
+		string(OptionalFeatureErrorPermission),
+		string(OptionalFeatureErrorSourceMissing),
+		string(OptionalFeatureErrorUnsupported),
+		string(OptionalFeatureErrorTimeout),
+		string(OptionalFeatureErrorInvalidParameter):
+		return OptionalFeatureErrorKind(k)
+	default:
+		return OptionalFeatureErrorUnknown
+	}
+}
+
+// optionalFeatureInstallStates maps DISM states to the install_state
+// vocabulary of windows_feature.
+var optionalFeatureInstallStates = map[string]string{
+	"Enabled":                    "Installed",
+	"EnablePending":              "InstallPending",
+	"Disabled":                   "Available",
+	"DisablePending":             "UninstallPending",
+	"DisabledWithPayloadRemoved": "Removed",
+}
+
+// optionalFeaturePayload mirrors Format-OptionalFeature.
+type optionalFeaturePayload struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+// optionalFeatureChangePayload mirrors the JSON returned by Enable/Disable.
+type optionalFeatureChangePayload struct {
+	Feature       *optionalFeaturePayload `json:"feature"`
+	RestartNeeded bool                    `json:"restart_needed"`
+	Unchanged     bool                    `json:"unchanged"`
+}
+
+func toOptionalFeatureInfo(d *optionalFeaturePayload) *OptionalFeatureInfo {
+	if d == nil {
+		return nil
+	}
+	installState, ok := optionalFeatureInstallStates[d.State]
+	if !ok {
+		installState = d.State
+	}
+	return &OptionalFeatureInfo{
+		Name:           d.Name,
+		DisplayName:    d.DisplayName,
+		Description:    d.Description,
+		State:          d.State,
+		InstallState:   installState,
+		Enabled:        d.State == "Enabled" || d.State == "EnablePending",
+		RestartPending: d.State == "EnablePending" || d.State == "DisablePending",
+	}
+}
+
+// psOptionalFeatureReadBody emits the feature data (or null when unknown).
+const psOptionalFeatureReadBody = `
+Ensure-DismCmdlets
+function Read-OptionalFeature([string]$Name) {
+  try {
+    $f = Get-WindowsOptionalFeature -Online -FeatureName $Name -ErrorAction Stop
+  } catch {
+    $msg = $_.Exception.Message
+    $kind = Classify-OptionalFeature $msg
+    if ($kind -eq 'not_found') { Emit-OK $null; return }
+    Emit-Err $kind $msg @{ name = $Name }
+    return
+  }
+  if (-not $f) { Emit-OK $null; return }
+  Emit-OK (Format-OptionalFeature $f)
+}
+`
+
+// Read implements WindowsOptionalFeatureClient.Read.
+func (o *OptionalFeatureClient) Read(ctx context.Context, name string) (*OptionalFeatureInfo, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, NewOptionalFeatureError(OptionalFeatureErrorInvalidParameter, "feature name is empty", nil, nil)
+	}
+	script := psOptionalFeatureReadBody + "\nRead-OptionalFeature -Name " + psQuote(name) + "\n"
+	resp, err := o.runEnvelope(ctx, "read", name, script)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return nil, nil
+	}
+	var payload optionalFeaturePayload
+	if jerr := json.Unmarshal(resp.Data, &payload); jerr != nil {
+		return nil, NewOptionalFeatureError(OptionalFeatureErrorUnknown, "failed to parse read payload", jerr,
+			map[string]string{"name": name})
+	}
+	return toOptionalFeatureInfo(&payload), nil
+}
+
+// psOptionalFeatureEnableBody enables a feature unless it is already enabled
+// (or enable-pending) and emits the post-state. A feature whose payload was
+// removed needs a source unless Windows Update may be used.
+const psOptionalFeatureEnableBody = `
+Ensure-DismCmdlets
+function Run-Enable([string]$Name, [bool]$All, [string]$Source, [bool]$LimitAccess) {
+  try {
+    $cur = Get-WindowsOptionalFeature -Online -FeatureName $Name -ErrorAction Stop
+  } catch {
+    $msg = $_.Exception.Message
+    Emit-Err (Classify-OptionalFeature $msg) $msg @{ name = $Name; phase = 'precheck' }
+    return
+  }
+  if (-not $cur) {
+    Emit-Err 'not_found' ("Optional feature '" + $Name + "' was not found on this host.") @{ name = $Name }
+    return
+  }
+  $state = [string]$cur.State
+  if ($state -eq 'Enabled' -or $state -eq 'EnablePending') {
+    Emit-OK ([ordered]@{ feature = (Format-OptionalFeature $cur); restart_needed = $false; unchanged = $true })
+    return
+  }
+  if ($state -eq 'DisabledWithPayloadRemoved' -and $LimitAccess -and [string]::IsNullOrEmpty($Source)) {
+    Emit-Err 'source_missing' ("Optional feature '" + $Name + "' has its payload removed; a 'source' is required when limit_access is true.") @{ name = $Name; state = $state }
+    return
+  }
+  $params = @{ Online = $true; FeatureName = $Name; NoRestart = $true; ErrorAction = 'Stop' }
+  if ($All)         { $params['All'] = $true }
+  if ($LimitAccess) { $params['LimitAccess'] = $true }
+  if (-not [string]::IsNullOrEmpty($Source)) { $params['Source'] = $Source }
+  try {
+    $r = Enable-WindowsOptionalFeature @params
+  } catch {
+    $msg = $_.Exception.Message
+    Emit-Err (Classify-OptionalFeature $msg) $msg @{ name = $Name; phase = 'enable' }
+    return
+  }
+  $restartNeeded = $false
+  if ($r -and $r.PSObject.Properties['RestartNeeded']) { $restartNeeded = [bool]$r.RestartNeeded }
+  $f = Get-WindowsOptionalFeature -Online -FeatureName $Name -ErrorAction Stop
+  Emit-OK ([ordered]@{ feature = (Format-OptionalFeature $f); restart_needed = [bool]$restartNeeded; unchanged = $false })
+}
+`
+
+// Enable implements WindowsOptionalFeatureClient.Enable.
+func (o *OptionalFeatureClient) Enable(ctx context.Context, in OptionalFeatureInput) (*OptionalFeatureInfo, *OptionalFeatureResult, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, nil, NewOptionalFeatureError(OptionalFeatureErrorInvalidParameter, "feature name is empty", nil, nil)
+	}
+	call := fmt.Sprintf("Run-Enable -Name %s -All:$%s -Source %s -LimitAccess:$%s",
+		psQuote(in.Name),
+		psBool(in.All),
+		psQuote(in.Source),
+		psBool(in.LimitAccess),
+	)
+	return o.change(ctx, "enable", in.Name, psOptionalFeatureEnableBody+"\n"+call+"\n")
+}
+
+// psOptionalFeatureDisableBody disables a feature unless it is already
+// disabled, or unknown on the host, and emits the post-state.
+const psOptionalFeatureDisableBody = `
+Ensure-DismCmdlets
+function Run-Disable([string]$Name, [bool]$Remove) {
+  try {
+    $cur = Get-WindowsOptionalFeature -Online -FeatureName $Name -ErrorAction Stop
+  } catch {
+    $msg = $_.Exception.Message
+    $kind = Classify-OptionalFeature $msg
+    if ($kind -eq 'not_found') { Emit-OK ([ordered]@{ feature = $null; restart_needed = $false; unchanged = $true }); return }
+    Emit-Err $kind $msg @{ name = $Name; phase = 'precheck' }
+    return
+  }
+  if (-not $cur) {
+    Emit-OK ([ordered]@{ feature = $null; restart_needed = $false; unchanged = $true })
+    return
+  }
+  $state = [string]$cur.State
+  $done = ($state -eq 'DisabledWithPayloadRemoved') -or (-not $Remove -and ($state -eq 'Disabled' -or $state -eq 'DisablePending'))
+  if ($done) {
+    Emit-OK ([ordered]@{ feature = (Format-OptionalFeature $cur); restart_needed = $false; unchanged = $true })
+    return
+  }
+  $params = @{ Online = $true; FeatureName = $Name; NoRestart = $true; ErrorAction = 'Stop' }
+  if ($Remove) { $params['Remove'] = $true }
+  try {
+    $r = Disable-WindowsOptionalFeature @params
+  } catch {
+    $msg = $_.Exception.Message
+    Emit-Err (Classify-OptionalFeature $msg) $msg @{ name = $Name; phase = 'disable' }
+    return
+  }
+  $restartNeeded = $false
+  if ($r -and $r.PSObject.Properties['RestartNeeded']) { $restartNeeded = [bool]$r.RestartNeeded }
+  $f = Get-WindowsOptionalFeature -Online -FeatureName $Name -ErrorAction Stop
+  Emit-OK ([ordered]@{ feature = (Format-OptionalFeature $f); restart_needed = [bool]$restartNeeded; unchanged = $false })
+}
+`
+
+// Disable implements WindowsOptionalFeatureClient.Disable.
+func (o *OptionalFeatureClient) Disable(ctx context.Context, in OptionalFeatureInput) (*OptionalFeatureInfo, *OptionalFeatureResult, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, nil, NewOptionalFeatureError(OptionalFeatureErrorInvalidParameter, "feature name is empty", nil, nil)
+	}
+	call := fmt.Sprintf("Run-Disable -Name %s -Remove:$%s", psQuote(in.Name), psBool(in.RemovePayload))
+	return o.change(ctx, "disable", in.Name, psOptionalFeatureDisableBody+"\n"+call+"\n")
+}
+
+// change runs an Enable/Disable script and decodes its payload.
+func (o *OptionalFeatureClient) change(ctx context.Context, op, name, script string) (*OptionalFeatureInfo, *OptionalFeatureResult, error) {
+	resp, err := o.runEnvelope(ctx, op, name, script)
+	if err != nil {
+		return nil, nil, err
+	}
+	var payload optionalFeatureChangePayload
+	if jerr := json.Unmarshal(resp.Data, &payload); jerr != nil {
+		return nil, nil, NewOptionalFeatureError(OptionalFeatureErrorUnknown,
+			fmt.Sprintf("failed to parse %s payload", op), jerr, map[string]string{"name": name})
+	}
+	return toOptionalFeatureInfo(payload.Feature), &OptionalFeatureResult{
+		RestartNeeded: payload.RestartNeeded,
+		Unchanged:     payload.Unchanged,
+	}, nil
+}
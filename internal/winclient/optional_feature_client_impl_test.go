@@ -0,0 +1,153 @@
+// Package winclient — unit tests for OptionalFeatureClient.
+//
+// These tests stub the package-level seam runOptionalFeaturePowerShell to
+// inject scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubOFRun replaces runOptionalFeaturePowerShell for the duration of a test.
+func stubOFRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runOptionalFeaturePowerShell
+	runOptionalFeaturePowerShell = fn
+	return func() { runOptionalFeaturePowerShell = prev }
+}
+
+func fakeOptionalFeature(name, state string) map[string]any {
+	return map[string]any{
+		"name":         name,
+		"display_name": name + " Display",
+		"description":  "optional feature description",
+		"state":        state,
+	}
+}
+
+func TestOptionalFeatureRead_StateMapping(t *testing.T) {
+	for state, want := range map[string]struct {
+		installState     string
+		enabled, pending bool
+	}{
+		"Enabled":                    {"Installed", true, false},
+		"EnablePending":              {"InstallPending", true, true},
+		"Disabled":                   {"Available", false, false},
+		"DisablePending":             {"UninstallPending", false, true},
+		"DisabledWithPayloadRemoved": {"Removed", false, false},
+	} {
+		restore := stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+			if !strings.Contains(script, "Get-WindowsOptionalFeature -Online -FeatureName $Name") ||
+				!strings.Contains(script, "Read-OptionalFeature -Name 'TelnetClient'") {
+				t.Errorf("unexpected script: %s", script)
+			}
+			return featOK(t, fakeOptionalFeature("TelnetClient", state)), "", nil
+		})
+		info, err := NewOptionalFeatureClient(newTestClient(t)).Read(context.Background(), "TelnetClient")
+		restore()
+		if err != nil {
+			t.Fatalf("%s: Read err: %v", state, err)
+		}
+		if info.State != state || info.InstallState != want.installState ||
+			info.Enabled != want.enabled || info.RestartPending != want.pending {
+			t.Errorf("%s: info = %+v", state, info)
+		}
+	}
+}
+
+func TestOptionalFeatureRead_NotFound(t *testing.T) {
+	defer stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		return `{"ok":true,"data":null}` + "\n", "", nil
+	})()
+	info, err := NewOptionalFeatureClient(newTestClient(t)).Read(context.Background(), "Nope")
+	if err != nil || info != nil {
+		t.Errorf("Read = %+v, %v; want nil, nil", info, err)
+	}
+}
+
+func TestOptionalFeatureRead_EmptyName(t *testing.T) {
+	_, err := NewOptionalFeatureClient(newTestClient(t)).Read(context.Background(), " ")
+	if !errors.Is(err, ErrOptionalFeatureInvalidParameter) {
+		t.Errorf("err = %v, want invalid_parameter", err)
+	}
+}
+
+func TestOptionalFeatureRead_PermissionDenied(t *testing.T) {
+	defer stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		return featErr(t, "permission_denied", "The requested operation requires elevation."), "", nil
+	})()
+	_, err := NewOptionalFeatureClient(newTestClient(t)).Read(context.Background(), "TelnetClient")
+	if !IsOptionalFeatureError(err, OptionalFeatureErrorPermission) || !strings.Contains(err.Error(), "Administrator") {
+		t.Errorf("err = %v, want permission_denied with hint", err)
+	}
+}
+
+func TestOptionalFeatureEnable_Script(t *testing.T) {
+	var captured string
+	defer stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return featOK(t, map[string]any{
+			"feature":        fakeOptionalFeature("NetFx3", "EnablePending"),
+			"restart_needed": true,
+			"unchanged":      false,
+		}), "", nil
+	})()
+	info, res, err := NewOptionalFeatureClient(newTestClient(t)).Enable(context.Background(), OptionalFeatureInput{
+		Name: "NetFx3", All: true, Source: `D:\sources\sxs`, LimitAccess: true,
+	})
+	if err != nil {
+		t.Fatalf("Enable err: %v", err)
+	}
+	if !strings.Contains(captured, `Run-Enable -Name 'NetFx3' -All:$true -Source 'D:\sources\sxs' -LimitAccess:$true`) {
+		t.Errorf("unexpected call: %s", captured)
+	}
+	if !strings.Contains(captured, "NoRestart = $true") {
+		t.Errorf("Enable must never reboot: %s", captured)
+	}
+	if !info.Enabled || !res.RestartNeeded || res.Unchanged {
+		t.Errorf("info = %+v, result = %+v", info, res)
+	}
+}
+
+func TestOptionalFeatureEnable_SourceMissing(t *testing.T) {
+	defer stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		return featErr(t, "source_missing", "The source files could not be found. 0x800f081f"), "", nil
+	})()
+	_, _, err := NewOptionalFeatureClient(newTestClient(t)).Enable(context.Background(), OptionalFeatureInput{Name: "NetFx3"})
+	if !errors.Is(err, ErrOptionalFeatureSourceMissing) {
+		t.Errorf("err = %v, want source_missing", err)
+	}
+}
+
+func TestOptionalFeatureDisable_AlreadyGone(t *testing.T) {
+	var captured string
+	defer stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return featOK(t, map[string]any{"feature": nil, "restart_needed": false, "unchanged": true}), "", nil
+	})()
+	info, res, err := NewOptionalFeatureClient(newTestClient(t)).Disable(context.Background(), OptionalFeatureInput{
+		Name: "TelnetClient", RemovePayload: true,
+	})
+	if err != nil || info != nil || !res.Unchanged {
+		t.Errorf("Disable = %+v, %+v, %v", info, res, err)
+	}
+	if !strings.Contains(captured, "Run-Disable -Name 'TelnetClient' -Remove:$true") {
+		t.Errorf("unexpected call: %s", captured)
+	}
+}
+
+func TestOptionalFeatureEnable_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	defer stubOFRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	})()
+	_, _, err := NewOptionalFeatureClient(newTestClient(t)).Enable(ctx, OptionalFeatureInput{Name: "NetFx3"})
+	if !errors.Is(err, ErrOptionalFeatureTimeout) || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
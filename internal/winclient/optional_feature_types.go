@@ -0,0 +1,144 @@
+// Package winclient: types for the windows_optional_feature resource.
+//
+// OptionalFeatureInfo is the observed state returned by Read;
+// OptionalFeatureResult is the extra payload returned by Enable/Disable.
+// OptionalFeatureErrorKind / OptionalFeatureError follow the same shape as
+// FeatureError so the resource layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OptionalFeatureErrorKind categorises errors returned by
+// WindowsOptionalFeatureClient.
+type OptionalFeatureErrorKind string
+
+const (
+	OptionalFeatureErrorNotFound         OptionalFeatureErrorKind = "not_found"
+	OptionalFeatureErrorPermission       OptionalFeatureErrorKind = "permission_denied"
+	OptionalFeatureErrorSourceMissing    OptionalFeatureErrorKind = "source_missing"
+	OptionalFeatureErrorUnsupported      OptionalFeatureErrorKind = "unsupported"
+	OptionalFeatureErrorTimeout          OptionalFeatureErrorKind = "timeout"
+	OptionalFeatureErrorInvalidParameter OptionalFeatureErrorKind = "invalid_parameter"
+	OptionalFeatureErrorUnknown          OptionalFeatureErrorKind = "unknown"
+)
+
+// OptionalFeatureError is the structured error type returned by
+// WindowsOptionalFeatureClient.
+type OptionalFeatureError struct {
+	Kind    OptionalFeatureErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *OptionalFeatureError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_optional_feature [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_optional_feature [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *OptionalFeatureError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *OptionalFeatureError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*OptionalFeatureError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewOptionalFeatureError constructs a *OptionalFeatureError.
+func NewOptionalFeatureError(kind OptionalFeatureErrorKind, msg string, cause error, ctx map[string]string) *OptionalFeatureError {
+	return &OptionalFeatureError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsOptionalFeatureError reports whether err is a *OptionalFeatureError of
+// the given kind.
+func IsOptionalFeatureError(err error, kind OptionalFeatureErrorKind) bool {
+	var oe *OptionalFeatureError
+	if errors.As(err, &oe) {
+		return oe.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrOptionalFeatureNotFound         = &OptionalFeatureError{Kind: OptionalFeatureErrorNotFound}
+	ErrOptionalFeaturePermission       = &OptionalFeatureError{Kind: OptionalFeatureErrorPermission}
+	ErrOptionalFeatureSourceMissing    = &OptionalFeatureError{Kind: OptionalFeatureErrorSourceMissing}
+	ErrOptionalFeatureUnsupported      = &OptionalFeatureError{Kind: OptionalFeatureErrorUnsupported}
+	ErrOptionalFeatureTimeout          = &OptionalFeatureError{Kind: OptionalFeatureErrorTimeout}
+	ErrOptionalFeatureInvalidParameter = &OptionalFeatureError{Kind: OptionalFeatureErrorInvalidParameter}
+	ErrOptionalFeatureUnknown          = &OptionalFeatureError{Kind: OptionalFeatureErrorUnknown}
+)
+
+// OptionalFeatureInfo is the observed state of a Windows optional feature.
+type OptionalFeatureInfo struct {
+	// Name is the DISM feature name (e.g. Microsoft-Hyper-V-All).
+	Name string
+	// DisplayName and Description are as reported by Get-WindowsOptionalFeature.
+	DisplayName string
+	Description string
+	// State is the raw DISM state: Enabled, Disabled, EnablePending,
+	// DisablePending or DisabledWithPayloadRemoved.
+	State string
+	// InstallState is State in the vocabulary of windows_feature: Installed,
+	// InstallPending, Available, UninstallPending or Removed.
+	InstallState string
+	// Enabled is true when State is Enabled or EnablePending.
+	Enabled bool
+	// RestartPending is true when State is a pending state.
+	RestartPending bool
+}
+
+// OptionalFeatureResult is the side-channel returned by Enable/Disable.
+type OptionalFeatureResult struct {
+	// RestartNeeded is true when the DISM cmdlet reported RestartNeeded.
+	RestartNeeded bool
+	// Unchanged is true when the feature was already in the requested state
+	// and the cmdlet was not run.
+	Unchanged bool
+}
+
+// OptionalFeatureInput carries the desired configuration for Enable/Disable.
+type OptionalFeatureInput struct {
+	Name string
+	// All also enables the parent features the feature depends on (-All).
+	All bool
+	// Source is the payload location (-Source): a mounted image, an SxS
+	// folder or a WIM path.
+	Source string
+	// LimitAccess keeps DISM from contacting Windows Update (-LimitAccess).
+	LimitAccess bool
+	// RemovePayload removes the payload on Disable (-Remove), leaving the
+	// feature in DisabledWithPayloadRemoved.
+	RemovePayload bool
+}
+
+// WindowsOptionalFeatureClient is the contract for the
+// windows_optional_feature resource.
+type WindowsOptionalFeatureClient interface {
+	// Read returns the current state of the feature, or (nil, nil) if the
+	// feature does not exist on the target host.
+	Read(ctx context.Context, name string) (*OptionalFeatureInfo, error)
+
+	// Enable enables the feature. The cmdlet runs with -NoRestart; a needed
+	// reboot is reported in the result.
+	Enable(ctx context.Context, in OptionalFeatureInput) (*OptionalFeatureInfo, *OptionalFeatureResult, error)
+
+	// Disable disables the feature. Name and RemovePayload are honoured; a
+	// feature that no longer exists is treated as disabled.
+	Disable(ctx context.Context, in OptionalFeatureInput) (*OptionalFeatureInfo, *OptionalFeatureResult, error)
+}
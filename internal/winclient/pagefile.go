@@ -0,0 +1,348 @@
+// Package winclient: pagefile sizing over WinRM.
+//
+// PagefileClient is the concrete WindowsPagefileClient backing the
+// windows_pagefile resource. It uses the CIM classes Win32_ComputerSystem
+// (AutomaticManagedPagefile), Win32_PageFileSetting (the configured size per
+// drive) and Win32_PageFileUsage (the pagefile in use since boot).
+//
+// Pagefile changes only take effect at the next boot. PendingReboot is
+// derived by comparing the pagefile in use with the configuration:
+//
+//   - automatic management: pending while no pagefile is in use at all;
+//   - system managed size on the drive: pending while none is in use there;
+//   - custom size: pending while none is in use there, or its allocated size
+//     differs from the initial size (Windows creates it at the initial size
+//     and only grows it under memory pressure);
+//   - no setting for the drive: pending while one is still in use there.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Compile-time assertion: PagefileClient satisfies WindowsPagefileClient.
+var _ WindowsPagefileClient = (*PagefileClient)(nil)
+
+// PagefileClient is the PowerShell/WinRM-backed WindowsPagefileClient.
+type PagefileClient struct {
+	c *Client
+}
+
+// NewPagefileClient wraps the given WinRM Client.
+func NewPagefileClient(c *Client) *PagefileClient { return &PagefileClient{c: c} }
+
+// runPagefilePowerShell is the package-level indirection used by
+// PagefileClient. Tests may override it; production code must not.
+var runPagefilePowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// pagefileDriveRe matches a drive letter with its colon.
+var pagefileDriveRe = regexp.MustCompile(`^[A-Za-z]:$`)
+
+// pagefilePSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type pagefilePSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// pagefileStatePayload is the "data" object emitted by Get-PfState.
+type pagefileStatePayload struct {
+	DriveExists      bool  `json:"drive_exists"`
+	AutomaticManaged bool  `json:"automatic_managed"`
+	Configured       bool  `json:"configured"`
+	InitialSizeMB    int64 `json:"initial_size_mb"`
+	MaximumSizeMB    int64 `json:"maximum_size_mb"`
+	Active           bool  `json:"active"`
+	CurrentSizeMB    int64 `json:"current_size_mb"`
+	AnyActive        bool  `json:"any_active"`
+}
+
+// psPagefileHeader prepends Emit-OK/Emit-Err, Classify-Pagefile and
+// Get-PfState.
+const psPagefileHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+$WarningPreference     = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Classify-Pagefile([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'Access denied' -or $Msg -match 'UnauthorizedAccess') { return 'permission_denied' }
+  if ($Msg -match 'Invalid parameter' -or $Msg -match 'Invalid property') { return 'invalid_parameter' }
+  return 'unknown'
+}
+function Get-PfSetting([string]$Drive) {
+  $path = $Drive + '\pagefile.sys'
+  return Get-CimInstance -ClassName Win32_PageFileSetting | Where-Object { $_.Name -ieq $path } | Select-Object -First 1
+}
+function Get-PfState([string]$Drive) {
+  $cs   = Get-CimInstance -ClassName Win32_ComputerSystem
+  $vol  = Get-CimInstance -ClassName Win32_LogicalDisk -Filter ("DeviceID='" + $Drive + "'")
+  $set  = Get-PfSetting $Drive
+  $use  = @(Get-CimInstance -ClassName Win32_PageFileUsage)
+  $path = $Drive + '\pagefile.sys'
+  $mine = $use | Where-Object { $_.Name -ieq $path } | Select-Object -First 1
+  return [ordered]@{
+    drive_exists      = ($null -ne $vol)
+    automatic_managed = [bool]$cs.AutomaticManagedPagefile
+    configured        = ($null -ne $set)
+    initial_size_mb   = $(if ($set) { [int64]$set.InitialSize } else { 0 })
+    maximum_size_mb   = $(if ($set) { [int64]$set.MaximumSize } else { 0 })
+    active            = ($null -ne $mine)
+    current_size_mb   = $(if ($mine) { [int64]$mine.AllocatedBaseSize } else { 0 })
+    any_active        = ($use.Count -gt 0)
+  }
+}
+function Set-AutomaticManaged([bool]$On) {
+  $cs = Get-CimInstance -ClassName Win32_ComputerSystem
+  if ([bool]$cs.AutomaticManagedPagefile -ne $On) {
+    Set-CimInstance -InputObject $cs -Property @{ AutomaticManagedPagefile = $On }
+  }
+}
+`
+
+// psSetPagefile applies the configuration. %[1]s drive (quoted), %[2]s
+// automatic ($true/$false), %[3]d initial MB, %[4]d maximum MB. Turning
+// automatic management off first is required: Windows ignores
+// Win32_PageFileSetting while it is on.
+const psSetPagefile = `
+try {
+  $drive = %[1]s
+  $st = Get-PfState $drive
+  if (-not $st.drive_exists) {
+    Emit-Err 'not_found' ('drive ' + $drive + ' does not exist') @{ drive = $drive }
+    return
+  }
+  if (%[2]s) {
+    Set-AutomaticManaged $true
+  } else {
+    Set-AutomaticManaged $false
+    $set = Get-PfSetting $drive
+    if ($null -eq $set) {
+      $set = New-CimInstance -ClassName Win32_PageFileSetting -Property @{ Name = ($drive + '\pagefile.sys') }
+    }
+    Set-CimInstance -InputObject $set -Property @{ InitialSize = [uint32]%[3]d; MaximumSize = [uint32]%[4]d }
+  }
+  Emit-OK (Get-PfState $drive)
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Pagefile $msg) $msg @{ drive = %[1]s }
+}
+`
+
+// psReadPagefile reports the state of a drive.
+const psReadPagefile = `
+try {
+  Emit-OK (Get-PfState %[1]s)
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Pagefile $msg) $msg @{ drive = %[1]s }
+}
+`
+
+// psDeletePagefile removes the drive's setting and, when none is left,
+// switches automatic management back on.
+const psDeletePagefile = `
+try {
+  $set = Get-PfSetting %[1]s
+  if ($null -ne $set) { Remove-CimInstance -InputObject $set }
+  if (@(Get-CimInstance -ClassName Win32_PageFileSetting).Count -eq 0) { Set-AutomaticManaged $true }
+  Emit-OK $null
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Pagefile $msg) $msg @{ drive = %[1]s }
+}
+`
+
+// runPagefileEnvelope executes script (prepended with psPagefileHeader) and
+// parses the JSON envelope. Cancellation maps to PagefileErrorTimeout; other
+// transport failures to PagefileErrorUnknown.
+func (p *PagefileClient) runPagefileEnvelope(ctx context.Context, op, script string) (*pagefilePSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psPagefileHeader + "\n" + script
+	stdout, stderr, err := runPagefilePowerShell(ctx, p.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      p.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewPagefileError(PagefileErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewPagefileError(PagefileErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewPagefileError(PagefileErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp pagefilePSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewPagefileError(PagefileErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewPagefileError(mapPagefileKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapPagefileKind translates a PS-side "kind" string to a typed
+// PagefileErrorKind. Unknown values fall through to PagefileErrorUnknown.
+func mapPagefileKind(k string) PagefileErrorKind {
+	switch k {
+	case string(PagefileErrorInvalidParameter),
+		string(PagefileErrorNotFound),
+		string(PagefileErrorPermission),
+		string(PagefileErrorTimeout):
+		return PagefileErrorKind(k)
+	default:
+		return PagefileErrorUnknown
+	}
+}
+
+// canonicalPagefileDrive validates drive and returns it upper-cased.
+func (p *PagefileClient) canonicalPagefileDrive(drive string) (string, error) {
+	if !pagefileDriveRe.MatchString(drive) {
+		return "", NewPagefileError(PagefileErrorInvalidParameter,
+			fmt.Sprintf("drive must be a drive letter with its colon (e.g. C:), got %q", drive),
+			nil, map[string]string{"host": p.c.cfg.Host})
+	}
+	return strings.ToUpper(drive), nil
+}
+
+// validatePagefileSizes checks the size combination described on
+// PagefileInput.
+func validatePagefileSizes(in PagefileInput) string {
+	switch {
+	case in.InitialSizeMB < 0 || in.MaximumSizeMB < 0:
+		return "sizes must not be negative"
+	case in.AutomaticManaged && (in.InitialSizeMB != 0 || in.MaximumSizeMB != 0):
+		return "sizes cannot be set while the pagefile is automatically managed"
+	case (in.InitialSizeMB == 0) != (in.MaximumSizeMB == 0):
+		return "initial and maximum size must be set together"
+	case in.InitialSizeMB > in.MaximumSizeMB:
+		return fmt.Sprintf("initial size (%d MB) must not exceed maximum size (%d MB)", in.InitialSizeMB, in.MaximumSizeMB)
+	case in.MaximumSizeMB > 0xFFFFFFFF:
+		return fmt.Sprintf("maximum size %d MB is out of range", in.MaximumSizeMB)
+	}
+	return ""
+}
+
+// parsePagefileState decodes the "data" object of a Set/Read envelope. It
+// returns nil when the drive does not exist.
+func (p *PagefileClient) parsePagefileState(drive string, resp *pagefilePSResponse) (*PagefileState, error) {
+	var pl pagefileStatePayload
+	if jerr := json.Unmarshal(resp.Data, &pl); jerr != nil {
+		return nil, NewPagefileError(PagefileErrorUnknown,
+			"failed to parse pagefile state", jerr,
+			map[string]string{"host": p.c.cfg.Host, "drive": drive})
+	}
+	if !pl.DriveExists {
+		return nil, nil
+	}
+	st := &PagefileState{
+		Drive:            drive,
+		AutomaticManaged: pl.AutomaticManaged,
+		Configured:       pl.Configured,
+		InitialSizeMB:    pl.InitialSizeMB,
+		MaximumSizeMB:    pl.MaximumSizeMB,
+		Active:           pl.Active,
+		CurrentSizeMB:    pl.CurrentSizeMB,
+	}
+	switch {
+	case st.AutomaticManaged:
+		st.PendingReboot = !pl.AnyActive
+	case !st.Configured:
+		st.PendingReboot = st.Active
+	case st.InitialSizeMB == 0:
+		st.PendingReboot = !st.Active
+	default:
+		st.PendingReboot = !st.Active || st.CurrentSizeMB != st.InitialSizeMB
+	}
+	return st, nil
+}
+
+// Set applies the pagefile configuration and returns the observed state.
+func (p *PagefileClient) Set(ctx context.Context, in PagefileInput) (*PagefileState, error) {
+	drive, err := p.canonicalPagefileDrive(in.Drive)
+	if err != nil {
+		return nil, err
+	}
+	if msg := validatePagefileSizes(in); msg != "" {
+		return nil, NewPagefileError(PagefileErrorInvalidParameter, msg, nil,
+			map[string]string{"host": p.c.cfg.Host, "drive": drive})
+	}
+	script := fmt.Sprintf(psSetPagefile, psQuote(drive), "$"+psBool(in.AutomaticManaged), in.InitialSizeMB, in.MaximumSizeMB)
+	resp, err := p.runPagefileEnvelope(ctx, "set", script)
+	if err != nil {
+		return nil, err
+	}
+	st, err := p.parsePagefileState(drive, resp)
+	if err == nil && st == nil {
+		err = NewPagefileError(PagefileErrorNotFound, "drive "+drive+" disappeared while it was configured", nil,
+			map[string]string{"host": p.c.cfg.Host, "drive": drive})
+	}
+	return st, err
+}
+
+// Read returns the pagefile state of drive, or (nil, nil) when the drive
+// does not exist.
+func (p *PagefileClient) Read(ctx context.Context, drive string) (*PagefileState, error) {
+	drive, err := p.canonicalPagefileDrive(drive)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.runPagefileEnvelope(ctx, "read", fmt.Sprintf(psReadPagefile, psQuote(drive)))
+	if err != nil {
+		return nil, err
+	}
+	return p.parsePagefileState(drive, resp)
+}
+
+// Delete removes the drive's pagefile setting, restoring automatic
+// management when no setting is left.
+func (p *PagefileClient) Delete(ctx context.Context, drive string) error {
+	drive, err := p.canonicalPagefileDrive(drive)
+	if err != nil {
+		return err
+	}
+	_, err = p.runPagefileEnvelope(ctx, "delete", fmt.Sprintf(psDeletePagefile, psQuote(drive)))
+	return err
+}
@@ -0,0 +1,208 @@
+// Package winclient — unit tests for PagefileClient.
+//
+// These tests stub the package-level seam runPagefilePowerShell to inject
+// scripted stdout/stderr/err triples and to capture the generated script.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newPagefileTestClient(t *testing.T) *PagefileClient {
+	t.Helper()
+	c, err := New(Config{
+		Host:     "win01",
+		Username: "u",
+		Password: "p",
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return NewPagefileClient(c)
+}
+
+// stubPagefileRun replaces runPagefilePowerShell for the duration of a test
+// and returns a restore function (typically deferred).
+func stubPagefileRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runPagefilePowerShell
+	runPagefilePowerShell = fn
+	return func() { runPagefilePowerShell = prev }
+}
+
+func pagefileOK(t *testing.T, data any) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"ok": true, "data": data})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b) + "\n"
+}
+
+func TestPagefileError_IsAndHelper(t *testing.T) {
+	err := NewPagefileError(PagefileErrorNotFound, "no drive", errors.New("inner"), nil)
+	if !errors.Is(err, ErrPagefileNotFound) || errors.Is(err, ErrPagefileUnknown) {
+		t.Error("errors.Is should match on Kind only")
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("not_found should be classed ErrNotFound")
+	}
+	if !IsPagefileError(err, PagefileErrorNotFound) {
+		t.Error("IsPagefileError should match")
+	}
+	if errors.Unwrap(err).Error() != "inner" {
+		t.Error("Unwrap should return cause")
+	}
+}
+
+func TestMapPagefileKind(t *testing.T) {
+	cases := map[string]PagefileErrorKind{
+		"invalid_parameter": PagefileErrorInvalidParameter,
+		"not_found":         PagefileErrorNotFound,
+		"permission_denied": PagefileErrorPermission,
+		"timeout":           PagefileErrorTimeout,
+		"bogus":             PagefileErrorUnknown,
+	}
+	for in, want := range cases {
+		if got := mapPagefileKind(in); got != want {
+			t.Errorf("mapPagefileKind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPagefileSet_CustomSize(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	var script string
+	defer stubPagefileRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return pagefileOK(t, map[string]any{
+			"drive_exists": true, "automatic_managed": false, "configured": true,
+			"initial_size_mb": 4096, "maximum_size_mb": 8192,
+			"active": true, "current_size_mb": 2048, "any_active": true,
+		}), "", nil
+	})()
+
+	st, err := pc.Set(context.Background(), PagefileInput{Drive: "d:", InitialSizeMB: 4096, MaximumSizeMB: 8192})
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	for _, want := range []string{"$drive = 'D:'", "if ($false)", "InitialSize = [uint32]4096; MaximumSize = [uint32]8192", "Set-AutomaticManaged $false"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	if st.Drive != "D:" || st.AutomaticManaged || st.InitialSizeMB != 4096 || st.CurrentSizeMB != 2048 {
+		t.Errorf("state = %+v", st)
+	}
+	if !st.PendingReboot {
+		t.Error("a pagefile in use at 2048 MB with a 4096 MB initial size needs a reboot")
+	}
+}
+
+func TestPagefileSet_Validation(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	defer stubPagefileRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		t.Fatal("no remote call expected")
+		return "", "", nil
+	})()
+	cases := map[string]PagefileInput{
+		"bad drive":          {Drive: "C:\\"},
+		"drive injection":    {Drive: "C:'; Remove-Item"},
+		"sizes with auto":    {Drive: "C:", AutomaticManaged: true, InitialSizeMB: 1024, MaximumSizeMB: 2048},
+		"only initial":       {Drive: "C:", InitialSizeMB: 1024},
+		"initial over max":   {Drive: "C:", InitialSizeMB: 4096, MaximumSizeMB: 2048},
+		"negative":           {Drive: "C:", InitialSizeMB: -1, MaximumSizeMB: 2048},
+		"maximum over range": {Drive: "C:", InitialSizeMB: 1, MaximumSizeMB: 1 << 33},
+	}
+	for name, in := range cases {
+		if _, err := pc.Set(context.Background(), in); !IsPagefileError(err, PagefileErrorInvalidParameter) {
+			t.Errorf("%s: err = %v, want invalid_parameter", name, err)
+		}
+	}
+}
+
+func TestPagefileSet_DriveNotFound(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	defer stubPagefileRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return `{"ok":false,"kind":"not_found","message":"drive Q: does not exist","context":{"drive":"Q:"}}` + "\n", "", nil
+	})()
+	_, err := pc.Set(context.Background(), PagefileInput{Drive: "Q:"})
+	var pe *PagefileError
+	if !errors.As(err, &pe) || pe.Kind != PagefileErrorNotFound || pe.Context["drive"] != "Q:" || pe.Context["operation"] != "set" {
+		t.Errorf("err = %#v", err)
+	}
+}
+
+func TestParsePagefileState_PendingReboot(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	cases := []struct {
+		name string
+		data map[string]any
+		want bool
+	}{
+		{"automatic, in use", map[string]any{"automatic_managed": true, "any_active": true}, false},
+		{"automatic, none in use", map[string]any{"automatic_managed": true}, true},
+		{"system managed, in use", map[string]any{"configured": true, "active": true, "current_size_mb": 1280, "any_active": true}, false},
+		{"system managed, not yet in use", map[string]any{"configured": true, "any_active": true}, true},
+		{"custom, applied", map[string]any{"configured": true, "initial_size_mb": 4096, "maximum_size_mb": 4096, "active": true, "current_size_mb": 4096}, false},
+		{"custom, resized", map[string]any{"configured": true, "initial_size_mb": 2048, "maximum_size_mb": 4096, "active": true, "current_size_mb": 4096}, true},
+		{"no setting, still in use", map[string]any{"active": true, "current_size_mb": 1024}, true},
+		{"no setting, none in use", map[string]any{}, false},
+	}
+	for _, tc := range cases {
+		tc.data["drive_exists"] = true
+		raw, _ := json.Marshal(tc.data)
+		st, err := pc.parsePagefileState("C:", &pagefilePSResponse{OK: true, Data: raw})
+		if err != nil || st == nil {
+			t.Fatalf("%s: %+v, %v", tc.name, st, err)
+		}
+		if st.PendingReboot != tc.want {
+			t.Errorf("%s: PendingReboot = %v, want %v", tc.name, st.PendingReboot, tc.want)
+		}
+	}
+}
+
+func TestPagefileRead_MissingDrive(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	defer stubPagefileRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return pagefileOK(t, map[string]any{"drive_exists": false}), "", nil
+	})()
+	st, err := pc.Read(context.Background(), "E:")
+	if err != nil || st != nil {
+		t.Errorf("Read = %+v, %v; want nil, nil", st, err)
+	}
+}
+
+func TestPagefileDelete(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	var script string
+	defer stubPagefileRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return pagefileOK(t, nil), "", nil
+	})()
+	if err := pc.Delete(context.Background(), "c:"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, want := range []string{"Get-PfSetting 'C:'", "Remove-CimInstance", "Set-AutomaticManaged $true"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestPagefileRun_Cancelled(t *testing.T) {
+	pc := newPagefileTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer stubPagefileRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.Canceled
+	})()
+	if _, err := pc.Read(ctx, "C:"); !IsPagefileError(err, PagefileErrorTimeout) {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package winclient: types for the windows_pagefile resource.
+//
+// PagefileState combines the machine-wide Win32_ComputerSystem.
+// AutomaticManagedPagefile flag, the per-drive Win32_PageFileSetting and the
+// pagefile actually in use since boot (Win32_PageFileUsage).
+// PagefileErrorKind / PagefileError follow the same shape as FeatureError so
+// the resource layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PagefileErrorKind categorises errors returned by WindowsPagefileClient.
+type PagefileErrorKind string
+
+const (
+	// PagefileErrorInvalidParameter: drive or sizes rejected before anything
+	// ran, or by WMI.
+	PagefileErrorInvalidParameter PagefileErrorKind = "invalid_parameter"
+	// PagefileErrorNotFound: the drive does not exist on the host.
+	PagefileErrorNotFound   PagefileErrorKind = "not_found"
+	PagefileErrorPermission PagefileErrorKind = "permission_denied"
+	PagefileErrorTimeout    PagefileErrorKind = "timeout"
+	PagefileErrorUnknown    PagefileErrorKind = "unknown"
+)
+
+// PagefileError is the structured error type returned by
+// WindowsPagefileClient.
+type PagefileError struct {
+	Kind    PagefileErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *PagefileError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_pagefile [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_pagefile [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *PagefileError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *PagefileError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*PagefileError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewPagefileError constructs a *PagefileError.
+func NewPagefileError(kind PagefileErrorKind, msg string, cause error, ctx map[string]string) *PagefileError {
+	return &PagefileError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsPagefileError reports whether err is a *PagefileError of the given kind.
+func IsPagefileError(err error, kind PagefileErrorKind) bool {
+	var pe *PagefileError
+	if errors.As(err, &pe) {
+		return pe.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrPagefileInvalidParameter = &PagefileError{Kind: PagefileErrorInvalidParameter}
+	ErrPagefileNotFound         = &PagefileError{Kind: PagefileErrorNotFound}
+	ErrPagefilePermission       = &PagefileError{Kind: PagefileErrorPermission}
+	ErrPagefileTimeout          = &PagefileError{Kind: PagefileErrorTimeout}
+	ErrPagefileUnknown          = &PagefileError{Kind: PagefileErrorUnknown}
+)
+
+// PagefileInput is the desired pagefile configuration of one drive.
+//
+// Zero-value semantics:
+//   - AutomaticManaged true: Windows manages every pagefile; both sizes must
+//     be zero.
+//   - AutomaticManaged false, both sizes zero: a pagefile on Drive whose size
+//     Windows manages ("System managed size").
+//   - AutomaticManaged false, both sizes set: a pagefile of that custom size.
+type PagefileInput struct {
+	// Drive is a drive letter with its colon, e.g. "C:".
+	Drive            string
+	AutomaticManaged bool
+	InitialSizeMB    int64
+	MaximumSizeMB    int64
+}
+
+// PagefileState is the observed pagefile configuration of one drive.
+type PagefileState struct {
+	// Drive is the upper-case drive letter with its colon.
+	Drive string
+	// AutomaticManaged is Win32_ComputerSystem.AutomaticManagedPagefile. It
+	// is machine-wide, not per drive.
+	AutomaticManaged bool
+	// Configured is true when a Win32_PageFileSetting exists for the drive.
+	// InitialSizeMB and MaximumSizeMB are 0 when it does not, or when the
+	// size is system managed.
+	Configured    bool
+	InitialSizeMB int64
+	MaximumSizeMB int64
+	// Active is true when a pagefile on the drive is in use
+	// (Win32_PageFileUsage); CurrentSizeMB is its allocated size.
+	Active        bool
+	CurrentSizeMB int64
+	// PendingReboot is true when the pagefile in use does not match the
+	// configuration, so a reboot is needed to apply it. See pagefile.go.
+	PendingReboot bool
+}
+
+// WindowsPagefileClient is the contract for the windows_pagefile resource.
+type WindowsPagefileClient interface {
+	// Set applies in and returns the observed state. It returns
+	// PagefileErrorNotFound when the drive does not exist.
+	Set(ctx context.Context, in PagefileInput) (*PagefileState, error)
+
+	// Read returns the state of drive, or (nil, nil) when the drive does not
+	// exist.
+	Read(ctx context.Context, drive string) (*PagefileState, error)
+
+	// Delete removes the drive's Win32_PageFileSetting. When no setting is
+	// left, automatic management is switched back on so the host is not left
+	// without a pagefile after the next reboot. It is idempotent.
+	Delete(ctx context.Context, drive string) error
+}
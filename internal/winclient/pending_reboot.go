@@ -0,0 +1,184 @@
+// Package winclient: pending-reboot detection over WinRM.
+//
+// PendingRebootClient is the concrete WindowsPendingRebootClient backing the
+// windows_pending_reboot data source. One script reads the registry signals
+// Windows and its installers leave behind when a reboot is required and emits
+// the list of reasons found.
+//
+// Security invariants:
+//   - The script takes no user input; nothing is interpolated.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time assertion: PendingRebootClient satisfies
+// WindowsPendingRebootClient.
+var _ WindowsPendingRebootClient = (*PendingRebootClient)(nil)
+
+// PendingRebootClient is the PowerShell/WinRM-backed
+// WindowsPendingRebootClient.
+type PendingRebootClient struct {
+	c *Client
+}
+
+// NewPendingRebootClient wraps the given WinRM Client.
+func NewPendingRebootClient(c *Client) *PendingRebootClient { return &PendingRebootClient{c: c} }
+
+// runPendingRebootPowerShell is the package-level indirection used by
+// PendingRebootClient. Tests may override it; production code must not.
+var runPendingRebootPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// pendingRebootPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type pendingRebootPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// pendingRebootPayload mirrors the object emitted by psGetPendingReboot.
+type pendingRebootPayload struct {
+	Reasons []string `json:"reasons"`
+}
+
+// psPendingRebootHeader prepends Emit-OK/Emit-Err and Classify-PendingReboot.
+const psPendingRebootHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Classify-PendingReboot([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied' -or $Msg -match 'not allowed') { return 'permission_denied' }
+  return 'unknown'
+}
+`
+
+// psGetPendingReboot checks, in order, the signals listed by the
+// PendingReboot* constants. The Component Based Servicing and WindowsUpdate
+// signals are keys whose mere presence means a reboot is required; the other
+// two are values. The computer-rename check matches the hostname read
+// (hostname.go): NetBIOS name and Tcpip DNS host name, case-insensitive.
+const psGetPendingReboot = `
+try {
+  $reasons = New-Object System.Collections.Generic.List[string]
+  if (Test-Path 'HKLM:\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\RebootPending') { $reasons.Add('component_based_servicing') }
+  if (Test-Path 'HKLM:\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired') { $reasons.Add('windows_update') }
+  $sm = Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\Session Manager' -ErrorAction Stop
+  if (@($sm.PendingFileRenameOperations | Where-Object { $_ }).Count -gt 0) { $reasons.Add('pending_file_rename') }
+  $act  = [string](Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ActiveComputerName' -Name ComputerName -ErrorAction Stop).ComputerName
+  $pend = [string](Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\ComputerName\ComputerName'       -Name ComputerName -ErrorAction Stop).ComputerName
+  $rename = ($act.ToLowerInvariant() -ne $pend.ToLowerInvariant())
+  $tcp = Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Services\Tcpip\Parameters' -ErrorAction SilentlyContinue
+  if ($tcp -and $tcp.Hostname -and $tcp.'NV Hostname' -and ([string]$tcp.Hostname).ToLowerInvariant() -ne ([string]$tcp.'NV Hostname').ToLowerInvariant()) { $rename = $true }
+  if ($rename) { $reasons.Add('computer_rename') }
+  Emit-OK ([ordered]@{ reasons = @($reasons) })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-PendingReboot $msg) $msg @{}
+}
+`
+
+// runPendingRebootEnvelope executes script (prepended with
+// psPendingRebootHeader) and parses the JSON envelope. Cancellation maps to
+// PendingRebootErrorTimeout; other transport failures to
+// PendingRebootErrorUnknown.
+func (p *PendingRebootClient) runPendingRebootEnvelope(ctx context.Context, op, script string) (*pendingRebootPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psPendingRebootHeader + "\n" + script
+	stdout, stderr, err := runPendingRebootPowerShell(ctx, p.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      p.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewPendingRebootError(PendingRebootErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewPendingRebootError(PendingRebootErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewPendingRebootError(PendingRebootErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp pendingRebootPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewPendingRebootError(PendingRebootErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewPendingRebootError(mapPendingRebootKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapPendingRebootKind translates a PS-side "kind" string to a typed
+// PendingRebootErrorKind. Unknown values fall through to
+// PendingRebootErrorUnknown.
+func mapPendingRebootKind(k string) PendingRebootErrorKind {
+	switch k {
+	case string(PendingRebootErrorPermission),
+		string(PendingRebootErrorTimeout):
+		return PendingRebootErrorKind(k)
+	default:
+		return PendingRebootErrorUnknown
+	}
+}
+
+// Get checks the pending-reboot registry signals of the host.
+func (p *PendingRebootClient) Get(ctx context.Context) (*PendingReboot, error) {
+	resp, err := p.runPendingRebootEnvelope(ctx, "get", psGetPendingReboot)
+	if err != nil {
+		return nil, err
+	}
+	var pl pendingRebootPayload
+	if jerr := json.Unmarshal(resp.Data, &pl); jerr != nil {
+		return nil, NewPendingRebootError(PendingRebootErrorUnknown,
+			"failed to parse pending reboot state", jerr,
+			map[string]string{"host": p.c.cfg.Host})
+	}
+	reasons := make([]string, 0, len(pl.Reasons))
+	for _, r := range pl.Reasons {
+		if r != "" {
+			reasons = append(reasons, r)
+		}
+	}
+	return &PendingReboot{Pending: len(reasons) > 0, Reasons: reasons}, nil
+}
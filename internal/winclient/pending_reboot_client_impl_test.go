@@ -0,0 +1,114 @@
+// Package winclient — unit tests for PendingRebootClient.
+//
+// These tests stub the package-level seam runPendingRebootPowerShell to
+// inject scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubPendingRebootRun replaces runPendingRebootPowerShell for the duration
+// of a test and returns a restore function (typically deferred).
+func stubPendingRebootRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runPendingRebootPowerShell
+	runPendingRebootPowerShell = fn
+	return func() { runPendingRebootPowerShell = prev }
+}
+
+func pendingRebootStdout(t *testing.T, v any) func(context.Context, *Client, string) (string, string, error) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return string(b) + "\n", "", nil
+	}
+}
+
+func TestPendingRebootGet(t *testing.T) {
+	cases := []struct {
+		name    string
+		reasons any
+		want    PendingReboot
+	}{
+		{"nothing pending", []string{}, PendingReboot{Pending: false, Reasons: []string{}}},
+		{"null reasons", nil, PendingReboot{Pending: false, Reasons: []string{}}},
+		{"servicing and rename", []string{PendingRebootComponentServicing, PendingRebootComputerRename},
+			PendingReboot{Pending: true, Reasons: []string{"component_based_servicing", "computer_rename"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer stubPendingRebootRun(pendingRebootStdout(t, map[string]any{
+				"ok": true, "data": map[string]any{"reasons": tc.reasons},
+			}))()
+			got, err := NewPendingRebootClient(newLouTestClient(t)).Get(context.Background())
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tc.want) {
+				t.Errorf("Get = %#v, want %#v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPendingRebootGet_PermissionDenied(t *testing.T) {
+	defer stubPendingRebootRun(pendingRebootStdout(t, map[string]any{
+		"ok": false, "kind": "permission_denied", "message": "Requested registry access is not allowed.",
+	}))()
+	_, err := NewPendingRebootClient(newLouTestClient(t)).Get(context.Background())
+	if !errors.Is(err, ErrPendingRebootPermission) {
+		t.Fatalf("expected permission_denied, got %v", err)
+	}
+	var pe *PendingRebootError
+	errors.As(err, &pe)
+	if pe.Context["host"] != "win01" || pe.Context["operation"] != "get" {
+		t.Errorf("context = %v", pe.Context)
+	}
+}
+
+func TestPendingRebootGet_Timeout(t *testing.T) {
+	defer stubPendingRebootRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.Canceled
+	})()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewPendingRebootClient(newLouTestClient(t)).Get(ctx)
+	if !IsPendingRebootError(err, PendingRebootErrorTimeout) {
+		t.Errorf("expected timeout on cancelled ctx, got %v", err)
+	}
+}
+
+func TestPendingRebootGet_ScriptShape(t *testing.T) {
+	var captured string
+	defer stubPendingRebootRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		captured = script
+		return "no json\n", "", nil
+	})()
+	_, err := NewPendingRebootClient(newLouTestClient(t)).Get(context.Background())
+	if !IsPendingRebootError(err, PendingRebootErrorUnknown) {
+		t.Errorf("missing JSON envelope should yield unknown, got %v", err)
+	}
+	for _, want := range []string{
+		`Component Based Servicing\RebootPending`,
+		`WindowsUpdate\Auto Update\RebootRequired`,
+		"PendingFileRenameOperations",
+		`ComputerName\ActiveComputerName`,
+		`$tcp.'NV Hostname'`,
+		"'" + PendingRebootComponentServicing + "'",
+		"'" + PendingRebootWindowsUpdate + "'",
+		"'" + PendingRebootFileRename + "'",
+		"'" + PendingRebootComputerRename + "'",
+	} {
+		if !strings.Contains(captured, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package winclient: types for the windows_pending_reboot data source.
+//
+// PendingReboot reports whether the host has a reboot queued and which of the
+// well-known registry signals say so. PendingRebootErrorKind /
+// PendingRebootError follow the same shape as SystemInfoError.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PendingRebootErrorKind categorises errors returned by
+// WindowsPendingRebootClient.
+type PendingRebootErrorKind string
+
+const (
+	PendingRebootErrorPermission PendingRebootErrorKind = "permission_denied"
+	PendingRebootErrorTimeout    PendingRebootErrorKind = "timeout"
+	PendingRebootErrorUnknown    PendingRebootErrorKind = "unknown"
+)
+
+// PendingRebootError is the structured error type returned by
+// WindowsPendingRebootClient.
+type PendingRebootError struct {
+	Kind    PendingRebootErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *PendingRebootError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_pending_reboot [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_pending_reboot [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *PendingRebootError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *PendingRebootError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*PendingRebootError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewPendingRebootError constructs a *PendingRebootError.
+func NewPendingRebootError(kind PendingRebootErrorKind, msg string, cause error, ctx map[string]string) *PendingRebootError {
+	return &PendingRebootError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsPendingRebootError reports whether err is a *PendingRebootError of the
+// given kind.
+func IsPendingRebootError(err error, kind PendingRebootErrorKind) bool {
+	var pe *PendingRebootError
+	if errors.As(err, &pe) {
+		return pe.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrPendingRebootPermission = &PendingRebootError{Kind: PendingRebootErrorPermission}
+	ErrPendingRebootTimeout    = &PendingRebootError{Kind: PendingRebootErrorTimeout}
+	ErrPendingRebootUnknown    = &PendingRebootError{Kind: PendingRebootErrorUnknown}
+)
+
+// Reasons reported in PendingReboot.Reasons, in the order they are checked.
+const (
+	// PendingRebootComponentServicing: the Component Based Servicing
+	// RebootPending key exists (feature or update servicing).
+	PendingRebootComponentServicing = "component_based_servicing"
+	// PendingRebootWindowsUpdate: the WindowsUpdate Auto Update
+	// RebootRequired key exists.
+	PendingRebootWindowsUpdate = "windows_update"
+	// PendingRebootFileRename: Session Manager PendingFileRenameOperations
+	// is set (files replaced on the next boot).
+	PendingRebootFileRename = "pending_file_rename"
+	// PendingRebootComputerRename: the computer name or the DNS host name
+	// differs from the value queued for the next boot.
+	PendingRebootComputerRename = "computer_rename"
+)
+
+// PendingReboot is the pending-reboot state of the host.
+type PendingReboot struct {
+	// Pending is true when at least one reason is present.
+	Pending bool
+	// Reasons lists the PendingReboot* reasons found; empty when nothing
+	// is pending.
+	Reasons []string
+}
+
+// WindowsPendingRebootClient is the contract for the windows_pending_reboot
+// data source.
+type WindowsPendingRebootClient interface {
+	// Get checks the pending-reboot registry signals of the host.
+	Get(ctx context.Context) (*PendingReboot, error)
+}
@@ -0,0 +1,123 @@
+// Package winclient: user-supplied PowerShell scripts over WinRM.
+//
+// PowerShellScriptClient backs the windows_powershell_script resource. The
+// scripts are run exactly as written, through the same -EncodedCommand
+// bootstrap as every other script (so $ErrorActionPreference is 'Stop' and a
+// failing native command is caught by the status line, see
+// script_status.go). They are not wrapped in an envelope: a script fails
+// when it throws, exits non-zero or leaves $? False, and its stderr is
+// surfaced in the error.
+//
+// Security invariants:
+//   - Scripts travel on stdin via Client.RunPowerShell, never on the command
+//     line.
+//   - Script output is only placed in error context, truncated; the script
+//     body itself is never logged.
+package winclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Compile-time assertion: PowerShellScriptClient satisfies
+// WindowsPowerShellScriptClient.
+var _ WindowsPowerShellScriptClient = (*PowerShellScriptClient)(nil)
+
+// PowerShellScriptClient is the PowerShell/WinRM-backed
+// WindowsPowerShellScriptClient.
+type PowerShellScriptClient struct {
+	c *Client
+}
+
+// NewPowerShellScriptClient wraps the given WinRM Client.
+func NewPowerShellScriptClient(c *Client) *PowerShellScriptClient {
+	return &PowerShellScriptClient{c: c}
+}
+
+// runScriptPowerShell is the package-level indirection used by
+// PowerShellScriptClient. Tests may override it; production code must not.
+var runScriptPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// scriptStderrSummaryLines is how many leading stderr lines are quoted in
+// the error message; the full (truncated) stderr stays in Context.
+const scriptStderrSummaryLines = 5
+
+// Run implements WindowsPowerShellScriptClient.Run.
+func (p *PowerShellScriptClient) Run(ctx context.Context, op, script string) (string, error) {
+	ctx = withCommandOp(ctx, op)
+	stdout, stderr, err := runScriptPowerShell(ctx, p.c, script)
+	if err != nil {
+		return "", p.runError(ctx, op, stdout, stderr, err)
+	}
+	return stdout, nil
+}
+
+// Read implements WindowsPowerShellScriptClient.Read.
+func (p *PowerShellScriptClient) Read(ctx context.Context, script string) (string, error) {
+	stdout, err := p.Run(ctx, "read", script)
+	if err != nil {
+		return "", err
+	}
+	out := strings.TrimSpace(strings.TrimPrefix(stdout, "\uFEFF"))
+	if out == "" || out == "null" {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if jerr := json.Compact(&buf, []byte(out)); jerr != nil {
+		return "", NewPowerShellScriptError(PowerShellScriptErrorInvalidOutput,
+			"read script output is not JSON; print a single value with ConvertTo-Json and "+
+				"send logging to Write-Verbose or Write-Warning",
+			jerr, map[string]string{
+				"operation": "read",
+				"host":      p.c.cfg.Host,
+				"stdout":    truncate(stdout, 2048),
+			})
+	}
+	return buf.String(), nil
+}
+
+// runError classifies a failed run: cancellation maps to timeout, a run that
+// carries an exit code to script_failed, anything else (dial, auth) to
+// unknown.
+func (p *PowerShellScriptClient) runError(ctx context.Context, op, stdout, stderr string, err error) error {
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      p.c.cfg.Host,
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return NewPowerShellScriptError(PowerShellScriptErrorTimeout,
+			fmt.Sprintf("%s script timed out or was cancelled", op), ctxErr, baseCtx)
+	}
+	baseCtx["stdout"] = truncate(stdout, 2048)
+	baseCtx["stderr"] = truncate(stderr, 4096)
+	if code := CommandExitCode(err); code != 0 {
+		msg := fmt.Sprintf("%s script failed with exit code %d", op, code)
+		if summary := stderrSummary(stderr); summary != "" {
+			msg += ": " + summary
+		}
+		return NewPowerShellScriptError(PowerShellScriptErrorFailed, msg, nil, withExitCode(baseCtx, err))
+	}
+	return NewPowerShellScriptError(PowerShellScriptErrorUnknown,
+		fmt.Sprintf("WinRM transport error during %s script", op), err, baseCtx)
+}
+
+// stderrSummary returns the first non-blank stderr lines, joined with "; ".
+func stderrSummary(stderr string) string {
+	var lines []string
+	for _, l := range strings.Split(stderr, "\n") {
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		}
+		lines = append(lines, l)
+		if len(lines) == scriptStderrSummaryLines {
+			break
+		}
+	}
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,110 @@
+// Package winclient — unit tests for PowerShellScriptClient.
+//
+// These tests stub the package-level seam runScriptPowerShell to inject
+// scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubScriptRun replaces runScriptPowerShell for the duration of a test and
+// returns a restore function (typically deferred).
+func stubScriptRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runScriptPowerShell
+	runScriptPowerShell = fn
+	return func() { runScriptPowerShell = prev }
+}
+
+func TestPowerShellScriptRun_PassesScriptVerbatim(t *testing.T) {
+	const userScript = "New-Item -ItemType Directory -Path 'C:\\App' -Force | Out-Null\nWrite-Output done"
+	var got string
+	defer stubScriptRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		got = s
+		return "done\r\n", "", nil
+	})()
+
+	out, err := NewPowerShellScriptClient(newTestClient(t)).Run(context.Background(), "create", userScript)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got != userScript || out != "done\r\n" {
+		t.Errorf("script = %q, stdout = %q", got, out)
+	}
+}
+
+func TestPowerShellScriptRun_FailureSurfacesStderr(t *testing.T) {
+	stderr := "Cannot find path 'C:\\Missing' because it does not exist.\r\n" +
+		"At line:1 char:1\r\n+ Get-Item C:\\Missing\r\n"
+	defer stubScriptRun(func(context.Context, *Client, string) (string, string, error) {
+		return "partial\r\n", stderr, &TransportError{Kind: FailureCommand, Err: errors.New("exit 1"), ExitCode: 1}
+	})()
+
+	_, err := NewPowerShellScriptClient(newTestClient(t)).Run(context.Background(), "delete", "Get-Item C:\\Missing")
+	if !errors.Is(err, ErrPowerShellScriptFailed) {
+		t.Fatalf("err = %v, want script_failed", err)
+	}
+	var pe *PowerShellScriptError
+	errors.As(err, &pe)
+	if !strings.Contains(pe.Message, "delete script failed with exit code 1: Cannot find path") ||
+		!strings.Contains(pe.Message, "; At line:1 char:1") {
+		t.Errorf("Message = %q", pe.Message)
+	}
+	if pe.Context["exit_code"] != "1" || pe.Context["stderr"] != stderr || pe.Context["stdout"] != "partial\r\n" {
+		t.Errorf("Context = %+v", pe.Context)
+	}
+}
+
+func TestPowerShellScriptRun_TransportAndTimeout(t *testing.T) {
+	defer stubScriptRun(func(context.Context, *Client, string) (string, string, error) {
+		return "", "", &TransportError{Kind: FailureDial, Err: errors.New("connection refused")}
+	})()
+	_, err := NewPowerShellScriptClient(newTestClient(t)).Run(context.Background(), "create", "x")
+	if !IsPowerShellScriptError(err, PowerShellScriptErrorUnknown) || TransportFailureKind(err) != FailureDial {
+		t.Errorf("err = %v, want unknown wrapping the dial failure", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer stubScriptRun(func(ctx context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", ctx.Err()
+	})()
+	_, err = NewPowerShellScriptClient(newTestClient(t)).Run(ctx, "update", "x")
+	if !errors.Is(err, ErrPowerShellScriptTimeout) {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
+
+func TestPowerShellScriptRead(t *testing.T) {
+	cases := []struct {
+		name, stdout, want string
+		wantKind           PowerShellScriptErrorKind
+	}{
+		{name: "multi-line ConvertTo-Json", stdout: "\uFEFF{\r\n    \"path\":  \"C:\\\\App\",\r\n    \"acl\":  [1, 2]\r\n}\r\n",
+			want: `{"path":"C:\\App","acl":[1,2]}`},
+		{name: "scalar", stdout: "42\r\n", want: "42"},
+		{name: "empty means gone", stdout: "\r\n", want: ""},
+		{name: "null means gone", stdout: "null\r\n", want: ""},
+		{name: "host output mixed in", stdout: "Checking...\r\n{\"a\":1}\r\n", wantKind: PowerShellScriptErrorInvalidOutput},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			defer stubScriptRun(func(context.Context, *Client, string) (string, string, error) {
+				return c.stdout, "", nil
+			})()
+			got, err := NewPowerShellScriptClient(newTestClient(t)).Read(context.Background(), "x")
+			if c.wantKind != "" {
+				if !IsPowerShellScriptError(err, c.wantKind) {
+					t.Fatalf("err = %v, want %s", err, c.wantKind)
+				}
+				return
+			}
+			if err != nil || got != c.want {
+				t.Errorf("Read = %q, %v; want %q", got, err, c.want)
+			}
+		})
+	}
+}
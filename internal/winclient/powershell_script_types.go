@@ -0,0 +1,97 @@
+// Package winclient: types for the windows_powershell_script resource.
+//
+// PowerShellScriptErrorKind / PowerShellScriptError follow the same shape as
+// FeatureError so the resource layer can branch with errors.Is. Unlike the
+// other clients, the scripts are supplied by the user and emit no JSON
+// envelope: a failure is a non-zero exit or a terminating error, and the
+// script's stderr is kept in the error context.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PowerShellScriptErrorKind categorises errors returned by
+// WindowsPowerShellScriptClient.
+type PowerShellScriptErrorKind string
+
+const (
+	// PowerShellScriptErrorFailed: the script threw a terminating error,
+	// exited non-zero or left $? False.
+	PowerShellScriptErrorFailed PowerShellScriptErrorKind = "script_failed"
+	// PowerShellScriptErrorInvalidOutput: read_script printed something
+	// that is not JSON.
+	PowerShellScriptErrorInvalidOutput PowerShellScriptErrorKind = "invalid_output"
+	PowerShellScriptErrorTimeout       PowerShellScriptErrorKind = "timeout"
+	PowerShellScriptErrorUnknown       PowerShellScriptErrorKind = "unknown"
+)
+
+// PowerShellScriptError is the structured error type returned by
+// WindowsPowerShellScriptClient. Context carries "operation", "host" and,
+// when the script ran, "stdout", "stderr" and "exit_code".
+type PowerShellScriptError struct {
+	Kind    PowerShellScriptErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *PowerShellScriptError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_powershell_script [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_powershell_script [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *PowerShellScriptError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *PowerShellScriptError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*PowerShellScriptError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewPowerShellScriptError constructs a *PowerShellScriptError.
+func NewPowerShellScriptError(kind PowerShellScriptErrorKind, msg string, cause error, ctx map[string]string) *PowerShellScriptError {
+	return &PowerShellScriptError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsPowerShellScriptError reports whether err is a *PowerShellScriptError of
+// the given kind.
+func IsPowerShellScriptError(err error, kind PowerShellScriptErrorKind) bool {
+	var pe *PowerShellScriptError
+	if errors.As(err, &pe) {
+		return pe.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrPowerShellScriptFailed        = &PowerShellScriptError{Kind: PowerShellScriptErrorFailed}
+	ErrPowerShellScriptInvalidOutput = &PowerShellScriptError{Kind: PowerShellScriptErrorInvalidOutput}
+	ErrPowerShellScriptTimeout       = &PowerShellScriptError{Kind: PowerShellScriptErrorTimeout}
+	ErrPowerShellScriptUnknown       = &PowerShellScriptError{Kind: PowerShellScriptErrorUnknown}
+)
+
+// WindowsPowerShellScriptClient runs user-supplied PowerShell scripts.
+type WindowsPowerShellScriptClient interface {
+	// Run executes script and returns its standard output. op names the
+	// script ("create", "update", "delete") in errors.
+	Run(ctx context.Context, op, script string) (string, error)
+
+	// Read executes script and returns its standard output as compact JSON.
+	// Empty output, or a literal null, is returned as "" and means the
+	// object the script describes does not exist.
+	Read(ctx context.Context, script string) (string, error)
+}
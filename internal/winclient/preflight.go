@@ -0,0 +1,112 @@
+// Package winclient: provider-level pre-flight checks.
+//
+// IsAdministrator backs the provider's require_admin option: it runs once at
+// Configure time so a non-elevated WinRM account fails with one clear
+// diagnostic instead of access-denied errors deep inside individual
+// resources. PowerShellVersion likewise probes a custom powershell_path once,
+// so a wrong executable is reported as a configuration error.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// runPreflightPowerShell is the package-level indirection used by the
+// pre-flight checks. Tests may override it; production code must not.
+var runPreflightPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// psIsAdministrator reports the WinRM token's identity and whether it holds
+// the BUILTIN\Administrators role. A local administrator that is not the
+// built-in Administrator gets a filtered (non-elevated) token over WinRM
+// unless LocalAccountTokenFilterPolicy=1, and therefore reports false here.
+const psIsAdministrator = `
+$ErrorActionPreference = 'Stop'
+$id = [Security.Principal.WindowsIdentity]::GetCurrent()
+$p  = New-Object Security.Principal.WindowsPrincipal($id)
+$o  = [ordered]@{
+  user     = [string]$id.Name
+  is_admin = [bool]$p.IsInRole([Security.Principal.WindowsBuiltInRole]::Administrator)
+}
+[Console]::Out.WriteLine(($o | ConvertTo-Json -Compress))
+`
+
+// AdminCheckResult is the outcome of IsAdministrator.
+type AdminCheckResult struct {
+	// User is the remote identity (DOMAIN\user) the WinRM session runs as.
+	User string
+	// IsAdmin is true when the session token holds the Administrators role.
+	IsAdmin bool
+}
+
+// IsAdministrator reports whether the WinRM session runs with an elevated
+// token in BUILTIN\Administrators.
+func (c *Client) IsAdministrator(ctx context.Context) (*AdminCheckResult, error) {
+	ctx = withCommandOp(ctx, "is_administrator")
+	stdout, stderr, err := runPreflightPowerShell(ctx, c, psIsAdministrator)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("winclient: administrator check timed out or was cancelled: %w", ctxErr)
+		}
+		return nil, fmt.Errorf("winclient: administrator check: %w (stderr: %s)", err, truncate(stderr, 512))
+	}
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		return nil, fmt.Errorf("winclient: administrator check returned no JSON (stdout: %s)", truncate(stdout, 512))
+	}
+	var out struct {
+		User    string `json:"user"`
+		IsAdmin bool   `json:"is_admin"`
+	}
+	if jerr := json.Unmarshal([]byte(line), &out); jerr != nil {
+		return nil, fmt.Errorf("winclient: administrator check: invalid JSON: %w", jerr)
+	}
+	return &AdminCheckResult{User: out.User, IsAdmin: out.IsAdmin}, nil
+}
+
+// psPowerShellVersion reports the edition and version of the PowerShell the
+// bootstrap ran under.
+const psPowerShellVersion = `
+$o = [ordered]@{
+  edition = [string]$PSVersionTable.PSEdition
+  version = [string]$PSVersionTable.PSVersion
+}
+[Console]::Out.WriteLine(($o | ConvertTo-Json -Compress))
+`
+
+// PowerShellInfo is the outcome of PowerShellVersion.
+type PowerShellInfo struct {
+	// Edition is "Desktop" for Windows PowerShell and "Core" for PowerShell 7+.
+	Edition string
+	// Version is $PSVersionTable.PSVersion, e.g. "5.1.20348.2849" or "7.4.6".
+	Version string
+}
+
+// PowerShellVersion runs a trivial script through Config.PowerShellPath and
+// reports which PowerShell answered. A missing executable fails as a command
+// failure whose stderr names it ("'pwsh.exe' is not recognized ...").
+func (c *Client) PowerShellVersion(ctx context.Context) (*PowerShellInfo, error) {
+	ctx = withCommandOp(ctx, "powershell_version")
+	stdout, stderr, err := runPreflightPowerShell(ctx, c, psPowerShellVersion)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("winclient: powershell version probe timed out or was cancelled: %w", ctxErr)
+		}
+		return nil, fmt.Errorf("winclient: powershell version probe: %w (stderr: %s)", err, truncate(stderr, 512))
+	}
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		return nil, fmt.Errorf("winclient: powershell version probe returned no JSON (stdout: %s)", truncate(stdout, 512))
+	}
+	var out struct {
+		Edition string `json:"edition"`
+		Version string `json:"version"`
+	}
+	if jerr := json.Unmarshal([]byte(line), &out); jerr != nil {
+		return nil, fmt.Errorf("winclient: powershell version probe: invalid JSON: %w", jerr)
+	}
+	return &PowerShellInfo{Edition: out.Edition, Version: out.Version}, nil
+}
@@ -0,0 +1,91 @@
+// Package winclient — unit tests for the provider pre-flight checks.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubPreflightRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runPreflightPowerShell
+	runPreflightPowerShell = fn
+	return func() { runPreflightPowerShell = prev }
+}
+
+func newPreflightTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := New(Config{Host: "win01", Username: "u", Password: "p", Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestIsAdministrator(t *testing.T) {
+	cases := []struct {
+		stdout string
+		want   bool
+	}{
+		{`{"user":"WIN01\\admin","is_admin":true}` + "\n", true},
+		{"noise\n" + `{"user":"WIN01\\op","is_admin":false}` + "\n", false},
+	}
+	for _, c := range cases {
+		var script string
+		restore := stubPreflightRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+			script = s
+			return c.stdout, "", nil
+		})
+		res, err := newPreflightTestClient(t).IsAdministrator(context.Background())
+		restore()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.IsAdmin != c.want || !strings.HasPrefix(res.User, `WIN01\`) {
+			t.Errorf("got %+v, want IsAdmin=%v", res, c.want)
+		}
+		if !strings.Contains(script, "WindowsBuiltInRole]::Administrator") {
+			t.Error("script should test the Administrators role")
+		}
+	}
+}
+
+func TestIsAdministrator_Errors(t *testing.T) {
+	defer stubPreflightRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "boom", errors.New("winrm: tcp reset")
+	})()
+	if _, err := newPreflightTestClient(t).IsAdministrator(context.Background()); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("transport error should surface with stderr, got %v", err)
+	}
+
+	defer stubPreflightRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "not json\n", "", nil
+	})()
+	if _, err := newPreflightTestClient(t).IsAdministrator(context.Background()); err == nil {
+		t.Error("missing JSON should be an error")
+	}
+}
+
+func TestPowerShellVersion(t *testing.T) {
+	defer stubPreflightRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if !strings.Contains(s, "$PSVersionTable") {
+			t.Errorf("unexpected script: %s", s)
+		}
+		return `{"edition":"Core","version":"7.4.6"}` + "\n", "", nil
+	})()
+	res, err := newPreflightTestClient(t).PowerShellVersion(context.Background())
+	if err != nil || res.Edition != "Core" || res.Version != "7.4.6" {
+		t.Fatalf("PowerShellVersion = %+v, %v", res, err)
+	}
+
+	defer stubPreflightRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "'pwsh.exe' is not recognized as an internal or external command",
+			&TransportError{Kind: FailureCommand, Err: errors.New("winclient: powershell exited with code 1")}
+	})()
+	_, err = newPreflightTestClient(t).PowerShellVersion(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "is not recognized") || TransportFailureKind(err) != FailureCommand {
+		t.Errorf("a missing executable should surface stderr as a command failure, got %v", err)
+	}
+}
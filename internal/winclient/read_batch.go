@@ -0,0 +1,245 @@
+// Package winclient: batched reads (Config.ReadBatching).
+//
+// A refresh of a configuration with 50 windows_local_user resources used to
+// cost 50 Get-LocalUser invocations. With ReadBatching, the first Read of a
+// local user loads every local user in one Get-LocalUser call, and the first
+// Read of a feature loads every feature in one Get-WindowsFeature call. Later
+// Reads are served from that snapshot for readBatchTTL, and concurrent Reads
+// share the one in-flight load. Any mutation through the same Client drops the
+// snapshot, so a Read after a Create or an Install sees the new state.
+//
+// The trade-off is error granularity: when the load fails, every Read waiting
+// on it fails with the same error, where unbatched Reads would fail or succeed
+// one by one. That is why the mode is opt-in. Failed loads are never reused;
+// a waiter whose load was cancelled by another caller's context loads again.
+//
+// Reads that must see the state of the moment, such as the progress polls of
+// a running install, opt out with WithoutReadBatching: a snapshot taken when
+// the install started would otherwise be served until the install returns.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readBatchTTL bounds how long a snapshot is reused. Tests may shorten it.
+var readBatchTTL = 30 * time.Second
+
+// skipReadBatchKey is the context key for WithoutReadBatching.
+type skipReadBatchKey struct{}
+
+// WithoutReadBatching returns a copy of ctx under which Reads go to the host
+// even with Config.ReadBatching, without using or refreshing the snapshot.
+func WithoutReadBatching(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipReadBatchKey{}, true)
+}
+
+// readBatching reports whether a Read made with ctx is served from the
+// snapshot.
+func (c *Client) readBatching(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipReadBatchKey{}).(bool)
+	return c.cfg.ReadBatching && !skip
+}
+
+// readBatch holds the current snapshot of one kind of object, keyed by a
+// normalised identifier.
+type readBatch[T any] struct {
+	mu  sync.Mutex
+	cur *readBatchLoad[T]
+	gen uint64
+}
+
+// readBatchLoad is one snapshot load. done is closed once items, err,
+// cancelled and reuse are set; reuse is false for failed loads and for loads
+// that raced an invalidation.
+type readBatchLoad[T any] struct {
+	done      chan struct{}
+	items     map[string]T
+	err       error
+	cancelled bool
+	reuse     bool
+	at        time.Time
+}
+
+// get returns the current snapshot, running load when there is none. A
+// caller waiting on another caller's load gets that load's result, unless it
+// was cancelled, in which case it loads again with its own ctx.
+func (b *readBatch[T]) get(ctx context.Context, load func(context.Context) (map[string]T, error)) (map[string]T, error) {
+	for {
+		b.mu.Lock()
+		l := b.cur
+		if l == nil {
+			break
+		}
+		b.mu.Unlock()
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if l.reuse && time.Since(l.at) < readBatchTTL {
+			return l.items, nil
+		}
+		if l.err != nil && !l.cancelled {
+			return nil, l.err
+		}
+		// Expired, cancelled or stale: drop it (unless already replaced) and
+		// look again.
+		b.mu.Lock()
+		if b.cur == l {
+			b.cur = nil
+		}
+		b.mu.Unlock()
+	}
+
+	l := &readBatchLoad[T]{done: make(chan struct{})}
+	b.cur = l
+	gen := b.gen
+	b.mu.Unlock()
+
+	items, err := load(ctx)
+
+	b.mu.Lock()
+	l.items, l.err, l.at = items, err, time.Now()
+	l.cancelled = err != nil && ctx.Err() != nil
+	l.reuse = err == nil && gen == b.gen
+	if !l.reuse && b.cur == l {
+		b.cur = nil
+	}
+	close(l.done)
+	b.mu.Unlock()
+	return items, err
+}
+
+// invalidate drops the snapshot; a load in flight is not reused.
+func (b *readBatch[T]) invalidate() {
+	b.mu.Lock()
+	b.gen++
+	b.cur = nil
+	b.mu.Unlock()
+}
+
+// ---------------------------------------------------------------------------
+// Features
+// ---------------------------------------------------------------------------
+
+// psFeatureReadAllBody emits every feature with the single-feature read
+// keys. The array is wrapped in an object so ConvertTo-Json keeps it an
+// array for a single feature.
+const psFeatureReadAllBody = `
+Ensure-FeatureCmdlets
+try {
+  $pending = [bool](Test-PendingReboot)
+  $all = @(Get-WindowsFeature -ErrorAction Stop)
+  $byName = @{}
+  foreach ($x in $all) { $byName[[string]$x.Name] = $x }
+  $lookup = { param($n) foreach ($k in $n) { $byName[[string]$k] } }
+  $rows = @($all | ForEach-Object {
+      $comp = [ordered]@{ all_sub = $true; mgmt = $true }
+      if ($_.InstallState -eq 'Installed') { $comp = Get-FeatureComponents $_ $lookup }
+      [ordered]@{
+        name            = [string]$_.Name
+        display_name    = [string]$_.DisplayName
+        description     = [string]$_.Description
+        installed       = ($_.InstallState -eq 'Installed')
+        install_state   = [string]$_.InstallState
+        restart_pending = $pending
+        all_sub_features_installed = [bool]$comp.all_sub
+        management_tools_installed = [bool]$comp.mgmt
+      }
+    })
+  Emit-OK ([ordered]@{ features = $rows })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Feature $msg) $msg @{}
+}
+`
+
+// readBatched serves Read from the feature snapshot. A name missing from
+// the snapshot reads as (nil, nil), like a feature Get-WindowsFeature does
+// not know.
+func (f *FeatureClient) readBatched(ctx context.Context, name string) (*FeatureInfo, error) {
+	items, err := f.c.featureBatch.get(ctx, f.loadFeatures)
+	if err != nil {
+		if ctx.Err() != nil && !IsFeatureError(err, FeatureErrorTimeout) {
+			return nil, NewFeatureError(FeatureErrorTimeout,
+				fmt.Sprintf("read of feature %q was cancelled while waiting for the batched read", name),
+				err, map[string]string{"operation": "read", "name": name, "host": f.c.cfg.Host})
+		}
+		return nil, err
+	}
+	return items[strings.ToLower(strings.TrimSpace(name))], nil
+}
+
+// loadFeatures reads every feature in one call, keyed by lower-case name.
+func (f *FeatureClient) loadFeatures(ctx context.Context) (map[string]*FeatureInfo, error) {
+	resp, err := retryTransientRead(ctx, func() (*featurePSResponse, error) {
+		return f.runFeatureEnvelope(ctx, "read_batch", "*", psFeatureReadAllBody)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Features jsonList[featureDataPayload] `json:"features"`
+	}
+	if len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if jerr := json.Unmarshal(resp.Data, &wrapper); jerr != nil {
+			return nil, NewFeatureError(FeatureErrorUnknown, "failed to parse feature list", jerr,
+				map[string]string{"operation": "read_batch", "host": f.c.cfg.Host})
+		}
+	}
+	items := make(map[string]*FeatureInfo, len(wrapper.Features))
+	for i := range wrapper.Features {
+		items[strings.ToLower(wrapper.Features[i].Name)] = toFeatureInfo(&wrapper.Features[i])
+	}
+	return items, nil
+}
+
+// ---------------------------------------------------------------------------
+// Local users
+// ---------------------------------------------------------------------------
+
+// readBatched serves Read from the local user snapshot. A SID missing from
+// the snapshot reads as (nil, nil), like a deleted user.
+func (lc *LocalUserClientImpl) readBatched(ctx context.Context, sid string) (*UserState, error) {
+	items, err := lc.c.userBatch.get(ctx, lc.loadUsers)
+	if err != nil {
+		var le *LocalUserError
+		if !errors.As(err, &le) {
+			return nil, NewLocalUserError(LocalUserErrorUnknown,
+				"read was cancelled while waiting for the batched read", err,
+				map[string]string{"operation": "read", "key": sid, "host": lc.c.cfg.Host})
+		}
+		return nil, err
+	}
+	st := items[strings.ToUpper(sid)]
+	if st == nil {
+		return nil, nil
+	}
+	// Callers own the returned state; the snapshot is shared.
+	cp := *st
+	return &cp, nil
+}
+
+// loadUsers reads every local user in one call, keyed by upper-case SID.
+func (lc *LocalUserClientImpl) loadUsers(ctx context.Context) (map[string]*UserState, error) {
+	users, err := lc.listUsers(ctx, "read_batch")
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]*UserState, len(users))
+	for _, st := range users {
+		items[strings.ToUpper(st.SID)] = st
+	}
+	return items, nil
+}
+
+// invalidateUserBatch drops the local user snapshot. Every LocalUserClientImpl
+// method that changes a user calls it.
+func (c *Client) invalidateUserBatch() { c.userBatch.invalidate() }
@@ -0,0 +1,272 @@
+// Package winclient — unit tests for batched reads (Config.ReadBatching).
+package winclient
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newBatchingTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := New(Config{
+		Host:         "win01",
+		Username:     "u",
+		Password:     "p",
+		Timeout:      30 * time.Second,
+		ReadBatching: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestReadBatching_FeaturesShareOneLoad(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer stubFeatRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		calls.Add(1)
+		if !strings.Contains(script, "@(Get-WindowsFeature -ErrorAction Stop)") ||
+			!strings.Contains(script, "Get-FeatureComponents $_ $lookup") {
+			t.Errorf("expected the batched script, got:\n%s", script)
+		}
+		<-release
+		return featOK(t, map[string]any{"features": []any{
+			fakeFeatureData("DNS", "Installed"),
+			fakeFeatureData("Web-Server", "Available"),
+		}}), "", nil
+	})()
+	f := NewFeatureClient(newBatchingTestClient(t))
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"DNS", "web-server", "DNS", "Missing"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := f.Read(context.Background(), name)
+			if err != nil {
+				t.Errorf("Read(%q): %v", name, err)
+				return
+			}
+			switch name {
+			case "DNS":
+				if info == nil || !info.Installed {
+					t.Errorf("Read(DNS) = %+v", info)
+				}
+			case "web-server":
+				if info == nil || info.Name != "Web-Server" || info.Installed {
+					t.Errorf("Read(web-server) = %+v", info)
+				}
+			case "Missing":
+				if info != nil {
+					t.Errorf("Read(Missing) = %+v, want nil", info)
+				}
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("host calls = %d, want 1", n)
+	}
+}
+
+func TestReadBatching_FeatureInstallInvalidates(t *testing.T) {
+	state := "Available"
+	var loads int
+	defer stubFeatRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		if strings.Contains(script, "Run-Install") {
+			state = "Installed"
+			return featOK(t, fakeInstallData("DNS", state, false, "Success")), "", nil
+		}
+		loads++
+		return featOK(t, map[string]any{"features": fakeFeatureData("DNS", state)}), "", nil
+	})()
+	f := NewFeatureClient(newBatchingTestClient(t))
+
+	if info, _ := f.Read(context.Background(), "DNS"); info == nil || info.Installed {
+		t.Fatalf("before install: %+v", info)
+	}
+	if _, _, err := f.Install(context.Background(), FeatureInput{Name: "DNS"}); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if info, _ := f.Read(context.Background(), "DNS"); info == nil || !info.Installed {
+		t.Fatalf("after install: %+v", info)
+	}
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2", loads)
+	}
+}
+
+func TestReadBatching_WithoutReadBatchingReadsFresh(t *testing.T) {
+	state := "Available"
+	var loads, reads int
+	defer stubFeatRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		if strings.Contains(script, "Read-Feature -Name 'DNS'") {
+			reads++
+			return featOK(t, fakeFeatureData("DNS", state)), "", nil
+		}
+		loads++
+		return featOK(t, map[string]any{"features": fakeFeatureData("DNS", state)}), "", nil
+	})()
+	f := NewFeatureClient(newBatchingTestClient(t))
+
+	if info, _ := f.Read(context.Background(), "DNS"); info == nil || info.Installed {
+		t.Fatalf("snapshot read: %+v", info)
+	}
+	// The host changes behind the snapshot, as during an install.
+	state = "Installed"
+	if info, _ := f.Read(WithoutReadBatching(context.Background()), "DNS"); info == nil || !info.Installed {
+		t.Fatalf("unbatched read: %+v", info)
+	}
+	if info, _ := f.Read(context.Background(), "DNS"); info == nil || info.Installed {
+		t.Errorf("the unbatched read must leave the snapshot alone: %+v", info)
+	}
+	if loads != 1 || reads != 1 {
+		t.Errorf("loads = %d, reads = %d, want 1 and 1", loads, reads)
+	}
+}
+
+func TestReadBatching_FailedLoadIsSharedNotCached(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			return featErr(t, "permission_denied", "Access is denied"), "", nil
+		}
+		return featOK(t, map[string]any{"features": []any{fakeFeatureData("DNS", "Installed")}}), "", nil
+	})()
+	f := NewFeatureClient(newBatchingTestClient(t))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.Read(context.Background(), "DNS"); !IsFeatureError(err, FeatureErrorPermission) {
+				t.Errorf("err = %v, want permission_denied", err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("host calls = %d, want 1", n)
+	}
+
+	if info, err := f.Read(context.Background(), "DNS"); err != nil || info == nil {
+		t.Errorf("Read after failure = %+v, %v", info, err)
+	}
+}
+
+func TestReadBatching_CancelledLoadIsRetriedByWaiter(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	defer stubFeatRun(func(ctx context.Context, _ *Client, _ string) (string, string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return "", "", ctx.Err()
+		}
+		return featOK(t, map[string]any{"features": []any{fakeFeatureData("DNS", "Installed")}}), "", nil
+	})()
+	f := NewFeatureClient(newBatchingTestClient(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := f.Read(ctx, "DNS")
+		first <- err
+	}()
+	<-started
+	second := make(chan error, 1)
+	go func() {
+		info, err := f.Read(context.Background(), "DNS")
+		if err == nil && info == nil {
+			err = context.Canceled
+		}
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-first; !IsFeatureError(err, FeatureErrorTimeout) {
+		t.Errorf("cancelled reader: err = %v, want timeout", err)
+	}
+	if err := <-second; err != nil {
+		t.Errorf("waiting reader should load again, got %v", err)
+	}
+}
+
+func TestReadBatching_FeatureSnapshotExpires(t *testing.T) {
+	prev := readBatchTTL
+	readBatchTTL = 10 * time.Millisecond
+	defer func() { readBatchTTL = prev }()
+
+	var calls atomic.Int32
+	defer stubFeatRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls.Add(1)
+		return featOK(t, map[string]any{"features": []any{fakeFeatureData("DNS", "Installed")}}), "", nil
+	})()
+	f := NewFeatureClient(newBatchingTestClient(t))
+
+	f.Read(context.Background(), "DNS")
+	time.Sleep(20 * time.Millisecond)
+	f.Read(context.Background(), "DNS")
+	if n := calls.Load(); n != 2 {
+		t.Errorf("host calls = %d, want 2", n)
+	}
+}
+
+func TestReadBatching_LocalUsers(t *testing.T) {
+	var calls atomic.Int32
+	defer stubLURun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		if strings.Contains(script, "Set-LocalUser") {
+			return luOK(t, fakeUserData("alice", "S-1-5-21-1-1001")), "", nil
+		}
+		calls.Add(1)
+		if !strings.Contains(script, "Get-LocalUser -ErrorAction Stop |") {
+			t.Errorf("expected the batched script, got:\n%s", script)
+		}
+		return luOK(t, map[string]any{"users": []any{
+			fakeUserData("alice", "S-1-5-21-1-1001"),
+			fakeUserData("bob", "S-1-5-21-1-1002"),
+		}}), "", nil
+	})()
+	_, lc := newLUClient(t)
+	lc.c.cfg.ReadBatching = true
+
+	for _, sid := range []string{"S-1-5-21-1-1001", "s-1-5-21-1-1002"} {
+		st, err := lc.Read(context.Background(), sid)
+		if err != nil || st == nil {
+			t.Fatalf("Read(%s) = %+v, %v", sid, st, err)
+		}
+	}
+	if st, err := lc.Read(context.Background(), "S-1-5-21-1-1099"); err != nil || st != nil {
+		t.Errorf("Read(missing) = %+v, %v; want nil, nil", st, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loads = %d, want 1", n)
+	}
+
+	st, _ := lc.Read(context.Background(), "S-1-5-21-1-1001")
+	st.FullName = "changed"
+	if again, _ := lc.Read(context.Background(), "S-1-5-21-1-1001"); again.FullName == "changed" {
+		t.Error("Read must not hand out the shared snapshot")
+	}
+
+	if _, err := lc.Update(context.Background(), "S-1-5-21-1-1001", UserInput{Name: "alice"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	lc.Read(context.Background(), "S-1-5-21-1-1001")
+	if n := calls.Load(); n != 2 {
+		t.Errorf("loads after Update = %d, want 2", n)
+	}
+}
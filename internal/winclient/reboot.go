@@ -0,0 +1,268 @@
+// Package winclient: host reboot over WinRM.
+//
+// Reboot records the current boot time, issues Restart-Computer -Force and
+// then polls until the host answers with a strictly newer boot time. Every
+// WinRM run opens its own HTTP connection, so no session has to be rebuilt
+// after the restart: the next poll simply dials again. While the host is
+// down, polls fail with dial errors (throttled by the Client's dial back-off,
+// see health.go) or with EOF / connection reset while the listener is
+// shutting down; all of these are tolerated until the timeout elapses.
+//
+// Comparing boot times rather than "host answered" matters: the listener
+// usually keeps answering for a few seconds after Restart-Computer returns,
+// and a poll landing in that window must not be mistaken for the host
+// having come back.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Compile-time assertion: RebootClientImpl satisfies RebootClient.
+var _ RebootClient = (*RebootClientImpl)(nil)
+
+// RebootClientImpl is the PowerShell/WinRM-backed RebootClient.
+type RebootClientImpl struct {
+	c *Client
+}
+
+// NewRebootClient constructs a RebootClientImpl wrapping the given WinRM Client.
+func NewRebootClient(c *Client) *RebootClientImpl {
+	return &RebootClientImpl{c: c}
+}
+
+// rebootPollInterval is the delay between reachability polls. Tests may
+// shorten it.
+var rebootPollInterval = 10 * time.Second
+
+// psRebootHeader defines the helpers shared by the reboot scripts.
+const psRebootHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Classify([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied' -or $Msg -match 'privilege' -or $Msg -match 'not authorized') { return 'permission_denied' }
+  return 'unknown'
+}
+`
+
+// psRebootBootTime reports the hostname and last boot time in UTC (ISO 8601).
+const psRebootBootTime = `
+try {
+  $os = Get-CimInstance -ClassName Win32_OperatingSystem
+  Emit-OK ([ordered]@{
+    hostname  = [string]$env:COMPUTERNAME
+    boot_time = $os.LastBootUpTime.ToUniversalTime().ToString('o')
+  })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify $msg) $msg @{}
+}
+`
+
+// psRebootRestart initiates the restart. Restart-Computer returns as soon as
+// the shutdown is scheduled, so the envelope normally arrives before the
+// connection drops; Reboot does not rely on it.
+const psRebootRestart = `
+try {
+  Restart-Computer -Force
+  Emit-OK ([ordered]@{ initiated = $true })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify $msg) $msg @{}
+}
+`
+
+// runRebootPowerShell is the package-level hook for test substitution.
+// Production code must not reassign this outside tests.
+var runRebootPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// rbPSResponse is the parsed JSON envelope for reboot operations.
+type rbPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// rbBootPayload mirrors the JSON object returned by psRebootBootTime.
+type rbBootPayload struct {
+	Hostname string `json:"hostname"`
+	BootTime string `json:"boot_time"`
+}
+
+// runScript executes a PS script and parses the JSON envelope.
+func (r *RebootClientImpl) runScript(ctx context.Context, op, script string) (*rbPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	stdout, stderr, err := runRebootPowerShell(ctx, r.c, script)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &RebootError{
+				Kind:    RebootErrorUnknown,
+				Message: fmt.Sprintf("operation %q timed out or was cancelled", op),
+				Cause:   ctxErr,
+				Context: map[string]string{"operation": op, "host": r.c.cfg.Host},
+			}
+		}
+		return nil, &RebootError{
+			Kind:    RebootErrorUnknown,
+			Message: fmt.Sprintf("powershell transport error during %q", op),
+			Cause:   err,
+			Context: map[string]string{
+				"operation": op, "host": r.c.cfg.Host,
+				"stderr": truncate(stderr, 2048),
+				"stdout": truncate(stdout, 2048),
+			},
+		}
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		return nil, &RebootError{
+			Kind:    RebootErrorUnknown,
+			Message: fmt.Sprintf("no JSON envelope returned from %q", op),
+			Context: map[string]string{
+				"operation": op, "host": r.c.cfg.Host,
+				"stderr": truncate(stderr, 2048),
+				"stdout": truncate(stdout, 2048),
+			},
+		}
+	}
+
+	var resp rbPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		return nil, &RebootError{
+			Kind:    RebootErrorUnknown,
+			Message: fmt.Sprintf("invalid JSON envelope from %q", op),
+			Cause:   jerr,
+			Context: map[string]string{"operation": op, "stdout": truncate(stdout, 2048)},
+		}
+	}
+
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		ctxMap["operation"] = op
+		ctxMap["host"] = r.c.cfg.Host
+		return &resp, &RebootError{Kind: mapRebootErrorKind(resp.Kind), Message: resp.Message, Context: ctxMap}
+	}
+	return &resp, nil
+}
+
+// mapRebootErrorKind translates the PS-side "kind" string to a typed RebootErrorKind.
+func mapRebootErrorKind(k string) RebootErrorKind {
+	switch k {
+	case string(RebootErrorPermission):
+		return RebootErrorKind(k)
+	default:
+		return RebootErrorUnknown
+	}
+}
+
+// BootTime implements RebootClient.BootTime.
+func (r *RebootClientImpl) BootTime(ctx context.Context) (*BootInfo, error) {
+	resp, err := r.runScript(ctx, "boot_time", psRebootHeader+psRebootBootTime)
+	if err != nil {
+		return nil, err
+	}
+	var p rbBootPayload
+	if err := json.Unmarshal(resp.Data, &p); err != nil {
+		return nil, NewRebootError(RebootErrorUnknown, "failed to parse boot time payload", err,
+			map[string]string{"host": r.c.cfg.Host})
+	}
+	bt, err := time.Parse(time.RFC3339Nano, p.BootTime)
+	if err != nil {
+		return nil, NewRebootError(RebootErrorUnknown, fmt.Sprintf("invalid boot time %q", p.BootTime), err,
+			map[string]string{"host": r.c.cfg.Host})
+	}
+	return &BootInfo{Hostname: p.Hostname, BootTime: bt.UTC()}, nil
+}
+
+// Reboot implements RebootClient.Reboot.
+//
+// timeout bounds the whole operation, from the restart request until the
+// host answers with a newer boot time.
+func (r *RebootClientImpl) Reboot(ctx context.Context, timeout time.Duration) (*BootInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	before, err := r.BootTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.runScript(ctx, "restart", psRebootHeader+psRebootRestart); err != nil && !restartDropTolerated(ctx, err) {
+		return nil, err
+	}
+
+	var lastErr error
+	for {
+		t := time.NewTimer(rebootPollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, r.waitError(ctx, timeout, before, lastErr)
+		case <-t.C:
+		}
+
+		info, err := r.BootTime(ctx)
+		switch {
+		case err == nil && info.BootTime.After(before.BootTime):
+			return info, nil
+		case err == nil:
+			// Still the old boot: the shutdown has not started yet.
+			lastErr = nil
+		case AuthFailureLatched(err):
+			return nil, err
+		case ctx.Err() != nil:
+			return nil, r.waitError(ctx, timeout, before, lastErr)
+		default:
+			lastErr = err
+		}
+	}
+}
+
+// restartDropTolerated reports whether err, returned by the restart script,
+// is the connection dropping as the host goes down rather than a failure to
+// restart. Error envelopes, cancellation and authentication failures are not
+// tolerated.
+func restartDropTolerated(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	kind := TransportFailureKind(err)
+	return kind != "" && kind != FailureAuth
+}
+
+// waitError builds the error returned once ctx has ended while waiting for
+// the host: a timeout unless the caller cancelled.
+func (r *RebootClientImpl) waitError(ctx context.Context, timeout time.Duration, before *BootInfo, lastErr error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return NewRebootError(RebootErrorUnknown, "waiting for the host to come back was cancelled", ctx.Err(),
+			map[string]string{"host": r.c.cfg.Host})
+	}
+	return NewRebootError(RebootErrorTimeout,
+		fmt.Sprintf("host did not come back within %s", timeout), lastErr,
+		map[string]string{
+			"host":               r.c.cfg.Host,
+			"previous_boot_time": before.BootTime.Format(time.RFC3339),
+		})
+}
@@ -0,0 +1,172 @@
+// Package winclient — unit tests for RebootClientImpl.
+//
+// Tests stub the package-level runRebootPowerShell hook and shorten
+// rebootPollInterval so no real WinRM connection or reboot is required.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubRebootRun replaces runRebootPowerShell and rebootPollInterval for the
+// duration of a test.
+func stubRebootRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prevRun, prevInterval := runRebootPowerShell, rebootPollInterval
+	runRebootPowerShell, rebootPollInterval = fn, time.Millisecond
+	return func() { runRebootPowerShell, rebootPollInterval = prevRun, prevInterval }
+}
+
+func bootEnvelope(t *testing.T, bootTime string) string {
+	return okEnvelope(t, map[string]any{"hostname": "WIN01", "boot_time": bootTime})
+}
+
+const (
+	oldBoot = "2026-10-17T06:00:00.0000000Z"
+	newBoot = "2026-10-17T09:30:12.5000000Z"
+)
+
+func TestRebootBootTime(t *testing.T) {
+	defer stubRebootRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if !strings.Contains(s, "LastBootUpTime") {
+			t.Errorf("unexpected script: %s", s)
+		}
+		return bootEnvelope(t, "2026-10-17T08:00:00.1234567+02:00"), "", nil
+	})()
+
+	info, err := NewRebootClient(newTestClient(t)).BootTime(context.Background())
+	if err != nil {
+		t.Fatalf("BootTime: %v", err)
+	}
+	want := time.Date(2026, 10, 17, 6, 0, 0, 123456700, time.UTC)
+	if info.Hostname != "WIN01" || !info.BootTime.Equal(want) || info.BootTime.Location() != time.UTC {
+		t.Errorf("BootTime = %+v", info)
+	}
+}
+
+func TestReboot_ToleratesDropAndWaitsForNewBoot(t *testing.T) {
+	var restarts int
+	polls := []func() (string, string, error){
+		// Listener still up right after Restart-Computer: same boot.
+		func() (string, string, error) { return bootEnvelope(t, oldBoot), "", nil },
+		// Going down mid-command, then unreachable.
+		func() (string, string, error) {
+			return "", "", &TransportError{Kind: FailureCommand, Err: io.EOF}
+		},
+		func() (string, string, error) {
+			return "", "", &TransportError{Kind: FailureDial, Err: errors.New("connection refused")}
+		},
+		// Booted, but the domain login is rejected until the host rejoins.
+		func() (string, string, error) {
+			return "", "", &TransportError{Kind: FailureAuth, Err: errors.New("http error 401: ")}
+		},
+		// Booted, but WMI not ready yet.
+		func() (string, string, error) { return errEnvelope(t, "unknown", "Invalid namespace"), "", nil },
+		func() (string, string, error) { return bootEnvelope(t, newBoot), "", nil },
+	}
+	booted := false
+	defer stubRebootRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if strings.Contains(s, "Restart-Computer -Force") {
+			restarts++
+			return "", "", &TransportError{Kind: FailureCommand, Err: io.ErrUnexpectedEOF}
+		}
+		if !booted {
+			booted = true
+			return bootEnvelope(t, oldBoot), "", nil
+		}
+		next := polls[0]
+		if len(polls) > 1 {
+			polls = polls[1:]
+		}
+		return next()
+	})()
+
+	info, err := NewRebootClient(newTestClient(t)).Reboot(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatalf("Reboot: %v", err)
+	}
+	if restarts != 1 {
+		t.Errorf("restart issued %d times, want 1", restarts)
+	}
+	if info.BootTime.Format(time.RFC3339) != "2026-10-17T09:30:12Z" {
+		t.Errorf("BootTime = %s", info.BootTime)
+	}
+}
+
+func TestReboot_Timeout(t *testing.T) {
+	defer stubRebootRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if strings.Contains(s, "Restart-Computer") {
+			return okEnvelope(t, map[string]any{"initiated": true}), "", nil
+		}
+		return bootEnvelope(t, oldBoot), "", nil
+	})()
+
+	_, err := NewRebootClient(newTestClient(t)).Reboot(context.Background(), 20*time.Millisecond)
+	if !errors.Is(err, ErrRebootTimeout) {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+	var re *RebootError
+	if !errors.As(err, &re) || re.Context["previous_boot_time"] != "2026-10-17T06:00:00Z" {
+		t.Errorf("context = %+v", re)
+	}
+}
+
+func TestReboot_RestartRefused(t *testing.T) {
+	var polled bool
+	defer stubRebootRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if strings.Contains(s, "Restart-Computer") {
+			return errEnvelope(t, "permission_denied", "Privilege not held."), "", nil
+		}
+		if polled {
+			t.Error("no poll may run after a refused restart")
+		}
+		polled = true
+		return bootEnvelope(t, oldBoot), "", nil
+	})()
+
+	_, err := NewRebootClient(newTestClient(t)).Reboot(context.Background(), time.Minute)
+	if !IsRebootError(err, RebootErrorPermission) {
+		t.Errorf("err = %v, want permission_denied", err)
+	}
+}
+
+func TestReboot_AuthFailureEndsWait(t *testing.T) {
+	first := true
+	defer stubRebootRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		switch {
+		case strings.Contains(s, "Restart-Computer"):
+			return okEnvelope(t, map[string]any{"initiated": true}), "", nil
+		case first:
+			first = false
+			return bootEnvelope(t, oldBoot), "", nil
+		}
+		return "", "", &TransportError{Kind: FailureAuth, Err: errors.New("http error 401: "), Latched: true}
+	})()
+
+	_, err := NewRebootClient(newTestClient(t)).Reboot(context.Background(), time.Minute)
+	if TransportFailureKind(err) != FailureAuth || IsRebootError(err, RebootErrorTimeout) {
+		t.Errorf("err = %v, want the latched auth failure", err)
+	}
+}
+
+func TestReboot_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	first := true
+	defer stubRebootRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if first {
+			first = false
+			return bootEnvelope(t, oldBoot), "", nil
+		}
+		cancel()
+		return "", "", context.Canceled
+	})()
+
+	_, err := NewRebootClient(newTestClient(t)).Reboot(ctx, time.Minute)
+	if err == nil || IsRebootError(err, RebootErrorTimeout) || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want a cancellation error", err)
+	}
+}
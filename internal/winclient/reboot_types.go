@@ -0,0 +1,96 @@
+// Package winclient: reboot types, interface, and error definitions.
+//
+// windows_reboot restarts the host and blocks until it is reachable again,
+// so that resources depending on it (e.g. a feature that needs a restart to
+// finish installing) only run once the machine is back.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RebootErrorKind categorises errors returned by RebootClient operations.
+type RebootErrorKind string
+
+const (
+	RebootErrorTimeout    RebootErrorKind = "timeout"
+	RebootErrorPermission RebootErrorKind = "permission_denied"
+	RebootErrorUnknown    RebootErrorKind = "unknown"
+)
+
+// RebootError is the structured error type returned by all RebootClient methods.
+type RebootError struct {
+	Kind    RebootErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements the error interface.
+func (e *RebootError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_reboot [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_reboot [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *RebootError) Unwrap() error { return e.Cause }
+
+// Is implements errors.Is comparison by Kind or ErrorClass.
+func (e *RebootError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*RebootError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewRebootError constructs a *RebootError.
+func NewRebootError(kind RebootErrorKind, message string, cause error, ctx map[string]string) *RebootError {
+	return &RebootError{Kind: kind, Message: message, Cause: cause, Context: ctx}
+}
+
+// IsRebootError reports whether err is a *RebootError with the given kind.
+func IsRebootError(err error, kind RebootErrorKind) bool {
+	var re *RebootError
+	if errors.As(err, &re) {
+		return re.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrRebootTimeout    = &RebootError{Kind: RebootErrorTimeout}
+	ErrRebootPermission = &RebootError{Kind: RebootErrorPermission}
+	ErrRebootUnknown    = &RebootError{Kind: RebootErrorUnknown}
+)
+
+// BootInfo is the observed boot state of the remote host.
+type BootInfo struct {
+	// Hostname is $env:COMPUTERNAME as seen after the boot.
+	Hostname string
+	// BootTime is Win32_OperatingSystem.LastBootUpTime in UTC.
+	BootTime time.Time
+}
+
+// RebootClient restarts Windows hosts over WinRM.
+//
+// Error conventions:
+//   - Reboot returns RebootErrorTimeout when the host has not come back with
+//     a newer boot time before timeout elapses.
+//   - Transport failures after the restart was initiated (EOF, connection
+//     reset, refused dials, a rejected login while the host rejoins its
+//     domain) are expected and are not errors; an authentication failure
+//     latched by the Client ends the wait early.
+type RebootClient interface {
+	BootTime(ctx context.Context) (*BootInfo, error)
+	Reboot(ctx context.Context, timeout time.Duration) (*BootInfo, error)
+}
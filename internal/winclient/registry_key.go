@@ -0,0 +1,349 @@
+// Package winclient: Windows registry key CRUD implementation over WinRM.
+//
+// Like the registry value client, all operations use the .NET
+// Microsoft.Win32.Registry API via PowerShell rather than the registry
+// provider cmdlets (New-Item / Remove-Item), which behave differently on
+// keys containing '/' and on HKCR. Scripts emit a JSON envelope
+// (Emit-OK/Emit-Err) for locale-independent, machine-parseable output.
+//
+// Security invariants:
+//   - hive and path are interpolated only through psQuote.
+//   - The hive root itself can never be created or deleted: an empty path is
+//     rejected in Go before any script runs.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Compile-time assertion: RegistryKeyClientImpl satisfies RegistryKeyClient.
+var _ RegistryKeyClient = (*RegistryKeyClientImpl)(nil)
+
+// RegistryKeyClientImpl is the PowerShell/WinRM-backed RegistryKeyClient.
+type RegistryKeyClientImpl struct {
+	c *Client
+}
+
+// NewRegistryKeyClient constructs a RegistryKeyClientImpl wrapping the given WinRM Client.
+func NewRegistryKeyClient(c *Client) *RegistryKeyClientImpl {
+	return &RegistryKeyClientImpl{c: c}
+}
+
+// psRegistryKeyHeader defines the helpers shared by the registry key scripts.
+const psRegistryKeyHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Get-RegHive([string]$Hive) {
+  switch ($Hive) {
+    'HKLM' { return [Microsoft.Win32.Registry]::LocalMachine }
+    'HKCU' { return [Microsoft.Win32.Registry]::CurrentUser }
+    'HKCR' { return [Microsoft.Win32.Registry]::ClassesRoot }
+    'HKU'  { return [Microsoft.Win32.Registry]::Users }
+    'HKCC' { return [Microsoft.Win32.Registry]::CurrentConfig }
+    default { throw ("Unknown hive: " + $Hive) }
+  }
+}
+function Key-Data($Key, [bool]$Existed) {
+  return [ordered]@{
+    found         = $true
+    subkey_count  = [int]$Key.SubKeyCount
+    value_count   = [int]$Key.ValueCount
+    existed       = $Existed
+  }
+}
+`
+
+// psRegistryKeyCreateBody creates the key (and any missing parents).
+// Placeholders @@HIVE@@, @@PATH@@, @@ALLOW@@ are replaced by strings.NewReplacer.
+const psRegistryKeyCreateBody = `
+& {
+  $_rkKey = $null
+  try {
+    $root = Get-RegHive @@HIVE@@
+    $_rkKey = $root.OpenSubKey(@@PATH@@, $false)
+    $existed = ($null -ne $_rkKey)
+    if ($existed -and -not @@ALLOW@@) {
+      Emit-Err 'already_exists' ('registry key already exists: ' + @@HIVE@@ + '\' + @@PATH@@) @{ hive = @@HIVE@@; path = @@PATH@@ }
+      return
+    }
+    if (-not $existed) {
+      $_rkKey = $root.CreateSubKey(@@PATH@@)
+      if ($null -eq $_rkKey) {
+        Emit-Err 'permission_denied' 'CreateSubKey returned null (insufficient privileges)' @{}
+        return
+      }
+    }
+    Emit-OK (Key-Data $_rkKey $existed)
+  } catch [System.UnauthorizedAccessException] {
+    Emit-Err 'permission_denied' $_.Exception.Message @{}
+  } catch [System.Security.SecurityException] {
+    Emit-Err 'permission_denied' $_.Exception.Message @{}
+  } catch {
+    Emit-Err 'unknown' $_.Exception.Message @{}
+  } finally {
+    if ($null -ne $_rkKey) { $_rkKey.Close() }
+  }
+}
+`
+
+// psRegistryKeyReadBody reports whether the key exists and its child counts.
+// Placeholders @@HIVE@@, @@PATH@@ are replaced by strings.NewReplacer.
+const psRegistryKeyReadBody = `
+& {
+  $_rkKey = $null
+  try {
+    $root = Get-RegHive @@HIVE@@
+    $_rkKey = $root.OpenSubKey(@@PATH@@, $false)
+    if ($null -eq $_rkKey) {
+      Emit-OK @{ found = $false }
+      return
+    }
+    Emit-OK (Key-Data $_rkKey $true)
+  } catch [System.UnauthorizedAccessException] {
+    Emit-Err 'permission_denied' $_.Exception.Message @{}
+  } catch [System.Security.SecurityException] {
+    Emit-Err 'permission_denied' $_.Exception.Message @{}
+  } catch {
+    Emit-Err 'unknown' $_.Exception.Message @{}
+  } finally {
+    if ($null -ne $_rkKey) { $_rkKey.Close() }
+  }
+}
+`
+
+// psRegistryKeyDeleteBody deletes the key, its values and, with @@RECURSE@@,
+// its child keys.
+// Placeholders @@HIVE@@, @@PATH@@, @@RECURSE@@ are replaced by strings.NewReplacer.
+const psRegistryKeyDeleteBody = `
+& {
+  try {
+    $root = Get-RegHive @@HIVE@@
+    $probe = $root.OpenSubKey(@@PATH@@, $false)
+    if ($null -eq $probe) {
+      Emit-OK @{ deleted = $false; reason = 'key_not_found' }
+      return
+    }
+    $children = [int]$probe.SubKeyCount
+    $probe.Close()
+    if (@@RECURSE@@) {
+      $root.DeleteSubKeyTree(@@PATH@@, $false)
+    } elseif ($children -gt 0) {
+      Emit-Err 'has_subkeys' ('registry key has ' + $children + ' child key(s); set delete_recursive to remove them') @{ subkey_count = [string]$children }
+      return
+    } else {
+      $root.DeleteSubKey(@@PATH@@, $false)
+    }
+    Emit-OK @{ deleted = $true }
+  } catch [System.UnauthorizedAccessException] {
+    Emit-Err 'permission_denied' $_.Exception.Message @{}
+  } catch [System.Security.SecurityException] {
+    Emit-Err 'permission_denied' $_.Exception.Message @{}
+  } catch {
+    Emit-Err 'unknown' $_.Exception.Message @{}
+  }
+}
+`
+
+// runRegistryKeyPowerShell is the package-level hook for test substitution.
+// Production code must not reassign this outside tests.
+var runRegistryKeyPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// rkPSResponse is the parsed JSON envelope for registry key operations.
+type rkPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// rkDataPayload mirrors the JSON object returned by Create and Read.
+type rkDataPayload struct {
+	Found       bool `json:"found"`
+	SubKeyCount int  `json:"subkey_count"`
+	ValueCount  int  `json:"value_count"`
+	Existed     bool `json:"existed"`
+}
+
+// validRegistryKeyHives are the hive abbreviations Get-RegHive accepts.
+var validRegistryKeyHives = map[string]bool{"HKLM": true, "HKCU": true, "HKCR": true, "HKU": true, "HKCC": true}
+
+// checkRegistryKeyTarget rejects unknown hives and the hive root.
+func checkRegistryKeyTarget(hive, regPath string) error {
+	if !validRegistryKeyHives[hive] {
+		return NewRegistryKeyError(RegistryKeyErrorInvalidInput,
+			fmt.Sprintf("unknown hive %q; must be one of HKLM, HKCU, HKCR, HKU, HKCC", hive), nil, nil)
+	}
+	if strings.Trim(regPath, `\`) == "" {
+		return NewRegistryKeyError(RegistryKeyErrorInvalidInput,
+			"registry key path is empty; a hive root cannot be managed", nil, map[string]string{"hive": hive})
+	}
+	return nil
+}
+
+// runScript executes a PS script and parses the JSON envelope.
+func (r *RegistryKeyClientImpl) runScript(ctx context.Context, op, script string) (*rkPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	stdout, stderr, err := runRegistryKeyPowerShell(ctx, r.c, script)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &RegistryKeyError{
+				Kind:    RegistryKeyErrorUnknown,
+				Message: fmt.Sprintf("operation %q timed out or was cancelled", op),
+				Cause:   ctxErr,
+				Context: map[string]string{"operation": op, "host": r.c.cfg.Host},
+			}
+		}
+		return nil, &RegistryKeyError{
+			Kind:    RegistryKeyErrorUnknown,
+			Message: fmt.Sprintf("powershell transport error during %q", op),
+			Cause:   err,
+			Context: map[string]string{
+				"operation": op, "host": r.c.cfg.Host,
+				"stderr": truncate(stderr, 2048),
+				"stdout": truncate(stdout, 2048),
+			},
+		}
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		return nil, &RegistryKeyError{
+			Kind:    RegistryKeyErrorUnknown,
+			Message: fmt.Sprintf("no JSON envelope returned from %q", op),
+			Context: map[string]string{
+				"operation": op, "host": r.c.cfg.Host,
+				"stderr": truncate(stderr, 2048),
+				"stdout": truncate(stdout, 2048),
+			},
+		}
+	}
+
+	var resp rkPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		return nil, &RegistryKeyError{
+			Kind:    RegistryKeyErrorUnknown,
+			Message: fmt.Sprintf("invalid JSON envelope from %q", op),
+			Cause:   jerr,
+			Context: map[string]string{"operation": op, "stdout": truncate(stdout, 2048)},
+		}
+	}
+
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		ctxMap["operation"] = op
+		ctxMap["host"] = r.c.cfg.Host
+		return &resp, &RegistryKeyError{Kind: mapRegistryKeyErrorKind(resp.Kind), Message: resp.Message, Context: ctxMap}
+	}
+	return &resp, nil
+}
+
+// mapRegistryKeyErrorKind translates the PS-side "kind" string to a typed RegistryKeyErrorKind.
+func mapRegistryKeyErrorKind(k string) RegistryKeyErrorKind {
+	switch k {
+	case string(RegistryKeyErrorAlreadyExists),
+		string(RegistryKeyErrorHasSubKeys),
+		string(RegistryKeyErrorPermission),
+		string(RegistryKeyErrorInvalidInput):
+		return RegistryKeyErrorKind(k)
+	default:
+		return RegistryKeyErrorUnknown
+	}
+}
+
+// parseKeyPayload decodes a Create/Read payload; found=false yields nil.
+func parseKeyPayload(raw json.RawMessage, hive, regPath string) (*RegistryKeyState, error) {
+	var p rkDataPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, NewRegistryKeyError(RegistryKeyErrorUnknown, "failed to parse registry key payload", err,
+			map[string]string{"hive": hive, "path": regPath})
+	}
+	if !p.Found {
+		return nil, nil
+	}
+	return &RegistryKeyState{
+		Hive:        hive,
+		Path:        regPath,
+		SubKeyCount: p.SubKeyCount,
+		ValueCount:  p.ValueCount,
+		Existed:     p.Existed,
+	}, nil
+}
+
+// Create implements RegistryKeyClient.Create.
+func (r *RegistryKeyClientImpl) Create(ctx context.Context, input RegistryKeyInput) (*RegistryKeyState, error) {
+	if err := checkRegistryKeyTarget(input.Hive, input.Path); err != nil {
+		return nil, err
+	}
+	repl := strings.NewReplacer(
+		"@@HIVE@@", psQuote(input.Hive),
+		"@@PATH@@", psQuote(input.Path),
+		"@@ALLOW@@", "$"+psBool(input.AllowExisting),
+	)
+	resp, err := r.runScript(ctx, "create", psRegistryKeyHeader+repl.Replace(psRegistryKeyCreateBody))
+	if err != nil {
+		return nil, err
+	}
+	st, err := parseKeyPayload(resp.Data, input.Hive, input.Path)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, NewRegistryKeyError(RegistryKeyErrorUnknown, "create returned no key state", nil,
+			map[string]string{"hive": input.Hive, "path": input.Path})
+	}
+	return st, nil
+}
+
+// Read implements RegistryKeyClient.Read.
+//
+// Returns (nil, nil) when the key does not exist. The resource Read handler
+// must call resp.State.RemoveResource() in that case.
+func (r *RegistryKeyClientImpl) Read(ctx context.Context, hive, regPath string) (*RegistryKeyState, error) {
+	if err := checkRegistryKeyTarget(hive, regPath); err != nil {
+		return nil, err
+	}
+	repl := strings.NewReplacer(
+		"@@HIVE@@", psQuote(hive),
+		"@@PATH@@", psQuote(regPath),
+	)
+	resp, err := r.runScript(ctx, "read", psRegistryKeyHeader+repl.Replace(psRegistryKeyReadBody))
+	if err != nil {
+		return nil, err
+	}
+	return parseKeyPayload(resp.Data, hive, regPath)
+}
+
+// Delete implements RegistryKeyClient.Delete.
+//
+// Idempotent: a missing key is a silent no-op.
+func (r *RegistryKeyClientImpl) Delete(ctx context.Context, hive, regPath string, recursive bool) error {
+	if err := checkRegistryKeyTarget(hive, regPath); err != nil {
+		return err
+	}
+	repl := strings.NewReplacer(
+		"@@HIVE@@", psQuote(hive),
+		"@@PATH@@", psQuote(regPath),
+		"@@RECURSE@@", "$"+psBool(recursive),
+	)
+	_, err := r.runScript(ctx, "delete", psRegistryKeyHeader+repl.Replace(psRegistryKeyDeleteBody))
+	return err
+}
@@ -0,0 +1,163 @@
+// Package winclient — unit tests for RegistryKeyClientImpl.
+//
+// Tests stub the package-level runRegistryKeyPowerShell hook so no real
+// WinRM connection is required. The rvOKEnvelope / rvErrEnvelope helpers are
+// shared with the registry value tests.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRKTestClient(t *testing.T) *RegistryKeyClientImpl {
+	t.Helper()
+	c, err := New(Config{Host: "winrk01", Username: "u", Password: "p", Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return NewRegistryKeyClient(c)
+}
+
+// stubRKRun replaces runRegistryKeyPowerShell for the duration of a test.
+func stubRKRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runRegistryKeyPowerShell
+	runRegistryKeyPowerShell = fn
+	return func() { runRegistryKeyPowerShell = prev }
+}
+
+func rkKeyData(subkeys, values int, existed bool) map[string]any {
+	return map[string]any{"found": true, "subkey_count": subkeys, "value_count": values, "existed": existed}
+}
+
+func TestRegistryKeyCreate(t *testing.T) {
+	var script string
+	defer stubRKRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return rvOKEnvelope(t, rkKeyData(0, 0, false)), "", nil
+	})()
+	rk := newRKTestClient(t)
+
+	st, err := rk.Create(context.Background(), RegistryKeyInput{Hive: "HKLM", Path: `SOFTWARE\Contoso's App`})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if st.Hive != "HKLM" || st.Path != `SOFTWARE\Contoso's App` || st.Existed {
+		t.Errorf("state = %+v", st)
+	}
+	for _, want := range []string{"'HKLM'", `'SOFTWARE\Contoso''s App'`, "-not $false", "CreateSubKey"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("create script missing %q", want)
+		}
+	}
+}
+
+func TestRegistryKeyCreate_AlreadyExists(t *testing.T) {
+	defer stubRKRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		if strings.Contains(s, "-not $true") {
+			return rvOKEnvelope(t, rkKeyData(2, 3, true)), "", nil
+		}
+		return rvErrEnvelope(t, "already_exists", `registry key already exists: HKLM\SOFTWARE\X`, nil), "", nil
+	})()
+	rk := newRKTestClient(t)
+
+	_, err := rk.Create(context.Background(), RegistryKeyInput{Hive: "HKLM", Path: `SOFTWARE\X`})
+	if !errors.Is(err, ErrRegistryKeyAlreadyExists) {
+		t.Fatalf("err = %v, want already_exists", err)
+	}
+	var rke *RegistryKeyError
+	if !errors.As(err, &rke) || rke.Context["operation"] != "create" || rke.Context["host"] != "winrk01" {
+		t.Errorf("context = %+v", rke)
+	}
+
+	st, err := rk.Create(context.Background(), RegistryKeyInput{Hive: "HKLM", Path: `SOFTWARE\X`, AllowExisting: true})
+	if err != nil || !st.Existed || st.SubKeyCount != 2 || st.ValueCount != 3 {
+		t.Errorf("AllowExisting: %+v, %v", st, err)
+	}
+}
+
+func TestRegistryKeyRead(t *testing.T) {
+	found := true
+	defer stubRKRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		if !found {
+			return rvOKEnvelope(t, map[string]any{"found": false}), "", nil
+		}
+		return rvOKEnvelope(t, rkKeyData(1, 4, true)), "", nil
+	})()
+	rk := newRKTestClient(t)
+
+	st, err := rk.Read(context.Background(), "HKCU", `Software\X`)
+	if err != nil || st == nil || st.SubKeyCount != 1 || st.ValueCount != 4 {
+		t.Fatalf("Read = %+v, %v", st, err)
+	}
+	found = false
+	if st, err := rk.Read(context.Background(), "HKCU", `Software\X`); st != nil || err != nil {
+		t.Errorf("missing key: Read = %+v, %v, want nil, nil", st, err)
+	}
+}
+
+func TestRegistryKeyDelete(t *testing.T) {
+	var script string
+	defer stubRKRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		if strings.Contains(s, "if ($false)") {
+			return rvErrEnvelope(t, "has_subkeys", "registry key has 2 child key(s)", map[string]string{"subkey_count": "2"}), "", nil
+		}
+		return rvOKEnvelope(t, map[string]any{"deleted": true}), "", nil
+	})()
+	rk := newRKTestClient(t)
+
+	if err := rk.Delete(context.Background(), "HKLM", `SOFTWARE\X`, false); !IsRegistryKeyError(err, RegistryKeyErrorHasSubKeys) {
+		t.Errorf("non-recursive delete = %v, want has_subkeys", err)
+	}
+	if err := rk.Delete(context.Background(), "HKLM", `SOFTWARE\X`, true); err != nil {
+		t.Fatalf("recursive delete: %v", err)
+	}
+	if !strings.Contains(script, "DeleteSubKeyTree") {
+		t.Error("recursive delete must use DeleteSubKeyTree")
+	}
+}
+
+func TestRegistryKey_RejectsHiveRootAndUnknownHive(t *testing.T) {
+	defer stubRKRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		t.Fatal("no script may run for an invalid target")
+		return "", "", nil
+	})()
+	rk := newRKTestClient(t)
+
+	if err := rk.Delete(context.Background(), "HKLM", `\`, true); !IsRegistryKeyError(err, RegistryKeyErrorInvalidInput) {
+		t.Errorf("hive root delete = %v", err)
+	}
+	if _, err := rk.Create(context.Background(), RegistryKeyInput{Hive: "HKXX", Path: "a"}); !IsRegistryKeyError(err, RegistryKeyErrorInvalidInput) {
+		t.Errorf("unknown hive = %v", err)
+	}
+}
+
+func TestRegistryKey_TransportAndEnvelopeErrors(t *testing.T) {
+	rk := newRKTestClient(t)
+
+	restore := stubRKRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "boom", errors.New("transport down")
+	})
+	_, err := rk.Read(context.Background(), "HKLM", "SOFTWARE")
+	restore()
+	if !IsRegistryKeyError(err, RegistryKeyErrorUnknown) || !strings.Contains(err.Error(), "transport down") {
+		t.Errorf("transport error = %v", err)
+	}
+
+	restore = stubRKRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "not json\n", "", nil
+	})
+	_, err = rk.Read(context.Background(), "HKLM", "SOFTWARE")
+	restore()
+	if !IsRegistryKeyError(err, RegistryKeyErrorUnknown) {
+		t.Errorf("missing envelope = %v", err)
+	}
+
+	if got := mapRegistryKeyErrorKind("bogus"); got != RegistryKeyErrorUnknown {
+		t.Errorf("mapRegistryKeyErrorKind(bogus) = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package winclient: registry key types, interface, and error definitions.
+//
+// windows_registry_key manages a key itself (its existence), independently of
+// the values it holds, which windows_registry_value manages.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RegistryKeyErrorKind categorises errors returned by RegistryKeyClient operations.
+type RegistryKeyErrorKind string
+
+const (
+	RegistryKeyErrorAlreadyExists RegistryKeyErrorKind = "already_exists"
+	RegistryKeyErrorHasSubKeys    RegistryKeyErrorKind = "has_subkeys"
+	RegistryKeyErrorPermission    RegistryKeyErrorKind = "permission_denied"
+	RegistryKeyErrorInvalidInput  RegistryKeyErrorKind = "invalid_input"
+	RegistryKeyErrorUnknown       RegistryKeyErrorKind = "unknown"
+)
+
+// RegistryKeyError is the structured error type returned by all RegistryKeyClient methods.
+type RegistryKeyError struct {
+	Kind    RegistryKeyErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements the error interface.
+func (e *RegistryKeyError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_registry_key [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_registry_key [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *RegistryKeyError) Unwrap() error { return e.Cause }
+
+// Is implements errors.Is comparison by Kind or ErrorClass.
+func (e *RegistryKeyError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*RegistryKeyError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewRegistryKeyError constructs a *RegistryKeyError.
+func NewRegistryKeyError(kind RegistryKeyErrorKind, message string, cause error, ctx map[string]string) *RegistryKeyError {
+	return &RegistryKeyError{Kind: kind, Message: message, Cause: cause, Context: ctx}
+}
+
+// IsRegistryKeyError reports whether err is a *RegistryKeyError with the given kind.
+func IsRegistryKeyError(err error, kind RegistryKeyErrorKind) bool {
+	var rke *RegistryKeyError
+	if errors.As(err, &rke) {
+		return rke.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrRegistryKeyAlreadyExists = &RegistryKeyError{Kind: RegistryKeyErrorAlreadyExists}
+	ErrRegistryKeyHasSubKeys    = &RegistryKeyError{Kind: RegistryKeyErrorHasSubKeys}
+	ErrRegistryKeyPermission    = &RegistryKeyError{Kind: RegistryKeyErrorPermission}
+	ErrRegistryKeyInvalidInput  = &RegistryKeyError{Kind: RegistryKeyErrorInvalidInput}
+	ErrRegistryKeyUnknown       = &RegistryKeyError{Kind: RegistryKeyErrorUnknown}
+)
+
+// RegistryKeyInput carries the parameters for a RegistryKeyClient.Create call.
+type RegistryKeyInput struct {
+	// Hive is one of HKLM, HKCU, HKCR, HKU, HKCC.
+	Hive string
+	// Path is the subkey path below the hive, without leading or trailing
+	// backslashes. Missing intermediate keys are created.
+	Path string
+	// AllowExisting adopts a key that already exists instead of failing with
+	// RegistryKeyErrorAlreadyExists.
+	AllowExisting bool
+}
+
+// RegistryKeyState is the observed state of a Windows registry key.
+type RegistryKeyState struct {
+	Hive string
+	Path string
+	// SubKeyCount and ValueCount are the direct children of the key.
+	SubKeyCount int
+	ValueCount  int
+	// Existed is set by Create when the key was already present (only
+	// possible with AllowExisting).
+	Existed bool
+}
+
+// RegistryKeyClient manages Windows registry keys over WinRM using the .NET
+// Microsoft.Win32.Registry API via PowerShell.
+//
+// Error conventions:
+//   - Create returns RegistryKeyErrorAlreadyExists when the key exists and
+//     AllowExisting is false.
+//   - Read returns (nil, nil) when the key does not exist.
+//   - Delete is idempotent: a missing key is a silent no-op. Without recursive
+//     it returns RegistryKeyErrorHasSubKeys when the key has child keys.
+type RegistryKeyClient interface {
+	Create(ctx context.Context, input RegistryKeyInput) (*RegistryKeyState, error)
+	Read(ctx context.Context, hive, path string) (*RegistryKeyState, error)
+	Delete(ctx context.Context, hive, path string, recursive bool) error
+}
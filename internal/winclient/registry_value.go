@@ -125,16 +125,25 @@ function Build-DataResult([string]$KindStr, [object]$RawVal) {
 
 // psRegistryValueSetBody1 is the first part of the Set PS script (up to "$valData = ").
 // The value expression is injected via Go string concatenation between body1 and body2.
-// Placeholders @@HIVE@@, @@PATH@@, @@NAME@@, @@KIND@@ are replaced by strings.NewReplacer.
+// Placeholders @@HIVE@@, @@PATH@@, @@NAME@@, @@KIND@@, @@CREATE@@ are replaced by strings.NewReplacer.
 const psRegistryValueSetBody1 = `
 & {
   $_rvKey = $null
   try {
     $root = Get-RegHive @@HIVE@@
-    $_rvKey = $root.CreateSubKey(@@PATH@@)
-    if ($null -eq $_rvKey) {
-      Emit-Err 'permission_denied' 'CreateSubKey returned null (insufficient privileges)' @{}
-      return
+    $createKey = @@CREATE@@
+    if ($createKey) {
+      $_rvKey = $root.CreateSubKey(@@PATH@@)
+      if ($null -eq $_rvKey) {
+        Emit-Err 'permission_denied' 'CreateSubKey returned null (insufficient privileges)' @{}
+        return
+      }
+    } else {
+      $_rvKey = $root.OpenSubKey(@@PATH@@, $true)
+      if ($null -eq $_rvKey) {
+        Emit-Err 'not_found' ('registry key does not exist: ' + @@HIVE@@ + '\' + @@PATH@@) @{ hive = @@HIVE@@; path = @@PATH@@ }
+        return
+      }
     }
     $existProbe = $_rvKey.GetValue(@@NAME@@, $null, [Microsoft.Win32.RegistryValueOptions]::DoNotExpandEnvironmentNames)
     if ($null -ne $existProbe) {
@@ -258,6 +267,7 @@ type rvDataPayload struct {
 
 // runScript executes a PS script and parses the JSON envelope.
 func (r *RegistryValueClientImpl) runScript(ctx context.Context, op, script string) (*rvPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	stdout, stderr, err := runRegistryValuePowerShell(ctx, r.c, script)
 	if err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
@@ -375,6 +385,14 @@ func buildPSValueExpr(input RegistryValueInput) (string, error) {
 		if strs == nil {
 			strs = []string{}
 		}
+		for i, s := range strs {
+			// An empty string ends a REG_MULTI_SZ (double NUL), so it and
+			// every later element would be lost on read.
+			if s == "" {
+				return "", &RegistryValueError{Kind: RegistryValueErrorInvalidInput,
+					Message: fmt.Sprintf("REG_MULTI_SZ element %d is empty; a multi-string value cannot hold empty strings", i)}
+			}
+		}
 		return "[string[]]" + psQuoteList(strs), nil
 
 	case RegistryValueKindBinary, RegistryValueKindNone:
@@ -385,6 +403,9 @@ func buildPSValueExpr(input RegistryValueInput) (string, error) {
 		if hex == "" {
 			return "[byte[]]@()", nil
 		}
+		if err := validateRegistryHex(hex); err != nil {
+			return "", err
+		}
 		return fmt.Sprintf("(Hex-To-Bytes %s)", psQuote(hex)), nil
 
 	default:
@@ -393,6 +414,22 @@ func buildPSValueExpr(input RegistryValueInput) (string, error) {
 	}
 }
 
+// validateRegistryHex checks that hex is lowercase hexadecimal with an even
+// number of characters, the form Hex-To-Bytes expects and Bytes-To-Hex emits.
+func validateRegistryHex(hex string) error {
+	if len(hex)%2 != 0 {
+		return &RegistryValueError{Kind: RegistryValueErrorInvalidInput,
+			Message: fmt.Sprintf("binary value must have an even number of hex characters; got %d", len(hex))}
+	}
+	for i, r := range hex {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return &RegistryValueError{Kind: RegistryValueErrorInvalidInput,
+				Message: fmt.Sprintf("binary value must be lowercase hex without separators; invalid character %q at offset %d", r, i)}
+		}
+	}
+	return nil
+}
+
 // parseMultiStringPayload handles the PS ConvertTo-Json quirk where a
 // single-element [string[]] may be serialised as a JSON string scalar rather
 // than a JSON array.
@@ -445,7 +482,8 @@ func (r *RegistryValueClientImpl) parseDataPayload(raw json.RawMessage, hive, re
 
 	case RegistryValueKindBinary, RegistryValueKindNone:
 		if payload.ValueBinary != nil {
-			state.ValueBinary = payload.ValueBinary
+			hex := strings.ToLower(*payload.ValueBinary)
+			state.ValueBinary = &hex
 		} else {
 			empty := ""
 			state.ValueBinary = &empty
@@ -460,7 +498,7 @@ func (r *RegistryValueClientImpl) parseDataPayload(raw json.RawMessage, hive, re
 
 // Set implements RegistryValueClient.Set.
 //
-// Creates missing parent keys (EC-1), enforces type-conflict guard (EC-3),
+// Creates missing parent keys (EC-1) unless input.FailIfKeyMissing is set, enforces type-conflict guard (EC-3),
 // writes the value, and returns the post-write state.
 func (r *RegistryValueClientImpl) Set(ctx context.Context, input RegistryValueInput) (*RegistryValueState, error) {
 	valueExpr, err := buildPSValueExpr(input)
@@ -474,6 +512,7 @@ func (r *RegistryValueClientImpl) Set(ctx context.Context, input RegistryValueIn
 		"@@NAME@@", psQuote(input.Name),
 		"@@KIND@@", psQuote(string(input.Kind)),
 		"@@EXPAND@@", "$"+psBool(input.ExpandEnvironmentVariables),
+		"@@CREATE@@", "$"+psBool(!input.FailIfKeyMissing),
 	)
 
 	script := psRegistryValueHeader + "\n" +
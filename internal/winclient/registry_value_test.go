@@ -487,6 +487,27 @@ func TestBuildPSValueExpr_REG_NONE_WithBinary(t *testing.T) {
 	}
 }
 
+func TestBuildPSValueExpr_REG_MULTI_SZ_EmptyElementRejected(t *testing.T) {
+	_, err := buildPSValueExpr(RegistryValueInput{
+		Kind:         RegistryValueKindMultiString,
+		ValueStrings: []string{"a", "", "b"},
+	})
+	if !IsRegistryValueError(err, RegistryValueErrorInvalidInput) {
+		t.Errorf("expected invalid_input for an empty element, got: %v", err)
+	}
+}
+
+func TestBuildPSValueExpr_BinaryHexRejected(t *testing.T) {
+	for _, hex := range []string{"abc", "0", "DEADBEEF", "de ad", "zz", "0x01"} {
+		for _, kind := range []RegistryValueKind{RegistryValueKindBinary, RegistryValueKindNone} {
+			_, err := buildPSValueExpr(RegistryValueInput{Kind: kind, ValueBinary: rvPtr(hex)})
+			if !IsRegistryValueError(err, RegistryValueErrorInvalidInput) {
+				t.Errorf("%s %q: expected invalid_input, got: %v", kind, hex, err)
+			}
+		}
+	}
+}
+
 func TestBuildPSValueExpr_UnknownKind(t *testing.T) {
 	_, err := buildPSValueExpr(RegistryValueInput{Kind: RegistryValueKind("REG_BOGUS")})
 	if err == nil {
@@ -689,6 +710,18 @@ func TestParseDataPayload_REG_MULTI_SZ_Empty(t *testing.T) {
 	}
 }
 
+func TestParseDataPayload_REG_BINARY_LowercasesHex(t *testing.T) {
+	_, rv := newRVTestClient(t)
+	raw, _ := json.Marshal(rvFoundBinary("REG_BINARY", "DEADbeef"))
+	state, err := rv.parseDataPayload(json.RawMessage(raw), "HKLM", `SOFTWARE\Test`, "Bin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.ValueBinary == nil || *state.ValueBinary != "deadbeef" {
+		t.Errorf("ValueBinary = %v, want deadbeef", state.ValueBinary)
+	}
+}
+
 func TestParseDataPayload_REG_MULTI_SZ_PSScalarQuirk(t *testing.T) {
 	// PS serialises single-element [string[]] as a JSON scalar string
 	_, rv := newRVTestClient(t)
@@ -824,6 +857,53 @@ func TestRegistryValueSet_HappyPath_REG_MULTI_SZ(t *testing.T) {
 	}
 }
 
+// TestRegistryValueSet_RoundTrip checks that each multi-string and binary
+// input reaches the script in the expected form and reads back unchanged.
+func TestRegistryValueSet_RoundTrip(t *testing.T) {
+	cases := []struct {
+		name       string
+		in         RegistryValueInput
+		wantScript string
+		payload    map[string]any
+	}{
+		{"multi", RegistryValueInput{Kind: RegistryValueKindMultiString, ValueStrings: []string{"C:\\data", "it's"}},
+			`[string[]]@('C:\data','it''s')`, rvFoundMulti([]string{"C:\\data", "it's"})},
+		{"multi single", RegistryValueInput{Kind: RegistryValueKindMultiString, ValueStrings: []string{"only"}},
+			`[string[]]@('only')`, map[string]any{"found": true, "kind": "REG_MULTI_SZ", "value_strings": "only"}},
+		{"multi empty", RegistryValueInput{Kind: RegistryValueKindMultiString, ValueStrings: []string{}},
+			`[string[]]@()`, rvFoundMulti([]string{})},
+		{"binary", RegistryValueInput{Kind: RegistryValueKindBinary, ValueBinary: rvPtr("00ff10")},
+			`(Hex-To-Bytes '00ff10')`, rvFoundBinary("REG_BINARY", "00ff10")},
+		{"binary empty", RegistryValueInput{Kind: RegistryValueKindBinary, ValueBinary: rvPtr("")},
+			`[byte[]]@()`, rvFoundBinary("REG_BINARY", "")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var script string
+			defer stubRVRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+				script = s
+				return rvOKEnvelope(t, tc.payload), "", nil
+			})()
+			_, rv := newRVTestClient(t)
+			tc.in.Hive, tc.in.Path, tc.in.Name = "HKLM", `SOFTWARE\Test`, "V"
+			state, err := rv.Set(context.Background(), tc.in)
+			if err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			if !strings.Contains(script, tc.wantScript) {
+				t.Errorf("script missing %s", tc.wantScript)
+			}
+			if tc.in.Kind == RegistryValueKindMultiString {
+				if state.ValueStrings == nil || strings.Join(state.ValueStrings, "|") != strings.Join(tc.in.ValueStrings, "|") {
+					t.Errorf("ValueStrings = %#v, want %#v", state.ValueStrings, tc.in.ValueStrings)
+				}
+			} else if state.ValueBinary == nil || *state.ValueBinary != *tc.in.ValueBinary {
+				t.Errorf("ValueBinary = %v, want %q", state.ValueBinary, *tc.in.ValueBinary)
+			}
+		})
+	}
+}
+
 func TestRegistryValueSet_HappyPath_REG_BINARY(t *testing.T) {
 	defer stubRVRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
 		return rvOKEnvelope(t, rvFoundBinary("REG_BINARY", "deadbeef")), "", nil
@@ -891,6 +971,33 @@ func TestRegistryValueSet_ExpandTrue_ScriptContainsTrueFlag(t *testing.T) {
 }
 
 // EC-3: type conflict
+func TestRegistryValueSet_FailIfKeyMissing(t *testing.T) {
+	var scripts []string
+	defer stubRVRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		scripts = append(scripts, script)
+		if strings.Contains(script, "$createKey = $false") {
+			return rvErrEnvelope(t, "not_found", `registry key does not exist: HKLM\SOFTWARE\Missing`,
+				map[string]string{"hive": "HKLM", "path": `SOFTWARE\Missing`}), "", nil
+		}
+		return rvOKEnvelope(t, rvFoundData("REG_SZ", "1")), "", nil
+	})()
+	_, rv := newRVTestClient(t)
+	in := RegistryValueInput{Hive: "HKLM", Path: `SOFTWARE\Missing`, Name: "v", Kind: RegistryValueKindString, ValueString: rvPtr("1")}
+
+	if _, err := rv.Set(context.Background(), in); err != nil {
+		t.Fatalf("default Set must create the key: %v", err)
+	}
+	if !strings.Contains(scripts[0], "$createKey = $true") {
+		t.Error("default Set script must take the CreateSubKey branch")
+	}
+
+	in.FailIfKeyMissing = true
+	_, err := rv.Set(context.Background(), in)
+	if !IsRegistryValueError(err, RegistryValueErrorNotFound) {
+		t.Fatalf("Set with FailIfKeyMissing = %v, want not_found", err)
+	}
+}
+
 func TestRegistryValueSet_EC3_TypeConflict(t *testing.T) {
 	defer stubRVRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
 		return rvErrEnvelope(t, "type_conflict",
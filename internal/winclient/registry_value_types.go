@@ -53,8 +53,11 @@ func (e *RegistryValueError) Error() string {
 // Unwrap returns the underlying cause.
 func (e *RegistryValueError) Unwrap() error { return e.Cause }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 func (e *RegistryValueError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*RegistryValueError)
 	if !ok {
 		return false
@@ -95,6 +98,9 @@ type RegistryValueInput struct {
 	ValueStrings               []string
 	ValueBinary                *string
 	ExpandEnvironmentVariables bool
+	// FailIfKeyMissing makes Set return RegistryValueErrorNotFound when the
+	// parent key does not exist, instead of creating it.
+	FailIfKeyMissing bool
 }
 
 // RegistryValueState is the observed state of a Windows registry value.
@@ -114,6 +120,7 @@ type RegistryValueState struct {
 // Error conventions:
 //   - Read returns (nil, nil) when the key or value does not exist (EC-4).
 //   - Set returns RegistryValueErrorTypeConflict when the value exists with a different kind (EC-3).
+//   - Set returns RegistryValueErrorNotFound when the key is missing and FailIfKeyMissing is set.
 //   - Delete is idempotent: missing value/key is a silent no-op (EC-12).
 type RegistryValueClient interface {
 	Set(ctx context.Context, input RegistryValueInput) (*RegistryValueState, error)
@@ -0,0 +1,86 @@
+// Package winclient: bounded retries for reads that race host start-up.
+//
+// Right after a reboot, WinRM answers before WMI, the Service Control
+// Manager or the Server Manager provider are ready, and the first
+// Get-WindowsFeature / Get-Service can fail with errors that succeed a few
+// seconds later. Resources opt in per call with WithReadRetries; only errors
+// classed ErrTransient (text matching TransientErrorPatterns) are retried, so
+// a real failure (not found, access denied, bad credentials) still surfaces
+// on the first attempt, even when its text happens to match a pattern.
+package winclient
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// TransientErrorPatterns are case-insensitive substrings of error text that
+// mark a read failure as transient. Callers embedding the package may extend
+// the list before creating clients.
+var TransientErrorPatterns = []string{
+	"not ready",
+	"rpc server is unavailable",
+	"invalid namespace",
+	"provider load failure",
+	"cannot accept control messages",
+	"0x80070015", // ERROR_NOT_READY
+	"0x800706ba", // RPC_S_SERVER_UNAVAILABLE
+}
+
+// readRetryBackoff is the delay before the first retry; it doubles after
+// every attempt. Tests may shorten it.
+var readRetryBackoff = 2 * time.Second
+
+// readRetriesKey is the context key for WithReadRetries.
+type readRetriesKey struct{}
+
+// WithReadRetries returns a copy of ctx under which reads that support it
+// make up to n extra attempts after a transient failure. n <= 0 disables
+// retries (the default).
+func WithReadRetries(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, readRetriesKey{}, n)
+}
+
+// readRetries returns the retry count carried by ctx.
+func readRetries(ctx context.Context) int {
+	n, _ := ctx.Value(readRetriesKey{}).(int)
+	return n
+}
+
+// isTransientError reports whether err's text matches TransientErrorPatterns.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range TransientErrorPatterns {
+		if strings.Contains(msg, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryTransientRead calls read once, then again while it fails with a
+// transient error and the retry budget from ctx (WithReadRetries) lasts.
+// The last error is returned when the budget runs out or ctx ends while
+// waiting.
+func retryTransientRead[T any](ctx context.Context, read func() (T, error)) (T, error) {
+	retries := readRetries(ctx)
+	delay := readRetryBackoff
+	for attempt := 0; ; attempt++ {
+		v, err := read()
+		if err == nil || attempt >= retries || Classify(err) != ErrTransient {
+			return v, err
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return v, err
+		case <-t.C:
+		}
+		delay *= 2
+	}
+}
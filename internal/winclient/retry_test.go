@@ -0,0 +1,121 @@
+// Package winclient — unit tests for the transient read retry.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubReadRetryBackoff shortens readRetryBackoff for the duration of a test.
+func stubReadRetryBackoff() func() {
+	prev := readRetryBackoff
+	readRetryBackoff = time.Millisecond
+	return func() { readRetryBackoff = prev }
+}
+
+func TestIsTransientError(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"The device is not ready.":                    true,
+		"The RPC server is unavailable. (0x800706BA)": true,
+		"Invalid namespace":                           true,
+		"Access is denied.":                           false,
+		"feature not found":                           false,
+	} {
+		if got := isTransientError(errors.New(msg)); got != want {
+			t.Errorf("isTransientError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+	if isTransientError(nil) {
+		t.Error("nil error must not be transient")
+	}
+}
+
+func TestFeatureRead_RetriesTransient(t *testing.T) {
+	defer stubReadRetryBackoff()()
+	calls := 0
+	defer stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		calls++
+		if calls < 3 {
+			return featErr(t, "unknown", "The RPC server is unavailable."), "", nil
+		}
+		return featOK(t, fakeFeatureData("Web-Server", "Installed")), "", nil
+	})()
+
+	ctx := WithReadRetries(context.Background(), 3)
+	info, err := NewFeatureClient(newFeatTestClient(t)).Read(ctx, "Web-Server")
+	if err != nil || info == nil || !info.Installed {
+		t.Fatalf("Read = %+v, %v", info, err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestFeatureRead_NoRetryByDefault(t *testing.T) {
+	defer stubReadRetryBackoff()()
+	calls := 0
+	defer stubFeatRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		calls++
+		return featErr(t, "unknown", "The RPC server is unavailable."), "", nil
+	})()
+
+	if _, err := NewFeatureClient(newFeatTestClient(t)).Read(context.Background(), "Web-Server"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestServiceRead_RetryBudgetExhausted(t *testing.T) {
+	defer stubReadRetryBackoff()()
+	calls := 0
+	defer stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		calls++
+		return errEnvelope(t, "unknown", "The service is not ready."), "", nil
+	})()
+
+	ctx := WithReadRetries(context.Background(), 2)
+	if _, err := NewServiceClient(newTestClient(t)).Read(ctx, "svc"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (1 + 2 retries)", calls)
+	}
+}
+
+func TestServiceRead_NonTransientNotRetried(t *testing.T) {
+	defer stubReadRetryBackoff()()
+	calls := 0
+	defer stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		calls++
+		return errEnvelope(t, "permission_denied", "Access is denied."), "", nil
+	})()
+
+	ctx := WithReadRetries(context.Background(), 5)
+	if _, err := NewServiceClient(newTestClient(t)).Read(ctx, "svc"); !errors.Is(err, ErrServicePermission) {
+		t.Fatalf("err = %v, want permission_denied", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetryTransientRead_StopsOnCancel(t *testing.T) {
+	prev := readRetryBackoff
+	readRetryBackoff = time.Hour
+	defer func() { readRetryBackoff = prev }()
+
+	ctx, cancel := context.WithCancel(WithReadRetries(context.Background(), 5))
+	calls := 0
+	_, err := retryTransientRead(ctx, func() (int, error) {
+		calls++
+		cancel()
+		return 0, errors.New("not ready")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("calls = %d, err = %v", calls, err)
+	}
+}
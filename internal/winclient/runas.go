@@ -0,0 +1,122 @@
+// Package winclient: running scripts under another account (RunAs).
+//
+// Some operations need a privileged account other than the WinRM login, for
+// instance a domain account that may change ACLs on a share while the login
+// is a local administrator. With Config.RunAs set, every run starts a small
+// wrapper as the WinRM login instead of the script itself. The wrapper opens
+// a PowerShell remoting session to the host itself as the RunAs account
+// (Invoke-Command -ComputerName localhost -Credential), starts the usual
+// bootstrap there as a child process with redirected stdio, feeds it the
+// script and its input, and writes the child's stdout and stderr back out
+// unchanged. The caller therefore sees exactly what a direct run would
+// produce: the JSON envelope on stdout, and error records and the bootstrap
+// status line on stderr. A non-zero exit code of the child becomes the exit
+// code of the run. Start-Process -Credential is not used: it goes through
+// the Secondary Logon service, which refuses network logons such as a WinRM
+// session.
+//
+// Security invariants:
+//   - The password is the first stdin line of the wrapper and is never part
+//     of a script or command line; it only lives in a SecureString on the
+//     host.
+//   - The username is interpolated only through psQuote.
+//   - The script and its input travel as they would without RunAs: the
+//     base64 script line and the caller's input, on the child's stdin.
+package winclient
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RunAsConfig is the account scripts run under when Config.RunAs is set.
+type RunAsConfig struct {
+	// Username is the account name: DOMAIN\user, user@domain, or a local
+	// user. It must be allowed to open a PowerShell remoting session on the
+	// host (Administrators or Remote Management Users).
+	Username string
+	Password string
+}
+
+// validate checks that both credentials are set and the username fits on
+// one line.
+func (r *RunAsConfig) validate() error {
+	if strings.TrimSpace(r.Username) == "" || r.Password == "" {
+		return fmt.Errorf("winclient: run_as needs both a username and a password")
+	}
+	if strings.ContainsAny(r.Username, "\r\n") {
+		return fmt.Errorf("winclient: run_as username must not contain line breaks")
+	}
+	if strings.ContainsAny(r.Password, "\r\n") {
+		return fmt.Errorf("winclient: run_as password must not contain line breaks")
+	}
+	return nil
+}
+
+// psRunAs is the wrapper run as the WinRM login. @@USER@@, @@EXE@@ and
+// @@ARGS@@ are replaced by runAsScript with quoted literals. stdin holds the
+// password line, then the child's whole stdin (base64 script line and the
+// caller's input).
+const psRunAs = `$ErrorActionPreference = 'Stop'
+$runAsPassword = [Console]::In.ReadLine()
+$runAsStdin = [Console]::In.ReadToEnd()
+$runAsCred = New-Object System.Management.Automation.PSCredential(@@USER@@, (ConvertTo-SecureString $runAsPassword -AsPlainText -Force))
+Remove-Variable runAsPassword
+$r = Invoke-Command -ComputerName localhost -Credential $runAsCred -ArgumentList $runAsStdin -ScriptBlock {
+  param([string]$In)
+  $psi = New-Object System.Diagnostics.ProcessStartInfo
+  $psi.FileName = @@EXE@@
+  $psi.Arguments = @@ARGS@@
+  $psi.UseShellExecute = $false
+  $psi.RedirectStandardInput = $true
+  $psi.RedirectStandardOutput = $true
+  $psi.RedirectStandardError = $true
+  $p = [System.Diagnostics.Process]::Start($psi)
+  $out = $p.StandardOutput.ReadToEndAsync()
+  $err = $p.StandardError.ReadToEndAsync()
+  $p.StandardInput.Write($In)
+  $p.StandardInput.Close()
+  $p.WaitForExit()
+  [pscustomobject]@{ Out = $out.Result; Err = $err.Result; Code = $p.ExitCode }
+}
+[Console]::Out.Write($r.Out)
+[Console]::Error.Write($r.Err)
+if ($r.Code -ne 0) { exit $r.Code }
+`
+
+// runAsScript renders psRunAs for the client's configuration. The child is
+// started with the same executable and bootstrap flags as a direct run.
+func (c *Client) runAsScript() string {
+	return strings.NewReplacer(
+		"@@USER@@", psQuote(c.cfg.RunAs.Username),
+		"@@EXE@@", psQuote(c.cfg.PowerShellPath),
+		"@@ARGS@@", psQuote(c.commandArgs()),
+	).Replace(psRunAs)
+}
+
+// runStdin lays out the bootstrap's stdin for script and input. With RunAs
+// the bootstrap runs the wrapper, whose input is the password line followed
+// by the stdin a direct run would have used.
+func (c *Client) runStdin(script, input string) io.Reader {
+	if c.cfg.RunAs == nil {
+		return composeStdin(script, input)
+	}
+	return composeStdin(c.runAsScript(), c.cfg.RunAs.Password+"\n"+encodePowerShell(script)+"\n"+input)
+}
+
+// splitRunStatus removes the bootstrap status line from stderr, as
+// splitScriptStatus does. With RunAs, stderr carries two: the script's, then
+// the wrapper's. When the wrapper succeeded the script's line is the one that
+// counts; a failed wrapper (e.g. the remoting session was refused) is
+// reported as is.
+func (c *Client) splitRunStatus(stderr string) (string, scriptStatus, bool) {
+	rest, st, ok := splitScriptStatus(stderr)
+	if c.cfg.RunAs == nil || !ok || st.failed() {
+		return rest, st, ok
+	}
+	if inner, ist, iok := splitScriptStatus(rest); iok {
+		return inner, ist, true
+	}
+	return rest, st, ok
+}
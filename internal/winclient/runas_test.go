@@ -0,0 +1,134 @@
+// Package winclient — unit tests for Config.RunAs.
+package winclient
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func newRunAsTestClient(t *testing.T, mod func(*Config)) *Client {
+	t.Helper()
+	cfg := Config{Host: "win01", Username: "u", Password: "p",
+		RunAs: &RunAsConfig{Username: `CONTOSO\o'brien`, Password: "s3cr3t-pÄss"}}
+	if mod != nil {
+		mod(&cfg)
+	}
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestNew_RunAsValidation(t *testing.T) {
+	for name, ra := range map[string]*RunAsConfig{
+		"no username":      {Password: "p"},
+		"no password":      {Username: "u"},
+		"newline username": {Username: "u\nWrite-Host x", Password: "p"},
+		"newline password": {Username: "u", Password: "p\nx"},
+	} {
+		if _, err := New(Config{Host: "win01", Username: "u", Password: "p", RunAs: ra}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRunStdin_WithoutRunAs(t *testing.T) {
+	c, err := New(Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(c.runStdin("Get-Date", "in"))
+	want, _ := io.ReadAll(composeStdin("Get-Date", "in"))
+	if string(got) != string(want) {
+		t.Errorf("runStdin without RunAs = %q, want %q", got, want)
+	}
+}
+
+func TestRunStdin_RunAs(t *testing.T) {
+	c := newRunAsTestClient(t, nil)
+	raw, err := io.ReadAll(c.runStdin("$p=[Console]::In.ReadLine()", "input line"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitN(string(raw), "\n", 4)
+	if len(lines) != 4 {
+		t.Fatalf("stdin has %d lines, want 4: %q", len(lines), raw)
+	}
+	wrapper := decodePowerShell(t, lines[0])
+	if lines[1] != "s3cr3t-pÄss" {
+		t.Errorf("line 2 = %q, want the password", lines[1])
+	}
+	if got := decodePowerShell(t, lines[2]); got != "$p=[Console]::In.ReadLine()" {
+		t.Errorf("line 3 decodes to %q, want the script", got)
+	}
+	if lines[3] != "input line" {
+		t.Errorf("remainder = %q, want the caller's input", lines[3])
+	}
+
+	if strings.Contains(wrapper, "s3cr3t") {
+		t.Error("the password must not appear in the wrapper script")
+	}
+	for _, want := range []string{
+		`PSCredential('CONTOSO\o''brien', `,
+		"Invoke-Command -ComputerName localhost -Credential $runAsCred",
+		"$psi.FileName = 'powershell.exe'",
+		"$psi.Arguments = '" + bootstrapArgs() + "'",
+		"if ($r.Code -ne 0) { exit $r.Code }",
+	} {
+		if !strings.Contains(wrapper, want) {
+			t.Errorf("wrapper missing %q", want)
+		}
+	}
+	if strings.Contains(wrapper, "@@") {
+		t.Errorf("unreplaced placeholder in wrapper:\n%s", wrapper)
+	}
+}
+
+func TestRunAsScript_FollowsBootstrapSettings(t *testing.T) {
+	c := newRunAsTestClient(t, func(cfg *Config) {
+		cfg.PowerShellPath = `C:\Program Files\PowerShell\7\pwsh.exe`
+		cfg.DisableEncodedCommand = true
+	})
+	wrapper := c.runAsScript()
+	if !strings.Contains(wrapper, `$psi.FileName = 'C:\Program Files\PowerShell\7\pwsh.exe'`) ||
+		!strings.Contains(wrapper, "$psi.Arguments = '"+strings.ReplaceAll(rawBootstrapArgs(), "'", "''")+"'") {
+		t.Errorf("child must use the configured executable and bootstrap flags:\n%s", wrapper)
+	}
+}
+
+func TestSplitRunStatus(t *testing.T) {
+	c := newRunAsTestClient(t, nil)
+	cases := []struct {
+		stderr   string
+		wantRest string
+		want     scriptStatus
+		wantOK   bool
+	}{
+		// The script failed, the wrapper did not: the script's line wins.
+		{"oops\n#winclient-status 1060 True\n#winclient-status 0 True\n", "oops\n", scriptStatus{ExitCode: 1060, Succeeded: true}, true},
+		{"#winclient-status 0 True\n#winclient-status 0 True\n", "", scriptStatus{ExitCode: 0, Succeeded: true}, true},
+		// The wrapper failed before the child wrote a status line.
+		{"Access is denied.\n#winclient-status 0 False\n", "Access is denied.\n", scriptStatus{ExitCode: 0, Succeeded: false}, true},
+		// The child wrote none but the wrapper succeeded.
+		{"#winclient-status 0 True\n", "", scriptStatus{ExitCode: 0, Succeeded: true}, true},
+		{"boom\n", "boom\n", scriptStatus{}, false},
+	}
+	for _, tc := range cases {
+		rest, st, ok := c.splitRunStatus(tc.stderr)
+		if rest != tc.wantRest || st != tc.want || ok != tc.wantOK {
+			t.Errorf("splitRunStatus(%q) = %q, %+v, %v; want %q, %+v, %v",
+				tc.stderr, rest, st, ok, tc.wantRest, tc.want, tc.wantOK)
+		}
+	}
+
+	direct, err := New(Config{Host: "win01", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest, st, _ := direct.splitRunStatus("#winclient-status 5 True\n#winclient-status 0 True\n"); st.ExitCode != 0 ||
+		rest != "#winclient-status 5 True\n" {
+		t.Errorf("without RunAs only the last status line is removed: %q %+v", rest, st)
+	}
+}
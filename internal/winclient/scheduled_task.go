@@ -313,18 +313,18 @@ type stPSResponse struct {
 }
 
 type stTaskPayload struct {
-	Name           string              `json:"name"`
-	Path           string              `json:"path"`
-	Description    string              `json:"description"`
-	Enabled        bool                `json:"enabled"`
-	State          string              `json:"state"`
-	LastRunTime    string              `json:"last_run_time"`
-	LastTaskResult int64               `json:"last_task_result"`
-	NextRunTime    string              `json:"next_run_time"`
-	Principal      *stPrincipalPayload `json:"principal"`
-	Actions        []stActionPayload   `json:"actions"`
-	Triggers       []stTriggerPayload  `json:"triggers"`
-	Settings       *stSettingsPayload  `json:"settings"`
+	Name           string                     `json:"name"`
+	Path           string                     `json:"path"`
+	Description    string                     `json:"description"`
+	Enabled        bool                       `json:"enabled"`
+	State          string                     `json:"state"`
+	LastRunTime    string                     `json:"last_run_time"`
+	LastTaskResult int64                      `json:"last_task_result"`
+	NextRunTime    string                     `json:"next_run_time"`
+	Principal      *stPrincipalPayload        `json:"principal"`
+	Actions        jsonList[stActionPayload]  `json:"actions"`
+	Triggers       jsonList[stTriggerPayload] `json:"triggers"`
+	Settings       *stSettingsPayload         `json:"settings"`
 }
 
 type stPrincipalPayload struct {
@@ -382,6 +382,7 @@ var runSTPSInput = func(ctx context.Context, c *Client, script, stdin string) (s
 }
 
 func (c *ScheduledTaskClientImpl) runSTEnvelope(ctx context.Context, op, id, script string) (*stPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := psSTHeader + "\n" + script
 	stdout, stderr, err := runSTPS(ctx, c.c, full)
 	if err != nil {
@@ -429,6 +430,7 @@ func (c *ScheduledTaskClientImpl) runSTEnvelope(ctx context.Context, op, id, scr
 // piped over stdin instead of embedded in the script body. The stdin value is
 // NEVER copied into error context or logs.
 func (c *ScheduledTaskClientImpl) runSTEnvelopeWithInput(ctx context.Context, op, id, script, stdin string) (*stPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := psSTHeader + "\n" + script
 	stdout, stderr, err := runSTPSInput(ctx, c.c, full, stdin)
 	if err != nil {
@@ -866,7 +868,7 @@ if ($null -ne $_stExisting) {
   exit 0
 }
 `, psQuote(input.Name), psQuote(input.Path),
-		strings.ReplaceAll(id, "'", "''"),
+		psEscapeSingleQuoted(id),
 		psQuote(input.Name), psQuote(input.Path)))
 
 	// Folder creation (ADR-ST-1)
@@ -1130,7 +1132,7 @@ if ($null -eq $_stTask) {
 Read-TaskState %s %s
 `,
 		psQuote(taskName), psQuote(taskPath),
-		strings.ReplaceAll(id, "'", "''"),
+		psEscapeSingleQuoted(id),
 		psQuote(taskName), psQuote(taskPath),
 		psQuote(taskName), psQuote(taskPath),
 	)
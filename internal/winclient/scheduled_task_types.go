@@ -52,6 +52,9 @@ func (e *ScheduledTaskError) Unwrap() error { return e.Cause }
 
 // Is compares by Kind, enabling errors.Is(err, ErrScheduledTask*) matching.
 func (e *ScheduledTaskError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*ScheduledTaskError)
 	if !ok {
 		return false
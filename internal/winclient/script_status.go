@@ -0,0 +1,89 @@
+// Package winclient: script status reported by the bootstrap.
+//
+// powershell.exe exits 0 when a script ends normally, even if its last
+// native command failed ($LASTEXITCODE != 0) or its last cmdlet wrote a
+// non-terminating error ($? = $false). Such a run used to look successful.
+// The bootstrap therefore writes one status line to stderr after the script:
+//
+//	#winclient-status <LASTEXITCODE> <True|False>
+//
+// run strips the line and, when the script reported a failure and wrote no
+// JSON envelope, returns a FailureCommand *TransportError with the exit code.
+// A script that did write an envelope has already reported the outcome
+// itself (many check $LASTEXITCODE of sc.exe, winget or msiexec and map it
+// to an error kind), so the envelope wins.
+package winclient
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// scriptStatusPrefix starts the status line written by psBootstrap.
+const scriptStatusPrefix = "#winclient-status "
+
+// scriptStatus is the parsed bootstrap status line.
+type scriptStatus struct {
+	// ExitCode is $LASTEXITCODE after the script returned.
+	ExitCode int
+	// Succeeded is $? after the script returned.
+	Succeeded bool
+}
+
+// failed reports whether the script signalled a failure.
+func (s scriptStatus) failed() bool {
+	return s.ExitCode != 0 || !s.Succeeded
+}
+
+// splitScriptStatus removes the last status line from stderr and parses it.
+// ok is false when stderr carries no well-formed status line (the bootstrap
+// did not get that far, e.g. it was interrupted), in which case stderr is
+// returned unchanged.
+func splitScriptStatus(stderr string) (rest string, st scriptStatus, ok bool) {
+	i := strings.LastIndex(stderr, scriptStatusPrefix)
+	if i < 0 || (i > 0 && stderr[i-1] != '\n') {
+		return stderr, scriptStatus{}, false
+	}
+	line, after, _ := strings.Cut(stderr[i+len(scriptStatusPrefix):], "\n")
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return stderr, scriptStatus{}, false
+	}
+	code, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return stderr, scriptStatus{}, false
+	}
+	st = scriptStatus{ExitCode: code, Succeeded: strings.EqualFold(fields[1], "True")}
+	return stderr[:i] + after, st, true
+}
+
+// recordScriptFailure counts a run whose script reported a failure through
+// the status line and returns the error the caller should see. The run
+// itself was already counted by recordRun.
+func (c *Client) recordScriptFailure(st scriptStatus) error {
+	code := st.ExitCode
+	msg := fmt.Sprintf("winclient: powershell script failed with exit code %d", code)
+	if code == 0 {
+		// $? was false without a native exit code: a non-terminating error.
+		code = 1
+		msg = "winclient: powershell script reported an error ($? is False)"
+	}
+	te := &TransportError{Kind: FailureCommand, Err: errors.New(msg), ExitCode: code}
+
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.stats.CommandFailures++
+	c.stats.LastFailureKind, c.stats.LastFailure = te.Kind, te.Error()
+	return te
+}
+
+// withExitCode adds an "exit_code" entry to an error context map when err
+// carries a non-zero exit code, and returns the map.
+func withExitCode(ctxMap map[string]string, err error) map[string]string {
+	if code := CommandExitCode(err); code != 0 {
+		ctxMap["exit_code"] = strconv.Itoa(code)
+	}
+	return ctxMap
+}
@@ -0,0 +1,67 @@
+// Package winclient — unit tests for the bootstrap script status line.
+package winclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBootstrapWritesScriptStatus(t *testing.T) {
+	const encFlag = "-EncodedCommand "
+	cmd := bootstrapCommand(DefaultPowerShellPath)
+	script := decodePowerShell(t, cmd[strings.Index(cmd, encFlag)+len(encFlag):])
+	invoke := strings.Index(script, "[ScriptBlock]::Create")
+	status := strings.Index(script, scriptStatusPrefix+"$LASTEXITCODE $s")
+	if status < invoke || !strings.Contains(script, "[Console]::Error.WriteLine") {
+		t.Errorf("bootstrap must write the status line to stderr after the script:\n%s", script)
+	}
+}
+
+func TestSplitScriptStatus(t *testing.T) {
+	cases := []struct {
+		stderr   string
+		wantRest string
+		want     scriptStatus
+		wantOK   bool
+	}{
+		{"#winclient-status 0 True\r\n", "", scriptStatus{ExitCode: 0, Succeeded: true}, true},
+		{"WARNING: x\n#winclient-status 1060 True\n", "WARNING: x\n", scriptStatus{ExitCode: 1060, Succeeded: true}, true},
+		{"#winclient-status 0 False", "", scriptStatus{ExitCode: 0, Succeeded: false}, true},
+		{"#winclient-status -1 True\n", "", scriptStatus{ExitCode: -1, Succeeded: true}, true},
+		// No status line: the bootstrap was interrupted.
+		{"boom\n", "boom\n", scriptStatus{}, false},
+		// Malformed or not at the start of a line.
+		{"#winclient-status x True\n", "#winclient-status x True\n", scriptStatus{}, false},
+		{"echo #winclient-status 0 True\n", "echo #winclient-status 0 True\n", scriptStatus{}, false},
+	}
+	for _, c := range cases {
+		rest, st, ok := splitScriptStatus(c.stderr)
+		if strings.TrimRight(rest, "\r") != c.wantRest || st != c.want || ok != c.wantOK {
+			t.Errorf("splitScriptStatus(%q) = %q, %+v, %v; want %q, %+v, %v",
+				c.stderr, rest, st, ok, c.wantRest, c.want, c.wantOK)
+		}
+	}
+}
+
+func TestRecordScriptFailure(t *testing.T) {
+	c, err := New(Config{Host: "win01", Username: "u", Password: "p", Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	err = c.recordScriptFailure(scriptStatus{ExitCode: 1060, Succeeded: true})
+	if TransportFailureKind(err) != FailureCommand || CommandExitCode(err) != 1060 ||
+		!strings.Contains(err.Error(), "exit code 1060") {
+		t.Errorf("err = %v (exit code %d)", err, CommandExitCode(err))
+	}
+
+	err = c.recordScriptFailure(scriptStatus{ExitCode: 0, Succeeded: false})
+	if CommandExitCode(err) != 1 || !strings.Contains(err.Error(), "$? is False") {
+		t.Errorf("err = %v (exit code %d)", err, CommandExitCode(err))
+	}
+
+	if st := c.ConnectionStats(); st.CommandFailures != 2 || st.Runs != 0 || st.LastFailureKind != FailureCommand {
+		t.Errorf("stats = %+v", st)
+	}
+}
@@ -0,0 +1,404 @@
+// Package winclient: local security policy ([System Access]) over WinRM.
+//
+// SecurityPolicyClient is the concrete WindowsSecurityPolicyClient backing the
+// windows_local_security_policy resource. Windows has no cmdlet for the
+// account policies, so both directions go through secedit.exe:
+//
+//   - Read runs secedit /export /areas SECURITYPOLICY and returns the INF text,
+//     which parseSecurityPolicyINF parses here, where it can be unit tested.
+//   - Set writes a template holding only the managed keys and applies it with
+//     secedit /configure against a fresh, throw-away database. secedit only
+//     touches what the template defines, so every setting the configuration
+//     leaves out, and every other section of the policy, stays as it is. The
+//     exported INF is never re-imported: doing so would rewrite every setting
+//     it lists, including ones changed concurrently by someone else.
+//
+// Windows requires "reset account lockout counter after" (ResetLockoutCount)
+// not to exceed the lockout duration. When a shorter duration is written, the
+// script lowers ResetLockoutCount to the new duration in the same template;
+// otherwise secedit rejects the duration.
+//
+// The template, database and log live in a %TEMP% staging directory that the
+// script removes on every exit path, or that temp_artifacts.go removes when
+// the script never got there.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Compile-time assertion: SecurityPolicyClient satisfies
+// WindowsSecurityPolicyClient.
+var _ WindowsSecurityPolicyClient = (*SecurityPolicyClient)(nil)
+
+// SecurityPolicyClient is the PowerShell/WinRM-backed
+// WindowsSecurityPolicyClient.
+type SecurityPolicyClient struct {
+	c *Client
+}
+
+// NewSecurityPolicyClient wraps the given WinRM Client.
+func NewSecurityPolicyClient(c *Client) *SecurityPolicyClient { return &SecurityPolicyClient{c: c} }
+
+// runSecurityPolicyPowerShell is the package-level indirection used by
+// SecurityPolicyClient. Tests may override it; production code must not.
+var runSecurityPolicyPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// secpolStagingRoot is the %TEMP%-relative parent of the per-run staging
+// directories.
+const secpolStagingRoot = "windows_local_security_policy"
+
+// INF key names in the [System Access] section.
+const (
+	secpolKeyMinPasswordLength = "MinimumPasswordLength"
+	secpolKeyPasswordHistory   = "PasswordHistorySize"
+	secpolKeyLockoutBadCount   = "LockoutBadCount"
+	secpolKeyLockoutDuration   = "LockoutDuration"
+)
+
+// securityPolicyPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type securityPolicyPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// securityPolicyPayload is the "data" object of both scripts.
+type securityPolicyPayload struct {
+	// INF is the secedit /export output.
+	INF string `json:"inf"`
+	// ExitCode and Log are the secedit /configure exit code and log (Set
+	// only).
+	ExitCode int    `json:"exit_code"`
+	Log      string `json:"log"`
+}
+
+// psSecurityPolicyHeader prepends Emit-OK/Emit-Err, Classify-Secpol and
+// Export-Secpol.
+const psSecurityPolicyHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+$WarningPreference     = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 8 -Compress))
+}
+function Classify-Secpol([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'Access denied' -or $Msg -match 'UnauthorizedAccess') { return 'permission_denied' }
+  return 'unknown'
+}
+function Export-Secpol([string]$Dir) {
+  $cfg = Join-Path $Dir 'export.inf'
+  Remove-Item -LiteralPath $cfg -Force -ErrorAction SilentlyContinue
+  $out = & secedit.exe /export /cfg $cfg /areas SECURITYPOLICY /quiet 2>&1 | Out-String
+  if ($LASTEXITCODE -ne 0 -or -not (Test-Path -LiteralPath $cfg)) {
+    throw ("secedit /export failed (exit " + $LASTEXITCODE + "): " + $out.Trim())
+  }
+  return (Get-Content -LiteralPath $cfg -Raw -Encoding Unicode)
+}
+`
+
+// psSetSecurityPolicy applies the template. %[1]s staging dir (quoted,
+// %TEMP%-relative), %[2]s [System Access] lines (PS array), %[3]s new
+// lockout duration in minutes or $null.
+const psSetSecurityPolicy = `
+$dir = Join-Path $env:TEMP %[1]s
+try {
+  New-Item -ItemType Directory -Path $dir -Force | Out-Null
+  $lines = @(%[2]s)
+  $duration = %[3]s
+  if ($null -ne $duration -and $duration -gt 0) {
+    $current = Export-Secpol $dir
+    if ($current -match '(?m)^\s*ResetLockoutCount\s*=\s*(\d+)' -and [int64]$Matches[1] -gt $duration) {
+      $lines += ('ResetLockoutCount = ' + $duration)
+    }
+  }
+  $cfg = Join-Path $dir 'apply.inf'
+  $db  = Join-Path $dir 'apply.sdb'
+  $log = Join-Path $dir 'apply.log'
+  $inf = @('[Unicode]', 'Unicode=yes', '[Version]', 'signature="$CHICAGO$"', 'Revision=1', '[System Access]') + $lines
+  Set-Content -LiteralPath $cfg -Value $inf -Encoding Unicode
+  $out = & secedit.exe /configure /db $db /cfg $cfg /areas SECURITYPOLICY /quiet /log $log 2>&1 | Out-String
+  $code = $LASTEXITCODE
+  $logText = ''
+  if (Test-Path -LiteralPath $log) { $logText = Get-Content -LiteralPath $log -Raw }
+  if (-not $logText) { $logText = $out }
+  Emit-OK @{ inf = (Export-Secpol $dir); exit_code = $code; log = $logText }
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Secpol $msg) $msg @{}
+} finally {
+  Remove-Item -LiteralPath $dir -Recurse -Force -ErrorAction SilentlyContinue
+}
+`
+
+// psReadSecurityPolicy exports the policy. %[1]s staging dir.
+const psReadSecurityPolicy = `
+$dir = Join-Path $env:TEMP %[1]s
+try {
+  New-Item -ItemType Directory -Path $dir -Force | Out-Null
+  Emit-OK @{ inf = (Export-Secpol $dir) }
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-Secpol $msg) $msg @{}
+} finally {
+  Remove-Item -LiteralPath $dir -Recurse -Force -ErrorAction SilentlyContinue
+}
+`
+
+// runSecurityPolicyEnvelope executes script (prepended with
+// psSecurityPolicyHeader) and parses the JSON envelope. Cancellation maps to
+// SecurityPolicyErrorTimeout; other transport failures to
+// SecurityPolicyErrorUnknown. A nil response with an error means the script
+// never reported back, so its finally block cannot be trusted to have run.
+func (s *SecurityPolicyClient) runSecurityPolicyEnvelope(ctx context.Context, op, script string) (*securityPolicyPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	s.c.sweepTempArtifacts(ctx)
+	full := psSecurityPolicyHeader + "\n" + script
+	stdout, stderr, err := runSecurityPolicyPowerShell(ctx, s.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      s.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewSecurityPolicyError(SecurityPolicyErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp securityPolicyPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewSecurityPolicyError(mapSecurityPolicyKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapSecurityPolicyKind translates a PS-side "kind" string to a typed
+// SecurityPolicyErrorKind. Unknown values fall through to
+// SecurityPolicyErrorUnknown.
+func mapSecurityPolicyKind(k string) SecurityPolicyErrorKind {
+	switch k {
+	case string(SecurityPolicyErrorInvalidParameter),
+		string(SecurityPolicyErrorNotApplied),
+		string(SecurityPolicyErrorPermission),
+		string(SecurityPolicyErrorTimeout):
+		return SecurityPolicyErrorKind(k)
+	default:
+		return SecurityPolicyErrorUnknown
+	}
+}
+
+// securityPolicySetting ties an input field to its INF key and range.
+type securityPolicySetting struct {
+	key      string
+	value    *int64
+	min, max int64
+}
+
+// securityPolicySettings lists the managed settings of in, in template
+// order.
+func securityPolicySettings(in SecurityPolicyInput) []securityPolicySetting {
+	return []securityPolicySetting{
+		{secpolKeyMinPasswordLength, in.MinimumPasswordLength, 0, 128},
+		{secpolKeyPasswordHistory, in.PasswordHistorySize, 0, 24},
+		{secpolKeyLockoutBadCount, in.LockoutThreshold, 0, 999},
+		{secpolKeyLockoutDuration, in.LockoutDurationMinutes, -1, 99999},
+	}
+}
+
+// securityPolicyLines validates in and renders the [System Access] lines of
+// its non-nil settings. It returns an empty message when in is valid.
+func securityPolicyLines(in SecurityPolicyInput) ([]string, string) {
+	var lines []string
+	for _, s := range securityPolicySettings(in) {
+		if s.value == nil {
+			continue
+		}
+		v := *s.value
+		if v < s.min || v > s.max {
+			return nil, fmt.Sprintf("%s must be between %d and %d, got %d", s.key, s.min, s.max, v)
+		}
+		if s.key == secpolKeyLockoutDuration && v == 0 {
+			return nil, fmt.Sprintf("%s must be -1 (until an administrator unlocks) or at least 1 minute, got 0", s.key)
+		}
+		lines = append(lines, s.key+" = "+strconv.FormatInt(v, 10))
+	}
+	return lines, ""
+}
+
+var secpolLineRe = regexp.MustCompile(`^\s*([A-Za-z]+)\s*=\s*(-?\d+)\s*$`)
+
+// parseSecurityPolicyINF reads the managed keys from the [System Access]
+// section of a secedit export. Keys absent from the export stay nil.
+func parseSecurityPolicyINF(inf string) *SecurityPolicyState {
+	st := &SecurityPolicyState{}
+	fields := map[string]**int64{
+		secpolKeyMinPasswordLength: &st.MinimumPasswordLength,
+		secpolKeyPasswordHistory:   &st.PasswordHistorySize,
+		secpolKeyLockoutBadCount:   &st.LockoutThreshold,
+		secpolKeyLockoutDuration:   &st.LockoutDurationMinutes,
+	}
+	section := ""
+	for _, line := range strings.Split(strings.TrimPrefix(inf, "\ufeff"), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line
+			continue
+		}
+		if !strings.EqualFold(section, "[System Access]") {
+			continue
+		}
+		m := secpolLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		for key, p := range fields {
+			if strings.EqualFold(m[1], key) {
+				v, err := strconv.ParseInt(m[2], 10, 64)
+				if err == nil {
+					*p = &v
+				}
+			}
+		}
+	}
+	return st
+}
+
+// securityPolicyMismatches lists the managed settings of in that st does not
+// report with the requested value.
+func securityPolicyMismatches(in SecurityPolicyInput, st *SecurityPolicyState) []string {
+	got := securityPolicySettings(SecurityPolicyInput(*st))
+	var out []string
+	for i, s := range securityPolicySettings(in) {
+		if s.value == nil {
+			continue
+		}
+		if g := got[i].value; g == nil || *g != *s.value {
+			have := "absent"
+			if g != nil {
+				have = strconv.FormatInt(*g, 10)
+			}
+			out = append(out, fmt.Sprintf("%s = %d (reads back %s)", s.key, *s.value, have))
+		}
+	}
+	return out
+}
+
+// parseSecurityPolicyPayload decodes the "data" object of an envelope.
+func (s *SecurityPolicyClient) parseSecurityPolicyPayload(op string, resp *securityPolicyPSResponse) (*securityPolicyPayload, error) {
+	var pl securityPolicyPayload
+	if jerr := json.Unmarshal(resp.Data, &pl); jerr != nil {
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorUnknown,
+			"failed to parse security policy export", jerr,
+			map[string]string{"host": s.c.cfg.Host, "operation": op})
+	}
+	return &pl, nil
+}
+
+// Set writes the managed settings of in and returns the policy read back.
+//
+// secedit /configure exits 3 when it applied the template with warnings;
+// that is accepted as long as every managed setting reads back as written.
+func (s *SecurityPolicyClient) Set(ctx context.Context, in SecurityPolicyInput) (*SecurityPolicyState, error) {
+	lines, msg := securityPolicyLines(in)
+	if msg != "" {
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorInvalidParameter, msg, nil,
+			map[string]string{"host": s.c.cfg.Host})
+	}
+	if len(lines) == 0 {
+		return s.Read(ctx)
+	}
+	duration := "$null"
+	if in.LockoutDurationMinutes != nil {
+		duration = strconv.FormatInt(*in.LockoutDurationMinutes, 10)
+	}
+	staging := secpolStagingRoot + `\set_` + newTempToken()
+	resp, err := s.runSecurityPolicyEnvelope(ctx, "set",
+		fmt.Sprintf(psSetSecurityPolicy, psQuote(staging), psQuoteList(lines), duration))
+	if err != nil {
+		if resp == nil {
+			s.c.cleanupTempArtifact(ctx, staging)
+		}
+		return nil, err
+	}
+	pl, err := s.parseSecurityPolicyPayload("set", resp)
+	if err != nil {
+		return nil, err
+	}
+	st := parseSecurityPolicyINF(pl.INF)
+	errCtx := map[string]string{"host": s.c.cfg.Host, "exit_code": strconv.Itoa(pl.ExitCode)}
+	if pl.ExitCode != 0 && pl.ExitCode != 3 {
+		errCtx["log"] = truncate(pl.Log, 2048)
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorUnknown,
+			fmt.Sprintf("secedit /configure failed with exit code %d", pl.ExitCode), nil, errCtx)
+	}
+	if bad := securityPolicyMismatches(in, st); len(bad) > 0 {
+		errCtx["log"] = truncate(pl.Log, 2048)
+		return nil, NewSecurityPolicyError(SecurityPolicyErrorNotApplied,
+			"secedit applied the policy but these settings did not take effect: "+strings.Join(bad, ", ")+
+				". A domain Group Policy defining them overrides the local policy.", nil, errCtx)
+	}
+	return st, nil
+}
+
+// Read exports and returns the current policy.
+func (s *SecurityPolicyClient) Read(ctx context.Context) (*SecurityPolicyState, error) {
+	staging := secpolStagingRoot + `\read_` + newTempToken()
+	resp, err := s.runSecurityPolicyEnvelope(ctx, "read", fmt.Sprintf(psReadSecurityPolicy, psQuote(staging)))
+	if err != nil {
+		if resp == nil {
+			s.c.cleanupTempArtifact(ctx, staging)
+		}
+		return nil, err
+	}
+	pl, err := s.parseSecurityPolicyPayload("read", resp)
+	if err != nil {
+		return nil, err
+	}
+	return parseSecurityPolicyINF(pl.INF), nil
+}
@@ -0,0 +1,275 @@
+// Package winclient — unit tests for SecurityPolicyClient.
+//
+// These tests stub the package-level seam runSecurityPolicyPowerShell to
+// inject scripted stdout/stderr/err triples and to capture the generated
+// script.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSecurityPolicyTestClient(t *testing.T) *SecurityPolicyClient {
+	t.Helper()
+	c, err := New(Config{
+		Host:     "win01",
+		Username: "u",
+		Password: "p",
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return NewSecurityPolicyClient(c)
+}
+
+// stubSecurityPolicyRun replaces runSecurityPolicyPowerShell for the duration
+// of a test and returns a restore function (typically deferred).
+func stubSecurityPolicyRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runSecurityPolicyPowerShell
+	runSecurityPolicyPowerShell = fn
+	return func() { runSecurityPolicyPowerShell = prev }
+}
+
+func securityPolicyOK(t *testing.T, data any) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"ok": true, "data": data})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b) + "\n"
+}
+
+func i64(v int64) *int64 { return &v }
+
+// secpolExport is a trimmed secedit /export of a host with a 12-character
+// minimum, 5 remembered passwords and lockout after 10 failures for 30
+// minutes.
+const secpolExport = "\ufeff[Unicode]\r\n" +
+	"Unicode=yes\r\n" +
+	"[System Access]\r\n" +
+	"MinimumPasswordAge = 0\r\n" +
+	"MaximumPasswordAge = 42\r\n" +
+	"MinimumPasswordLength = 12\r\n" +
+	"PasswordComplexity = 1\r\n" +
+	"PasswordHistorySize = 5\r\n" +
+	"LockoutBadCount = 10\r\n" +
+	"ResetLockoutCount = 30\r\n" +
+	"LockoutDuration = 30\r\n" +
+	"NewAdministratorName = \"Administrator\"\r\n" +
+	"[Event Audit]\r\n" +
+	"LockoutBadCount = 99\r\n" +
+	"[Version]\r\n" +
+	"signature=\"$CHICAGO$\"\r\n" +
+	"Revision=1\r\n"
+
+func TestSecurityPolicyError_IsAndHelper(t *testing.T) {
+	err := NewSecurityPolicyError(SecurityPolicyErrorPermission, "denied", errors.New("inner"), nil)
+	if !errors.Is(err, ErrSecurityPolicyPermission) || errors.Is(err, ErrSecurityPolicyUnknown) {
+		t.Error("errors.Is should match on Kind only")
+	}
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Error("permission_denied should be classed ErrAccessDenied")
+	}
+	if !IsSecurityPolicyError(err, SecurityPolicyErrorPermission) {
+		t.Error("IsSecurityPolicyError should match")
+	}
+	if errors.Unwrap(err).Error() != "inner" {
+		t.Error("Unwrap should return cause")
+	}
+}
+
+func TestMapSecurityPolicyKind(t *testing.T) {
+	cases := map[string]SecurityPolicyErrorKind{
+		"invalid_parameter": SecurityPolicyErrorInvalidParameter,
+		"not_applied":       SecurityPolicyErrorNotApplied,
+		"permission_denied": SecurityPolicyErrorPermission,
+		"timeout":           SecurityPolicyErrorTimeout,
+		"bogus":             SecurityPolicyErrorUnknown,
+	}
+	for in, want := range cases {
+		if got := mapSecurityPolicyKind(in); got != want {
+			t.Errorf("mapSecurityPolicyKind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseSecurityPolicyINF(t *testing.T) {
+	want := &SecurityPolicyState{
+		MinimumPasswordLength: i64(12), PasswordHistorySize: i64(5),
+		LockoutThreshold: i64(10), LockoutDurationMinutes: i64(30),
+	}
+	if got := parseSecurityPolicyINF(secpolExport); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Lockout disabled: Windows omits LockoutDuration. Keys outside
+	// [System Access] are ignored.
+	noLockout := "[System Access]\nLockoutBadCount = 0\nMinimumPasswordLength=0\n[Registry Values]\nLockoutDuration = 5\n"
+	got := parseSecurityPolicyINF(noLockout)
+	if got.LockoutThreshold == nil || *got.LockoutThreshold != 0 || got.LockoutDurationMinutes != nil ||
+		got.MinimumPasswordLength == nil || *got.MinimumPasswordLength != 0 || got.PasswordHistorySize != nil {
+		t.Errorf("no lockout: got %+v", got)
+	}
+
+	if got := parseSecurityPolicyINF("[System Access]\nLockoutDuration = -1\n"); got.LockoutDurationMinutes == nil || *got.LockoutDurationMinutes != -1 {
+		t.Errorf("forever: got %+v", got)
+	}
+}
+
+func TestSecurityPolicyLines(t *testing.T) {
+	lines, msg := securityPolicyLines(SecurityPolicyInput{MinimumPasswordLength: i64(14), LockoutDurationMinutes: i64(-1)})
+	if msg != "" || !reflect.DeepEqual(lines, []string{"MinimumPasswordLength = 14", "LockoutDuration = -1"}) {
+		t.Errorf("lines = %q, msg = %q", lines, msg)
+	}
+	if lines, msg := securityPolicyLines(SecurityPolicyInput{}); msg != "" || len(lines) != 0 {
+		t.Errorf("empty input: lines = %q, msg = %q", lines, msg)
+	}
+}
+
+func TestSecurityPolicySet_Validation(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		t.Fatal("no remote call expected")
+		return "", "", nil
+	})()
+	cases := map[string]SecurityPolicyInput{
+		"length over range":   {MinimumPasswordLength: i64(129)},
+		"negative history":    {PasswordHistorySize: i64(-1)},
+		"history over range":  {PasswordHistorySize: i64(25)},
+		"threshold too large": {LockoutThreshold: i64(1000)},
+		"zero duration":       {LockoutDurationMinutes: i64(0)},
+		"duration below -1":   {LockoutDurationMinutes: i64(-2)},
+	}
+	for name, in := range cases {
+		if _, err := sp.Set(context.Background(), in); !IsSecurityPolicyError(err, SecurityPolicyErrorInvalidParameter) {
+			t.Errorf("%s: err = %v, want invalid_parameter", name, err)
+		}
+	}
+}
+
+func TestSecurityPolicySet_AppliesOnlyManagedKeys(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	var script string
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return securityPolicyOK(t, map[string]any{"inf": secpolExport, "exit_code": 0}), "", nil
+	})()
+
+	st, err := sp.Set(context.Background(), SecurityPolicyInput{MinimumPasswordLength: i64(12), LockoutDurationMinutes: i64(30)})
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	for _, want := range []string{
+		"$lines = @(@('MinimumPasswordLength = 12','LockoutDuration = 30'))",
+		"$duration = 30",
+		"secedit.exe /configure /db $db /cfg $cfg /areas SECURITYPOLICY",
+		`$dir = Join-Path $env:TEMP 'windows_local_security_policy\set_`,
+		"Remove-Item -LiteralPath $dir -Recurse",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"PasswordHistorySize =", "LockoutBadCount =", "/overwrite"} {
+		if strings.Contains(script, unwanted) {
+			t.Errorf("script must not contain %q", unwanted)
+		}
+	}
+	if st.PasswordHistorySize == nil || *st.PasswordHistorySize != 5 {
+		t.Errorf("unmanaged settings must still be read back: %+v", st)
+	}
+
+	if _, err := sp.Set(context.Background(), SecurityPolicyInput{PasswordHistorySize: i64(5)}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if !strings.Contains(script, "$duration = $null") {
+		t.Errorf("no lockout duration: fragment=%s", firstContainingLine(script, "$duration ="))
+	}
+}
+
+func TestSecurityPolicySet_ExitCodes(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	in := SecurityPolicyInput{LockoutThreshold: i64(10)}
+	code := 0
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return securityPolicyOK(t, map[string]any{"inf": secpolExport, "exit_code": code, "log": "Task is completed. Warnings."}), "", nil
+	})()
+
+	code = 3
+	if _, err := sp.Set(context.Background(), in); err != nil {
+		t.Errorf("exit 3 with the value applied should succeed: %v", err)
+	}
+	code = 1
+	_, err := sp.Set(context.Background(), in)
+	var se *SecurityPolicyError
+	if !errors.As(err, &se) || se.Kind != SecurityPolicyErrorUnknown || se.Context["exit_code"] != "1" || !strings.Contains(se.Context["log"], "Warnings") {
+		t.Errorf("exit 1: err = %#v", err)
+	}
+}
+
+func TestSecurityPolicySet_NotApplied(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return securityPolicyOK(t, map[string]any{"inf": secpolExport, "exit_code": 0}), "", nil
+	})()
+	_, err := sp.Set(context.Background(), SecurityPolicyInput{MinimumPasswordLength: i64(14), LockoutThreshold: i64(10)})
+	if !IsSecurityPolicyError(err, SecurityPolicyErrorNotApplied) ||
+		!strings.Contains(err.Error(), "MinimumPasswordLength = 14 (reads back 12)") ||
+		strings.Contains(err.Error(), "LockoutBadCount") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSecurityPolicySet_NothingManaged(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	var script string
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return securityPolicyOK(t, map[string]any{"inf": secpolExport}), "", nil
+	})()
+	if _, err := sp.Set(context.Background(), SecurityPolicyInput{}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if strings.Contains(script, "/configure") || !strings.Contains(script, "Export-Secpol $dir") {
+		t.Error("Set without managed settings must only read")
+	}
+}
+
+func TestSecurityPolicyRead_PermissionDenied(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return `{"ok":false,"kind":"permission_denied","message":"Access is denied."}` + "\n", "", nil
+	})()
+	_, err := sp.Read(context.Background())
+	var se *SecurityPolicyError
+	if !errors.As(err, &se) || se.Kind != SecurityPolicyErrorPermission || se.Context["operation"] != "read" {
+		t.Errorf("err = %#v", err)
+	}
+}
+
+func TestSecurityPolicyRun_CancelledCleansStaging(t *testing.T) {
+	sp := newSecurityPolicyTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer stubSecurityPolicyRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.Canceled
+	})()
+	var cleaned string
+	defer stubTempCleanup(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		cleaned = s
+		return `{"failed":[]}`, "", nil
+	})()
+	if _, err := sp.Read(ctx); !IsSecurityPolicyError(err, SecurityPolicyErrorTimeout) {
+		t.Errorf("err = %v, want timeout", err)
+	}
+	if !strings.Contains(cleaned, `'windows_local_security_policy\read_`) {
+		t.Errorf("staging directory not cleaned up: %s", cleaned)
+	}
+}
@@ -0,0 +1,124 @@
+// Package winclient: types for the windows_local_security_policy resource.
+//
+// SecurityPolicyInput carries the curated [System Access] settings the
+// resource manages; every field is optional so settings left out of the
+// configuration are never written. SecurityPolicyErrorKind /
+// SecurityPolicyError follow the same shape as PagefileError so the resource
+// layer can branch with errors.Is.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SecurityPolicyErrorKind categorises errors returned by
+// WindowsSecurityPolicyClient.
+type SecurityPolicyErrorKind string
+
+const (
+	// SecurityPolicyErrorInvalidParameter: a value rejected before anything
+	// ran.
+	SecurityPolicyErrorInvalidParameter SecurityPolicyErrorKind = "invalid_parameter"
+	// SecurityPolicyErrorNotApplied: secedit ran but the exported policy
+	// does not show the requested value, typically because a domain Group
+	// Policy defines the setting and overrides the local policy.
+	SecurityPolicyErrorNotApplied SecurityPolicyErrorKind = "not_applied"
+	SecurityPolicyErrorPermission SecurityPolicyErrorKind = "permission_denied"
+	SecurityPolicyErrorTimeout    SecurityPolicyErrorKind = "timeout"
+	SecurityPolicyErrorUnknown    SecurityPolicyErrorKind = "unknown"
+)
+
+// SecurityPolicyError is the structured error type returned by
+// WindowsSecurityPolicyClient.
+type SecurityPolicyError struct {
+	Kind    SecurityPolicyErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *SecurityPolicyError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_local_security_policy [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_local_security_policy [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *SecurityPolicyError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *SecurityPolicyError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*SecurityPolicyError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewSecurityPolicyError constructs a *SecurityPolicyError.
+func NewSecurityPolicyError(kind SecurityPolicyErrorKind, msg string, cause error, ctx map[string]string) *SecurityPolicyError {
+	return &SecurityPolicyError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsSecurityPolicyError reports whether err is a *SecurityPolicyError of the
+// given kind.
+func IsSecurityPolicyError(err error, kind SecurityPolicyErrorKind) bool {
+	var se *SecurityPolicyError
+	if errors.As(err, &se) {
+		return se.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrSecurityPolicyInvalidParameter = &SecurityPolicyError{Kind: SecurityPolicyErrorInvalidParameter}
+	ErrSecurityPolicyNotApplied       = &SecurityPolicyError{Kind: SecurityPolicyErrorNotApplied}
+	ErrSecurityPolicyPermission       = &SecurityPolicyError{Kind: SecurityPolicyErrorPermission}
+	ErrSecurityPolicyTimeout          = &SecurityPolicyError{Kind: SecurityPolicyErrorTimeout}
+	ErrSecurityPolicyUnknown          = &SecurityPolicyError{Kind: SecurityPolicyErrorUnknown}
+)
+
+// SecurityPolicyInput is the desired local security policy. A nil field is
+// not managed: it is neither written nor compared after the apply.
+type SecurityPolicyInput struct {
+	// MinimumPasswordLength is MinimumPasswordLength (characters).
+	MinimumPasswordLength *int64
+	// PasswordHistorySize is PasswordHistorySize (remembered passwords).
+	PasswordHistorySize *int64
+	// LockoutThreshold is LockoutBadCount (failed logons before lockout,
+	// 0 = never lock out).
+	LockoutThreshold *int64
+	// LockoutDurationMinutes is LockoutDuration; -1 keeps accounts locked
+	// until an administrator unlocks them.
+	LockoutDurationMinutes *int64
+}
+
+// SecurityPolicyState is the local security policy read back with
+// secedit /export. A nil field is absent from the export; Windows omits
+// LockoutDuration while LockoutThreshold is 0.
+type SecurityPolicyState struct {
+	MinimumPasswordLength  *int64
+	PasswordHistorySize    *int64
+	LockoutThreshold       *int64
+	LockoutDurationMinutes *int64
+}
+
+// WindowsSecurityPolicyClient is the contract for the
+// windows_local_security_policy resource.
+type WindowsSecurityPolicyClient interface {
+	// Set writes the non-nil settings of in and returns the policy read back
+	// afterwards. It returns SecurityPolicyErrorNotApplied when a written
+	// setting does not read back with the requested value.
+	Set(ctx context.Context, in SecurityPolicyInput) (*SecurityPolicyState, error)
+
+	// Read exports and returns the current policy.
+	Read(ctx context.Context) (*SecurityPolicyState, error)
+}
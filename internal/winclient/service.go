@@ -13,14 +13,16 @@
 //     IIS WMSvc traces, and any host-side Set-PSDebug/Start-Transcript output
 //     (mirrors the pattern documented in ADR-LU-3 for windows_local_user).
 //   - All other user-supplied strings are interpolated only through psQuote
-//     (single-quoted PowerShell literal where every embedded apostrophe is
-//     doubled, per the PowerShell single-quoted-string escape rule).
+//     (single-quoted PowerShell literal where every embedded apostrophe,
+//     including the typographic U+2018..U+201B forms, is doubled, per the
+//     PowerShell single-quoted-string escape rule).
 //   - The password value is NEVER copied into ServiceError context or logged.
 //   - All scripts are rendered as UTF-16LE / base64 via the underlying Client
 //     (-EncodedCommand); no shell metacharacters ever reach cmd.exe.
 package winclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -49,7 +51,31 @@ func NewServiceClient(c *Client) *ServiceClient { return &ServiceClient{c: c} }
 // embedded single quotes doubled. No PowerShell expansion occurs inside single
 // quotes, which prevents $var, backtick, and subexpression injection.
 func psQuote(s string) string {
-	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	return "'" + psEscapeSingleQuoted(s) + "'"
+}
+
+// psSingleQuotes is the set of characters the PowerShell tokenizer accepts as a
+// single-quote delimiter: the ASCII apostrophe plus the typographic quotes
+// U+2018..U+201B. Escaping only the ASCII one would let a value containing,
+// say, a right single quotation mark close the literal early.
+const psSingleQuotes = "'\u2018\u2019\u201a\u201b"
+
+// psEscapeSingleQuoted doubles every single-quote delimiter in s so it can be
+// embedded verbatim between single quotes (the same rule as PowerShell's
+// CodeGeneration.EscapeSingleQuotedStringContent).
+func psEscapeSingleQuoted(s string) string {
+	if !strings.ContainsAny(s, psSingleQuotes) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 4)
+	for _, r := range s {
+		if strings.ContainsRune(psSingleQuotes, r) {
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
 // psQuoteList renders a []string as a PowerShell string array literal.
@@ -123,6 +149,7 @@ type psResponse struct {
 // context cancellation) are translated into *ServiceError with Kind=unknown or
 // Kind=timeout as appropriate.
 func (s *ServiceClient) runEnvelope(ctx context.Context, op, name, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := psHeader + "\n" + script
 	stdout, stderr, err := runPowerShell(ctx, s.c, full)
 	if err != nil {
@@ -135,11 +162,11 @@ func (s *ServiceClient) runEnvelope(ctx context.Context, op, name, script string
 		}
 		return nil, NewServiceError(ServiceErrorUnknown,
 			fmt.Sprintf("powershell transport error during %q", op),
-			err, map[string]string{
+			err, withExitCode(map[string]string{
 				"operation": op, "name": name, "host": s.c.cfg.Host,
 				"stderr": truncate(stderr, 2048),
 				"stdout": truncate(stdout, 2048),
-			})
+			}, err))
 	}
 
 	line := extractLastJSONLine(stdout)
@@ -181,6 +208,7 @@ func (s *ServiceClient) runEnvelope(ctx context.Context, op, name, script string
 //
 // Transport / parsing semantics are identical to runEnvelope.
 func (s *ServiceClient) runEnvelopeWithInput(ctx context.Context, op, name, script, stdin string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := psHeader + "\n" + script
 	stdout, stderr, err := runPSInput(ctx, s.c, full, stdin)
 	if err != nil {
@@ -193,11 +221,11 @@ func (s *ServiceClient) runEnvelopeWithInput(ctx context.Context, op, name, scri
 		}
 		return nil, NewServiceError(ServiceErrorUnknown,
 			fmt.Sprintf("powershell transport error during %q", op),
-			err, map[string]string{
+			err, withExitCode(map[string]string{
 				"operation": op, "name": name, "host": s.c.cfg.Host,
 				"stderr": truncate(stderr, 2048),
 				"stdout": truncate(stdout, 2048),
-			})
+			}, err))
 	}
 
 	line := extractLastJSONLine(stdout)
@@ -247,6 +275,40 @@ func extractLastJSONLine(stdout string) string {
 	return ""
 }
 
+// normalizeJSONArray returns raw as a JSON array. ConvertTo-Json collapses a
+// one-element collection into the bare element and renders an empty one as
+// null (or nothing at all), so a lone value is wrapped in [] and an empty or
+// null document becomes []. Input that already is an array is returned as is.
+func normalizeJSONArray(raw json.RawMessage) json.RawMessage {
+	trim := bytes.TrimSpace(raw)
+	switch {
+	case len(trim) == 0 || string(trim) == "null":
+		return json.RawMessage("[]")
+	case trim[0] == '[':
+		return trim
+	default:
+		out := make([]byte, 0, len(trim)+2)
+		out = append(out, '[')
+		out = append(out, trim...)
+		return append(out, ']')
+	}
+}
+
+// jsonList is a slice that decodes from either a JSON array or a single
+// collapsed element (see normalizeJSONArray). Use it for payload fields fed
+// by PowerShell collections.
+type jsonList[T any] []T
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *jsonList[T]) UnmarshalJSON(b []byte) error {
+	var out []T
+	if err := json.Unmarshal(normalizeJSONArray(b), &out); err != nil {
+		return err
+	}
+	*l = out
+	return nil
+}
+
 func truncate(s string, max int) string {
 	if len(s) <= max {
 		return s
@@ -264,7 +326,8 @@ func mapKind(k string) ServiceErrorKind {
 		string(ServiceErrorInvalidParameter),
 		string(ServiceErrorRunning),
 		string(ServiceErrorNotRunning),
-		string(ServiceErrorDisabled):
+		string(ServiceErrorDisabled),
+		string(ServiceErrorDependencyFailed):
 		return ServiceErrorKind(k)
 	default:
 		return ServiceErrorUnknown
@@ -296,6 +359,16 @@ function Read-ServiceState([string]$Name) {
   $descRaw  = & sc.exe qdescription $Name 2>&1 | Out-String
   $descCode = $LASTEXITCODE
 
+  # sc.exe qsidtype (non-fatal on failure; '' when unreadable)
+  $sidRaw  = & sc.exe qsidtype $Name 2>&1 | Out-String
+  $sidCode = $LASTEXITCODE
+
+  # sc.exe qfailure (non-fatal on failure; '' when unreadable). Parsed on the
+  # Go side; the buffer size leaves room for a long COMMAND_LINE.
+  $failRaw  = & sc.exe qfailure $Name 8192 2>&1 | Out-String
+  $failure  = ''
+  if ($LASTEXITCODE -eq 0) { $failure = $failRaw }
+
   # Parse qc
   $binary     = ''
   $startType  = 'Automatic'
@@ -338,6 +411,16 @@ function Read-ServiceState([string]$Name) {
     }
   }
 
+  # Parse qsidtype ("SERVICE_SID_TYPE:  UNRESTRICTED")
+  $sidType = ''
+  if ($sidCode -eq 0 -and $sidRaw -match 'SERVICE_SID_TYPE\s*:\s*([A-Z_]+)') {
+    switch ($Matches[1]) {
+      'NONE'         { $sidType = 'None' }
+      'UNRESTRICTED' { $sidType = 'Unrestricted' }
+      'RESTRICTED'   { $sidType = 'Restricted' }
+    }
+  }
+
   # Map Get-Service status enum -> provider values
   $statusName = [string]$svc.Status
   switch ($statusName) {
@@ -360,6 +443,8 @@ function Read-ServiceState([string]$Name) {
     current_status  = $current
     service_account = $account
     dependencies    = @($deps)
+    sid_type        = $sidType
+    failure_actions = $failure
     hostname        = $env:COMPUTERNAME
   }
 }
@@ -375,12 +460,58 @@ type stateData struct {
 	CurrentStatus  string   `json:"current_status"`
 	ServiceAccount string   `json:"service_account"`
 	Dependencies   []string `json:"dependencies"`
+	SidType        string   `json:"sid_type"`
+	FailureActions string   `json:"failure_actions"`
 	Hostname       string   `json:"hostname"`
 }
 
 // quoteOuterRe strips symmetric outer double-quotes from binary paths (EC-14).
 var quoteOuterRe = regexp.MustCompile(`^"(.*)"$`)
 
+// serviceCommandLine builds the SCM command line from an executable path and
+// its arguments. The executable is always double-quoted (unless the caller
+// already quoted it) so a path containing spaces is neither split at the
+// first space nor exposed to the unquoted-service-path hijack.
+func serviceCommandLine(exe, args string) string {
+	exe = strings.TrimSpace(exe)
+	if !strings.HasPrefix(exe, `"`) {
+		exe = `"` + exe + `"`
+	}
+	if args = strings.TrimSpace(args); args != "" {
+		return exe + " " + args
+	}
+	return exe
+}
+
+// splitServiceCommandLine splits a raw BINARY_PATH_NAME into the executable
+// path (without quotes) and the argument string. A quoted executable ends at
+// the closing quote. An unquoted one ends after the first ".exe" followed by
+// whitespace or end of string; without such a token the whole value is
+// treated as the executable, because an unquoted path may itself contain
+// spaces.
+func splitServiceCommandLine(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, `"`) {
+		end := strings.Index(raw[1:], `"`)
+		if end < 0 {
+			return raw[1:], ""
+		}
+		return raw[1 : end+1], strings.TrimSpace(raw[end+2:])
+	}
+	lower := strings.ToLower(raw)
+	for from := 0; ; {
+		i := strings.Index(lower[from:], ".exe")
+		if i < 0 {
+			return raw, ""
+		}
+		end := from + i + len(".exe")
+		if end == len(raw) || raw[end] == ' ' || raw[end] == '\t' {
+			return raw[:end], strings.TrimSpace(raw[end:])
+		}
+		from = end
+	}
+}
+
 // normaliseState applies EC-14 (outer quote strip) and SS10 (service_account
 // normalisation) on a raw stateData, producing a canonical *ServiceState.
 func normaliseState(d *stateData) *ServiceState {
@@ -402,16 +533,32 @@ func normaliseState(d *stateData) *ServiceState {
 	if deps == nil {
 		deps = []string{}
 	}
+	exe, args := splitServiceCommandLine(d.BinaryPath)
 	return &ServiceState{
 		Name:           d.Name,
 		DisplayName:    d.DisplayName,
 		Description:    d.Description,
 		BinaryPath:     bin,
+		Executable:     exe,
+		Arguments:      args,
 		StartType:      d.StartType,
 		CurrentStatus:  d.CurrentStatus,
 		ServiceAccount: account,
 		Dependencies:   deps,
+		SidType:        d.SidType,
+		Recovery:       parseServiceFailureOutput(d.FailureActions),
+	}
+}
+
+// validateSidType rejects a SidType other than "" (unchanged) or one of the
+// values accepted by sc.exe sidtype.
+func validateSidType(t string) error {
+	switch t {
+	case "", "None", "Unrestricted", "Restricted":
+		return nil
 	}
+	return NewServiceError(ServiceErrorInvalidParameter,
+		fmt.Sprintf("SidType %q must be one of None, Unrestricted, Restricted", t), nil, nil)
 }
 
 // -----------------------------------------------------------------------------
@@ -427,6 +574,12 @@ func (s *ServiceClient) Create(ctx context.Context, input ServiceInput) (*Servic
 	if input.BinaryPath == "" {
 		return nil, NewServiceError(ServiceErrorInvalidParameter, "BinaryPath is required", nil, nil)
 	}
+	if err := validateSidType(input.SidType); err != nil {
+		return nil, err
+	}
+	if err := validateServiceRecovery(input.Recovery); err != nil {
+		return nil, err
+	}
 
 	startType := input.StartType
 	if startType == "" {
@@ -444,10 +597,15 @@ func (s *ServiceClient) Create(ctx context.Context, input ServiceInput) (*Servic
 		display = input.Name
 	}
 
-	script := psReadStateBody + `
+	binary := input.BinaryPath
+	if input.Arguments != nil {
+		binary = serviceCommandLine(input.BinaryPath, *input.Arguments)
+	}
+
+	script := psReadStateBody + psInvokeScRaw + `
 try {
   $name    = ` + psQuote(input.Name) + `
-  $binary  = ` + psQuote(input.BinaryPath) + `
+  $binary  = ` + psQuote(binary) + `
   $display = ` + psQuote(display) + `
   $desc    = ` + psQuote(input.Description) + `
   $stype   = ` + psQuote(newSvcStart) + `
@@ -457,6 +615,8 @@ try {
   $password = [Console]::In.ReadLine()
   if ($null -eq $password) { $password = '' }
   $deps     = ` + psQuoteList(input.Dependencies) + `
+  $sidType  = ` + psQuote(strings.ToLower(input.SidType)) + `
+  $failArgs = ` + psQuote(serviceFailureArgs(input.Name, input.Recovery)) + `
 
   # EC-1 pre-existence check
   $existing = Get-Service -Name $name -ErrorAction SilentlyContinue
@@ -493,6 +653,16 @@ try {
     if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe depend= failed: " + $out.Trim()) @{}; return }
   }
 
+  if ($sidType) {
+    $out = & sc.exe sidtype $name $sidType 2>&1 | Out-String
+    if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe sidtype failed: " + $out.Trim()) @{}; return }
+  }
+
+  if ($failArgs) {
+    $r = Invoke-ScRaw $failArgs
+    if ($r.code -ne 0) { Emit-Err (Classify $r.out) ("sc.exe failure failed: " + $r.out.Trim()) @{}; return }
+  }
+
   $st = Read-ServiceState $name
   if (-not $st) { Emit-Err 'unknown' "service disappeared after create" @{}; return }
   Emit-OK $st
@@ -518,7 +688,7 @@ try {
 
 	// Reconcile runtime state if DesiredStatus set.
 	if input.DesiredStatus != "" {
-		if err := s.reconcileStatus(ctx, input.Name, input.DesiredStatus); err != nil {
+		if err := s.reconcileStatus(ctx, input.Name, input.DesiredStatus, input.StateTimeout); err != nil {
 			return state, err
 		}
 		// Re-read to capture new current_status.
@@ -535,6 +705,7 @@ try {
 // -----------------------------------------------------------------------------
 
 // Read returns the current state. (nil, nil) if the service does not exist.
+// Transient failures are retried as configured by WithReadRetries.
 func (s *ServiceClient) Read(ctx context.Context, name string) (*ServiceState, error) {
 	if name == "" {
 		return nil, NewServiceError(ServiceErrorInvalidParameter, "name is required", nil, nil)
@@ -551,7 +722,9 @@ try {
   Emit-Err $kind $msg @{}
 }
 `
-	resp, err := s.runEnvelope(ctx, "Read", name, script)
+	resp, err := retryTransientRead(ctx, func() (*psResponse, error) {
+		return s.runEnvelope(ctx, "Read", name, script)
+	})
 	if err != nil {
 		if errors.Is(err, ErrServiceNotFound) {
 			return nil, nil
@@ -573,11 +746,18 @@ try {
 // -----------------------------------------------------------------------------
 
 // Update applies in-place configuration changes. BinaryPath is ForceNew and
-// must not be mutated here; any non-empty input.BinaryPath is ignored.
+// is only used here to rebuild the command line when input.Arguments is
+// non-nil; otherwise it is ignored.
 func (s *ServiceClient) Update(ctx context.Context, name string, input ServiceInput) (*ServiceState, error) {
 	if name == "" {
 		return nil, NewServiceError(ServiceErrorInvalidParameter, "name is required", nil, nil)
 	}
+	if err := validateSidType(input.SidType); err != nil {
+		return nil, err
+	}
+	if err := validateServiceRecovery(input.Recovery); err != nil {
+		return nil, err
+	}
 	startType := input.StartType
 	if startType == "" {
 		startType = "Automatic"
@@ -600,7 +780,18 @@ func (s *ServiceClient) Update(ctx context.Context, name string, input ServiceIn
 		}
 	}
 
-	script := psReadStateBody + `
+	// Arguments == nil means "do not touch the command line".
+	cmdMode := "skip"
+	cmdLine := ""
+	if input.Arguments != nil {
+		if input.BinaryPath == "" {
+			return nil, NewServiceError(ServiceErrorInvalidParameter, "BinaryPath is required when Arguments is set", nil, nil)
+		}
+		cmdMode = "set"
+		cmdLine = serviceCommandLine(input.BinaryPath, *input.Arguments)
+	}
+
+	script := psReadStateBody + psInvokeScRaw + `
 try {
   $name     = ` + psQuote(name) + `
   $display  = ` + psQuote(input.DisplayName) + `
@@ -613,13 +804,35 @@ try {
   if ($null -eq $password) { $password = '' }
   $depsMode = ` + psQuote(depsMode) + `
   $depArg   = ` + psQuote(depArg) + `
+  $cmdMode  = ` + psQuote(cmdMode) + `
+  $cmdLine  = ` + psQuote(cmdLine) + `
+  $sidType  = ` + psQuote(strings.ToLower(input.SidType)) + `
+  $failArgs = ` + psQuote(serviceFailureArgs(name, input.Recovery)) + `
 
   $existing = Get-Service -Name $name -ErrorAction SilentlyContinue
   if (-not $existing) { Emit-Err 'not_found' "service '$name' does not exist" @{}; return }
 
+  # Command line via Win32_Service.Change (ChangeServiceConfig): sc.exe
+  # binPath= would have its embedded quotes mangled by native argument
+  # passing, and a direct ImagePath registry write is not seen by the SCM
+  # until reboot.
+  if ($cmdMode -eq 'set') {
+    $wqlName = $name.Replace('\', '\\').Replace("'", "\'")
+    $cim = Get-CimInstance -ClassName Win32_Service -Filter ("Name='" + $wqlName + "'")
+    if ($cim.PathName -ne $cmdLine) {
+      $r = Invoke-CimMethod -InputObject $cim -MethodName Change -Arguments @{ PathName = $cmdLine }
+      if ($r.ReturnValue -ne 0) {
+        $kind = 'unknown'
+        if ($r.ReturnValue -eq 2) { $kind = 'permission_denied' }
+        Emit-Err $kind ("Win32_Service.Change(PathName) failed with return value " + $r.ReturnValue) @{}
+        return
+      }
+    }
+  }
+
   # Set-Service: display_name, description, start_type, credential
   $setArgs = @{ Name = $name; StartupType = $stype }
-  if ($display) { $setArgs['DisplayName'] = $display }
+  if ($display -and $existing.DisplayName -cne $display) { $setArgs['DisplayName'] = $display }
   if ($desc -ne $null) { $setArgs['Description'] = $desc }
   if ($account -and $password) {
     $sec  = ConvertTo-SecureString $password -AsPlainText -Force
@@ -637,6 +850,14 @@ try {
   if ($finalStart -eq 'AutomaticDelayedStart') {
     $out = & sc.exe config $name start= delayed-auto 2>&1 | Out-String
     if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe delayed-auto failed: " + $out.Trim()) @{}; return }
+  } elseif ($finalStart -eq 'Automatic') {
+    # Set-Service -StartupType Automatic leaves the DelayedAutostart flag
+    # set, so a delayed service would still read back as delayed.
+    $delayed = (Get-ItemProperty -Path ("HKLM:\SYSTEM\CurrentControlSet\Services\" + $name) -Name DelayedAutostart -ErrorAction SilentlyContinue).DelayedAutostart
+    if ($delayed -eq 1) {
+      $out = & sc.exe config $name start= auto 2>&1 | Out-String
+      if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe start= auto failed: " + $out.Trim()) @{}; return }
+    }
   }
 
   if ($depsMode -ne 'skip') {
@@ -644,6 +865,16 @@ try {
     if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe depend= failed: " + $out.Trim()) @{}; return }
   }
 
+  if ($sidType) {
+    $out = & sc.exe sidtype $name $sidType 2>&1 | Out-String
+    if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe sidtype failed: " + $out.Trim()) @{}; return }
+  }
+
+  if ($failArgs) {
+    $r = Invoke-ScRaw $failArgs
+    if ($r.code -ne 0) { Emit-Err (Classify $r.out) ("sc.exe failure failed: " + $r.out.Trim()) @{}; return }
+  }
+
   $st = Read-ServiceState $name
   if (-not $st) { Emit-Err 'not_found' "service disappeared after update" @{}; return }
   Emit-OK $st
@@ -666,7 +897,7 @@ try {
 	state := normaliseState(&d)
 
 	if input.DesiredStatus != "" {
-		if err := s.reconcileStatus(ctx, name, input.DesiredStatus); err != nil {
+		if err := s.reconcileStatus(ctx, name, input.DesiredStatus, input.StateTimeout); err != nil {
 			return state, err
 		}
 		if ns, rerr := s.Read(ctx, name); rerr == nil && ns != nil {
@@ -680,15 +911,15 @@ try {
 // Delete (SS11: Stop → WaitForStatus → Remove)
 // -----------------------------------------------------------------------------
 
-// Delete stops and removes the service. Win32 1060 (not found) is success.
+// Delete stops and removes the service. Services that depend on it are
+// stopped first, one at a time, so a dependent that will not stop is named
+// in a dependency_failed error instead of failing the whole stop. Win32 1060
+// (not found) is success.
 func (s *ServiceClient) Delete(ctx context.Context, name string) error {
 	if name == "" {
 		return NewServiceError(ServiceErrorInvalidParameter, "name is required", nil, nil)
 	}
-	waitSec := int(s.c.cfg.Timeout / time.Second)
-	if waitSec < 10 {
-		waitSec = 30
-	}
+	waitSec := s.stateWaitSeconds(0)
 
 	script := `
 try {
@@ -699,6 +930,20 @@ try {
   if (-not $svc) { Emit-OK @{ deleted = $true; already_absent = $true }; return }
 
   if ($svc.Status -eq 'Running' -or $svc.Status -eq 'Paused') {
+    # DependentServices lists direct and indirect dependents in stop order.
+    foreach ($dep in @($svc.DependentServices)) {
+      $dep.Refresh()
+      if ($dep.Status -eq 'Stopped') { continue }
+      try {
+        if ($dep.Status -ne 'StopPending') { Stop-Service -InputObject $dep -Force -ErrorAction Stop }
+        $dep.WaitForStatus('Stopped', [TimeSpan]::FromSeconds($waitSec))
+      } catch {
+        $dep.Refresh()
+        $c = @{ dependent = $dep.Name; dependent_status = [string]$dep.Status }
+        Emit-Err 'dependency_failed' ("dependent service '" + $dep.Name + "' of service '$name' did not stop within $waitSec s: " + $_.Exception.Message) $c
+        return
+      }
+    }
     try { Stop-Service -Name $name -Force -ErrorAction Stop } catch {
       $m = $_.Exception.Message
       if ($m -notmatch '1062') { Emit-Err (Classify $m) $m @{}; return }
@@ -746,9 +991,38 @@ try {
 // StartService / StopService / PauseService
 // -----------------------------------------------------------------------------
 
-// StartService starts the named service.
+// StartService starts the named service. Services it depends on
+// (RequiredServices) that are not Running are started first, so a start on
+// create does not race a dependency that is still coming up.
 func (s *ServiceClient) StartService(ctx context.Context, name string) error {
-	return s.runStateOp(ctx, "Start", name, `
+	return s.startService(ctx, name, 0)
+}
+
+// startService is StartService with an explicit wait; 0 uses the default.
+func (s *ServiceClient) startService(ctx context.Context, name string, wait time.Duration) error {
+	return s.runStateOp(ctx, "Start", name, wait, `
+  $svc = Get-Service -Name $name -ErrorAction SilentlyContinue
+  $depCtx = @{}
+  if ($svc) {
+    foreach ($dep in @($svc.ServicesDependedOn)) {
+      $dep.Refresh()
+      if ($dep.Status -eq 'Running') { continue }
+      $c = @{ dependency = $dep.Name; dependency_status = [string]$dep.Status; dependency_start_type = [string]$dep.StartType }
+      if ([string]$dep.StartType -eq 'Disabled') {
+        Emit-Err 'dependency_failed' ("dependency '" + $dep.Name + "' of service '$name' is Disabled and cannot be started") $c
+        return
+      }
+      try {
+        if ($dep.Status -ne 'StartPending') { Start-Service -InputObject $dep -ErrorAction Stop }
+        $dep.WaitForStatus('Running', [TimeSpan]::FromSeconds($waitSec))
+      } catch {
+        $dep.Refresh()
+        $c.dependency_status = [string]$dep.Status
+        Emit-Err 'dependency_failed' ("dependency '" + $dep.Name + "' of service '$name' did not reach Running within $waitSec s: " + $_.Exception.Message) $c
+        return
+      }
+    }
+  }
   try {
     Start-Service -Name $name -ErrorAction Stop
     $svc = Get-Service -Name $name
@@ -758,15 +1032,24 @@ func (s *ServiceClient) StartService(ctx context.Context, name string) error {
     $m = $_.Exception.Message
     if ($m -match '1056') { Emit-Err 'already_running' $m @{}; return }
     if ($m -match '1058') { Emit-Err 'disabled' $m @{}; return }
+    if ($svc) {
+      $d = @($svc.ServicesDependedOn | ForEach-Object { $_.Refresh(); $_.Name + '=' + $_.Status }) -join ', '
+      if ($d) { $depCtx.dependencies = $d }
+    }
     $k = Classify $m
     if ($k -eq 'unknown' -and $m -match 'time') { $k = 'timeout' }
-    Emit-Err $k $m @{}
+    Emit-Err $k $m $depCtx
   }`)
 }
 
 // StopService stops the named service (cascades to dependents).
 func (s *ServiceClient) StopService(ctx context.Context, name string) error {
-	return s.runStateOp(ctx, "Stop", name, `
+	return s.stopService(ctx, name, 0)
+}
+
+// stopService is StopService with an explicit wait; 0 uses the default.
+func (s *ServiceClient) stopService(ctx context.Context, name string, wait time.Duration) error {
+	return s.runStateOp(ctx, "Stop", name, wait, `
   try {
     Stop-Service -Name $name -Force -ErrorAction Stop
     $svc = Get-Service -Name $name
@@ -783,7 +1066,12 @@ func (s *ServiceClient) StopService(ctx context.Context, name string) error {
 
 // PauseService suspends the named service. EC-13: verifies CanPauseAndContinue.
 func (s *ServiceClient) PauseService(ctx context.Context, name string) error {
-	return s.runStateOp(ctx, "Pause", name, `
+	return s.pauseService(ctx, name, 0)
+}
+
+// pauseService is PauseService with an explicit wait; 0 uses the default.
+func (s *ServiceClient) pauseService(ctx context.Context, name string, wait time.Duration) error {
+	return s.runStateOp(ctx, "Pause", name, wait, `
   try {
     $svc = Get-Service -Name $name -ErrorAction Stop
     if (-not $svc.CanPauseAndContinue) {
@@ -801,37 +1089,47 @@ func (s *ServiceClient) PauseService(ctx context.Context, name string) error {
   }`)
 }
 
-// runStateOp factors the body used by Start/Stop/Pause.
-func (s *ServiceClient) runStateOp(ctx context.Context, op, name, body string) error {
+// stateWaitSeconds returns the WaitForStatus bound for a state operation:
+// wait when positive, otherwise the client timeout (30s if under 10s).
+func (s *ServiceClient) stateWaitSeconds(wait time.Duration) int {
+	if wait > 0 {
+		return int((wait + time.Second - 1) / time.Second)
+	}
 	waitSec := int(s.c.cfg.Timeout / time.Second)
 	if waitSec < 10 {
 		waitSec = 30
 	}
+	return waitSec
+}
+
+// runStateOp factors the body used by Start/Stop/Pause.
+func (s *ServiceClient) runStateOp(ctx context.Context, op, name string, wait time.Duration, body string) error {
 	script := `
 $name    = ` + psQuote(name) + `
-$waitSec = ` + fmt.Sprintf("%d", waitSec) + `
+$waitSec = ` + fmt.Sprintf("%d", s.stateWaitSeconds(wait)) + `
 ` + body + "\n"
 	_, err := s.runEnvelope(ctx, op, name, script)
 	return err
 }
 
 // reconcileStatus dispatches to Start / Stop / Pause based on desired. Idempotent
-// "already in target state" responses are swallowed.
-func (s *ServiceClient) reconcileStatus(ctx context.Context, name, desired string) error {
+// "already in target state" responses are swallowed. wait bounds each
+// WaitForStatus; 0 uses the default.
+func (s *ServiceClient) reconcileStatus(ctx context.Context, name, desired string, wait time.Duration) error {
 	var err error
 	switch desired {
 	case "Running":
-		err = s.StartService(ctx, name)
+		err = s.startService(ctx, name, wait)
 		if errors.Is(err, ErrServiceRunning) {
 			return nil
 		}
 	case "Stopped":
-		err = s.StopService(ctx, name)
+		err = s.stopService(ctx, name, wait)
 		if errors.Is(err, ErrServiceNotRunning) {
 			return nil
 		}
 	case "Paused":
-		err = s.PauseService(ctx, name)
+		err = s.pauseService(ctx, name, wait)
 	default:
 		return NewServiceError(ServiceErrorInvalidParameter,
 			fmt.Sprintf("unknown desired status %q", desired), nil, nil)
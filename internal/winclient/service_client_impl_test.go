@@ -10,6 +10,7 @@
 //   - Update happy path + not_found + empty-name validation
 //   - Delete happy path + already-absent + timeout + empty-name validation
 //   - StartService / StopService / PauseService success + EC-13 PauseService guard
+//   - StartService dependency start-up + dependency_failed + StateTimeout wait
 //   - runEnvelope: ctx cancellation (EC-7 timeout) + transport error + missing envelope
 package winclient
 
@@ -102,6 +103,11 @@ func TestPsQuote(t *testing.T) {
 		{"hello", "'hello'"},
 		{"it's", "'it''s'"},
 		{"a'b'c", "'a''b''c'"},
+		// Typographic single quotes also terminate a PowerShell literal.
+		{"it\u2019s", "'it\u2019\u2019s'"},
+		{"\u2018x\u201b", "'\u2018\u2018x\u201b\u201b'"},
+		{"x\u201a'; Remove-Item C:\\ #", "'x\u201a\u201a''; Remove-Item C:\\ #'"},
+		{"\u201cdouble\u201d", "'\u201cdouble\u201d'"},
 	}
 	for _, tc := range cases {
 		if got := psQuote(tc.in); got != tc.want {
@@ -140,6 +146,54 @@ func TestExtractLastJSONLine(t *testing.T) {
 	}
 }
 
+func TestNormalizeJSONArray(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"", "[]"},
+		{"null", "[]"},
+		{" null\n", "[]"},
+		{"[]", "[]"},
+		{`{"a":1}`, `[{"a":1}]`},
+		{` "x" `, `["x"]`},
+		{`[{"a":1},{"a":2}]`, `[{"a":1},{"a":2}]`},
+	}
+	for _, tc := range cases {
+		if got := string(normalizeJSONArray(json.RawMessage(tc.in))); got != tc.want {
+			t.Errorf("normalizeJSONArray(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestJSONList_ZeroOneMany(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+	cases := map[string]int{
+		`{}`:                                  0,
+		`{"items":null}`:                      0,
+		`{"items":[]}`:                        0,
+		`{"items":{"n":1}}`:                   1,
+		`{"items":[{"n":1},{"n":2}]}`:         2,
+		`{"items":[{"n":1},{"n":2},{"n":3}]}`: 3,
+	}
+	for in, want := range cases {
+		var v struct {
+			Items jsonList[item] `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(in), &v); err != nil || len(v.Items) != want {
+			t.Errorf("%s: %d items, %v; want %d", in, len(v.Items), err, want)
+		}
+		if want > 0 && v.Items[0].N != 1 {
+			t.Errorf("%s: first item = %+v", in, v.Items[0])
+		}
+	}
+	var v struct {
+		Items jsonList[item] `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(`{"items":"oops"}`), &v); err == nil {
+		t.Error("a scalar of the wrong type must fail")
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	if got := truncate("abc", 10); got != "abc" {
 		t.Errorf("short = %q", got)
@@ -159,6 +213,7 @@ func TestMapKind(t *testing.T) {
 		"already_running":   ServiceErrorRunning,
 		"not_running":       ServiceErrorNotRunning,
 		"disabled":          ServiceErrorDisabled,
+		"dependency_failed": ServiceErrorDependencyFailed,
 		"":                  ServiceErrorUnknown,
 		"weird":             ServiceErrorUnknown,
 	}
@@ -277,6 +332,9 @@ func TestCreate_ValidationErrors(t *testing.T) {
 	if _, err := s.Create(ctx, ServiceInput{Name: "svc"}); !IsServiceError(err, ServiceErrorInvalidParameter) {
 		t.Errorf("empty BinaryPath should yield invalid_parameter, got %v", err)
 	}
+	if _, err := s.Create(ctx, ServiceInput{Name: "svc", BinaryPath: `C:\x.exe`, SidType: "restricted"}); !IsServiceError(err, ServiceErrorInvalidParameter) {
+		t.Errorf("unknown SidType should yield invalid_parameter, got %v", err)
+	}
 }
 
 func TestRead_EmptyName(t *testing.T) {
@@ -332,6 +390,20 @@ func TestRunEnvelope_TransportErrorUnknown(t *testing.T) {
 	}
 }
 
+func TestRunEnvelope_ScriptExitCodeInContext(t *testing.T) {
+	restore := stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		return "", "", &TransportError{Kind: FailureCommand, ExitCode: 1060,
+			Err: errors.New("winclient: powershell script failed with exit code 1060")}
+	})
+	defer restore()
+
+	_, err := NewServiceClient(newTestClient(t)).Read(context.Background(), "svc")
+	var se *ServiceError
+	if !errors.As(err, &se) || se.Context["exit_code"] != "1060" || CommandExitCode(err) != 1060 {
+		t.Errorf("expected exit_code 1060 in context, got %v", err)
+	}
+}
+
 func TestRunEnvelope_NoJSONEnvelope(t *testing.T) {
 	restore := stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
 		return "WARNING: no JSON printed\n", "", nil
@@ -428,6 +500,38 @@ func TestCreate_InvalidParameter_EC11_NoPasswordLeak(t *testing.T) {
 	}
 }
 
+// TestCreate_QuotesEveryInterpolatedField checks that display name, binary
+// path and account are rendered through psQuote, including values carrying a
+// typographic apostrophe that PowerShell would otherwise treat as a delimiter.
+func TestCreate_QuotesEveryInterpolatedField(t *testing.T) {
+	var script string
+	restore := stubRunInput(func(_ context.Context, _ *Client, s, _ string) (string, string, error) {
+		script = s
+		return okEnvelope(t, fakeState("svc")), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	_, err := s.Create(context.Background(), ServiceInput{
+		Name:           "svc",
+		BinaryPath:     `C:\O'Brien\svc.exe`,
+		DisplayName:    "O\u2019Brien'; Stop-Computer #",
+		ServiceAccount: "CORP\\o\u2018brien",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, want := range []string{
+		`$binary  = 'C:\O''Brien\svc.exe'`,
+		"$display = 'O\u2019\u2019Brien''; Stop-Computer #'",
+		"$account = 'CORP\\o\u2018\u2018brien'",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
 // TestCreate_PasswordInjectedViaStdin_NotInScriptBody verifies the security
 // invariant: service_password is delivered over stdin, never embedded in the
 // PowerShell -EncodedCommand payload.
@@ -621,6 +725,87 @@ func TestUpdate_HappyPath(t *testing.T) {
 	}
 }
 
+func TestUpdate_DisplayNameOnlyWhenDifferent(t *testing.T) {
+	var captured string
+	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return okEnvelope(t, fakeState("svc")), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{DisplayName: "New Display"}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, "$existing.DisplayName -cne $display") {
+		t.Errorf("Set-Service -DisplayName must be guarded by a comparison with the live value, fragment=%s",
+			firstContainingLine(captured, "DisplayName"))
+	}
+}
+
+func TestUpdate_SidType(t *testing.T) {
+	var captured string
+	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		st := fakeState("svc")
+		st["sid_type"] = "Restricted"
+		return okEnvelope(t, st), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	st, err := s.Update(context.Background(), "svc", ServiceInput{SidType: "Restricted"})
+	if err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, "$sidType  = 'restricted'") || !strings.Contains(captured, "sc.exe sidtype $name $sidType") {
+		t.Errorf("sid type not applied, fragment=%s", firstContainingLine(captured, "$sidType  ="))
+	}
+	if !strings.Contains(captured, "sc.exe qsidtype $Name") {
+		t.Error("read-back should query sc.exe qsidtype")
+	}
+	if st.SidType != "Restricted" {
+		t.Errorf("SidType = %q, want Restricted", st.SidType)
+	}
+
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, "$sidType  = ''") {
+		t.Errorf("empty SidType must leave the SID type untouched, fragment=%s", firstContainingLine(captured, "$sidType  ="))
+	}
+}
+
+func TestUpdate_AutomaticClearsDelayedStart(t *testing.T) {
+	var captured string
+	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return okEnvelope(t, fakeState("svc")), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{StartType: "Automatic"}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, "$finalStart = 'Automatic'") {
+		t.Errorf("finalStart not Automatic, fragment=%s", firstContainingLine(captured, "$finalStart ="))
+	}
+	if !strings.Contains(captured, "$finalStart -eq 'Automatic'") ||
+		!strings.Contains(captured, "-Name DelayedAutostart") ||
+		!strings.Contains(captured, "sc.exe config $name start= auto") {
+		t.Error("Automatic must clear a leftover DelayedAutostart flag via sc.exe start= auto")
+	}
+
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{StartType: "AutomaticDelayedStart"}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, "$stype    = 'Automatic'") || !strings.Contains(captured, "$finalStart = 'AutomaticDelayedStart'") {
+		t.Errorf("delayed start must map to Set-Service Automatic plus sc.exe delayed-auto, fragment=%s",
+			firstContainingLine(captured, "$stype"))
+	}
+}
+
 func TestUpdate_ClearsDependencies(t *testing.T) {
 	var captured string
 	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
@@ -694,6 +879,49 @@ func TestDelete_Timeout_EC7(t *testing.T) {
 	}
 }
 
+func TestDelete_StopsDependentsFirst(t *testing.T) {
+	var script string
+	restore := stubRun(func(ctx context.Context, c *Client, s string) (string, string, error) {
+		script = s
+		return okEnvelope(t, map[string]any{"deleted": true}), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	if err := s.Delete(context.Background(), "svc"); err != nil {
+		t.Fatalf("Delete err: %v", err)
+	}
+	deps, self := strings.Index(script, "$svc.DependentServices"), strings.Index(script, "Stop-Service -Name $name")
+	if deps < 0 || self < 0 || deps > self {
+		t.Errorf("dependents must be stopped before the service itself:\n%s", script)
+	}
+	if !strings.Contains(script, "$dep.WaitForStatus('Stopped'") {
+		t.Error("script should wait for each dependent to reach Stopped")
+	}
+}
+
+func TestDelete_DependentFailed(t *testing.T) {
+	restore := stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		b, _ := json.Marshal(map[string]any{
+			"ok": false, "kind": "dependency_failed",
+			"message": "dependent service 'W3SVC' of service 'svc' did not stop within 30 s",
+			"context": map[string]string{"dependent": "W3SVC", "dependent_status": "StopPending"},
+		})
+		return string(b) + "\n", "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	err := s.Delete(context.Background(), "svc")
+	if !errors.Is(err, ErrServiceDependencyFailed) {
+		t.Fatalf("expected dependency_failed, got %v", err)
+	}
+	var se *ServiceError
+	if !errors.As(err, &se) || se.Context["dependent"] != "W3SVC" {
+		t.Errorf("dependent should be surfaced in Context, got %+v", se)
+	}
+}
+
 // -----------------------------------------------------------------------------
 // StartService / StopService / PauseService
 // -----------------------------------------------------------------------------
@@ -736,6 +964,74 @@ func TestStartService_Disabled(t *testing.T) {
 	}
 }
 
+func TestStartService_StartsDependenciesFirst(t *testing.T) {
+	var script string
+	restore := stubRun(func(ctx context.Context, c *Client, s string) (string, string, error) {
+		script = s
+		return okEnvelope(t, map[string]any{"status": "Running"}), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	if err := s.StartService(context.Background(), "svc"); err != nil {
+		t.Fatalf("Start err: %v", err)
+	}
+	deps, self := strings.Index(script, "$svc.ServicesDependedOn"), strings.Index(script, "Start-Service -Name $name")
+	if deps < 0 || self < 0 || deps > self {
+		t.Errorf("dependencies must be started before the service itself:\n%s", script)
+	}
+	if !strings.Contains(script, "$dep.WaitForStatus('Running'") {
+		t.Error("script should wait for each dependency to reach Running")
+	}
+	if !strings.Contains(script, "$waitSec = 30") {
+		t.Errorf("default wait should derive from the client timeout: %q", firstContainingLine(script, "$waitSec ="))
+	}
+}
+
+func TestStartService_DependencyFailed(t *testing.T) {
+	restore := stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		b, _ := json.Marshal(map[string]any{
+			"ok": false, "kind": "dependency_failed",
+			"message": "dependency 'Tcpip' of service 'svc' is Disabled and cannot be started",
+			"context": map[string]string{"dependency": "Tcpip", "dependency_status": "Stopped"},
+		})
+		return string(b) + "\n", "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	err := s.StartService(context.Background(), "svc")
+	if !errors.Is(err, ErrServiceDependencyFailed) {
+		t.Fatalf("expected dependency_failed, got %v", err)
+	}
+	var se *ServiceError
+	if !errors.As(err, &se) || se.Context["dependency"] != "Tcpip" {
+		t.Errorf("dependency should be surfaced in Context, got %+v", se)
+	}
+}
+
+func TestCreate_StateTimeoutBoundsWait(t *testing.T) {
+	var startScript string
+	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		if strings.Contains(script, "Start-Service -Name $name") {
+			startScript = script
+			return okEnvelope(t, map[string]any{"status": "Running"}), "", nil
+		}
+		return okEnvelope(t, fakeState("svc")), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	if _, err := s.Create(context.Background(), ServiceInput{
+		Name: "svc", BinaryPath: `C:\svc.exe`, DesiredStatus: "Running", StateTimeout: 90 * time.Second,
+	}); err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+	if !strings.Contains(startScript, "$waitSec = 90") {
+		t.Errorf("StateTimeout not applied: %q", firstContainingLine(startScript, "$waitSec ="))
+	}
+}
+
 func TestStopService_Success(t *testing.T) {
 	restore := stubRun(func(ctx context.Context, c *Client, script string) (string, string, error) {
 		return okEnvelope(t, map[string]any{"status": "Stopped"}), "", nil
@@ -790,6 +1086,111 @@ func TestPauseService_NotPausable_EC13(t *testing.T) {
 	}
 }
 
+// -----------------------------------------------------------------------------
+// Command line (binary_path + arguments)
+// -----------------------------------------------------------------------------
+
+func TestServiceCommandLine(t *testing.T) {
+	cases := []struct{ exe, args, want string }{
+		{`C:\app.exe`, "", `"C:\app.exe"`},
+		{`C:\Program Files\App\app.exe`, `--config "C:\My Cfg\app.json"`, `"C:\Program Files\App\app.exe" --config "C:\My Cfg\app.json"`},
+		{`"C:\Program Files\App\app.exe"`, "-v", `"C:\Program Files\App\app.exe" -v`},
+		{`C:\app.exe`, "  -v  ", `"C:\app.exe" -v`},
+	}
+	for _, c := range cases {
+		if got := serviceCommandLine(c.exe, c.args); got != c.want {
+			t.Errorf("serviceCommandLine(%q, %q) = %q, want %q", c.exe, c.args, got, c.want)
+		}
+	}
+}
+
+func TestSplitServiceCommandLine(t *testing.T) {
+	cases := []struct{ raw, exe, args string }{
+		{`"C:\Program Files\App\app.exe" --config "C:\My Cfg\app.json"`, `C:\Program Files\App\app.exe`, `--config "C:\My Cfg\app.json"`},
+		{`"C:\Program Files\App\app.exe"`, `C:\Program Files\App\app.exe`, ""},
+		{`C:\WINDOWS\system32\svchost.exe -k netsvcs -p`, `C:\WINDOWS\system32\svchost.exe`, "-k netsvcs -p"},
+		{`C:\Program Files\App\app.exe`, `C:\Program Files\App\app.exe`, ""},
+		{`C:\tools.exes\app.EXE /s`, `C:\tools.exes\app.EXE`, "/s"},
+		{`C:\scripts\run.bat arg`, `C:\scripts\run.bat arg`, ""},
+		{`"C:\unterminated.exe -x`, `C:\unterminated.exe -x`, ""},
+	}
+	for _, c := range cases {
+		exe, args := splitServiceCommandLine(c.raw)
+		if exe != c.exe || args != c.args {
+			t.Errorf("splitServiceCommandLine(%q) = (%q, %q), want (%q, %q)", c.raw, exe, args, c.exe, c.args)
+		}
+	}
+}
+
+func TestNormaliseState_SplitsCommandLine(t *testing.T) {
+	d := &stateData{Name: "s", BinaryPath: `"C:\Program Files\App\app.exe" --port 8080`, ServiceAccount: "LocalSystem"}
+	st := normaliseState(d)
+	if st.Executable != `C:\Program Files\App\app.exe` || st.Arguments != "--port 8080" {
+		t.Errorf("split mismatch: exe=%q args=%q", st.Executable, st.Arguments)
+	}
+	if st.BinaryPath != `"C:\Program Files\App\app.exe" --port 8080` {
+		t.Errorf("BinaryPath must keep the full command line: %q", st.BinaryPath)
+	}
+}
+
+func TestCreate_ArgumentsQuotedIntoBinary(t *testing.T) {
+	var captured string
+	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return okEnvelope(t, fakeState("svc")), "", nil
+	})
+	defer restore()
+
+	args := `--config "C:\My Cfg\app.json"`
+	s := NewServiceClient(newTestClient(t))
+	if _, err := s.Create(context.Background(), ServiceInput{
+		Name: "svc", BinaryPath: `C:\Program Files\App\app.exe`, Arguments: &args,
+	}); err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+	want := `'"C:\Program Files\App\app.exe" --config "C:\My Cfg\app.json"'`
+	if !strings.Contains(captured, want) {
+		t.Errorf("binary not quoted+joined, got line %s", firstContainingLine(captured, "$binary  ="))
+	}
+}
+
+func TestUpdate_ArgumentsSetsCommandLine(t *testing.T) {
+	var captured string
+	restore := stubBothPS(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		captured = script
+		return okEnvelope(t, fakeState("svc")), "", nil
+	})
+	defer restore()
+
+	s := NewServiceClient(newTestClient(t))
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(firstContainingLine(captured, "$cmdMode  ="), "'skip'") {
+		t.Errorf("nil Arguments must skip the command line: %s", firstContainingLine(captured, "$cmdMode  ="))
+	}
+
+	args := "-v"
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{BinaryPath: `C:\app.exe`, Arguments: &args}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, `$cmdLine  = '"C:\app.exe" -v'`) || !strings.Contains(captured, "'set'") {
+		t.Errorf("expected set mode with quoted command line, got %s", firstContainingLine(captured, "$cmdLine  ="))
+	}
+	if !strings.Contains(captured, "Win32_Service") {
+		t.Error("command line should be applied through Win32_Service.Change")
+	}
+}
+
+func TestUpdate_ArgumentsRequireBinaryPath(t *testing.T) {
+	args := "-v"
+	s := NewServiceClient(newTestClient(t))
+	_, err := s.Update(context.Background(), "svc", ServiceInput{Arguments: &args})
+	if !IsServiceError(err, ServiceErrorInvalidParameter) {
+		t.Errorf("expected invalid_parameter, got %v", err)
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Classification & helpers coverage
 // -----------------------------------------------------------------------------
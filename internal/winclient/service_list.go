@@ -0,0 +1,113 @@
+// Package winclient: Windows service enumeration over WinRM.
+//
+// ServiceClient.List backs the windows_services data source. Services are
+// enumerated with Get-Service | Where-Object; the logon account and the
+// delayed-start flag, which Get-Service does not expose on Windows
+// PowerShell 5.1, are joined in from a single Win32_Service query.
+//
+// Security invariants:
+//   - The name and status filters are interpolated only through psQuote.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Compile-time assertion: ServiceClient satisfies WindowsServiceLister.
+var _ WindowsServiceLister = (*ServiceClient)(nil)
+
+// serviceSummaryPayload is one entry of the "services" array emitted by
+// psListServices.
+type serviceSummaryPayload struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Status      string `json:"status"`
+	StartType   string `json:"start_type"`
+	StartName   string `json:"start_name"`
+}
+
+// psListServices enumerates services matching @@NAME@@ / @@STATUS@@. The
+// services array is wrapped in an object so ConvertTo-Json keeps it an array
+// for a single match; List still accepts a bare object defensively.
+const psListServices = `
+try {
+  $nameFilter   = @@NAME@@
+  $statusFilter = @@STATUS@@
+
+  $cim = @{}
+  try {
+    Get-CimInstance -ClassName Win32_Service -ErrorAction Stop | ForEach-Object { $cim[$_.Name] = $_ }
+  } catch { }
+
+  $rows = @(Get-Service -ErrorAction SilentlyContinue | Where-Object {
+      ($nameFilter -eq '' -or $_.Name -like $nameFilter) -and
+      ($statusFilter -eq '' -or [string]$_.Status -eq $statusFilter)
+    } | Sort-Object -Property Name | ForEach-Object {
+      $w = $cim[$_.Name]
+      $startType = [string]$_.StartType
+      $startName = ''
+      if ($w) {
+        switch ([string]$w.StartMode) {
+          'Auto'     { if ($w.DelayedAutoStart) { $startType = 'AutomaticDelayedStart' } else { $startType = 'Automatic' } }
+          'Manual'   { $startType = 'Manual' }
+          'Disabled' { $startType = 'Disabled' }
+          'Boot'     { $startType = 'Boot' }
+          'System'   { $startType = 'System' }
+        }
+        $startName = [string]$w.StartName
+      }
+      [ordered]@{
+        name         = [string]$_.Name
+        display_name = [string]$_.DisplayName
+        status       = [string]$_.Status
+        start_type   = $startType
+        start_name   = $startName
+      }
+    })
+  Emit-OK ([ordered]@{ services = $rows })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify $msg) $msg @{}
+}
+`
+
+// List enumerates the services matching filter, sorted by name.
+func (s *ServiceClient) List(ctx context.Context, filter ServiceListFilter) ([]ServiceSummary, error) {
+	script := strings.NewReplacer(
+		"@@NAME@@", psQuote(filter.NamePattern),
+		"@@STATUS@@", psQuote(filter.Status),
+	).Replace(psListServices)
+
+	resp, err := s.runEnvelope(ctx, "List", filter.NamePattern, script)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := decodeServiceSummaries(resp.Data)
+	if err != nil {
+		return nil, NewServiceError(ServiceErrorUnknown, "failed to parse service list", err,
+			map[string]string{"operation": "List", "host": s.c.cfg.Host})
+	}
+
+	out := make([]ServiceSummary, 0, len(payload))
+	for _, p := range payload {
+		out = append(out, ServiceSummary(p))
+	}
+	return out, nil
+}
+
+// decodeServiceSummaries extracts the "services" list from the List
+// envelope data, accepting the collapsed shapes ConvertTo-Json can produce
+// (see normalizeJSONArray).
+func decodeServiceSummaries(data json.RawMessage) ([]serviceSummaryPayload, error) {
+	var wrapper struct {
+		Services jsonList[serviceSummaryPayload] `json:"services"`
+	}
+	if len(data) > 0 && string(data) != "null" {
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, err
+		}
+	}
+	return wrapper.Services, nil
+}
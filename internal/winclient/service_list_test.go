@@ -0,0 +1,89 @@
+// Package winclient — unit tests for ServiceClient.List.
+package winclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestServiceList_FiltersAndDecodes(t *testing.T) {
+	var script string
+	defer stubRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return okEnvelope(t, map[string]any{"services": []map[string]string{
+			{"name": "MSSQL$A", "display_name": "SQL Server (A)", "status": "Running", "start_type": "AutomaticDelayedStart", "start_name": `NT Service\MSSQL$A`},
+			{"name": "MSSQL$B", "display_name": "SQL Server (B)", "status": "StopPending", "start_type": "Manual", "start_name": "LocalSystem"},
+		}}), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	got, err := s.List(context.Background(), ServiceListFilter{NamePattern: "MSSQL*", Status: "Running"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 2 || got[0].StartType != "AutomaticDelayedStart" || got[1].Status != "StopPending" || got[0].StartName != `NT Service\MSSQL$A` {
+		t.Errorf("List = %+v", got)
+	}
+	for _, want := range []string{"$nameFilter   = 'MSSQL*'", "$statusFilter = 'Running'", "Get-Service", "Where-Object"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestServiceList_QuotesFilter(t *testing.T) {
+	var script string
+	defer stubRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		script = s
+		return okEnvelope(t, map[string]any{"services": []any{}}), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	if _, err := s.List(context.Background(), ServiceListFilter{NamePattern: "x'; Stop-Computer; '"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(script, "$nameFilter   = 'x''; Stop-Computer; '''") {
+		t.Error("name filter was not quoted")
+	}
+}
+
+func TestDecodeServiceSummaries_Shapes(t *testing.T) {
+	cases := map[string]int{
+		`null`:                            0,
+		`{"services":null}`:               0,
+		`{"services":[]}`:                 0,
+		`{"services":{"name":"W32Time"}}`: 1,
+		`{"services":[{"name":"a"},{"name":"b"}]}`: 2,
+	}
+	for in, want := range cases {
+		got, err := decodeServiceSummaries([]byte(in))
+		if err != nil || len(got) != want {
+			t.Errorf("decode(%s) = %d entries, %v; want %d", in, len(got), err, want)
+		}
+	}
+	if _, err := decodeServiceSummaries([]byte(`{"services":"oops"}`)); err == nil {
+		t.Error("a scalar services value must fail")
+	}
+}
+
+func TestServiceList_EmptyAndErrors(t *testing.T) {
+	restore := stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return okEnvelope(t, map[string]any{"services": nil}), "", nil
+	})
+	s := NewServiceClient(newTestClient(t))
+	got, err := s.List(context.Background(), ServiceListFilter{NamePattern: "nomatch*"})
+	restore()
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("no match: %#v, %v; want empty non-nil slice", got, err)
+	}
+
+	restore = stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return errEnvelope(t, "permission_denied", "Access is denied"), "", nil
+	})
+	_, err = s.List(context.Background(), ServiceListFilter{})
+	restore()
+	if !IsServiceError(err, ServiceErrorPermission) {
+		t.Errorf("err = %v, want permission_denied", err)
+	}
+}
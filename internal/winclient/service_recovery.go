@@ -0,0 +1,240 @@
+// Package winclient: service failure actions (ServiceInput.Recovery).
+//
+// Failure actions are written with sc.exe failure and read back with
+// sc.exe qfailure; neither Set-Service nor Win32_Service covers them.
+//
+// sc.exe failure needs an empty argument to mean "no action" (actions= "")
+// and takes the Run command as one argument that may itself contain quotes.
+// Windows PowerShell drops empty arguments to native commands and re-quotes
+// embedded ones differently across versions, so the argument line is built
+// here with windowsArgQuote and handed to sc.exe verbatim through
+// ProcessStartInfo.Arguments (Invoke-ScRaw). The line only reaches the script
+// through psQuote.
+//
+// qfailure output is text, not JSON:
+//
+//	[SC] QueryServiceConfig2 SUCCESS
+//
+//	SERVICE_NAME: MyApp
+//	        RESET_PERIOD (in seconds)    : 86400
+//	        REBOOT_MESSAGE               :
+//	        COMMAND_LINE                 : C:\ops\notify.cmd
+//	        FAILURE_ACTIONS              : RESTART -- Delay = 60000 milliseconds.
+//	                                       RUN PROCESS -- Delay = 60000 milliseconds.
+//
+// Read-ServiceState returns it raw and parseServiceFailureOutput parses it
+// here, where it can be unit tested.
+package winclient
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// psInvokeScRaw defines Invoke-ScRaw, which runs sc.exe with an argument line
+// passed through unchanged and returns its exit code and combined output.
+const psInvokeScRaw = `
+function Invoke-ScRaw([string]$ArgLine) {
+  $psi = New-Object System.Diagnostics.ProcessStartInfo
+  $psi.FileName               = Join-Path $env:SystemRoot 'System32\sc.exe'
+  $psi.Arguments              = $ArgLine
+  $psi.UseShellExecute        = $false
+  $psi.RedirectStandardOutput = $true
+  $psi.RedirectStandardError  = $true
+  $p = [System.Diagnostics.Process]::Start($psi)
+  $out = $p.StandardOutput.ReadToEnd() + $p.StandardError.ReadToEnd()
+  $p.WaitForExit()
+  return @{ code = $p.ExitCode; out = $out }
+}
+`
+
+// scFailureActions maps ServiceRecovery action names to sc.exe failure
+// tokens. "None" is the empty token.
+var scFailureActions = map[string]string{
+	"":        "",
+	"None":    "",
+	"Restart": "restart",
+	"Run":     "run",
+	"Reboot":  "reboot",
+}
+
+// validateServiceRecovery rejects unknown actions, negative durations and a
+// Run action without a command. A nil Recovery is valid (unmanaged).
+func validateServiceRecovery(r *ServiceRecovery) error {
+	if r == nil {
+		return nil
+	}
+	run := false
+	for _, a := range []string{r.FirstAction, r.SecondAction, r.SubsequentActions} {
+		if _, ok := scFailureActions[a]; !ok {
+			return NewServiceError(ServiceErrorInvalidParameter,
+				fmt.Sprintf("recovery action %q must be one of None, Restart, Run, Reboot", a), nil, nil)
+		}
+		run = run || a == "Run"
+	}
+	if r.ResetPeriodSeconds < 0 || r.RestartDelaySeconds < 0 {
+		return NewServiceError(ServiceErrorInvalidParameter,
+			"recovery reset period and restart delay must not be negative", nil, nil)
+	}
+	if run && strings.TrimSpace(r.Command) == "" {
+		return NewServiceError(ServiceErrorInvalidParameter,
+			"recovery command is required when an action is Run", nil, nil)
+	}
+	return nil
+}
+
+// serviceFailureArgs renders the sc.exe failure argument line for r, or ""
+// when r is nil. Trailing "None" actions are dropped so qfailure reads back
+// the same list; when every action is "None" the actions are cleared with
+// actions= "". The command is always sent so removing it clears it.
+func serviceFailureArgs(name string, r *ServiceRecovery) string {
+	if r == nil {
+		return ""
+	}
+	acts := []string{r.FirstAction, r.SecondAction, r.SubsequentActions}
+	n := len(acts)
+	for n > 0 && scFailureActions[acts[n-1]] == "" {
+		n--
+	}
+	actions := `""`
+	if n > 0 {
+		delay := strconv.FormatInt(r.RestartDelaySeconds*1000, 10)
+		parts := make([]string, 0, 2*n)
+		for _, a := range acts[:n] {
+			tok := scFailureActions[a]
+			d := delay
+			if tok == "" {
+				d = "0"
+			}
+			parts = append(parts, tok, d)
+		}
+		actions = strings.Join(parts, "/")
+	}
+	return fmt.Sprintf("failure %s reset= %d actions= %s command= %s",
+		windowsArgQuote(name), r.ResetPeriodSeconds, actions, windowsArgQuote(r.Command))
+}
+
+// windowsArgQuote quotes s as a single argument for the C runtime argv
+// parser (CommandLineToArgvW rules): backslashes are literal except before
+// a double quote, where they and the quote are escaped.
+func windowsArgQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for _, r := range s {
+		switch r {
+		case '\\':
+			slashes++
+			continue
+		case '"':
+			b.WriteString(strings.Repeat(`\`, 2*slashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, slashes))
+		}
+		slashes = 0
+		b.WriteRune(r)
+	}
+	b.WriteString(strings.Repeat(`\`, 2*slashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+var (
+	qfailureKeyRe    = regexp.MustCompile(`^\s*([A-Z_]+)[^:]*:\s?(.*)$`)
+	qfailureActionRe = regexp.MustCompile(`^\s*([A-Z][A-Z ]*?)\s*--\s*Delay\s*=\s*(\d+)`)
+)
+
+// parseServiceFailureOutput parses sc.exe qfailure output. It returns nil
+// when raw is empty or has no RESET_PERIOD line (the query failed or the
+// output is not what qfailure prints).
+//
+// Actions are positional: the first and second listed are FirstAction and
+// SecondAction, and the last of the third and later is SubsequentActions (the
+// SCM repeats the last action). Labels other than RESTART, RUN PROCESS and
+// REBOOT read as "None". A RESET_PERIOD of INFINITE reads as 4294967295, the
+// value the SCM stores for it.
+func parseServiceFailureOutput(raw string) *ServiceRecovery {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	type action struct {
+		name    string
+		delayMs int64
+	}
+	var (
+		out     ServiceRecovery
+		found   bool
+		actions []action
+		inActs  bool
+	)
+	addAction := func(text string) bool {
+		m := qfailureActionRe.FindStringSubmatch(text)
+		if m == nil {
+			return false
+		}
+		ms, _ := strconv.ParseInt(m[2], 10, 64)
+		name := "None"
+		switch strings.ToUpper(strings.TrimSpace(m[1])) {
+		case "RESTART":
+			name = "Restart"
+		case "RUN PROCESS":
+			name = "Run"
+		case "REBOOT":
+			name = "Reboot"
+		}
+		actions = append(actions, action{name, ms})
+		return true
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if inActs && addAction(line) {
+			continue
+		}
+		inActs = false
+		m := qfailureKeyRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		val := strings.TrimSpace(m[2])
+		switch m[1] {
+		case "RESET_PERIOD":
+			found = true
+			if strings.EqualFold(val, "INFINITE") {
+				out.ResetPeriodSeconds = 0xFFFFFFFF
+			} else {
+				out.ResetPeriodSeconds, _ = strconv.ParseInt(val, 10, 64)
+			}
+		case "COMMAND_LINE":
+			out.Command = val
+		case "FAILURE_ACTIONS":
+			inActs = true
+			addAction(val)
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	slots := []*string{&out.FirstAction, &out.SecondAction, &out.SubsequentActions}
+	for i, p := range slots {
+		*p = "None"
+		switch {
+		case i < 2 && i < len(actions):
+			*p = actions[i].name
+		case i == 2 && len(actions) > 2:
+			*p = actions[len(actions)-1].name
+		}
+	}
+	for _, a := range actions {
+		if a.name != "None" {
+			out.RestartDelaySeconds = a.delayMs / 1000
+			break
+		}
+	}
+	return &out
+}
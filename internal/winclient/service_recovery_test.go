@@ -0,0 +1,173 @@
+// Package winclient — unit tests for service failure actions
+// (ServiceInput.Recovery / ServiceState.Recovery).
+package winclient
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const qfailureSample = "[SC] QueryServiceConfig2 SUCCESS\r\n" +
+	"\r\n" +
+	"SERVICE_NAME: MyApp\r\n" +
+	"        RESET_PERIOD (in seconds)    : 86400\r\n" +
+	"        REBOOT_MESSAGE               :\r\n" +
+	"        COMMAND_LINE                 : \"C:\\ops tools\\notify.cmd\" MyApp\r\n" +
+	"        FAILURE_ACTIONS              : RESTART -- Delay = 60000 milliseconds.\r\n" +
+	"                                       RESTART -- Delay = 60000 milliseconds.\r\n" +
+	"                                       RUN PROCESS -- Delay = 60000 milliseconds.\r\n" +
+	"\r\n"
+
+func TestParseServiceFailureOutput(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want *ServiceRecovery
+	}{
+		{"empty", "", nil},
+		{"not qfailure output", "[SC] OpenService FAILED 1060:\r\n", nil},
+		{"full", qfailureSample, &ServiceRecovery{
+			FirstAction: "Restart", SecondAction: "Restart", SubsequentActions: "Run",
+			ResetPeriodSeconds: 86400, RestartDelaySeconds: 60, Command: `"C:\ops tools\notify.cmd" MyApp`,
+		}},
+		{"no actions", "SERVICE_NAME: MyApp\n        RESET_PERIOD (in seconds)    : 0\n        REBOOT_MESSAGE               :\n        COMMAND_LINE                 :\n", &ServiceRecovery{
+			FirstAction: "None", SecondAction: "None", SubsequentActions: "None",
+		}},
+		{"one action, infinite reset", "SERVICE_NAME: MyApp\n        RESET_PERIOD (in seconds)    : INFINITE\n        FAILURE_ACTIONS              : REBOOT -- Delay = 120000 milliseconds.\n", &ServiceRecovery{
+			FirstAction: "Reboot", SecondAction: "None", SubsequentActions: "None",
+			ResetPeriodSeconds: 0xFFFFFFFF, RestartDelaySeconds: 120,
+		}},
+		{"none first, last of four repeats", "        RESET_PERIOD (in seconds)    : 60\n" +
+			"        FAILURE_ACTIONS              : NONE -- Delay = 0 milliseconds.\n" +
+			"                                       RESTART -- Delay = 5000 milliseconds.\n" +
+			"                                       RESTART -- Delay = 5000 milliseconds.\n" +
+			"                                       REBOOT -- Delay = 5000 milliseconds.\n" +
+			"        SOMETHING_ELSE               : RESTART -- Delay = 1 milliseconds.\n", &ServiceRecovery{
+			FirstAction: "None", SecondAction: "Restart", SubsequentActions: "Reboot",
+			ResetPeriodSeconds: 60, RestartDelaySeconds: 5,
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseServiceFailureOutput(tc.raw); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestServiceFailureArgs(t *testing.T) {
+	cases := []struct {
+		name string
+		in   *ServiceRecovery
+		want string
+	}{
+		{"nil", nil, ""},
+		{"restart twice then run", &ServiceRecovery{
+			FirstAction: "Restart", SecondAction: "Restart", SubsequentActions: "Run",
+			ResetPeriodSeconds: 86400, RestartDelaySeconds: 60, Command: `"C:\ops tools\notify.cmd" MyApp`,
+		}, `failure MyApp reset= 86400 actions= restart/60000/restart/60000/run/60000 command= "\"C:\ops tools\notify.cmd\" MyApp"`},
+		{"trailing none dropped, middle none kept", &ServiceRecovery{
+			FirstAction: "Restart", SecondAction: "None", SubsequentActions: "",
+			RestartDelaySeconds: 5,
+		}, `failure MyApp reset= 0 actions= restart/5000 command= ""`},
+		{"none between actions", &ServiceRecovery{
+			FirstAction: "None", SecondAction: "Reboot", RestartDelaySeconds: 1,
+		}, `failure MyApp reset= 0 actions= /0/reboot/1000 command= ""`},
+		{"all none clears", &ServiceRecovery{FirstAction: "None", ResetPeriodSeconds: 60},
+			`failure MyApp reset= 60 actions= "" command= ""`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := serviceFailureArgs("MyApp", tc.in); got != tc.want {
+				t.Errorf("got  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWindowsArgQuote(t *testing.T) {
+	cases := map[string]string{
+		"":                `""`,
+		"plain":           "plain",
+		`C:\dir\x.cmd`:    `C:\dir\x.cmd`,
+		`C:\my dir\x.cmd`: `"C:\my dir\x.cmd"`,
+		`a"b`:             `"a\"b"`,
+		`trail\ `:         `"trail\ "`,
+		`dir\`:            `dir\`,
+		`my dir\`:         `"my dir\\"`,
+		`x\"y`:            `"x\\\"y"`,
+	}
+	for in, want := range cases {
+		if got := windowsArgQuote(in); got != want {
+			t.Errorf("windowsArgQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestValidateServiceRecovery(t *testing.T) {
+	valid := []*ServiceRecovery{
+		nil,
+		{},
+		{FirstAction: "Restart", SecondAction: "Reboot", SubsequentActions: "None"},
+		{FirstAction: "Run", Command: "notify.cmd"},
+	}
+	for _, r := range valid {
+		if err := validateServiceRecovery(r); err != nil {
+			t.Errorf("%+v: %v", r, err)
+		}
+	}
+	invalid := []*ServiceRecovery{
+		{FirstAction: "restart"},
+		{SubsequentActions: "Run"},
+		{FirstAction: "Restart", RestartDelaySeconds: -1},
+	}
+	for _, r := range invalid {
+		if err := validateServiceRecovery(r); !IsServiceError(err, ServiceErrorInvalidParameter) {
+			t.Errorf("%+v: err = %v, want invalid_parameter", r, err)
+		}
+	}
+}
+
+func TestUpdate_Recovery(t *testing.T) {
+	var captured string
+	defer stubBothPS(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		captured = script
+		st := fakeState("svc")
+		st["failure_actions"] = qfailureSample
+		return okEnvelope(t, st), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	st, err := s.Update(context.Background(), "svc", ServiceInput{Recovery: &ServiceRecovery{
+		FirstAction: "Restart", ResetPeriodSeconds: 3600, RestartDelaySeconds: 30,
+	}})
+	if err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, `$failArgs = 'failure svc reset= 3600 actions= restart/30000 command= ""'`) ||
+		!strings.Contains(captured, "Invoke-ScRaw $failArgs") {
+		t.Errorf("failure actions not applied, fragment=%s", firstContainingLine(captured, "$failArgs ="))
+	}
+	if !strings.Contains(captured, "sc.exe qfailure $Name 8192") {
+		t.Error("read-back should query sc.exe qfailure")
+	}
+	if st.Recovery == nil || st.Recovery.SubsequentActions != "Run" || st.Recovery.ResetPeriodSeconds != 86400 {
+		t.Errorf("Recovery = %+v", st.Recovery)
+	}
+
+	if _, err := s.Update(context.Background(), "svc", ServiceInput{}); err != nil {
+		t.Fatalf("Update err: %v", err)
+	}
+	if !strings.Contains(captured, "$failArgs = ''") {
+		t.Errorf("nil Recovery must leave failure actions untouched, fragment=%s", firstContainingLine(captured, "$failArgs ="))
+	}
+
+	if _, err := s.Create(context.Background(), ServiceInput{
+		Name: "svc", BinaryPath: `C:\svc.exe`, Recovery: &ServiceRecovery{FirstAction: "Run"},
+	}); !IsServiceError(err, ServiceErrorInvalidParameter) {
+		t.Errorf("Create with Run and no command: err = %v", err)
+	}
+}
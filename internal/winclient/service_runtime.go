@@ -0,0 +1,106 @@
+// Package winclient: start type and runtime state of an existing service.
+//
+// ServiceClient.SetRuntime backs the windows_service_state resource, which
+// adopts a service it did not create. Only the start type (Set-Service
+// -StartupType, plus sc.exe for the delayed-start flag) and the runtime
+// state (the Start/Stop paths shared with windows_service) are touched; the
+// binary path, account, description and dependencies are never rewritten.
+//
+// Security invariants:
+//   - The service name is interpolated only through psQuote.
+package winclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Compile-time assertion: ServiceClient satisfies WindowsServiceStateClient.
+var _ WindowsServiceStateClient = (*ServiceClient)(nil)
+
+// psSetStartType sets @@STYPE@@ via Set-Service and reconciles the
+// DelayedAutostart flag for @@FINAL@@ the same way Update does.
+const psSetStartType = `
+try {
+  $name       = @@NAME@@
+  $stype      = @@STYPE@@
+  $finalStart = @@FINAL@@
+
+  $existing = Get-Service -Name $name -ErrorAction SilentlyContinue
+  if (-not $existing) { Emit-Err 'not_found' "service '$name' does not exist" @{}; return }
+
+  Set-Service -Name $name -StartupType $stype -ErrorAction Stop
+
+  if ($finalStart -eq 'AutomaticDelayedStart') {
+    $out = & sc.exe config $name start= delayed-auto 2>&1 | Out-String
+    if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe delayed-auto failed: " + $out.Trim()) @{}; return }
+  } elseif ($finalStart -eq 'Automatic') {
+    $delayed = (Get-ItemProperty -Path ("HKLM:\SYSTEM\CurrentControlSet\Services\" + $name) -Name DelayedAutostart -ErrorAction SilentlyContinue).DelayedAutostart
+    if ($delayed -eq 1) {
+      $out = & sc.exe config $name start= auto 2>&1 | Out-String
+      if ($LASTEXITCODE -ne 0) { Emit-Err (Classify $out) ("sc.exe start= auto failed: " + $out.Trim()) @{}; return }
+    }
+  }
+  Emit-OK @{ start_type = $finalStart }
+} catch {
+  $msg  = $_.Exception.Message
+  $kind = Classify $msg
+  Emit-Err $kind $msg @{}
+}
+`
+
+// SetRuntime applies in.StartType, then in.DesiredStatus, to the existing
+// service name and returns its observed state.
+func (s *ServiceClient) SetRuntime(ctx context.Context, name string, in ServiceRuntimeInput) (*ServiceState, error) {
+	if name == "" {
+		return nil, NewServiceError(ServiceErrorInvalidParameter, "name is required", nil, nil)
+	}
+	switch in.StartType {
+	case "", "Automatic", "AutomaticDelayedStart", "Manual", "Disabled":
+	default:
+		return nil, NewServiceError(ServiceErrorInvalidParameter,
+			fmt.Sprintf("unknown start type %q", in.StartType), nil, map[string]string{"name": name})
+	}
+	switch in.DesiredStatus {
+	case "", "Running", "Stopped":
+	default:
+		return nil, NewServiceError(ServiceErrorInvalidParameter,
+			fmt.Sprintf("desired status must be Running or Stopped, got %q", in.DesiredStatus), nil, map[string]string{"name": name})
+	}
+	if in.StartType == "Disabled" && in.DesiredStatus == "Running" {
+		return nil, NewServiceError(ServiceErrorInvalidParameter,
+			"a Disabled service cannot be Running", nil, map[string]string{"name": name})
+	}
+
+	if in.StartType != "" {
+		setSvcStart := in.StartType
+		if setSvcStart == "AutomaticDelayedStart" {
+			setSvcStart = "Automatic"
+		}
+		script := strings.NewReplacer(
+			"@@NAME@@", psQuote(name),
+			"@@STYPE@@", psQuote(setSvcStart),
+			"@@FINAL@@", psQuote(in.StartType),
+		).Replace(psSetStartType)
+		if _, err := s.runEnvelope(ctx, "SetStartType", name, script); err != nil {
+			return nil, err
+		}
+	}
+
+	if in.DesiredStatus != "" {
+		if err := s.reconcileStatus(ctx, name, in.DesiredStatus, in.StateTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	state, err := s.Read(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, NewServiceError(ServiceErrorNotFound,
+			fmt.Sprintf("service %q does not exist", name), nil, map[string]string{"name": name})
+	}
+	return state, nil
+}
@@ -0,0 +1,106 @@
+// Package winclient — unit tests for ServiceClient.SetRuntime.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// runtimeStateData is a Read-ServiceState payload for the "Spooler" service.
+func runtimeStateData(startType, status string) map[string]any {
+	return map[string]any{
+		"name": "Spooler", "display_name": "Print Spooler", "binary_path": `C:\Windows\System32\spoolsv.exe`,
+		"start_type": startType, "current_status": status, "service_account": "LocalSystem",
+	}
+}
+
+func TestSetRuntime_StartTypeThenStatus(t *testing.T) {
+	var scripts []string
+	defer stubRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		scripts = append(scripts, s)
+		if strings.Contains(s, "Read-ServiceState $name") && !strings.Contains(s, "Set-Service") {
+			return okEnvelope(t, runtimeStateData("AutomaticDelayedStart", "Running")), "", nil
+		}
+		return okEnvelope(t, map[string]any{}), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	got, err := s.SetRuntime(context.Background(), "Spooler",
+		ServiceRuntimeInput{StartType: "AutomaticDelayedStart", DesiredStatus: "Running"})
+	if err != nil {
+		t.Fatalf("SetRuntime: %v", err)
+	}
+	if got.StartType != "AutomaticDelayedStart" || got.CurrentStatus != "Running" {
+		t.Errorf("state = %+v", got)
+	}
+	if len(scripts) != 3 {
+		t.Fatalf("expected set start type, start, read; got %d scripts", len(scripts))
+	}
+	for _, want := range []string{"$stype      = 'Automatic'", "$finalStart = 'AutomaticDelayedStart'", "start= delayed-auto"} {
+		if !strings.Contains(scripts[0], want) {
+			t.Errorf("start type script missing %q", want)
+		}
+	}
+	if !strings.Contains(scripts[1], "Start-Service -Name $name") {
+		t.Error("second script must start the service")
+	}
+	for _, s := range scripts {
+		for _, bad := range []string{"BinaryPathName", "Description", "depend=", "obj=", "Remove-Service"} {
+			if strings.Contains(s, bad) {
+				t.Errorf("SetRuntime must not touch the registration, found %q", bad)
+			}
+		}
+	}
+}
+
+func TestSetRuntime_StatusOnlySkipsStartType(t *testing.T) {
+	var scripts []string
+	defer stubRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		scripts = append(scripts, s)
+		if strings.Contains(s, "Stop-Service") {
+			return errEnvelope(t, "not_running", "Cannot stop service (1062)"), "", nil
+		}
+		return okEnvelope(t, runtimeStateData("Manual", "Stopped")), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	got, err := s.SetRuntime(context.Background(), "Spooler", ServiceRuntimeInput{DesiredStatus: "Stopped"})
+	if err != nil {
+		t.Fatalf("already stopped must be success: %v", err)
+	}
+	if got.CurrentStatus != "Stopped" || len(scripts) != 2 || strings.Contains(scripts[0], "Set-Service") {
+		t.Errorf("state=%+v scripts=%d", got, len(scripts))
+	}
+}
+
+func TestSetRuntime_Errors(t *testing.T) {
+	calls := 0
+	restore := stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls++
+		return errEnvelope(t, "not_found", "service 'nope' does not exist"), "", nil
+	})
+	defer restore()
+	s := NewServiceClient(newTestClient(t))
+
+	for _, in := range []ServiceRuntimeInput{
+		{StartType: "Disabled", DesiredStatus: "Running"},
+		{StartType: "Boot"},
+		{DesiredStatus: "Paused"},
+	} {
+		if _, err := s.SetRuntime(context.Background(), "Spooler", in); !errors.Is(err, ErrServiceInvalidParameter) {
+			t.Errorf("%+v: err = %v, want invalid_parameter", in, err)
+		}
+	}
+	if _, err := s.SetRuntime(context.Background(), "", ServiceRuntimeInput{}); !errors.Is(err, ErrServiceInvalidParameter) {
+		t.Errorf("empty name: err = %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("invalid input must not reach the host, got %d calls", calls)
+	}
+
+	if _, err := s.SetRuntime(context.Background(), "nope", ServiceRuntimeInput{StartType: "Manual"}); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("missing service: err = %v, want not_found", err)
+	}
+}
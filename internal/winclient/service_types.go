@@ -5,7 +5,7 @@
 //
 // File layout:
 //
-//	ServiceErrorKind  — string enum of typed error categories (9 kinds)
+//	ServiceErrorKind  — string enum of typed error categories (10 kinds)
 //	ServiceError      — structured error type with Kind, Message, Context, Cause
 //	Sentinel errors   — pre-constructed *ServiceError values for errors.Is
 //	ServiceInput      — input parameters for Create/Update operations
@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 // ---------------------------------------------------------------------------
@@ -66,6 +67,13 @@ const (
 	// Disabled service (Win32 error 1058).
 	ServiceErrorDisabled ServiceErrorKind = "disabled"
 
+	// ServiceErrorDependencyFailed is returned by StartService when a service
+	// listed in RequiredServices is Disabled or does not reach Running. The
+	// failing dependency is named in ServiceError.Context["dependency"].
+	// Delete returns it when a service that depends on the one being removed
+	// does not stop; that service is named in Context["dependent"].
+	ServiceErrorDependencyFailed ServiceErrorKind = "dependency_failed"
+
 	// ServiceErrorUnknown is returned for generic sc.exe non-zero exit codes
 	// or unrecognised PowerShell errors.  The full sc.exe stdout/stderr is
 	// captured in ServiceError.Context["output"] for diagnostics.
@@ -114,11 +122,14 @@ func (e *ServiceError) Unwrap() error {
 	return e.Cause
 }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 // This allows:
 //
 //	errors.Is(err, ErrServiceNotFound) // true when err.Kind == ServiceErrorNotFound
 func (e *ServiceError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*ServiceError)
 	if !ok {
 		return false
@@ -164,6 +175,7 @@ var (
 	ErrServiceRunning          = &ServiceError{Kind: ServiceErrorRunning}
 	ErrServiceNotRunning       = &ServiceError{Kind: ServiceErrorNotRunning}
 	ErrServiceDisabled         = &ServiceError{Kind: ServiceErrorDisabled}
+	ErrServiceDependencyFailed = &ServiceError{Kind: ServiceErrorDependencyFailed}
 	ErrServiceUnknown          = &ServiceError{Kind: ServiceErrorUnknown}
 )
 
@@ -184,14 +196,24 @@ var (
 //     built-in ServiceAccount).
 //   - Dependencies nil   → preserve existing deps on Update; none on Create.
 //   - Dependencies []{}  → clear all deps (sc.exe config depend= /).
+//   - SidType ""         → leave the service SID type untouched (None on Create).
+//   - StateTimeout 0     → wait derived from the client timeout (min 30s).
 type ServiceInput struct {
 	// Name is the Windows short service name (required, immutable after Create).
 	Name string
 
 	// BinaryPath is the full executable path with arguments (required on Create;
-	// excluded from Update — ForceNew attribute).
+	// excluded from Update — ForceNew attribute). When Arguments is non-nil,
+	// BinaryPath must be the bare executable: it is quoted and joined with
+	// *Arguments, and Update uses it to rewrite the command line.
 	BinaryPath string
 
+	// Arguments are the start-up arguments appended to the quoted BinaryPath.
+	// nil means "not managed": BinaryPath is sent verbatim on Create and the
+	// command line is left untouched on Update. A non-nil empty string
+	// manages the command line as the quoted executable alone.
+	Arguments *string
+
 	// DisplayName is the human-readable name shown in services.msc.
 	// Empty string on Create → Windows defaults to Name.
 	DisplayName string
@@ -222,6 +244,46 @@ type ServiceInput struct {
 	// on.  nil means "do not change existing dependencies" (Update only).
 	// An empty non-nil slice clears all dependencies.
 	Dependencies []string
+
+	// SidType is the service SID type applied with sc.exe sidtype: "None",
+	// "Unrestricted" or "Restricted". Empty leaves it unchanged.
+	SidType string
+
+	// StateTimeout bounds each WaitForStatus issued while reconciling
+	// DesiredStatus, including the wait for every stopped dependency to reach
+	// Running. Zero uses the default derived from the client timeout.
+	StateTimeout time.Duration
+
+	// Recovery is the failure-action configuration applied with
+	// sc.exe failure. nil leaves it untouched; a Recovery whose actions are
+	// all "None" clears it.
+	Recovery *ServiceRecovery
+}
+
+// ---------------------------------------------------------------------------
+// ServiceRecovery — failure actions (sc.exe failure / qfailure)
+// ---------------------------------------------------------------------------
+
+// ServiceRecovery is what the SCM does when the service process ends
+// unexpectedly: the Recovery tab of services.msc.
+type ServiceRecovery struct {
+	// FirstAction, SecondAction and SubsequentActions are "None", "Restart",
+	// "Run" or "Reboot". SubsequentActions applies to the third and every
+	// later failure within ResetPeriodSeconds. Empty reads as "None".
+	FirstAction       string
+	SecondAction      string
+	SubsequentActions string
+
+	// ResetPeriodSeconds is the failure-free time after which the failure
+	// count goes back to zero.
+	ResetPeriodSeconds int64
+
+	// RestartDelaySeconds is the wait before each action runs. Read reports
+	// the delay of the first action that is not "None" (0 when there is none).
+	RestartDelaySeconds int64
+
+	// Command is the command line run by a "Run" action.
+	Command string
 }
 
 // ---------------------------------------------------------------------------
@@ -249,6 +311,12 @@ type ServiceState struct {
 	// after EC-14 outer-quote normalisation.
 	BinaryPath string
 
+	// Executable and Arguments are BINARY_PATH_NAME split by
+	// splitServiceCommandLine: the unquoted executable path and the
+	// remaining argument string ("" when there are none).
+	Executable string
+	Arguments  string
+
 	// StartType is one of: "Automatic", "AutomaticDelayedStart", "Manual",
 	// "Disabled".  Derived from sc.exe qc START_TYPE field (incl. DELAYED flag).
 	StartType string
@@ -264,6 +332,14 @@ type ServiceState struct {
 	// Dependencies is the ordered list of dependency service names parsed from
 	// sc.exe qc DEPENDENCIES section.  Empty slice when no dependencies.
 	Dependencies []string
+
+	// SidType is the service SID type from sc.exe qsidtype: "None",
+	// "Unrestricted" or "Restricted". Empty when it could not be read.
+	SidType string
+
+	// Recovery is the failure-action configuration from sc.exe qfailure.
+	// nil when it could not be read.
+	Recovery *ServiceRecovery
 }
 
 // ---------------------------------------------------------------------------
@@ -330,6 +406,8 @@ type WindowsServiceClient interface {
 	// Returns ErrServiceRunning   (Win32 1056) if already Running — caller may
 	// treat this as success.
 	// Returns ErrServiceDisabled  (Win32 1058) if start_type is Disabled.
+	// Returns ErrServiceDependencyFailed if a RequiredServices entry that is
+	// not Running is Disabled or cannot be started first.
 	// Returns ErrServiceTimeout   (EC-7) if the service does not reach Running
 	// within the context deadline.
 	StartService(ctx context.Context, name string) error
@@ -353,3 +431,91 @@ type WindowsServiceClient interface {
 	// within the context deadline.
 	PauseService(ctx context.Context, name string) error
 }
+
+// ---------------------------------------------------------------------------
+// WindowsServiceLister — service enumeration
+// ---------------------------------------------------------------------------
+
+// ServiceListFilter narrows a WindowsServiceLister.List call. Empty fields do
+// not filter.
+type ServiceListFilter struct {
+	// NamePattern is a PowerShell -like wildcard (e.g. "MSSQL*") matched
+	// case-insensitively against the short service name.
+	NamePattern string
+	// Status is a ServiceControllerStatus name (e.g. "Running", "Stopped",
+	// "StartPending") compared case-insensitively with the raw status.
+	Status string
+}
+
+// ServiceSummary is one entry returned by WindowsServiceLister.List.
+type ServiceSummary struct {
+	Name        string
+	DisplayName string
+	// Status is the raw ServiceControllerStatus name, including the pending
+	// states (unlike ServiceState.CurrentStatus, which folds them).
+	Status string
+	// StartType is Automatic, AutomaticDelayedStart, Manual, Disabled, Boot
+	// or System.
+	StartType string
+	// StartName is the logon account; empty when Win32_Service could not be
+	// queried.
+	StartName string
+}
+
+// WindowsServiceLister enumerates the services registered in the SCM. It is
+// implemented by ServiceClient and backs the windows_services data source.
+type WindowsServiceLister interface {
+	// List returns the services matching filter, sorted by name. No match
+	// yields an empty, non-nil slice.
+	List(ctx context.Context, filter ServiceListFilter) ([]ServiceSummary, error)
+}
+
+// ---------------------------------------------------------------------------
+// WindowsServiceStateClient — runtime state of an existing service
+// ---------------------------------------------------------------------------
+
+// ServiceRuntimeInput is the desired runtime configuration applied by
+// WindowsServiceStateClient.SetRuntime. Empty fields are left untouched.
+type ServiceRuntimeInput struct {
+	// StartType is Automatic, AutomaticDelayedStart, Manual or Disabled.
+	StartType string
+	// DesiredStatus is Running or Stopped.
+	DesiredStatus string
+	// StateTimeout bounds the wait for DesiredStatus; 0 derives it from the
+	// client timeout (min 30s).
+	StateTimeout time.Duration
+}
+
+// WindowsServiceStateClient manages only the start type and runtime state of
+// a service that already exists; the service's registration is never
+// created, changed or removed. It is implemented by ServiceClient and backs
+// the windows_service_state resource.
+type WindowsServiceStateClient interface {
+	// Read is WindowsServiceClient.Read: (nil, nil) when the service does not
+	// exist.
+	Read(ctx context.Context, name string) (*ServiceState, error)
+
+	// SetRuntime applies the start type first, so a Disabled service can be
+	// re-enabled and started in one call, then reconciles the runtime state
+	// and returns the observed state.
+	//
+	// Returns ErrServiceNotFound when the service does not exist and
+	// ErrServiceInvalidParameter for StartType Disabled with DesiredStatus
+	// Running.
+	SetRuntime(ctx context.Context, name string, in ServiceRuntimeInput) (*ServiceState, error)
+}
+
+// WindowsServiceWaiter blocks until a service reaches a status. It is
+// implemented by ServiceClient and backs the windows_wait_for_service
+// resource.
+type WindowsServiceWaiter interface {
+	// WaitForStatus polls the named service every few seconds until its
+	// status is status (Running, Stopped or Paused) and returns it. A service
+	// that does not exist yet, and transient or connection errors, are
+	// polled again.
+	//
+	// Returns ErrServiceTimeout when timeout elapses first; the message and
+	// the "last_status" context entry carry the last observed status
+	// (ServiceStatusNotFound while the service did not exist).
+	WaitForStatus(ctx context.Context, name, status string, timeout time.Duration) (string, error)
+}
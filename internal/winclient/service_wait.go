@@ -0,0 +1,126 @@
+// Package winclient: waiting for a service to reach a status.
+//
+// ServiceClient.WaitForStatus backs the windows_wait_for_service resource.
+// It polls Get-Service from the provider side, so each poll is a short run
+// that the client timeout comfortably covers, and a service that does not
+// exist yet (a feature or MSI still installing it) is simply polled again.
+//
+// Security invariants:
+//   - The service name is interpolated only through psQuote.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Compile-time assertion: ServiceClient satisfies WindowsServiceWaiter.
+var _ WindowsServiceWaiter = (*ServiceClient)(nil)
+
+// servicePollInterval is the delay between two WaitForStatus polls. Tests
+// may shorten it.
+var servicePollInterval = 5 * time.Second
+
+// ServiceStatusNotFound is the status WaitForStatus reports while the
+// service does not exist.
+const ServiceStatusNotFound = "NotFound"
+
+// psPollServiceStatus emits the raw ServiceControllerStatus of @@NAME@@, or
+// NotFound.
+const psPollServiceStatus = `
+try {
+  $svc = Get-Service -Name @@NAME@@ -ErrorAction SilentlyContinue
+  if (-not $svc) { Emit-OK @{ status = 'NotFound' }; return }
+  Emit-OK @{ status = [string]$svc.Status }
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify $msg) $msg @{}
+}
+`
+
+// WaitForStatus polls the named service until its status is status or
+// timeout elapses, and returns the status reached.
+func (s *ServiceClient) WaitForStatus(ctx context.Context, name, status string, timeout time.Duration) (string, error) {
+	if name == "" {
+		return "", NewServiceError(ServiceErrorInvalidParameter, "name is required", nil, nil)
+	}
+	switch status {
+	case "Running", "Stopped", "Paused":
+	default:
+		return "", NewServiceError(ServiceErrorInvalidParameter,
+			fmt.Sprintf("status must be Running, Stopped or Paused, got %q", status), nil, map[string]string{"name": name})
+	}
+	if timeout <= 0 {
+		return "", NewServiceError(ServiceErrorInvalidParameter, "timeout must be positive", nil, map[string]string{"name": name})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	script := strings.Replace(psPollServiceStatus, "@@NAME@@", psQuote(name), 1)
+
+	last := ""
+	var lastErr error
+	for {
+		got, err := s.pollStatus(ctx, name, script)
+		switch {
+		case err == nil && got == status:
+			return got, nil
+		case err == nil:
+			last, lastErr = got, nil
+		case ctx.Err() != nil:
+			return "", s.waitStatusError(ctx, name, status, timeout, last, lastErr)
+		case Classify(err) == ErrTransient || (TransportFailureKind(err) != "" && !AuthFailureLatched(err)):
+			lastErr = err
+		default:
+			return "", err
+		}
+
+		t := time.NewTimer(servicePollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return "", s.waitStatusError(ctx, name, status, timeout, last, lastErr)
+		case <-t.C:
+		}
+	}
+}
+
+// pollStatus runs one psPollServiceStatus poll.
+func (s *ServiceClient) pollStatus(ctx context.Context, name, script string) (string, error) {
+	resp, err := s.runEnvelope(ctx, "WaitForStatus", name, script)
+	if err != nil {
+		return "", err
+	}
+	var p struct {
+		Status string `json:"status"`
+	}
+	if jerr := json.Unmarshal(resp.Data, &p); jerr != nil || p.Status == "" {
+		return "", NewServiceError(ServiceErrorUnknown, "invalid status payload", jerr, map[string]string{"name": name})
+	}
+	return p.Status, nil
+}
+
+// waitStatusError builds the error returned once ctx has ended while
+// waiting: a timeout naming the last observed status, unless the caller
+// cancelled.
+func (s *ServiceClient) waitStatusError(ctx context.Context, name, status string, timeout time.Duration, last string, lastErr error) error {
+	ctxMap := map[string]string{"name": name, "host": s.c.cfg.Host, "desired_status": status}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return NewServiceError(ServiceErrorUnknown,
+			fmt.Sprintf("waiting for service %q was cancelled", name), ctx.Err(), ctxMap)
+	}
+	if last == "" {
+		ctxMap["last_status"] = "unknown"
+		return NewServiceError(ServiceErrorTimeout,
+			fmt.Sprintf("service %q did not reach %s within %s; its status could not be read", name, status, timeout),
+			lastErr, ctxMap)
+	}
+	ctxMap["last_status"] = last
+	return NewServiceError(ServiceErrorTimeout,
+		fmt.Sprintf("service %q did not reach %s within %s (last observed status: %s)", name, status, timeout, last),
+		lastErr, ctxMap)
+}
@@ -0,0 +1,130 @@
+// Package winclient — unit tests for ServiceClient.WaitForStatus.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shortServicePoll shortens servicePollInterval for the test.
+func shortServicePoll(t *testing.T) {
+	t.Helper()
+	prev := servicePollInterval
+	servicePollInterval = time.Millisecond
+	t.Cleanup(func() { servicePollInterval = prev })
+}
+
+func TestWaitForStatus_PollsUntilReached(t *testing.T) {
+	shortServicePoll(t)
+	seq := []string{"NotFound", "StartPending", "Running"}
+	var scripts []string
+	defer stubRun(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		scripts = append(scripts, s)
+		st := seq[0]
+		if len(seq) > 1 {
+			seq = seq[1:]
+		}
+		return okEnvelope(t, map[string]string{"status": st}), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	got, err := s.WaitForStatus(context.Background(), "W3SVC", "Running", time.Minute)
+	if err != nil || got != "Running" {
+		t.Fatalf("WaitForStatus = %q, %v", got, err)
+	}
+	if len(scripts) != 3 {
+		t.Errorf("polls = %d, want 3", len(scripts))
+	}
+	if !strings.Contains(scripts[0], "Get-Service -Name 'W3SVC'") {
+		t.Error("service name was not quoted into the poll")
+	}
+}
+
+func TestWaitForStatus_TimeoutNamesLastStatus(t *testing.T) {
+	shortServicePoll(t)
+	defer stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return okEnvelope(t, map[string]string{"status": "StartPending"}), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	_, err := s.WaitForStatus(context.Background(), "W3SVC", "Running", 20*time.Millisecond)
+	if !errors.Is(err, ErrServiceTimeout) {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+	var se *ServiceError
+	if !errors.As(err, &se) || se.Context["last_status"] != "StartPending" || !strings.Contains(se.Message, "last observed status: StartPending") {
+		t.Errorf("timeout must carry the last status: %+v", se)
+	}
+}
+
+func TestWaitForStatus_TransientErrorsArePolledAgain(t *testing.T) {
+	shortServicePoll(t)
+	calls := 0
+	defer stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls++
+		if calls == 1 {
+			return errEnvelope(t, "unknown", "The RPC server is unavailable."), "", nil
+		}
+		return okEnvelope(t, map[string]string{"status": "Stopped"}), "", nil
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	if got, err := s.WaitForStatus(context.Background(), "W3SVC", "Stopped", time.Minute); err != nil || got != "Stopped" {
+		t.Fatalf("WaitForStatus = %q, %v", got, err)
+	}
+}
+
+func TestWaitForStatus_AuthFailureStopsOnlyWhenLatched(t *testing.T) {
+	shortServicePoll(t)
+	calls := 0
+	defer stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls++
+		switch calls {
+		case 1:
+			return "", "", &TransportError{Kind: FailureAuth, Err: errors.New("http error 401: ")}
+		case 2:
+			return okEnvelope(t, map[string]string{"status": "Running"}), "", nil
+		}
+		return "", "", &TransportError{Kind: FailureAuth, Err: errors.New("http error 401: "), Latched: true}
+	})()
+
+	s := NewServiceClient(newTestClient(t))
+	if got, err := s.WaitForStatus(context.Background(), "W3SVC", "Running", time.Minute); err != nil || got != "Running" {
+		t.Fatalf("a single rejected poll must be polled again: %q, %v", got, err)
+	}
+	if _, err := s.WaitForStatus(context.Background(), "W3SVC", "Running", time.Minute); !AuthFailureLatched(err) {
+		t.Errorf("a latched auth failure must end the wait, got %v", err)
+	}
+}
+
+func TestWaitForStatus_Errors(t *testing.T) {
+	calls := 0
+	defer stubRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		calls++
+		return errEnvelope(t, "permission_denied", "Access is denied"), "", nil
+	})()
+	s := NewServiceClient(newTestClient(t))
+
+	for _, tc := range []struct {
+		name, status string
+		timeout      time.Duration
+	}{
+		{"", "Running", time.Minute},
+		{"W3SVC", "StartPending", time.Minute},
+		{"W3SVC", "Running", 0},
+	} {
+		if _, err := s.WaitForStatus(context.Background(), tc.name, tc.status, tc.timeout); !errors.Is(err, ErrServiceInvalidParameter) {
+			t.Errorf("%+v: err = %v, want invalid_parameter", tc, err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("invalid input must not reach the host, got %d calls", calls)
+	}
+
+	if _, err := s.WaitForStatus(context.Background(), "W3SVC", "Running", time.Minute); !errors.Is(err, ErrServicePermission) || calls != 1 {
+		t.Errorf("a real failure must end the wait at once: err=%v calls=%d", err, calls)
+	}
+}
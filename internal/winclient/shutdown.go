@@ -0,0 +1,145 @@
+// Package winclient: graceful client shutdown.
+//
+// Tearing the provider down while a run is in flight used to abandon the
+// remote command wherever it happened to be, which during a cancelled apply
+// can leave a mutation half done (a registry batch partly written, a service
+// created but not configured). CloseGraceful stops the client from starting
+// new runs, waits for the runs already in flight to finish on their own, and
+// only cancels the stragglers once the caller's deadline passes. Once the
+// runs are done it retries the removal of orphaned remote temp artifacts
+// (see temp_artifacts.go), which may hold PFX private keys or secedit
+// exports, then closes the SSH bastion session, if any, and stops its
+// keepalive goroutine, so nothing outlives the close.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrClientClosed is returned by runs started after CloseGraceful, and wraps
+// the error of a run cancelled because the close deadline passed.
+var ErrClientClosed = errors.New("winclient: client is closed")
+
+// closeSweepKey marks the context of the temp artifact sweep CloseGraceful
+// runs after it has stopped accepting runs.
+type closeSweepKey struct{}
+
+// beginRun registers an in-flight run. It returns a context that is also
+// cancelled when CloseGraceful gives up waiting, and a release func the
+// caller must invoke when the run returns. After CloseGraceful it returns
+// ErrClientClosed, except for CloseGraceful's own sweep.
+func (c *Client) beginRun(ctx context.Context) (context.Context, func(), error) {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	sweep := ctx.Value(closeSweepKey{}) != nil
+	if c.closing && !sweep {
+		return nil, nil, ErrClientClosed
+	}
+	c.initStopLocked()
+	c.inflight++
+
+	runCtx, cancel := context.WithCancel(ctx)
+	unregister := func() bool { return false }
+	if !sweep {
+		unregister = context.AfterFunc(c.stopCtx, cancel)
+	}
+	return runCtx, func() {
+		unregister()
+		cancel()
+		c.endRun()
+	}, nil
+}
+
+// endRun unregisters an in-flight run and signals CloseGraceful when the last
+// one returns.
+func (c *Client) endRun() {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	c.inflight--
+	if c.closing && c.inflight == 0 && c.drained != nil {
+		select {
+		case <-c.drained:
+		default:
+			close(c.drained)
+		}
+	}
+}
+
+// initStopLocked lazily creates the context CloseGraceful cancels to
+// interrupt stragglers. lifeMu must be held.
+func (c *Client) initStopLocked() {
+	if c.stopCtx == nil {
+		c.stopCtx, c.stopRuns = context.WithCancel(context.Background())
+	}
+}
+
+// forcedByClose reports whether a run whose context ended was cancelled by
+// CloseGraceful rather than by its caller.
+func (c *Client) forcedByClose(callerCtx context.Context) bool {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	return callerCtx.Err() == nil && c.stopCtx != nil && c.stopCtx.Err() != nil
+}
+
+// InFlight returns the number of runs currently executing.
+func (c *Client) InFlight() int {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	return c.inflight
+}
+
+// CloseGraceful stops the client from starting new runs and waits for the
+// runs in flight to return. If ctx ends first, the remaining runs are
+// cancelled, CloseGraceful waits for them to unwind and returns an error
+// wrapping ctx.Err(). Either way orphaned temp artifacts are then swept,
+// within tempCleanupTimeout even when ctx has ended, and the bastion
+// session is closed. It is safe to call more than once.
+func (c *Client) CloseGraceful(ctx context.Context) error {
+	c.lifeMu.Lock()
+	c.closing = true
+	c.initStopLocked()
+	if c.drained == nil {
+		c.drained = make(chan struct{})
+		if c.inflight == 0 {
+			close(c.drained)
+		}
+	}
+	drained, stop := c.drained, c.stopRuns
+	c.lifeMu.Unlock()
+
+	select {
+	case <-drained:
+		stop()
+		c.closeSweep(ctx)
+		c.closeBastion()
+		return nil
+	case <-ctx.Done():
+	}
+	stop()
+	<-drained
+	c.closeSweep(ctx)
+	c.closeBastion()
+	return fmt.Errorf("winclient: graceful close: in-flight runs cancelled: %w", ctx.Err())
+}
+
+// closeSweep runs sweepTempArtifacts on a fresh deadline, so the staging
+// directories left by cancelled operations are removed before the process
+// exits rather than by a later operation that may never come.
+func (c *Client) closeSweep(ctx context.Context) {
+	if len(c.pendingTempArtifacts()) == 0 {
+		return
+	}
+	sctx, cancel := context.WithTimeout(context.WithValue(context.WithoutCancel(ctx), closeSweepKey{}, true), tempCleanupTimeout)
+	defer cancel()
+	c.sweepTempArtifacts(sctx)
+}
+
+// closeBastion closes the bastion session and waits for its keepalive
+// goroutine. A no-op without a bastion.
+func (c *Client) closeBastion() {
+	if c.bastion != nil {
+		c.bastion.close()
+	}
+}
@@ -0,0 +1,175 @@
+// Package winclient — unit tests for CloseGraceful.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloseGraceful_WaitsForInFlightRun(t *testing.T) {
+	c := newHealthTestClient(t)
+	runCtx, release, err := c.beginRun(context.Background())
+	if err != nil {
+		t.Fatalf("beginRun: %v", err)
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		if runCtx.Err() != nil {
+			t.Error("run inside the grace window must not be cancelled")
+		}
+		close(finished)
+		release()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := c.CloseGraceful(ctx); err != nil {
+		t.Fatalf("CloseGraceful: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("CloseGraceful returned before the in-flight run completed")
+	}
+	if n := c.InFlight(); n != 0 {
+		t.Errorf("InFlight = %d, want 0", n)
+	}
+	if err := c.CloseGraceful(context.Background()); err != nil {
+		t.Errorf("second CloseGraceful = %v, want nil", err)
+	}
+}
+
+func TestCloseGraceful_CancelsStragglersAfterDeadline(t *testing.T) {
+	c := newHealthTestClient(t)
+	callerCtx := context.Background()
+	runCtx, release, err := c.beginRun(callerCtx)
+	if err != nil {
+		t.Fatalf("beginRun: %v", err)
+	}
+	go func() {
+		<-runCtx.Done()
+		release()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = c.CloseGraceful(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CloseGraceful = %v, want deadline exceeded", err)
+	}
+	if runCtx.Err() == nil {
+		t.Error("straggler context must be cancelled after the deadline")
+	}
+	if !c.forcedByClose(callerCtx) {
+		t.Error("forcedByClose must report the shutdown cancellation")
+	}
+}
+
+func TestCloseGraceful_RejectsNewRuns(t *testing.T) {
+	c := newHealthTestClient(t)
+	if err := c.CloseGraceful(context.Background()); err != nil {
+		t.Fatalf("CloseGraceful: %v", err)
+	}
+	if _, _, err := c.RunPowerShell(context.Background(), "Get-Date"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("RunPowerShell after close = %v, want ErrClientClosed", err)
+	}
+	if st := c.ConnectionStats(); st.Runs != 0 {
+		t.Errorf("rejected runs must not be counted: %+v", st)
+	}
+}
+
+func TestCloseGraceful_SweepsOrphanedTempArtifacts(t *testing.T) {
+	c := newHealthTestClient(t)
+	c.trackTempArtifacts([]string{`tf-cert\imp_0123456789abcdef`})
+	var swept []string
+	defer stubTempCleanup(func(ctx context.Context, c *Client, script string) (string, string, error) {
+		// The sweep must get past the closed client.
+		_, release, err := c.beginRun(ctx)
+		if err != nil {
+			t.Errorf("sweep refused by the closing client: %v", err)
+			return "", "", err
+		}
+		defer release()
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("the sweep must be bounded")
+		}
+		swept = append(swept, script)
+		return `{"failed":[]}` + "\n", "", nil
+	})()
+
+	// An expired close deadline must not skip the sweep.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = c.CloseGraceful(ctx)
+	if len(swept) != 1 || !strings.Contains(swept[0], `imp_0123456789abcdef`) {
+		t.Errorf("orphan not swept on close: %q", swept)
+	}
+	if got := c.pendingTempArtifacts(); len(got) != 0 {
+		t.Errorf("orphans left after close: %v", got)
+	}
+	if _, _, err := c.RunPowerShell(context.Background(), "Get-Date"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("RunPowerShell after close = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestCloseGraceful_NoSweepWithoutOrphans(t *testing.T) {
+	c := newHealthTestClient(t)
+	defer stubTempCleanup(func(context.Context, *Client, string) (string, string, error) {
+		t.Error("no cleanup run expected without orphans")
+		return "", "", nil
+	})()
+	if err := c.CloseGraceful(context.Background()); err != nil {
+		t.Fatalf("CloseGraceful: %v", err)
+	}
+}
+
+func TestCloseGraceful_ClosesBastionSession(t *testing.T) {
+	b := startTestBastion(t)
+	target := startEchoServer(t)
+	host, portStr, _ := net.SplitHostPort(target)
+	port, _ := strconv.Atoi(portStr)
+	cfg := bastionCfg(t, b, "pw")
+	cfg.KeepaliveInterval = 10 * time.Millisecond
+	c, err := New(Config{Host: host, Port: port, Username: "u", Password: "p", AuthType: "basic",
+		Timeout: 2 * time.Second, Bastion: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := c.bastion.Dial("tcp", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for b.keepalives.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := c.CloseGraceful(context.Background()); err != nil {
+		t.Fatalf("CloseGraceful: %v", err)
+	}
+	// close waits for the keepalive goroutine, so no keepalive is sent
+	// after CloseGraceful returns; one already on the wire may still land.
+	time.Sleep(20 * time.Millisecond)
+	sent := b.keepalives.Load()
+	time.Sleep(50 * time.Millisecond)
+	if n := b.keepalives.Load(); n != sent {
+		t.Errorf("keepalives went on after close: %d -> %d", sent, n)
+	}
+	if _, err := conn.Write([]byte("x")); err == nil {
+		t.Error("channels of the closed session must be closed")
+	}
+	if _, err := c.bastion.Dial("tcp", target); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Dial after close = %v, want ErrClientClosed", err)
+	}
+	if n := b.handshakes.Load(); n != 1 {
+		t.Errorf("handshakes = %d, want 1 (no session re-opened after close)", n)
+	}
+}
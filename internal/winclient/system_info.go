@@ -0,0 +1,207 @@
+// Package winclient: host metadata over WinRM.
+//
+// SystemInfoClient is the concrete WindowsSystemInfoClient backing the
+// windows_system_info data source. One script reads Win32_OperatingSystem and
+// Win32_ComputerSystem through CIM and emits a single JSON object.
+//
+// Security invariants:
+//   - The script takes no user input; nothing is interpolated.
+//   - All scripts are sent via -EncodedCommand by Client.RunPowerShell.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Compile-time assertion: SystemInfoClient satisfies WindowsSystemInfoClient.
+var _ WindowsSystemInfoClient = (*SystemInfoClient)(nil)
+
+// SystemInfoClient is the PowerShell/WinRM-backed WindowsSystemInfoClient.
+type SystemInfoClient struct {
+	c *Client
+}
+
+// NewSystemInfoClient wraps the given WinRM Client.
+func NewSystemInfoClient(c *Client) *SystemInfoClient { return &SystemInfoClient{c: c} }
+
+// runSystemInfoPowerShell is the package-level indirection used by
+// SystemInfoClient. Tests may override it; production code must not.
+var runSystemInfoPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// systemInfoPSResponse is the JSON envelope produced by Emit-OK/Emit-Err.
+type systemInfoPSResponse struct {
+	OK      bool              `json:"ok"`
+	Kind    string            `json:"kind,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+}
+
+// systemInfoPayload mirrors the object emitted by psGetSystemInfo.
+type systemInfoPayload struct {
+	OSName              string `json:"os_name"`
+	OSVersion           string `json:"os_version"`
+	OSBuild             string `json:"os_build"`
+	OSArchitecture      string `json:"os_architecture"`
+	ProductType         int    `json:"product_type"`
+	TotalPhysicalMemory int64  `json:"total_physical_memory"`
+	LogicalProcessors   int64  `json:"logical_processors"`
+	Domain              string `json:"domain"`
+	Workgroup           string `json:"workgroup"`
+}
+
+// psSystemInfoHeader prepends Emit-OK/Emit-Err and Classify-SystemInfo.
+const psSystemInfoHeader = `
+$ErrorActionPreference = 'Stop'
+$ProgressPreference    = 'SilentlyContinue'
+
+function Emit-OK([object]$Data) {
+  $obj = [ordered]@{ ok = $true; data = $Data }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Emit-Err([string]$Kind, [string]$Message, [hashtable]$Ctx) {
+  if (-not $Ctx) { $Ctx = @{} }
+  $obj = [ordered]@{ ok = $false; kind = $Kind; message = $Message; context = $Ctx }
+  [Console]::Out.WriteLine(($obj | ConvertTo-Json -Depth 4 -Compress))
+}
+function Classify-SystemInfo([string]$Msg) {
+  if ($Msg -match 'Access is denied' -or $Msg -match 'AccessDenied') { return 'permission_denied' }
+  return 'unknown'
+}
+`
+
+// psGetSystemInfo reads the OS and computer system classes. The workgroup
+// logic matches the hostname read (hostname.go): on a workgroup host,
+// Win32_ComputerSystem.Domain holds the workgroup name.
+const psGetSystemInfo = `
+try {
+  $os = Get-CimInstance -ClassName Win32_OperatingSystem -ErrorAction Stop
+  $cs = Get-CimInstance -ClassName Win32_ComputerSystem -ErrorAction Stop
+  $dom = ''; $wg = ''
+  if ($cs.PartOfDomain) { $dom = [string]$cs.Domain } else { $wg = [string]$cs.Workgroup; if (-not $wg) { $wg = [string]$cs.Domain } }
+  Emit-OK ([ordered]@{
+    os_name               = ([string]$os.Caption).Trim()
+    os_version            = [string]$os.Version
+    os_build              = [string]$os.BuildNumber
+    os_architecture       = [string]$os.OSArchitecture
+    product_type          = [int]$os.ProductType
+    total_physical_memory = [int64]$cs.TotalPhysicalMemory
+    logical_processors    = [int64]$cs.NumberOfLogicalProcessors
+    domain                = $dom
+    workgroup             = $wg
+  })
+} catch {
+  $msg = $_.Exception.Message
+  Emit-Err (Classify-SystemInfo $msg) $msg @{}
+}
+`
+
+// runSystemInfoEnvelope executes script (prepended with psSystemInfoHeader)
+// and parses the JSON envelope. Cancellation maps to SystemInfoErrorTimeout;
+// other transport failures to SystemInfoErrorUnknown.
+func (s *SystemInfoClient) runSystemInfoEnvelope(ctx context.Context, op, script string) (*systemInfoPSResponse, error) {
+	ctx = withCommandOp(ctx, op)
+	full := psSystemInfoHeader + "\n" + script
+	stdout, stderr, err := runSystemInfoPowerShell(ctx, s.c, full)
+
+	baseCtx := map[string]string{
+		"operation": op,
+		"host":      s.c.cfg.Host,
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, NewSystemInfoError(SystemInfoErrorTimeout,
+				fmt.Sprintf("operation %q timed out or was cancelled", op),
+				ctxErr, baseCtx)
+		}
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewSystemInfoError(SystemInfoErrorUnknown,
+			fmt.Sprintf("WinRM transport error during %q", op),
+			err, baseCtx)
+	}
+
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		baseCtx["stderr"] = truncate(stderr, 2048)
+		return nil, NewSystemInfoError(SystemInfoErrorUnknown,
+			fmt.Sprintf("no JSON envelope returned from %q", op), nil, baseCtx)
+	}
+	var resp systemInfoPSResponse
+	if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+		baseCtx["stdout"] = truncate(stdout, 2048)
+		return nil, NewSystemInfoError(SystemInfoErrorUnknown,
+			fmt.Sprintf("invalid JSON envelope from %q", op), jerr, baseCtx)
+	}
+	if !resp.OK {
+		ctxMap := resp.Context
+		if ctxMap == nil {
+			ctxMap = map[string]string{}
+		}
+		for k, v := range baseCtx {
+			if _, ok := ctxMap[k]; !ok {
+				ctxMap[k] = v
+			}
+		}
+		return &resp, NewSystemInfoError(mapSystemInfoKind(resp.Kind), resp.Message, nil, ctxMap)
+	}
+	return &resp, nil
+}
+
+// mapSystemInfoKind translates a PS-side "kind" string to a typed
+// SystemInfoErrorKind. Unknown values fall through to SystemInfoErrorUnknown.
+func mapSystemInfoKind(k string) SystemInfoErrorKind {
+	switch k {
+	case string(SystemInfoErrorPermission),
+		string(SystemInfoErrorTimeout):
+		return SystemInfoErrorKind(k)
+	default:
+		return SystemInfoErrorUnknown
+	}
+}
+
+// productTypeName maps Win32_OperatingSystem.ProductType to a ProductType*
+// constant. Unknown codes are returned as their decimal string.
+func productTypeName(code int) string {
+	switch code {
+	case 1:
+		return ProductTypeWorkstation
+	case 2:
+		return ProductTypeDomainController
+	case 3:
+		return ProductTypeServer
+	default:
+		return fmt.Sprintf("%d", code)
+	}
+}
+
+// Get reads the host's operating system and hardware summary.
+func (s *SystemInfoClient) Get(ctx context.Context) (*SystemInfo, error) {
+	resp, err := s.runSystemInfoEnvelope(ctx, "get", psGetSystemInfo)
+	if err != nil {
+		return nil, err
+	}
+	var p systemInfoPayload
+	if jerr := json.Unmarshal(resp.Data, &p); jerr != nil {
+		return nil, NewSystemInfoError(SystemInfoErrorUnknown,
+			"failed to parse system info", jerr,
+			map[string]string{"host": s.c.cfg.Host})
+	}
+	return &SystemInfo{
+		OSName:              p.OSName,
+		OSVersion:           p.OSVersion,
+		OSBuild:             p.OSBuild,
+		OSArchitecture:      p.OSArchitecture,
+		ProductType:         productTypeName(p.ProductType),
+		TotalPhysicalMemory: p.TotalPhysicalMemory,
+		LogicalProcessors:   p.LogicalProcessors,
+		Domain:              p.Domain,
+		Workgroup:           p.Workgroup,
+	}, nil
+}
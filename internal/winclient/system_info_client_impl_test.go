@@ -0,0 +1,107 @@
+// Package winclient — unit tests for SystemInfoClient.
+//
+// These tests stub the package-level seam runSystemInfoPowerShell to inject
+// scripted stdout/stderr/err triples.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubSysInfoRun replaces runSystemInfoPowerShell for the duration of a test
+// and returns a restore function (typically deferred).
+func stubSysInfoRun(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runSystemInfoPowerShell
+	runSystemInfoPowerShell = fn
+	return func() { runSystemInfoPowerShell = prev }
+}
+
+func sysInfoStdout(t *testing.T, v any) func(context.Context, *Client, string) (string, string, error) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return string(b) + "\n", "", nil
+	}
+}
+
+func TestSystemInfoGet(t *testing.T) {
+	defer stubSysInfoRun(sysInfoStdout(t, map[string]any{"ok": true, "data": map[string]any{
+		"os_name": "Microsoft Windows Server 2022 Standard", "os_version": "10.0.20348",
+		"os_build": "20348", "os_architecture": "64-bit", "product_type": 3,
+		"total_physical_memory": 17179398144, "logical_processors": 4,
+		"domain": "", "workgroup": "WORKGROUP",
+	}}))()
+
+	got, err := NewSystemInfoClient(newLouTestClient(t)).Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	want := SystemInfo{
+		OSName: "Microsoft Windows Server 2022 Standard", OSVersion: "10.0.20348",
+		OSBuild: "20348", OSArchitecture: "64-bit", ProductType: ProductTypeServer,
+		TotalPhysicalMemory: 17179398144, LogicalProcessors: 4, Workgroup: "WORKGROUP",
+	}
+	if *got != want {
+		t.Errorf("Get = %#v, want %#v", *got, want)
+	}
+}
+
+func TestProductTypeName(t *testing.T) {
+	cases := map[int]string{1: ProductTypeWorkstation, 2: ProductTypeDomainController, 3: ProductTypeServer, 7: "7"}
+	for in, want := range cases {
+		if got := productTypeName(in); got != want {
+			t.Errorf("productTypeName(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSystemInfoGet_PermissionDenied(t *testing.T) {
+	defer stubSysInfoRun(sysInfoStdout(t, map[string]any{
+		"ok": false, "kind": "permission_denied", "message": "Access is denied.",
+	}))()
+	_, err := NewSystemInfoClient(newLouTestClient(t)).Get(context.Background())
+	if !errors.Is(err, ErrSystemInfoPermission) {
+		t.Fatalf("expected permission_denied, got %v", err)
+	}
+	var se *SystemInfoError
+	errors.As(err, &se)
+	if se.Context["host"] != "win01" || se.Context["operation"] != "get" {
+		t.Errorf("context = %v", se.Context)
+	}
+}
+
+func TestSystemInfoGet_Timeout(t *testing.T) {
+	defer stubSysInfoRun(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", context.Canceled
+	})()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewSystemInfoClient(newLouTestClient(t)).Get(ctx)
+	if !IsSystemInfoError(err, SystemInfoErrorTimeout) {
+		t.Errorf("expected timeout on cancelled ctx, got %v", err)
+	}
+}
+
+func TestSystemInfoGet_ScriptShape(t *testing.T) {
+	var captured string
+	defer stubSysInfoRun(func(_ context.Context, _ *Client, script string) (string, string, error) {
+		captured = script
+		return "no json\n", "", nil
+	})()
+	_, err := NewSystemInfoClient(newLouTestClient(t)).Get(context.Background())
+	if !IsSystemInfoError(err, SystemInfoErrorUnknown) {
+		t.Errorf("missing JSON envelope should yield unknown, got %v", err)
+	}
+	for _, want := range []string{"Win32_OperatingSystem", "Win32_ComputerSystem", "NumberOfLogicalProcessors", "function Emit-OK"} {
+		if !strings.Contains(captured, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package winclient: types for the windows_system_info data source.
+//
+// SystemInfo is the host metadata read from Win32_OperatingSystem and
+// Win32_ComputerSystem. SystemInfoErrorKind / SystemInfoError follow the same
+// shape as LoggedOnUsersError.
+package winclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SystemInfoErrorKind categorises errors returned by WindowsSystemInfoClient.
+type SystemInfoErrorKind string
+
+const (
+	SystemInfoErrorPermission SystemInfoErrorKind = "permission_denied"
+	SystemInfoErrorTimeout    SystemInfoErrorKind = "timeout"
+	SystemInfoErrorUnknown    SystemInfoErrorKind = "unknown"
+)
+
+// SystemInfoError is the structured error type returned by
+// WindowsSystemInfoClient.
+type SystemInfoError struct {
+	Kind    SystemInfoErrorKind
+	Message string
+	Context map[string]string
+	Cause   error
+}
+
+// Error implements error.
+func (e *SystemInfoError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("windows_system_info [%s]: %s: %v", e.Kind, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("windows_system_info [%s]: %s", e.Kind, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *SystemInfoError) Unwrap() error { return e.Cause }
+
+// Is matches by Kind, or by ErrorClass (see errors.go).
+func (e *SystemInfoError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
+	t, ok := target.(*SystemInfoError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// NewSystemInfoError constructs a *SystemInfoError.
+func NewSystemInfoError(kind SystemInfoErrorKind, msg string, cause error, ctx map[string]string) *SystemInfoError {
+	return &SystemInfoError{Kind: kind, Message: msg, Cause: cause, Context: ctx}
+}
+
+// IsSystemInfoError reports whether err is a *SystemInfoError of the given
+// kind.
+func IsSystemInfoError(err error, kind SystemInfoErrorKind) bool {
+	var se *SystemInfoError
+	if errors.As(err, &se) {
+		return se.Kind == kind
+	}
+	return false
+}
+
+// Sentinel errors usable with errors.Is.
+var (
+	ErrSystemInfoPermission = &SystemInfoError{Kind: SystemInfoErrorPermission}
+	ErrSystemInfoTimeout    = &SystemInfoError{Kind: SystemInfoErrorTimeout}
+	ErrSystemInfoUnknown    = &SystemInfoError{Kind: SystemInfoErrorUnknown}
+)
+
+// Product types reported in SystemInfo.ProductType
+// (Win32_OperatingSystem.ProductType 1, 2 and 3).
+const (
+	ProductTypeWorkstation      = "Workstation"
+	ProductTypeDomainController = "DomainController"
+	ProductTypeServer           = "Server"
+)
+
+// SystemInfo is the operating system and hardware summary of the host.
+type SystemInfo struct {
+	// OSName is Win32_OperatingSystem.Caption, e.g.
+	// "Microsoft Windows Server 2022 Standard".
+	OSName string
+	// OSVersion is Win32_OperatingSystem.Version, e.g. "10.0.20348".
+	OSVersion string
+	// OSBuild is Win32_OperatingSystem.BuildNumber, e.g. "20348".
+	OSBuild string
+	// OSArchitecture is Win32_OperatingSystem.OSArchitecture, e.g. "64-bit".
+	OSArchitecture string
+	// ProductType is one of the ProductType* constants.
+	ProductType string
+	// TotalPhysicalMemory is Win32_ComputerSystem.TotalPhysicalMemory in bytes.
+	TotalPhysicalMemory int64
+	// LogicalProcessors is Win32_ComputerSystem.NumberOfLogicalProcessors.
+	LogicalProcessors int64
+	// Domain is the AD domain of a domain member; "" on a workgroup host.
+	Domain string
+	// Workgroup is the workgroup name of a workgroup host; "" on a domain member.
+	Workgroup string
+}
+
+// WindowsSystemInfoClient is the contract for the windows_system_info data
+// source.
+type WindowsSystemInfoClient interface {
+	// Get returns the host's operating system and hardware summary.
+	Get(ctx context.Context) (*SystemInfo, error)
+}
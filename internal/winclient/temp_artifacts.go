@@ -0,0 +1,134 @@
+// Package winclient: best-effort removal of temp artifacts on the remote host.
+//
+// Scripts travel over stdin, so the provider only writes staging
+// directories to the remote host where a tool needs a file: installers
+// downloaded by windows_legacy_package, the PFX Import-PfxCertificate reads
+// for windows_certificate (private key included), and the secedit
+// export/import files of windows_local_security_policy. The PowerShell side
+// removes them in a `finally` block, but when the Terraform context is
+// cancelled or times out WinRM tears the shell down and that block may never
+// run. The Go side then retries the removal on a fresh context, and
+// remembers any path it still could not remove. The next operation of those
+// resources on the same Client sweeps it, and so does CloseGraceful (see
+// shutdown.go) when the provider shuts down.
+package winclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tempCleanupTimeout bounds a post-cancellation cleanup run. Tests may
+// shorten it.
+var tempCleanupTimeout = 30 * time.Second
+
+// runTempCleanupPowerShell is the package-level indirection used by the
+// cleanup script. Tests may override it; production code must not.
+var runTempCleanupPowerShell = func(ctx context.Context, c *Client, script string) (string, string, error) {
+	return c.RunPowerShell(ctx, script)
+}
+
+// newTempToken returns a random 16-hex-character token used to give each
+// operation its own staging directory, so a cleanup can never remove files
+// belonging to a concurrent run.
+func newTempToken() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// psRemoveTempArtifacts removes each %TEMP%-relative path in rels and reports
+// the ones that still exist afterwards.
+func psRemoveTempArtifacts(rels []string) string {
+	return `
+$ErrorActionPreference = 'Stop'
+$failed = @()
+foreach ($rel in ` + psQuoteList(rels) + `) {
+  $p = Join-Path $env:TEMP $rel
+  if (Test-Path -LiteralPath $p) {
+    Remove-Item -LiteralPath $p -Recurse -Force -ErrorAction SilentlyContinue
+    if (Test-Path -LiteralPath $p) { $failed += $rel }
+  }
+}
+[Console]::Out.WriteLine((ConvertTo-Json -Compress -InputObject @{ failed = @($failed) }))
+`
+}
+
+// removeTempArtifacts runs psRemoveTempArtifacts and returns the paths that
+// could not be removed. On transport failure every path is returned.
+func (c *Client) removeTempArtifacts(ctx context.Context, rels []string) ([]string, error) {
+	ctx = withCommandOp(ctx, "temp_cleanup")
+	if len(rels) == 0 {
+		return nil, nil
+	}
+	stdout, stderr, err := runTempCleanupPowerShell(ctx, c, psRemoveTempArtifacts(rels))
+	if err != nil {
+		return rels, fmt.Errorf("winclient: temp cleanup: %w (stderr: %s)", err, truncate(stderr, 512))
+	}
+	var out struct {
+		Failed []string `json:"failed"`
+	}
+	line := extractLastJSONLine(stdout)
+	if line == "" {
+		return rels, fmt.Errorf("winclient: temp cleanup returned no JSON (stdout: %s)", truncate(stdout, 512))
+	}
+	if jerr := json.Unmarshal([]byte(line), &out); jerr != nil {
+		return rels, fmt.Errorf("winclient: temp cleanup: invalid JSON: %w", jerr)
+	}
+	if len(out.Failed) > 0 {
+		return out.Failed, fmt.Errorf("winclient: temp cleanup left %s", strings.Join(out.Failed, ", "))
+	}
+	return nil, nil
+}
+
+// cleanupTempArtifact removes rel after its owning operation failed without
+// reaching its own cleanup. It deliberately ignores cancellation of ctx (a
+// cancelled ctx is the usual reason it is called) and uses a fresh deadline
+// instead. A path that still cannot be removed is recorded for the next
+// sweepTempArtifacts.
+func (c *Client) cleanupTempArtifact(ctx context.Context, rel string) {
+	cctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tempCleanupTimeout)
+	defer cancel()
+	if left, err := c.removeTempArtifacts(cctx, []string{rel}); err != nil {
+		c.trackTempArtifacts(left)
+	}
+}
+
+// trackTempArtifacts records rels as orphaned.
+func (c *Client) trackTempArtifacts(rels []string) {
+	if len(rels) == 0 {
+		return
+	}
+	c.tempMu.Lock()
+	defer c.tempMu.Unlock()
+	c.orphanTemp = append(c.orphanTemp, rels...)
+}
+
+// pendingTempArtifacts returns a copy of the orphaned paths.
+func (c *Client) pendingTempArtifacts() []string {
+	c.tempMu.Lock()
+	defer c.tempMu.Unlock()
+	return append([]string(nil), c.orphanTemp...)
+}
+
+// sweepTempArtifacts retries removal of every orphaned path. Failures are
+// kept for the next sweep and never surface to the caller: an orphaned
+// download must not break an unrelated operation.
+func (c *Client) sweepTempArtifacts(ctx context.Context) {
+	c.tempMu.Lock()
+	rels := c.orphanTemp
+	c.orphanTemp = nil
+	c.tempMu.Unlock()
+	if len(rels) == 0 {
+		return
+	}
+	left, _ := c.removeTempArtifacts(ctx, rels)
+	c.trackTempArtifacts(left)
+}
@@ -0,0 +1,189 @@
+// Package winclient — unit tests for remote temp artifact cleanup.
+//
+// Tests stub runPSInput (the legacy_package transport) and
+// runTempCleanupPowerShell (the cleanup transport) independently so a
+// cancelled operation can be followed by a successful or failing cleanup.
+package winclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func stubTempCleanup(fn func(ctx context.Context, c *Client, script string) (string, string, error)) func() {
+	prev := runTempCleanupPowerShell
+	runTempCleanupPowerShell = fn
+	return func() { runTempCleanupPowerShell = prev }
+}
+
+// lpCancelledCreate runs a source_url Create whose transport fails after ctx
+// is cancelled, and returns the staging_dir sent in the payload.
+func lpCancelledCreate(t *testing.T, lp *LegacyPackageClientImpl) string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	var staging string
+	restore := stubLPInput(func(_ context.Context, _ *Client, _, stdin string) (string, string, error) {
+		var p map[string]any
+		if err := json.Unmarshal([]byte(stdin), &p); err != nil {
+			t.Fatalf("stdin: %v", err)
+		}
+		staging, _ = p["staging_dir"].(string)
+		cancel()
+		return "", "", context.Canceled
+	})
+	defer restore()
+	_, err := lp.Create(ctx, LegacyPackageInput{
+		Name: "demo", InstallerType: "msi", SourceURL: "https://example.invalid/app.msi",
+	})
+	if !IsLegacyPackageError(err, "timeout") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	return staging
+}
+
+func TestLPCreate_CancelledRemovesStagingDir(t *testing.T) {
+	c, lp := lpNewClient(t)
+	var script string
+	var cleanupCtxErr error
+	defer stubTempCleanup(func(ctx context.Context, _ *Client, s string) (string, string, error) {
+		script, cleanupCtxErr = s, ctx.Err()
+		return `{"failed":[]}` + "\n", "", nil
+	})()
+
+	staging := lpCancelledCreate(t, lp)
+	if !strings.HasPrefix(staging, `windows_legacy_package\dl_`) {
+		t.Fatalf("staging_dir = %q", staging)
+	}
+	if !strings.Contains(script, psQuote(staging)) {
+		t.Errorf("cleanup script should target %q:\n%s", staging, script)
+	}
+	if cleanupCtxErr != nil {
+		t.Errorf("cleanup must run on a live context, got %v", cleanupCtxErr)
+	}
+	if got := c.pendingTempArtifacts(); len(got) != 0 {
+		t.Errorf("no orphan expected, got %v", got)
+	}
+}
+
+func TestLPCreate_FailedCleanupIsSweptLater(t *testing.T) {
+	c, lp := lpNewClient(t)
+	restore := stubTempCleanup(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return "", "", errors.New("connection refused")
+	})
+	staging := lpCancelledCreate(t, lp)
+	restore()
+	if got := c.pendingTempArtifacts(); !reflect.DeepEqual(got, []string{staging}) {
+		t.Fatalf("orphans = %v, want [%s]", got, staging)
+	}
+
+	var swept string
+	defer stubTempCleanup(func(_ context.Context, _ *Client, s string) (string, string, error) {
+		swept = s
+		return `{"failed":[]}` + "\n", "", nil
+	})()
+	defer stubLPInput(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return lpOKNull(), "", nil
+	})()
+	if _, err := lp.Read(context.Background(), "{ABC}"); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !strings.Contains(swept, psQuote(staging)) {
+		t.Errorf("next operation should sweep %q, script:\n%s", staging, swept)
+	}
+	if got := c.pendingTempArtifacts(); len(got) != 0 {
+		t.Errorf("orphans after sweep = %v", got)
+	}
+}
+
+func TestLPCreate_NoCleanupWhenScriptReported(t *testing.T) {
+	_, lp := lpNewClient(t)
+	called := false
+	defer stubTempCleanup(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		called = true
+		return "", "", nil
+	})()
+	defer stubLPInput(func(_ context.Context, _ *Client, _, _ string) (string, string, error) {
+		return lpErr(t, "checksum_mismatch", "hash differs"), "", nil
+	})()
+
+	_, err := lp.Create(context.Background(), LegacyPackageInput{
+		Name: "demo", InstallerType: "msi", SourceURL: "https://example.invalid/app.msi",
+	})
+	if !IsLegacyPackageError(err, "checksum_mismatch") {
+		t.Fatalf("kind = %v", err)
+	}
+	if called {
+		t.Error("an Emit-Err envelope means the script's finally ran; no extra cleanup expected")
+	}
+}
+
+func TestLPCreate_SourcePathHasNoStagingDir(t *testing.T) {
+	_, lp := lpNewClient(t)
+	called := false
+	defer stubTempCleanup(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		called = true
+		return "", "", nil
+	})()
+	defer stubLPInput(func(_ context.Context, _ *Client, _, stdin string) (string, string, error) {
+		if strings.Contains(stdin, "staging_dir") {
+			t.Errorf("source_path payload should not carry staging_dir: %s", stdin)
+		}
+		return "", "", errors.New("connection reset")
+	})()
+
+	if _, err := lp.Create(context.Background(), LegacyPackageInput{
+		Name: "demo", InstallerType: "msi", SourcePath: `C:\inst.msi`,
+	}); err == nil {
+		t.Fatal("expected error")
+	}
+	if called {
+		t.Error("nothing was downloaded; no cleanup expected")
+	}
+}
+
+func TestLPCreateScript_FinallyRemovesDownload(t *testing.T) {
+	var script string
+	_, lp := lpNewClient(t)
+	defer stubLPInput(func(_ context.Context, _ *Client, s, _ string) (string, string, error) {
+		script = s
+		return lpOKNull(), "", nil
+	})()
+	if _, err := lp.Create(context.Background(), LegacyPackageInput{Name: "demo"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	try, fin := strings.Index(script, "try {\n"), strings.LastIndex(script, "} finally {")
+	if try < 0 || fin < try || !strings.Contains(script[fin:], "Remove-Item -LiteralPath $tmpDir -Recurse") {
+		t.Errorf("create body should be wrapped in try/finally removing $tmpDir:\n%s", script)
+	}
+	if !strings.Contains(script, "$cfg.staging_dir") {
+		t.Error("create body should honour staging_dir")
+	}
+}
+
+func TestRemoveTempArtifacts_PartialFailure(t *testing.T) {
+	c, _ := lpNewClient(t)
+	defer stubTempCleanup(func(_ context.Context, _ *Client, _ string) (string, string, error) {
+		return `{"failed":["b"]}` + "\n", "", nil
+	})()
+	left, err := c.removeTempArtifacts(context.Background(), []string{"a", "b"})
+	if err == nil || !reflect.DeepEqual(left, []string{"b"}) {
+		t.Errorf("left = %v, err = %v", left, err)
+	}
+
+	c.trackTempArtifacts([]string{"a", "b"})
+	c.sweepTempArtifacts(context.Background())
+	if got := c.pendingTempArtifacts(); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("orphans after partial sweep = %v, want [b]", got)
+	}
+}
+
+func TestNewTempToken_Unique(t *testing.T) {
+	a, b := newTempToken(), newTempToken()
+	if len(a) != 16 || a == b {
+		t.Errorf("tokens %q, %q", a, b)
+	}
+}
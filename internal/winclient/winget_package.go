@@ -370,6 +370,7 @@ func parseWPState(resp *psResponse) (*WingetPackageState, error) {
 // and parses the JSON envelope. Transport errors that bypass Emit-Err (non-zero
 // exit, context cancellation) are wrapped as WingetPackageErrorUnknown.
 func (w *WingetPackageClientImpl) runWPEnvelope(ctx context.Context, op, pkgID, script string) (*psResponse, error) {
+	ctx = withCommandOp(ctx, op)
 	full := wpHeader + "\n" + script
 	stdout, stderr, err := runPowerShell(ctx, w.c, full)
 	if err != nil {
@@ -100,8 +100,11 @@ func (e *WingetPackageError) Unwrap() error {
 	return e.Cause
 }
 
-// Is implements errors.Is comparison by Kind only.
+// Is implements errors.Is comparison by Kind or ErrorClass.
 func (e *WingetPackageError) Is(target error) bool {
+	if c, ok := target.(*ErrorClass); ok {
+		return c.matchesKind(string(e.Kind), e)
+	}
 	t, ok := target.(*WingetPackageError)
 	if !ok {
 		return false
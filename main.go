@@ -7,6 +7,10 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
@@ -16,6 +20,10 @@ import (
 // providerAddress is the Terraform Registry address of this provider.
 const providerAddress = "registry.terraform.io/kfrlabs/windows"
 
+// shutdownGrace bounds how long in-flight remote commands may run after a
+// shutdown signal before they are cancelled.
+const shutdownGrace = 30 * time.Second
+
 // version is injected at build time via -ldflags.
 var version = "dev"
 
@@ -29,7 +37,37 @@ func main() {
 		Debug:   debug,
 	}
 
-	if err := providerserver.Serve(context.Background(), provider.New(version), opts); err != nil {
-		log.Fatal(err.Error())
+	// On SIGTERM, drain the WinRM clients while the server still serves the
+	// RPCs in flight, then exit. SIGINT is left to go-plugin, which ignores
+	// it: Terraform forwards Ctrl-C by cancelling the RPCs and keeps running
+	// the steps an interrupted apply still needs, so draining then would
+	// refuse them.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shutdownDone := provider.ShutdownOnDone(ctx, shutdownGrace)
+	go func() {
+		<-sigs
+		cancel()
+		logShutdown(shutdownDone())
+		os.Exit(1)
+	}()
+
+	serveErr := providerserver.Serve(ctx, provider.New(version), opts)
+
+	signal.Stop(sigs)
+	cancel()
+	logShutdown(shutdownDone())
+
+	if serveErr != nil {
+		log.Fatal(serveErr.Error())
+	}
+}
+
+// logShutdown logs the error of a graceful shutdown, if any.
+func logShutdown(err error) {
+	if err != nil {
+		log.Printf("[WARN] %s", err)
 	}
 }